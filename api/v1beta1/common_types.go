@@ -131,6 +131,11 @@ const (
 	// RemediateMachineAnnotation request the MachineHealthCheck reconciler to mark a Machine as unhealthy. CAPI builtin remediation will prioritize Machines with the annotation to be remediated.
 	RemediateMachineAnnotation = "cluster.x-k8s.io/remediate-machine"
 
+	// UpdateInProgressAnnotation is the annotation set on a Machine while it is being updated in-place by a Runtime Extension,
+	// on behalf of the MachineSet or of the control plane controlling the Machine.
+	// The annotation is removed once the in-place update is completed.
+	UpdateInProgressAnnotation = "cluster.x-k8s.io/update-in-progress"
+
 	// MachineSetSkipPreflightChecksAnnotation is the annotation used to provide a comma-separated list of
 	// preflight checks that should be skipped during the MachineSet reconciliation.
 	// Supported items are:
@@ -143,6 +148,12 @@ const (
 	// the MachineSet.
 	MachineSetSkipPreflightChecksAnnotation = "machineset.cluster.x-k8s.io/skip-preflight-checks"
 
+	// MachineSetInPlaceUpdateDeclinedAnnotation is the annotation set on a MachineSet by the MachineSet controller when some
+	// of its Machines cannot be updated in-place to the spec of the MachineSet, e.g. because the Runtime Extension declined
+	// the update. The MachineDeployment controller then replaces the Machines of the MachineSet with a rolling update,
+	// creating a new MachineSet.
+	MachineSetInPlaceUpdateDeclinedAnnotation = "machineset.cluster.x-k8s.io/in-place-update-declined"
+
 	// ClusterSecretType defines the type of secret created by core components.
 	// Note: This is used by core CAPI, CAPBK, and KCP to determine whether a secret is created by the controllers
 	// themselves or supplied by the user (e.g. bring your own certificates).
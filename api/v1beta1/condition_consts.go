@@ -161,6 +161,13 @@ const (
 
 	// UnhealthyNodeConditionReason is the reason used when a machine's node has one of the MachineHealthCheck's unhealthy conditions.
 	UnhealthyNodeConditionReason = "UnhealthyNode"
+
+	// UnhealthyMachineConditionReason is the reason used when a machine has one of the MachineHealthCheck's unhealthy machine conditions.
+	UnhealthyMachineConditionReason = "UnhealthyMachine"
+
+	// UnhealthyExpressionReason is the reason used when one of the MachineHealthCheck's unhealthy expressions
+	// evaluates to true for a machine.
+	UnhealthyExpressionReason = "UnhealthyExpression"
 )
 
 const (
@@ -218,6 +225,25 @@ const (
 	NodeInspectionFailedReason = "NodeInspectionFailed"
 )
 
+// Conditions and condition Reasons for the in-place update of a Machine.
+
+const (
+	// InPlaceUpdateSucceededCondition reports the status of the last in-place update of a Machine, performed by a
+	// Runtime Extension on behalf of the MachineSet or of the control plane controlling the Machine.
+	InPlaceUpdateSucceededCondition ConditionType = "InPlaceUpdateSucceeded"
+
+	// InPlaceUpdateInProgressReason (Severity=Info) documents a Machine being updated in-place.
+	InPlaceUpdateInProgressReason = "InPlaceUpdateInProgress"
+
+	// InPlaceUpdateFailedReason (Severity=Warning) documents a failure of the in-place update of a Machine; the update is retried.
+	InPlaceUpdateFailedReason = "InPlaceUpdateFailed"
+
+	// InPlaceUpdateDeclinedReason (Severity=Info) documents that the in-place update of a Machine has been declined, either
+	// because no Runtime Extension implements in-place updates or because the Runtime Extension did not accept the update;
+	// the Machine is going to be replaced.
+	InPlaceUpdateDeclinedReason = "InPlaceUpdateDeclined"
+)
+
 // Conditions and condition Reasons for the MachineHealthCheck object.
 
 const (
@@ -228,6 +254,14 @@ const (
 	// TooManyUnhealthyReason is the reason used when too many Machines are unhealthy and the MachineHealthCheck is blocked
 	// from making any further remediations.
 	TooManyUnhealthyReason = "TooManyUnhealthy"
+
+	// UnhealthyExpressionsValidCondition is set on MachineHealthChecks with unhealthy expressions to show whether all the
+	// unhealthy expressions can be compiled; invalid expressions are not evaluated.
+	UnhealthyExpressionsValidCondition ConditionType = "UnhealthyExpressionsValid"
+
+	// InvalidUnhealthyExpressionReason (Severity=Error) is the reason used when one or more unhealthy expressions
+	// of a MachineHealthCheck cannot be compiled.
+	InvalidUnhealthyExpressionReason = "InvalidUnhealthyExpression"
 )
 
 // Conditions and condition Reasons for  MachineDeployments.
@@ -273,6 +273,14 @@ const (
 	// defined by a MachineHealthCheck object.
 	MachineHealthCheckUnhealthyNodeV1Beta2Reason = "UnhealthyNode"
 
+	// MachineHealthCheckUnhealthyMachineV1Beta2Reason surfaces when the machine does not pass the health checks
+	// on Machine conditions defined by a MachineHealthCheck object.
+	MachineHealthCheckUnhealthyMachineV1Beta2Reason = "UnhealthyMachine"
+
+	// MachineHealthCheckUnhealthyExpressionV1Beta2Reason surfaces when one of the unhealthy expressions defined by
+	// a MachineHealthCheck object evaluates to true for the machine.
+	MachineHealthCheckUnhealthyExpressionV1Beta2Reason = "UnhealthyExpression"
+
 	// MachineHealthCheckNodeStartupTimeoutV1Beta2Reason surfaces when the node hosted on the machine does not appear within
 	// the timeout defined by a MachineHealthCheck object.
 	MachineHealthCheckNodeStartupTimeoutV1Beta2Reason = "NodeStartupTimeout"
@@ -568,6 +576,12 @@ type MachineStatus struct {
 	// +optional
 	Deletion *MachineDeletionStatus `json:"deletion,omitempty"`
 
+	// remediation contains information relating to the remediation of the Machine through the
+	// remediation escalation steps of a MachineHealthCheck.
+	// Only present while the Machine is unhealthy and the MachineHealthCheck has remediation escalation steps.
+	// +optional
+	Remediation *MachineRemediationStatus `json:"remediation,omitempty"`
+
 	// v1beta2 groups all the fields that will be added or modified in Machine's status with the V1Beta2 version.
 	// +optional
 	V1Beta2 *MachineV1Beta2Status `json:"v1beta2,omitempty"`
@@ -606,6 +620,147 @@ type MachineDeletionStatus struct {
 	// Only present when the Machine has a deletionTimestamp and waiting for volume detachments had been started.
 	// +optional
 	WaitForNodeVolumeDetachStartTime *metav1.Time `json:"waitForNodeVolumeDetachStartTime,omitempty"`
+
+	// nodeDrain reports the progress of the drain of the node, e.g. which Pods are currently blocking the drain.
+	// Only present when the Machine has a deletionTimestamp and draining the node had been started.
+	// +optional
+	NodeDrain *MachineNodeDrainStatus `json:"nodeDrain,omitempty"`
+}
+
+// MachineNodeDrainBlockingPodReason is the reason why a Pod is blocking the drain of a Node.
+// +kubebuilder:validation:Enum=Terminating;PodDisruptionBudget;EvictionFailed;WaitCompleted
+type MachineNodeDrainBlockingPodReason string
+
+const (
+	// MachineNodeDrainBlockingPodReasonTerminating is used to report that a Pod has a deletionTimestamp
+	// but has not been removed from the Node yet.
+	MachineNodeDrainBlockingPodReasonTerminating MachineNodeDrainBlockingPodReason = "Terminating"
+
+	// MachineNodeDrainBlockingPodReasonPodDisruptionBudget is used to report that the eviction of a Pod
+	// has been rejected because it would violate the Pod's disruption budget.
+	MachineNodeDrainBlockingPodReasonPodDisruptionBudget MachineNodeDrainBlockingPodReason = "PodDisruptionBudget"
+
+	// MachineNodeDrainBlockingPodReasonEvictionFailed is used to report that the eviction of a Pod
+	// failed for reasons other than the Pod's disruption budget.
+	MachineNodeDrainBlockingPodReasonEvictionFailed MachineNodeDrainBlockingPodReason = "EvictionFailed"
+
+	// MachineNodeDrainBlockingPodReasonWaitCompleted is used to report that the drain is waiting for a Pod
+	// to complete without eviction (MachineDrainRule behavior "WaitCompleted").
+	MachineNodeDrainBlockingPodReasonWaitCompleted MachineNodeDrainBlockingPodReason = "WaitCompleted"
+)
+
+// MachineNodeDrainStatus is the progress of the drain of the node of a Machine.
+type MachineNodeDrainStatus struct {
+	// lastAttemptTime is the time of the last attempt to drain the node.
+	// +optional
+	LastAttemptTime *metav1.Time `json:"lastAttemptTime,omitempty"`
+
+	// attempts is the number of attempts to drain the node since the drain started.
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
+
+	// podsTerminating is the number of Pods that have a deletionTimestamp but have not been removed from the node yet.
+	// +optional
+	PodsTerminating int32 `json:"podsTerminating,omitempty"`
+
+	// podsBlockedByPodDisruptionBudget is the number of Pods for which eviction has been rejected
+	// because it would violate the Pod's disruption budget.
+	// +optional
+	PodsBlockedByPodDisruptionBudget int32 `json:"podsBlockedByPodDisruptionBudget,omitempty"`
+
+	// podsFailedEviction is the number of Pods for which eviction failed for reasons other than
+	// the Pod's disruption budget, e.g. errors or webhooks not allowing eviction yet.
+	// +optional
+	PodsFailedEviction int32 `json:"podsFailedEviction,omitempty"`
+
+	// podsWaitingForCompletion is the number of Pods the drain is waiting for to complete without eviction.
+	// +optional
+	PodsWaitingForCompletion int32 `json:"podsWaitingForCompletion,omitempty"`
+
+	// podsPendingEviction is the number of Pods that will be evicted or waited for after the Pods
+	// with a lower drain order have been removed from the node.
+	// +optional
+	PodsPendingEviction int32 `json:"podsPendingEviction,omitempty"`
+
+	// blockingPods is the list of the first Pods that are currently blocking the drain, with the reason.
+	// Pods blocked by disruption budgets are listed first, followed by Pods that failed eviction,
+	// Pods that are terminating and Pods the drain is waiting for to complete.
+	// +optional
+	// +listType=atomic
+	// +kubebuilder:validation:MaxItems=10
+	BlockingPods []MachineNodeDrainBlockingPod `json:"blockingPods,omitempty"`
+}
+
+// MachineNodeDrainBlockingPod is a Pod that is blocking the drain of the node of a Machine.
+type MachineNodeDrainBlockingPod struct {
+	// namespace is the namespace of the Pod.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	Namespace string `json:"namespace"`
+
+	// name is the name of the Pod.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	Name string `json:"name"`
+
+	// reason is the reason why the Pod is blocking the drain.
+	// +required
+	Reason MachineNodeDrainBlockingPodReason `json:"reason"`
+
+	// message is a human-readable message with details, e.g. the error returned by the eviction.
+	// +optional
+	// +kubebuilder:validation:MaxLength=1024
+	Message string `json:"message,omitempty"`
+
+	// attempts is the number of consecutive drain attempts in which the Pod has been blocking the drain
+	// with the same reason.
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
+}
+
+// MachineRemediationStatus is the state of the remediation of a Machine through the remediation escalation
+// steps of a MachineHealthCheck.
+type MachineRemediationStatus struct {
+	// startTime is the time when the remediation of the Machine started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// currentStep is the name of the remediation step in progress.
+	// Empty once all the remediation steps have been exhausted and the Machine is remediated by its owner.
+	// +optional
+	// +kubebuilder:validation:MaxLength=63
+	CurrentStep string `json:"currentStep,omitempty"`
+
+	// steps contains the state of the remediation steps attempted so far.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=10
+	Steps []MachineRemediationStepStatus `json:"steps,omitempty"`
+}
+
+// MachineRemediationStepStatus is the state of a remediation step of a Machine.
+type MachineRemediationStepStatus struct {
+	// name is the name of the remediation step.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// attempts is the number of attempts of the remediation step.
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
+
+	// lastAttemptTime is the time of the last attempt of the remediation step.
+	// +optional
+	LastAttemptTime *metav1.Time `json:"lastAttemptTime,omitempty"`
+
+	// timeoutTime is the time after which the last attempt of the remediation step is considered failed
+	// if the Machine is still unhealthy.
+	// +optional
+	TimeoutTime *metav1.Time `json:"timeoutTime,omitempty"`
 }
 
 // SetTypedPhase sets the Phase field to the string representation of MachinePhase.
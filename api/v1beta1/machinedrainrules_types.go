@@ -27,8 +27,8 @@ const (
 	PodDrainLabel = "cluster.x-k8s.io/drain"
 )
 
-// MachineDrainRuleDrainBehavior defines the drain behavior. Can be either "Drain", "Skip", or "WaitCompleted".
-// +kubebuilder:validation:Enum=Drain;Skip;WaitCompleted
+// MachineDrainRuleDrainBehavior defines the drain behavior. Can be either "Drain", "Skip", "WaitCompleted", "Delete" or "Webhook".
+// +kubebuilder:validation:Enum=Drain;Skip;WaitCompleted;Delete;Webhook
 type MachineDrainRuleDrainBehavior string
 
 const (
@@ -41,6 +41,12 @@ const (
 	// MachineDrainRuleDrainBehaviorWaitCompleted means the Pod should not be evicted,
 	// but overall drain should wait until the Pod completes.
 	MachineDrainRuleDrainBehaviorWaitCompleted MachineDrainRuleDrainBehavior = "WaitCompleted"
+
+	// MachineDrainRuleDrainBehaviorDelete means a Pod should be deleted instead of evicted.
+	MachineDrainRuleDrainBehaviorDelete MachineDrainRuleDrainBehavior = "Delete"
+
+	// MachineDrainRuleDrainBehaviorWebhook means a webhook should be called for a Pod before it is evicted.
+	MachineDrainRuleDrainBehaviorWebhook MachineDrainRuleDrainBehavior = "Webhook"
 )
 
 // MachineDrainRuleSpec defines the spec of a MachineDrainRule.
@@ -116,7 +122,7 @@ type MachineDrainRuleSpec struct {
 // MachineDrainRuleDrainConfig configures if and how Pods are drained.
 type MachineDrainRuleDrainConfig struct {
 	// behavior defines the drain behavior.
-	// Can be either "Drain", "Skip", or "WaitCompleted".
+	// Can be either "Drain", "Skip", "WaitCompleted", "Delete" or "Webhook".
 	// "Drain" means that the Pods to which this MachineDrainRule applies will be drained.
 	// If behavior is set to "Drain" the order in which Pods are drained can be configured
 	// with the order field. When draining Pods of a Node the Pods will be grouped by order
@@ -126,16 +132,51 @@ type MachineDrainRuleDrainConfig struct {
 	// "Skip" means that the Pods to which this MachineDrainRule applies will be skipped during drain.
 	// "WaitCompleted" means that the pods to which this MachineDrainRule applies will never be evicted
 	// and we wait for them to be completed, it is enforced that pods marked with this behavior always have Order=0.
+	// "Delete" means that the Pods to which this MachineDrainRule applies will be deleted instead of evicted,
+	// i.e. PodDisruptionBudgets are not taken into account.
+	// "Webhook" means that the webhook configured in the webhook field is called for each Pod to which this
+	// MachineDrainRule applies, and the Pod is evicted only after the webhook allowed the eviction.
+	// Pods with behavior "Delete" or "Webhook" are drained in order like Pods with behavior "Drain".
 	// +required
 	Behavior MachineDrainRuleDrainBehavior `json:"behavior"`
 
 	// order defines the order in which Pods are drained.
 	// Pods with higher order are drained after Pods with lower order.
-	// order can only be set if behavior is set to "Drain".
+	// order can only be set if behavior is set to "Drain", "Delete" or "Webhook".
 	// If order is not set, 0 will be used.
 	// Valid values for order are from -2147483648 to 2147483647 (inclusive).
 	// +optional
 	Order *int32 `json:"order,omitempty"`
+
+	// webhook configures the webhook that is called for each Pod before it is evicted.
+	// webhook must be set if behavior is set to "Webhook" and must not be set otherwise.
+	// +optional
+	Webhook *MachineDrainRuleDrainWebhook `json:"webhook,omitempty"`
+}
+
+// MachineDrainRuleDrainWebhook configures the webhook that is called for each Pod before it is evicted.
+type MachineDrainRuleDrainWebhook struct {
+	// url is the URL of the webhook, it must use the https scheme.
+	// The webhook is called with a POST request for each Pod before it is evicted,
+	// e.g. to fail over a database primary running in the Pod, and it is called again
+	// on subsequent reconciles until it allows the eviction. Accordingly, webhooks must be idempotent.
+	// The request only contains the namespace, name, labels and owner references of the Pod, and redirects are not followed.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=512
+	URL string `json:"url"`
+
+	// caBundle is a PEM encoded CA bundle which will be used to validate the webhook's server certificate.
+	// If not set, the system trust roots are used.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+
+	// timeoutSeconds is the timeout for a single call of the webhook.
+	// If not set, 10 seconds will be used.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=30
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
 }
 
 // MachineDrainRuleMachineSelector defines to which Machines this MachineDrainRule should be applied.
@@ -39,12 +39,35 @@ const (
 	MachineHealthCheckRemediationAllowedV1Beta2Reason = "RemediationAllowed"
 )
 
+// MachineHealthCheck's UnhealthyExpressionsValid condition and corresponding reasons that will be used in v1Beta2 API version.
+const (
+	// MachineHealthCheckUnhealthyExpressionsValidV1Beta2Condition surfaces whether all the unhealthy expressions
+	// of the MachineHealthCheck can be compiled; invalid expressions are not evaluated.
+	MachineHealthCheckUnhealthyExpressionsValidV1Beta2Condition = "UnhealthyExpressionsValid"
+
+	// MachineHealthCheckUnhealthyExpressionsValidV1Beta2Reason is the reason used when all the unhealthy expressions
+	// of the MachineHealthCheck can be compiled.
+	MachineHealthCheckUnhealthyExpressionsValidV1Beta2Reason = "UnhealthyExpressionsValid"
+
+	// MachineHealthCheckInvalidUnhealthyExpressionV1Beta2Reason is the reason used when one or more unhealthy expressions
+	// of the MachineHealthCheck cannot be compiled.
+	MachineHealthCheckInvalidUnhealthyExpressionV1Beta2Reason = "InvalidUnhealthyExpression"
+)
+
 var (
 	// DefaultNodeStartupTimeout is the time allowed for a node to start up.
 	// Can be made longer as part of spec if required for particular provider.
 	// 10 minutes should allow the instance to start and the node to join the
 	// cluster on most providers.
 	DefaultNodeStartupTimeout = metav1.Duration{Duration: 10 * time.Minute}
+
+	// DefaultRemediationStepTimeout is the time allowed for a Machine to become healthy
+	// after an attempt of a remediation escalation step.
+	DefaultRemediationStepTimeout = metav1.Duration{Duration: 10 * time.Minute}
+
+	// DefaultRemediationStabilizationPeriod is the time a Machine must stay healthy
+	// before its remediation escalation is reset.
+	DefaultRemediationStabilizationPeriod = metav1.Duration{Duration: 10 * time.Minute}
 )
 
 // ANCHOR: MachineHealthCheckSpec
@@ -65,6 +88,26 @@ type MachineHealthCheckSpec struct {
 	// +optional
 	UnhealthyConditions []UnhealthyCondition `json:"unhealthyConditions,omitempty"`
 
+	// unhealthyMachineConditions contains a list of the Machine conditions that determine
+	// whether a machine is considered unhealthy, e.g. InfrastructureReady reported as False by
+	// the infrastructure provider. The conditions are combined in a logical OR, i.e. if any of
+	// the conditions is met, the machine is unhealthy.
+	//
+	// +optional
+	// +listType=atomic
+	// +kubebuilder:validation:MaxItems=100
+	UnhealthyMachineConditions []UnhealthyMachineCondition `json:"unhealthyMachineConditions,omitempty"`
+
+	// unhealthyExpressions contains a list of CEL expressions that determine whether
+	// a machine is considered unhealthy. The expressions are combined in a logical OR,
+	// i.e. if any of the expressions evaluates to true, the machine is unhealthy.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=100
+	UnhealthyExpressions []UnhealthyExpression `json:"unhealthyExpressions,omitempty"`
+
 	// maxUnhealthy specifies the maximum number of unhealthy machines allowed.
 	// Any further remediation is only allowed if at most "maxUnhealthy" machines selected by
 	// "selector" are not healthy.
@@ -110,10 +153,95 @@ type MachineHealthCheckSpec struct {
 	// a controller that lives outside of Cluster API.
 	// +optional
 	RemediationTemplate *corev1.ObjectReference `json:"remediationTemplate,omitempty"`
+
+	// remediation configures how unhealthy machines are remediated.
+	//
+	// When remediation escalation steps are set, the MachineHealthCheck controller
+	// goes through the steps in order, e.g. first reboots the machine and then reprovisions it in place,
+	// and the machine is remediated by its owner, i.e. replaced, only after all the steps have been exhausted.
+	// This field cannot be used together with remediationTemplate.
+	// +optional
+	Remediation *MachineHealthCheckRemediation `json:"remediation,omitempty"`
 }
 
 // ANCHOR_END: MachineHealthCHeckSpec
 
+// ANCHOR: MachineHealthCheckRemediation
+
+// MachineHealthCheckRemediation configures how unhealthy machines are remediated.
+type MachineHealthCheckRemediation struct {
+	// escalation is the ordered list of remediation steps.
+	// The first step is started when a machine becomes unhealthy; if the machine is still unhealthy
+	// after the timeout of the last attempt of a step, the next step is started.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=10
+	Escalation []MachineHealthCheckRemediationStep `json:"escalation,omitempty"`
+
+	// stabilizationPeriod is the time a machine must stay healthy before its remediation escalation is reset;
+	// a machine becoming unhealthy again within this period continues from the step it reached
+	// instead of starting again from the first step.
+	// Defaults to 10 minutes.
+	// +optional
+	StabilizationPeriod *metav1.Duration `json:"stabilizationPeriod,omitempty"`
+}
+
+// MachineHealthCheckRemediationAction is the remediation action performed during a remediation step.
+// +kubebuilder:validation:Enum=Reboot;Reprovision
+type MachineHealthCheckRemediationAction string
+
+const (
+	// MachineHealthCheckRemediationActionReboot is used for remediation steps rebooting the machine.
+	MachineHealthCheckRemediationActionReboot MachineHealthCheckRemediationAction = "Reboot"
+
+	// MachineHealthCheckRemediationActionReprovision is used for remediation steps reprovisioning the machine in place.
+	MachineHealthCheckRemediationActionReprovision MachineHealthCheckRemediationAction = "Reprovision"
+)
+
+// MachineHealthCheckRemediationStep is a step of the remediation escalation of a MachineHealthCheck.
+// Exactly one of templateRef and extension must be set.
+type MachineHealthCheckRemediationStep struct {
+	// name of the remediation step. It must be unique within the remediation escalation steps.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// action is the remediation action performed during this step, one of Reboot or Reprovision.
+	// +required
+	Action MachineHealthCheckRemediationAction `json:"action"`
+
+	// templateRef is a reference to a remediation template provided by an infrastructure provider.
+	// When set, the MachineHealthCheck controller creates a new object from the template referenced
+	// for each attempt, and hands off remediation of the machine to a controller that lives outside of Cluster API.
+	// +optional
+	TemplateRef *corev1.ObjectReference `json:"templateRef,omitempty"`
+
+	// extension is the name of the Runtime Extension handler implementing the RemediateMachine hook,
+	// which is called for each attempt.
+	// Note: This field requires the RuntimeSDK feature gate to be enabled.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=512
+	Extension *string `json:"extension,omitempty"`
+
+	// timeout is the time to wait for the machine to become healthy after an attempt,
+	// before starting another attempt or the next step.
+	// Defaults to 10 minutes.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// maxAttempts is the number of attempts of this step before the next step is started.
+	// Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	MaxAttempts *int32 `json:"maxAttempts,omitempty"`
+}
+
+// ANCHOR_END: MachineHealthCheckRemediation
+
 // ANCHOR: UnhealthyCondition
 
 // UnhealthyCondition represents a Node condition type and value with a timeout
@@ -139,6 +267,70 @@ type UnhealthyCondition struct {
 
 // ANCHOR_END: UnhealthyCondition
 
+// ANCHOR: UnhealthyMachineCondition
+
+// UnhealthyMachineCondition represents a Machine condition type and value with a timeout
+// specified as a duration. When the named condition has been in the given
+// status for at least the timeout value, a machine is considered unhealthy.
+type UnhealthyMachineCondition struct {
+	// type of Machine condition.
+	// Both the conditions in status.conditions and in status.v1beta2.conditions are considered,
+	// the latter take precedence if a condition with the same type exists in both.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=316
+	Type string `json:"type"`
+
+	// status of the condition, one of True, False, Unknown.
+	// +required
+	// +kubebuilder:validation:Enum=True;False;Unknown
+	Status metav1.ConditionStatus `json:"status"`
+
+	// timeout is the duration that a machine must be in a given status for,
+	// after which the machine is considered unhealthy.
+	// For example, with a value of "1h", the machine must match the status
+	// for at least 1 hour before being considered unhealthy.
+	// +required
+	Timeout metav1.Duration `json:"timeout"`
+}
+
+// ANCHOR_END: UnhealthyMachineCondition
+
+// ANCHOR: UnhealthyExpression
+
+// UnhealthyExpression represents a CEL expression that determines whether a machine is unhealthy.
+type UnhealthyExpression struct {
+	// name of the expression. It is used to report which expression determined
+	// that the machine is unhealthy.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// expression is a CEL expression which must evaluate to a boolean.
+	// If the expression evaluates to true, the machine is considered unhealthy.
+	//
+	// The following variables are available:
+	// - machine: the Machine object.
+	// - node: the Node object of the Machine, or null if the Machine does not have a Node.
+	// - now: the current time as a timestamp.
+	//
+	// Expressions are evaluated again at least every minute, so that expressions
+	// depending on now are eventually reporting the machine as unhealthy.
+	//
+	// Example: the Node has been NotReady and unschedulable for more than 10 minutes:
+	//   node != null && node.spec.?unschedulable.orValue(false) &&
+	//     node.status.conditions.exists(c, c.type == 'Ready' && c.status != 'True' &&
+	//       now - timestamp(c.lastTransitionTime) > duration('10m'))
+	//
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=4096
+	Expression string `json:"expression"`
+}
+
+// ANCHOR_END: UnhealthyExpression
+
 // ANCHOR: MachineHealthCheckStatus
 
 // MachineHealthCheckStatus defines the observed state of MachineHealthCheck.
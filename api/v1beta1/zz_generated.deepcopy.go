@@ -1134,6 +1134,11 @@ func (in *MachineDeletionStatus) DeepCopyInto(out *MachineDeletionStatus) {
 		in, out := &in.WaitForNodeVolumeDetachStartTime, &out.WaitForNodeVolumeDetachStartTime
 		*out = (*in).DeepCopy()
 	}
+	if in.NodeDrain != nil {
+		in, out := &in.NodeDrain, &out.NodeDrain
+		*out = new(MachineNodeDrainStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineDeletionStatus.
@@ -1556,6 +1561,11 @@ func (in *MachineDrainRuleDrainConfig) DeepCopyInto(out *MachineDrainRuleDrainCo
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(MachineDrainRuleDrainWebhook)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineDrainRuleDrainConfig.
@@ -1568,6 +1578,31 @@ func (in *MachineDrainRuleDrainConfig) DeepCopy() *MachineDrainRuleDrainConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineDrainRuleDrainWebhook) DeepCopyInto(out *MachineDrainRuleDrainWebhook) {
+	*out = *in
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineDrainRuleDrainWebhook.
+func (in *MachineDrainRuleDrainWebhook) DeepCopy() *MachineDrainRuleDrainWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineDrainRuleDrainWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachineDrainRuleList) DeepCopyInto(out *MachineDrainRuleList) {
 	*out = *in
@@ -1779,6 +1814,68 @@ func (in *MachineHealthCheckList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineHealthCheckRemediation) DeepCopyInto(out *MachineHealthCheckRemediation) {
+	*out = *in
+	if in.Escalation != nil {
+		in, out := &in.Escalation, &out.Escalation
+		*out = make([]MachineHealthCheckRemediationStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StabilizationPeriod != nil {
+		in, out := &in.StabilizationPeriod, &out.StabilizationPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineHealthCheckRemediation.
+func (in *MachineHealthCheckRemediation) DeepCopy() *MachineHealthCheckRemediation {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineHealthCheckRemediation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineHealthCheckRemediationStep) DeepCopyInto(out *MachineHealthCheckRemediationStep) {
+	*out = *in
+	if in.TemplateRef != nil {
+		in, out := &in.TemplateRef, &out.TemplateRef
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.Extension != nil {
+		in, out := &in.Extension, &out.Extension
+		*out = new(string)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxAttempts != nil {
+		in, out := &in.MaxAttempts, &out.MaxAttempts
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineHealthCheckRemediationStep.
+func (in *MachineHealthCheckRemediationStep) DeepCopy() *MachineHealthCheckRemediationStep {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineHealthCheckRemediationStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachineHealthCheckSpec) DeepCopyInto(out *MachineHealthCheckSpec) {
 	*out = *in
@@ -1788,6 +1885,16 @@ func (in *MachineHealthCheckSpec) DeepCopyInto(out *MachineHealthCheckSpec) {
 		*out = make([]UnhealthyCondition, len(*in))
 		copy(*out, *in)
 	}
+	if in.UnhealthyMachineConditions != nil {
+		in, out := &in.UnhealthyMachineConditions, &out.UnhealthyMachineConditions
+		*out = make([]UnhealthyMachineCondition, len(*in))
+		copy(*out, *in)
+	}
+	if in.UnhealthyExpressions != nil {
+		in, out := &in.UnhealthyExpressions, &out.UnhealthyExpressions
+		*out = make([]UnhealthyExpression, len(*in))
+		copy(*out, *in)
+	}
 	if in.MaxUnhealthy != nil {
 		in, out := &in.MaxUnhealthy, &out.MaxUnhealthy
 		*out = new(intstr.IntOrString)
@@ -1808,6 +1915,11 @@ func (in *MachineHealthCheckSpec) DeepCopyInto(out *MachineHealthCheckSpec) {
 		*out = new(v1.ObjectReference)
 		**out = **in
 	}
+	if in.Remediation != nil {
+		in, out := &in.Remediation, &out.Remediation
+		*out = new(MachineHealthCheckRemediation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineHealthCheckSpec.
@@ -1942,6 +2054,45 @@ func (in *MachineNamingStrategy) DeepCopy() *MachineNamingStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineNodeDrainBlockingPod) DeepCopyInto(out *MachineNodeDrainBlockingPod) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineNodeDrainBlockingPod.
+func (in *MachineNodeDrainBlockingPod) DeepCopy() *MachineNodeDrainBlockingPod {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineNodeDrainBlockingPod)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineNodeDrainStatus) DeepCopyInto(out *MachineNodeDrainStatus) {
+	*out = *in
+	if in.LastAttemptTime != nil {
+		in, out := &in.LastAttemptTime, &out.LastAttemptTime
+		*out = (*in).DeepCopy()
+	}
+	if in.BlockingPods != nil {
+		in, out := &in.BlockingPods, &out.BlockingPods
+		*out = make([]MachineNodeDrainBlockingPod, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineNodeDrainStatus.
+func (in *MachineNodeDrainStatus) DeepCopy() *MachineNodeDrainStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineNodeDrainStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachinePoolClass) DeepCopyInto(out *MachinePoolClass) {
 	*out = *in
@@ -2114,6 +2265,55 @@ func (in *MachineReadinessGate) DeepCopy() *MachineReadinessGate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineRemediationStatus) DeepCopyInto(out *MachineRemediationStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]MachineRemediationStepStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineRemediationStatus.
+func (in *MachineRemediationStatus) DeepCopy() *MachineRemediationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineRemediationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineRemediationStepStatus) DeepCopyInto(out *MachineRemediationStepStatus) {
+	*out = *in
+	if in.LastAttemptTime != nil {
+		in, out := &in.LastAttemptTime, &out.LastAttemptTime
+		*out = (*in).DeepCopy()
+	}
+	if in.TimeoutTime != nil {
+		in, out := &in.TimeoutTime, &out.TimeoutTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineRemediationStepStatus.
+func (in *MachineRemediationStepStatus) DeepCopy() *MachineRemediationStepStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineRemediationStepStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachineRollingUpdateDeployment) DeepCopyInto(out *MachineRollingUpdateDeployment) {
 	*out = *in
@@ -2404,6 +2604,11 @@ func (in *MachineStatus) DeepCopyInto(out *MachineStatus) {
 		*out = new(MachineDeletionStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Remediation != nil {
+		in, out := &in.Remediation, &out.Remediation
+		*out = new(MachineRemediationStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.V1Beta2 != nil {
 		in, out := &in.V1Beta2, &out.V1Beta2
 		*out = new(MachineV1Beta2Status)
@@ -2681,6 +2886,37 @@ func (in *UnhealthyCondition) DeepCopy() *UnhealthyCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnhealthyExpression) DeepCopyInto(out *UnhealthyExpression) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnhealthyExpression.
+func (in *UnhealthyExpression) DeepCopy() *UnhealthyExpression {
+	if in == nil {
+		return nil
+	}
+	out := new(UnhealthyExpression)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnhealthyMachineCondition) DeepCopyInto(out *UnhealthyMachineCondition) {
+	*out = *in
+	out.Timeout = in.Timeout
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnhealthyMachineCondition.
+func (in *UnhealthyMachineCondition) DeepCopy() *UnhealthyMachineCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(UnhealthyMachineCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ValidationRule) DeepCopyInto(out *ValidationRule) {
 	*out = *in
@@ -76,6 +76,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineDeploymentVariables":               schema_sigsk8sio_cluster_api_api_v1beta1_MachineDeploymentVariables(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineDrainRule":                         schema_sigsk8sio_cluster_api_api_v1beta1_MachineDrainRule(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineDrainRuleDrainConfig":              schema_sigsk8sio_cluster_api_api_v1beta1_MachineDrainRuleDrainConfig(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.MachineDrainRuleDrainWebhook":             schema_sigsk8sio_cluster_api_api_v1beta1_MachineDrainRuleDrainWebhook(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineDrainRuleList":                     schema_sigsk8sio_cluster_api_api_v1beta1_MachineDrainRuleList(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineDrainRuleMachineSelector":          schema_sigsk8sio_cluster_api_api_v1beta1_MachineDrainRuleMachineSelector(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineDrainRulePodSelector":              schema_sigsk8sio_cluster_api_api_v1beta1_MachineDrainRulePodSelector(ref),
@@ -83,18 +84,24 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineHealthCheck":                       schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheck(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineHealthCheckClass":                  schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckClass(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineHealthCheckList":                   schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckList(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.MachineHealthCheckRemediation":            schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckRemediation(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.MachineHealthCheckRemediationStep":        schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckRemediationStep(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineHealthCheckSpec":                   schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckSpec(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineHealthCheckStatus":                 schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckStatus(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineHealthCheckTopology":               schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckTopology(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineHealthCheckV1Beta2Status":          schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckV1Beta2Status(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineList":                              schema_sigsk8sio_cluster_api_api_v1beta1_MachineList(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineNamingStrategy":                    schema_sigsk8sio_cluster_api_api_v1beta1_MachineNamingStrategy(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.MachineNodeDrainBlockingPod":              schema_sigsk8sio_cluster_api_api_v1beta1_MachineNodeDrainBlockingPod(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.MachineNodeDrainStatus":                   schema_sigsk8sio_cluster_api_api_v1beta1_MachineNodeDrainStatus(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachinePoolClass":                         schema_sigsk8sio_cluster_api_api_v1beta1_MachinePoolClass(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachinePoolClassNamingStrategy":           schema_sigsk8sio_cluster_api_api_v1beta1_MachinePoolClassNamingStrategy(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachinePoolClassTemplate":                 schema_sigsk8sio_cluster_api_api_v1beta1_MachinePoolClassTemplate(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachinePoolTopology":                      schema_sigsk8sio_cluster_api_api_v1beta1_MachinePoolTopology(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachinePoolVariables":                     schema_sigsk8sio_cluster_api_api_v1beta1_MachinePoolVariables(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineReadinessGate":                     schema_sigsk8sio_cluster_api_api_v1beta1_MachineReadinessGate(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.MachineRemediationStatus":                 schema_sigsk8sio_cluster_api_api_v1beta1_MachineRemediationStatus(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.MachineRemediationStepStatus":             schema_sigsk8sio_cluster_api_api_v1beta1_MachineRemediationStepStatus(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineRollingUpdateDeployment":           schema_sigsk8sio_cluster_api_api_v1beta1_MachineRollingUpdateDeployment(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineSet":                               schema_sigsk8sio_cluster_api_api_v1beta1_MachineSet(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineSetList":                           schema_sigsk8sio_cluster_api_api_v1beta1_MachineSetList(ref),
@@ -115,6 +122,8 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"sigs.k8s.io/cluster-api/api/v1beta1.RemediationStrategy":                      schema_sigsk8sio_cluster_api_api_v1beta1_RemediationStrategy(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.Topology":                                 schema_sigsk8sio_cluster_api_api_v1beta1_Topology(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.UnhealthyCondition":                       schema_sigsk8sio_cluster_api_api_v1beta1_UnhealthyCondition(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.UnhealthyExpression":                      schema_sigsk8sio_cluster_api_api_v1beta1_UnhealthyExpression(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.UnhealthyMachineCondition":                schema_sigsk8sio_cluster_api_api_v1beta1_UnhealthyMachineCondition(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.ValidationRule":                           schema_sigsk8sio_cluster_api_api_v1beta1_ValidationRule(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.VariableSchema":                           schema_sigsk8sio_cluster_api_api_v1beta1_VariableSchema(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.VariableSchemaMetadata":                   schema_sigsk8sio_cluster_api_api_v1beta1_VariableSchemaMetadata(ref),
@@ -1930,11 +1939,17 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineDeletionStatus(ref common.R
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
+					"nodeDrain": {
+						SchemaProps: spec.SchemaProps{
+							Description: "nodeDrain reports the progress of the drain of the node, e.g. which Pods are currently blocking the drain. Only present when the Machine has a deletionTimestamp and draining the node had been started.",
+							Ref:         ref("sigs.k8s.io/cluster-api/api/v1beta1.MachineNodeDrainStatus"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time", "sigs.k8s.io/cluster-api/api/v1beta1.MachineNodeDrainStatus"},
 	}
 }
 
@@ -2636,7 +2651,7 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineDrainRuleDrainConfig(ref co
 				Properties: map[string]spec.Schema{
 					"behavior": {
 						SchemaProps: spec.SchemaProps{
-							Description: "behavior defines the drain behavior. Can be either \"Drain\", \"Skip\", or \"WaitCompleted\". \"Drain\" means that the Pods to which this MachineDrainRule applies will be drained. If behavior is set to \"Drain\" the order in which Pods are drained can be configured with the order field. When draining Pods of a Node the Pods will be grouped by order and one group after another will be drained (by increasing order). Cluster API will wait until all Pods of a group are terminated / removed from the Node before starting with the next group. \"Skip\" means that the Pods to which this MachineDrainRule applies will be skipped during drain. \"WaitCompleted\" means that the pods to which this MachineDrainRule applies will never be evicted and we wait for them to be completed, it is enforced that pods marked with this behavior always have Order=0.",
+							Description: "behavior defines the drain behavior. Can be either \"Drain\", \"Skip\", \"WaitCompleted\", \"Delete\" or \"Webhook\". \"Drain\" means that the Pods to which this MachineDrainRule applies will be drained. If behavior is set to \"Drain\" the order in which Pods are drained can be configured with the order field. When draining Pods of a Node the Pods will be grouped by order and one group after another will be drained (by increasing order). Cluster API will wait until all Pods of a group are terminated / removed from the Node before starting with the next group. \"Skip\" means that the Pods to which this MachineDrainRule applies will be skipped during drain. \"WaitCompleted\" means that the pods to which this MachineDrainRule applies will never be evicted and we wait for them to be completed, it is enforced that pods marked with this behavior always have Order=0. \"Delete\" means that the Pods to which this MachineDrainRule applies will be deleted instead of evicted, i.e. PodDisruptionBudgets are not taken into account. \"Webhook\" means that the webhook configured in the webhook field is called for each Pod to which this MachineDrainRule applies, and the Pod is evicted only after the webhook allowed the eviction. Pods with behavior \"Delete\" or \"Webhook\" are drained in order like Pods with behavior \"Drain\".",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
@@ -2644,15 +2659,59 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineDrainRuleDrainConfig(ref co
 					},
 					"order": {
 						SchemaProps: spec.SchemaProps{
-							Description: "order defines the order in which Pods are drained. Pods with higher order are drained after Pods with lower order. order can only be set if behavior is set to \"Drain\". If order is not set, 0 will be used. Valid values for order are from -2147483648 to 2147483647 (inclusive).",
+							Description: "order defines the order in which Pods are drained. Pods with higher order are drained after Pods with lower order. order can only be set if behavior is set to \"Drain\", \"Delete\" or \"Webhook\". If order is not set, 0 will be used. Valid values for order are from -2147483648 to 2147483647 (inclusive).",
 							Type:        []string{"integer"},
 							Format:      "int32",
 						},
 					},
+					"webhook": {
+						SchemaProps: spec.SchemaProps{
+							Description: "webhook configures the webhook that is called for each Pod before it is evicted. webhook must be set if behavior is set to \"Webhook\" and must not be set otherwise.",
+							Ref:         ref("sigs.k8s.io/cluster-api/api/v1beta1.MachineDrainRuleDrainWebhook"),
+						},
+					},
 				},
 				Required: []string{"behavior"},
 			},
 		},
+		Dependencies: []string{
+			"sigs.k8s.io/cluster-api/api/v1beta1.MachineDrainRuleDrainWebhook"},
+	}
+}
+
+func schema_sigsk8sio_cluster_api_api_v1beta1_MachineDrainRuleDrainWebhook(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MachineDrainRuleDrainWebhook configures the webhook that is called for each Pod before it is evicted.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"url": {
+						SchemaProps: spec.SchemaProps{
+							Description: "url is the URL of the webhook, it must use the https scheme. The webhook is called with a POST request for each Pod before it is evicted, e.g. to fail over a database primary running in the Pod, and it is called again on subsequent reconciles until it allows the eviction. Accordingly, webhooks must be idempotent. The request only contains the namespace, name, labels and owner references of the Pod, and redirects are not followed.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"caBundle": {
+						SchemaProps: spec.SchemaProps{
+							Description: "caBundle is a PEM encoded CA bundle which will be used to validate the webhook's server certificate. If not set, the system trust roots are used.",
+							Type:        []string{"string"},
+							Format:      "byte",
+						},
+					},
+					"timeoutSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "timeoutSeconds is the timeout for a single call of the webhook. If not set, 10 seconds will be used.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+				Required: []string{"url"},
+			},
+		},
 	}
 }
 
@@ -2973,6 +3032,107 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckList(ref common.
 	}
 }
 
+func schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckRemediation(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MachineHealthCheckRemediation configures how unhealthy machines are remediated.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"escalation": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "escalation is the ordered list of remediation steps. The first step is started when a machine becomes unhealthy; if the machine is still unhealthy after the timeout of the last attempt of a step, the next step is started.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("sigs.k8s.io/cluster-api/api/v1beta1.MachineHealthCheckRemediationStep"),
+									},
+								},
+							},
+						},
+					},
+					"stabilizationPeriod": {
+						SchemaProps: spec.SchemaProps{
+							Description: "stabilizationPeriod is the time a machine must stay healthy before its remediation escalation is reset; a machine becoming unhealthy again within this period continues from the step it reached instead of starting again from the first step. Defaults to 10 minutes.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "sigs.k8s.io/cluster-api/api/v1beta1.MachineHealthCheckRemediationStep"},
+	}
+}
+
+func schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckRemediationStep(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MachineHealthCheckRemediationStep is a step of the remediation escalation of a MachineHealthCheck. Exactly one of templateRef and extension must be set.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "name of the remediation step. It must be unique within the remediation escalation steps.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"action": {
+						SchemaProps: spec.SchemaProps{
+							Description: "action is the remediation action performed during this step, one of Reboot or Reprovision.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"templateRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "templateRef is a reference to a remediation template provided by an infrastructure provider. When set, the MachineHealthCheck controller creates a new object from the template referenced for each attempt, and hands off remediation of the machine to a controller that lives outside of Cluster API.",
+							Ref:         ref("k8s.io/api/core/v1.ObjectReference"),
+						},
+					},
+					"extension": {
+						SchemaProps: spec.SchemaProps{
+							Description: "extension is the name of the Runtime Extension handler implementing the RemediateMachine hook, which is called for each attempt. Note: This field requires the RuntimeSDK feature gate to be enabled.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"timeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "timeout is the time to wait for the machine to become healthy after an attempt, before starting another attempt or the next step. Defaults to 10 minutes.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"maxAttempts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "maxAttempts is the number of attempts of this step before the next step is started. Defaults to 1.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+				Required: []string{"name", "action"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.ObjectReference", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
 func schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -3009,6 +3169,47 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckSpec(ref common.
 							},
 						},
 					},
+					"unhealthyMachineConditions": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "unhealthyMachineConditions contains a list of the Machine conditions that determine whether a machine is considered unhealthy, e.g. InfrastructureReady reported as False by the infrastructure provider. The conditions are combined in a logical OR, i.e. if any of the conditions is met, the machine is unhealthy.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("sigs.k8s.io/cluster-api/api/v1beta1.UnhealthyMachineCondition"),
+									},
+								},
+							},
+						},
+					},
+					"unhealthyExpressions": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "unhealthyExpressions contains a list of CEL expressions that determine whether a machine is considered unhealthy. The expressions are combined in a logical OR, i.e. if any of the expressions evaluates to true, the machine is unhealthy.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("sigs.k8s.io/cluster-api/api/v1beta1.UnhealthyExpression"),
+									},
+								},
+							},
+						},
+					},
 					"maxUnhealthy": {
 						SchemaProps: spec.SchemaProps{
 							Description: "maxUnhealthy specifies the maximum number of unhealthy machines allowed. Any further remediation is only allowed if at most \"maxUnhealthy\" machines selected by \"selector\" are not healthy.\n\nDeprecated: This field is deprecated and is going to be removed in the next apiVersion. Please see https://github.com/kubernetes-sigs/cluster-api/issues/10722 for more details.",
@@ -3034,12 +3235,18 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckSpec(ref common.
 							Ref:         ref("k8s.io/api/core/v1.ObjectReference"),
 						},
 					},
+					"remediation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "remediation configures how unhealthy machines are remediated.\n\nWhen remediation escalation steps are set, the MachineHealthCheck controller goes through the steps in order, e.g. first reboots the machine and then reprovisions it in place, and the machine is remediated by its owner, i.e. replaced, only after all the steps have been exhausted. This field cannot be used together with remediationTemplate.",
+							Ref:         ref("sigs.k8s.io/cluster-api/api/v1beta1.MachineHealthCheckRemediation"),
+						},
+					},
 				},
 				Required: []string{"clusterName", "selector"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/api/core/v1.ObjectReference", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector", "k8s.io/apimachinery/pkg/util/intstr.IntOrString", "sigs.k8s.io/cluster-api/api/v1beta1.UnhealthyCondition"},
+			"k8s.io/api/core/v1.ObjectReference", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector", "k8s.io/apimachinery/pkg/util/intstr.IntOrString", "sigs.k8s.io/cluster-api/api/v1beta1.MachineHealthCheckRemediation", "sigs.k8s.io/cluster-api/api/v1beta1.UnhealthyCondition", "sigs.k8s.io/cluster-api/api/v1beta1.UnhealthyExpression", "sigs.k8s.io/cluster-api/api/v1beta1.UnhealthyMachineCondition"},
 	}
 }
 
@@ -3291,6 +3498,140 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineNamingStrategy(ref common.R
 	}
 }
 
+func schema_sigsk8sio_cluster_api_api_v1beta1_MachineNodeDrainBlockingPod(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MachineNodeDrainBlockingPod is a Pod that is blocking the drain of the node of a Machine.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "namespace is the namespace of the Pod.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "name is the name of the Pod.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "reason is the reason why the Pod is blocking the drain.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "message is a human-readable message with details, e.g. the error returned by the eviction.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"attempts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "attempts is the number of consecutive drain attempts in which the Pod has been blocking the drain with the same reason.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+				Required: []string{"namespace", "name", "reason"},
+			},
+		},
+	}
+}
+
+func schema_sigsk8sio_cluster_api_api_v1beta1_MachineNodeDrainStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MachineNodeDrainStatus is the progress of the drain of the node of a Machine.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"lastAttemptTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "lastAttemptTime is the time of the last attempt to drain the node.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"attempts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "attempts is the number of attempts to drain the node since the drain started.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"podsTerminating": {
+						SchemaProps: spec.SchemaProps{
+							Description: "podsTerminating is the number of Pods that have a deletionTimestamp but have not been removed from the node yet.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"podsBlockedByPodDisruptionBudget": {
+						SchemaProps: spec.SchemaProps{
+							Description: "podsBlockedByPodDisruptionBudget is the number of Pods for which eviction has been rejected because it would violate the Pod's disruption budget.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"podsFailedEviction": {
+						SchemaProps: spec.SchemaProps{
+							Description: "podsFailedEviction is the number of Pods for which eviction failed for reasons other than the Pod's disruption budget, e.g. errors or webhooks not allowing eviction yet.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"podsWaitingForCompletion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "podsWaitingForCompletion is the number of Pods the drain is waiting for to complete without eviction.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"podsPendingEviction": {
+						SchemaProps: spec.SchemaProps{
+							Description: "podsPendingEviction is the number of Pods that will be evicted or waited for after the Pods with a lower drain order have been removed from the node.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"blockingPods": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "blockingPods is the list of the first Pods that are currently blocking the drain, with the reason. Pods blocked by disruption budgets are listed first, followed by Pods that failed eviction, Pods that are terminating and Pods the drain is waiting for to complete.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("sigs.k8s.io/cluster-api/api/v1beta1.MachineNodeDrainBlockingPod"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time", "sigs.k8s.io/cluster-api/api/v1beta1.MachineNodeDrainBlockingPod"},
+	}
+}
+
 func schema_sigsk8sio_cluster_api_api_v1beta1_MachinePoolClass(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -3576,6 +3917,99 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineReadinessGate(ref common.Re
 	}
 }
 
+func schema_sigsk8sio_cluster_api_api_v1beta1_MachineRemediationStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MachineRemediationStatus is the state of the remediation of a Machine through the remediation escalation steps of a MachineHealthCheck.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"startTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "startTime is the time when the remediation of the Machine started.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"currentStep": {
+						SchemaProps: spec.SchemaProps{
+							Description: "currentStep is the name of the remediation step in progress. Empty once all the remediation steps have been exhausted and the Machine is remediated by its owner.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"steps": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "steps contains the state of the remediation steps attempted so far.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("sigs.k8s.io/cluster-api/api/v1beta1.MachineRemediationStepStatus"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time", "sigs.k8s.io/cluster-api/api/v1beta1.MachineRemediationStepStatus"},
+	}
+}
+
+func schema_sigsk8sio_cluster_api_api_v1beta1_MachineRemediationStepStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MachineRemediationStepStatus is the state of a remediation step of a Machine.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "name is the name of the remediation step.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"attempts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "attempts is the number of attempts of the remediation step.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"lastAttemptTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "lastAttemptTime is the time of the last attempt of the remediation step.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"timeoutTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "timeoutTime is the time after which the last attempt of the remediation step is considered failed if the Machine is still unhealthy.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
 func schema_sigsk8sio_cluster_api_api_v1beta1_MachineRollingUpdateDeployment(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -4132,6 +4566,12 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineStatus(ref common.Reference
 							Ref:         ref("sigs.k8s.io/cluster-api/api/v1beta1.MachineDeletionStatus"),
 						},
 					},
+					"remediation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "remediation contains information relating to the remediation of the Machine through the remediation escalation steps of a MachineHealthCheck. Only present while the Machine is unhealthy and the MachineHealthCheck has remediation escalation steps.",
+							Ref:         ref("sigs.k8s.io/cluster-api/api/v1beta1.MachineRemediationStatus"),
+						},
+					},
 					"v1beta2": {
 						SchemaProps: spec.SchemaProps{
 							Description: "v1beta2 groups all the fields that will be added or modified in Machine's status with the V1Beta2 version.",
@@ -4142,7 +4582,7 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineStatus(ref common.Reference
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/api/core/v1.NodeSystemInfo", "k8s.io/api/core/v1.ObjectReference", "k8s.io/apimachinery/pkg/apis/meta/v1.Time", "sigs.k8s.io/cluster-api/api/v1beta1.Condition", "sigs.k8s.io/cluster-api/api/v1beta1.MachineAddress", "sigs.k8s.io/cluster-api/api/v1beta1.MachineDeletionStatus", "sigs.k8s.io/cluster-api/api/v1beta1.MachineV1Beta2Status"},
+			"k8s.io/api/core/v1.NodeSystemInfo", "k8s.io/api/core/v1.ObjectReference", "k8s.io/apimachinery/pkg/apis/meta/v1.Time", "sigs.k8s.io/cluster-api/api/v1beta1.Condition", "sigs.k8s.io/cluster-api/api/v1beta1.MachineAddress", "sigs.k8s.io/cluster-api/api/v1beta1.MachineDeletionStatus", "sigs.k8s.io/cluster-api/api/v1beta1.MachineRemediationStatus", "sigs.k8s.io/cluster-api/api/v1beta1.MachineV1Beta2Status"},
 	}
 }
 
@@ -4598,6 +5038,74 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_UnhealthyCondition(ref common.Refe
 	}
 }
 
+func schema_sigsk8sio_cluster_api_api_v1beta1_UnhealthyExpression(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UnhealthyExpression represents a CEL expression that determines whether a machine is unhealthy.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "name of the expression. It is used to report which expression determined that the machine is unhealthy.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"expression": {
+						SchemaProps: spec.SchemaProps{
+							Description: "expression is a CEL expression which must evaluate to a boolean. If the expression evaluates to true, the machine is considered unhealthy.\n\nThe following variables are available: - machine: the Machine object. - node: the Node object of the Machine, or null if the Machine does not have a Node. - now: the current time as a timestamp.\n\nExpressions are evaluated again at least every minute, so that expressions depending on now are eventually reporting the machine as unhealthy.\n\nExample: the Node has been NotReady and unschedulable for more than 10 minutes:\n  node != null && node.spec.?unschedulable.orValue(false) &&\n    node.status.conditions.exists(c, c.type == 'Ready' && c.status != 'True' &&\n      now - timestamp(c.lastTransitionTime) > duration('10m'))",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name", "expression"},
+			},
+		},
+	}
+}
+
+func schema_sigsk8sio_cluster_api_api_v1beta1_UnhealthyMachineCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UnhealthyMachineCondition represents a Machine condition type and value with a timeout specified as a duration. When the named condition has been in the given status for at least the timeout value, a machine is considered unhealthy.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "type of Machine condition. Both the conditions in status.conditions and in status.v1beta2.conditions are considered, the latter take precedence if a condition with the same type exists in both.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Description: "status of the condition, one of True, False, Unknown.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"timeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "timeout is the duration that a machine must be in a given status for, after which the machine is considered unhealthy. For example, with a value of \"1h\", the machine must match the status for at least 1 hour before being considered unhealthy.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+				Required: []string{"type", "status", "timeout"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
 func schema_sigsk8sio_cluster_api_api_v1beta1_ValidationRule(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
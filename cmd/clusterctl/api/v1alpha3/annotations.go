@@ -41,4 +41,9 @@ const (
 	// `clusterctl move` is invoked, then NO resources for ANY workload cluster will be created on the
 	// destination management cluster until the annotation is removed.
 	BlockMoveAnnotation = "clusterctl.cluster.x-k8s.io/block-move"
+
+	// CRDMigrationCheckpointAnnotation is set by clusterctl on CRDs while migrating CRs to the storage version, and it
+	// reports the storage version and the last CR migrated; it allows to resume an interrupted migration.
+	// The annotation is removed when the migration completes.
+	CRDMigrationCheckpointAnnotation = "clusterctl.cluster.x-k8s.io/crd-migration-checkpoint"
 )
@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+
+// ManagementCluster defines the desired state of the providers in a management cluster.
+// It is read from a file by clusterctl apply, and it is not stored in the management cluster.
+type ManagementCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// spec defines the desired state of the management cluster.
+	// +optional
+	Spec ManagementClusterSpec `json:"spec,omitempty"`
+}
+
+// ManagementClusterSpec defines the desired state of the management cluster.
+type ManagementClusterSpec struct {
+	// certManager defines the cert-manager to be installed in the management cluster.
+	// If unspecified, the cert-manager version from the clusterctl configuration is used.
+	// +optional
+	CertManager *ManagementClusterCertManager `json:"certManager,omitempty"`
+
+	// core defines the core provider. If unspecified, the Cluster API core provider is used.
+	// +optional
+	CoreProvider *ManagementClusterProvider `json:"core,omitempty"`
+
+	// bootstrap defines the bootstrap providers.
+	// +optional
+	BootstrapProviders []ManagementClusterProvider `json:"bootstrap,omitempty"`
+
+	// controlPlane defines the control plane providers.
+	// +optional
+	ControlPlaneProviders []ManagementClusterProvider `json:"controlPlane,omitempty"`
+
+	// infrastructure defines the infrastructure providers.
+	// +optional
+	InfrastructureProviders []ManagementClusterProvider `json:"infrastructure,omitempty"`
+
+	// ipam defines the IPAM providers.
+	// +optional
+	IPAMProviders []ManagementClusterProvider `json:"ipam,omitempty"`
+
+	// runtimeExtension defines the runtime extension providers.
+	// +optional
+	RuntimeExtensionProviders []ManagementClusterProvider `json:"runtimeExtension,omitempty"`
+
+	// addon defines the add-on providers.
+	// +optional
+	AddonProviders []ManagementClusterProvider `json:"addon,omitempty"`
+
+	// variables defines values for the variables in the provider components; they take precedence
+	// over the values from environment variables and the clusterctl configuration file.
+	// +optional
+	Variables map[string]string `json:"variables,omitempty"`
+
+	// images defines image overrides for the provider components, using the same format of the
+	// images entry in the clusterctl configuration file; they take precedence over the ones in the
+	// clusterctl configuration file.
+	// +optional
+	Images map[string]ManagementClusterImage `json:"images,omitempty"`
+}
+
+// ManagementClusterProvider defines a provider in the management cluster.
+type ManagementClusterProvider struct {
+	// name of the provider, e.g. aws. The provider must be defined in the clusterctl configuration.
+	Name string `json:"name"`
+
+	// version of the provider, e.g. v2.5.0. If unspecified, the latest release is installed,
+	// and an installed provider is never upgraded.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// targetNamespace is the namespace where the provider is installed.
+	// If unspecified, the provider components' default namespace is used.
+	// +optional
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+}
+
+// ManagementClusterCertManager defines the cert-manager in the management cluster.
+type ManagementClusterCertManager struct {
+	// version of cert-manager, e.g. v1.16.0.
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+// ManagementClusterImage defines an image override for the provider components.
+type ManagementClusterImage struct {
+	// repository sets the container registry to pull images from.
+	// +optional
+	Repository string `json:"repository,omitempty"`
+
+	// tag allows to specify a tag for the images.
+	// +optional
+	Tag string `json:"tag,omitempty"`
+}
+
+func init() {
+	objectTypes = append(objectTypes, &ManagementCluster{})
+}
@@ -24,6 +24,146 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementCluster) DeepCopyInto(out *ManagementCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementCluster.
+func (in *ManagementCluster) DeepCopy() *ManagementCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ManagementCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementClusterCertManager) DeepCopyInto(out *ManagementClusterCertManager) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementClusterCertManager.
+func (in *ManagementClusterCertManager) DeepCopy() *ManagementClusterCertManager {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementClusterCertManager)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementClusterImage) DeepCopyInto(out *ManagementClusterImage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementClusterImage.
+func (in *ManagementClusterImage) DeepCopy() *ManagementClusterImage {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementClusterImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementClusterProvider) DeepCopyInto(out *ManagementClusterProvider) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementClusterProvider.
+func (in *ManagementClusterProvider) DeepCopy() *ManagementClusterProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementClusterProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementClusterSpec) DeepCopyInto(out *ManagementClusterSpec) {
+	*out = *in
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(ManagementClusterCertManager)
+		**out = **in
+	}
+	if in.CoreProvider != nil {
+		in, out := &in.CoreProvider, &out.CoreProvider
+		*out = new(ManagementClusterProvider)
+		**out = **in
+	}
+	if in.BootstrapProviders != nil {
+		in, out := &in.BootstrapProviders, &out.BootstrapProviders
+		*out = make([]ManagementClusterProvider, len(*in))
+		copy(*out, *in)
+	}
+	if in.ControlPlaneProviders != nil {
+		in, out := &in.ControlPlaneProviders, &out.ControlPlaneProviders
+		*out = make([]ManagementClusterProvider, len(*in))
+		copy(*out, *in)
+	}
+	if in.InfrastructureProviders != nil {
+		in, out := &in.InfrastructureProviders, &out.InfrastructureProviders
+		*out = make([]ManagementClusterProvider, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPAMProviders != nil {
+		in, out := &in.IPAMProviders, &out.IPAMProviders
+		*out = make([]ManagementClusterProvider, len(*in))
+		copy(*out, *in)
+	}
+	if in.RuntimeExtensionProviders != nil {
+		in, out := &in.RuntimeExtensionProviders, &out.RuntimeExtensionProviders
+		*out = make([]ManagementClusterProvider, len(*in))
+		copy(*out, *in)
+	}
+	if in.AddonProviders != nil {
+		in, out := &in.AddonProviders, &out.AddonProviders
+		*out = make([]ManagementClusterProvider, len(*in))
+		copy(*out, *in)
+	}
+	if in.Variables != nil {
+		in, out := &in.Variables, &out.Variables
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make(map[string]ManagementClusterImage, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementClusterSpec.
+func (in *ManagementClusterSpec) DeepCopy() *ManagementClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Metadata) DeepCopyInto(out *Metadata) {
 	*out = *in
@@ -17,6 +17,7 @@ limitations under the License.
 package client
 
 import (
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/alpha"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
@@ -41,10 +42,25 @@ type Template repository.Template
 // UpgradePlan defines a list of possible upgrade targets for a management cluster.
 type UpgradePlan cluster.UpgradePlan
 
+// ProviderDiff describes what would change in the management cluster when upgrading a provider to the next version.
+type ProviderDiff = cluster.ProviderDiff
+
+// DoctorReport is the result of diagnosing a management cluster.
+type DoctorReport = cluster.DoctorReport
+
+// RolloutStatus describes the progress of the rollout of a cluster-api resource.
+type RolloutStatus = alpha.RolloutStatus
+
+// RolloutHistory describes the revisions of a cluster-api resource.
+type RolloutHistory = alpha.RolloutHistory
+
 // CertManagerUpgradePlan defines the upgrade plan if cert-manager needs to be
 // upgraded to a different version.
 type CertManagerUpgradePlan cluster.CertManagerUpgradePlan
 
+// MovePlan describes the actions a move operation would perform.
+type MovePlan cluster.MovePlan
+
 // Kubeconfig is a type that specifies inputs related to the actual kubeconfig.
 type Kubeconfig cluster.Kubeconfig
 
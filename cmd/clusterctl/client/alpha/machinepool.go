@@ -0,0 +1,45 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+)
+
+// getMachinePool retrieves the MachinePool object corresponding to the name and namespace specified.
+func getMachinePool(ctx context.Context, proxy cluster.Proxy, name, namespace string) (*expv1.MachinePool, error) {
+	mpObj := &expv1.MachinePool{}
+	c, err := proxy.NewClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mpObjKey := client.ObjectKey{
+		Namespace: namespace,
+		Name:      name,
+	}
+	if err := c.Get(ctx, mpObjKey, mpObj); err != nil {
+		return nil, errors.Wrapf(err, "failed to get MachinePool %s/%s",
+			mpObjKey.Namespace, mpObjKey.Name)
+	}
+	return mpObj, nil
+}
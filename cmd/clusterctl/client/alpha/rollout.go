@@ -18,6 +18,7 @@ package alpha
 
 import (
 	"context"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -29,6 +30,8 @@ const (
 	MachineDeployment = "machinedeployment"
 	// KubeadmControlPlane is a resource type.
 	KubeadmControlPlane = "kubeadmcontrolplane"
+	// MachinePool is a resource type.
+	MachinePool = "machinepool"
 )
 
 var validResourceTypes = []string{
@@ -38,6 +41,8 @@ var validResourceTypes = []string{
 
 var validRollbackResourceTypes = []string{
 	MachineDeployment,
+	KubeadmControlPlane,
+	MachinePool,
 }
 
 // Rollout defines the behavior of a rollout implementation.
@@ -46,6 +51,8 @@ type Rollout interface {
 	ObjectPauser(context.Context, cluster.Proxy, corev1.ObjectReference) error
 	ObjectResumer(context.Context, cluster.Proxy, corev1.ObjectReference) error
 	ObjectRollbacker(context.Context, cluster.Proxy, corev1.ObjectReference, int64) error
+	ObjectStatusViewer(context.Context, cluster.Proxy, corev1.ObjectReference, time.Duration) (*RolloutStatus, error)
+	ObjectHistoryViewer(context.Context, cluster.Proxy, corev1.ObjectReference) (*RolloutHistory, error)
 }
 
 var _ Rollout = &rollout{}
@@ -0,0 +1,183 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+)
+
+var validHistoryResourceTypes = []string{
+	MachineDeployment,
+}
+
+// RolloutHistory describes the revisions of a cluster-api resource.
+type RolloutHistory struct {
+	// Object is the resource the revisions belong to.
+	Object corev1.ObjectReference
+
+	// Revisions lists the revisions of the resource, sorted from the oldest to the newest.
+	Revisions []RolloutRevision
+}
+
+// RolloutRevision describes a revision of a cluster-api resource.
+type RolloutRevision struct {
+	Revision          int64
+	MachineSet        string
+	CreationTimestamp metav1.Time
+	Replicas          int32
+	ReadyReplicas     int32
+
+	// Changes lists the changes to the Machine template compared to the previous revision, e.g. "spec.version: v1.29.0 → v1.30.0".
+	Changes []string
+}
+
+// ObjectHistoryViewer returns the revisions of the specified cluster-api resource.
+func (r *rollout) ObjectHistoryViewer(ctx context.Context, proxy cluster.Proxy, ref corev1.ObjectReference) (*RolloutHistory, error) {
+	switch ref.Kind {
+	case MachineDeployment:
+		deployment, err := getMachineDeployment(ctx, proxy, ref.Name, ref.Namespace)
+		if err != nil || deployment == nil {
+			return nil, errors.Wrapf(err, "failed to fetch %v/%v", ref.Kind, ref.Name)
+		}
+		msList, err := getMachineSetsForDeployment(ctx, proxy, deployment)
+		if err != nil {
+			return nil, err
+		}
+		revisions, err := machineSetRevisions(msList)
+		if err != nil {
+			return nil, err
+		}
+		return &RolloutHistory{Object: ref, Revisions: revisions}, nil
+	default:
+		return nil, errors.Errorf("invalid resource type %q, valid values are %v", ref.Kind, validHistoryResourceTypes)
+	}
+}
+
+// machineSetRevisions returns a revision for each MachineSet, sorted by revision, with the changes to the
+// Machine template compared to the previous revision.
+func machineSetRevisions(msList []*clusterv1.MachineSet) ([]RolloutRevision, error) {
+	sorted := make([]*clusterv1.MachineSet, len(msList))
+	copy(sorted, msList)
+	revisionNumbers := map[*clusterv1.MachineSet]int64{}
+	for _, ms := range sorted {
+		v, err := revision(ms)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the revision of MachineSet %s", ms.Name)
+		}
+		revisionNumbers[ms] = v
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return revisionNumbers[sorted[i]] < revisionNumbers[sorted[j]]
+	})
+
+	revisions := make([]RolloutRevision, 0, len(sorted))
+	var previousFields map[string]string
+	for i, ms := range sorted {
+		fields, err := machineTemplateFields(ms.Spec.Template)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read the Machine template of MachineSet %s", ms.Name)
+		}
+
+		rev := RolloutRevision{
+			Revision:          revisionNumbers[ms],
+			MachineSet:        ms.Name,
+			CreationTimestamp: ms.CreationTimestamp,
+			Replicas:          ms.Status.Replicas,
+			ReadyReplicas:     ms.Status.ReadyReplicas,
+		}
+		if i > 0 {
+			rev.Changes = fieldChanges(previousFields, fields)
+		}
+		revisions = append(revisions, rev)
+		previousFields = fields
+	}
+	return revisions, nil
+}
+
+// machineTemplateFields returns all the values set in a Machine template, indexed by path (e.g. spec.version).
+// NOTE: The MachineDeployment unique label is ignored, because it is different for every revision.
+func machineTemplateFields(template clusterv1.MachineTemplateSpec) (map[string]string, error) {
+	template = *template.DeepCopy()
+	delete(template.Labels, clusterv1.MachineDeploymentUniqueLabel)
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&template)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]string{}
+	var walk func(path string, value interface{})
+	walk = func(path string, value interface{}) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			for key, item := range v {
+				if path == "" {
+					walk(key, item)
+					continue
+				}
+				walk(path+"."+key, item)
+			}
+		case []interface{}:
+			for i, item := range v {
+				walk(fmt.Sprintf("%s[%d]", path, i), item)
+			}
+		case nil:
+			// Null values are considered as not set.
+		default:
+			fields[path] = fmt.Sprintf("%v", v)
+		}
+	}
+	walk("", content)
+	return fields, nil
+}
+
+// fieldChanges returns the fields added, removed or changed between two sets of fields, sorted by path.
+func fieldChanges(previous, current map[string]string) []string {
+	paths := sets.New[string]()
+	for path := range previous {
+		paths.Insert(path)
+	}
+	for path := range current {
+		paths.Insert(path)
+	}
+
+	changes := []string{}
+	for _, path := range sets.List(paths) {
+		previousValue, inPrevious := previous[path]
+		currentValue, inCurrent := current[path]
+		switch {
+		case !inPrevious:
+			changes = append(changes, fmt.Sprintf("%s: <none> → %s", path, currentValue))
+		case !inCurrent:
+			changes = append(changes, fmt.Sprintf("%s: %s → <none>", path, previousValue))
+		case previousValue != currentValue:
+			changes = append(changes, fmt.Sprintf("%s: %s → %s", path, previousValue, currentValue))
+		}
+	}
+	return changes
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_ObjectHistoryViewer(t *testing.T) {
+	deployment := &clusterv1.MachineDeployment{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "MachineDeployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "md-1",
+			Namespace: "default",
+		},
+		Spec: clusterv1.MachineDeploymentSpec{
+			ClusterName: "test",
+			Selector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					clusterv1.ClusterNameLabel: "test",
+				},
+			},
+		},
+	}
+	machineSet := func(name, revision, version, infraTemplate string) *clusterv1.MachineSet {
+		return &clusterv1.MachineSet{
+			TypeMeta: metav1.TypeMeta{
+				Kind: "MachineSet",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(deployment, clusterv1.GroupVersion.WithKind("MachineDeployment")),
+				},
+				Labels: map[string]string{
+					clusterv1.ClusterNameLabel: "test",
+				},
+				Annotations: map[string]string{
+					clusterv1.RevisionAnnotation: revision,
+				},
+			},
+			Spec: clusterv1.MachineSetSpec{
+				ClusterName: "test",
+				Template: clusterv1.MachineTemplateSpec{
+					ObjectMeta: clusterv1.ObjectMeta{
+						Labels: map[string]string{
+							clusterv1.ClusterNameLabel:             "test",
+							clusterv1.MachineDeploymentUniqueLabel: name,
+						},
+					},
+					Spec: clusterv1.MachineSpec{
+						ClusterName: "test",
+						Version:     &version,
+						InfrastructureRef: corev1.ObjectReference{
+							Kind: "InfrastructureMachineTemplate",
+							Name: infraTemplate,
+						},
+					},
+				},
+			},
+			Status: clusterv1.MachineSetStatus{
+				Replicas:      1,
+				ReadyReplicas: 1,
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		objs          []client.Object
+		ref           corev1.ObjectReference
+		wantErr       bool
+		wantRevisions []RolloutRevision
+	}{
+		{
+			name: "machinedeployment revisions with changes",
+			objs: []client.Object{
+				deployment,
+				machineSet("ms-3", "3", "v1.30.0", "template-2"),
+				machineSet("ms-1", "1", "v1.29.0", "template-1"),
+				machineSet("ms-2", "2", "v1.29.0", "template-2"),
+			},
+			ref: corev1.ObjectReference{
+				Kind:      MachineDeployment,
+				Name:      "md-1",
+				Namespace: "default",
+			},
+			wantRevisions: []RolloutRevision{
+				{Revision: 1, MachineSet: "ms-1", Replicas: 1, ReadyReplicas: 1},
+				{Revision: 2, MachineSet: "ms-2", Replicas: 1, ReadyReplicas: 1, Changes: []string{"spec.infrastructureRef.name: template-1 → template-2"}},
+				{Revision: 3, MachineSet: "ms-3", Replicas: 1, ReadyReplicas: 1, Changes: []string{"spec.version: v1.29.0 → v1.30.0"}},
+			},
+		},
+		{
+			name: "kubeadmcontrolplane is not supported",
+			ref: corev1.ObjectReference{
+				Kind:      KubeadmControlPlane,
+				Name:      "kcp",
+				Namespace: "default",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			r := newRolloutClient()
+			proxy := test.NewFakeProxy().WithObjs(tt.objs...)
+			history, err := r.ObjectHistoryViewer(context.Background(), proxy, tt.ref)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(history.Object).To(Equal(tt.ref))
+
+			// Creation timestamps are set by the fake client, so they are not compared.
+			for i := range history.Revisions {
+				history.Revisions[i].CreationTimestamp = metav1.Time{}
+			}
+			g.Expect(history.Revisions).To(Equal(tt.wantRevisions))
+		})
+	}
+}
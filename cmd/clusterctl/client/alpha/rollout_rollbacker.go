@@ -18,13 +18,20 @@ package alpha
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	controllerrevision "sigs.k8s.io/cluster-api/internal/util/revision"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/labels/format"
 	"sigs.k8s.io/cluster-api/util/patch"
 )
 
@@ -42,6 +49,28 @@ func (r *rollout) ObjectRollbacker(ctx context.Context, proxy cluster.Proxy, ref
 		if err := rollbackMachineDeployment(ctx, proxy, deployment, toRevision); err != nil {
 			return err
 		}
+	case KubeadmControlPlane:
+		kcp, err := getKubeadmControlPlane(ctx, proxy, ref.Name, ref.Namespace)
+		if err != nil || kcp == nil {
+			return errors.Wrapf(err, "failed to get %v/%v", ref.Kind, ref.Name)
+		}
+		if annotations.HasPaused(kcp) {
+			return errors.Errorf("can't rollback a paused KubeadmControlPlane: please run 'clusterctl rollout resume %v/%v' first", ref.Kind, ref.Name)
+		}
+		if err := rollbackKubeadmControlPlane(ctx, proxy, kcp, toRevision); err != nil {
+			return err
+		}
+	case MachinePool:
+		mp, err := getMachinePool(ctx, proxy, ref.Name, ref.Namespace)
+		if err != nil || mp == nil {
+			return errors.Wrapf(err, "failed to get %v/%v", ref.Kind, ref.Name)
+		}
+		if annotations.HasPaused(mp) {
+			return errors.Errorf("can't rollback a paused MachinePool: please remove the 'cluster.x-k8s.io/paused' annotation from %v/%v first", ref.Kind, ref.Name)
+		}
+		if err := rollbackMachinePool(ctx, proxy, mp, toRevision); err != nil {
+			return err
+		}
 	default:
 		return errors.Errorf("invalid resource type %q, valid values are %v", ref.Kind, validRollbackResourceTypes)
 	}
@@ -80,3 +109,104 @@ func rollbackMachineDeployment(ctx context.Context, proxy cluster.Proxy, md *clu
 	md.Spec.Template = revMSTemplate
 	return patchHelper.Patch(ctx, md)
 }
+
+// rollbackKubeadmControlPlane will rollback to a previous revision of a KubeadmControlPlane, restoring the Kubernetes
+// version, the infrastructure template and the kubeadm config spec recorded by the KubeadmControlPlane controller.
+func rollbackKubeadmControlPlane(ctx context.Context, proxy cluster.Proxy, kcp *controlplanev1.KubeadmControlPlane, toRevision int64) error {
+	log := logf.Log
+	c, err := proxy.NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	if toRevision < 0 {
+		return errors.Errorf("revision number cannot be negative: %v", toRevision)
+	}
+	revisions, err := controllerrevision.List(ctx, c, kcp, map[string]string{clusterv1.MachineControlPlaneNameLabel: format.MustFormatValue(kcp.Name)})
+	if err != nil {
+		return err
+	}
+	log.V(7).Info("Found revisions", "count", len(revisions))
+	rev, err := findControllerRevision(toRevision, revisions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to rollback KubeadmControlPlane %s/%s", kcp.Namespace, kcp.Name)
+	}
+	log.V(7).Info("Found revision", "revision", rev.Revision)
+
+	previous := &controlplanev1.KubeadmControlPlane{}
+	if err := json.Unmarshal(rev.Data.Raw, previous); err != nil {
+		return errors.Wrapf(err, "failed to read revision %d of KubeadmControlPlane %s/%s", rev.Revision, kcp.Namespace, kcp.Name)
+	}
+
+	patchHelper, err := patch.NewHelper(kcp, c)
+	if err != nil {
+		return err
+	}
+	kcp.Spec.Version = previous.Spec.Version
+	kcp.Spec.MachineTemplate.InfrastructureRef = previous.Spec.MachineTemplate.InfrastructureRef
+	kcp.Spec.KubeadmConfigSpec = previous.Spec.KubeadmConfigSpec
+	return patchHelper.Patch(ctx, kcp)
+}
+
+// rollbackMachinePool will rollback to a previous revision of a MachinePool, restoring the Machine template
+// recorded by the MachinePool controller.
+// NOTE: Only the references to the bootstrap config and to the infrastructure MachinePool are restored, not the
+// content of the referenced objects.
+func rollbackMachinePool(ctx context.Context, proxy cluster.Proxy, mp *expv1.MachinePool, toRevision int64) error {
+	log := logf.Log
+	c, err := proxy.NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	if toRevision < 0 {
+		return errors.Errorf("revision number cannot be negative: %v", toRevision)
+	}
+	revisions, err := controllerrevision.List(ctx, c, mp, map[string]string{clusterv1.MachinePoolNameLabel: format.MustFormatValue(mp.Name)})
+	if err != nil {
+		return err
+	}
+	log.V(7).Info("Found revisions", "count", len(revisions))
+	rev, err := findControllerRevision(toRevision, revisions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to rollback MachinePool %s/%s", mp.Namespace, mp.Name)
+	}
+	log.V(7).Info("Found revision", "revision", rev.Revision)
+
+	previous := &expv1.MachinePool{}
+	if err := json.Unmarshal(rev.Data.Raw, previous); err != nil {
+		return errors.Wrapf(err, "failed to read revision %d of MachinePool %s/%s", rev.Revision, mp.Namespace, mp.Name)
+	}
+
+	patchHelper, err := patch.NewHelper(mp, c)
+	if err != nil {
+		return err
+	}
+	template := previous.Spec.Template
+	// The bootstrap data secret name is not recorded when using a bootstrap config, and it is set again by the
+	// MachinePool controller from the restored bootstrap config.
+	if template.Spec.Bootstrap.ConfigRef != nil {
+		template.Spec.Bootstrap.DataSecretName = mp.Spec.Template.Spec.Bootstrap.DataSecretName
+	}
+	mp.Spec.Template = template
+	return patchHelper.Patch(ctx, mp)
+}
+
+// findControllerRevision returns the ControllerRevision with the given revision number, or the revision immediately
+// preceding the latest one if toRevision is 0.
+func findControllerRevision(toRevision int64, revisions []*appsv1.ControllerRevision) (*appsv1.ControllerRevision, error) {
+	if toRevision > 0 {
+		for _, rev := range revisions {
+			if rev.Revision == toRevision {
+				return rev, nil
+			}
+		}
+		return nil, errors.Errorf("unable to find specified revision: %v", toRevision)
+	}
+
+	// NOTE: revisions are sorted by revision number, oldest first.
+	if len(revisions) < 2 {
+		return nil, errors.New("no rollout history found")
+	}
+	return revisions[len(revisions)-2], nil
+}
@@ -18,16 +18,25 @@ package alpha
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"testing"
 
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	fakeinfrastructure "sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test/providers/infrastructure"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/labels/format"
 )
 
 func Test_ObjectRollbacker(t *testing.T) {
@@ -259,3 +268,281 @@ func Test_ObjectRollbacker(t *testing.T) {
 		})
 	}
 }
+
+func Test_ObjectRollbacker_KubeadmControlPlane(t *testing.T) {
+	infraTemplateRef := func(name string) corev1.ObjectReference {
+		return corev1.ObjectReference{
+			APIVersion: fakeinfrastructure.GroupVersion.String(),
+			Kind:       "GenericInfrastructureMachineTemplate",
+			Namespace:  "default",
+			Name:       name,
+		}
+	}
+	kcpSpec := func(version, infraTemplate, preKubeadmCommand string) controlplanev1.KubeadmControlPlaneSpec {
+		return controlplanev1.KubeadmControlPlaneSpec{
+			Replicas: ptr.To[int32](3),
+			Version:  version,
+			MachineTemplate: controlplanev1.KubeadmControlPlaneMachineTemplate{
+				InfrastructureRef: infraTemplateRef(infraTemplate),
+			},
+			KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+				ClusterConfiguration: &bootstrapv1.ClusterConfiguration{
+					ClusterName: "test",
+				},
+				PreKubeadmCommands: []string{preKubeadmCommand},
+			},
+		}
+	}
+	kcp := &controlplanev1.KubeadmControlPlane{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "KubeadmControlPlane",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kcp",
+			Namespace: "default",
+			UID:       "kcp-uid",
+		},
+		Spec: kcpSpec("v1.31.0", "template-3", "echo 3"),
+	}
+	// kcpRevision returns a ControllerRevision recording the given KCP spec, as the KubeadmControlPlane controller does.
+	kcpRevision := func(rev int64, spec controlplanev1.KubeadmControlPlaneSpec) client.Object {
+		data, err := json.Marshal(map[string]interface{}{
+			"spec": map[string]interface{}{
+				"version":           spec.Version,
+				"machineTemplate":   map[string]interface{}{"infrastructureRef": spec.MachineTemplate.InfrastructureRef},
+				"kubeadmConfigSpec": spec.KubeadmConfigSpec,
+			},
+		})
+		if err != nil {
+			panic(err)
+		}
+		return &appsv1.ControllerRevision{
+			TypeMeta: metav1.TypeMeta{
+				Kind: "ControllerRevision",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("kcp-%d", rev),
+				Namespace: "default",
+				Labels: map[string]string{
+					clusterv1.ClusterNameLabel:             "test",
+					clusterv1.MachineControlPlaneNameLabel: format.MustFormatValue("kcp"),
+				},
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(kcp, controlplanev1.GroupVersion.WithKind("KubeadmControlPlane"))},
+			},
+			Data:     runtime.RawExtension{Raw: data},
+			Revision: rev,
+		}
+	}
+
+	tests := []struct {
+		name                   string
+		objs                   []client.Object
+		toRevision             int64
+		wantErr                bool
+		wantVersion            string
+		wantInfraTemplate      string
+		wantPreKubeadmCommands []string
+	}{
+		{
+			name: "kubeadmcontrolplane should rollback to the previous revision",
+			objs: []client.Object{
+				kcp,
+				kcpRevision(1, kcpSpec("v1.29.0", "template-1", "echo 1")),
+				kcpRevision(2, kcpSpec("v1.30.0", "template-2", "echo 2")),
+				kcpRevision(3, kcp.Spec),
+			},
+			wantVersion:            "v1.30.0",
+			wantInfraTemplate:      "template-2",
+			wantPreKubeadmCommands: []string{"echo 2"},
+		},
+		{
+			name: "kubeadmcontrolplane should rollback to revision=1",
+			objs: []client.Object{
+				kcp,
+				kcpRevision(1, kcpSpec("v1.29.0", "template-1", "echo 1")),
+				kcpRevision(2, kcpSpec("v1.30.0", "template-2", "echo 2")),
+				kcpRevision(3, kcp.Spec),
+			},
+			toRevision:             1,
+			wantVersion:            "v1.29.0",
+			wantInfraTemplate:      "template-1",
+			wantPreKubeadmCommands: []string{"echo 1"},
+		},
+		{
+			name: "kubeadmcontrolplane should not rollback because there is no previous revision",
+			objs: []client.Object{
+				kcp,
+				kcpRevision(1, kcp.Spec),
+			},
+			wantErr: true,
+		},
+		{
+			name: "kubeadmcontrolplane should not rollback because the specified revision does not exist",
+			objs: []client.Object{
+				kcp,
+				kcpRevision(1, kcpSpec("v1.30.0", "template-2", "echo 2")),
+				kcpRevision(2, kcp.Spec),
+			},
+			toRevision: 3,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			r := newRolloutClient()
+			proxy := test.NewFakeProxy().WithObjs(tt.objs...)
+			ref := corev1.ObjectReference{
+				Kind:      KubeadmControlPlane,
+				Name:      "kcp",
+				Namespace: "default",
+			}
+			err := r.ObjectRollbacker(context.Background(), proxy, ref, tt.toRevision)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			cl, err := proxy.NewClient(context.Background())
+			g.Expect(err).ToNot(HaveOccurred())
+			got := &controlplanev1.KubeadmControlPlane{}
+			g.Expect(cl.Get(context.Background(), client.ObjectKeyFromObject(kcp), got)).To(Succeed())
+			g.Expect(got.Spec.Version).To(Equal(tt.wantVersion))
+			g.Expect(got.Spec.MachineTemplate.InfrastructureRef).To(Equal(infraTemplateRef(tt.wantInfraTemplate)))
+			g.Expect(got.Spec.KubeadmConfigSpec.PreKubeadmCommands).To(Equal(tt.wantPreKubeadmCommands))
+			// Fields not recorded in the revision are preserved.
+			g.Expect(got.Spec.Replicas).To(Equal(ptr.To[int32](3)))
+		})
+	}
+}
+
+func Test_ObjectRollbacker_MachinePool(t *testing.T) {
+	template := func(version, bootstrapConfig string) clusterv1.MachineTemplateSpec {
+		return clusterv1.MachineTemplateSpec{
+			Spec: clusterv1.MachineSpec{
+				ClusterName: "test",
+				Version:     ptr.To(version),
+				Bootstrap: clusterv1.Bootstrap{
+					ConfigRef: &corev1.ObjectReference{
+						APIVersion: bootstrapv1.GroupVersion.String(),
+						Kind:       "KubeadmConfig",
+						Name:       bootstrapConfig,
+					},
+				},
+				InfrastructureRef: corev1.ObjectReference{
+					APIVersion: fakeinfrastructure.GroupVersion.String(),
+					Kind:       "GenericInfrastructureMachinePool",
+					Name:       "mp",
+				},
+			},
+		}
+	}
+	mp := &expv1.MachinePool{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "MachinePool",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mp",
+			Namespace: "default",
+			UID:       "mp-uid",
+		},
+		Spec: expv1.MachinePoolSpec{
+			ClusterName: "test",
+			Replicas:    ptr.To[int32](3),
+			Template:    template("v1.31.0", "config-2"),
+		},
+	}
+	mp.Spec.Template.Spec.Bootstrap.DataSecretName = ptr.To("config-2-data")
+	// mpRevision returns a ControllerRevision recording the given Machine template, as the MachinePool controller does.
+	mpRevision := func(rev int64, template clusterv1.MachineTemplateSpec) client.Object {
+		data, err := json.Marshal(map[string]interface{}{
+			"spec": map[string]interface{}{
+				"template": template,
+			},
+		})
+		if err != nil {
+			panic(err)
+		}
+		return &appsv1.ControllerRevision{
+			TypeMeta: metav1.TypeMeta{
+				Kind: "ControllerRevision",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("mp-%d", rev),
+				Namespace: "default",
+				Labels: map[string]string{
+					clusterv1.ClusterNameLabel:     "test",
+					clusterv1.MachinePoolNameLabel: format.MustFormatValue("mp"),
+				},
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(mp, expv1.GroupVersion.WithKind("MachinePool"))},
+			},
+			Data:     runtime.RawExtension{Raw: data},
+			Revision: rev,
+		}
+	}
+
+	tests := []struct {
+		name                string
+		objs                []client.Object
+		toRevision          int64
+		wantErr             bool
+		wantVersion         string
+		wantBootstrapConfig string
+	}{
+		{
+			name: "machinepool should rollback to the previous revision",
+			objs: []client.Object{
+				mp,
+				mpRevision(1, template("v1.30.0", "config-1")),
+				mpRevision(2, template("v1.31.0", "config-2")),
+			},
+			wantVersion:         "v1.30.0",
+			wantBootstrapConfig: "config-1",
+		},
+		{
+			name: "machinepool should not rollback because there is no previous revision",
+			objs: []client.Object{
+				mp,
+				mpRevision(1, template("v1.31.0", "config-2")),
+			},
+			wantErr: true,
+		},
+		{
+			name: "machinepool should not rollback because the specified revision does not exist",
+			objs: []client.Object{
+				mp,
+				mpRevision(1, template("v1.30.0", "config-1")),
+				mpRevision(2, template("v1.31.0", "config-2")),
+			},
+			toRevision: 3,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			r := newRolloutClient()
+			proxy := test.NewFakeProxy().WithObjs(tt.objs...)
+			ref := corev1.ObjectReference{
+				Kind:      MachinePool,
+				Name:      "mp",
+				Namespace: "default",
+			}
+			err := r.ObjectRollbacker(context.Background(), proxy, ref, tt.toRevision)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			cl, err := proxy.NewClient(context.Background())
+			g.Expect(err).ToNot(HaveOccurred())
+			got := &expv1.MachinePool{}
+			g.Expect(cl.Get(context.Background(), client.ObjectKeyFromObject(mp), got)).To(Succeed())
+			g.Expect(got.Spec.Template.Spec.Version).To(Equal(ptr.To(tt.wantVersion)))
+			g.Expect(got.Spec.Template.Spec.Bootstrap.ConfigRef.Name).To(Equal(tt.wantBootstrapConfig))
+			// The bootstrap data secret name is preserved, and it is updated by the MachinePool controller.
+			g.Expect(got.Spec.Template.Spec.Bootstrap.DataSecretName).To(Equal(ptr.To("config-2-data")))
+			g.Expect(got.Spec.Replicas).To(Equal(ptr.To[int32](3)))
+		})
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/labels/format"
+)
+
+// RolloutStatus describes the progress of the rollout of a cluster-api resource.
+type RolloutStatus struct {
+	// Object is the resource being rolled out.
+	Object corev1.ObjectReference
+
+	// DesiredReplicas is the number of replicas defined in the resource spec.
+	DesiredReplicas int32
+
+	// Replicas is the number of Machines, including the Machines with an old spec.
+	Replicas int32
+
+	// UpdatedReplicas is the number of Machines with the desired spec.
+	UpdatedReplicas int32
+
+	// AvailableReplicas is the number of available Machines for MachineDeployments, and
+	// the number of ready Machines for KubeadmControlPlanes.
+	AvailableReplicas int32
+
+	// Paused is true if the resource is paused, and thus the rollout is not progressing.
+	Paused bool
+
+	// Done is true when the rollout is completed.
+	Done bool
+
+	// Failed is true when the rollout can't complete without a manual intervention, e.g. because of failed Machines.
+	Failed bool
+
+	// Message is a human-readable description of the rollout progress.
+	Message string
+
+	// StuckMachines lists the Machines failed, or that did not complete provisioning or deletion in the expected time.
+	StuckMachines []StuckMachine
+}
+
+// StuckMachine describes a Machine that is blocking the progress of a rollout.
+type StuckMachine struct {
+	Name  string
+	Phase string
+
+	// Reason is a human-readable description of why the Machine is stuck.
+	Reason string
+
+	// Duration is the time since the Machine entered the current phase.
+	Duration time.Duration
+
+	// Failed is true if the Machine reported a terminal failure.
+	Failed bool
+}
+
+// ObjectStatusViewer returns the status of the rollout of the specified cluster-api resource.
+// Machines not completing provisioning or deletion within stuckMachineTimeout are reported as stuck.
+func (r *rollout) ObjectStatusViewer(ctx context.Context, proxy cluster.Proxy, ref corev1.ObjectReference, stuckMachineTimeout time.Duration) (*RolloutStatus, error) {
+	status := &RolloutStatus{Object: ref}
+
+	var machineLabels client.MatchingLabels
+	switch ref.Kind {
+	case MachineDeployment:
+		deployment, err := getMachineDeployment(ctx, proxy, ref.Name, ref.Namespace)
+		if err != nil || deployment == nil {
+			return nil, errors.Wrapf(err, "failed to fetch %v/%v", ref.Kind, ref.Name)
+		}
+		status.DesiredReplicas = ptr.Deref(deployment.Spec.Replicas, 1)
+		status.Replicas = deployment.Status.Replicas
+		status.UpdatedReplicas = deployment.Status.UpdatedReplicas
+		status.AvailableReplicas = deployment.Status.AvailableReplicas
+		status.Paused = deployment.Spec.Paused || annotations.HasPaused(deployment)
+		setRolloutProgress(status, deployment.Generation > deployment.Status.ObservedGeneration, "available")
+
+		machineLabels = client.MatchingLabels{
+			clusterv1.ClusterNameLabel:           deployment.Spec.ClusterName,
+			clusterv1.MachineDeploymentNameLabel: deployment.Name,
+		}
+	case KubeadmControlPlane:
+		kcp, err := getKubeadmControlPlane(ctx, proxy, ref.Name, ref.Namespace)
+		if err != nil || kcp == nil {
+			return nil, errors.Wrapf(err, "failed to fetch %v/%v", ref.Kind, ref.Name)
+		}
+		status.DesiredReplicas = ptr.Deref(kcp.Spec.Replicas, 1)
+		status.Replicas = kcp.Status.Replicas
+		status.UpdatedReplicas = kcp.Status.UpdatedReplicas
+		status.AvailableReplicas = kcp.Status.ReadyReplicas
+		status.Paused = annotations.HasPaused(kcp)
+		setRolloutProgress(status, kcp.Generation > kcp.Status.ObservedGeneration, "ready")
+
+		machineLabels = client.MatchingLabels{
+			clusterv1.MachineControlPlaneNameLabel: format.MustFormatValue(kcp.Name),
+		}
+	default:
+		return nil, errors.Errorf("invalid resource type %q, valid values are %v", ref.Kind, validResourceTypes)
+	}
+
+	c, err := proxy.NewClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	machines := &clusterv1.MachineList{}
+	if err := c.List(ctx, machines, client.InNamespace(ref.Namespace), machineLabels); err != nil {
+		return nil, errors.Wrapf(err, "failed to list Machines for %v/%v", ref.Kind, ref.Name)
+	}
+
+	now := time.Now()
+	for i := range machines.Items {
+		if stuck := getStuckMachine(&machines.Items[i], stuckMachineTimeout, now); stuck != nil {
+			status.StuckMachines = append(status.StuckMachines, *stuck)
+			if stuck.Failed && !status.Done {
+				status.Failed = true
+			}
+		}
+	}
+	sort.Slice(status.StuckMachines, func(i, j int) bool {
+		return status.StuckMachines[i].Name < status.StuckMachines[j].Name
+	})
+
+	return status, nil
+}
+
+// setRolloutProgress sets Done and Message according to the replica counters of a rollout.
+// NOTE: This is the same logic used by kubectl rollout status for Deployments.
+func setRolloutProgress(status *RolloutStatus, specNotObserved bool, availableDescription string) {
+	switch {
+	case specNotObserved:
+		status.Message = fmt.Sprintf("Waiting for %s spec update to be observed...", status.Object.Name)
+	case status.UpdatedReplicas < status.DesiredReplicas:
+		status.Message = fmt.Sprintf("Waiting for %s rollout to finish: %d out of %d new replicas have been updated...",
+			status.Object.Name, status.UpdatedReplicas, status.DesiredReplicas)
+	case status.Replicas > status.UpdatedReplicas:
+		status.Message = fmt.Sprintf("Waiting for %s rollout to finish: %d old replicas are pending termination...",
+			status.Object.Name, status.Replicas-status.UpdatedReplicas)
+	case status.AvailableReplicas < status.UpdatedReplicas:
+		status.Message = fmt.Sprintf("Waiting for %s rollout to finish: %d of %d updated replicas are %s...",
+			status.Object.Name, status.AvailableReplicas, status.UpdatedReplicas, availableDescription)
+	default:
+		status.Message = fmt.Sprintf("%s successfully rolled out", status.Object.Name)
+		status.Done = true
+		return
+	}
+
+	if status.Paused {
+		status.Message = fmt.Sprintf("%s is paused: %s", status.Object.Name, status.Message)
+	}
+}
+
+// getStuckMachine returns a StuckMachine if the Machine reported a terminal failure, or if the
+// Machine has not been running or has been deleting for longer than the timeout; otherwise it returns nil.
+func getStuckMachine(machine *clusterv1.Machine, timeout time.Duration, now time.Time) *StuckMachine {
+	phase := machine.Status.GetTypedPhase()
+	since := machine.CreationTimestamp.Time
+	if machine.Status.LastUpdated != nil {
+		since = machine.Status.LastUpdated.Time
+	}
+	if !machine.DeletionTimestamp.IsZero() {
+		since = machine.DeletionTimestamp.Time
+	}
+
+	stuck := &StuckMachine{
+		Name:     machine.Name,
+		Phase:    string(phase),
+		Duration: now.Sub(since),
+	}
+
+	if machine.Status.FailureReason != nil || machine.Status.FailureMessage != nil {
+		stuck.Failed = true
+		stuck.Reason = ptr.Deref(machine.Status.FailureMessage, string(ptr.Deref(machine.Status.FailureReason, "")))
+		return stuck
+	}
+
+	if phase == clusterv1.MachinePhaseRunning && machine.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	if stuck.Duration < timeout {
+		return nil
+	}
+
+	stuck.Reason = "Machine is not ready"
+	if !machine.DeletionTimestamp.IsZero() {
+		stuck.Reason = "Machine deletion is not completed"
+		if drainCondition := conditions.Get(machine, clusterv1.DrainingSucceededCondition); drainCondition != nil && drainCondition.Status != corev1.ConditionTrue {
+			stuck.Reason = conditionDescription(drainCondition)
+		}
+	} else if readyCondition := conditions.Get(machine, clusterv1.ReadyCondition); readyCondition != nil && readyCondition.Status != corev1.ConditionTrue {
+		stuck.Reason = conditionDescription(readyCondition)
+	}
+	return stuck
+}
+
+func conditionDescription(c *clusterv1.Condition) string {
+	if c.Message == "" {
+		return c.Reason
+	}
+	return fmt.Sprintf("%s: %s", c.Reason, c.Message)
+}
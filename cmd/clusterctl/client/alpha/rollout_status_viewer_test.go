@@ -0,0 +1,224 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/labels/format"
+)
+
+func Test_ObjectStatusViewer(t *testing.T) {
+	now := time.Now()
+	deployment := func(replicas, updated, available int32) *clusterv1.MachineDeployment {
+		return &clusterv1.MachineDeployment{
+			TypeMeta: metav1.TypeMeta{
+				Kind: "MachineDeployment",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "md-1",
+				Namespace:  "default",
+				Generation: 2,
+			},
+			Spec: clusterv1.MachineDeploymentSpec{
+				ClusterName: "test",
+				Replicas:    ptr.To[int32](3),
+			},
+			Status: clusterv1.MachineDeploymentStatus{
+				ObservedGeneration: 2,
+				Replicas:           replicas,
+				UpdatedReplicas:    updated,
+				AvailableReplicas:  available,
+			},
+		}
+	}
+	machine := func(name string, phase clusterv1.MachinePhase, lastUpdated time.Time) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			TypeMeta: metav1.TypeMeta{
+				Kind: "Machine",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					clusterv1.ClusterNameLabel:           "test",
+					clusterv1.MachineDeploymentNameLabel: "md-1",
+				},
+			},
+			Status: clusterv1.MachineStatus{
+				Phase:       string(phase),
+				LastUpdated: &metav1.Time{Time: lastUpdated},
+				Conditions: clusterv1.Conditions{
+					{Type: clusterv1.ReadyCondition, Status: corev1.ConditionFalse, Reason: "WaitingForInfrastructure", Message: "0 of 2 completed"},
+				},
+			},
+		}
+	}
+	failedMachine := machine("md-1-failed", clusterv1.MachinePhaseFailed, now)
+	failedMachine.Status.FailureMessage = ptr.To("instance terminated")
+
+	tests := []struct {
+		name              string
+		objs              []client.Object
+		ref               corev1.ObjectReference
+		wantErr           bool
+		wantMessage       string
+		wantDone          bool
+		wantFailed        bool
+		wantStuckMachines []StuckMachine
+	}{
+		{
+			name: "machinedeployment rollout completed",
+			objs: []client.Object{deployment(3, 3, 3)},
+			ref: corev1.ObjectReference{
+				Kind:      MachineDeployment,
+				Name:      "md-1",
+				Namespace: "default",
+			},
+			wantMessage: "md-1 successfully rolled out",
+			wantDone:    true,
+		},
+		{
+			name: "machinedeployment rollout in progress with a stuck Machine",
+			objs: []client.Object{
+				deployment(4, 2, 2),
+				machine("md-1-provisioning", clusterv1.MachinePhaseProvisioning, now.Add(-time.Hour)),
+				machine("md-1-recent", clusterv1.MachinePhaseProvisioning, now),
+				machine("md-1-running", clusterv1.MachinePhaseRunning, now.Add(-time.Hour)),
+			},
+			ref: corev1.ObjectReference{
+				Kind:      MachineDeployment,
+				Name:      "md-1",
+				Namespace: "default",
+			},
+			wantMessage: "Waiting for md-1 rollout to finish: 2 out of 3 new replicas have been updated...",
+			wantStuckMachines: []StuckMachine{
+				{Name: "md-1-provisioning", Phase: "Provisioning", Reason: "WaitingForInfrastructure: 0 of 2 completed"},
+			},
+		},
+		{
+			name: "machinedeployment rollout failed",
+			objs: []client.Object{
+				deployment(3, 3, 2),
+				failedMachine,
+			},
+			ref: corev1.ObjectReference{
+				Kind:      MachineDeployment,
+				Name:      "md-1",
+				Namespace: "default",
+			},
+			wantMessage: "Waiting for md-1 rollout to finish: 2 of 3 updated replicas are available...",
+			wantFailed:  true,
+			wantStuckMachines: []StuckMachine{
+				{Name: "md-1-failed", Phase: "Failed", Reason: "instance terminated", Failed: true},
+			},
+		},
+		{
+			name: "kubeadmcontrolplane rollout with old replicas",
+			objs: []client.Object{
+				&controlplanev1.KubeadmControlPlane{
+					TypeMeta: metav1.TypeMeta{
+						Kind: "KubeadmControlPlane",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "kcp",
+						Namespace: "default",
+					},
+					Spec: controlplanev1.KubeadmControlPlaneSpec{
+						Replicas: ptr.To[int32](3),
+					},
+					Status: controlplanev1.KubeadmControlPlaneStatus{
+						Replicas:        4,
+						UpdatedReplicas: 3,
+						ReadyReplicas:   4,
+					},
+				},
+				&clusterv1.Machine{
+					TypeMeta: metav1.TypeMeta{
+						Kind: "Machine",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "kcp-old",
+						Namespace:         "default",
+						DeletionTimestamp: &metav1.Time{Time: now.Add(-time.Hour)},
+						Finalizers:        []string{clusterv1.MachineFinalizer},
+						Labels: map[string]string{
+							clusterv1.MachineControlPlaneNameLabel: format.MustFormatValue("kcp"),
+						},
+					},
+					Status: clusterv1.MachineStatus{
+						Phase: string(clusterv1.MachinePhaseDeleting),
+						Conditions: clusterv1.Conditions{
+							{Type: clusterv1.DrainingSucceededCondition, Status: corev1.ConditionFalse, Reason: clusterv1.DrainingReason, Message: "Cannot evict pod as it would violate the pod's disruption budget."},
+						},
+					},
+				},
+			},
+			ref: corev1.ObjectReference{
+				Kind:      KubeadmControlPlane,
+				Name:      "kcp",
+				Namespace: "default",
+			},
+			wantMessage: "Waiting for kcp rollout to finish: 1 old replicas are pending termination...",
+			wantStuckMachines: []StuckMachine{
+				{Name: "kcp-old", Phase: "Deleting", Reason: "Draining: Cannot evict pod as it would violate the pod's disruption budget."},
+			},
+		},
+		{
+			name: "invalid resource type",
+			ref: corev1.ObjectReference{
+				Kind:      "Machine",
+				Name:      "m-1",
+				Namespace: "default",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			r := newRolloutClient()
+			proxy := test.NewFakeProxy().WithObjs(tt.objs...)
+			status, err := r.ObjectStatusViewer(context.Background(), proxy, tt.ref, 10*time.Minute)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(status.Message).To(Equal(tt.wantMessage))
+			g.Expect(status.Done).To(Equal(tt.wantDone))
+			g.Expect(status.Failed).To(Equal(tt.wantFailed))
+
+			// Durations depend on the time the test runs, so they are not compared.
+			for i := range status.StuckMachines {
+				status.StuckMachines[i].Duration = 0
+			}
+			g.Expect(status.StuckMachines).To(Equal(tt.wantStuckMachines))
+		})
+	}
+}
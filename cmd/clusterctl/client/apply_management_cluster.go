@@ -0,0 +1,476 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/version"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+)
+
+// ManagementClusterProviderAction defines the action required to reconcile a provider in the management cluster.
+type ManagementClusterProviderAction string
+
+const (
+	// ManagementClusterProviderInstall is used for providers to be installed in the management cluster.
+	ManagementClusterProviderInstall = ManagementClusterProviderAction("Install")
+
+	// ManagementClusterProviderUpgrade is used for providers to be upgraded to the desired version.
+	ManagementClusterProviderUpgrade = ManagementClusterProviderAction("Upgrade")
+
+	// ManagementClusterProviderUpToDate is used for providers already in the desired state.
+	ManagementClusterProviderUpToDate = ManagementClusterProviderAction("UpToDate")
+
+	// ManagementClusterProviderDrift is used for providers that differ from the desired state, but cannot be reconciled
+	// by clusterctl apply, e.g. providers not defined in the ManagementCluster, or to be downgraded; drift is reported only.
+	ManagementClusterProviderDrift = ManagementClusterProviderAction("Drift")
+)
+
+// ApplyManagementClusterOptions carries the options supported by ApplyManagementCluster.
+type ApplyManagementClusterOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// ManagementCluster defines the desired state of the providers in the management cluster.
+	// NOTE: variables, image overrides and cert-manager version defined in the ManagementCluster are read from the
+	// configuration client, so the client should be created with config.WithManagementCluster.
+	ManagementCluster *clusterctlv1.ManagementCluster
+
+	// DryRun instructs ApplyManagementCluster to only compute the plan, without performing any actual action.
+	DryRun bool
+
+	// WaitProviders instructs ApplyManagementCluster to wait till the providers are installed or upgraded.
+	WaitProviders bool
+
+	// WaitProviderTimeout sets the timeout per provider installation or upgrade.
+	WaitProviderTimeout time.Duration
+
+	// IgnoreValidationErrors allows for skipping the validation of provider installs.
+	// NOTE this should only be used for development
+	IgnoreValidationErrors bool
+}
+
+// ManagementClusterPlan defines the actions required to reconcile a management cluster with a ManagementCluster.
+type ManagementClusterPlan struct {
+	// CertManager is the upgrade plan for cert-manager.
+	CertManager CertManagerUpgradePlan
+
+	// Providers are the providers defined in the ManagementCluster or installed in the management cluster,
+	// with the corresponding action.
+	Providers []ManagementClusterProviderPlan
+}
+
+// ManagementClusterProviderPlan defines the action required to reconcile a provider in the management cluster.
+type ManagementClusterProviderPlan struct {
+	// Name of the provider, e.g. aws.
+	Name string
+
+	// Type of the provider.
+	Type clusterctlv1.ProviderType
+
+	// Namespace where the provider is installed or is going to be installed, if known.
+	Namespace string
+
+	// CurrentVersion is the version of the provider installed in the management cluster, if any.
+	CurrentVersion string
+
+	// DesiredVersion is the version of the provider defined in the ManagementCluster, if any.
+	DesiredVersion string
+
+	// Action required to reconcile the provider.
+	Action ManagementClusterProviderAction
+
+	// Message provides details about the drift, if any.
+	Message string
+}
+
+// ManifestLabel returns the cluster.x-k8s.io/provider label value for the provider.
+func (p ManagementClusterProviderPlan) ManifestLabel() string {
+	return clusterctlv1.ManifestLabel(p.Name, p.Type)
+}
+
+// HasChanges returns true if the plan requires changes to the management cluster.
+func (p *ManagementClusterPlan) HasChanges() bool {
+	if p.CertManager.ShouldUpgrade {
+		return true
+	}
+	for _, provider := range p.Providers {
+		if provider.Action == ManagementClusterProviderInstall || provider.Action == ManagementClusterProviderUpgrade {
+			return true
+		}
+	}
+	return false
+}
+
+// HasDrift returns true if the management cluster differs from the ManagementCluster in a way
+// that cannot be reconciled by ApplyManagementCluster.
+func (p *ManagementClusterPlan) HasDrift() bool {
+	for _, provider := range p.Providers {
+		if provider.Action == ManagementClusterProviderDrift {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyManagementCluster reconciles a management cluster with a ManagementCluster: missing providers are installed,
+// providers behind the desired version are upgraded, and any other difference is reported as drift.
+func (c *clusterctlClient) ApplyManagementCluster(ctx context.Context, options ApplyManagementClusterOptions) (*ManagementClusterPlan, error) {
+	log := logf.Log
+
+	if options.ManagementCluster == nil {
+		return nil, errors.New("invalid options: ManagementCluster must be set")
+	}
+
+	// Default WaitProviderTimeout as we cannot rely on defaulting in the CLI
+	// when clusterctl is used as a library.
+	if options.WaitProviderTimeout.Nanoseconds() == 0 {
+		options.WaitProviderTimeout = time.Duration(5*60) * time.Second
+	}
+
+	desired, err := c.getDesiredProviders(options.ManagementCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the client for interacting with the management cluster.
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure this command only runs against empty management clusters or management clusters with the current Cluster API contract.
+	if err := clusterClient.ProviderInventory().CheckCAPIContract(ctx, cluster.AllowCAPINotInstalled{}); err != nil {
+		return nil, err
+	}
+
+	installed, err := c.listInstalledProviders(ctx, clusterClient, options.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := planManagementCluster(desired, installed)
+	if err != nil {
+		return nil, err
+	}
+
+	certManagerPlan, err := clusterClient.CertManager().PlanUpgrade(ctx)
+	if err != nil {
+		return nil, err
+	}
+	plan.CertManager = CertManagerUpgradePlan(certManagerPlan)
+
+	if options.DryRun {
+		return plan, nil
+	}
+
+	// Upgrade the providers behind the desired version; this also upgrades cert-manager, if required.
+	upgradeOptions := ApplyUpgradeOptions{
+		Kubeconfig:          options.Kubeconfig,
+		WaitProviders:       options.WaitProviders,
+		WaitProviderTimeout: options.WaitProviderTimeout,
+	}
+	upgrade := false
+	for _, p := range plan.Providers {
+		if p.Action != ManagementClusterProviderUpgrade {
+			continue
+		}
+		upgrade = true
+		addProviderToUpgradeOptions(&upgradeOptions, p.Type, fmt.Sprintf("%s/%s:%s", p.Namespace, p.Name, p.DesiredVersion))
+	}
+	if upgrade {
+		log.Info("Upgrading providers")
+		if err := c.ApplyUpgrade(ctx, upgradeOptions); err != nil {
+			return nil, err
+		}
+	} else if plan.CertManager.ShouldUpgrade {
+		if err := clusterClient.CertManager().EnsureLatestVersion(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	// Install the missing providers; Init supports a single target namespace, so providers are installed in
+	// groups, one for each target namespace, starting from the one with the core provider, if any.
+	for _, initOptions := range getInitOptionsForManagementCluster(plan, options) {
+		log.Info("Installing providers")
+		if _, err := c.Init(ctx, initOptions); err != nil {
+			return nil, err
+		}
+	}
+
+	return plan, nil
+}
+
+// listInstalledProviders returns the providers installed in the management cluster.
+// NOTE: in dry run the custom resource definitions required by clusterctl are not installed, so a management cluster
+// without the inventory CRD is considered as a management cluster without providers.
+func (c *clusterctlClient) listInstalledProviders(ctx context.Context, clusterClient cluster.Client, dryRun bool) ([]clusterctlv1.Provider, error) {
+	if dryRun {
+		cl, err := clusterClient.Proxy().NewClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := cl.Get(ctx, client.ObjectKey{Name: fmt.Sprintf("providers.%s", clusterctlv1.GroupVersion.Group)}, crd); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, errors.Wrap(err, "failed to check if the clusterctl inventory CRD exists")
+		}
+	} else {
+		// Ensures the custom resource definitions required by clusterctl are in place.
+		if err := clusterClient.ProviderInventory().EnsureCustomResourceDefinitions(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	installed, err := clusterClient.ProviderInventory().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return installed.Items, nil
+}
+
+// desiredProvider is a provider defined in a ManagementCluster.
+type desiredProvider struct {
+	clusterctlv1.ManagementClusterProvider
+	providerType clusterctlv1.ProviderType
+}
+
+// getDesiredProviders returns the providers defined in a ManagementCluster, defaulting the core provider.
+func (c *clusterctlClient) getDesiredProviders(managementCluster *clusterctlv1.ManagementCluster) ([]desiredProvider, error) {
+	spec := managementCluster.Spec
+
+	core := clusterctlv1.ManagementClusterProvider{Name: config.ClusterAPIProviderName}
+	if spec.CoreProvider != nil {
+		core = *spec.CoreProvider
+	}
+
+	desired := []desiredProvider{{ManagementClusterProvider: core, providerType: clusterctlv1.CoreProviderType}}
+	for _, providers := range []struct {
+		providerType clusterctlv1.ProviderType
+		providers    []clusterctlv1.ManagementClusterProvider
+	}{
+		{providerType: clusterctlv1.BootstrapProviderType, providers: spec.BootstrapProviders},
+		{providerType: clusterctlv1.ControlPlaneProviderType, providers: spec.ControlPlaneProviders},
+		{providerType: clusterctlv1.InfrastructureProviderType, providers: spec.InfrastructureProviders},
+		{providerType: clusterctlv1.IPAMProviderType, providers: spec.IPAMProviders},
+		{providerType: clusterctlv1.RuntimeExtensionProviderType, providers: spec.RuntimeExtensionProviders},
+		{providerType: clusterctlv1.AddonProviderType, providers: spec.AddonProviders},
+	} {
+		for _, p := range providers.providers {
+			desired = append(desired, desiredProvider{ManagementClusterProvider: p, providerType: providers.providerType})
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, p := range desired {
+		if p.Name == "" {
+			return nil, errors.Errorf("invalid ManagementCluster: the name of %s providers must be set", p.providerType)
+		}
+		if p.Version != "" {
+			if _, err := version.ParseSemantic(p.Version); err != nil {
+				return nil, errors.Wrapf(err, "invalid ManagementCluster: invalid version %q for provider %q", p.Version, clusterctlv1.ManifestLabel(p.Name, p.providerType))
+			}
+		}
+		label := clusterctlv1.ManifestLabel(p.Name, p.providerType)
+		if seen[label] {
+			return nil, errors.Errorf("invalid ManagementCluster: provider %q is defined more than once", label)
+		}
+		seen[label] = true
+
+		// Ensure the provider is defined in the clusterctl configuration.
+		if _, err := c.configClient.Providers().Get(p.Name, p.providerType); err != nil {
+			return nil, err
+		}
+	}
+	return desired, nil
+}
+
+// planManagementCluster compares the desired providers with the ones installed in the management cluster,
+// and returns the action required for each one of them.
+func planManagementCluster(desired []desiredProvider, installed []clusterctlv1.Provider) (*ManagementClusterPlan, error) {
+	plan := &ManagementClusterPlan{}
+
+	installedByLabel := map[string]clusterctlv1.Provider{}
+	for _, p := range installed {
+		installedByLabel[p.ManifestLabel()] = p
+	}
+
+	for _, d := range desired {
+		p := ManagementClusterProviderPlan{
+			Name:           d.Name,
+			Type:           d.providerType,
+			Namespace:      d.TargetNamespace,
+			DesiredVersion: d.Version,
+		}
+
+		current, ok := installedByLabel[p.ManifestLabel()]
+		if !ok {
+			p.Action = ManagementClusterProviderInstall
+			plan.Providers = append(plan.Providers, p)
+			continue
+		}
+		delete(installedByLabel, p.ManifestLabel())
+
+		p.Namespace = current.Namespace
+		p.CurrentVersion = current.Version
+		p.Action = ManagementClusterProviderUpToDate
+
+		switch {
+		case d.TargetNamespace != "" && d.TargetNamespace != current.Namespace:
+			p.Action = ManagementClusterProviderDrift
+			p.Message = fmt.Sprintf("installed in namespace %q instead of %q; changing the namespace of a provider is not supported", current.Namespace, d.TargetNamespace)
+		case d.Version != "":
+			currentVersion, err := version.ParseSemantic(current.Version)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse version %q of provider %q", current.Version, p.ManifestLabel())
+			}
+			desiredVersion := version.MustParseSemantic(d.Version)
+			switch {
+			case currentVersion.LessThan(desiredVersion):
+				p.Action = ManagementClusterProviderUpgrade
+			case desiredVersion.LessThan(currentVersion):
+				p.Action = ManagementClusterProviderDrift
+				p.Message = "installed version is newer than the desired version; downgrading a provider is not supported"
+			}
+		}
+		plan.Providers = append(plan.Providers, p)
+	}
+
+	// Report providers installed in the management cluster but not defined in the ManagementCluster as drift.
+	for _, current := range installedByLabel {
+		plan.Providers = append(plan.Providers, ManagementClusterProviderPlan{
+			Name:           current.ProviderName,
+			Type:           current.GetProviderType(),
+			Namespace:      current.Namespace,
+			CurrentVersion: current.Version,
+			Action:         ManagementClusterProviderDrift,
+			Message:        "installed but not defined in the management cluster configuration",
+		})
+	}
+
+	// ensure providers are sorted consistently (by Type, Name).
+	sort.SliceStable(plan.Providers, func(i, j int) bool {
+		if plan.Providers[i].Type.Order() != plan.Providers[j].Type.Order() {
+			return plan.Providers[i].Type.Order() < plan.Providers[j].Type.Order()
+		}
+		return plan.Providers[i].Name < plan.Providers[j].Name
+	})
+
+	return plan, nil
+}
+
+// getInitOptionsForManagementCluster returns the InitOptions for installing the providers in the plan,
+// one for each target namespace, starting from the one with the core provider, if any.
+func getInitOptionsForManagementCluster(plan *ManagementClusterPlan, options ApplyManagementClusterOptions) []InitOptions {
+	initOptionsByNamespace := map[string]*InitOptions{}
+	namespaces := []string{}
+	for _, p := range plan.Providers {
+		if p.Action != ManagementClusterProviderInstall {
+			continue
+		}
+
+		initOptions, ok := initOptionsByNamespace[p.Namespace]
+		if !ok {
+			initOptions = &InitOptions{
+				Kubeconfig:             options.Kubeconfig,
+				TargetNamespace:        p.Namespace,
+				LogUsageInstructions:   false,
+				WaitProviders:          options.WaitProviders,
+				WaitProviderTimeout:    options.WaitProviderTimeout,
+				IgnoreValidationErrors: options.IgnoreValidationErrors,
+			}
+			initOptionsByNamespace[p.Namespace] = initOptions
+			namespaces = append(namespaces, p.Namespace)
+		}
+
+		ref := p.Name
+		if p.DesiredVersion != "" {
+			ref = fmt.Sprintf("%s:%s", p.Name, p.DesiredVersion)
+		}
+		addProviderToInitOptions(initOptions, p.Type, ref)
+	}
+
+	// NOTE: providers are sorted by type, so the core provider, if any, is in the first namespace.
+	ret := make([]InitOptions, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		initOptions := initOptionsByNamespace[namespace]
+
+		// Opt-out from the automatic installation of bootstrap and control plane providers, so only
+		// the providers defined in the ManagementCluster are installed.
+		if len(initOptions.BootstrapProviders) == 0 {
+			initOptions.BootstrapProviders = []string{NoopProvider}
+		}
+		if len(initOptions.ControlPlaneProviders) == 0 {
+			initOptions.ControlPlaneProviders = []string{NoopProvider}
+		}
+		ret = append(ret, *initOptions)
+	}
+	return ret
+}
+
+func addProviderToInitOptions(options *InitOptions, providerType clusterctlv1.ProviderType, ref string) {
+	switch providerType {
+	case clusterctlv1.CoreProviderType:
+		options.CoreProvider = ref
+	case clusterctlv1.BootstrapProviderType:
+		options.BootstrapProviders = append(options.BootstrapProviders, ref)
+	case clusterctlv1.ControlPlaneProviderType:
+		options.ControlPlaneProviders = append(options.ControlPlaneProviders, ref)
+	case clusterctlv1.InfrastructureProviderType:
+		options.InfrastructureProviders = append(options.InfrastructureProviders, ref)
+	case clusterctlv1.IPAMProviderType:
+		options.IPAMProviders = append(options.IPAMProviders, ref)
+	case clusterctlv1.RuntimeExtensionProviderType:
+		options.RuntimeExtensionProviders = append(options.RuntimeExtensionProviders, ref)
+	case clusterctlv1.AddonProviderType:
+		options.AddonProviders = append(options.AddonProviders, ref)
+	}
+}
+
+func addProviderToUpgradeOptions(options *ApplyUpgradeOptions, providerType clusterctlv1.ProviderType, ref string) {
+	switch providerType {
+	case clusterctlv1.CoreProviderType:
+		options.CoreProvider = ref
+	case clusterctlv1.BootstrapProviderType:
+		options.BootstrapProviders = append(options.BootstrapProviders, ref)
+	case clusterctlv1.ControlPlaneProviderType:
+		options.ControlPlaneProviders = append(options.ControlPlaneProviders, ref)
+	case clusterctlv1.InfrastructureProviderType:
+		options.InfrastructureProviders = append(options.InfrastructureProviders, ref)
+	case clusterctlv1.IPAMProviderType:
+		options.IPAMProviders = append(options.IPAMProviders, ref)
+	case clusterctlv1.RuntimeExtensionProviderType:
+		options.RuntimeExtensionProviders = append(options.RuntimeExtensionProviders, ref)
+	case clusterctlv1.AddonProviderType:
+		options.AddonProviders = append(options.AddonProviders, ref)
+	}
+}
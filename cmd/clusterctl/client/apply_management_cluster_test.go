@@ -0,0 +1,259 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+)
+
+func Test_planManagementCluster(t *testing.T) {
+	core := func(version, namespace string) desiredProvider {
+		return desiredProvider{
+			ManagementClusterProvider: clusterctlv1.ManagementClusterProvider{Name: "cluster-api", Version: version, TargetNamespace: namespace},
+			providerType:              clusterctlv1.CoreProviderType,
+		}
+	}
+	infra := func(version, namespace string) desiredProvider {
+		return desiredProvider{
+			ManagementClusterProvider: clusterctlv1.ManagementClusterProvider{Name: "infra", Version: version, TargetNamespace: namespace},
+			providerType:              clusterctlv1.InfrastructureProviderType,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		desired   []desiredProvider
+		installed []clusterctlv1.Provider
+		want      []ManagementClusterProviderPlan
+		wantErr   bool
+	}{
+		{
+			name:      "install missing providers",
+			desired:   []desiredProvider{infra("v2.0.0", "infra-system"), core("", "")},
+			installed: nil,
+			want: []ManagementClusterProviderPlan{
+				{Name: "cluster-api", Type: clusterctlv1.CoreProviderType, Action: ManagementClusterProviderInstall},
+				{Name: "infra", Type: clusterctlv1.InfrastructureProviderType, Namespace: "infra-system", DesiredVersion: "v2.0.0", Action: ManagementClusterProviderInstall},
+			},
+		},
+		{
+			name:    "upgrade providers behind the desired version",
+			desired: []desiredProvider{core("v1.0.1", ""), infra("v2.0.0", "")},
+			installed: []clusterctlv1.Provider{
+				fakeProvider("cluster-api", clusterctlv1.CoreProviderType, "v1.0.0", "cluster-api-system"),
+				fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v2.0.0", "infra-system"),
+			},
+			want: []ManagementClusterProviderPlan{
+				{Name: "cluster-api", Type: clusterctlv1.CoreProviderType, Namespace: "cluster-api-system", CurrentVersion: "v1.0.0", DesiredVersion: "v1.0.1", Action: ManagementClusterProviderUpgrade},
+				{Name: "infra", Type: clusterctlv1.InfrastructureProviderType, Namespace: "infra-system", CurrentVersion: "v2.0.0", DesiredVersion: "v2.0.0", Action: ManagementClusterProviderUpToDate},
+			},
+		},
+		{
+			name:    "providers without a desired version are up to date",
+			desired: []desiredProvider{core("", "")},
+			installed: []clusterctlv1.Provider{
+				fakeProvider("cluster-api", clusterctlv1.CoreProviderType, "v1.0.0", "cluster-api-system"),
+			},
+			want: []ManagementClusterProviderPlan{
+				{Name: "cluster-api", Type: clusterctlv1.CoreProviderType, Namespace: "cluster-api-system", CurrentVersion: "v1.0.0", Action: ManagementClusterProviderUpToDate},
+			},
+		},
+		{
+			name:    "report drift",
+			desired: []desiredProvider{core("v0.9.0", ""), infra("v2.0.0", "other-system")},
+			installed: []clusterctlv1.Provider{
+				fakeProvider("cluster-api", clusterctlv1.CoreProviderType, "v1.0.0", "cluster-api-system"),
+				fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v2.0.0", "infra-system"),
+				fakeProvider("kubeadm", clusterctlv1.BootstrapProviderType, "v1.0.0", "kubeadm-system"),
+			},
+			want: []ManagementClusterProviderPlan{
+				{Name: "cluster-api", Type: clusterctlv1.CoreProviderType, Namespace: "cluster-api-system", CurrentVersion: "v1.0.0", DesiredVersion: "v0.9.0", Action: ManagementClusterProviderDrift,
+					Message: "installed version is newer than the desired version; downgrading a provider is not supported"},
+				{Name: "kubeadm", Type: clusterctlv1.BootstrapProviderType, Namespace: "kubeadm-system", CurrentVersion: "v1.0.0", Action: ManagementClusterProviderDrift,
+					Message: "installed but not defined in the management cluster configuration"},
+				{Name: "infra", Type: clusterctlv1.InfrastructureProviderType, Namespace: "infra-system", CurrentVersion: "v2.0.0", DesiredVersion: "v2.0.0", Action: ManagementClusterProviderDrift,
+					Message: "installed in namespace \"infra-system\" instead of \"other-system\"; changing the namespace of a provider is not supported"},
+			},
+		},
+		{
+			name:    "fails for invalid installed versions",
+			desired: []desiredProvider{core("v1.0.0", "")},
+			installed: []clusterctlv1.Provider{
+				fakeProvider("cluster-api", clusterctlv1.CoreProviderType, "foo", "cluster-api-system"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := planManagementCluster(tt.desired, tt.installed)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.Providers).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_clusterctlClient_ApplyManagementCluster(t *testing.T) {
+	kubeconfig := Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"}
+
+	tests := []struct {
+		name              string
+		client            *fakeClient
+		managementCluster *clusterctlv1.ManagementCluster
+		dryRun            bool
+		wantProviders     map[string]string
+		wantErr           bool
+	}{
+		{
+			name:   "install providers in an empty management cluster",
+			client: fakeEmptyCluster(), // repositories for cluster-api v1.0.0, kubeadm bootstrap and control plane v2.0.0, infra v3.0.0
+			managementCluster: &clusterctlv1.ManagementCluster{
+				Spec: clusterctlv1.ManagementClusterSpec{
+					InfrastructureProviders: []clusterctlv1.ManagementClusterProvider{{Name: "infra"}},
+				},
+			},
+			// only the providers in the ManagementCluster are installed.
+			wantProviders: map[string]string{"cluster-api": "v1.0.0", "infrastructure-infra": "v3.0.0"},
+		},
+		{
+			name:   "upgrade providers",
+			client: fakeClientForUpgrade(), // core v1.0.0 (v1.0.1 available), infra v2.0.0 (v2.0.1 available)
+			managementCluster: &clusterctlv1.ManagementCluster{
+				Spec: clusterctlv1.ManagementClusterSpec{
+					CoreProvider:            &clusterctlv1.ManagementClusterProvider{Name: "cluster-api", Version: "v1.0.1"},
+					InfrastructureProviders: []clusterctlv1.ManagementClusterProvider{{Name: "infra", Version: "v2.0.0"}},
+				},
+			},
+			wantProviders: map[string]string{"cluster-api": "v1.0.1", "infrastructure-infra": "v2.0.0"},
+		},
+		{
+			name:   "dry run does not change the management cluster",
+			client: fakeClientForUpgrade(), // core v1.0.0 (v1.0.1 available), infra v2.0.0 (v2.0.1 available)
+			managementCluster: &clusterctlv1.ManagementCluster{
+				Spec: clusterctlv1.ManagementClusterSpec{
+					CoreProvider:            &clusterctlv1.ManagementClusterProvider{Name: "cluster-api", Version: "v1.0.1"},
+					InfrastructureProviders: []clusterctlv1.ManagementClusterProvider{{Name: "infra", Version: "v2.0.1"}},
+				},
+			},
+			dryRun:        true,
+			wantProviders: map[string]string{"cluster-api": "v1.0.0", "infrastructure-infra": "v2.0.0"},
+		},
+		{
+			name:   "fails for providers not defined in the clusterctl configuration",
+			client: fakeClientForUpgrade(),
+			managementCluster: &clusterctlv1.ManagementCluster{
+				Spec: clusterctlv1.ManagementClusterSpec{
+					InfrastructureProviders: []clusterctlv1.ManagementClusterProvider{{Name: "foo"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:   "fails for providers defined more than once",
+			client: fakeClientForUpgrade(),
+			managementCluster: &clusterctlv1.ManagementCluster{
+				Spec: clusterctlv1.ManagementClusterSpec{
+					InfrastructureProviders: []clusterctlv1.ManagementClusterProvider{{Name: "infra"}, {Name: "infra", Version: "v2.0.1"}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ctx := context.Background()
+
+			plan, err := tt.client.ApplyManagementCluster(ctx, ApplyManagementClusterOptions{
+				Kubeconfig:        kubeconfig,
+				ManagementCluster: tt.managementCluster,
+				DryRun:            tt.dryRun,
+			})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(plan.HasDrift()).To(BeFalse())
+
+			proxy := tt.client.clusters[cluster.Kubeconfig(kubeconfig)].Proxy()
+			c, err := proxy.NewClient(ctx)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			gotProviders := &clusterctlv1.ProviderList{}
+			g.Expect(c.List(ctx, gotProviders)).To(Succeed())
+
+			got := map[string]string{}
+			for _, p := range gotProviders.Items {
+				got[p.Name] = p.Version
+			}
+			g.Expect(got).To(Equal(tt.wantProviders))
+		})
+	}
+}
+
+func Test_clusterctlClient_ApplyManagementCluster_dryRunDoesNotCreateObjects(t *testing.T) {
+	g := NewWithT(t)
+
+	ctx := context.Background()
+
+	kubeconfig := Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"}
+	client := fakeEmptyCluster() // repositories for cluster-api v1.0.0, kubeadm bootstrap and control plane v2.0.0, infra v3.0.0
+
+	plan, err := client.ApplyManagementCluster(ctx, ApplyManagementClusterOptions{
+		Kubeconfig: kubeconfig,
+		ManagementCluster: &clusterctlv1.ManagementCluster{
+			Spec: clusterctlv1.ManagementClusterSpec{
+				InfrastructureProviders: []clusterctlv1.ManagementClusterProvider{{Name: "infra"}},
+			},
+		},
+		DryRun: true,
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The missing inventory CRD is considered as an empty inventory, so all the providers are going to be installed.
+	g.Expect(plan.Providers).To(HaveLen(2))
+	for _, p := range plan.Providers {
+		g.Expect(p.Action).To(Equal(ManagementClusterProviderInstall))
+	}
+
+	// Nothing is created in the management cluster, not even the custom resource definitions required by clusterctl.
+	c, err := client.clusters[cluster.Kubeconfig(kubeconfig)].Proxy().NewClient(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	crds := &apiextensionsv1.CustomResourceDefinitionList{}
+	g.Expect(c.List(ctx, crds)).To(Succeed())
+	g.Expect(crds.Items).To(BeEmpty())
+
+	providers := &clusterctlv1.ProviderList{}
+	g.Expect(c.List(ctx, providers)).To(Succeed())
+	g.Expect(providers.Items).To(BeEmpty())
+}
@@ -0,0 +1,528 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
+	yamlprocessor "sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/util"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
+)
+
+const (
+	// bundleManifestFile is the name of the file describing the content of a bundle.
+	bundleManifestFile = "bundle.yaml"
+
+	// bundleImagesFile is the name of the file listing the container images required by the providers in a bundle.
+	bundleImagesFile = "images.txt"
+
+	// bundleConfigFile is the name of the clusterctl configuration file generated when using a bundle.
+	bundleConfigFile = "clusterctl.yaml"
+
+	// bundleCertManagerDir is the name of the directory hosting the cert-manager manifest in a bundle.
+	bundleCertManagerDir = "cert-manager"
+)
+
+// CreateBundleOptions carries the options supported by CreateBundle.
+type CreateBundleOptions struct {
+	// CoreProvider version (e.g. cluster-api:v1.1.5) to add to the bundle. If unspecified, the
+	// cluster-api core provider's latest release is used.
+	CoreProvider string
+
+	// BootstrapProviders and versions (e.g. kubeadm:v1.1.5) to add to the bundle.
+	// If unspecified, the kubeadm bootstrap provider's latest release is used.
+	BootstrapProviders []string
+
+	// InfrastructureProviders and versions (e.g. aws:v0.5.0) to add to the bundle.
+	InfrastructureProviders []string
+
+	// ControlPlaneProviders and versions (e.g. kubeadm:v1.1.5) to add to the bundle.
+	// If unspecified, the kubeadm control plane provider latest release is used.
+	ControlPlaneProviders []string
+
+	// IPAMProviders and versions (e.g. infoblox:v0.0.1) to add to the bundle.
+	IPAMProviders []string
+
+	// RuntimeExtensionProviders and versions (e.g. test:v0.0.1) to add to the bundle.
+	RuntimeExtensionProviders []string
+
+	// AddonProviders and versions (e.g. helm:v0.1.0) to add to the bundle.
+	AddonProviders []string
+
+	// Flavors defines the additional cluster template flavors to add to the bundle for infrastructure providers;
+	// the default cluster template is always added, if it exists.
+	Flavors []string
+
+	// OutputFile defines the path of the bundle tarball to be created.
+	OutputFile string
+}
+
+// UseBundleOptions carries the options supported by UseBundle.
+type UseBundleOptions struct {
+	// BundleFile defines the path of the bundle tarball to be used.
+	BundleFile string
+
+	// Directory defines the directory where the bundle should be extracted.
+	Directory string
+}
+
+// bundleManifest describes the content of a bundle.
+type bundleManifest struct {
+	// Providers is the list of providers in the bundle.
+	Providers []bundleProvider `json:"providers"`
+
+	// CertManager is the cert-manager release in the bundle.
+	CertManager bundleCertManager `json:"certManager"`
+
+	// Images is the list of container images required by the providers and cert-manager in the bundle.
+	Images []string `json:"images"`
+}
+
+// bundleProvider describes a provider release stored in a bundle.
+type bundleProvider struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Version string `json:"version"`
+	// Path is the path of the provider components file, relative to the bundle root.
+	Path string `json:"path"`
+}
+
+// bundleCertManager describes a cert-manager release stored in a bundle.
+type bundleCertManager struct {
+	Version string `json:"version"`
+	// Path is the path of the cert-manager manifest, relative to the bundle root.
+	Path string `json:"path"`
+}
+
+// bundleConfig mirrors the subset of the clusterctl configuration file generated when using a bundle.
+type bundleConfig struct {
+	Providers   []bundleConfigProvider  `json:"providers"`
+	CertManager bundleConfigCertManager `json:"cert-manager"`
+}
+
+type bundleConfigProvider struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	Type string `json:"type"`
+}
+
+type bundleConfigCertManager struct {
+	URL     string `json:"url"`
+	Version string `json:"version"`
+}
+
+// CreateBundle creates a tarball containing components, metadata and cluster templates for the selected providers
+// and the cert-manager manifest, laid out as a local repository, so they can be used in disconnected environments.
+func (c *clusterctlClient) CreateBundle(ctx context.Context, options CreateBundleOptions) error {
+	log := logf.Log
+
+	if options.OutputFile == "" {
+		return errors.New("invalid arguments: please provide an OutputFile")
+	}
+
+	// Default to the same providers added by init on an empty management cluster.
+	if options.CoreProvider == "" {
+		options.CoreProvider = config.ClusterAPIProviderName
+	}
+	if len(options.BootstrapProviders) == 0 {
+		options.BootstrapProviders = []string{config.KubeadmBootstrapProviderName}
+	}
+	if len(options.ControlPlaneProviders) == 0 {
+		options.ControlPlaneProviders = []string{config.KubeadmControlPlaneProviderName}
+	}
+
+	files := map[string][]byte{}
+	manifest := &bundleManifest{}
+	images := sets.Set[string]{}
+
+	providersByType := []struct {
+		providerType clusterctlv1.ProviderType
+		providers    []string
+	}{
+		{clusterctlv1.CoreProviderType, []string{options.CoreProvider}},
+		{clusterctlv1.BootstrapProviderType, options.BootstrapProviders},
+		{clusterctlv1.ControlPlaneProviderType, options.ControlPlaneProviders},
+		{clusterctlv1.InfrastructureProviderType, options.InfrastructureProviders},
+		{clusterctlv1.IPAMProviderType, options.IPAMProviders},
+		{clusterctlv1.RuntimeExtensionProviderType, options.RuntimeExtensionProviders},
+		{clusterctlv1.AddonProviderType, options.AddonProviders},
+	}
+	for _, p := range providersByType {
+		for _, provider := range p.providers {
+			// It is possible to opt-out from bundling bootstrap/control-plane providers using '-' as a provider name (NoopProvider).
+			if provider == NoopProvider {
+				if p.providerType == clusterctlv1.CoreProviderType {
+					return errors.New("the '-' value can not be used for the core provider")
+				}
+				continue
+			}
+
+			log.Info("Fetching", "provider", provider, "type", p.providerType)
+			bp, providerImages, err := c.addProviderToBundle(ctx, files, provider, p.providerType, options.Flavors)
+			if err != nil {
+				return errors.Wrapf(err, "failed to add the %q provider to the bundle", provider)
+			}
+			manifest.Providers = append(manifest.Providers, *bp)
+			images.Insert(providerImages...)
+		}
+	}
+
+	log.Info("Fetching cert-manager")
+	certManager, certManagerImages, err := c.addCertManagerToBundle(ctx, files)
+	if err != nil {
+		return errors.Wrap(err, "failed to add cert-manager to the bundle")
+	}
+	manifest.CertManager = *certManager
+	images.Insert(certManagerImages...)
+
+	manifest.Images = sets.List(images)
+	files[bundleImagesFile] = []byte(strings.Join(manifest.Images, "\n") + "\n")
+
+	manifestBytes, err := yaml.Marshal(manifest)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the bundle manifest")
+	}
+	files[bundleManifestFile] = manifestBytes
+
+	if err := writeBundle(options.OutputFile, files); err != nil {
+		return errors.Wrapf(err, "failed to write bundle %q", options.OutputFile)
+	}
+
+	log.Info("Bundle created", "file", options.OutputFile, "providers", len(manifest.Providers), "images", len(manifest.Images))
+	return nil
+}
+
+// addProviderToBundle adds components, metadata and cluster templates for a provider to the bundle files.
+func (c *clusterctlClient) addProviderToBundle(ctx context.Context, files map[string][]byte, provider string, providerType clusterctlv1.ProviderType, flavors []string) (*bundleProvider, []string, error) {
+	log := logf.Log
+
+	// Parse the abbreviated syntax for name[:version]
+	name, version, err := parseProviderName(provider)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Gets the provider configuration (that includes the location of the provider repository)
+	providerConfig, err := c.configClient.Providers().Get(name, providerType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	repositoryClient, err := c.repositoryClientFactory(ctx, RepositoryClientFactoryInput{Provider: providerConfig})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if version == "" {
+		version = repositoryClient.DefaultVersion()
+	}
+
+	// Files are stored using the local repository layout, {provider-label}/{version}/{file}.
+	providerDir := path.Join(providerConfig.ManifestLabel(), version)
+
+	componentsOptions := repository.ComponentsOptions{
+		Version:             version,
+		SkipTemplateProcess: true,
+	}
+	rawComponents, err := repositoryClient.Components().Raw(ctx, componentsOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+	componentsPath := path.Join(providerDir, path.Base(providerConfig.URL()))
+	files[componentsPath] = rawComponents
+
+	components, err := repositoryClient.Components().Get(ctx, componentsOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	metadata, err := repositoryClient.Metadata(version).Get(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	metadata.APIVersion = clusterctlv1.GroupVersion.String()
+	metadata.Kind = "Metadata"
+	metadataBytes, err := yaml.Marshal(metadata)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to marshal metadata")
+	}
+	files[path.Join(providerDir, "metadata.yaml")] = metadataBytes
+
+	// Cluster templates are expected to exist for the infrastructure providers only.
+	if providerType == clusterctlv1.InfrastructureProviderType {
+		rawTemplateClient, ok := repositoryClient.Templates(version).(repository.RawTemplateClient)
+		if !ok {
+			return nil, nil, errors.Errorf("the template client for provider %q does not support reading raw cluster templates", provider)
+		}
+		processor := yamlprocessor.NewSimpleProcessor()
+		for _, flavor := range append([]string{""}, flavors...) {
+			template, err := rawTemplateClient.Raw(ctx, flavor)
+			if err != nil {
+				// Templates are optional, e.g. a flavor might exist only for some of the infrastructure providers.
+				log.V(1).Info("Skipping cluster template", "provider", provider, "flavor", flavor, "error", err.Error())
+				continue
+			}
+			files[path.Join(providerDir, processor.GetTemplateName(version, flavor))] = template
+		}
+	}
+
+	return &bundleProvider{
+		Name:    providerConfig.Name(),
+		Type:    string(providerType),
+		Version: version,
+		Path:    componentsPath,
+	}, components.Images(), nil
+}
+
+// addCertManagerToBundle adds the cert-manager manifest to the bundle files.
+func (c *clusterctlClient) addCertManagerToBundle(ctx context.Context, files map[string][]byte) (*bundleCertManager, []string, error) {
+	certManagerConfig, err := c.configClient.CertManager().Get()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Given that cert manager components yaml are stored in a repository like providers components yaml,
+	// we are using the same machinery to retrieve the file by using a fake provider object using
+	// the cert manager repository url.
+	certManagerFakeProvider := config.NewProvider(bundleCertManagerDir, certManagerConfig.URL(), "")
+	certManagerRepository, err := c.repositoryClientFactory(ctx, RepositoryClientFactoryInput{Provider: certManagerFakeProvider})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := certManagerRepository.Components().Raw(ctx, repository.ComponentsOptions{
+		Version: certManagerConfig.Version(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certManagerPath := path.Join(bundleCertManagerDir, certManagerConfig.Version(), path.Base(certManagerConfig.URL()))
+	files[certManagerPath] = file
+
+	objs, err := utilyaml.ToUnstructured(file)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to parse yaml for cert-manager manifest")
+	}
+
+	// Apply image overrides, so the image list matches the images used at install time.
+	objs, err = util.FixImages(objs, func(image string) (string, error) {
+		return c.configClient.ImageMeta().AlterImage(config.CertManagerImageComponent, image)
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to apply image override to the cert-manager manifest")
+	}
+
+	images, err := util.InspectImages(objs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &bundleCertManager{
+		Version: certManagerConfig.Version(),
+		Path:    certManagerPath,
+	}, images, nil
+}
+
+// UseBundle extracts a bundle created with CreateBundle and generates a clusterctl configuration file pointing
+// to the local repository in the bundle; it returns the path of the generated configuration file.
+func (c *clusterctlClient) UseBundle(_ context.Context, options UseBundleOptions) (string, error) {
+	log := logf.Log
+
+	if options.BundleFile == "" {
+		return "", errors.New("invalid arguments: please provide a BundleFile")
+	}
+	if options.Directory == "" {
+		return "", errors.New("invalid arguments: please provide a Directory")
+	}
+
+	directory, err := filepath.Abs(options.Directory)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get absolute path for %q", options.Directory)
+	}
+
+	if err := extractBundle(options.BundleFile, directory); err != nil {
+		return "", errors.Wrapf(err, "failed to extract bundle %q", options.BundleFile)
+	}
+
+	manifestBytes, err := os.ReadFile(filepath.Join(directory, bundleManifestFile)) //nolint:gosec
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read the bundle manifest")
+	}
+	manifest := &bundleManifest{}
+	if err := yaml.Unmarshal(manifestBytes, manifest); err != nil {
+		return "", errors.Wrapf(err, "failed to parse the bundle manifest")
+	}
+
+	cfg := &bundleConfig{
+		CertManager: bundleConfigCertManager{
+			URL:     bundleFileURL(directory, manifest.CertManager.Path),
+			Version: manifest.CertManager.Version,
+		},
+	}
+	for _, p := range manifest.Providers {
+		cfg.Providers = append(cfg.Providers, bundleConfigProvider{
+			Name: p.Name,
+			URL:  bundleFileURL(directory, p.Path),
+			Type: p.Type,
+		})
+	}
+	sort.Slice(cfg.Providers, func(i, j int) bool {
+		if cfg.Providers[i].Type != cfg.Providers[j].Type {
+			return cfg.Providers[i].Type < cfg.Providers[j].Type
+		}
+		return cfg.Providers[i].Name < cfg.Providers[j].Name
+	})
+
+	cfgBytes, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal the clusterctl configuration")
+	}
+	configPath := filepath.Join(directory, bundleConfigFile)
+	if err := os.WriteFile(configPath, cfgBytes, 0600); err != nil {
+		return "", errors.Wrapf(err, "failed to write %q", configPath)
+	}
+
+	log.V(1).Info("Bundle extracted", "directory", directory, "config", configPath)
+	return configPath, nil
+}
+
+// bundleFileURL returns the file:// URL for a file in an extracted bundle.
+// NOTE: URLs are used instead of plain paths so Windows paths are handled by the local repository as expected.
+func bundleFileURL(directory, p string) string {
+	u := filepath.ToSlash(filepath.Join(directory, filepath.FromSlash(p)))
+	if !strings.HasPrefix(u, "/") {
+		u = "/" + u
+	}
+	return "file://" + u
+}
+
+// writeBundle writes files into a gzipped tarball.
+func writeBundle(outputFile string, files map[string][]byte) error {
+	if dir := filepath.Dir(outputFile); dir != "" {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Create(outputFile) //nolint:gosec
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	// Write files in a stable order, so bundles with the same content are identical.
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		content := files[name]
+		if err := tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0600,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}); err != nil {
+			return err
+		}
+		if _, err := tw.Write(content); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// extractBundle extracts a gzipped tarball into a directory.
+func extractBundle(bundleFile, directory string) error {
+	f, err := os.Open(bundleFile) //nolint:gosec
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		// Ensure files are extracted inside the target directory.
+		target := filepath.Join(directory, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(target, filepath.Clean(directory)+string(os.PathSeparator)) {
+			return errors.Errorf("invalid file path %q in bundle", header.Name)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+			return err
+		}
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600) //nolint:gosec
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, tr); err != nil { //nolint:gosec // bundle files are created by clusterctl and expected to be small
+			out.Close()
+			return err
+		}
+		if err := out.Close(); err != nil {
+			return err
+		}
+	}
+}
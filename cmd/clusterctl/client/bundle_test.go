@@ -0,0 +1,214 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/yaml"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
+)
+
+var certManagerManifestYAML = []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: cert-manager
+  namespace: cert-manager
+spec:
+  template:
+    spec:
+      containers:
+      - image: quay.io/jetstack/cert-manager-controller:v1.16.3
+        name: cert-manager
+`)
+
+func fakeClientForBundle() *fakeClient {
+	client := fakeEmptyCluster()
+
+	certManagerConfig, _ := client.configClient.CertManager().Get()
+	certManagerProvider := config.NewProvider("cert-manager", certManagerConfig.URL(), "")
+	client.repositories[certManagerProvider.ManifestLabel()] = newFakeRepository(ctx, certManagerProvider, client.configClient).
+		WithPaths("root", "cert-manager.yaml").
+		WithDefaultVersion(certManagerConfig.Version()).
+		WithFile(certManagerConfig.Version(), "cert-manager.yaml", certManagerManifestYAML)
+
+	return client
+}
+
+func Test_clusterctlClient_CreateBundle(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   CreateBundleOptions
+		wantFiles []string
+		wantErr   bool
+	}{
+		{
+			name: "creates a bundle with default providers, templates and cert-manager",
+			options: CreateBundleOptions{
+				InfrastructureProviders: []string{"infra"},
+				Flavors:                 []string{"does-not-exist"},
+			},
+			wantFiles: []string{
+				"bundle.yaml",
+				"images.txt",
+				"cluster-api/v1.0.0/url",
+				"cluster-api/v1.0.0/metadata.yaml",
+				"bootstrap-kubeadm/v2.0.0/url",
+				"bootstrap-kubeadm/v2.0.0/metadata.yaml",
+				"control-plane-kubeadm/v2.0.0/url",
+				"control-plane-kubeadm/v2.0.0/metadata.yaml",
+				"infrastructure-infra/v3.0.0/url",
+				"infrastructure-infra/v3.0.0/metadata.yaml",
+				"infrastructure-infra/v3.0.0/cluster-template.yaml",
+				"cert-manager/" + config.CertManagerDefaultVersion + "/cert-manager.yaml",
+			},
+		},
+		{
+			name: "creates a bundle with an explicit provider version and without the control plane provider",
+			options: CreateBundleOptions{
+				CoreProvider:          "cluster-api:v1.1.0",
+				ControlPlaneProviders: []string{NoopProvider},
+			},
+			wantFiles: []string{
+				"bundle.yaml",
+				"images.txt",
+				"cluster-api/v1.1.0/url",
+				"cluster-api/v1.1.0/metadata.yaml",
+				"bootstrap-kubeadm/v2.0.0/url",
+				"bootstrap-kubeadm/v2.0.0/metadata.yaml",
+				"cert-manager/" + config.CertManagerDefaultVersion + "/cert-manager.yaml",
+			},
+		},
+		{
+			name: "fails if a provider does not exist",
+			options: CreateBundleOptions{
+				InfrastructureProviders: []string{"not-provided"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "fails if the core provider is disabled",
+			options: CreateBundleOptions{
+				CoreProvider: NoopProvider,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			tmpDir := t.TempDir()
+			tt.options.OutputFile = filepath.Join(tmpDir, "bundle.tar.gz")
+
+			client := fakeClientForBundle()
+			err := client.CreateBundle(ctx, tt.options)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			extractDir := filepath.Join(tmpDir, "extracted")
+			g.Expect(extractBundle(tt.options.OutputFile, extractDir)).To(Succeed())
+
+			var gotFiles []string
+			g.Expect(filepath.Walk(extractDir, func(path string, info os.FileInfo, err error) error {
+				if err != nil || info.IsDir() {
+					return err
+				}
+				rel, err := filepath.Rel(extractDir, path)
+				gotFiles = append(gotFiles, filepath.ToSlash(rel))
+				return err
+			})).To(Succeed())
+			g.Expect(gotFiles).To(ConsistOf(tt.wantFiles))
+
+			images, err := os.ReadFile(filepath.Join(extractDir, bundleImagesFile)) //nolint:gosec
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(string(images)).To(ContainSubstring("quay.io/jetstack/cert-manager-controller:v1.16.3"))
+		})
+	}
+}
+
+func Test_clusterctlClient_UseBundle(t *testing.T) {
+	g := NewWithT(t)
+
+	tmpDir := t.TempDir()
+	bundleFile := filepath.Join(tmpDir, "bundle.tar.gz")
+
+	client := fakeClientForBundle()
+	g.Expect(client.CreateBundle(ctx, CreateBundleOptions{
+		InfrastructureProviders: []string{"infra"},
+		OutputFile:              bundleFile,
+	})).To(Succeed())
+
+	configPath, err := client.UseBundle(ctx, UseBundleOptions{
+		BundleFile: bundleFile,
+		Directory:  filepath.Join(tmpDir, "repository"),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	raw, err := os.ReadFile(configPath) //nolint:gosec
+	g.Expect(err).ToNot(HaveOccurred())
+	cfg := &bundleConfig{}
+	g.Expect(yaml.Unmarshal(raw, cfg)).To(Succeed())
+	g.Expect(cfg.Providers).To(HaveLen(4))
+	g.Expect(cfg.CertManager.Version).To(Equal(config.CertManagerDefaultVersion))
+
+	// The generated configuration file must be usable by the local repository implementation.
+	configClient, err := config.New(ctx, configPath)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	providerConfig, err := configClient.Providers().Get("infra", clusterctlv1.InfrastructureProviderType)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	repositoryClient, err := repository.New(ctx, providerConfig, configClient)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(repositoryClient.DefaultVersion()).To(Equal("v3.0.0"))
+
+	components, err := repositoryClient.Components().Raw(ctx, repository.ComponentsOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(components).To(Equal(infraComponentsYAML("ns4")))
+
+	templateClient, ok := repositoryClient.Templates("v3.0.0").(repository.RawTemplateClient)
+	g.Expect(ok).To(BeTrue())
+	template, err := templateClient.Raw(ctx, "")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(template).To(Equal(templateYAML("ns4", "test")))
+
+	metadata, err := repositoryClient.Metadata("v3.0.0").Get(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(metadata.ReleaseSeries).To(HaveLen(2))
+}
+
+func Test_extractBundle_rejectsPathTraversal(t *testing.T) {
+	g := NewWithT(t)
+
+	tmpDir := t.TempDir()
+	bundleFile := filepath.Join(tmpDir, "bundle.tar.gz")
+	g.Expect(writeBundle(bundleFile, map[string][]byte{"../evil.yaml": []byte("evil")})).To(Succeed())
+
+	err := extractBundle(bundleFile, filepath.Join(tmpDir, "extracted"))
+	g.Expect(err).To(MatchError(ContainSubstring("invalid file path")))
+}
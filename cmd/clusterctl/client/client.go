@@ -44,9 +44,20 @@ type Client interface {
 	// InitImages returns the list of images required for executing the init command.
 	InitImages(ctx context.Context, options InitOptions) ([]string, error)
 
+	// CreateBundle creates a tarball with the provider and cert-manager manifests required for initializing
+	// a management cluster in a disconnected environment.
+	CreateBundle(ctx context.Context, options CreateBundleOptions) error
+
+	// UseBundle extracts a bundle and returns the path of a clusterctl configuration file pointing to it.
+	UseBundle(ctx context.Context, options UseBundleOptions) (string, error)
+
 	// GetClusterTemplate returns a workload cluster template.
 	GetClusterTemplate(ctx context.Context, options GetClusterTemplateOptions) (Template, error)
 
+	// ValidateClusterTemplate validates a workload cluster template against the schemas of the provider CRDs
+	// and the variables of the ClusterClasses it uses, and returns the objects failing validation.
+	ValidateClusterTemplate(ctx context.Context, options ValidateClusterTemplateOptions) ([]TemplateValidationResult, error)
+
 	// GetKubeconfig returns the kubeconfig of the workload cluster.
 	GetKubeconfig(ctx context.Context, options GetKubeconfigOptions) (string, error)
 
@@ -56,15 +67,27 @@ type Client interface {
 	// Move moves all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a target management cluster.
 	Move(ctx context.Context, options MoveOptions) error
 
+	// PlanMove returns the plan for moving all the Cluster API objects existing in a namespace (or from all the namespaces if empty)
+	// to a target management cluster, without performing any actual action.
+	PlanMove(ctx context.Context, options MoveOptions) (*MovePlan, error)
+
 	// PlanUpgrade returns a set of suggested Upgrade plans for the cluster.
 	PlanUpgrade(ctx context.Context, options PlanUpgradeOptions) ([]UpgradePlan, error)
 
 	// PlanCertManagerUpgrade returns a CertManagerUpgradePlan.
 	PlanCertManagerUpgrade(ctx context.Context, options PlanUpgradeOptions) (CertManagerUpgradePlan, error)
 
+	// PlanFleetUpgrade returns the provider inventory, the health and the upgrade plan of each management cluster of a fleet,
+	// planning the upgrade of the management clusters in parallel.
+	PlanFleetUpgrade(ctx context.Context, options PlanFleetUpgradeOptions) ([]FleetUpgradePlan, error)
+
 	// ApplyUpgrade executes an upgrade plan.
 	ApplyUpgrade(ctx context.Context, options ApplyUpgradeOptions) error
 
+	// ApplyManagementCluster reconciles a management cluster with a ManagementCluster, by installing the missing providers
+	// and upgrading the providers behind the desired version, and returns the plan with drift that cannot be reconciled.
+	ApplyManagementCluster(ctx context.Context, options ApplyManagementClusterOptions) (*ManagementClusterPlan, error)
+
 	// ProcessYAML provides a direct way to process a yaml and inspect its
 	// variables.
 	ProcessYAML(ctx context.Context, options ProcessYAMLOptions) (YamlPrinter, error)
@@ -72,6 +95,14 @@ type Client interface {
 	// DescribeCluster returns the object tree representing the status of a Cluster API cluster.
 	DescribeCluster(ctx context.Context, options DescribeClusterOptions) (*tree.ObjectTree, error)
 
+	// WatchCluster watches the objects representing the status of a Cluster API cluster, and calls the handler
+	// with an updated object tree every time any of those objects changes, until the context is canceled.
+	WatchCluster(ctx context.Context, options DescribeClusterOptions, handler func(*tree.ObjectTree) error) error
+
+	// Doctor checks the health of a management cluster against what clusterctl knows about it, and returns
+	// a report with the outcome of each check.
+	Doctor(ctx context.Context, options DoctorOptions) (*DoctorReport, error)
+
 	// AlphaClient is an Interface for alpha features in clusterctl
 	AlphaClient
 }
@@ -88,6 +119,10 @@ type AlphaClient interface {
 	//
 	// Deprecated: RolloutUndo is deprecated and will be removed in one of the upcoming releases.
 	RolloutUndo(ctx context.Context, options RolloutUndoOptions) error
+	// RolloutStatus provides the rollout status of cluster-api resources
+	RolloutStatus(ctx context.Context, options RolloutStatusOptions) ([]RolloutStatus, error)
+	// RolloutHistory provides the rollout history of cluster-api resources
+	RolloutHistory(ctx context.Context, options RolloutHistoryOptions) ([]RolloutHistory, error)
 	// TopologyPlan dry runs the topology reconciler
 	//
 	// Deprecated: TopologyPlan is deprecated and will be removed in one of the upcoming releases.
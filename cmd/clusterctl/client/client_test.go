@@ -91,6 +91,10 @@ func (f fakeClient) GetClusterTemplate(ctx context.Context, options GetClusterTe
 	return f.internalClient.GetClusterTemplate(ctx, options)
 }
 
+func (f fakeClient) ValidateClusterTemplate(ctx context.Context, options ValidateClusterTemplateOptions) ([]TemplateValidationResult, error) {
+	return f.internalClient.ValidateClusterTemplate(ctx, options)
+}
+
 func (f fakeClient) GetKubeconfig(ctx context.Context, options GetKubeconfigOptions) (string, error) {
 	return f.internalClient.GetKubeconfig(ctx, options)
 }
@@ -103,6 +107,14 @@ func (f fakeClient) InitImages(ctx context.Context, options InitOptions) ([]stri
 	return f.internalClient.InitImages(ctx, options)
 }
 
+func (f fakeClient) CreateBundle(ctx context.Context, options CreateBundleOptions) error {
+	return f.internalClient.CreateBundle(ctx, options)
+}
+
+func (f fakeClient) UseBundle(ctx context.Context, options UseBundleOptions) (string, error) {
+	return f.internalClient.UseBundle(ctx, options)
+}
+
 func (f fakeClient) Delete(ctx context.Context, options DeleteOptions) error {
 	return f.internalClient.Delete(ctx, options)
 }
@@ -111,10 +123,18 @@ func (f fakeClient) Move(ctx context.Context, options MoveOptions) error {
 	return f.internalClient.Move(ctx, options)
 }
 
+func (f fakeClient) PlanMove(ctx context.Context, options MoveOptions) (*MovePlan, error) {
+	return f.internalClient.PlanMove(ctx, options)
+}
+
 func (f fakeClient) PlanUpgrade(ctx context.Context, options PlanUpgradeOptions) ([]UpgradePlan, error) {
 	return f.internalClient.PlanUpgrade(ctx, options)
 }
 
+func (f fakeClient) PlanFleetUpgrade(ctx context.Context, options PlanFleetUpgradeOptions) ([]FleetUpgradePlan, error) {
+	return f.internalClient.PlanFleetUpgrade(ctx, options)
+}
+
 func (f fakeClient) PlanCertManagerUpgrade(ctx context.Context, options PlanUpgradeOptions) (CertManagerUpgradePlan, error) {
 	return f.internalClient.PlanCertManagerUpgrade(ctx, options)
 }
@@ -123,6 +143,10 @@ func (f fakeClient) ApplyUpgrade(ctx context.Context, options ApplyUpgradeOption
 	return f.internalClient.ApplyUpgrade(ctx, options)
 }
 
+func (f fakeClient) ApplyManagementCluster(ctx context.Context, options ApplyManagementClusterOptions) (*ManagementClusterPlan, error) {
+	return f.internalClient.ApplyManagementCluster(ctx, options)
+}
+
 func (f fakeClient) ProcessYAML(ctx context.Context, options ProcessYAMLOptions) (YamlPrinter, error) {
 	return f.internalClient.ProcessYAML(ctx, options)
 }
@@ -135,6 +159,14 @@ func (f fakeClient) DescribeCluster(ctx context.Context, options DescribeCluster
 	return f.internalClient.DescribeCluster(ctx, options)
 }
 
+func (f fakeClient) Doctor(ctx context.Context, options DoctorOptions) (*DoctorReport, error) {
+	return f.internalClient.Doctor(ctx, options)
+}
+
+func (f fakeClient) WatchCluster(ctx context.Context, options DescribeClusterOptions, handler func(*tree.ObjectTree) error) error {
+	return f.internalClient.WatchCluster(ctx, options, handler)
+}
+
 func (f fakeClient) RolloutPause(ctx context.Context, options RolloutPauseOptions) error {
 	return f.internalClient.RolloutPause(ctx, options)
 }
@@ -147,6 +179,14 @@ func (f fakeClient) RolloutUndo(ctx context.Context, options RolloutUndoOptions)
 	return f.internalClient.RolloutUndo(ctx, options)
 }
 
+func (f fakeClient) RolloutStatus(ctx context.Context, options RolloutStatusOptions) ([]RolloutStatus, error) {
+	return f.internalClient.RolloutStatus(ctx, options)
+}
+
+func (f fakeClient) RolloutHistory(ctx context.Context, options RolloutHistoryOptions) ([]RolloutHistory, error) {
+	return f.internalClient.RolloutHistory(ctx, options)
+}
+
 func (f fakeClient) TopologyPlan(ctx context.Context, options TopologyPlanOptions) (*cluster.TopologyPlanOutput, error) {
 	return f.internalClient.TopologyPlan(ctx, options)
 }
@@ -325,6 +365,10 @@ func (f *fakeClusterClient) Topology() cluster.TopologyClient {
 	return f.internalclient.Topology()
 }
 
+func (f *fakeClusterClient) Doctor() cluster.DoctorClient {
+	return f.internalclient.Doctor()
+}
+
 func (f *fakeClusterClient) WithObjs(objs ...client.Object) *fakeClusterClient {
 	f.fakeProxy.WithObjs(objs...)
 	return f
@@ -387,6 +431,10 @@ func (f fakeConfigClient) ImageMeta() config.ImageMetaClient {
 	return f.internalclient.ImageMeta()
 }
 
+func (f fakeConfigClient) Verification() config.VerificationClient {
+	return f.internalclient.Verification()
+}
+
 func (f *fakeConfigClient) WithVar(key, value string) *fakeConfigClient {
 	f.fakeReader.WithVar(key, value)
 	return f
@@ -504,13 +552,7 @@ type fakeTemplateClient struct {
 }
 
 func (f *fakeTemplateClient) Get(ctx context.Context, flavor, targetNamespace string, skipTemplateProcess bool) (repository.Template, error) {
-	name := "cluster-template"
-	if flavor != "" {
-		name = fmt.Sprintf("%s-%s", name, flavor)
-	}
-	name = fmt.Sprintf("%s.yaml", name)
-
-	content, err := f.fakeRepository.GetFile(ctx, f.version, name)
+	content, err := f.Raw(ctx, flavor)
 	if err != nil {
 		return nil, err
 	}
@@ -523,6 +565,16 @@ func (f *fakeTemplateClient) Get(ctx context.Context, flavor, targetNamespace st
 	})
 }
 
+func (f *fakeTemplateClient) Raw(ctx context.Context, flavor string) ([]byte, error) {
+	name := "cluster-template"
+	if flavor != "" {
+		name = fmt.Sprintf("%s-%s", name, flavor)
+	}
+	name = fmt.Sprintf("%s.yaml", name)
+
+	return f.fakeRepository.GetFile(ctx, f.version, name)
+}
+
 // fakeClusterClassClient provides a super simple TemplateClient (e.g. without support for local overrides).
 type fakeClusterClassClient struct {
 	version               string
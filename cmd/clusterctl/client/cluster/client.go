@@ -82,6 +82,9 @@ type Client interface {
 
 	// Topology returns a TopologyClient that can be used for performing dry run executions of the topology reconciler.
 	Topology() TopologyClient
+
+	// Doctor returns a DoctorClient that can be used for diagnosing the health of the management cluster.
+	Doctor() DoctorClient
 }
 
 // PollImmediateWaiter tries a condition func until it returns true, an error, or the timeout is reached.
@@ -147,6 +150,10 @@ func (c *clusterClient) Topology() TopologyClient {
 	return newTopologyClient(c.proxy, c.ProviderInventory())
 }
 
+func (c *clusterClient) Doctor() DoctorClient {
+	return newDoctorClient(c.configClient, c.proxy, c.repositoryClientFactory, c.ProviderInventory(), c.ProviderComponents())
+}
+
 // Option is a configuration option supplied to New.
 type Option func(*clusterClient)
 
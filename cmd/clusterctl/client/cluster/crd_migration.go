@@ -20,26 +20,57 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/scheme"
 	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
 )
 
 // CRDMigrator interface defines methods for migrating CRs to the storage version of new CRDs.
 type CRDMigrator interface {
+	// Run migrates CRs to the storage version of new CRDs.
 	Run(ctx context.Context, objs []unstructured.Unstructured) error
+
+	// Plan returns the migrations of CRs to the storage version of new CRDs, without performing any change.
+	Plan(ctx context.Context, objs []unstructured.Unstructured) ([]CRDMigration, error)
+}
+
+// CRDMigration defines the migration of the CRs of a CRD to the storage version of the CRD.
+type CRDMigration struct {
+	// CRD is the name of the CustomResourceDefinition.
+	CRD string
+
+	// Kind of the CRs.
+	Kind string
+
+	// StorageVersion is the version the CRs are migrated to.
+	StorageVersion string
+
+	// StoredVersionsToDelete are the versions the CRs might still be stored in.
+	StoredVersionsToDelete []string
+
+	// Objects is the number of CRs.
+	Objects int
+
+	// MigratedObjects is the number of CRs already migrated by a previous interrupted migration;
+	// the migration resumes from the first CR not yet migrated.
+	MigratedObjects int
 }
 
 // crdMigrator migrates CRs to the storage version of new CRDs.
@@ -47,13 +78,33 @@ type CRDMigrator interface {
 // was previously used as a storage version.
 type crdMigrator struct {
 	Client client.Client
+
+	// Concurrency is the number of CRs migrated concurrently.
+	Concurrency int
+}
+
+// CRDMigratorOption is a configuration option supplied to NewCRDMigrator.
+type CRDMigratorOption func(*crdMigrator)
+
+// WithCRDMigrationConcurrency sets the number of CRs migrated concurrently; if not set, CRs are migrated one at a time.
+func WithCRDMigrationConcurrency(concurrency int) CRDMigratorOption {
+	return func(m *crdMigrator) {
+		if concurrency > 0 {
+			m.Concurrency = concurrency
+		}
+	}
 }
 
 // NewCRDMigrator creates a new CRD migrator.
-func NewCRDMigrator(client client.Client) CRDMigrator {
-	return &crdMigrator{
-		Client: client,
+func NewCRDMigrator(client client.Client, options ...CRDMigratorOption) CRDMigrator {
+	m := &crdMigrator{
+		Client:      client,
+		Concurrency: 1,
 	}
+	for _, o := range options {
+		o(m)
+	}
+	return m
 }
 
 // Run migrates CRs to the storage version of new CRDs.
@@ -77,10 +128,52 @@ func (m *crdMigrator) Run(ctx context.Context, objs []unstructured.Unstructured)
 	return nil
 }
 
+// Plan returns the migrations of CRs to the storage version of new CRDs, without performing any change.
+func (m *crdMigrator) Plan(ctx context.Context, objs []unstructured.Unstructured) ([]CRDMigration, error) {
+	migrations := []CRDMigration{}
+	for i := range objs {
+		obj := objs[i]
+
+		if obj.GetKind() == "CustomResourceDefinition" {
+			crd := &apiextensionsv1.CustomResourceDefinition{}
+			if err := scheme.Scheme.Convert(&obj, crd, nil); err != nil {
+				return nil, errors.Wrapf(err, "failed to convert CRD %q", obj.GetName())
+			}
+
+			migration, _, err := m.plan(ctx, crd)
+			if err != nil {
+				return nil, err
+			}
+			if migration != nil {
+				migrations = append(migrations, *migration)
+			}
+		}
+	}
+	return migrations, nil
+}
+
 // run migrates CRs of a new CRD.
 // This is necessary when the new CRD drops or stops serving
 // a version which was previously used as a storage version.
 func (m *crdMigrator) run(ctx context.Context, newCRD *apiextensionsv1.CustomResourceDefinition) (bool, error) {
+	migration, currentCRD, err := m.plan(ctx, newCRD)
+	if err != nil || migration == nil {
+		return false, err
+	}
+
+	if err := m.migrateResourcesForCRD(ctx, currentCRD, migration); err != nil {
+		return false, err
+	}
+
+	if err := m.patchCRDStoredVersions(ctx, currentCRD, migration.StorageVersion); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// plan returns the migration of the CRs of a new CRD, if required, and the current CRD.
+func (m *crdMigrator) plan(ctx context.Context, newCRD *apiextensionsv1.CustomResourceDefinition) (*CRDMigration, *apiextensionsv1.CustomResourceDefinition, error) {
 	log := logf.Log
 
 	// Gets the list of version supported by the new CRD
@@ -96,30 +189,64 @@ func (m *crdMigrator) run(ctx context.Context, newCRD *apiextensionsv1.CustomRes
 	}); err != nil {
 		// Return if the CRD doesn't exist yet. We only have to migrate if the CRD exists already.
 		if apierrors.IsNotFound(err) {
-			return false, nil
+			return nil, nil, nil
 		}
-		return false, err
+		return nil, nil, err
 	}
 
 	// Get the storage version of the current CRD.
 	currentStorageVersion, err := storageVersionForCRD(currentCRD)
 	if err != nil {
-		return false, err
+		return nil, nil, err
 	}
 
 	// Return an error, if the current storage version has been dropped in the new CRD.
 	if !newVersions.Has(currentStorageVersion) {
-		return false, errors.Errorf("unable to upgrade CRD %q because the new CRD does not contain the storage version %q of the current CRD, thus not allowing CR migration", newCRD.Name, currentStorageVersion)
+		return nil, nil, errors.Errorf("unable to upgrade CRD %q because the new CRD does not contain the storage version %q of the current CRD, thus not allowing CR migration", newCRD.Name, currentStorageVersion)
 	}
 
-	currentStatusStoredVersions := sets.Set[string]{}.Insert(currentCRD.Status.StoredVersions...)
+	storedVersionsToDelete := storedVersionsToMigrate(currentCRD, currentStorageVersion)
 	// If the old CRD only contains its current storageVersion as storedVersion,
 	// nothing to do as all objects are already on the current storageVersion.
 	// Note: We want to migrate objects to new storage versions as soon as possible
 	// to prevent unnecessary conversion webhook calls.
-	if currentStatusStoredVersions.Len() == 1 && currentCRD.Status.StoredVersions[0] == currentStorageVersion {
+	if storedVersionsToDelete.Len() == 0 {
 		log.V(2).Info("CRD migration check passed", "CustomResourceDefinition", klog.KObj(newCRD))
-		return false, nil
+		return nil, nil, nil
+	}
+
+	migration := &CRDMigration{
+		CRD:                    currentCRD.Name,
+		Kind:                   currentCRD.Spec.Names.Kind,
+		StorageVersion:         currentStorageVersion,
+		StoredVersionsToDelete: sets.List(storedVersionsToDelete),
+	}
+
+	// Count the CRs to be migrated, and the ones already migrated by a previous interrupted migration, if any.
+	checkpoint := migrationCheckpoint(currentCRD, currentStorageVersion)
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   currentCRD.Spec.Group,
+		Version: currentStorageVersion,
+		Kind:    currentCRD.Spec.Names.ListKind,
+	})
+	for {
+		if err := retryWithExponentialBackoff(ctx, newReadBackoff(), func(ctx context.Context) error {
+			return m.Client.List(ctx, list, client.Limit(crdMigrationPageSize), client.Continue(list.GetContinue()))
+		}); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to list %q", list.GetKind())
+		}
+
+		for i := range list.Items {
+			migration.Objects++
+			if checkpoint != "" && migrationKey(&list.Items[i]) <= checkpoint {
+				migration.MigratedObjects++
+			}
+		}
+
+		if list.GetContinue() == "" {
+			break
+		}
 	}
 
 	// Note: We are simply migrating all CR objects independent of the version in which they are actually stored in etcd.
@@ -127,54 +254,64 @@ func (m *crdMigrator) run(ctx context.Context, newCRD *apiextensionsv1.CustomRes
 	// Alternatively, we would have to figure out which objects are stored in which version but this information is not
 	// exposed by the apiserver.
 	// Ref https://kubernetes.io/docs/tasks/extend-kubernetes/custom-resources/custom-resource-definition-versioning/#writing-reading-and-updating-versioned-customresourcedefinition-objects
-	storedVersionsToDelete := currentStatusStoredVersions.Delete(currentStorageVersion)
-	log.Info("CR migration required", "kind", newCRD.Spec.Names.Kind, "storedVersionsToDelete", strings.Join(sets.List(storedVersionsToDelete), ","), "storedVersionToPreserve", currentStorageVersion)
-
-	if err := m.migrateResourcesForCRD(ctx, currentCRD, currentStorageVersion); err != nil {
-		return false, err
-	}
+	log.Info("CR migration required", "kind", newCRD.Spec.Names.Kind, "storedVersionsToDelete", strings.Join(migration.StoredVersionsToDelete, ","), "storedVersionToPreserve", currentStorageVersion, "objects", migration.Objects)
 
-	if err := m.patchCRDStoredVersions(ctx, currentCRD, currentStorageVersion); err != nil {
-		return false, err
-	}
-
-	return true, nil
+	return migration, currentCRD, nil
 }
 
-func (m *crdMigrator) migrateResourcesForCRD(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition, currentStorageVersion string) error {
+func (m *crdMigrator) migrateResourcesForCRD(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition, migration *CRDMigration) error {
 	log := logf.Log.WithValues("CustomResourceDefinition", klog.KObj(crd))
-	log.Info("Migrating CRs, this operation may take a while...")
+
+	checkpoint := migrationCheckpoint(crd, migration.StorageVersion)
+	if checkpoint != "" {
+		log.Info("Resuming CR migration", "objects", migration.Objects, "alreadyMigrated", migration.MigratedObjects)
+	}
+	log.Info("Migrating CRs, this operation may take a while...", "objects", migration.Objects, "concurrency", m.Concurrency)
 
 	list := &unstructured.UnstructuredList{}
 	list.SetGroupVersionKind(schema.GroupVersionKind{
 		Group:   crd.Spec.Group,
-		Version: currentStorageVersion,
+		Version: migration.StorageVersion,
 		Kind:    crd.Spec.Names.ListKind,
 	})
 
-	var i int
+	// Checkpoints are written only if CRs are listed in order, which is the case with the API server
+	// given that CRs are listed in the order of the keys in etcd, i.e. by namespace and name.
+	ordered := true
+	lastKey := ""
+	migrated := migration.MigratedObjects
+	var updated atomic.Int64
 	for {
 		if err := retryWithExponentialBackoff(ctx, newCRDMigrationBackoff(), func(ctx context.Context) error {
-			return m.Client.List(ctx, list, client.Continue(list.GetContinue()))
+			return m.Client.List(ctx, list, client.Limit(crdMigrationPageSize), client.Continue(list.GetContinue()))
 		}); err != nil {
 			return errors.Wrapf(err, "failed to list %q", list.GetKind())
 		}
 
+		// Skip the CRs already migrated by a previous interrupted migration.
+		objs := make([]unstructured.Unstructured, 0, len(list.Items))
 		for i := range list.Items {
-			obj := list.Items[i]
+			key := migrationKey(&list.Items[i])
+			if key < lastKey {
+				ordered = false
+			}
+			lastKey = key
 
-			log.V(5).Info("Migrating", logf.UnstructuredToValues(obj)...)
-			if err := retryWithExponentialBackoff(ctx, newCRDMigrationBackoff(), func(ctx context.Context) error {
-				return handleMigrateErr(m.Client.Update(ctx, &obj))
-			}); err != nil {
-				return errors.Wrapf(err, "failed to migrate %s/%s", obj.GetNamespace(), obj.GetName())
+			if checkpoint != "" && key <= checkpoint {
+				continue
 			}
+			objs = append(objs, list.Items[i])
+		}
 
-			// Add some random delays to avoid pressure on the API server.
-			i++
-			if i%10 == 0 {
-				log.V(2).Info(fmt.Sprintf("%d objects migrated", i))
-				time.Sleep(time.Duration(rand.IntnRange(50*int(time.Millisecond), 250*int(time.Millisecond))))
+		if err := m.migrateResources(ctx, objs, &updated); err != nil {
+			return err
+		}
+		migrated += len(objs)
+		log.Info("CR migration progress", "migrated", migrated, "objects", max(migration.Objects, migrated))
+
+		if ordered && len(objs) > 0 && list.GetContinue() != "" {
+			if err := m.patchCRDMigrationCheckpoint(ctx, crd, fmt.Sprintf("%s:%s", migration.StorageVersion, lastKey)); err != nil {
+				return err
 			}
 		}
 
@@ -183,11 +320,91 @@ func (m *crdMigrator) migrateResourcesForCRD(ctx context.Context, crd *apiextens
 		}
 	}
 
-	log.V(2).Info(fmt.Sprintf("CR migration completed: migrated %d objects", i))
+	log.V(2).Info(fmt.Sprintf("CR migration completed: migrated %d objects", updated.Load()))
+	return nil
+}
+
+// migrateResources migrates a set of CRs, using up to Concurrency workers.
+func (m *crdMigrator) migrateResources(ctx context.Context, objs []unstructured.Unstructured, updated *atomic.Int64) error {
+	log := logf.Log
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	objCh := make(chan *unstructured.Unstructured)
+	errCh := make(chan error, len(objs))
+	wg := sync.WaitGroup{}
+	for range max(m.Concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for obj := range objCh {
+				log.V(5).Info("Migrating", logf.UnstructuredToValues(*obj)...)
+				if err := retryWithExponentialBackoff(ctx, newCRDMigrationBackoff(), func(ctx context.Context) error {
+					return handleMigrateErr(m.Client.Update(ctx, obj))
+				}); err != nil {
+					errCh <- errors.Wrapf(err, "failed to migrate %s/%s", obj.GetNamespace(), obj.GetName())
+					cancel()
+					continue
+				}
+
+				// Add some random delays to avoid pressure on the API server.
+				if i := updated.Add(1); i%10 == 0 {
+					log.V(2).Info(fmt.Sprintf("%d objects migrated", i))
+					time.Sleep(time.Duration(rand.IntnRange(50*int(time.Millisecond), 250*int(time.Millisecond))))
+				}
+			}
+		}()
+	}
+
+	for i := range objs {
+		if ctx.Err() != nil {
+			break
+		}
+		objCh <- &objs[i]
+	}
+	close(objCh)
+	wg.Wait()
+	close(errCh)
+
+	errs := []error{}
+	for err := range errCh {
+		errs = append(errs, err)
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// patchCRDMigrationCheckpoint records the last CR migrated on the CRD, so an interrupted migration can be resumed.
+func (m *crdMigrator) patchCRDMigrationCheckpoint(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition, checkpoint string) error {
+	original := crd.DeepCopy()
+	annotations := crd.GetAnnotations()
+	if checkpoint == "" {
+		if _, ok := annotations[clusterctlv1.CRDMigrationCheckpointAnnotation]; !ok {
+			return nil
+		}
+		delete(annotations, clusterctlv1.CRDMigrationCheckpointAnnotation)
+	} else {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[clusterctlv1.CRDMigrationCheckpointAnnotation] = checkpoint
+	}
+	crd.SetAnnotations(annotations)
+
+	if err := retryWithExponentialBackoff(ctx, newWriteBackoff(), func(ctx context.Context) error {
+		return m.Client.Patch(ctx, crd, client.MergeFrom(original))
+	}); err != nil {
+		return errors.Wrapf(err, "failed to update the CR migration checkpoint for CRD %q", crd.Name)
+	}
 	return nil
 }
 
 func (m *crdMigrator) patchCRDStoredVersions(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition, currentStorageVersion string) error {
+	// Remove the checkpoint before updating stored versions, so a stale checkpoint is never left on a migrated CRD.
+	if err := m.patchCRDMigrationCheckpoint(ctx, crd, ""); err != nil {
+		return err
+	}
+
 	crd.Status.StoredVersions = []string{currentStorageVersion}
 	if err := retryWithExponentialBackoff(ctx, newWriteBackoff(), func(ctx context.Context) error {
 		return m.Client.Status().Update(ctx, crd)
@@ -218,6 +435,31 @@ func handleMigrateErr(err error) error {
 	return err
 }
 
+// storedVersionsToMigrate returns the versions listed in the CRD status.storedVersions, other than the current storage version;
+// if the list is not empty, CR objects might still be stored in those versions and a migration is required.
+func storedVersionsToMigrate(crd *apiextensionsv1.CustomResourceDefinition, currentStorageVersion string) sets.Set[string] {
+	return sets.New[string](crd.Status.StoredVersions...).Delete(currentStorageVersion)
+}
+
+// migrationCheckpoint returns the key of the last CR migrated by a previous interrupted migration to the storage version, if any.
+func migrationCheckpoint(crd *apiextensionsv1.CustomResourceDefinition, storageVersion string) string {
+	checkpoint, ok := crd.GetAnnotations()[clusterctlv1.CRDMigrationCheckpointAnnotation]
+	if !ok {
+		return ""
+	}
+	// Ignore checkpoints of migrations to other storage versions.
+	version, key, ok := strings.Cut(checkpoint, ":")
+	if !ok || version != storageVersion {
+		return ""
+	}
+	return key
+}
+
+// migrationKey returns the key used to track the progress of a migration; it sorts like CRs are listed by the API server.
+func migrationKey(obj metav1.Object) string {
+	return fmt.Sprintf("%s/%s", obj.GetNamespace(), obj.GetName())
+}
+
 // storageVersionForCRD discovers the storage version for a given CRD.
 func storageVersionForCRD(crd *apiextensionsv1.CustomResourceDefinition) (string, error) {
 	for _, v := range crd.Spec.Versions {
@@ -228,6 +470,9 @@ func storageVersionForCRD(crd *apiextensionsv1.CustomResourceDefinition) (string
 	return "", errors.Errorf("could not find storage version for CRD %q", crd.Name)
 }
 
+// crdMigrationPageSize is the number of CRs listed at a time during a migration.
+const crdMigrationPageSize = 500
+
 // newCRDMigrationBackoff creates a new API Machinery backoff parameter set suitable for use with crd migration operations.
 // Clusterctl upgrades cert-manager right before doing CRD migration. This may lead to rollout of new certificates.
 // The time between new certificate creation + injection into objects (CRD, Webhooks) and the new secrets getting propagated
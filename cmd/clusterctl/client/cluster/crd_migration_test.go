@@ -19,6 +19,7 @@ package cluster
 import (
 	"context"
 	"fmt"
+	"sync"
 	"testing"
 
 	. "github.com/onsi/gomega"
@@ -27,6 +28,8 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/scheme"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
 )
 
@@ -236,19 +239,152 @@ func Test_CRDMigrator(t *testing.T) {
 	}
 }
 
+func Test_CRDMigrator_PlanAndResume(t *testing.T) {
+	newCR := func(name string) unstructured.Unstructured {
+		return unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "foo/v1beta1",
+				"kind":       "Foo",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": metav1.NamespaceDefault,
+				},
+			},
+		}
+	}
+	newCurrentCRD := func(checkpoint string) *apiextensionsv1.CustomResourceDefinition {
+		crd := &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: "foo",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: "Foo", ListKind: "FooList"},
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{Name: "v1beta1", Storage: true, Served: true},
+					{Name: "v1alpha1", Served: true},
+				},
+			},
+			Status: apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: []string{"v1beta1", "v1alpha1"}},
+		}
+		if checkpoint != "" {
+			crd.Annotations = map[string]string{clusterctlv1.CRDMigrationCheckpointAnnotation: checkpoint}
+		}
+		return crd
+	}
+	newCRD := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "foo",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: "Foo", ListKind: "FooList"},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1", Storage: true, Served: true},
+				{Name: "v1beta1", Served: true},
+			},
+		},
+	}
+
+	tests := []struct {
+		name                string
+		currentCRD          *apiextensionsv1.CustomResourceDefinition
+		concurrency         int
+		wantMigratedObjects int
+		wantUpdated         []string
+	}{
+		{
+			name:                "Migrate all the CRs",
+			currentCRD:          newCurrentCRD(""),
+			concurrency:         1,
+			wantMigratedObjects: 0,
+			wantUpdated:         []string{"cr1", "cr2", "cr3", "cr4"},
+		},
+		{
+			name:                "Migrate all the CRs concurrently",
+			currentCRD:          newCurrentCRD(""),
+			concurrency:         3,
+			wantMigratedObjects: 0,
+			wantUpdated:         []string{"cr1", "cr2", "cr3", "cr4"},
+		},
+		{
+			name:                "Resume migration from the checkpoint",
+			currentCRD:          newCurrentCRD("v1beta1:default/cr2"),
+			concurrency:         2,
+			wantMigratedObjects: 2,
+			wantUpdated:         []string{"cr3", "cr4"},
+		},
+		{
+			name:                "Ignore checkpoint of a migration to another storage version",
+			currentCRD:          newCurrentCRD("v1alpha1:default/cr2"),
+			concurrency:         1,
+			wantMigratedObjects: 0,
+			wantUpdated:         []string{"cr1", "cr2", "cr3", "cr4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			crs := []unstructured.Unstructured{newCR("cr1"), newCR("cr2"), newCR("cr3"), newCR("cr4")}
+			objs := []client.Object{tt.currentCRD}
+			for i := range crs {
+				objs = append(objs, &crs[i])
+			}
+
+			c, err := test.NewFakeProxy().WithObjs(objs...).NewClient(context.Background())
+			g.Expect(err).ToNot(HaveOccurred())
+			countingClient := newUpgradeCountingClient(c)
+
+			u := &unstructured.Unstructured{}
+			g.Expect(scheme.Scheme.Convert(newCRD, u, nil)).To(Succeed())
+			u.SetKind("CustomResourceDefinition")
+
+			m := NewCRDMigrator(countingClient, WithCRDMigrationConcurrency(tt.concurrency))
+
+			// Plan reports the CRs to be migrated without changing them.
+			migrations, err := m.Plan(context.Background(), []unstructured.Unstructured{*u})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(migrations).To(Equal([]CRDMigration{{
+				CRD:                    "foo",
+				Kind:                   "Foo",
+				StorageVersion:         "v1beta1",
+				StoredVersionsToDelete: []string{"v1alpha1"},
+				Objects:                len(crs),
+				MigratedObjects:        tt.wantMigratedObjects,
+			}}))
+			g.Expect(countingClient.count).To(BeEmpty())
+
+			// Run migrates only the CRs not already migrated.
+			g.Expect(m.Run(context.Background(), []unstructured.Unstructured{*u})).To(Succeed())
+			g.Expect(countingClient.count).To(HaveKeyWithValue("foo/v1beta1, Kind=Foo", len(tt.wantUpdated)))
+			for _, name := range tt.wantUpdated {
+				g.Expect(countingClient.count).To(HaveKeyWithValue(name, 1))
+			}
+
+			// Check storage versions has been cleaned up, and the checkpoint removed.
+			currentCRD := &apiextensionsv1.CustomResourceDefinition{}
+			g.Expect(c.Get(context.Background(), client.ObjectKeyFromObject(newCRD), currentCRD)).To(Succeed())
+			g.Expect(currentCRD.Status.StoredVersions).To(Equal([]string{"v1beta1"}))
+			g.Expect(currentCRD.Annotations).ToNot(HaveKey(clusterctlv1.CRDMigrationCheckpointAnnotation))
+		})
+	}
+}
+
 type UpgradeCountingClient struct {
+	lock  *sync.Mutex
 	count map[string]int
 	client.Client
 }
 
 func newUpgradeCountingClient(inner client.Client) UpgradeCountingClient {
 	return UpgradeCountingClient{
+		lock:   &sync.Mutex{},
 		count:  map[string]int{},
 		Client: inner,
 	}
 }
 
 func (u UpgradeCountingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	u.lock.Lock()
 	u.count[obj.GetObjectKind().GroupVersionKind().String()]++
+	u.count[obj.GetName()]++
+	u.lock.Unlock()
 	return u.Client.Update(ctx, obj, opts...)
 }
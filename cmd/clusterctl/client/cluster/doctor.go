@@ -0,0 +1,539 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/util/annotations"
+)
+
+// DoctorCheckStatus defines the outcome of a check run by the DoctorClient.
+type DoctorCheckStatus string
+
+const (
+	// DoctorCheckPassed documents a check which did not detect any issue.
+	DoctorCheckPassed DoctorCheckStatus = "Pass"
+
+	// DoctorCheckWarning documents a check which detected an issue that does not prevent the management cluster from
+	// working now, but that might lead to failures if not addressed.
+	DoctorCheckWarning DoctorCheckStatus = "Warn"
+
+	// DoctorCheckFailed documents a check which detected an issue that prevents the management cluster from working properly.
+	DoctorCheckFailed DoctorCheckStatus = "Fail"
+)
+
+// Names of the checks run by the DoctorClient.
+const (
+	doctorProvidersCheck         = "Providers"
+	doctorContractsCheck         = "Contracts"
+	doctorWebhooksCheck          = "Webhooks"
+	doctorCertificatesCheck      = "Certificates"
+	doctorCRDStoredVersionsCheck = "CRD stored versions"
+	doctorPausedClustersCheck    = "Paused Clusters"
+)
+
+// defaultCertificateExpiryThreshold is the time before expiry after which certificates are reported with a warning.
+const defaultCertificateExpiryThreshold = 30 * 24 * time.Hour
+
+// DoctorCheckResult describes a finding of a check run by the DoctorClient.
+type DoctorCheckResult struct {
+	// Check is the name of the check, e.g. Providers.
+	Check string
+
+	// Status is the outcome of the check.
+	Status DoctorCheckStatus
+
+	// Message is a human-readable description of the finding.
+	Message string
+
+	// Remediation is a human-readable hint about how to fix the issue; it is empty for checks that passed.
+	Remediation string
+}
+
+// DoctorReport is the result of diagnosing a management cluster.
+type DoctorReport struct {
+	Results []DoctorCheckResult
+}
+
+// HasFailures returns true if at least one of the checks failed.
+func (r *DoctorReport) HasFailures() bool {
+	for _, result := range r.Results {
+		if result.Status == DoctorCheckFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// DiagnoseOptions carries the options supported by DoctorClient.Diagnose.
+type DiagnoseOptions struct {
+	// CertificateExpiryThreshold is the time before expiry after which certificates are reported with a warning.
+	// If not set, it defaults to 30 days.
+	CertificateExpiryThreshold time.Duration
+}
+
+// DoctorClient has methods to diagnose the health of a management cluster.
+type DoctorClient interface {
+	// Diagnose checks the management cluster against what clusterctl knows about it, e.g. the provider inventory,
+	// the provider metadata, the provider webhooks and CRDs, and reports the outcome of each check.
+	Diagnose(ctx context.Context, options DiagnoseOptions) (*DoctorReport, error)
+}
+
+// doctorClient implements DoctorClient.
+type doctorClient struct {
+	proxy             Proxy
+	providerInventory InventoryClient
+	providerUpgrader  *providerUpgrader
+}
+
+// ensure doctorClient implements DoctorClient.
+var _ DoctorClient = &doctorClient{}
+
+func newDoctorClient(configClient config.Client, proxy Proxy, repositoryClientFactory RepositoryClientFactory, providerInventory InventoryClient, providerComponents ComponentsClient) *doctorClient {
+	return &doctorClient{
+		proxy:             proxy,
+		providerInventory: providerInventory,
+		providerUpgrader:  newProviderUpgrader(configClient, proxy, repositoryClientFactory, providerInventory, providerComponents),
+	}
+}
+
+func (d *doctorClient) Diagnose(ctx context.Context, options DiagnoseOptions) (*DoctorReport, error) {
+	if options.CertificateExpiryThreshold == 0 {
+		options.CertificateExpiryThreshold = defaultCertificateExpiryThreshold
+	}
+
+	c, err := d.proxy.NewClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	providerList, err := d.providerInventory.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	providers := providerList.Items
+
+	checks := []struct {
+		name string
+		run  func() ([]DoctorCheckResult, error)
+	}{
+		{name: doctorProvidersCheck, run: func() ([]DoctorCheckResult, error) { return d.checkProviders(ctx, c, providers) }},
+		{name: doctorContractsCheck, run: func() ([]DoctorCheckResult, error) { return d.checkContracts(ctx, providers) }},
+		{name: doctorWebhooksCheck, run: func() ([]DoctorCheckResult, error) {
+			return d.checkWebhooks(ctx, c, options.CertificateExpiryThreshold, time.Now())
+		}},
+		{name: doctorCertificatesCheck, run: func() ([]DoctorCheckResult, error) {
+			return d.checkCertificates(ctx, c, providers, options.CertificateExpiryThreshold, time.Now())
+		}},
+		{name: doctorCRDStoredVersionsCheck, run: func() ([]DoctorCheckResult, error) { return d.checkCRDStoredVersions(ctx, c) }},
+		{name: doctorPausedClustersCheck, run: func() ([]DoctorCheckResult, error) { return d.checkPausedClusters(ctx, c) }},
+	}
+
+	report := &DoctorReport{}
+	for _, check := range checks {
+		results, err := check.run()
+		if err != nil {
+			// A check that can't complete is reported as failed, so the other checks can still run.
+			results = []DoctorCheckResult{{
+				Check:       check.name,
+				Status:      DoctorCheckFailed,
+				Message:     fmt.Sprintf("Unable to complete the check: %v", err),
+				Remediation: "Verify the management cluster is reachable and that the current user has read access to the objects being checked.",
+			}}
+		}
+		report.Results = append(report.Results, results...)
+	}
+	return report, nil
+}
+
+// checkProviders checks that each provider in the inventory has its controllers deployed and available, and that
+// there are no provider controllers missing from the inventory.
+func (d *doctorClient) checkProviders(ctx context.Context, c client.Client, providers []clusterctlv1.Provider) ([]DoctorCheckResult, error) {
+	results := []DoctorCheckResult{}
+	inventoryLabels := map[string]bool{}
+	for _, provider := range providers {
+		inventoryLabels[provider.ManifestLabel()] = true
+
+		deployments := &appsv1.DeploymentList{}
+		if err := c.List(ctx, deployments, client.InNamespace(provider.Namespace), client.MatchingLabels{clusterv1.ProviderNameLabel: provider.ManifestLabel()}); err != nil {
+			return nil, errors.Wrapf(err, "failed to list Deployments for provider %s", provider.InstanceName())
+		}
+		if len(deployments.Items) == 0 {
+			results = append(results, DoctorCheckResult{
+				Check:       doctorProvidersCheck,
+				Status:      DoctorCheckFailed,
+				Message:     fmt.Sprintf("Provider %s %s is in the inventory, but no controller Deployment exists in namespace %s", provider.InstanceName(), provider.Version, provider.Namespace),
+				Remediation: "Re-install the provider with 'clusterctl init', or run 'clusterctl delete' to remove it from the inventory if it is no longer used.",
+			})
+			continue
+		}
+
+		for _, deployment := range deployments.Items {
+			desired := ptr.Deref(deployment.Spec.Replicas, 1)
+			if deployment.Status.AvailableReplicas < desired {
+				results = append(results, DoctorCheckResult{
+					Check:       doctorProvidersCheck,
+					Status:      DoctorCheckFailed,
+					Message:     fmt.Sprintf("Controller Deployment %s/%s of provider %s has %d of %d replicas available", deployment.Namespace, deployment.Name, provider.InstanceName(), deployment.Status.AvailableReplicas, desired),
+					Remediation: fmt.Sprintf("Inspect the controller with 'kubectl describe deployment -n %s %s' and its logs.", deployment.Namespace, deployment.Name),
+				})
+			}
+		}
+	}
+
+	// Look for provider controllers installed by clusterctl which are not tracked by the inventory.
+	deployments := &appsv1.DeploymentList{}
+	if err := c.List(ctx, deployments, client.HasLabels{clusterctlv1.ClusterctlLabel, clusterv1.ProviderNameLabel}); err != nil {
+		return nil, errors.Wrap(err, "failed to list provider Deployments")
+	}
+	for _, deployment := range deployments.Items {
+		if inventoryLabels[deployment.Labels[clusterv1.ProviderNameLabel]] {
+			continue
+		}
+		results = append(results, DoctorCheckResult{
+			Check:       doctorProvidersCheck,
+			Status:      DoctorCheckWarning,
+			Message:     fmt.Sprintf("Controller Deployment %s/%s of provider %s is not tracked in the inventory", deployment.Namespace, deployment.Name, deployment.Labels[clusterv1.ProviderNameLabel]),
+			Remediation: "Re-install the provider with 'clusterctl init' so it is added to the inventory, or delete the Deployment if it is a leftover.",
+		})
+	}
+
+	if len(results) == 0 {
+		results = append(results, DoctorCheckResult{
+			Check:   doctorProvidersCheck,
+			Status:  DoctorCheckPassed,
+			Message: fmt.Sprintf("All the %d providers in the inventory have their controllers available", len(providers)),
+		})
+	}
+	return results, nil
+}
+
+// checkContracts checks that the version of each provider implements the Cluster API contract supported by
+// this version of clusterctl, according to the provider metadata.
+func (d *doctorClient) checkContracts(ctx context.Context, providers []clusterctlv1.Provider) ([]DoctorCheckResult, error) {
+	results := []DoctorCheckResult{}
+	for _, provider := range providers {
+		upgradeInfo, err := d.providerUpgrader.getUpgradeInfo(ctx, provider)
+		if err != nil {
+			results = append(results, DoctorCheckResult{
+				Check:       doctorContractsCheck,
+				Status:      DoctorCheckWarning,
+				Message:     fmt.Sprintf("Unable to read the metadata of provider %s: %v", provider.InstanceName(), err),
+				Remediation: "Verify the provider repository is reachable and listed in the clusterctl configuration.",
+			})
+			continue
+		}
+		if upgradeInfo.currentContract != clusterv1.GroupVersion.Version {
+			results = append(results, DoctorCheckResult{
+				Check:       doctorContractsCheck,
+				Status:      DoctorCheckFailed,
+				Message:     fmt.Sprintf("Provider %s %s implements the %s contract, but the management cluster uses the %s contract", provider.InstanceName(), provider.Version, upgradeInfo.currentContract, clusterv1.GroupVersion.Version),
+				Remediation: "Run 'clusterctl upgrade plan' and upgrade the provider to a version implementing the current contract.",
+			})
+		}
+	}
+
+	if len(results) == 0 {
+		results = append(results, DoctorCheckResult{
+			Check:   doctorContractsCheck,
+			Status:  DoctorCheckPassed,
+			Message: fmt.Sprintf("All the providers implement the %s contract", clusterv1.GroupVersion.Version),
+		})
+	}
+	return results, nil
+}
+
+// doctorWebhook describes a webhook to be checked.
+type doctorWebhook struct {
+	owner        string
+	clientConfig admissionregistrationv1.WebhookClientConfig
+}
+
+// checkWebhooks checks that the admission and conversion webhooks of the providers are pointing to an existing
+// Service, and that their CA bundle is valid and not close to expiry.
+func (d *doctorClient) checkWebhooks(ctx context.Context, c client.Client, expiryThreshold time.Duration, now time.Time) ([]DoctorCheckResult, error) {
+	webhooks := []doctorWebhook{}
+
+	validatingWebhooks := &admissionregistrationv1.ValidatingWebhookConfigurationList{}
+	if err := c.List(ctx, validatingWebhooks, client.HasLabels{clusterctlv1.ClusterctlLabel}); err != nil {
+		return nil, errors.Wrap(err, "failed to list ValidatingWebhookConfigurations")
+	}
+	for _, configuration := range validatingWebhooks.Items {
+		for _, webhook := range configuration.Webhooks {
+			webhooks = append(webhooks, doctorWebhook{owner: fmt.Sprintf("ValidatingWebhookConfiguration %s (webhook %s)", configuration.Name, webhook.Name), clientConfig: webhook.ClientConfig})
+		}
+	}
+
+	mutatingWebhooks := &admissionregistrationv1.MutatingWebhookConfigurationList{}
+	if err := c.List(ctx, mutatingWebhooks, client.HasLabels{clusterctlv1.ClusterctlLabel}); err != nil {
+		return nil, errors.Wrap(err, "failed to list MutatingWebhookConfigurations")
+	}
+	for _, configuration := range mutatingWebhooks.Items {
+		for _, webhook := range configuration.Webhooks {
+			webhooks = append(webhooks, doctorWebhook{owner: fmt.Sprintf("MutatingWebhookConfiguration %s (webhook %s)", configuration.Name, webhook.Name), clientConfig: webhook.ClientConfig})
+		}
+	}
+
+	crds := &apiextensionsv1.CustomResourceDefinitionList{}
+	if err := c.List(ctx, crds, client.HasLabels{clusterctlv1.ClusterctlLabel}); err != nil {
+		return nil, errors.Wrap(err, "failed to list CustomResourceDefinitions")
+	}
+	for _, crd := range crds.Items {
+		if crd.Spec.Conversion == nil || crd.Spec.Conversion.Webhook == nil || crd.Spec.Conversion.Webhook.ClientConfig == nil {
+			continue
+		}
+		clientConfig := admissionregistrationv1.WebhookClientConfig{
+			URL:      crd.Spec.Conversion.Webhook.ClientConfig.URL,
+			CABundle: crd.Spec.Conversion.Webhook.ClientConfig.CABundle,
+		}
+		if service := crd.Spec.Conversion.Webhook.ClientConfig.Service; service != nil {
+			clientConfig.Service = &admissionregistrationv1.ServiceReference{Namespace: service.Namespace, Name: service.Name}
+		}
+		webhooks = append(webhooks, doctorWebhook{owner: fmt.Sprintf("CustomResourceDefinition %s (conversion webhook)", crd.Name), clientConfig: clientConfig})
+	}
+
+	results := []DoctorCheckResult{}
+	for _, webhook := range webhooks {
+		if service := webhook.clientConfig.Service; service != nil {
+			if err := c.Get(ctx, client.ObjectKey{Namespace: service.Namespace, Name: service.Name}, &corev1.Service{}); err != nil {
+				if !apierrors.IsNotFound(err) {
+					return nil, errors.Wrapf(err, "failed to get Service %s/%s", service.Namespace, service.Name)
+				}
+				results = append(results, DoctorCheckResult{
+					Check:       doctorWebhooksCheck,
+					Status:      DoctorCheckFailed,
+					Message:     fmt.Sprintf("%s points to Service %s/%s, which does not exist", webhook.owner, service.Namespace, service.Name),
+					Remediation: "Re-install the provider the webhook belongs to with 'clusterctl init', or delete the webhook if the provider is no longer installed.",
+				})
+				continue
+			}
+		}
+
+		if result := checkCABundle(webhook.owner, webhook.clientConfig.CABundle, expiryThreshold, now); result != nil {
+			results = append(results, *result)
+		}
+	}
+
+	if len(results) == 0 {
+		results = append(results, DoctorCheckResult{
+			Check:   doctorWebhooksCheck,
+			Status:  DoctorCheckPassed,
+			Message: fmt.Sprintf("All the %d webhooks have a valid CA bundle and an existing Service", len(webhooks)),
+		})
+	}
+	return results, nil
+}
+
+// checkCABundle checks that a CA bundle contains valid certificates, and that none of them is expired or close to expiry.
+func checkCABundle(owner string, caBundle []byte, expiryThreshold time.Duration, now time.Time) *DoctorCheckResult {
+	const remediation = "Verify cert-manager is running and that its cainjector is injecting the CA bundle; see 'kubectl -n cert-manager logs deployment/cert-manager-cainjector'."
+
+	certificates := []*x509.Certificate{}
+	rest := caBundle
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return &DoctorCheckResult{Check: doctorWebhooksCheck, Status: DoctorCheckFailed, Message: fmt.Sprintf("%s has an invalid CA bundle: %v", owner, err), Remediation: remediation}
+		}
+		certificates = append(certificates, certificate)
+	}
+	if len(certificates) == 0 {
+		return &DoctorCheckResult{Check: doctorWebhooksCheck, Status: DoctorCheckFailed, Message: fmt.Sprintf("%s does not have a CA bundle", owner), Remediation: remediation}
+	}
+
+	for _, certificate := range certificates {
+		if now.After(certificate.NotAfter) {
+			return &DoctorCheckResult{Check: doctorWebhooksCheck, Status: DoctorCheckFailed, Message: fmt.Sprintf("%s has a CA bundle which expired on %s", owner, certificate.NotAfter.Format(time.RFC3339)), Remediation: remediation}
+		}
+		if certificate.NotAfter.Sub(now) < expiryThreshold {
+			return &DoctorCheckResult{Check: doctorWebhooksCheck, Status: DoctorCheckWarning, Message: fmt.Sprintf("%s has a CA bundle expiring in %s", owner, duration.HumanDuration(certificate.NotAfter.Sub(now))), Remediation: remediation}
+		}
+	}
+	return nil
+}
+
+// checkCertificates checks that the cert-manager Certificates in the provider namespaces are ready and not close to expiry.
+func (d *doctorClient) checkCertificates(ctx context.Context, c client.Client, providers []clusterctlv1.Provider, expiryThreshold time.Duration, now time.Time) ([]DoctorCheckResult, error) {
+	results := []DoctorCheckResult{}
+	namespaces := map[string]bool{}
+	count := 0
+	for _, provider := range providers {
+		if namespaces[provider.Namespace] {
+			continue
+		}
+		namespaces[provider.Namespace] = true
+
+		certificates := &unstructured.UnstructuredList{}
+		certificates.SetGroupVersionKind(schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "CertificateList"})
+		if err := c.List(ctx, certificates, client.InNamespace(provider.Namespace)); err != nil {
+			if meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
+				return []DoctorCheckResult{{
+					Check:       doctorCertificatesCheck,
+					Status:      DoctorCheckFailed,
+					Message:     "cert-manager is not installed",
+					Remediation: "Run 'clusterctl init' to install cert-manager.",
+				}}, nil
+			}
+			return nil, errors.Wrapf(err, "failed to list Certificates in namespace %s", provider.Namespace)
+		}
+
+		for _, certificate := range certificates.Items {
+			count++
+			name := fmt.Sprintf("Certificate %s/%s", certificate.GetNamespace(), certificate.GetName())
+			remediation := fmt.Sprintf("Inspect the Certificate with 'kubectl describe certificate -n %s %s' and verify cert-manager is running.", certificate.GetNamespace(), certificate.GetName())
+
+			if !isCertificateReady(certificate) {
+				results = append(results, DoctorCheckResult{Check: doctorCertificatesCheck, Status: DoctorCheckFailed, Message: fmt.Sprintf("%s is not ready", name), Remediation: remediation})
+				continue
+			}
+
+			notAfterValue, _, _ := unstructured.NestedString(certificate.Object, "status", "notAfter")
+			notAfter, err := time.Parse(time.RFC3339, notAfterValue)
+			if err != nil {
+				continue
+			}
+			switch {
+			case now.After(notAfter):
+				results = append(results, DoctorCheckResult{Check: doctorCertificatesCheck, Status: DoctorCheckFailed, Message: fmt.Sprintf("%s expired on %s", name, notAfterValue), Remediation: remediation})
+			case notAfter.Sub(now) < expiryThreshold:
+				// cert-manager renews certificates well before expiry, so a certificate close to expiry means renewal is not working.
+				results = append(results, DoctorCheckResult{Check: doctorCertificatesCheck, Status: DoctorCheckWarning, Message: fmt.Sprintf("%s expires in %s", name, duration.HumanDuration(notAfter.Sub(now))), Remediation: remediation})
+			}
+		}
+	}
+
+	if len(results) == 0 {
+		results = append(results, DoctorCheckResult{
+			Check:   doctorCertificatesCheck,
+			Status:  DoctorCheckPassed,
+			Message: fmt.Sprintf("All the %d cert-manager Certificates in the provider namespaces are ready", count),
+		})
+	}
+	return results, nil
+}
+
+func isCertificateReady(certificate unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(certificate.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condition["type"] == "Ready" {
+			return condition["status"] == string(corev1.ConditionTrue)
+		}
+	}
+	return false
+}
+
+// checkCRDStoredVersions checks that the objects of the provider CRDs are all stored in the current storage version.
+func (d *doctorClient) checkCRDStoredVersions(ctx context.Context, c client.Client) ([]DoctorCheckResult, error) {
+	crds := &apiextensionsv1.CustomResourceDefinitionList{}
+	if err := c.List(ctx, crds, client.HasLabels{clusterctlv1.ClusterctlLabel}); err != nil {
+		return nil, errors.Wrap(err, "failed to list CustomResourceDefinitions")
+	}
+
+	results := []DoctorCheckResult{}
+	for i := range crds.Items {
+		crd := &crds.Items[i]
+		storageVersion, err := storageVersionForCRD(crd)
+		if err != nil {
+			return nil, err
+		}
+		if versions := storedVersionsToMigrate(crd, storageVersion); versions.Len() > 0 {
+			results = append(results, DoctorCheckResult{
+				Check:       doctorCRDStoredVersionsCheck,
+				Status:      DoctorCheckWarning,
+				Message:     fmt.Sprintf("CustomResourceDefinition %s still lists %s as stored versions, while the storage version is %s", crd.Name, strings.Join(sets.List(versions), ", "), storageVersion),
+				Remediation: "Run 'clusterctl upgrade apply' for the provider owning the CRD to migrate the stored objects; upgrading to a version dropping the old API versions fails until the migration is completed.",
+			})
+		}
+	}
+
+	if len(results) == 0 {
+		results = append(results, DoctorCheckResult{
+			Check:   doctorCRDStoredVersionsCheck,
+			Status:  DoctorCheckPassed,
+			Message: fmt.Sprintf("All the objects of the %d provider CRDs are stored in the current storage version", len(crds.Items)),
+		})
+	}
+	return results, nil
+}
+
+// checkPausedClusters checks that no Cluster is paused, e.g. because of an interrupted clusterctl move.
+func (d *doctorClient) checkPausedClusters(ctx context.Context, c client.Client) ([]DoctorCheckResult, error) {
+	clusters := &clusterv1.ClusterList{}
+	if err := c.List(ctx, clusters); err != nil {
+		return nil, errors.Wrap(err, "failed to list Clusters")
+	}
+
+	results := []DoctorCheckResult{}
+	for i := range clusters.Items {
+		cluster := &clusters.Items[i]
+		if !cluster.Spec.Paused && !annotations.HasPaused(cluster) {
+			continue
+		}
+		// NOTE: clusterctl move pauses Clusters by setting spec.paused, so an interrupted move leaves them paused.
+		remediation := fmt.Sprintf("If the Cluster is not paused on purpose, e.g. it was left paused by an interrupted 'clusterctl move', resume it with: kubectl patch cluster -n %s %s --type merge -p '{\"spec\":{\"paused\":false}}'", cluster.Namespace, cluster.Name)
+		if annotations.HasPaused(cluster) {
+			remediation = fmt.Sprintf("If the Cluster is not paused on purpose, resume it with: kubectl annotate cluster -n %s %s %s-", cluster.Namespace, cluster.Name, clusterv1.PausedAnnotation)
+		}
+		results = append(results, DoctorCheckResult{
+			Check:       doctorPausedClustersCheck,
+			Status:      DoctorCheckWarning,
+			Message:     fmt.Sprintf("Cluster %s/%s is paused, so its objects are not reconciled", cluster.Namespace, cluster.Name),
+			Remediation: remediation,
+		})
+	}
+
+	if len(results) == 0 {
+		results = append(results, DoctorCheckResult{
+			Check:   doctorPausedClustersCheck,
+			Status:  DoctorCheckPassed,
+			Message: fmt.Sprintf("None of the %d Clusters is paused", len(clusters.Items)),
+		})
+	}
+	return results, nil
+}
@@ -0,0 +1,424 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_doctorClient_checkProviders(t *testing.T) {
+	provider := fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v1.0.0", "infra-system")
+	deployment := func(namespace, name, providerLabel string, available int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: appsv1.SchemeGroupVersion.String(),
+				Kind:       "Deployment",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+				Labels: map[string]string{
+					clusterctlv1.ClusterctlLabel: "",
+					clusterv1.ProviderNameLabel:  providerLabel,
+				},
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To[int32](1),
+			},
+			Status: appsv1.DeploymentStatus{
+				AvailableReplicas: available,
+			},
+		}
+	}
+
+	tests := []struct {
+		name string
+		objs []client.Object
+		want []DoctorCheckStatus
+	}{
+		{
+			name: "pass if the provider controller is available",
+			objs: []client.Object{deployment("infra-system", "infra-controller", "infrastructure-infra", 1)},
+			want: []DoctorCheckStatus{DoctorCheckPassed},
+		},
+		{
+			name: "fail if the provider controller is missing",
+			want: []DoctorCheckStatus{DoctorCheckFailed},
+		},
+		{
+			name: "fail if the provider controller is not available",
+			objs: []client.Object{deployment("infra-system", "infra-controller", "infrastructure-infra", 0)},
+			want: []DoctorCheckStatus{DoctorCheckFailed},
+		},
+		{
+			name: "warn if a provider controller is not in the inventory",
+			objs: []client.Object{
+				deployment("infra-system", "infra-controller", "infrastructure-infra", 1),
+				deployment("other-system", "other-controller", "infrastructure-other", 1),
+			},
+			want: []DoctorCheckStatus{DoctorCheckWarning},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ctx := context.Background()
+			c, err := test.NewFakeProxy().WithObjs(tt.objs...).NewClient(ctx)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			d := &doctorClient{}
+			got, err := d.checkProviders(ctx, c, []clusterctlv1.Provider{provider})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(doctorCheckStatuses(got)).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_doctorClient_checkContracts(t *testing.T) {
+	tests := []struct {
+		name     string
+		contract string
+		want     []DoctorCheckStatus
+	}{
+		{
+			name:     "pass if the provider implements the current contract",
+			contract: test.CurrentCAPIContract,
+			want:     []DoctorCheckStatus{DoctorCheckPassed},
+		},
+		{
+			name:     "fail if the provider implements another contract",
+			contract: test.PreviousCAPIContractNotSupported,
+			want:     []DoctorCheckStatus{DoctorCheckFailed},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ctx := context.Background()
+			reader := test.NewFakeReader().WithProvider("infra", clusterctlv1.InfrastructureProviderType, "https://somewhere.com")
+			repo := repository.NewMemoryRepository().
+				WithVersions("v1.0.0").
+				WithMetadata("v1.0.0", &clusterctlv1.Metadata{
+					ReleaseSeries: []clusterctlv1.ReleaseSeries{
+						{Major: 1, Minor: 0, Contract: tt.contract},
+					},
+				})
+			configClient, _ := config.New(ctx, "", config.InjectReader(reader))
+
+			d := &doctorClient{
+				providerUpgrader: &providerUpgrader{
+					configClient: configClient,
+					repositoryClientFactory: func(ctx context.Context, provider config.Provider, configClient config.Client, _ ...repository.Option) (repository.Client, error) {
+						return repository.New(ctx, provider, configClient, repository.InjectRepository(repo))
+					},
+				},
+			}
+			got, err := d.checkContracts(ctx, []clusterctlv1.Provider{fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v1.0.0", "infra-system")})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(doctorCheckStatuses(got)).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_doctorClient_checkWebhooks(t *testing.T) {
+	now := time.Now()
+	webhookConfiguration := &admissionregistrationv1.ValidatingWebhookConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: admissionregistrationv1.SchemeGroupVersion.String(),
+			Kind:       "ValidatingWebhookConfiguration",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "infra-validating-webhook-configuration",
+			Labels: map[string]string{
+				clusterctlv1.ClusterctlLabel: "",
+			},
+		},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{
+			{
+				Name: "validation.foo.infrastructure.cluster.x-k8s.io",
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service:  &admissionregistrationv1.ServiceReference{Namespace: "infra-system", Name: "infra-webhook-service"},
+					CABundle: generateCertificatePEM(t, now.Add(365*24*time.Hour)),
+				},
+			},
+		},
+	}
+
+	g := NewWithT(t)
+	ctx := context.Background()
+	c, err := test.NewFakeProxy().WithObjs(webhookConfiguration).NewClient(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	d := &doctorClient{}
+	got, err := d.checkWebhooks(ctx, c, defaultCertificateExpiryThreshold, now)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(HaveLen(1))
+	g.Expect(got[0].Status).To(Equal(DoctorCheckFailed))
+	g.Expect(got[0].Message).To(ContainSubstring("infra-system/infra-webhook-service, which does not exist"))
+}
+
+func Test_checkCABundle(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		caBundle []byte
+		want     *DoctorCheckStatus
+	}{
+		{
+			name:     "pass for a valid CA bundle",
+			caBundle: generateCertificatePEM(t, now.Add(365*24*time.Hour)),
+			want:     nil,
+		},
+		{
+			name:     "fail for an empty CA bundle",
+			caBundle: nil,
+			want:     ptr.To(DoctorCheckFailed),
+		},
+		{
+			name:     "fail for an expired CA bundle",
+			caBundle: generateCertificatePEM(t, now.Add(-time.Hour)),
+			want:     ptr.To(DoctorCheckFailed),
+		},
+		{
+			name:     "warn for a CA bundle close to expiry",
+			caBundle: generateCertificatePEM(t, now.Add(24*time.Hour)),
+			want:     ptr.To(DoctorCheckWarning),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got := checkCABundle("webhook", tt.caBundle, defaultCertificateExpiryThreshold, now)
+			if tt.want == nil {
+				g.Expect(got).To(BeNil())
+				return
+			}
+			g.Expect(got).ToNot(BeNil())
+			g.Expect(got.Status).To(Equal(*tt.want))
+		})
+	}
+}
+
+func Test_doctorClient_checkCertificates(t *testing.T) {
+	now := time.Now()
+	certificate := func(name string, ready bool, notAfter time.Time) *unstructured.Unstructured {
+		status := "False"
+		if ready {
+			status = "True"
+		}
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "cert-manager.io/v1",
+				"kind":       "Certificate",
+				"metadata": map[string]interface{}{
+					"namespace": "infra-system",
+					"name":      name,
+				},
+				"status": map[string]interface{}{
+					"notAfter": notAfter.Format(time.RFC3339),
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Ready", "status": status},
+					},
+				},
+			},
+		}
+	}
+
+	g := NewWithT(t)
+	ctx := context.Background()
+	c, err := test.NewFakeProxy().WithObjs(
+		certificate("valid", true, now.Add(60*24*time.Hour)),
+		certificate("not-ready", false, now.Add(60*24*time.Hour)),
+		certificate("expiring", true, now.Add(24*time.Hour)),
+	).NewClient(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	d := &doctorClient{}
+	got, err := d.checkCertificates(ctx, c, []clusterctlv1.Provider{fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v1.0.0", "infra-system")}, defaultCertificateExpiryThreshold, now)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(ConsistOf(
+		HaveField("Message", "Certificate infra-system/expiring expires in 23h"),
+		HaveField("Message", "Certificate infra-system/not-ready is not ready"),
+	))
+}
+
+func Test_doctorClient_checkCRDStoredVersions(t *testing.T) {
+	crd := func(storedVersions ...string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: apiextensionsv1.SchemeGroupVersion.String(),
+				Kind:       "CustomResourceDefinition",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foos.infrastructure.cluster.x-k8s.io",
+				Labels: map[string]string{
+					clusterctlv1.ClusterctlLabel: "",
+				},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{Name: "v1beta1", Served: true},
+					{Name: "v1beta2", Served: true, Storage: true},
+				},
+			},
+			Status: apiextensionsv1.CustomResourceDefinitionStatus{
+				StoredVersions: storedVersions,
+			},
+		}
+	}
+
+	tests := []struct {
+		name string
+		crd  *apiextensionsv1.CustomResourceDefinition
+		want []DoctorCheckStatus
+	}{
+		{
+			name: "pass if only the storage version is stored",
+			crd:  crd("v1beta2"),
+			want: []DoctorCheckStatus{DoctorCheckPassed},
+		},
+		{
+			name: "warn if other versions are stored",
+			crd:  crd("v1beta1", "v1beta2"),
+			want: []DoctorCheckStatus{DoctorCheckWarning},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ctx := context.Background()
+			c, err := test.NewFakeProxy().WithObjs(tt.crd).NewClient(ctx)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			d := &doctorClient{}
+			got, err := d.checkCRDStoredVersions(ctx, c)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(doctorCheckStatuses(got)).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_doctorClient_checkPausedClusters(t *testing.T) {
+	cluster := func(name string, paused bool, annotations map[string]string) *clusterv1.Cluster {
+		return &clusterv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: clusterv1.GroupVersion.String(),
+				Kind:       "Cluster",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "default",
+				Name:        name,
+				Annotations: annotations,
+			},
+			Spec: clusterv1.ClusterSpec{
+				Paused: paused,
+			},
+		}
+	}
+
+	tests := []struct {
+		name string
+		objs []client.Object
+		want []DoctorCheckStatus
+	}{
+		{
+			name: "pass if no Cluster is paused",
+			objs: []client.Object{cluster("c1", false, nil)},
+			want: []DoctorCheckStatus{DoctorCheckPassed},
+		},
+		{
+			name: "warn for each paused Cluster",
+			objs: []client.Object{
+				cluster("c1", true, nil),
+				cluster("c2", false, map[string]string{clusterv1.PausedAnnotation: ""}),
+				cluster("c3", false, nil),
+			},
+			want: []DoctorCheckStatus{DoctorCheckWarning, DoctorCheckWarning},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ctx := context.Background()
+			c, err := test.NewFakeProxy().WithObjs(tt.objs...).NewClient(ctx)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			d := &doctorClient{}
+			got, err := d.checkPausedClusters(ctx, c)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(doctorCheckStatuses(got)).To(Equal(tt.want))
+		})
+	}
+}
+
+func doctorCheckStatuses(results []DoctorCheckResult) []DoctorCheckStatus {
+	statuses := []DoctorCheckStatus{}
+	for _, result := range results {
+		statuses = append(statuses, result.Status)
+	}
+	return statuses
+}
+
+// generateCertificatePEM returns a PEM encoded self-signed certificate expiring at notAfter.
+func generateCertificatePEM(t *testing.T, notAfter time.Time) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
@@ -317,6 +317,10 @@ func (c *fakeComponents) Variables() []string {
 	panic("not implemented")
 }
 
+func (c *fakeComponents) VariableMap() map[string]*string {
+	panic("not implemented")
+}
+
 func (c *fakeComponents) Images() []string {
 	panic("not implemented")
 }
@@ -28,6 +28,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -35,6 +36,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/version"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -48,16 +50,51 @@ import (
 // ResourceMutatorFunc holds the type for mutators to be applied on resources during a move operation.
 type ResourceMutatorFunc func(u *unstructured.Unstructured) error
 
+// ClusterSelector restricts a move operation to a subset of the Clusters existing in the namespace being moved.
+// An empty ClusterSelector selects all the Clusters.
+type ClusterSelector struct {
+	// Name is the name of the Cluster to be moved.
+	Name string `json:"name,omitempty"`
+
+	// LabelSelector is a label query over the Clusters to be moved, e.g. "env=dev".
+	LabelSelector string `json:"labelSelector,omitempty"`
+}
+
+// IsEmpty returns true if the ClusterSelector selects all the Clusters.
+func (s ClusterSelector) IsEmpty() bool {
+	return s.Name == "" && s.LabelSelector == ""
+}
+
 // ObjectMover defines methods for moving Cluster API objects to another management cluster.
 type ObjectMover interface {
 	// Move moves all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a target management cluster.
-	Move(ctx context.Context, namespace string, toCluster Client, dryRun bool, mutators ...ResourceMutatorFunc) error
+	// If the selector is not empty, only the selected Clusters are moved, together with the objects they depend on.
+	Move(ctx context.Context, namespace string, selector ClusterSelector, toCluster Client, dryRun bool, mutators ...ResourceMutatorFunc) error
 
 	// ToDirectory writes all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a target directory.
-	ToDirectory(ctx context.Context, namespace string, directory string) error
+	// If the selector is not empty, only the selected Clusters are written, together with the objects they depend on.
+	// If the options are not empty, files are encrypted and/or listed in a signed manifest.
+	ToDirectory(ctx context.Context, namespace string, selector ClusterSelector, directory string, options DirectoryOptions) error
 
 	// FromDirectory reads all the Cluster API objects existing in a configured directory to a target management cluster.
-	FromDirectory(ctx context.Context, toCluster Client, directory string) error
+	// If the directory contains a manifest, all the files are verified against it before restoring any object.
+	FromDirectory(ctx context.Context, toCluster Client, directory string, options DirectoryOptions) error
+
+	// MoveWithCheckpoint behaves like Move, but it persists the progress of the operation into a checkpoint file, so
+	// a move interrupted midway can be completed with ResumeMove or undone with RollbackMove.
+	MoveWithCheckpoint(ctx context.Context, namespace string, selector ClusterSelector, toCluster Client, checkpointFile string, mutators ...ResourceMutatorFunc) error
+
+	// ResumeMove completes a move operation interrupted midway, using the progress recorded in the checkpoint file.
+	ResumeMove(ctx context.Context, toCluster Client, checkpointFile string, mutators ...ResourceMutatorFunc) error
+
+	// RollbackMove undoes a move operation interrupted midway, using the progress recorded in the checkpoint file.
+	// Rollback is possible only if no object has been deleted from the source management cluster yet.
+	RollbackMove(ctx context.Context, toCluster Client, checkpointFile string) error
+
+	// Plan returns the plan for moving all the Cluster API objects existing in a namespace (or from all the namespaces if empty)
+	// to a target management cluster, without performing any actual action. If toCluster is nil, checks on the target
+	// management cluster are skipped.
+	Plan(ctx context.Context, namespace string, selector ClusterSelector, toCluster Client) (*MovePlan, error)
 }
 
 // objectMover implements the ObjectMover interface.
@@ -65,12 +102,19 @@ type objectMover struct {
 	fromProxy             Proxy
 	fromProviderInventory InventoryClient
 	dryRun                bool
+
+	// checkpoint records the progress of the move operation, if any.
+	checkpoint *moveCheckpoint
+
+	// backup reads and writes the files of a move to/from directory operation protected by encryption
+	// and/or by a signed manifest, if any.
+	backup *moveBackup
 }
 
 // ensure objectMover implements the ObjectMover interface.
 var _ ObjectMover = &objectMover{}
 
-func (o *objectMover) Move(ctx context.Context, namespace string, toCluster Client, dryRun bool, mutators ...ResourceMutatorFunc) error {
+func (o *objectMover) Move(ctx context.Context, namespace string, selector ClusterSelector, toCluster Client, dryRun bool, mutators ...ResourceMutatorFunc) error {
 	log := logf.Log
 	log.Info("Performing move...")
 	o.dryRun = dryRun
@@ -87,7 +131,7 @@ func (o *objectMover) Move(ctx context.Context, namespace string, toCluster Clie
 		}
 	}
 
-	objectGraph, err := o.getObjectGraph(ctx, namespace)
+	objectGraph, err := o.getObjectGraph(ctx, namespace, selector)
 	if err != nil {
 		return errors.Wrap(err, "failed to get object graph")
 	}
@@ -101,11 +145,63 @@ func (o *objectMover) Move(ctx context.Context, namespace string, toCluster Clie
 	return o.move(ctx, objectGraph, proxy, mutators...)
 }
 
-func (o *objectMover) ToDirectory(ctx context.Context, namespace string, directory string) error {
+func (o *objectMover) MoveWithCheckpoint(ctx context.Context, namespace string, selector ClusterSelector, toCluster Client, checkpointFile string, mutators ...ResourceMutatorFunc) error {
+	checkpoint, err := newMoveCheckpoint(checkpointFile, namespace, selector)
+	if err != nil {
+		return err
+	}
+	o.checkpoint = checkpoint
+
+	return o.Move(ctx, namespace, selector, toCluster, false, mutators...)
+}
+
+func (o *objectMover) ResumeMove(ctx context.Context, toCluster Client, checkpointFile string, mutators ...ResourceMutatorFunc) error {
+	log := logf.Log
+	log.Info("Resuming move...", "checkpoint", checkpointFile)
+
+	checkpoint, err := readMoveCheckpoint(checkpointFile)
+	if err != nil {
+		return err
+	}
+	if checkpoint.Phase != moveCheckpointInProgress {
+		return errors.Errorf("cannot resume the move operation recorded in %q: the operation is %s", checkpointFile, checkpoint.Phase)
+	}
+	o.checkpoint = checkpoint
+
+	// Discovery is executed again on the source management cluster, and objects already created in the target
+	// management cluster / deleted from the source management cluster are skipped by using the info in the checkpoint.
+	return o.Move(ctx, checkpoint.Namespace, ptr.Deref(checkpoint.ClusterSelector, ClusterSelector{}), toCluster, false, mutators...)
+}
+
+func (o *objectMover) RollbackMove(ctx context.Context, toCluster Client, checkpointFile string) error {
+	log := logf.Log
+	log.Info("Rolling back move...", "checkpoint", checkpointFile)
+
+	checkpoint, err := readMoveCheckpoint(checkpointFile)
+	if err != nil {
+		return err
+	}
+	if checkpoint.Phase != moveCheckpointInProgress {
+		return errors.Errorf("cannot rollback the move operation recorded in %q: the operation is %s", checkpointFile, checkpoint.Phase)
+	}
+	o.checkpoint = checkpoint
+
+	return o.rollback(ctx, toCluster.Proxy())
+}
+
+func (o *objectMover) ToDirectory(ctx context.Context, namespace string, selector ClusterSelector, directory string, options DirectoryOptions) error {
 	log := logf.Log
 	log.Info("Moving to directory...")
 
-	objectGraph, err := o.getObjectGraph(ctx, namespace)
+	if !options.IsEmpty() {
+		backup, err := newMoveBackup(options)
+		if err != nil {
+			return err
+		}
+		o.backup = backup
+	}
+
+	objectGraph, err := o.getObjectGraph(ctx, namespace, selector)
 	if err != nil {
 		return errors.Wrap(err, "failed to get object graph")
 	}
@@ -113,15 +209,22 @@ func (o *objectMover) ToDirectory(ctx context.Context, namespace string, directo
 	return o.toDirectory(ctx, objectGraph, directory)
 }
 
-func (o *objectMover) FromDirectory(ctx context.Context, toCluster Client, directory string) error {
+func (o *objectMover) FromDirectory(ctx context.Context, toCluster Client, directory string, options DirectoryOptions) error {
 	log := logf.Log
 	log.Info("Moving from directory...")
 
+	// Reads the manifest, if any, so all the files can be verified before restoring any object.
+	backup, err := readMoveBackup(directory, options)
+	if err != nil {
+		return err
+	}
+	o.backup = backup
+
 	// Build an empty object graph used for the fromDirectory sequence not tied to a specific namespace
 	objectGraph := newObjectGraph(o.fromProxy, o.fromProviderInventory)
 
 	// Gets all the types defined by the CRDs installed by clusterctl plus the ConfigMap/Secret core types.
-	err := objectGraph.getDiscoveryTypes(ctx)
+	err = objectGraph.getDiscoveryTypes(ctx)
 	if err != nil {
 		return errors.Wrap(err, "failed to retrieve discovery types")
 	}
@@ -157,6 +260,27 @@ func (o *objectMover) filesToObjs(dir string) ([]unstructured.Unstructured, erro
 	log := logf.Log
 	log.Info(fmt.Sprintf("Restoring files from %s", dir))
 
+	rawYAMLs, err := o.readFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	processedYAMLs := yaml.JoinYaml(rawYAMLs...)
+
+	objs, err := yaml.ToUnstructured(processedYAMLs)
+	if err != nil {
+		return nil, err
+	}
+
+	return objs, nil
+}
+
+// readFiles reads all the files in a directory; if the directory contains a manifest, files are verified against it.
+func (o *objectMover) readFiles(dir string) ([][]byte, error) {
+	if o.backup != nil {
+		return o.backup.readFiles(dir)
+	}
+
 	files, err := os.ReadDir(dir)
 	if err != nil {
 		return nil, err
@@ -173,18 +297,32 @@ func (o *objectMover) filesToObjs(dir string) ([]unstructured.Unstructured, erro
 
 		rawYAMLs = append(rawYAMLs, byObj)
 	}
+	return rawYAMLs, nil
+}
 
-	processedYAMLs := yaml.JoinYaml(rawYAMLs...)
-
-	objs, err := yaml.ToUnstructured(processedYAMLs)
+func (o *objectMover) getObjectGraph(ctx context.Context, namespace string, selector ClusterSelector) (*objectGraph, error) {
+	objectGraph, err := o.discoverObjectGraph(ctx, namespace, selector)
 	if err != nil {
 		return nil, err
 	}
 
-	return objs, nil
+	// Checks if Cluster API has already completed the provisioning of the infrastructure for the objects involved in the move/toDirectory operation.
+	// This is required because if the infrastructure is provisioned, then we can reasonably assume that the objects we are moving/backing up are
+	// not currently waiting for long-running reconciliation loops, and so we can safely rely on the pause field on the Cluster object
+	// for blocking any further object reconciliation on the source objects.
+	if err := o.checkProvisioningCompleted(ctx, objectGraph); err != nil {
+		return nil, errors.Wrap(err, "failed to check for provisioned infrastructure")
+	}
+
+	// Check whether nodes are not included in GVK considered for move
+	objectGraph.checkVirtualNode()
+
+	return objectGraph, nil
 }
 
-func (o *objectMover) getObjectGraph(ctx context.Context, namespace string) (*objectGraph, error) {
+// discoverObjectGraph returns the object graph for the objects existing in a namespace (or in all the namespaces if empty);
+// if the selector is not empty, the graph is restricted to the selected Clusters and to the objects they depend on.
+func (o *objectMover) discoverObjectGraph(ctx context.Context, namespace string, selector ClusterSelector) (*objectGraph, error) {
 	objectGraph := newObjectGraph(o.fromProxy, o.fromProviderInventory)
 
 	// Gets all the types defined by the CRDs installed by clusterctl plus the ConfigMap/Secret core types.
@@ -200,20 +338,52 @@ func (o *objectMover) getObjectGraph(ctx context.Context, namespace string) (*ob
 		return nil, errors.Wrap(err, "failed to discover the object graph")
 	}
 
-	// Checks if Cluster API has already completed the provisioning of the infrastructure for the objects involved in the move/toDirectory operation.
-	// This is required because if the infrastructure is provisioned, then we can reasonably assume that the objects we are moving/backing up are
-	// not currently waiting for long-running reconciliation loops, and so we can safely rely on the pause field on the Cluster object
-	// for blocking any further object reconciliation on the source objects.
-	if err := o.checkProvisioningCompleted(ctx, objectGraph); err != nil {
-		return nil, errors.Wrap(err, "failed to check for provisioned infrastructure")
+	if err := o.selectClusters(ctx, objectGraph, selector); err != nil {
+		return nil, errors.Wrap(err, "failed to select the Clusters to move")
 	}
 
-	// Check whether nodes are not included in GVK considered for move
-	objectGraph.checkVirtualNode()
-
 	return objectGraph, nil
 }
 
+// selectClusters restricts the object graph to the Clusters matching the selector and to the objects they depend on.
+func (o *objectMover) selectClusters(ctx context.Context, graph *objectGraph, selector ClusterSelector) error {
+	if selector.IsEmpty() {
+		return nil
+	}
+
+	labelSelector, err := labels.Parse(selector.LabelSelector)
+	if err != nil {
+		return errors.Wrapf(err, "invalid Cluster label selector %q", selector.LabelSelector)
+	}
+
+	clusters := []*node{}
+	readClusterBackoff := newReadBackoff()
+	for _, cluster := range graph.getClusters() {
+		if selector.Name != "" && cluster.identity.Name != selector.Name {
+			continue
+		}
+
+		clusterObj := &clusterv1.Cluster{}
+		if err := retryWithExponentialBackoff(ctx, readClusterBackoff, func(ctx context.Context) error {
+			return getClusterObj(ctx, o.fromProxy, cluster, clusterObj)
+		}); err != nil {
+			return err
+		}
+		if !labelSelector.Matches(labels.Set(clusterObj.GetLabels())) {
+			continue
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	// NOTE: when resuming a move, the selected Clusters might be already deleted from the source management cluster.
+	if len(clusters) == 0 && !o.checkpoint.hasPausedClusters() {
+		return errors.New("no Clusters matching the selector found")
+	}
+
+	graph.selectClusters(clusters)
+	return nil
+}
+
 func newObjectMover(fromProxy Proxy, fromProviderInventory InventoryClient) *objectMover {
 	return &objectMover{
 		fromProxy:             fromProxy,
@@ -324,6 +494,14 @@ func (o *objectMover) move(ctx context.Context, graph *objectGraph, toProxy Prox
 	clusterClasses := graph.getClusterClasses()
 	log.Info("Moving Cluster API objects", "ClusterClasses", len(clusterClasses))
 
+	// ClusterClasses still in use by Clusters not being moved are kept in the source management cluster.
+	sourceClusterClasses := []*node{}
+	for _, clusterClass := range clusterClasses {
+		if clusterClass.shouldNotDelete {
+			sourceClusterClasses = append(sourceClusterClasses, clusterClass)
+		}
+	}
+
 	// Sets the pause field on the Cluster object in the source management cluster, so the controllers stop reconciling it.
 	log.V(1).Info("Pausing the source cluster")
 	if err := setClusterPause(ctx, o.fromProxy, clusters, true, o.dryRun); err != nil {
@@ -335,6 +513,13 @@ func (o *objectMover) move(ctx context.Context, graph *objectGraph, toProxy Prox
 		return errors.Wrap(err, "error pausing ClusterClasses")
 	}
 
+	// Records the paused Clusters and ClusterClasses in the checkpoint, if any; when resuming a move, this also
+	// returns the Clusters and ClusterClasses already deleted from the source cluster by the interrupted move.
+	clusters, clusterClasses, err := o.checkpoint.recordPaused(clusters, clusterClasses)
+	if err != nil {
+		return err
+	}
+
 	log.Info("Waiting for all resources to be ready to move")
 	// exponential backoff configuration which returns durations for a total time of ~2m.
 	// Example: 0, 5s, 8s, 11s, 17s, 26s, 38s, 57s, 86s, 128s
@@ -379,6 +564,12 @@ func (o *objectMover) move(ctx context.Context, graph *objectGraph, toProxy Prox
 		}
 	}
 
+	// Resume the ClusterClasses kept in the source management cluster, so the controllers start reconciling them again.
+	log.V(1).Info("Resuming the source ClusterClasses not deleted")
+	if err := setClusterClassPause(ctx, o.fromProxy, sourceClusterClasses, false, o.dryRun); err != nil {
+		return errors.Wrap(err, "error resuming ClusterClasses")
+	}
+
 	// Resume the ClusterClasses in the target management cluster, so the controllers start reconciling it.
 	log.V(1).Info("Resuming the target ClusterClasses")
 	if err := setClusterClassPause(ctx, toProxy, clusterClasses, false, o.dryRun, mutators...); err != nil {
@@ -387,7 +578,84 @@ func (o *objectMover) move(ctx context.Context, graph *objectGraph, toProxy Prox
 
 	// Reset the pause field on the Cluster object in the target management cluster, so the controllers start reconciling it.
 	log.V(1).Info("Resuming the target cluster")
-	return setClusterPause(ctx, toProxy, clusters, false, o.dryRun, mutators...)
+	if err := setClusterPause(ctx, toProxy, clusters, false, o.dryRun, mutators...); err != nil {
+		return err
+	}
+
+	return o.checkpoint.setPhase(moveCheckpointCompleted)
+}
+
+// rollback undoes a move operation interrupted midway by deleting the objects already created in the target management
+// cluster and restoring the objects updated in the target management cluster, in reverse creation order, and then
+// resuming the Clusters and ClusterClasses in the source management cluster.
+func (o *objectMover) rollback(ctx context.Context, toProxy Proxy) error {
+	log := logf.Log
+
+	// If objects have been already deleted from the source cluster, the only safe way forward is to complete the move.
+	if o.checkpoint.hasDeletedObjects() {
+		return errors.New("cannot rollback the move operation: some objects have been already deleted from the source cluster, resume the move operation instead")
+	}
+
+	log.Info("Deleting objects from the target cluster")
+	deleteTargetObjectBackoff := newWriteBackoff()
+	for i := len(o.checkpoint.Objects) - 1; i >= 0; i-- {
+		object := &o.checkpoint.Objects[i]
+
+		// Don't touch cluster-wide objects and objects below a hierarchy that starts with a global object,
+		// because they might be in use by other objects in the target cluster.
+		if object.Global {
+			continue
+		}
+
+		switch {
+		case object.Existing:
+			// Objects existing in the target cluster before the move are never deleted, but restored if updated by move.
+			if object.Original != nil {
+				log.V(1).Info("Restoring", object.Target.Kind, object.Target.Name, "Namespace", object.Target.Namespace)
+				if err := retryWithExponentialBackoff(ctx, deleteTargetObjectBackoff, func(ctx context.Context) error {
+					return restoreObject(ctx, toProxy, object.Original)
+				}); err != nil {
+					return err
+				}
+			}
+		case (object.Created || object.Pending) && object.Target.UID == "":
+			// The move has been interrupted before the target cluster confirmed the creation of the object, so it is
+			// not possible to tell if an object with the same name has been created by move.
+			log.Info("Object might have been created in the target cluster by the interrupted move, please check and delete it manually if required", object.Target.Kind, object.Target.Name, "Namespace", object.Target.Namespace)
+		case object.Created || object.Pending:
+			// Objects created by move are deleted only if they still have the UID recorded in the checkpoint.
+			log.V(1).Info("Deleting", object.Target.Kind, object.Target.Name, "Namespace", object.Target.Namespace)
+			if err := retryWithExponentialBackoff(ctx, deleteTargetObjectBackoff, func(ctx context.Context) error {
+				return deleteObject(ctx, toProxy, object.Target)
+			}); err != nil {
+				return err
+			}
+		default:
+			continue
+		}
+
+		object.Created = false
+		object.Pending = false
+		object.Existing = false
+		object.Original = nil
+		if err := o.checkpoint.save(); err != nil {
+			return err
+		}
+	}
+
+	// Resume the ClusterClasses in the source management cluster, so the controllers start reconciling it again.
+	log.V(1).Info("Resuming the source ClusterClasses")
+	if err := setClusterClassPause(ctx, o.fromProxy, referencesToNodes(o.checkpoint.ClusterClasses), false, o.dryRun); err != nil {
+		return errors.Wrap(err, "error resuming ClusterClasses")
+	}
+
+	// Reset the pause field on the Cluster object in the source management cluster, so the controllers start reconciling it again.
+	log.V(1).Info("Resuming the source cluster")
+	if err := setClusterPause(ctx, o.fromProxy, referencesToNodes(o.checkpoint.Clusters), false, o.dryRun); err != nil {
+		return err
+	}
+
+	return o.checkpoint.setPhase(moveCheckpointRolledBack)
 }
 
 func (o *objectMover) toDirectory(ctx context.Context, graph *objectGraph, directory string) error {
@@ -425,6 +693,14 @@ func (o *objectMover) toDirectory(ctx context.Context, graph *objectGraph, direc
 		}
 	}
 
+	// Write the manifest listing all the files with their digests, if required.
+	if o.backup != nil {
+		log.Info("Writing the manifest of the saved files")
+		if err := o.backup.writeManifest(directory); err != nil {
+			return errors.Wrap(err, "failed to write the manifest")
+		}
+	}
+
 	// Resume the ClusterClasses in the target management cluster, so the controllers start reconciling it.
 	log.V(1).Info("Resuming the target ClusterClasses")
 	if err := setClusterClassPause(ctx, o.fromProxy, clusterClasses, false, o.dryRun); err != nil {
@@ -860,6 +1136,7 @@ func (o *objectMover) ensureNamespace(ctx context.Context, toProxy Proxy, namesp
 
 // createGroup creates all the Kubernetes objects into the target management cluster corresponding to the object graph nodes in a moveGroup.
 func (o *objectMover) createGroup(ctx context.Context, group moveGroup, toProxy Proxy, mutators ...ResourceMutatorFunc) error {
+	log := logf.Log
 	createTargetObjectBackoff := newWriteBackoff()
 	errList := []error{}
 
@@ -867,6 +1144,12 @@ func (o *objectMover) createGroup(ctx context.Context, group moveGroup, toProxy
 	// Nb. This prevents us from making repetitive (and expensive) calls in listing all namespaces to ensure a namespace exists before creating a resource.
 	existingNamespaces := sets.New[string]()
 	for _, nodeToCreate := range group {
+		// Skip objects already created in the target cluster by a previous, interrupted move.
+		if o.checkpoint.restoreCreated(nodeToCreate) {
+			log.V(5).Info("Object already created by a previous move, skipping", nodeToCreate.identity.Kind, nodeToCreate.identity.Name, "Namespace", nodeToCreate.identity.Namespace)
+			continue
+		}
+
 		// Creates the Kubernetes object corresponding to the nodeToCreate.
 		// Nb. The operation is wrapped in a retry loop to make move more resilient to unexpected conditions.
 		err := retryWithExponentialBackoff(ctx, createTargetObjectBackoff, func(ctx context.Context) error {
@@ -987,35 +1270,60 @@ func (o *objectMover) createTargetObject(ctx context.Context, nodeToCreate *node
 		existingNamespaces.Insert(obj.GetNamespace())
 	}
 	oldManagedFields := obj.GetManagedFields()
+
+	// Records the intent to create the object in the checkpoint, if any, before creating it; this allows to find
+	// the object in the target cluster on resume or rollback, even if the move is interrupted right after creating it.
+	if o.checkpoint.isPending(nodeToCreate) {
+		log.V(5).Info("Object might have been created by a previous move, adopting it if it exists", nodeToCreate.identity.Kind, nodeToCreate.identity.Name, "Namespace", nodeToCreate.identity.Namespace)
+	}
+	if err := o.checkpoint.recordCreating(nodeToCreate, obj); err != nil {
+		return err
+	}
+
+	// createdByMove tracks if the object has been created by this move, or by a previous, interrupted move.
+	createdByMove := true
 	if err := cTo.Create(ctx, obj); err != nil {
 		if !apierrors.IsAlreadyExists(err) {
 			return errors.Wrapf(err, "error creating %q %s/%s",
 				obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
 		}
 
+		// Retrieve the UID and the resource version of the existing object, so the UID can be used to rebuild
+		// the ownerReferences of the objects still to be created, and the resource version can be used for the update.
+		existingTargetObj := &unstructured.Unstructured{}
+		existingTargetObj.SetAPIVersion(obj.GetAPIVersion())
+		existingTargetObj.SetKind(obj.GetKind())
+		if err := cTo.Get(ctx, client.ObjectKeyFromObject(obj), existingTargetObj); err != nil {
+			return errors.Wrapf(err, "error reading resource for %q %s/%s",
+				existingTargetObj.GroupVersionKind(), existingTargetObj.GetNamespace(), existingTargetObj.GetName())
+		}
+		obj.SetUID(existingTargetObj.GetUID())
+
+		// If the object has not been created by a previous, interrupted move, it existed in the target cluster before the move;
+		// record it with its original content before updating it, so it gets restored instead of deleted on rollback.
+		createdByMove = o.checkpoint.isCreatedByMove(nodeToCreate, existingTargetObj.GetUID())
+		updated := !nodeToCreate.isGlobal && !nodeToCreate.isGlobalHierarchy
+		if !createdByMove {
+			if err := o.checkpoint.recordExisting(nodeToCreate, existingTargetObj, updated); err != nil {
+				return err
+			}
+		}
+
 		// If the object already exists, try to update it if it is node a global object / something belonging to a global object hierarchy (e.g. a secrets owned by a global identity object).
-		if nodeToCreate.isGlobal || nodeToCreate.isGlobalHierarchy {
+		if !updated {
 			log.V(5).Info("Object already exists, skipping upgrade because it is global/it is owned by a global object", nodeToCreate.identity.Kind, nodeToCreate.identity.Name, "Namespace", nodeToCreate.identity.Namespace)
 		} else {
 			// Nb. This should not happen, but it is supported to make move more resilient to unexpected interrupt/restarts of the move process.
 			log.V(5).Info("Object already exists, updating", nodeToCreate.identity.Kind, nodeToCreate.identity.Name, "Namespace", nodeToCreate.identity.Namespace)
 
-			// Retrieve the UID and the resource version for the update.
-			existingTargetObj := &unstructured.Unstructured{}
-			existingTargetObj.SetAPIVersion(obj.GetAPIVersion())
-			existingTargetObj.SetKind(obj.GetKind())
-			if err := cTo.Get(ctx, client.ObjectKeyFromObject(obj), existingTargetObj); err != nil {
-				return errors.Wrapf(err, "error reading resource for %q %s/%s",
-					existingTargetObj.GroupVersionKind(), existingTargetObj.GetNamespace(), existingTargetObj.GetName())
-			}
-
-			obj.SetUID(existingTargetObj.GetUID())
 			obj.SetResourceVersion(existingTargetObj.GetResourceVersion())
 			if err := cTo.Update(ctx, obj); err != nil {
 				return errors.Wrapf(err, "error updating %q %s/%s",
 					obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
 			}
 		}
+	} else if err := o.checkpoint.recordCreateConfirmed(nodeToCreate, obj.GetUID()); err != nil {
+		return err
 	}
 
 	// Stores the newUID assigned to the newly created object.
@@ -1025,7 +1333,12 @@ func (o *objectMover) createTargetObject(ctx context.Context, nodeToCreate *node
 		return errors.Wrap(err, "error patching the managed fields")
 	}
 
-	return nil
+	if !createdByMove {
+		return nil
+	}
+
+	// Records the object has been created in the checkpoint, if any.
+	return o.checkpoint.recordCreated(nodeToCreate, obj)
 }
 
 func (o *objectMover) backupTargetObject(ctx context.Context, nodeToCreate *node, directory string) error {
@@ -1058,6 +1371,10 @@ func (o *objectMover) backupTargetObject(ctx context.Context, nodeToCreate *node
 	}
 
 	filenameObj := nodeToCreate.getFilename()
+	if o.backup != nil {
+		return o.backup.writeFile(directory, filenameObj, byObj)
+	}
+
 	objectFile := filepath.Join(directory, filenameObj)
 
 	// If file exists, then remove it to be written again
@@ -1201,49 +1518,109 @@ func (o *objectMover) deleteSourceObject(ctx context.Context, nodeToDelete *node
 		return nil
 	}
 
-	cFrom, err := o.fromProxy.NewClient(ctx)
+	if err := deleteObject(ctx, o.fromProxy, nodeToDelete.identity); err != nil {
+		return err
+	}
+
+	// Records the object has been deleted in the checkpoint, if any.
+	return o.checkpoint.recordDeleted(nodeToDelete)
+}
+
+// deleteObject deletes a Kubernetes object, taking care of removing all the finalizers so the objects gets immediately
+// deleted (force delete); the delete-for-move annotation is added to the object before deletion, so controllers
+// can skip any cleanup of the external resources.
+// If the reference has a UID, the object is deleted only if it still has the same UID, i.e. it is not deleted if
+// it has been replaced by another object with the same name.
+func deleteObject(ctx context.Context, proxy Proxy, ref corev1.ObjectReference) error {
+	log := logf.Log
+
+	c, err := proxy.NewClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	// Get the source object
-	sourceObj := &unstructured.Unstructured{}
-	sourceObj.SetAPIVersion(nodeToDelete.identity.APIVersion)
-	sourceObj.SetKind(nodeToDelete.identity.Kind)
-	sourceObjKey := client.ObjectKey{
-		Namespace: nodeToDelete.identity.Namespace,
-		Name:      nodeToDelete.identity.Name,
+	// Get the object
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(ref.APIVersion)
+	obj.SetKind(ref.Kind)
+	objKey := client.ObjectKey{
+		Namespace: ref.Namespace,
+		Name:      ref.Name,
 	}
 
-	if err := cFrom.Get(ctx, sourceObjKey, sourceObj); err != nil {
+	if err := c.Get(ctx, objKey, obj); err != nil {
 		if apierrors.IsNotFound(err) {
 			// If the object is already deleted, move on.
-			log.V(5).Info("Object already deleted, skipping delete for", nodeToDelete.identity.Kind, nodeToDelete.identity.Name, "Namespace", nodeToDelete.identity.Namespace)
+			log.V(5).Info("Object already deleted, skipping delete for", ref.Kind, ref.Name, "Namespace", ref.Namespace)
 			return nil
 		}
 		return errors.Wrapf(err, "error reading %q %s/%s",
-			sourceObj.GroupVersionKind(), sourceObj.GetNamespace(), sourceObj.GetName())
+			obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
 	}
 
-	if err := cFrom.Patch(ctx, sourceObj, addDeleteForMoveAnnotationPatch); err != nil {
+	deleteOpts := []client.DeleteOption{}
+	if ref.UID != "" {
+		if obj.GetUID() != ref.UID {
+			log.V(5).Info("Object replaced by another object with the same name, skipping delete for", ref.Kind, ref.Name, "Namespace", ref.Namespace)
+			return nil
+		}
+		deleteOpts = append(deleteOpts, client.Preconditions{UID: &ref.UID})
+	}
+
+	if err := c.Patch(ctx, obj, addDeleteForMoveAnnotationPatch); err != nil {
 		return errors.Wrapf(err, "error adding delete-for-move annotation from %q %s/%s",
-			sourceObj.GroupVersionKind(), sourceObj.GetNamespace(), sourceObj.GetName())
+			obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
 	}
 
-	if err := cFrom.Delete(ctx, sourceObj); err != nil {
+	if err := c.Delete(ctx, obj, deleteOpts...); err != nil {
 		return errors.Wrapf(err, "error deleting %q %s/%s",
-			sourceObj.GroupVersionKind(), sourceObj.GetNamespace(), sourceObj.GetName())
+			obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
 	}
 
-	if len(sourceObj.GetFinalizers()) > 0 {
-		if err := cFrom.Patch(ctx, sourceObj, removeFinalizersPatch); err != nil {
+	if len(obj.GetFinalizers()) > 0 {
+		if err := c.Patch(ctx, obj, removeFinalizersPatch); err != nil {
 			return errors.Wrapf(err, "error removing finalizers from %q %s/%s",
-				sourceObj.GroupVersionKind(), sourceObj.GetNamespace(), sourceObj.GetName())
+				obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
 		}
 	}
 	return nil
 }
 
+// restoreObject restores the content of an object existing in the target management cluster before the move,
+// if it still has the same UID.
+func restoreObject(ctx context.Context, proxy Proxy, original *unstructured.Unstructured) error {
+	log := logf.Log
+
+	c, err := proxy.NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	current := &unstructured.Unstructured{}
+	current.SetAPIVersion(original.GetAPIVersion())
+	current.SetKind(original.GetKind())
+	if err := c.Get(ctx, client.ObjectKeyFromObject(original), current); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.V(5).Info("Object deleted, skipping restore for", original.GetKind(), original.GetName(), "Namespace", original.GetNamespace())
+			return nil
+		}
+		return errors.Wrapf(err, "error reading %q %s/%s",
+			original.GroupVersionKind(), original.GetNamespace(), original.GetName())
+	}
+	if current.GetUID() != original.GetUID() {
+		log.V(5).Info("Object replaced by another object with the same name, skipping restore for", original.GetKind(), original.GetName(), "Namespace", original.GetNamespace())
+		return nil
+	}
+
+	obj := original.DeepCopy()
+	obj.SetResourceVersion(current.GetResourceVersion())
+	if err := c.Update(ctx, obj); err != nil {
+		return errors.Wrapf(err, "error restoring %q %s/%s",
+			obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
+	}
+	return nil
+}
+
 // checkTargetProviders checks that all the providers installed in the source cluster exists in the target cluster as well (with a version >= of the current version).
 func (o *objectMover) checkTargetProviders(ctx context.Context, toInventory InventoryClient) error {
 	if o.dryRun {
@@ -0,0 +1,439 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/scrypt"
+	"sigs.k8s.io/yaml"
+
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+)
+
+const (
+	// moveBackupManifestFile is the name of the file listing all the files of a backup with their digests.
+	moveBackupManifestFile = "clusterctl-move-manifest.yaml"
+
+	// moveBackupSignatureFile is the name of the file containing the signature of the manifest.
+	moveBackupSignatureFile = moveBackupManifestFile + ".sig"
+
+	// moveBackupEncryptedFileSuffix is the suffix added to the name of encrypted files.
+	moveBackupEncryptedFileSuffix = ".enc"
+
+	moveBackupEncryptionAlgorithm = "AES-256-GCM"
+	moveBackupKeyDerivation       = "scrypt"
+
+	// Parameters for deriving the encryption key from the passphrase, as recommended by the scrypt documentation.
+	moveBackupScryptN       = 1 << 15
+	moveBackupScryptR       = 8
+	moveBackupScryptP       = 1
+	moveBackupKeyLength     = 32
+	moveBackupSaltLength    = 16
+	moveBackupMinPassphrase = 8
+
+	// moveBackupMACKeyInfo is used to derive the key authenticating the manifest from the encryption key,
+	// so the same key is never used for both encrypting the files and authenticating the manifest.
+	moveBackupMACKeyInfo = "clusterctl-move-manifest-mac"
+)
+
+// DirectoryOptions defines how the files written by ToDirectory and read by FromDirectory are protected.
+// If empty, files are written as plain YAML without a manifest.
+type DirectoryOptions struct {
+	// Passphrase is used to derive the key for encrypting the files written by ToDirectory and for decrypting
+	// the files read by FromDirectory.
+	Passphrase []byte
+
+	// SigningKey is used by ToDirectory to sign the manifest listing all the files written with their digests.
+	SigningKey ed25519.PrivateKey
+
+	// VerificationKey is used by FromDirectory to verify the signature of the manifest before restoring any object;
+	// if set, a backup without a signed manifest is rejected.
+	VerificationKey ed25519.PublicKey
+
+	// SkipSignatureVerification allows FromDirectory to read a backup with a signed manifest without a VerificationKey;
+	// if not set, a backup with a signed manifest is rejected when no VerificationKey is set.
+	SkipSignatureVerification bool
+}
+
+// IsEmpty returns true if no protection is required for the files written by ToDirectory and read by FromDirectory.
+func (o DirectoryOptions) IsEmpty() bool {
+	return len(o.Passphrase) == 0 && o.SigningKey == nil && o.VerificationKey == nil
+}
+
+// moveBackupManifest lists all the files of a backup with their digests.
+type moveBackupManifest struct {
+	// Encryption describes how the files are encrypted, if they are.
+	Encryption *moveBackupEncryption `json:"encryption,omitempty"`
+
+	// Files is the list of files in the backup.
+	Files []moveBackupFile `json:"files"`
+
+	// MAC is the hex encoded HMAC-SHA256 of the manifest, computed with MAC empty using a key derived from the
+	// encryption key, so tampering with the manifest of an encrypted backup is detected even if it is not signed.
+	MAC string `json:"mac,omitempty"`
+}
+
+// moveBackupEncryption describes how the files of a backup are encrypted.
+type moveBackupEncryption struct {
+	// Algorithm is the algorithm used to encrypt the files.
+	Algorithm string `json:"algorithm"`
+
+	// KeyDerivation is the function used to derive the encryption key from the passphrase.
+	KeyDerivation string `json:"keyDerivation"`
+
+	// Salt is the random salt used to derive the encryption key from the passphrase.
+	Salt []byte `json:"salt"`
+
+	// N, R and P are the cost parameters used to derive the encryption key from the passphrase.
+	N int `json:"n"`
+	R int `json:"r"`
+	P int `json:"p"`
+}
+
+// moveBackupFile describes a file of a backup.
+type moveBackupFile struct {
+	// Name is the name of the file.
+	Name string `json:"name"`
+
+	// SHA256 is the hex encoded SHA256 digest of the file content, as written on disk.
+	SHA256 string `json:"sha256"`
+}
+
+// moveBackup reads and writes the files of a backup protected by encryption and/or by a signed manifest.
+type moveBackup struct {
+	options  DirectoryOptions
+	key      []byte
+	manifest moveBackupManifest
+}
+
+// newMoveBackup returns a moveBackup for writing a new backup.
+func newMoveBackup(options DirectoryOptions) (*moveBackup, error) {
+	b := &moveBackup{
+		options: options,
+	}
+
+	if len(options.Passphrase) > 0 {
+		salt := make([]byte, moveBackupSaltLength)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, errors.Wrap(err, "failed to generate the salt for the encryption key")
+		}
+		b.manifest.Encryption = &moveBackupEncryption{
+			Algorithm:     moveBackupEncryptionAlgorithm,
+			KeyDerivation: moveBackupKeyDerivation,
+			Salt:          salt,
+			N:             moveBackupScryptN,
+			R:             moveBackupScryptR,
+			P:             moveBackupScryptP,
+		}
+		if err := b.deriveKey(); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// readMoveBackup reads the manifest of a backup, verifying its signature if a verification key is set.
+// If the backup does not have a manifest, nil is returned and the backup is read as plain YAML files;
+// in this case an error is returned if decryption or signature verification are required.
+func readMoveBackup(directory string, options DirectoryOptions) (*moveBackup, error) {
+	log := logf.Log
+
+	manifestFile := filepath.Join(directory, moveBackupManifestFile)
+	content, err := os.ReadFile(manifestFile) //nolint:gosec
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, errors.Wrapf(err, "failed to read the manifest %q", manifestFile)
+		}
+		if !options.IsEmpty() {
+			return nil, errors.Errorf("the manifest %q does not exist; the directory does not contain an encrypted or signed backup", manifestFile)
+		}
+		return nil, nil
+	}
+
+	signatureFile := filepath.Join(directory, moveBackupSignatureFile)
+	signature, err := os.ReadFile(signatureFile) //nolint:gosec
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrapf(err, "failed to read the manifest signature %q", signatureFile)
+	}
+	switch {
+	case options.VerificationKey != nil:
+		if signature == nil {
+			return nil, errors.Errorf("the manifest %q is not signed", manifestFile)
+		}
+		decodedSignature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode the manifest signature %q", signatureFile)
+		}
+		if !ed25519.Verify(options.VerificationKey, content, decodedSignature) {
+			return nil, errors.Errorf("failed to verify the signature of the manifest %q", manifestFile)
+		}
+		log.Info("Verified the signature of the manifest", "manifest", manifestFile)
+	case signature != nil:
+		if !options.SkipSignatureVerification {
+			return nil, errors.Errorf("the manifest %q is signed, a verification key is required to verify it; explicitly skip the signature verification to read the backup without verifying it", manifestFile)
+		}
+		log.Info("Warning: the manifest is signed, but the signature is not verified because the signature verification is skipped", "manifest", manifestFile)
+	}
+
+	b := &moveBackup{
+		options: options,
+	}
+	if err := yaml.Unmarshal(content, &b.manifest); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the manifest %q", manifestFile)
+	}
+
+	if b.manifest.Encryption != nil {
+		if len(options.Passphrase) == 0 {
+			return nil, errors.New("the backup is encrypted, a passphrase is required to decrypt it")
+		}
+		if b.manifest.Encryption.Algorithm != moveBackupEncryptionAlgorithm || b.manifest.Encryption.KeyDerivation != moveBackupKeyDerivation {
+			return nil, errors.Errorf("unsupported encryption %s with key derivation %s", b.manifest.Encryption.Algorithm, b.manifest.Encryption.KeyDerivation)
+		}
+		if err := b.deriveKey(); err != nil {
+			return nil, err
+		}
+		if err := b.verifyManifestMAC(); err != nil {
+			return nil, errors.Wrapf(err, "failed to authenticate the manifest %q", manifestFile)
+		}
+	} else if len(options.Passphrase) > 0 {
+		return nil, errors.New("a passphrase is set, but the backup is not encrypted")
+	}
+	return b, nil
+}
+
+// deriveKey derives the encryption key from the passphrase, using the parameters recorded in the manifest.
+func (b *moveBackup) deriveKey() error {
+	if len(b.options.Passphrase) < moveBackupMinPassphrase {
+		return errors.Errorf("the passphrase must be at least %d characters long", moveBackupMinPassphrase)
+	}
+
+	// NOTE: the key derivation parameters are read from the manifest, which could be crafted to exhaust memory or CPU
+	// when deriving the key, so parameters more expensive than the ones used when writing a backup are rejected.
+	e := b.manifest.Encryption
+	if e.N <= 0 || e.R <= 0 || e.P <= 0 || e.N > moveBackupScryptN || e.R > moveBackupScryptR || e.P > moveBackupScryptP {
+		return errors.Errorf("unsupported key derivation parameters N=%d, r=%d, p=%d: expected values greater than zero and at most N=%d, r=%d, p=%d",
+			e.N, e.R, e.P, moveBackupScryptN, moveBackupScryptR, moveBackupScryptP)
+	}
+	key, err := scrypt.Key(b.options.Passphrase, e.Salt, e.N, e.R, e.P, moveBackupKeyLength)
+	if err != nil {
+		return errors.Wrap(err, "failed to derive the encryption key from the passphrase")
+	}
+	b.key = key
+	return nil
+}
+
+// writeFile writes a file into the backup, encrypting it if required, and records it into the manifest.
+func (b *moveBackup) writeFile(directory, filename string, content []byte) error {
+	if b.key != nil {
+		encrypted, err := b.encrypt(filename, content)
+		if err != nil {
+			return errors.Wrapf(err, "failed to encrypt %q", filename)
+		}
+		content = encrypted
+		filename += moveBackupEncryptedFileSuffix
+	}
+
+	if err := os.WriteFile(filepath.Join(directory, filename), content, 0600); err != nil {
+		return err
+	}
+
+	digest := sha256.Sum256(content)
+	file := moveBackupFile{
+		Name:   filename,
+		SHA256: hex.EncodeToString(digest[:]),
+	}
+	for i := range b.manifest.Files {
+		if b.manifest.Files[i].Name == filename {
+			b.manifest.Files[i] = file
+			return nil
+		}
+	}
+	b.manifest.Files = append(b.manifest.Files, file)
+	return nil
+}
+
+// writeManifest writes the manifest of the backup and, if a signing key is set, its signature.
+func (b *moveBackup) writeManifest(directory string) error {
+	sort.Slice(b.manifest.Files, func(i, j int) bool {
+		return b.manifest.Files[i].Name < b.manifest.Files[j].Name
+	})
+
+	if b.key != nil {
+		mac, err := b.manifestMAC()
+		if err != nil {
+			return err
+		}
+		b.manifest.MAC = hex.EncodeToString(mac)
+	}
+
+	content, err := yaml.Marshal(b.manifest)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the manifest")
+	}
+	if err := os.WriteFile(filepath.Join(directory, moveBackupManifestFile), content, 0600); err != nil {
+		return err
+	}
+
+	signatureFile := filepath.Join(directory, moveBackupSignatureFile)
+	if b.options.SigningKey == nil {
+		// Remove the signature of a previous backup into the same directory, if any, because it is not valid anymore.
+		if err := os.Remove(signatureFile); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	signature := ed25519.Sign(b.options.SigningKey, content)
+	return os.WriteFile(signatureFile, []byte(base64.StdEncoding.EncodeToString(signature)+"\n"), 0600)
+}
+
+// manifestMAC computes the MAC of the manifest, ignoring the MAC already recorded in it, if any.
+func (b *moveBackup) manifestMAC() ([]byte, error) {
+	manifest := b.manifest
+	manifest.MAC = ""
+	content, err := yaml.Marshal(manifest)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the manifest")
+	}
+
+	keyMAC := hmac.New(sha256.New, b.key)
+	keyMAC.Write([]byte(moveBackupMACKeyInfo))
+	mac := hmac.New(sha256.New, keyMAC.Sum(nil))
+	mac.Write(content)
+	return mac.Sum(nil), nil
+}
+
+// verifyManifestMAC verifies the MAC recorded in the manifest of an encrypted backup.
+func (b *moveBackup) verifyManifestMAC() error {
+	if b.manifest.MAC == "" {
+		return errors.New("the manifest does not have a MAC")
+	}
+	recorded, err := hex.DecodeString(b.manifest.MAC)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode the MAC")
+	}
+	expected, err := b.manifestMAC()
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal(recorded, expected) {
+		return errors.New("wrong passphrase or tampered manifest")
+	}
+	return nil
+}
+
+// readFiles reads all the files in the backup, verifying their digests against the manifest and decrypting them if required.
+// NOTE: all the files are verified before returning, so no object is restored from a backup that has been tampered with.
+func (b *moveBackup) readFiles(directory string) ([][]byte, error) {
+	files, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	inManifest := map[string]bool{}
+	for _, f := range b.manifest.Files {
+		inManifest[f.Name] = true
+	}
+	for _, f := range files {
+		if f.Name() == moveBackupManifestFile || f.Name() == moveBackupSignatureFile {
+			continue
+		}
+		if !inManifest[f.Name()] {
+			return nil, errors.Errorf("the file %q is not listed in the manifest", f.Name())
+		}
+	}
+
+	contents := make([][]byte, 0, len(b.manifest.Files))
+	for _, f := range b.manifest.Files {
+		if filepath.Base(f.Name) != f.Name {
+			return nil, errors.Errorf("invalid file name %q in the manifest", f.Name)
+		}
+
+		content, err := os.ReadFile(filepath.Join(directory, f.Name)) //nolint:gosec
+		if err != nil {
+			return nil, err
+		}
+
+		digest := sha256.Sum256(content)
+		if hex.EncodeToString(digest[:]) != f.SHA256 {
+			return nil, errors.Errorf("the digest of the file %q does not match the manifest", f.Name)
+		}
+
+		if b.manifest.Encryption != nil {
+			filename := strings.TrimSuffix(f.Name, moveBackupEncryptedFileSuffix)
+			if content, err = b.decrypt(filename, content); err != nil {
+				return nil, errors.Wrapf(err, "failed to decrypt %q", f.Name)
+			}
+		}
+		contents = append(contents, content)
+	}
+	return contents, nil
+}
+
+// encrypt encrypts the content of a file; the random nonce is prepended to the encrypted content,
+// and the file name is used as additional data so encrypted content can't be swapped between files.
+func (b *moveBackup) encrypt(filename string, content []byte) ([]byte, error) {
+	aead, err := b.aead()
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, content, []byte(filename)), nil
+}
+
+// decrypt decrypts the content of a file encrypted by encrypt.
+func (b *moveBackup) decrypt(filename string, content []byte) ([]byte, error) {
+	aead, err := b.aead()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(content) < aead.NonceSize() {
+		return nil, errors.New("encrypted content is too short")
+	}
+	nonce, encrypted := content[:aead.NonceSize()], content[aead.NonceSize():]
+	decrypted, err := aead.Open(nil, nonce, encrypted, []byte(filename))
+	if err != nil {
+		return nil, errors.New("wrong passphrase or corrupted content")
+	}
+	return decrypted, nil
+}
+
+func (b *moveBackup) aead() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(b.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,379 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+var (
+	testSigningKey      = ed25519.NewKeyFromSeed(bytes.Repeat([]byte{1}, ed25519.SeedSize))
+	testVerificationKey = testSigningKey.Public().(ed25519.PublicKey)
+	otherSigningKey     = ed25519.NewKeyFromSeed(bytes.Repeat([]byte{2}, ed25519.SeedSize))
+)
+
+func Test_moveBackup(t *testing.T) {
+	files := map[string][]byte{
+		"Cluster_ns1_foo.yaml":       []byte("kind: Cluster"),
+		"Secret_ns1_foo-ca.yaml":     []byte("kind: Secret"),
+		"Secret_ns1_foo-config.yaml": []byte("kind: Secret"),
+	}
+
+	tests := []struct {
+		name         string
+		writeOptions DirectoryOptions
+		readOptions  DirectoryOptions
+		tamper       func(dir string) error
+		wantErr      string
+	}{
+		{
+			name:         "signed backup",
+			writeOptions: DirectoryOptions{SigningKey: testSigningKey},
+			readOptions:  DirectoryOptions{VerificationKey: testVerificationKey},
+		},
+		{
+			name:         "encrypted backup",
+			writeOptions: DirectoryOptions{Passphrase: []byte("passphrase")},
+			readOptions:  DirectoryOptions{Passphrase: []byte("passphrase")},
+		},
+		{
+			name:         "encrypted and signed backup",
+			writeOptions: DirectoryOptions{Passphrase: []byte("passphrase"), SigningKey: testSigningKey},
+			readOptions:  DirectoryOptions{Passphrase: []byte("passphrase"), VerificationKey: testVerificationKey},
+		},
+		{
+			name:         "signed backup read skipping the signature verification",
+			writeOptions: DirectoryOptions{SigningKey: testSigningKey},
+			readOptions:  DirectoryOptions{SkipSignatureVerification: true},
+		},
+		{
+			name:         "fails if the manifest is signed and the verification key is not set",
+			writeOptions: DirectoryOptions{SigningKey: testSigningKey},
+			readOptions:  DirectoryOptions{},
+			wantErr:      "a verification key is required",
+		},
+		{
+			name:         "fails if the passphrase is set and the backup is not encrypted",
+			writeOptions: DirectoryOptions{SigningKey: testSigningKey},
+			readOptions:  DirectoryOptions{Passphrase: []byte("passphrase"), VerificationKey: testVerificationKey},
+			wantErr:      "the backup is not encrypted",
+		},
+		{
+			name:         "fails if the passphrase is too short",
+			writeOptions: DirectoryOptions{Passphrase: []byte("short")},
+			wantErr:      "the passphrase must be at least 8 characters long",
+		},
+		{
+			name:         "fails with a wrong passphrase",
+			writeOptions: DirectoryOptions{Passphrase: []byte("passphrase")},
+			readOptions:  DirectoryOptions{Passphrase: []byte("wrong passphrase")},
+			wantErr:      "wrong passphrase or tampered manifest",
+		},
+		{
+			name:         "fails if the backup is encrypted and the passphrase is not set",
+			writeOptions: DirectoryOptions{Passphrase: []byte("passphrase")},
+			readOptions:  DirectoryOptions{},
+			wantErr:      "a passphrase is required",
+		},
+		{
+			name:         "fails if the manifest is not signed",
+			writeOptions: DirectoryOptions{Passphrase: []byte("passphrase")},
+			readOptions:  DirectoryOptions{Passphrase: []byte("passphrase"), VerificationKey: testVerificationKey},
+			wantErr:      "is not signed",
+		},
+		{
+			name:         "fails if the manifest is signed with another key",
+			writeOptions: DirectoryOptions{SigningKey: otherSigningKey},
+			readOptions:  DirectoryOptions{VerificationKey: testVerificationKey},
+			wantErr:      "failed to verify the signature",
+		},
+		{
+			name:         "fails if the manifest has been tampered with",
+			writeOptions: DirectoryOptions{SigningKey: testSigningKey},
+			readOptions:  DirectoryOptions{VerificationKey: testVerificationKey},
+			tamper: func(dir string) error {
+				f, err := os.OpenFile(filepath.Join(dir, moveBackupManifestFile), os.O_APPEND|os.O_WRONLY, 0600)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				_, err = f.WriteString("- name: Secret_ns1_bar.yaml\n  sha256: 00\n")
+				return err
+			},
+			wantErr: "failed to verify the signature",
+		},
+		{
+			name:         "fails if a file has been tampered with",
+			writeOptions: DirectoryOptions{SigningKey: testSigningKey},
+			readOptions:  DirectoryOptions{VerificationKey: testVerificationKey},
+			tamper: func(dir string) error {
+				return os.WriteFile(filepath.Join(dir, "Secret_ns1_foo-ca.yaml"), []byte("kind: Secret\nfoo: bar"), 0600)
+			},
+			wantErr: "does not match the manifest",
+		},
+		{
+			name:         "fails if a file has been added",
+			writeOptions: DirectoryOptions{SigningKey: testSigningKey},
+			readOptions:  DirectoryOptions{VerificationKey: testVerificationKey},
+			tamper: func(dir string) error {
+				return os.WriteFile(filepath.Join(dir, "Secret_ns1_bar.yaml"), []byte("kind: Secret"), 0600)
+			},
+			wantErr: "is not listed in the manifest",
+		},
+		{
+			name:         "fails if a file has been removed",
+			writeOptions: DirectoryOptions{SigningKey: testSigningKey},
+			readOptions:  DirectoryOptions{VerificationKey: testVerificationKey},
+			tamper: func(dir string) error {
+				return os.Remove(filepath.Join(dir, "Secret_ns1_foo-ca.yaml"))
+			},
+			wantErr: "no such file or directory",
+		},
+		{
+			name:         "fails if the manifest requires expensive key derivation parameters",
+			writeOptions: DirectoryOptions{Passphrase: []byte("passphrase")},
+			readOptions:  DirectoryOptions{Passphrase: []byte("passphrase")},
+			tamper: func(dir string) error {
+				content, err := os.ReadFile(filepath.Join(dir, moveBackupManifestFile))
+				if err != nil {
+					return err
+				}
+				manifest := &moveBackupManifest{}
+				if err := yaml.Unmarshal(content, manifest); err != nil {
+					return err
+				}
+				manifest.Encryption.N = 1 << 30
+				if content, err = yaml.Marshal(manifest); err != nil {
+					return err
+				}
+				return os.WriteFile(filepath.Join(dir, moveBackupManifestFile), content, 0600)
+			},
+			wantErr: "unsupported key derivation parameters",
+		},
+		{
+			name:         "fails if the manifest of an encrypted backup has been tampered with",
+			writeOptions: DirectoryOptions{Passphrase: []byte("passphrase")},
+			readOptions:  DirectoryOptions{Passphrase: []byte("passphrase")},
+			tamper: func(dir string) error {
+				// NOTE: the manifest is not signed, so a file can be removed together with its entry in the manifest.
+				content, err := os.ReadFile(filepath.Join(dir, moveBackupManifestFile))
+				if err != nil {
+					return err
+				}
+				manifest := &moveBackupManifest{}
+				if err := yaml.Unmarshal(content, manifest); err != nil {
+					return err
+				}
+				if err := os.Remove(filepath.Join(dir, manifest.Files[0].Name)); err != nil {
+					return err
+				}
+				manifest.Files = manifest.Files[1:]
+				if content, err = yaml.Marshal(manifest); err != nil {
+					return err
+				}
+				return os.WriteFile(filepath.Join(dir, moveBackupManifestFile), content, 0600)
+			},
+			wantErr: "failed to authenticate the manifest",
+		},
+		{
+			name:         "fails if the MAC has been removed from the manifest of an encrypted backup",
+			writeOptions: DirectoryOptions{Passphrase: []byte("passphrase")},
+			readOptions:  DirectoryOptions{Passphrase: []byte("passphrase")},
+			tamper: func(dir string) error {
+				content, err := os.ReadFile(filepath.Join(dir, moveBackupManifestFile))
+				if err != nil {
+					return err
+				}
+				manifest := &moveBackupManifest{}
+				if err := yaml.Unmarshal(content, manifest); err != nil {
+					return err
+				}
+				manifest.MAC = ""
+				if content, err = yaml.Marshal(manifest); err != nil {
+					return err
+				}
+				return os.WriteFile(filepath.Join(dir, moveBackupManifestFile), content, 0600)
+			},
+			wantErr: "the manifest does not have a MAC",
+		},
+		{
+			name:         "fails if encrypted content is swapped between files",
+			writeOptions: DirectoryOptions{Passphrase: []byte("passphrase")},
+			readOptions:  DirectoryOptions{Passphrase: []byte("passphrase")},
+			tamper: func(dir string) error {
+				ca := filepath.Join(dir, "Secret_ns1_foo-ca.yaml.enc")
+				config := filepath.Join(dir, "Secret_ns1_foo-config.yaml.enc")
+				tmp := filepath.Join(dir, "tmp")
+				if err := os.Rename(ca, tmp); err != nil {
+					return err
+				}
+				if err := os.Rename(config, ca); err != nil {
+					return err
+				}
+				if err := os.Rename(tmp, config); err != nil {
+					return err
+				}
+
+				// NOTE: the manifest is not signed, but it is authenticated by the MAC, so updating it to match
+				// the swapped files is detected.
+				content, err := os.ReadFile(filepath.Join(dir, moveBackupManifestFile))
+				if err != nil {
+					return err
+				}
+				manifest := &moveBackupManifest{}
+				if err := yaml.Unmarshal(content, manifest); err != nil {
+					return err
+				}
+				for i := range manifest.Files {
+					fileContent, err := os.ReadFile(filepath.Join(dir, manifest.Files[i].Name))
+					if err != nil {
+						return err
+					}
+					digest := sha256.Sum256(fileContent)
+					manifest.Files[i].SHA256 = hex.EncodeToString(digest[:])
+				}
+				if content, err = yaml.Marshal(manifest); err != nil {
+					return err
+				}
+				return os.WriteFile(filepath.Join(dir, moveBackupManifestFile), content, 0600)
+			},
+			wantErr: "failed to authenticate the manifest",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			dir := t.TempDir()
+
+			backup, err := newMoveBackup(tt.writeOptions)
+			if err != nil {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			for name, content := range files {
+				g.Expect(backup.writeFile(dir, name, content)).To(Succeed())
+			}
+			g.Expect(backup.writeManifest(dir)).To(Succeed())
+
+			// Files are encrypted, if required.
+			for name, content := range files {
+				if len(tt.writeOptions.Passphrase) > 0 {
+					g.Expect(filepath.Join(dir, name)).ToNot(BeAnExistingFile())
+					encrypted, err := os.ReadFile(filepath.Join(dir, name+moveBackupEncryptedFileSuffix))
+					g.Expect(err).ToNot(HaveOccurred())
+					g.Expect(encrypted).ToNot(ContainSubstring(string(content)))
+					continue
+				}
+				g.Expect(os.ReadFile(filepath.Join(dir, name))).To(Equal(content))
+			}
+
+			if tt.tamper != nil {
+				g.Expect(tt.tamper(dir)).To(Succeed())
+			}
+
+			got, err := readMoveBackup(dir, tt.readOptions)
+			if err == nil {
+				var contents [][]byte
+				contents, err = got.readFiles(dir)
+				if err == nil {
+					want := [][]byte{}
+					for _, content := range files {
+						want = append(want, content)
+					}
+					g.Expect(contents).To(ConsistOf(want))
+				}
+			}
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
+
+func Test_readMoveBackup_withoutManifest(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(dir, "Cluster_ns1_foo.yaml"), []byte("kind: Cluster"), 0600)).To(Succeed())
+
+	// A backup without manifest is read as plain files.
+	backup, err := readMoveBackup(dir, DirectoryOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(backup).To(BeNil())
+
+	// A backup without manifest is rejected if decryption or signature verification are required.
+	_, err = readMoveBackup(dir, DirectoryOptions{VerificationKey: testVerificationKey})
+	g.Expect(err).To(MatchError(ContainSubstring("does not exist")))
+}
+
+func Test_objectMover_toDirectory_fromDirectory_withBackupOptions(t *testing.T) {
+	g := NewWithT(t)
+
+	ctx := context.Background()
+
+	graph := getObjectGraphWithObjs(test.NewFakeCluster("ns1", "foo").Objs())
+	g.Expect(graph.getDiscoveryTypes(ctx)).To(Succeed())
+	g.Expect(graph.Discovery(ctx, "")).To(Succeed())
+
+	dir := t.TempDir()
+
+	backup, err := newMoveBackup(DirectoryOptions{Passphrase: []byte("passphrase"), SigningKey: testSigningKey})
+	g.Expect(err).ToNot(HaveOccurred())
+	mover := objectMover{
+		fromProxy: graph.proxy,
+		backup:    backup,
+	}
+	g.Expect(mover.toDirectory(ctx, graph, dir)).To(Succeed())
+
+	// The directory contains the encrypted files, the manifest and its signature.
+	files, err := os.ReadDir(dir)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(files).To(HaveLen(len(graph.getMoveNodes()) + 2))
+
+	backup, err = readMoveBackup(dir, DirectoryOptions{Passphrase: []byte("passphrase"), VerificationKey: testVerificationKey})
+	g.Expect(err).ToNot(HaveOccurred())
+	mover = objectMover{
+		backup: backup,
+	}
+	objs, err := mover.filesToObjs(dir)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	gotObjs := []string{}
+	for _, obj := range objs {
+		gotObjs = append(gotObjs, obj.GetKind()+"_"+obj.GetNamespace()+"_"+obj.GetName()+".yaml")
+	}
+	wantObjs := []string{}
+	for _, n := range graph.getMoveNodes() {
+		wantObjs = append(wantObjs, n.getFilename())
+	}
+	g.Expect(gotObjs).To(ConsistOf(wantObjs))
+}
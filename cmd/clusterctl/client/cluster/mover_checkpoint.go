@@ -0,0 +1,435 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// moveCheckpointPhase defines the phase of a move operation recorded in a checkpoint.
+type moveCheckpointPhase string
+
+const (
+	// moveCheckpointInProgress is the phase of a move operation that is started but not yet completed.
+	moveCheckpointInProgress moveCheckpointPhase = "InProgress"
+
+	// moveCheckpointCompleted is the phase of a move operation that is successfully completed.
+	moveCheckpointCompleted moveCheckpointPhase = "Completed"
+
+	// moveCheckpointRolledBack is the phase of a move operation that has been rolled back.
+	moveCheckpointRolledBack moveCheckpointPhase = "RolledBack"
+)
+
+// moveCheckpoint records the progress of a move operation, so a move interrupted midway can be resumed or rolled back.
+//
+// The checkpoint is persisted to a file after every change, and it records:
+// - the Clusters and ClusterClasses paused in the source management cluster.
+// - the objects created, or going to be created, in the target management cluster, in creation order.
+// - the objects already existing in the target management cluster and updated by move, with their original content.
+// - the objects deleted from the source management cluster.
+//
+// NOTE: all the methods can be invoked on a nil checkpoint, and they are no-op in this case; this allows
+// to use the objectMover without a checkpoint, e.g. for dry-run.
+type moveCheckpoint struct {
+	// path is the file where the checkpoint is persisted.
+	path string
+
+	// index maps the key of the source objects to their position in Objects.
+	index map[string]int
+
+	// Namespace is the namespace the move operation is processing, or empty for all namespaces.
+	Namespace string `json:"namespace,omitempty"`
+
+	// ClusterSelector restricts the move operation to a subset of the Clusters in the namespace, if any.
+	ClusterSelector *ClusterSelector `json:"clusterSelector,omitempty"`
+
+	// Phase is the current phase of the move operation.
+	Phase moveCheckpointPhase `json:"phase"`
+
+	// Clusters is the list of Clusters paused in the source management cluster.
+	Clusters []corev1.ObjectReference `json:"clusters,omitempty"`
+
+	// ClusterClasses is the list of ClusterClasses paused in the source management cluster.
+	ClusterClasses []corev1.ObjectReference `json:"clusterClasses,omitempty"`
+
+	// Objects is the list of objects processed by the move operation, in creation order.
+	Objects []moveCheckpointObject `json:"objects,omitempty"`
+}
+
+// moveCheckpointObject records the progress of the move operation for a single object.
+type moveCheckpointObject struct {
+	// Source is the reference to the object in the source management cluster.
+	Source corev1.ObjectReference `json:"source"`
+
+	// Target is the reference to the object created in the target management cluster; it could be different
+	// from Source e.g. when mutators change the namespace, and its UID is the UID assigned by the target cluster.
+	Target corev1.ObjectReference `json:"target"`
+
+	// Global is true for objects that are never deleted by move, e.g. cluster-wide objects or their dependents;
+	// those objects are not deleted from the target management cluster in case of rollback as well.
+	Global bool `json:"global,omitempty"`
+
+	// Pending is true when the object is going to be created in the target management cluster, but the creation
+	// has not been completed yet; in this case Target is the planned reference to the object, and its UID is set
+	// only once the target management cluster confirmed the creation.
+	// NOTE: if the move is interrupted right after creating the object, a pending object with a UID exists in the
+	// target management cluster; it gets adopted on resume and deleted on rollback. Pending objects without a UID
+	// are never deleted on rollback, because it is not possible to tell if they have been created by move.
+	Pending bool `json:"pending,omitempty"`
+
+	// Created is true when the object has been created in the target management cluster.
+	Created bool `json:"created,omitempty"`
+
+	// Existing is true when the object already existed in the target management cluster, so it has not been
+	// created by move; those objects are never deleted from the target management cluster in case of rollback.
+	Existing bool `json:"existing,omitempty"`
+
+	// Original is the content of an existing object before move updated it, if any; it is restored in the target
+	// management cluster in case of rollback.
+	Original *unstructured.Unstructured `json:"original,omitempty"`
+
+	// Deleted is true when the object has been deleted from the source management cluster.
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// newMoveCheckpoint returns a checkpoint for a new move operation; if a checkpoint for a move operation still in progress
+// already exists at the given path, an error is returned so the existing checkpoint does not get overridden.
+func newMoveCheckpoint(path, namespace string, selector ClusterSelector) (*moveCheckpoint, error) {
+	existing, err := readMoveCheckpoint(path)
+	if err != nil && !os.IsNotExist(errors.Cause(err)) {
+		return nil, err
+	}
+	if existing != nil && existing.Phase == moveCheckpointInProgress {
+		return nil, errors.Errorf("the checkpoint file %q belongs to a move operation still in progress; resume or rollback it before starting a new move", path)
+	}
+
+	c := &moveCheckpoint{
+		path:      path,
+		index:     map[string]int{},
+		Namespace: namespace,
+		Phase:     moveCheckpointInProgress,
+	}
+	if !selector.IsEmpty() {
+		c.ClusterSelector = &selector
+	}
+	if err := c.save(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// readMoveCheckpoint reads a checkpoint from a file.
+func readMoveCheckpoint(path string) (*moveCheckpoint, error) {
+	content, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the checkpoint file %q", path)
+	}
+
+	c := &moveCheckpoint{}
+	if err := yaml.Unmarshal(content, c); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the checkpoint file %q", path)
+	}
+	c.path = path
+	c.index = map[string]int{}
+	for i := range c.Objects {
+		c.index[checkpointKey(c.Objects[i].Source)] = i
+	}
+	return c, nil
+}
+
+// checkpointKey returns the key identifying an object in a checkpoint.
+// NOTE: the API version is not considered, because it could change e.g. after a provider upgrade.
+func checkpointKey(ref corev1.ObjectReference) string {
+	gk := schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind).GroupKind()
+	return fmt.Sprintf("%s, %s/%s", gk.String(), ref.Namespace, ref.Name)
+}
+
+// save persists the checkpoint; the file is written to a temporary file first and then renamed, so an interruption
+// of the process never leaves a partially written checkpoint.
+func (c *moveCheckpoint) save() error {
+	if c == nil {
+		return nil
+	}
+
+	content, err := yaml.Marshal(c)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the move checkpoint")
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".tmp-*")
+	if err != nil {
+		return errors.Wrapf(err, "failed to write the checkpoint file %q", c.path)
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		return errors.Wrapf(err, "failed to write the checkpoint file %q", c.path)
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrapf(err, "failed to write the checkpoint file %q", c.path)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return errors.Wrapf(err, "failed to write the checkpoint file %q", c.path)
+	}
+	return nil
+}
+
+// recordPaused records the Clusters and ClusterClasses paused in the source management cluster and returns all the
+// Clusters and ClusterClasses recorded in the checkpoint, including the ones paused by a previous, interrupted move.
+// NOTE: this is required because when resuming a move, Clusters and ClusterClasses might be already deleted from the
+// source management cluster, but they still must be resumed in the target management cluster.
+func (c *moveCheckpoint) recordPaused(clusters, clusterClasses []*node) ([]*node, []*node, error) {
+	if c == nil {
+		return clusters, clusterClasses, nil
+	}
+
+	c.Clusters = mergeObjectReferences(c.Clusters, clusters)
+	c.ClusterClasses = mergeObjectReferences(c.ClusterClasses, clusterClasses)
+	if err := c.save(); err != nil {
+		return nil, nil, err
+	}
+	return referencesToNodes(c.Clusters), referencesToNodes(c.ClusterClasses), nil
+}
+
+// restoreCreated checks if the object corresponding to the node has been already created in the target management cluster;
+// if yes, the UID the object got in the target management cluster is restored into the node, so it can be used to rebuild
+// the ownerReferences of the objects still to be created.
+func (c *moveCheckpoint) restoreCreated(n *node) bool {
+	if c == nil {
+		return false
+	}
+
+	i, ok := c.index[checkpointKey(n.identity)]
+	if !ok || !c.Objects[i].Created {
+		return false
+	}
+	n.newUID = c.Objects[i].Target.UID
+	return true
+}
+
+// isPending returns true if the object corresponding to the node was going to be created in the target management
+// cluster by a previous, interrupted move, but the creation has not been completed.
+func (c *moveCheckpoint) isPending(n *node) bool {
+	if c == nil {
+		return false
+	}
+
+	i, ok := c.index[checkpointKey(n.identity)]
+	return ok && c.Objects[i].Pending
+}
+
+// isCreatedByMove returns true if the object corresponding to the node has been created in the target management
+// cluster by a previous, interrupted move, i.e. the UID recorded when the creation has been confirmed matches uid.
+func (c *moveCheckpoint) isCreatedByMove(n *node, uid types.UID) bool {
+	if c == nil || uid == "" {
+		return false
+	}
+
+	i, ok := c.index[checkpointKey(n.identity)]
+	return ok && (c.Objects[i].Pending || c.Objects[i].Created) && c.Objects[i].Target.UID == uid
+}
+
+// recordCreating records that the object corresponding to the node is going to be created in the target management cluster.
+func (c *moveCheckpoint) recordCreating(n *node, obj *unstructured.Unstructured) error {
+	if c == nil {
+		return nil
+	}
+
+	o := c.object(n)
+	if o.Existing {
+		// The object already exists in the target cluster, so it is going to be updated again.
+		return nil
+	}
+	target := corev1.ObjectReference{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	}
+	if o.Pending && o.Target.Namespace == target.Namespace && o.Target.Name == target.Name {
+		// Preserve the UID of an object created by a previous, interrupted move, if any, so it can be adopted.
+		target.UID = o.Target.UID
+	}
+	o.Pending = true
+	o.Target = target
+	return c.save()
+}
+
+// recordCreateConfirmed records the UID assigned by the target management cluster to the object corresponding to
+// the node, as soon as the creation is confirmed; this allows to delete the object on rollback, even if the move is
+// interrupted before completing the creation.
+func (c *moveCheckpoint) recordCreateConfirmed(n *node, uid types.UID) error {
+	if c == nil {
+		return nil
+	}
+
+	o := c.object(n)
+	o.Target.UID = uid
+	return c.save()
+}
+
+// recordExisting records that the object corresponding to the node already exists in the target management cluster,
+// and it is going to be updated if updated is true; in this case the original content of the object is recorded,
+// so it can be restored on rollback.
+// NOTE: the original content recorded first is preserved, because on resume the object might be already updated.
+func (c *moveCheckpoint) recordExisting(n *node, existing *unstructured.Unstructured, updated bool) error {
+	if c == nil {
+		return nil
+	}
+
+	o := c.object(n)
+	o.Pending = false
+	o.Created = false
+	o.Existing = true
+	o.Target = corev1.ObjectReference{
+		APIVersion: existing.GetAPIVersion(),
+		Kind:       existing.GetKind(),
+		Namespace:  existing.GetNamespace(),
+		Name:       existing.GetName(),
+		UID:        existing.GetUID(),
+	}
+	if updated && o.Original == nil {
+		o.Original = existing.DeepCopy()
+		o.Original.SetManagedFields(nil)
+	}
+	return c.save()
+}
+
+// recordCreated records that the object corresponding to the node has been created in the target management cluster.
+func (c *moveCheckpoint) recordCreated(n *node, obj *unstructured.Unstructured) error {
+	if c == nil {
+		return nil
+	}
+
+	o := c.object(n)
+	o.Pending = false
+	o.Created = true
+	o.Existing = false
+	o.Original = nil
+	o.Target = corev1.ObjectReference{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		UID:        obj.GetUID(),
+	}
+	return c.save()
+}
+
+// recordDeleted records that the object corresponding to the node has been deleted from the source management cluster.
+func (c *moveCheckpoint) recordDeleted(n *node) error {
+	if c == nil {
+		return nil
+	}
+
+	c.object(n).Deleted = true
+	return c.save()
+}
+
+// hasPausedClusters returns true if at least one Cluster has been paused in the source management cluster.
+func (c *moveCheckpoint) hasPausedClusters() bool {
+	if c == nil {
+		return false
+	}
+
+	return len(c.Clusters) > 0
+}
+
+// hasDeletedObjects returns true if at least one object has been deleted from the source management cluster.
+func (c *moveCheckpoint) hasDeletedObjects() bool {
+	if c == nil {
+		return false
+	}
+
+	for i := range c.Objects {
+		if c.Objects[i].Deleted {
+			return true
+		}
+	}
+	return false
+}
+
+// setPhase sets the phase of the move operation.
+func (c *moveCheckpoint) setPhase(phase moveCheckpointPhase) error {
+	if c == nil {
+		return nil
+	}
+
+	c.Phase = phase
+	return c.save()
+}
+
+// object returns the checkpoint entry corresponding to a node, adding it if it does not exist yet.
+func (c *moveCheckpoint) object(n *node) *moveCheckpointObject {
+	key := checkpointKey(n.identity)
+	if i, ok := c.index[key]; ok {
+		return &c.Objects[i]
+	}
+
+	c.Objects = append(c.Objects, moveCheckpointObject{
+		Source: corev1.ObjectReference{
+			APIVersion: n.identity.APIVersion,
+			Kind:       n.identity.Kind,
+			Namespace:  n.identity.Namespace,
+			Name:       n.identity.Name,
+			UID:        n.identity.UID,
+		},
+		Global: n.isGlobal || n.isGlobalHierarchy,
+	})
+	c.index[key] = len(c.Objects) - 1
+	return &c.Objects[len(c.Objects)-1]
+}
+
+// mergeObjectReferences adds to a list of references the identity of the given nodes, if not already included.
+func mergeObjectReferences(refs []corev1.ObjectReference, nodes []*node) []corev1.ObjectReference {
+	keys := map[string]bool{}
+	for _, ref := range refs {
+		keys[checkpointKey(ref)] = true
+	}
+	for _, n := range nodes {
+		if keys[checkpointKey(n.identity)] {
+			continue
+		}
+		keys[checkpointKey(n.identity)] = true
+		refs = append(refs, corev1.ObjectReference{
+			APIVersion: n.identity.APIVersion,
+			Kind:       n.identity.Kind,
+			Namespace:  n.identity.Namespace,
+			Name:       n.identity.Name,
+		})
+	}
+	return refs
+}
+
+// referencesToNodes returns a list of nodes with the given identities.
+func referencesToNodes(refs []corev1.ObjectReference) []*node {
+	nodes := make([]*node, 0, len(refs))
+	for _, ref := range refs {
+		nodes = append(nodes, &node{identity: ref})
+	}
+	return nodes
+}
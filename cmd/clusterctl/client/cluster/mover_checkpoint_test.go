@@ -0,0 +1,453 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_newMoveCheckpoint(t *testing.T) {
+	g := NewWithT(t)
+
+	path := filepath.Join(t.TempDir(), "checkpoint.yaml")
+
+	checkpoint, err := newMoveCheckpoint(path, "ns1", ClusterSelector{})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(checkpoint.Phase).To(Equal(moveCheckpointInProgress))
+
+	// A new checkpoint can't override a checkpoint for a move still in progress.
+	_, err = newMoveCheckpoint(path, "ns1", ClusterSelector{})
+	g.Expect(err).To(MatchError(ContainSubstring("still in progress")))
+
+	// A new checkpoint can override a checkpoint for a completed move.
+	g.Expect(checkpoint.setPhase(moveCheckpointCompleted)).To(Succeed())
+	_, err = newMoveCheckpoint(path, "ns2", ClusterSelector{Name: "foo"})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	got, err := readMoveCheckpoint(path)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.Namespace).To(Equal("ns2"))
+	g.Expect(got.ClusterSelector).To(Equal(&ClusterSelector{Name: "foo"}))
+	g.Expect(got.Phase).To(Equal(moveCheckpointInProgress))
+}
+
+func Test_objectMover_move_withCheckpoint(t *testing.T) {
+	for _, tt := range moveTests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ctx := context.Background()
+
+			graph := getObjectGraphWithObjs(tt.fields.objs)
+			g.Expect(graph.getDiscoveryTypes(ctx)).To(Succeed())
+			g.Expect(graph.Discovery(ctx, "")).To(Succeed())
+
+			toProxy := getFakeProxyWithCRDs()
+
+			path := filepath.Join(t.TempDir(), "checkpoint.yaml")
+			checkpoint, err := newMoveCheckpoint(path, "", ClusterSelector{})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			mover := objectMover{
+				fromProxy:  graph.proxy,
+				checkpoint: checkpoint,
+			}
+			err = mover.move(ctx, graph, toProxy)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			got, err := readMoveCheckpoint(path)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.Phase).To(Equal(moveCheckpointCompleted))
+			g.Expect(got.Clusters).To(HaveLen(len(graph.getClusters())))
+			g.Expect(got.ClusterClasses).To(HaveLen(len(graph.getClusterClasses())))
+			g.Expect(got.Objects).To(HaveLen(len(graph.getMoveNodes())))
+			for _, n := range graph.getMoveNodes() {
+				o := got.Objects[got.index[checkpointKey(n.identity)]]
+				g.Expect(o.Created).To(BeTrue(), "%s not recorded as created", n.identityStr())
+				g.Expect(o.Deleted).To(Equal(!n.isGlobal && !n.isGlobalHierarchy && !n.shouldNotDelete), "%s deleted status not recorded as expected", n.identityStr())
+			}
+		})
+	}
+}
+
+func Test_objectMover_move_resumeFromCheckpoint(t *testing.T) {
+	g := NewWithT(t)
+
+	ctx := context.Background()
+
+	objs := test.NewFakeCluster("ns1", "foo").Objs()
+	path := filepath.Join(t.TempDir(), "checkpoint.yaml")
+	toProxy := getFakeProxyWithCRDs()
+
+	// Simulate a move interrupted after creating the first group of objects in the target cluster.
+	graph := getObjectGraphWithObjs(objs)
+	g.Expect(graph.getDiscoveryTypes(ctx)).To(Succeed())
+	g.Expect(graph.Discovery(ctx, "")).To(Succeed())
+
+	checkpoint, err := newMoveCheckpoint(path, "ns1", ClusterSelector{})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	mover := objectMover{
+		fromProxy:  graph.proxy,
+		checkpoint: checkpoint,
+	}
+	_, _, err = mover.checkpoint.recordPaused(graph.getClusters(), graph.getClusterClasses())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(mover.createGroup(ctx, getMoveSequence(graph).getGroup(0), toProxy)).To(Succeed())
+
+	// Resume the move with a new object graph, read from the source cluster again.
+	checkpoint, err = readMoveCheckpoint(path)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(checkpoint.Objects).To(HaveLen(1))
+
+	resumeGraph := newObjectGraph(graph.proxy, graph.providerInventory)
+	g.Expect(resumeGraph.getDiscoveryTypes(ctx)).To(Succeed())
+	g.Expect(resumeGraph.Discovery(ctx, "ns1")).To(Succeed())
+
+	mover = objectMover{
+		fromProxy:  graph.proxy,
+		checkpoint: checkpoint,
+	}
+	g.Expect(mover.move(ctx, resumeGraph, toProxy)).To(Succeed())
+
+	got, err := readMoveCheckpoint(path)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.Phase).To(Equal(moveCheckpointCompleted))
+
+	// Check all the objects have been moved.
+	csFrom, err := graph.proxy.NewClient(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	csTo, err := toProxy.NewClient(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	for _, n := range graph.getMoveNodes() {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(n.identity.APIVersion)
+		obj.SetKind(n.identity.Kind)
+		key := client.ObjectKey{Namespace: n.identity.Namespace, Name: n.identity.Name}
+
+		g.Expect(apierrors.IsNotFound(csFrom.Get(ctx, key, obj))).To(BeTrue(), "%s not deleted from the source cluster", n.identityStr())
+		g.Expect(csTo.Get(ctx, key, obj)).To(Succeed(), "%s not created in the target cluster", n.identityStr())
+	}
+
+	// Resume the Cluster in the target cluster, even if it was already deleted from the source cluster.
+	cluster := &clusterv1.Cluster{}
+	g.Expect(csTo.Get(ctx, client.ObjectKey{Namespace: "ns1", Name: "foo"}, cluster)).To(Succeed())
+	g.Expect(cluster.Spec.Paused).To(BeFalse())
+}
+
+func Test_objectMover_rollback(t *testing.T) {
+	g := NewWithT(t)
+
+	ctx := context.Background()
+
+	objs := test.NewFakeCluster("ns1", "foo").Objs()
+	path := filepath.Join(t.TempDir(), "checkpoint.yaml")
+	toProxy := getFakeProxyWithCRDs()
+
+	// Simulate a move interrupted after creating all the objects in the target cluster.
+	graph := getObjectGraphWithObjs(objs)
+	g.Expect(graph.getDiscoveryTypes(ctx)).To(Succeed())
+	g.Expect(graph.Discovery(ctx, "")).To(Succeed())
+
+	checkpoint, err := newMoveCheckpoint(path, "ns1", ClusterSelector{})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	mover := objectMover{
+		fromProxy:  graph.proxy,
+		checkpoint: checkpoint,
+	}
+	g.Expect(setClusterPause(ctx, graph.proxy, graph.getClusters(), true, false)).To(Succeed())
+	_, _, err = mover.checkpoint.recordPaused(graph.getClusters(), graph.getClusterClasses())
+	g.Expect(err).ToNot(HaveOccurred())
+	moveSequence := getMoveSequence(graph)
+	for i := range moveSequence.groups {
+		g.Expect(mover.createGroup(ctx, moveSequence.getGroup(i), toProxy)).To(Succeed())
+	}
+
+	// Rollback the move.
+	checkpoint, err = readMoveCheckpoint(path)
+	g.Expect(err).ToNot(HaveOccurred())
+	mover = objectMover{
+		fromProxy:  graph.proxy,
+		checkpoint: checkpoint,
+	}
+	g.Expect(mover.rollback(ctx, toProxy)).To(Succeed())
+
+	got, err := readMoveCheckpoint(path)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.Phase).To(Equal(moveCheckpointRolledBack))
+
+	// Check all the objects have been deleted from the target cluster and kept in the source cluster.
+	csFrom, err := graph.proxy.NewClient(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	csTo, err := toProxy.NewClient(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	for _, n := range graph.getMoveNodes() {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(n.identity.APIVersion)
+		obj.SetKind(n.identity.Kind)
+		key := client.ObjectKey{Namespace: n.identity.Namespace, Name: n.identity.Name}
+
+		g.Expect(csFrom.Get(ctx, key, obj)).To(Succeed(), "%s deleted from the source cluster", n.identityStr())
+		g.Expect(apierrors.IsNotFound(csTo.Get(ctx, key, obj))).To(BeTrue(), "%s not deleted from the target cluster", n.identityStr())
+	}
+
+	// Check the Cluster in the source cluster is not paused anymore.
+	cluster := &clusterv1.Cluster{}
+	g.Expect(csFrom.Get(ctx, client.ObjectKey{Namespace: "ns1", Name: "foo"}, cluster)).To(Succeed())
+	g.Expect(cluster.Spec.Paused).To(BeFalse())
+}
+
+func Test_objectMover_rollback_failsIfObjectsAreDeleted(t *testing.T) {
+	g := NewWithT(t)
+
+	path := filepath.Join(t.TempDir(), "checkpoint.yaml")
+	checkpoint, err := newMoveCheckpoint(path, "ns1", ClusterSelector{})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(checkpoint.recordDeleted(&node{identity: corev1.ObjectReference{APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Namespace: "ns1", Name: "foo"}})).To(Succeed())
+
+	mover := objectMover{
+		fromProxy:  test.NewFakeProxy(),
+		checkpoint: checkpoint,
+	}
+	g.Expect(mover.rollback(context.Background(), test.NewFakeProxy())).To(MatchError(ContainSubstring("resume the move operation instead")))
+
+	// The checkpoint is preserved, so the move can be resumed.
+	_, err = os.Stat(path)
+	g.Expect(err).ToNot(HaveOccurred())
+}
+
+func Test_objectMover_rollback_preservesExistingObjects(t *testing.T) {
+	g := NewWithT(t)
+
+	ctx := context.Background()
+
+	objs := test.NewFakeCluster("ns1", "foo").Objs()
+	path := filepath.Join(t.TempDir(), "checkpoint.yaml")
+	toProxy := getFakeProxyWithCRDs()
+
+	graph := getObjectGraphWithObjs(objs)
+	g.Expect(graph.getDiscoveryTypes(ctx)).To(Succeed())
+	g.Expect(graph.Discovery(ctx, "")).To(Succeed())
+
+	// Create the Cluster in the target cluster before the move.
+	csTo, err := toProxy.NewClient(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(csTo.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1"}})).To(Succeed())
+	existing := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns1",
+			Name:      "foo",
+			Labels:    map[string]string{"pre-existing": "true"},
+		},
+	}
+	g.Expect(csTo.Create(ctx, existing)).To(Succeed())
+
+	// Simulate a move interrupted after creating all the objects in the target cluster; the existing Cluster gets updated.
+	checkpoint, err := newMoveCheckpoint(path, "ns1", ClusterSelector{})
+	g.Expect(err).ToNot(HaveOccurred())
+	mover := objectMover{
+		fromProxy:  graph.proxy,
+		checkpoint: checkpoint,
+	}
+	moveSequence := getMoveSequence(graph)
+	for i := range moveSequence.groups {
+		g.Expect(mover.createGroup(ctx, moveSequence.getGroup(i), toProxy)).To(Succeed())
+	}
+
+	cluster := &clusterv1.Cluster{}
+	g.Expect(csTo.Get(ctx, client.ObjectKeyFromObject(existing), cluster)).To(Succeed())
+	g.Expect(cluster.Labels).ToNot(HaveKey("pre-existing"))
+
+	checkpoint, err = readMoveCheckpoint(path)
+	g.Expect(err).ToNot(HaveOccurred())
+	o := checkpoint.Objects[checkpoint.index[checkpointKey(corev1.ObjectReference{APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Namespace: "ns1", Name: "foo"})]]
+	g.Expect(o.Existing).To(BeTrue())
+	g.Expect(o.Created).To(BeFalse())
+	g.Expect(o.Target.UID).To(Equal(existing.UID))
+	g.Expect(o.Original).ToNot(BeNil())
+
+	// Rollback the move.
+	mover = objectMover{
+		fromProxy:  graph.proxy,
+		checkpoint: checkpoint,
+	}
+	g.Expect(mover.rollback(ctx, toProxy)).To(Succeed())
+
+	// Check the existing Cluster survived the rollback and it has been restored, while the other objects have been deleted.
+	cluster = &clusterv1.Cluster{}
+	g.Expect(csTo.Get(ctx, client.ObjectKeyFromObject(existing), cluster)).To(Succeed())
+	g.Expect(cluster.UID).To(Equal(existing.UID))
+	g.Expect(cluster.Labels).To(HaveKeyWithValue("pre-existing", "true"))
+	for _, n := range graph.getMoveNodes() {
+		if n.identity.Kind == "Cluster" {
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(n.identity.APIVersion)
+		obj.SetKind(n.identity.Kind)
+		key := client.ObjectKey{Namespace: n.identity.Namespace, Name: n.identity.Name}
+		g.Expect(apierrors.IsNotFound(csTo.Get(ctx, key, obj))).To(BeTrue(), "%s not deleted from the target cluster", n.identityStr())
+	}
+}
+
+func Test_objectMover_resumeAndRollback_withPendingObjects(t *testing.T) {
+	// Simulates a move interrupted right after creating the first group of objects in the target cluster,
+	// before recording them as created in the checkpoint; if confirmUID is true, the UIDs assigned by the
+	// target cluster are recorded in the checkpoint.
+	setup := func(g *WithT, ctx context.Context, path string, confirmUID bool) (*objectGraph, Proxy, *moveCheckpoint) {
+		objs := test.NewFakeCluster("ns1", "foo").Objs()
+		toProxy := getFakeProxyWithCRDs()
+
+		graph := getObjectGraphWithObjs(objs)
+		g.Expect(graph.getDiscoveryTypes(ctx)).To(Succeed())
+		g.Expect(graph.Discovery(ctx, "")).To(Succeed())
+
+		checkpoint, err := newMoveCheckpoint(path, "ns1", ClusterSelector{})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(setClusterPause(ctx, graph.proxy, graph.getClusters(), true, false)).To(Succeed())
+		_, _, err = checkpoint.recordPaused(graph.getClusters(), graph.getClusterClasses())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		// Create the objects without a checkpoint, and then record them as pending only.
+		mover := objectMover{
+			fromProxy: graph.proxy,
+		}
+		group := getMoveSequence(graph).getGroup(0)
+		g.Expect(mover.createGroup(ctx, group, toProxy)).To(Succeed())
+		for _, n := range group {
+			obj := &unstructured.Unstructured{}
+			obj.SetAPIVersion(n.identity.APIVersion)
+			obj.SetKind(n.identity.Kind)
+			obj.SetNamespace(n.identity.Namespace)
+			obj.SetName(n.identity.Name)
+			g.Expect(checkpoint.recordCreating(n, obj)).To(Succeed())
+			if confirmUID {
+				g.Expect(checkpoint.recordCreateConfirmed(n, n.newUID)).To(Succeed())
+			}
+		}
+
+		checkpoint, err = readMoveCheckpoint(path)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(checkpoint.Objects).To(HaveLen(len(group)))
+		g.Expect(checkpoint.Objects[0].Pending).To(BeTrue())
+		g.Expect(checkpoint.Objects[0].Created).To(BeFalse())
+		return graph, toProxy, checkpoint
+	}
+
+	t.Run("resume adopts pending objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ctx := context.Background()
+		path := filepath.Join(t.TempDir(), "checkpoint.yaml")
+		graph, toProxy, checkpoint := setup(g, ctx, path, true)
+
+		resumeGraph := newObjectGraph(graph.proxy, graph.providerInventory)
+		g.Expect(resumeGraph.getDiscoveryTypes(ctx)).To(Succeed())
+		g.Expect(resumeGraph.Discovery(ctx, "ns1")).To(Succeed())
+
+		mover := objectMover{
+			fromProxy:  graph.proxy,
+			checkpoint: checkpoint,
+		}
+		g.Expect(mover.move(ctx, resumeGraph, toProxy)).To(Succeed())
+
+		got, err := readMoveCheckpoint(path)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got.Phase).To(Equal(moveCheckpointCompleted))
+		for _, o := range got.Objects {
+			g.Expect(o.Pending).To(BeFalse(), "%s still pending", o.Source.Name)
+			g.Expect(o.Created).To(BeTrue(), "%s not recorded as created", o.Source.Name)
+			g.Expect(o.Target.UID).ToNot(BeEmpty(), "%s target UID not recorded", o.Source.Name)
+		}
+	})
+
+	t.Run("rollback deletes pending objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ctx := context.Background()
+		path := filepath.Join(t.TempDir(), "checkpoint.yaml")
+		graph, toProxy, checkpoint := setup(g, ctx, path, true)
+
+		mover := objectMover{
+			fromProxy:  graph.proxy,
+			checkpoint: checkpoint,
+		}
+		g.Expect(mover.rollback(ctx, toProxy)).To(Succeed())
+
+		got, err := readMoveCheckpoint(path)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got.Phase).To(Equal(moveCheckpointRolledBack))
+
+		csTo, err := toProxy.NewClient(ctx)
+		g.Expect(err).ToNot(HaveOccurred())
+		for _, o := range got.Objects {
+			g.Expect(o.Pending).To(BeFalse(), "%s still pending", o.Source.Name)
+
+			obj := &unstructured.Unstructured{}
+			obj.SetAPIVersion(o.Source.APIVersion)
+			obj.SetKind(o.Source.Kind)
+			key := client.ObjectKey{Namespace: o.Source.Namespace, Name: o.Source.Name}
+			g.Expect(apierrors.IsNotFound(csTo.Get(ctx, key, obj))).To(BeTrue(), "%s not deleted from the target cluster", o.Source.Name)
+		}
+	})
+
+	t.Run("rollback does not delete pending objects without a UID", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ctx := context.Background()
+		path := filepath.Join(t.TempDir(), "checkpoint.yaml")
+		graph, toProxy, checkpoint := setup(g, ctx, path, false)
+
+		mover := objectMover{
+			fromProxy:  graph.proxy,
+			checkpoint: checkpoint,
+		}
+		g.Expect(mover.rollback(ctx, toProxy)).To(Succeed())
+
+		got, err := readMoveCheckpoint(path)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got.Phase).To(Equal(moveCheckpointRolledBack))
+
+		csTo, err := toProxy.NewClient(ctx)
+		g.Expect(err).ToNot(HaveOccurred())
+		for _, o := range got.Objects {
+			obj := &unstructured.Unstructured{}
+			obj.SetAPIVersion(o.Source.APIVersion)
+			obj.SetKind(o.Source.Kind)
+			key := client.ObjectKey{Namespace: o.Source.Namespace, Name: o.Source.Name}
+			g.Expect(csTo.Get(ctx, key, obj)).To(Succeed(), "%s deleted from the target cluster", o.Source.Name)
+		}
+	})
+}
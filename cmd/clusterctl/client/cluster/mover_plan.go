@@ -0,0 +1,321 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/utils/ptr"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+)
+
+// MovePlan describes the actions a move operation would perform, as derived from the object graph.
+type MovePlan struct {
+	// Namespace is the namespace the move operation would process, or empty for all namespaces.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Clusters is the list of Clusters that would be moved.
+	Clusters []MovePlanCluster `json:"clusters,omitempty"`
+
+	// Objects is the list of objects that would be moved, in the order they would be created in the target
+	// management cluster; objects are deleted from the source management cluster in reverse order.
+	Objects []MovePlanObject `json:"objects,omitempty"`
+
+	// Excluded is the list of objects discovered in the source management cluster that would not be moved.
+	Excluded []MovePlanExcludedObject `json:"excluded,omitempty"`
+
+	// Blockers is the list of issues that would prevent the move operation from completing.
+	Blockers []MovePlanBlocker `json:"blockers,omitempty"`
+}
+
+// MovePlanCluster describes a Cluster that would be moved.
+type MovePlanCluster struct {
+	// Object is the reference to the Cluster.
+	Object corev1.ObjectReference `json:"object"`
+
+	// Paused is true if the Cluster is already paused in the source management cluster.
+	// NOTE: move pauses all the Clusters in the source management cluster, and it resumes all of them
+	// in the target management cluster once the move operation completes.
+	Paused bool `json:"paused"`
+}
+
+// MovePlanObject describes an object that would be moved.
+type MovePlanObject struct {
+	// Group is the index of the group the object belongs to; objects in the same group have no dependencies
+	// on each other, and groups are processed in order, so an object is moved only after its owners.
+	Group int `json:"group"`
+
+	// Object is the reference to the object.
+	Object corev1.ObjectReference `json:"object"`
+
+	// Owners is the list of objects owning this object via OwnerReferences.
+	Owners []corev1.ObjectReference `json:"owners,omitempty"`
+
+	// SoftOwners is the list of objects linked to this object without an explicit OwnerReference,
+	// e.g. Secrets linked to a Cluster by a naming convention.
+	SoftOwners []corev1.ObjectReference `json:"softOwners,omitempty"`
+
+	// Tenants is the list of objects this object belongs to, e.g. a Cluster or a ClusterResourceSet,
+	// no matter if the object is linked directly or indirectly via the OwnerReference chain.
+	Tenants []corev1.ObjectReference `json:"tenants,omitempty"`
+
+	// DeleteFromSource is true if the object would be deleted from the source management cluster.
+	DeleteFromSource bool `json:"deleteFromSource"`
+
+	// KeepReason explains why the object would not be deleted from the source management cluster, if any.
+	KeepReason string `json:"keepReason,omitempty"`
+}
+
+// MovePlanExcludedObject describes an object that would not be moved.
+type MovePlanExcludedObject struct {
+	// Object is the reference to the object.
+	Object corev1.ObjectReference `json:"object"`
+
+	// Reason explains why the object would not be moved.
+	Reason string `json:"reason"`
+}
+
+// MovePlanBlocker describes an issue that would prevent the move operation from completing.
+type MovePlanBlocker struct {
+	// Object is the reference to the object causing the issue, if any.
+	Object *corev1.ObjectReference `json:"object,omitempty"`
+
+	// Message describes the issue.
+	Message string `json:"message"`
+}
+
+// HasBlockers returns true if there are issues that would prevent the move operation from completing.
+func (p *MovePlan) HasBlockers() bool {
+	return len(p.Blockers) > 0
+}
+
+func (o *objectMover) Plan(ctx context.Context, namespace string, selector ClusterSelector, toCluster Client) (*MovePlan, error) {
+	log := logf.Log
+	log.Info("Planning move...")
+
+	// Discovery the object graph, without checking if the move operation can actually be executed; checks are
+	// executed later and reported as blockers in the plan.
+	objectGraph, err := o.discoverObjectGraph(ctx, namespace, selector)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get object graph")
+	}
+
+	plan := newMovePlan(objectGraph, namespace)
+
+	// Reads the pause state of the Clusters.
+	readClusterBackoff := newReadBackoff()
+	for _, cluster := range objectGraph.getClusters() {
+		clusterObj := &clusterv1.Cluster{}
+		if err := retryWithExponentialBackoff(ctx, readClusterBackoff, func(ctx context.Context) error {
+			return getClusterObj(ctx, o.fromProxy, cluster, clusterObj)
+		}); err != nil {
+			return nil, err
+		}
+		plan.Clusters = append(plan.Clusters, MovePlanCluster{
+			Object: planObjectReference(cluster),
+			Paused: clusterObj.Spec.Paused,
+		})
+	}
+	sort.Slice(plan.Clusters, func(i, j int) bool {
+		return planObjectReferenceLess(plan.Clusters[i].Object, plan.Clusters[j].Object)
+	})
+
+	// Checks if Cluster API has already completed the provisioning of the infrastructure for the objects involved in the move operation.
+	if err := plan.addBlockers(o.checkProvisioningCompleted(ctx, objectGraph)); err != nil {
+		return nil, errors.Wrap(err, "failed to check for provisioned infrastructure")
+	}
+
+	// Checks for objects blocking the move operation.
+	for _, n := range objectGraph.getMoveNodes() {
+		if n.blockingMove {
+			plan.Blockers = append(plan.Blockers, MovePlanBlocker{
+				Object:  ptr.To(planObjectReference(n)),
+				Message: fmt.Sprintf("the object has the %s annotation; move waits for it to be removed", clusterctlv1.BlockMoveAnnotation),
+			})
+		}
+	}
+
+	// Checks that all the required providers and CRDs are in place in the target cluster, if any.
+	if toCluster != nil {
+		if err := plan.addBlockers(o.checkTargetProviders(ctx, toCluster.ProviderInventory())); err != nil {
+			return nil, errors.Wrap(err, "failed to check providers in target cluster")
+		}
+
+		if err := plan.addBlockers(checkTargetCRDs(ctx, objectGraph, toCluster.Proxy())); err != nil {
+			return nil, errors.Wrap(err, "failed to check CRDs in target cluster")
+		}
+	}
+
+	return plan, nil
+}
+
+// newMovePlan returns a MovePlan with the objects that would be moved, in order, and the objects that would not be moved.
+func newMovePlan(graph *objectGraph, namespace string) *MovePlan {
+	plan := &MovePlan{
+		Namespace: namespace,
+	}
+
+	moveSequence := getMoveSequence(graph)
+	for groupIndex := range len(moveSequence.groups) {
+		group := moveSequence.getGroup(groupIndex)
+
+		objects := make([]MovePlanObject, 0, len(group))
+		for _, n := range group {
+			object := MovePlanObject{
+				Group:            groupIndex,
+				Object:           planObjectReference(n),
+				DeleteFromSource: true,
+			}
+			for owner := range n.owners {
+				object.Owners = append(object.Owners, planObjectReference(owner))
+			}
+			for owner := range n.softOwners {
+				object.SoftOwners = append(object.SoftOwners, planObjectReference(owner))
+			}
+			for tenant := range n.tenant {
+				object.Tenants = append(object.Tenants, planObjectReference(tenant))
+			}
+			sortPlanObjectReferences(object.Owners)
+			sortPlanObjectReferences(object.SoftOwners)
+			sortPlanObjectReferences(object.Tenants)
+
+			// Nb. this must be kept in sync with deleteSourceObject.
+			switch {
+			case n.isGlobal:
+				object.DeleteFromSource = false
+				object.KeepReason = "the object is cluster-wide"
+			case n.isGlobalHierarchy:
+				object.DeleteFromSource = false
+				object.KeepReason = "the object belongs to a hierarchy that starts with a cluster-wide object"
+			case n.shouldNotDelete:
+				object.DeleteFromSource = false
+				object.KeepReason = "the object is used by objects outside of the namespace being moved"
+			}
+
+			objects = append(objects, object)
+		}
+		sort.Slice(objects, func(i, j int) bool {
+			return planObjectReferenceLess(objects[i].Object, objects[j].Object)
+		})
+		plan.Objects = append(plan.Objects, objects...)
+	}
+
+	for _, n := range graph.getNodes() {
+		if moveSequence.hasNode(n) {
+			continue
+		}
+
+		reason := "the object does not belong to any Cluster or ClusterResourceSet, and it is not labeled for move"
+		if n.virtual {
+			reason = "the object is referenced by an OwnerReference, but its kind is not considered for move"
+		}
+		plan.Excluded = append(plan.Excluded, MovePlanExcludedObject{
+			Object: planObjectReference(n),
+			Reason: reason,
+		})
+	}
+	sort.Slice(plan.Excluded, func(i, j int) bool {
+		return planObjectReferenceLess(plan.Excluded[i].Object, plan.Excluded[j].Object)
+	})
+
+	return plan
+}
+
+// addBlockers adds the errors returned by a check to the list of blockers; if the check failed
+// for other reasons, e.g. because it was not possible to read objects, the error is returned.
+func (p *MovePlan) addBlockers(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var aggregate kerrors.Aggregate
+	if !errors.As(err, &aggregate) {
+		return err
+	}
+	for _, e := range aggregate.Errors() {
+		p.Blockers = append(p.Blockers, MovePlanBlocker{
+			Message: e.Error(),
+		})
+	}
+	return nil
+}
+
+// checkTargetCRDs checks that the CRDs for all the objects to be moved exist in the target cluster.
+func checkTargetCRDs(ctx context.Context, graph *objectGraph, toProxy Proxy) error {
+	crdList := &apiextensionsv1.CustomResourceDefinitionList{}
+	readCRDsBackoff := newReadBackoff()
+	if err := retryWithExponentialBackoff(ctx, readCRDsBackoff, func(ctx context.Context) error {
+		return getCRDList(ctx, toProxy, crdList)
+	}); err != nil {
+		return err
+	}
+
+	targetTypes := map[schema.GroupKind]bool{}
+	for _, crd := range crdList.Items {
+		targetTypes[schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}] = true
+	}
+
+	errList := []error{}
+	missingTypes := map[schema.GroupKind]bool{}
+	for _, n := range graph.getMoveNodes() {
+		gk := n.identity.GroupVersionKind().GroupKind()
+
+		// Core types like Secrets and ConfigMaps always exists.
+		if gk.Group == "" || targetTypes[gk] || missingTypes[gk] {
+			continue
+		}
+		missingTypes[gk] = true
+		errList = append(errList, errors.Errorf("the CRD for %s does not exist in the target cluster", gk))
+	}
+	return kerrors.NewAggregate(errList)
+}
+
+// planObjectReference returns the reference to the object corresponding to a node to be used in a plan.
+func planObjectReference(n *node) corev1.ObjectReference {
+	return corev1.ObjectReference{
+		APIVersion: n.identity.APIVersion,
+		Kind:       n.identity.Kind,
+		Namespace:  n.identity.Namespace,
+		Name:       n.identity.Name,
+	}
+}
+
+func sortPlanObjectReferences(refs []corev1.ObjectReference) {
+	sort.Slice(refs, func(i, j int) bool {
+		return planObjectReferenceLess(refs[i], refs[j])
+	})
+}
+
+func planObjectReferenceLess(a, b corev1.ObjectReference) bool {
+	if a.Kind != b.Kind {
+		return a.Kind < b.Kind
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_newMovePlan(t *testing.T) {
+	// NB. we are testing the move plan using the same set of moveTests used for the move sequence.
+	for _, tt := range moveTests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ctx := context.Background()
+
+			graph := getObjectGraphWithObjs(tt.fields.objs)
+			g.Expect(graph.getDiscoveryTypes(ctx)).To(Succeed())
+			g.Expect(graph.Discovery(ctx, "")).To(Succeed())
+
+			plan := newMovePlan(graph, "")
+
+			gotGroups := make([][]string, len(tt.wantMoveGroups))
+			lastGroup := 0
+			for _, o := range plan.Objects {
+				// Objects must be listed in move order.
+				g.Expect(o.Group).To(BeNumerically(">=", lastGroup))
+				lastGroup = o.Group
+
+				g.Expect(o.Group).To(BeNumerically("<", len(tt.wantMoveGroups)))
+				gvk := schema.FromAPIVersionAndKind(o.Object.APIVersion, o.Object.Kind)
+				key := fmt.Sprintf("%s, %s/%s", gvk, o.Object.Namespace, o.Object.Name)
+				if o.Object.Namespace == "" {
+					key = fmt.Sprintf("%s, %s", gvk, o.Object.Name)
+				}
+				gotGroups[o.Group] = append(gotGroups[o.Group], key)
+			}
+			for i := range tt.wantMoveGroups {
+				g.Expect(gotGroups[i]).To(ConsistOf(tt.wantMoveGroups[i]))
+			}
+		})
+	}
+}
+
+func Test_objectMover_Plan(t *testing.T) {
+	g := NewWithT(t)
+
+	ctx := context.Background()
+
+	objs := test.NewFakeCluster("ns1", "foo").Objs()
+	objs = append(objs, &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns1",
+			Name:      "not-linked-to-a-cluster",
+		},
+	})
+	graph := getObjectGraphWithObjs(objs)
+
+	// The target cluster is empty, so both providers and CRDs are missing.
+	toCluster := New(Kubeconfig{}, nil, InjectProxy(test.NewFakeProxy()))
+
+	mover := newObjectMover(graph.proxy, graph.providerInventory)
+	plan, err := mover.Plan(ctx, "ns1", ClusterSelector{}, toCluster)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(plan.Namespace).To(Equal("ns1"))
+	g.Expect(plan.Clusters).To(ConsistOf(MovePlanCluster{
+		Object: corev1.ObjectReference{APIVersion: "cluster.x-k8s.io/v1beta1", Kind: "Cluster", Namespace: "ns1", Name: "foo"},
+	}))
+
+	g.Expect(plan.Objects).To(HaveLen(4))
+	g.Expect(plan.Objects[0].Object.Kind).To(Equal("Cluster"))
+	for _, o := range plan.Objects[1:] {
+		g.Expect(o.Group).To(Equal(1))
+		g.Expect(o.Tenants).To(ConsistOf(plan.Objects[0].Object))
+		g.Expect(o.DeleteFromSource).To(BeTrue())
+		g.Expect(append(o.Owners, o.SoftOwners...)).To(ConsistOf(plan.Objects[0].Object))
+	}
+
+	g.Expect(plan.Excluded).To(ContainElement(MovePlanExcludedObject{
+		Object: corev1.ObjectReference{APIVersion: "v1", Kind: "Secret", Namespace: "ns1", Name: "not-linked-to-a-cluster"},
+		Reason: "the object does not belong to any Cluster or ClusterResourceSet, and it is not labeled for move",
+	}))
+
+	blockers := []string{}
+	for _, b := range plan.Blockers {
+		blockers = append(blockers, b.Message)
+	}
+	g.Expect(plan.HasBlockers()).To(BeTrue())
+	g.Expect(blockers).To(ConsistOf(
+		ContainSubstring("is still provisioning the infrastructure"),
+		Equal("provider infrastructure-infra1 not found in the target cluster"),
+		Equal("the CRD for Cluster.cluster.x-k8s.io does not exist in the target cluster"),
+		Equal("the CRD for GenericInfrastructureCluster.infrastructure.cluster.x-k8s.io does not exist in the target cluster"),
+	))
+}
+
+func Test_objectMover_Plan_blockMoveAnnotation(t *testing.T) {
+	g := NewWithT(t)
+
+	ctx := context.Background()
+
+	objs := test.NewFakeCluster("ns1", "foo").Objs()
+	for _, o := range objs {
+		if o.GetObjectKind().GroupVersionKind().Kind == "GenericInfrastructureCluster" {
+			o.SetAnnotations(map[string]string{clusterctlv1.BlockMoveAnnotation: "true"})
+		}
+	}
+	graph := getObjectGraphWithObjs(objs)
+
+	mover := newObjectMover(graph.proxy, graph.providerInventory)
+	plan, err := mover.Plan(ctx, "ns1", ClusterSelector{}, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(plan.Blockers).To(ContainElement(MovePlanBlocker{
+		Object:  &corev1.ObjectReference{APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1", Kind: "GenericInfrastructureCluster", Namespace: "ns1", Name: "foo"},
+		Message: fmt.Sprintf("the object has the %s annotation; move waits for it to be removed", clusterctlv1.BlockMoveAnnotation),
+	}))
+}
@@ -2512,3 +2512,115 @@ func Test_applyMutators(t *testing.T) {
 		})
 	}
 }
+
+func Test_objectMover_selectClusters(t *testing.T) {
+	objs := test.NewFakeCluster("ns1", "foo1").Objs()
+	objs = append(objs, test.NewFakeCluster("ns1", "foo2").Objs()...)
+	objs = append(objs, test.NewFakeCluster("ns1", "bar").Objs()...)
+	for _, o := range objs {
+		if o.GetObjectKind().GroupVersionKind().Kind == "Cluster" && strings.HasPrefix(o.GetName(), "foo") {
+			o.SetLabels(map[string]string{"env": "dev"})
+		}
+	}
+
+	tests := []struct {
+		name         string
+		selector     ClusterSelector
+		wantClusters []string
+		wantErr      bool
+	}{
+		{
+			name:         "empty selector selects all the Clusters",
+			selector:     ClusterSelector{},
+			wantClusters: []string{"foo1", "foo2", "bar"},
+		},
+		{
+			name:         "select a Cluster by name",
+			selector:     ClusterSelector{Name: "foo2"},
+			wantClusters: []string{"foo2"},
+		},
+		{
+			name:         "select Clusters by label",
+			selector:     ClusterSelector{LabelSelector: "env=dev"},
+			wantClusters: []string{"foo1", "foo2"},
+		},
+		{
+			name:     "fails if no Clusters match the selector",
+			selector: ClusterSelector{Name: "bar", LabelSelector: "env=dev"},
+			wantErr:  true,
+		},
+		{
+			name:     "fails with an invalid label selector",
+			selector: ClusterSelector{LabelSelector: "env=="},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ctx := context.Background()
+
+			graph := getObjectGraphWithObjs(objs)
+			g.Expect(graph.getDiscoveryTypes(ctx)).To(Succeed())
+			g.Expect(graph.Discovery(ctx, "")).To(Succeed())
+
+			mover := objectMover{
+				fromProxy: graph.proxy,
+			}
+			err := mover.selectClusters(ctx, graph, tt.selector)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			gotClusters := []string{}
+			for _, cluster := range graph.getClusters() {
+				gotClusters = append(gotClusters, cluster.identity.Name)
+			}
+			g.Expect(gotClusters).To(ConsistOf(tt.wantClusters))
+		})
+	}
+}
+
+func Test_objectMover_move_selectedClusters(t *testing.T) {
+	g := NewWithT(t)
+
+	ctx := context.Background()
+
+	objs := test.NewFakeClusterClass("ns1", "class1").Objs()
+	objs = append(objs, test.NewFakeCluster("ns1", "foo1").WithTopologyClass("class1").Objs()...)
+	objs = append(objs, test.NewFakeCluster("ns1", "foo2").WithTopologyClass("class1").Objs()...)
+	objs = deduplicateObjects(objs)
+
+	graph := getObjectGraphWithObjs(objs)
+	g.Expect(graph.getDiscoveryTypes(ctx)).To(Succeed())
+	g.Expect(graph.Discovery(ctx, "")).To(Succeed())
+
+	toProxy := getFakeProxyWithCRDs()
+
+	mover := objectMover{
+		fromProxy: graph.proxy,
+	}
+	g.Expect(mover.selectClusters(ctx, graph, ClusterSelector{Name: "foo1"})).To(Succeed())
+	g.Expect(mover.move(ctx, graph, toProxy)).To(Succeed())
+
+	csFrom, err := graph.proxy.NewClient(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	csTo, err := toProxy.NewClient(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The selected Cluster is moved, while the other Cluster is left in the source cluster.
+	g.Expect(apierrors.IsNotFound(csFrom.Get(ctx, client.ObjectKey{Namespace: "ns1", Name: "foo1"}, &clusterv1.Cluster{}))).To(BeTrue())
+	g.Expect(csTo.Get(ctx, client.ObjectKey{Namespace: "ns1", Name: "foo1"}, &clusterv1.Cluster{})).To(Succeed())
+	g.Expect(csFrom.Get(ctx, client.ObjectKey{Namespace: "ns1", Name: "foo2"}, &clusterv1.Cluster{})).To(Succeed())
+	g.Expect(apierrors.IsNotFound(csTo.Get(ctx, client.ObjectKey{Namespace: "ns1", Name: "foo2"}, &clusterv1.Cluster{}))).To(BeTrue())
+
+	// The ClusterClass is copied, because it is still used by the other Cluster, and it is not paused in both clusters.
+	for _, c := range []client.Client{csFrom, csTo} {
+		clusterClass := &clusterv1.ClusterClass{}
+		g.Expect(c.Get(ctx, client.ObjectKey{Namespace: "ns1", Name: "class1"}, clusterClass)).To(Succeed())
+		g.Expect(clusterClass.GetAnnotations()).ToNot(HaveKey(clusterv1.PausedAnnotation))
+	}
+}
@@ -854,3 +854,134 @@ func (o *objectGraph) setShouldNotDeleteHierarchy(node *node) {
 		}
 	}
 }
+
+// selectClusters restricts the object graph to the given Clusters and to the objects they depend on, e.g. the ClusterClass
+// with its templates or the ClusterResourceSets applied to the Clusters; objects belonging only to other Clusters are removed
+// from the graph, so they are not considered for move.
+// Objects the selected Clusters depend on but still in use by other Clusters, e.g. a ClusterClass shared by many Clusters,
+// are marked as should not delete, so they are copied to the target management cluster instead of being moved.
+// NOTE: hierarchies starting from a global object (e.g. identities) are always kept, because Clusters can reference them
+// without an explicit OwnerReference; those objects are never deleted from the source management cluster.
+func (o *objectGraph) selectClusters(clusters []*node) {
+	clusterGroupKind := clusterv1.GroupVersion.WithKind("Cluster").GroupKind()
+
+	selected := map[*node]empty{}
+	for _, cluster := range clusters {
+		selected[cluster] = empty{}
+	}
+
+	// belongsToSelectedClusters returns true if the node belongs to at least one of the selected Clusters.
+	belongsToSelectedClusters := func(n *node) bool {
+		for tenant := range n.tenant {
+			if _, ok := selected[tenant]; ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	// belongsToOtherClusters returns true if the node belongs only to Clusters not selected.
+	belongsToOtherClusters := func(n *node) bool {
+		if belongsToSelectedClusters(n) {
+			return false
+		}
+		for tenant := range n.tenant {
+			if tenant.identity.GroupVersionKind().GroupKind() == clusterGroupKind {
+				return true
+			}
+		}
+		return false
+	}
+
+	keep := map[*node]empty{}
+	var keepHierarchy func(n *node)
+	keepHierarchy = func(n *node) {
+		if _, ok := keep[n]; ok {
+			return
+		}
+		keep[n] = empty{}
+
+		// Keep the objects the node depends on, e.g. the ClusterClass used by a Cluster or the ClusterResourceSet a binding belongs to.
+		for owner := range n.owners {
+			if !belongsToOtherClusters(owner) {
+				keepHierarchy(owner)
+			}
+		}
+		for owner := range n.softOwners {
+			if !belongsToOtherClusters(owner) {
+				keepHierarchy(owner)
+			}
+		}
+
+		// If the node is the root of a hierarchy, e.g. a Cluster or a ClusterClass, keep all the objects in the hierarchy
+		// except the ones belonging only to other Clusters.
+		if n.forceMoveHierarchy {
+			for _, other := range o.getNodes() {
+				if _, ok := other.tenant[n]; ok && !belongsToOtherClusters(other) {
+					keepHierarchy(other)
+				}
+			}
+		}
+	}
+	for _, cluster := range clusters {
+		keepHierarchy(cluster)
+	}
+	for _, n := range o.getNodes() {
+		if n.forceMoveHierarchy && n.isGlobal {
+			keepHierarchy(n)
+		}
+	}
+
+	// If objects that would have been moved together with other Clusters depend on an object being moved,
+	// the object must be kept in the source management cluster, e.g. a ClusterClass used by Clusters not selected.
+	var setShouldNotDeleteShared func(n *node)
+	setShouldNotDeleteShared = func(n *node) {
+		n.shouldNotDelete = true
+		for _, other := range o.getNodes() {
+			// Ensure that also the objects owned by the shared object are not deleted, e.g. the templates of a ClusterClass,
+			// except the ones belonging to the selected Clusters, e.g. the ClusterResourceSetBinding for a selected Cluster.
+			if _, ok := keep[other]; ok && other.isOwnedBy(n) && !other.shouldNotDelete && !belongsToSelectedClusters(other) {
+				setShouldNotDeleteShared(other)
+			}
+		}
+	}
+	for _, n := range o.getNodes() {
+		if _, ok := keep[n]; ok || (len(n.tenant) == 0 && !n.forceMove) {
+			continue
+		}
+		for owner := range n.owners {
+			if _, ok := keep[owner]; ok {
+				setShouldNotDeleteShared(owner)
+			}
+		}
+		for owner := range n.softOwners {
+			if _, ok := keep[owner]; ok {
+				setShouldNotDeleteShared(owner)
+			}
+		}
+	}
+
+	// Remove the objects not selected from the graph, including all the references to them.
+	for uid, n := range o.uidToNode {
+		if _, ok := keep[n]; !ok {
+			delete(o.uidToNode, uid)
+		}
+	}
+	for _, n := range o.uidToNode {
+		for owner := range n.owners {
+			if _, ok := keep[owner]; !ok {
+				delete(n.owners, owner)
+			}
+		}
+		for owner := range n.softOwners {
+			if _, ok := keep[owner]; !ok {
+				delete(n.softOwners, owner)
+			}
+		}
+		for tenant := range n.tenant {
+			if _, ok := keep[tenant]; !ok {
+				delete(n.tenant, tenant)
+			}
+		}
+	}
+}
@@ -2761,6 +2761,174 @@ func Test_objectGraph_setGlobalIdentityTenants(t *testing.T) {
 	}
 }
 
+func Test_objectGraph_selectClusters(t *testing.T) {
+	type fields struct {
+		objs []client.Object
+	}
+	tests := []struct {
+		name                string
+		fields              fields
+		selectClusters      []string
+		wantNodes           []string
+		wantShouldNotDelete []string
+	}{
+		{
+			name: "A Cluster using a ClusterClass shared with another Cluster",
+			fields: fields{
+				objs: func() []client.Object {
+					objs := test.NewFakeClusterClass("ns1", "class1").Objs()
+					objs = append(objs, test.NewFakeCluster("ns1", "foo1").WithTopologyClass("class1").Objs()...)
+					objs = append(objs, test.NewFakeCluster("ns1", "foo2").WithTopologyClass("class1").Objs()...)
+					return deduplicateObjects(objs)
+				}(),
+			},
+			selectClusters: []string{
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/foo1",
+			},
+			wantNodes: []string{
+				"cluster.x-k8s.io/v1beta1, Kind=ClusterClass, ns1/class1",
+				"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureClusterTemplate, ns1/class1",
+				"controlplane.cluster.x-k8s.io/v1beta1, Kind=GenericControlPlaneTemplate, ns1/class1",
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/foo1",
+				"/v1, Kind=Secret, ns1/foo1-ca",
+				"/v1, Kind=Secret, ns1/foo1-kubeconfig",
+				"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureCluster, ns1/foo1",
+			},
+			wantShouldNotDelete: []string{ // the ClusterClass is still used by foo2
+				"cluster.x-k8s.io/v1beta1, Kind=ClusterClass, ns1/class1",
+				"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureClusterTemplate, ns1/class1",
+				"controlplane.cluster.x-k8s.io/v1beta1, Kind=GenericControlPlaneTemplate, ns1/class1",
+			},
+		},
+		{
+			name: "A Cluster using a ClusterClass not used by other Clusters",
+			fields: fields{
+				objs: func() []client.Object {
+					objs := test.NewFakeClusterClass("ns1", "class1").Objs()
+					objs = append(objs, test.NewFakeClusterClass("ns1", "class2").Objs()...)
+					objs = append(objs, test.NewFakeCluster("ns1", "foo1").WithTopologyClass("class1").Objs()...)
+					objs = append(objs, test.NewFakeCluster("ns1", "foo2").WithTopologyClass("class2").Objs()...)
+					return deduplicateObjects(objs)
+				}(),
+			},
+			selectClusters: []string{
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/foo1",
+			},
+			wantNodes: []string{
+				"cluster.x-k8s.io/v1beta1, Kind=ClusterClass, ns1/class1",
+				"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureClusterTemplate, ns1/class1",
+				"controlplane.cluster.x-k8s.io/v1beta1, Kind=GenericControlPlaneTemplate, ns1/class1",
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/foo1",
+				"/v1, Kind=Secret, ns1/foo1-ca",
+				"/v1, Kind=Secret, ns1/foo1-kubeconfig",
+				"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureCluster, ns1/foo1",
+			},
+			wantShouldNotDelete: []string{},
+		},
+		{
+			name: "A ClusterResourceSet applied to two Clusters",
+			fields: fields{
+				objs: func() []client.Object {
+					objs := []client.Object{}
+					objs = append(objs, test.NewFakeCluster("ns1", "cluster1").Objs()...)
+					objs = append(objs, test.NewFakeCluster("ns1", "cluster2").Objs()...)
+
+					objs = append(objs, test.NewFakeClusterResourceSet("ns1", "crs1").
+						WithSecret("resource-s1").
+						WithConfigMap("resource-c1").
+						ApplyToCluster(test.SelectClusterObj(objs, "ns1", "cluster1")).
+						ApplyToCluster(test.SelectClusterObj(objs, "ns1", "cluster2")).
+						Objs()...)
+
+					return objs
+				}(),
+			},
+			selectClusters: []string{
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/cluster1",
+			},
+			wantNodes: []string{
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/cluster1",
+				"/v1, Kind=Secret, ns1/cluster1-ca",
+				"/v1, Kind=Secret, ns1/cluster1-kubeconfig",
+				"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureCluster, ns1/cluster1",
+				"addons.cluster.x-k8s.io/v1beta1, Kind=ClusterResourceSet, ns1/crs1",
+				"addons.cluster.x-k8s.io/v1beta1, Kind=ClusterResourceSetBinding, ns1/cluster1",
+				"/v1, Kind=Secret, ns1/resource-s1",
+				"/v1, Kind=ConfigMap, ns1/resource-c1",
+			},
+			wantShouldNotDelete: []string{ // the ClusterResourceSet is still applied to cluster2
+				"addons.cluster.x-k8s.io/v1beta1, Kind=ClusterResourceSet, ns1/crs1",
+				"/v1, Kind=Secret, ns1/resource-s1",
+				"/v1, Kind=ConfigMap, ns1/resource-c1",
+			},
+		},
+		{
+			name: "Global identities are always selected, objects not linked to the selected Clusters are not",
+			fields: fields{
+				objs: func() []client.Object {
+					objs := []client.Object{}
+					objs = append(objs, test.NewFakeCluster("ns1", "cluster1").Objs()...)
+					objs = append(objs, test.NewFakeCluster("ns1", "cluster2").Objs()...)
+					objs = append(objs, test.NewFakeClusterInfrastructureIdentity("infra1-identity").WithSecretIn("infra1-system").Objs()...)
+					objs = append(objs, test.NewFakeExternalObject("ns1", "externalObject1").Objs()...)
+					return objs
+				}(),
+			},
+			selectClusters: []string{
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/cluster1",
+			},
+			wantNodes: []string{
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/cluster1",
+				"/v1, Kind=Secret, ns1/cluster1-ca",
+				"/v1, Kind=Secret, ns1/cluster1-kubeconfig",
+				"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureCluster, ns1/cluster1",
+				"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericClusterInfrastructureIdentity, infra1-identity",
+				"/v1, Kind=Secret, infra1-system/infra1-identity-credentials",
+			},
+			wantShouldNotDelete: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ctx := context.Background()
+
+			graph := getObjectGraphWithObjs(tt.fields.objs)
+			g.Expect(graph.getDiscoveryTypes(ctx)).To(Succeed())
+			g.Expect(graph.Discovery(ctx, "")).To(Succeed())
+
+			clusters := []*node{}
+			for _, uid := range tt.selectClusters {
+				clusters = append(clusters, graph.uidToNode[types.UID(uid)])
+			}
+			graph.selectClusters(clusters)
+
+			gotNodes := []string{}
+			gotShouldNotDelete := []string{}
+			for _, n := range graph.getMoveNodes() {
+				gotNodes = append(gotNodes, string(n.identity.UID))
+				if n.shouldNotDelete {
+					gotShouldNotDelete = append(gotShouldNotDelete, string(n.identity.UID))
+				}
+
+				// Nodes must not be linked to nodes removed from the graph.
+				for owner := range n.owners {
+					g.Expect(graph.uidToNode).To(HaveKey(owner.identity.UID))
+				}
+				for owner := range n.softOwners {
+					g.Expect(graph.uidToNode).To(HaveKey(owner.identity.UID))
+				}
+				for tenant := range n.tenant {
+					g.Expect(graph.uidToNode).To(HaveKey(tenant.identity.UID))
+				}
+			}
+			g.Expect(gotNodes).To(ConsistOf(tt.wantNodes))
+			g.Expect(gotShouldNotDelete).To(ConsistOf(tt.wantShouldNotDelete))
+		})
+	}
+}
+
 func deduplicateObjects(objs []client.Object) []client.Object {
 	res := []client.Object{}
 	uniqueObjectKeys := sets.Set[string]{}
@@ -47,18 +47,31 @@ type ProviderUpgrader interface {
 
 	// ApplyCustomPlan plan executes an upgrade using the UpgradeItems provided by the user.
 	ApplyCustomPlan(ctx context.Context, opts UpgradeOptions, providersToUpgrade ...UpgradeItem) error
+
+	// Diff returns the changes to the provider components that upgrading a provider to the next version would apply.
+	Diff(ctx context.Context, upgradeItem UpgradeItem) (*ProviderDiff, error)
 }
 
 // UpgradePlan defines a list of possible upgrade targets for a management cluster.
 type UpgradePlan struct {
 	Contract  string
 	Providers []UpgradeItem
+
+	// Diffs holds the changes to the provider components that the upgrade would apply, one for each provider.
+	// NOTE: Diffs are computed only if explicitly requested.
+	Diffs []ProviderDiff
 }
 
 // UpgradeOptions defines the options used to upgrade installation.
 type UpgradeOptions struct {
 	WaitProviders       bool
 	WaitProviderTimeout time.Duration
+
+	// CRDMigrationConcurrency is the number of CRs migrated concurrently to the storage version of new CRDs.
+	CRDMigrationConcurrency int
+
+	// DryRun reports the CRs to be migrated to the storage version of new CRDs, without changing the management cluster.
+	DryRun bool
 }
 
 // isPartialUpgrade returns true if at least one upgradeItem in the plan does not have a target version.
@@ -383,11 +396,24 @@ func (u *providerUpgrader) doUpgrade(ctx context.Context, upgradePlan *UpgradePl
 			return err
 		}
 
-		if err := NewCRDMigrator(c).Run(ctx, components.Objs()); err != nil {
+		crdMigrator := NewCRDMigrator(c, WithCRDMigrationConcurrency(opts.CRDMigrationConcurrency))
+		if opts.DryRun {
+			// Plan logs the CR migrations required for the provider.
+			if _, err := crdMigrator.Plan(ctx, components.Objs()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := crdMigrator.Run(ctx, components.Objs()); err != nil {
 			return err
 		}
 	}
 
+	if opts.DryRun {
+		return nil
+	}
+
 	// Scale down all providers.
 	// This is done to ensure all Pods of all "old" provider Deployments have been deleted.
 	// Otherwise it can happen that a provider Pod survives the upgrade because we create
@@ -448,7 +474,7 @@ func (u *providerUpgrader) doUpgrade(ctx context.Context, upgradePlan *UpgradePl
 		}
 	}
 
-	return waitForProvidersReady(ctx, InstallOptions(opts), installQueue, u.proxy)
+	return waitForProvidersReady(ctx, InstallOptions{WaitProviders: opts.WaitProviders, WaitProviderTimeout: opts.WaitProviderTimeout}, installQueue, u.proxy)
 }
 
 func (u *providerUpgrader) scaleDownProvider(ctx context.Context, provider clusterctlv1.Provider) error {
@@ -0,0 +1,612 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
+)
+
+const (
+	clusterRoleKind = "ClusterRole"
+	roleKind        = "Role"
+	deploymentKind  = "Deployment"
+)
+
+// ObjectDiffOperation defines the type of change to an object of the provider components.
+type ObjectDiffOperation string
+
+const (
+	// ObjectAdded is used for objects that exist only in the next version of the provider components.
+	ObjectAdded ObjectDiffOperation = "Added"
+
+	// ObjectRemoved is used for objects that exist only in the installed provider components.
+	ObjectRemoved ObjectDiffOperation = "Removed"
+
+	// ObjectChanged is used for objects that exist both in the installed and in the next version of the provider components.
+	ObjectChanged ObjectDiffOperation = "Changed"
+)
+
+// ObjectDiff describes the changes to an object of the provider components.
+type ObjectDiff struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Operation ObjectDiffOperation
+
+	// Changes is a list of human-readable descriptions of the changes; it is set only for changed objects.
+	Changes []string
+}
+
+// VariableDiff describes a variable required by the next version of the provider components
+// that is not used by the installed version.
+type VariableDiff struct {
+	Name string
+
+	// Set is true if a value for the variable is defined in the clusterctl configuration or in the environment.
+	Set bool
+}
+
+// ProviderDiff describes what would change in the management cluster when upgrading a provider to the next version.
+type ProviderDiff struct {
+	UpgradeItem
+
+	// CustomResourceDefinitions lists changes to the provider's CRDs, e.g. API versions or schema fields added or removed.
+	CustomResourceDefinitions []ObjectDiff
+
+	// RBAC lists changes to the permissions granted by the provider's ClusterRoles and Roles.
+	RBAC []ObjectDiff
+
+	// Deployments lists changes to the provider's Deployments, e.g. images, args or env variables.
+	Deployments []ObjectDiff
+
+	// Variables lists the variables without a default value that are required by the next version only.
+	Variables []VariableDiff
+}
+
+// IsEmpty returns true if the upgrade does not change any of the objects and variables considered by the diff.
+func (d *ProviderDiff) IsEmpty() bool {
+	return len(d.CustomResourceDefinitions) == 0 && len(d.RBAC) == 0 && len(d.Deployments) == 0 && len(d.Variables) == 0
+}
+
+// Diff computes the changes between the components of a provider installed in the management cluster
+// and the components of the next version of the same provider.
+// NOTE: Only CRDs, ClusterRoles, Roles and Deployments are compared; other objects are replaced during the upgrade as well.
+func (u *providerUpgrader) Diff(ctx context.Context, upgradeItem UpgradeItem) (*ProviderDiff, error) {
+	diff := &ProviderDiff{UpgradeItem: upgradeItem}
+
+	// If there is not a specified next version, there is nothing to compare (we are already up-to-date).
+	if upgradeItem.NextVersion == "" {
+		return diff, nil
+	}
+
+	configRepository, err := u.configClient.Providers().Get(upgradeItem.ProviderName, upgradeItem.GetProviderType())
+	if err != nil {
+		return nil, err
+	}
+
+	providerRepository, err := u.repositoryClientFactory(ctx, configRepository, u.configClient)
+	if err != nil {
+		return nil, err
+	}
+
+	// Gets the raw components for the current and the next version, so variables can be compared
+	// even if the value of the newly required variables is not set yet.
+	currentComponents, err := providerRepository.Components().Get(ctx, repository.ComponentsOptions{
+		Version:             upgradeItem.Version,
+		TargetNamespace:     upgradeItem.Namespace,
+		SkipTemplateProcess: true,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the components of the %s provider, version %s", upgradeItem.InstanceName(), upgradeItem.Version)
+	}
+
+	rawNextComponents, err := providerRepository.Components().Get(ctx, repository.ComponentsOptions{
+		Version:             upgradeItem.NextVersion,
+		TargetNamespace:     upgradeItem.Namespace,
+		SkipTemplateProcess: true,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the components of the %s provider, version %s", upgradeItem.InstanceName(), upgradeItem.NextVersion)
+	}
+
+	diff.Variables = u.diffVariables(currentComponents, rawNextComponents)
+
+	// Gets the components for the next version the same way upgrade apply does, so objects can be compared
+	// with the installed ones; if some of the variables are not set, the raw components are used instead
+	// (those variables are reported in the diff).
+	nextObjs := rawNextComponents.Objs()
+	if nextComponents, err := providerRepository.Components().Get(ctx, repository.ComponentsOptions{
+		Version:         upgradeItem.NextVersion,
+		TargetNamespace: upgradeItem.Namespace,
+	}); err == nil {
+		nextObjs = nextComponents.Objs()
+	}
+
+	installed, err := u.getInstalledObjects(ctx, upgradeItem.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := newDiffObjects(nextObjs)
+	if err != nil {
+		return nil, err
+	}
+
+	diff.CustomResourceDefinitions = diffObjects(customResourceDefinitionKind, installed.crds, next.crds, crdChanges)
+	diff.RBAC = append(
+		diffObjects(clusterRoleKind, installed.clusterRoles, next.clusterRoles, clusterRoleChanges),
+		diffObjects(roleKind, installed.roles, next.roles, roleChanges)...,
+	)
+	diff.Deployments = diffObjects(deploymentKind, installed.deployments, next.deployments, deploymentChanges)
+
+	return diff, nil
+}
+
+// diffVariables returns the variables without a default value that are used by the next components only.
+func (u *providerUpgrader) diffVariables(current, next repository.Components) []VariableDiff {
+	ret := []VariableDiff{}
+	for name, defaultValue := range next.VariableMap() {
+		if defaultValue != nil {
+			continue
+		}
+		if _, ok := current.VariableMap()[name]; ok {
+			continue
+		}
+		_, err := u.configClient.Variables().Get(name)
+		ret = append(ret, VariableDiff{Name: name, Set: err == nil})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
+
+// diffObjectSet holds the objects of the provider components considered by the diff, indexed by namespace/name.
+type diffObjectSet struct {
+	crds         map[client.ObjectKey]*apiextensionsv1.CustomResourceDefinition
+	clusterRoles map[client.ObjectKey]*rbacv1.ClusterRole
+	roles        map[client.ObjectKey]*rbacv1.Role
+	deployments  map[client.ObjectKey]*appsv1.Deployment
+}
+
+func newDiffObjectSet() *diffObjectSet {
+	return &diffObjectSet{
+		crds:         map[client.ObjectKey]*apiextensionsv1.CustomResourceDefinition{},
+		clusterRoles: map[client.ObjectKey]*rbacv1.ClusterRole{},
+		roles:        map[client.ObjectKey]*rbacv1.Role{},
+		deployments:  map[client.ObjectKey]*appsv1.Deployment{},
+	}
+}
+
+// newDiffObjects converts the objects of the provider components considered by the diff to typed objects.
+func newDiffObjects(objs []unstructured.Unstructured) (*diffObjectSet, error) {
+	ret := newDiffObjectSet()
+	for i := range objs {
+		obj := &objs[i]
+
+		var typedObj client.Object
+		switch obj.GroupVersionKind().GroupKind() {
+		case apiextensionsv1.SchemeGroupVersion.WithKind(customResourceDefinitionKind).GroupKind():
+			crd := &apiextensionsv1.CustomResourceDefinition{}
+			ret.crds[client.ObjectKeyFromObject(obj)] = crd
+			typedObj = crd
+		case rbacv1.SchemeGroupVersion.WithKind(clusterRoleKind).GroupKind():
+			clusterRole := &rbacv1.ClusterRole{}
+			ret.clusterRoles[client.ObjectKeyFromObject(obj)] = clusterRole
+			typedObj = clusterRole
+		case rbacv1.SchemeGroupVersion.WithKind(roleKind).GroupKind():
+			role := &rbacv1.Role{}
+			ret.roles[client.ObjectKeyFromObject(obj)] = role
+			typedObj = role
+		case appsv1.SchemeGroupVersion.WithKind(deploymentKind).GroupKind():
+			deployment := &appsv1.Deployment{}
+			ret.deployments[client.ObjectKeyFromObject(obj)] = deployment
+			typedObj = deployment
+		default:
+			continue
+		}
+
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), typedObj); err != nil {
+			return nil, errors.Wrapf(err, "failed to convert %s %s", obj.GetKind(), client.ObjectKeyFromObject(obj))
+		}
+	}
+	return ret, nil
+}
+
+// getInstalledObjects returns the objects of the provider components installed in the management cluster that are considered by the diff.
+func (u *providerUpgrader) getInstalledObjects(ctx context.Context, provider clusterctlv1.Provider) (*diffObjectSet, error) {
+	c, err := u.proxy.NewClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := client.MatchingLabels{
+		clusterctlv1.ClusterctlLabel: "",
+		clusterv1.ProviderNameLabel:  provider.ManifestLabel(),
+	}
+
+	ret := newDiffObjectSet()
+
+	crdList := &apiextensionsv1.CustomResourceDefinitionList{}
+	if err := c.List(ctx, crdList, labels); err != nil {
+		return nil, errors.Wrapf(err, "failed to list CustomResourceDefinitions for provider %s", provider.InstanceName())
+	}
+	for i := range crdList.Items {
+		ret.crds[client.ObjectKeyFromObject(&crdList.Items[i])] = &crdList.Items[i]
+	}
+
+	clusterRoleList := &rbacv1.ClusterRoleList{}
+	if err := c.List(ctx, clusterRoleList, labels); err != nil {
+		return nil, errors.Wrapf(err, "failed to list ClusterRoles for provider %s", provider.InstanceName())
+	}
+	for i := range clusterRoleList.Items {
+		ret.clusterRoles[client.ObjectKeyFromObject(&clusterRoleList.Items[i])] = &clusterRoleList.Items[i]
+	}
+
+	roleList := &rbacv1.RoleList{}
+	if err := c.List(ctx, roleList, labels, client.InNamespace(provider.Namespace)); err != nil {
+		return nil, errors.Wrapf(err, "failed to list Roles for provider %s", provider.InstanceName())
+	}
+	for i := range roleList.Items {
+		ret.roles[client.ObjectKeyFromObject(&roleList.Items[i])] = &roleList.Items[i]
+	}
+
+	deploymentList := &appsv1.DeploymentList{}
+	if err := c.List(ctx, deploymentList, labels, client.InNamespace(provider.Namespace)); err != nil {
+		return nil, errors.Wrapf(err, "failed to list Deployments for provider %s", provider.InstanceName())
+	}
+	for i := range deploymentList.Items {
+		ret.deployments[client.ObjectKeyFromObject(&deploymentList.Items[i])] = &deploymentList.Items[i]
+	}
+
+	return ret, nil
+}
+
+// diffObjects compares the installed and the next version of the objects of a kind, and returns the objects
+// added, removed or changed, sorted by namespace and name.
+func diffObjects[T any](kind string, installed, next map[client.ObjectKey]T, changes func(installed, next T) []string) []ObjectDiff {
+	keys := sets.New[client.ObjectKey]()
+	for key := range installed {
+		keys.Insert(key)
+	}
+	for key := range next {
+		keys.Insert(key)
+	}
+	sortedKeys := keys.UnsortedList()
+	sort.Slice(sortedKeys, func(i, j int) bool {
+		if sortedKeys[i].Namespace != sortedKeys[j].Namespace {
+			return sortedKeys[i].Namespace < sortedKeys[j].Namespace
+		}
+		return sortedKeys[i].Name < sortedKeys[j].Name
+	})
+
+	ret := []ObjectDiff{}
+	for _, key := range sortedKeys {
+		objDiff := ObjectDiff{Kind: kind, Namespace: key.Namespace, Name: key.Name}
+
+		installedObj, isInstalled := installed[key]
+		nextObj, isNext := next[key]
+		switch {
+		case !isInstalled:
+			objDiff.Operation = ObjectAdded
+		case !isNext:
+			objDiff.Operation = ObjectRemoved
+		default:
+			objDiff.Changes = changes(installedObj, nextObj)
+			if len(objDiff.Changes) == 0 {
+				continue
+			}
+			objDiff.Operation = ObjectChanged
+		}
+		ret = append(ret, objDiff)
+	}
+	return ret
+}
+
+// crdChanges returns the changes to the API versions of a CRD and to the schema of the API versions existing in both the installed and the next CRD.
+func crdChanges(installed, next *apiextensionsv1.CustomResourceDefinition) []string {
+	changes := []string{}
+
+	installedVersions := map[string]*apiextensionsv1.CustomResourceDefinitionVersion{}
+	installedStorageVersion := ""
+	for i := range installed.Spec.Versions {
+		v := &installed.Spec.Versions[i]
+		installedVersions[v.Name] = v
+		if v.Storage {
+			installedStorageVersion = v.Name
+		}
+	}
+
+	nextVersions := sets.Set[string]{}
+	nextStorageVersion := ""
+	for i := range next.Spec.Versions {
+		v := &next.Spec.Versions[i]
+		nextVersions.Insert(v.Name)
+		if v.Storage {
+			nextStorageVersion = v.Name
+		}
+
+		installedVersion, ok := installedVersions[v.Name]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("version %s added", v.Name))
+			continue
+		}
+
+		if installedVersion.Served && !v.Served {
+			changes = append(changes, fmt.Sprintf("version %s is no longer served", v.Name))
+		}
+		if !installedVersion.Served && v.Served {
+			changes = append(changes, fmt.Sprintf("version %s is now served", v.Name))
+		}
+		if !installedVersion.Deprecated && v.Deprecated {
+			changes = append(changes, fmt.Sprintf("version %s is now deprecated", v.Name))
+		}
+
+		for _, change := range schemaChanges(installedVersion.Schema, v.Schema) {
+			changes = append(changes, fmt.Sprintf("version %s: %s", v.Name, change))
+		}
+	}
+
+	for i := range installed.Spec.Versions {
+		if !nextVersions.Has(installed.Spec.Versions[i].Name) {
+			changes = append(changes, fmt.Sprintf("version %s removed", installed.Spec.Versions[i].Name))
+		}
+	}
+
+	if installedStorageVersion != nextStorageVersion {
+		changes = append(changes, fmt.Sprintf("storage version changed from %s to %s", installedStorageVersion, nextStorageVersion))
+	}
+
+	return changes
+}
+
+// schemaChanges returns the fields added, removed, changing type or becoming required between two versions of a CRD schema.
+func schemaChanges(installed, next *apiextensionsv1.CustomResourceValidation) []string {
+	installedFields, installedRequired := schemaFields(installed)
+	nextFields, nextRequired := schemaFields(next)
+
+	paths := sets.New[string]()
+	for path := range installedFields {
+		paths.Insert(path)
+	}
+	for path := range nextFields {
+		paths.Insert(path)
+	}
+
+	changes := []string{}
+	for _, path := range sets.List(paths) {
+		installedType, isInstalled := installedFields[path]
+		nextType, isNext := nextFields[path]
+		switch {
+		case !isInstalled:
+			changes = append(changes, fmt.Sprintf("field %s added", path))
+		case !isNext:
+			changes = append(changes, fmt.Sprintf("field %s removed", path))
+		case installedType != nextType:
+			changes = append(changes, fmt.Sprintf("field %s type changed from %s to %s", path, installedType, nextType))
+		}
+		if isNext && nextRequired.Has(path) && !installedRequired.Has(path) {
+			changes = append(changes, fmt.Sprintf("field %s is now required", path))
+		}
+	}
+	return changes
+}
+
+// schemaFields returns the type of all the fields defined in a CRD schema, indexed by path (e.g. spec.template.spec),
+// and the paths of the required fields.
+func schemaFields(validation *apiextensionsv1.CustomResourceValidation) (map[string]string, sets.Set[string]) {
+	fields := map[string]string{}
+	required := sets.Set[string]{}
+	if validation == nil || validation.OpenAPIV3Schema == nil {
+		return fields, required
+	}
+
+	var walk func(path string, schema *apiextensionsv1.JSONSchemaProps)
+	walk = func(path string, schema *apiextensionsv1.JSONSchemaProps) {
+		if path != "" {
+			fields[path] = schema.Type
+		}
+		for _, name := range schema.Required {
+			required.Insert(joinSchemaPath(path, name))
+		}
+		for name, property := range schema.Properties {
+			walk(joinSchemaPath(path, name), &property)
+		}
+		if schema.Items != nil && schema.Items.Schema != nil {
+			walk(path+"[*]", schema.Items.Schema)
+		}
+		if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+			walk(joinSchemaPath(path, "*"), schema.AdditionalProperties.Schema)
+		}
+	}
+	walk("", validation.OpenAPIV3Schema)
+
+	return fields, required
+}
+
+func joinSchemaPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func clusterRoleChanges(installed, next *rbacv1.ClusterRole) []string {
+	return policyRuleChanges(installed.Rules, next.Rules)
+}
+
+func roleChanges(installed, next *rbacv1.Role) []string {
+	return policyRuleChanges(installed.Rules, next.Rules)
+}
+
+// policyRuleChanges returns the permissions granted or revoked by a change to a set of policy rules.
+// NOTE: Rules are expanded to single permissions, so rules can be reorganized without being reported as changed.
+func policyRuleChanges(installed, next []rbacv1.PolicyRule) []string {
+	installedPermissions := policyRulePermissions(installed)
+	nextPermissions := policyRulePermissions(next)
+
+	changes := []string{}
+	for _, permission := range sets.List(nextPermissions.Difference(installedPermissions)) {
+		changes = append(changes, fmt.Sprintf("granted %s", permission))
+	}
+	for _, permission := range sets.List(installedPermissions.Difference(nextPermissions)) {
+		changes = append(changes, fmt.Sprintf("revoked %s", permission))
+	}
+	return changes
+}
+
+// policyRulePermissions returns the single permissions granted by a set of policy rules, e.g. "get deployments.apps".
+func policyRulePermissions(rules []rbacv1.PolicyRule) sets.Set[string] {
+	permissions := sets.Set[string]{}
+	for _, rule := range rules {
+		for _, verb := range rule.Verbs {
+			for _, group := range rule.APIGroups {
+				for _, resource := range rule.Resources {
+					if group != "" {
+						resource = fmt.Sprintf("%s.%s", resource, group)
+					}
+					if len(rule.ResourceNames) == 0 {
+						permissions.Insert(fmt.Sprintf("%s %s", verb, resource))
+						continue
+					}
+					for _, name := range rule.ResourceNames {
+						permissions.Insert(fmt.Sprintf("%s %s/%s", verb, resource, name))
+					}
+				}
+			}
+			for _, url := range rule.NonResourceURLs {
+				permissions.Insert(fmt.Sprintf("%s %s", verb, url))
+			}
+		}
+	}
+	return permissions
+}
+
+// deploymentChanges returns the changes to the images, args and env variables of the containers of a Deployment.
+func deploymentChanges(installed, next *appsv1.Deployment) []string {
+	changes := []string{}
+
+	installedContainers := map[string]*corev1.Container{}
+	for i := range installed.Spec.Template.Spec.Containers {
+		installedContainers[installed.Spec.Template.Spec.Containers[i].Name] = &installed.Spec.Template.Spec.Containers[i]
+	}
+
+	nextContainers := sets.Set[string]{}
+	for i := range next.Spec.Template.Spec.Containers {
+		nextContainer := &next.Spec.Template.Spec.Containers[i]
+		nextContainers.Insert(nextContainer.Name)
+
+		installedContainer, ok := installedContainers[nextContainer.Name]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("container %s added", nextContainer.Name))
+			continue
+		}
+		for _, change := range containerChanges(installedContainer, nextContainer) {
+			changes = append(changes, fmt.Sprintf("container %s: %s", nextContainer.Name, change))
+		}
+	}
+
+	for i := range installed.Spec.Template.Spec.Containers {
+		if !nextContainers.Has(installed.Spec.Template.Spec.Containers[i].Name) {
+			changes = append(changes, fmt.Sprintf("container %s removed", installed.Spec.Template.Spec.Containers[i].Name))
+		}
+	}
+
+	return changes
+}
+
+func containerChanges(installed, next *corev1.Container) []string {
+	changes := []string{}
+
+	if installed.Image != next.Image {
+		changes = append(changes, fmt.Sprintf("image changed from %s to %s", installed.Image, next.Image))
+	}
+
+	for _, arg := range next.Args {
+		if !slices.Contains(installed.Args, arg) {
+			changes = append(changes, fmt.Sprintf("arg %s added", arg))
+		}
+	}
+	for _, arg := range installed.Args {
+		if !slices.Contains(next.Args, arg) {
+			changes = append(changes, fmt.Sprintf("arg %s removed", arg))
+		}
+	}
+
+	installedEnv := map[string]string{}
+	for _, env := range installed.Env {
+		installedEnv[env.Name] = envVarValue(env)
+	}
+	nextEnv := sets.Set[string]{}
+	for _, env := range next.Env {
+		nextEnv.Insert(env.Name)
+		installedValue, ok := installedEnv[env.Name]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("env %s added", env.Name))
+		case installedValue != envVarValue(env):
+			changes = append(changes, fmt.Sprintf("env %s changed from %q to %q", env.Name, installedValue, envVarValue(env)))
+		}
+	}
+	for _, env := range installed.Env {
+		if !nextEnv.Has(env.Name) {
+			changes = append(changes, fmt.Sprintf("env %s removed", env.Name))
+		}
+	}
+
+	return changes
+}
+
+// envVarValue returns the value of an env variable, or a description of its source.
+// NOTE: Defaulted fields of the source, e.g. the API version of a field reference, are not considered.
+func envVarValue(env corev1.EnvVar) string {
+	if env.ValueFrom == nil {
+		return env.Value
+	}
+
+	source := env.ValueFrom
+	switch {
+	case source.FieldRef != nil:
+		return fmt.Sprintf("fieldRef(%s)", source.FieldRef.FieldPath)
+	case source.ResourceFieldRef != nil:
+		return fmt.Sprintf("resourceFieldRef(%s)", source.ResourceFieldRef.Resource)
+	case source.ConfigMapKeyRef != nil:
+		return fmt.Sprintf("configMapKeyRef(%s/%s)", source.ConfigMapKeyRef.Name, source.ConfigMapKeyRef.Key)
+	case source.SecretKeyRef != nil:
+		return fmt.Sprintf("secretKeyRef(%s/%s)", source.SecretKeyRef.Name, source.SecretKeyRef.Key)
+	default:
+		return strings.TrimSpace(source.String())
+	}
+}
@@ -0,0 +1,417 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+var diffComponentsV1 = []byte(`apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: foos.infrastructure.cluster.x-k8s.io
+spec:
+  group: infrastructure.cluster.x-k8s.io
+  names:
+    kind: Foo
+    listKind: FooList
+    plural: foos
+    singular: foo
+  scope: Namespaced
+  versions:
+  - name: v1beta1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            properties:
+              region:
+                type: string
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: manager-role
+rules:
+- apiGroups: [""]
+  resources: ["secrets"]
+  verbs: ["get", "list"]
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: controller-manager
+  namespace: infra-system
+spec:
+  selector:
+    matchLabels:
+      app: manager
+  template:
+    metadata:
+      labels:
+        app: manager
+    spec:
+      containers:
+      - name: manager
+        image: registry.k8s.io/infra:v1.0.0
+        args: ["--leader-elect"]
+        env:
+        - name: REGION
+          value: ${REGION}
+`)
+
+var diffComponentsV2 = []byte(`apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: foos.infrastructure.cluster.x-k8s.io
+spec:
+  group: infrastructure.cluster.x-k8s.io
+  names:
+    kind: Foo
+    listKind: FooList
+    plural: foos
+    singular: foo
+  scope: Namespaced
+  versions:
+  - name: v1beta1
+    served: true
+    storage: false
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            required: ["zone"]
+            properties:
+              region:
+                type: string
+              zone:
+                type: string
+  - name: v1beta2
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: manager-role
+rules:
+- apiGroups: [""]
+  resources: ["secrets"]
+  verbs: ["get", "list", "delete"]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: leader-election-role
+  namespace: infra-system
+rules:
+- apiGroups: ["coordination.k8s.io"]
+  resources: ["leases"]
+  verbs: ["get"]
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: controller-manager
+  namespace: infra-system
+spec:
+  selector:
+    matchLabels:
+      app: manager
+  template:
+    metadata:
+      labels:
+        app: manager
+    spec:
+      containers:
+      - name: manager
+        image: registry.k8s.io/infra:v1.0.1
+        args: ["--leader-elect", "--feature-gates=Foo=${FOO_FEATURE:=false}"]
+        env:
+        - name: REGION
+          value: ${REGION}
+        - name: TOKEN
+          value: ${TOKEN}
+`)
+
+func Test_providerUpgrader_Diff(t *testing.T) {
+	g := NewWithT(t)
+
+	ctx := context.Background()
+
+	configClient, err := config.New(ctx, "", config.InjectReader(test.NewFakeReader().
+		WithProvider("infra", clusterctlv1.InfrastructureProviderType, "https://somewhere.com").
+		WithVar("REGION", "eu")))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	repositoryClientFactory := func(ctx context.Context, provider config.Provider, configClient config.Client, _ ...repository.Option) (repository.Client, error) {
+		return repository.New(ctx, provider, configClient, repository.InjectRepository(repository.NewMemoryRepository().
+			WithPaths("root", "components.yaml").
+			WithDefaultVersion("v1.0.0").
+			WithFile("v1.0.0", "components.yaml", diffComponentsV1).
+			WithFile("v1.0.1", "components.yaml", diffComponentsV2)))
+	}
+
+	// Install the current version of the provider components.
+	providerConfig, err := configClient.Providers().Get("infra", clusterctlv1.InfrastructureProviderType)
+	g.Expect(err).ToNot(HaveOccurred())
+	repositoryClient, err := repositoryClientFactory(ctx, providerConfig, configClient)
+	g.Expect(err).ToNot(HaveOccurred())
+	installedComponents, err := repositoryClient.Components().Get(ctx, repository.ComponentsOptions{Version: "v1.0.0", TargetNamespace: "infra-system"})
+	g.Expect(err).ToNot(HaveOccurred())
+	installedObjs := []client.Object{}
+	for i := range installedComponents.Objs() {
+		// The Namespace is not relevant for the diff.
+		if installedComponents.Objs()[i].GetKind() == namespaceKind {
+			continue
+		}
+		installedObjs = append(installedObjs, &installedComponents.Objs()[i])
+	}
+
+	u := &providerUpgrader{
+		configClient:            configClient,
+		proxy:                   test.NewFakeProxy().WithObjs(installedObjs...),
+		repositoryClientFactory: repositoryClientFactory,
+	}
+
+	diff, err := u.Diff(ctx, UpgradeItem{
+		Provider:    fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v1.0.0", "infra-system"),
+		NextVersion: "v1.0.1",
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(diff.CustomResourceDefinitions).To(ConsistOf(ObjectDiff{
+		Kind:      "CustomResourceDefinition",
+		Name:      "foos.infrastructure.cluster.x-k8s.io",
+		Operation: ObjectChanged,
+		Changes: []string{
+			"version v1beta1: field spec.zone added",
+			"version v1beta1: field spec.zone is now required",
+			"version v1beta2 added",
+			"storage version changed from v1beta1 to v1beta2",
+		},
+	}))
+	g.Expect(diff.RBAC).To(ConsistOf(
+		HaveField("Operation", ObjectChanged),
+		ObjectDiff{Kind: "Role", Namespace: "infra-system", Name: "leader-election-role", Operation: ObjectAdded},
+	))
+	g.Expect(diff.RBAC[0].Changes).To(Equal([]string{"granted delete secrets"}))
+
+	// The next version requires a variable which is not set, so the diff is computed on the raw components.
+	g.Expect(diff.Deployments).To(ConsistOf(ObjectDiff{
+		Kind:      "Deployment",
+		Namespace: "infra-system",
+		Name:      "controller-manager",
+		Operation: ObjectChanged,
+		Changes: []string{
+			"container manager: image changed from registry.k8s.io/infra:v1.0.0 to registry.k8s.io/infra:v1.0.1",
+			"container manager: arg --feature-gates=Foo=${FOO_FEATURE:=false} added",
+			`container manager: env REGION changed from "eu" to "${REGION}"`,
+			"container manager: env TOKEN added",
+		},
+	}))
+
+	// Variables with a default value are not reported.
+	g.Expect(diff.Variables).To(Equal([]VariableDiff{{Name: "TOKEN", Set: false}}))
+	g.Expect(diff.IsEmpty()).To(BeFalse())
+
+	// When all the variables are set, the diff is computed on the processed components.
+	configClient.Variables().Set("TOKEN", "secret")
+
+	diff, err = u.Diff(ctx, UpgradeItem{
+		Provider:    fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v1.0.0", "infra-system"),
+		NextVersion: "v1.0.1",
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(diff.Deployments).To(ConsistOf(HaveField("Changes", []string{
+		"container manager: image changed from registry.k8s.io/infra:v1.0.0 to registry.k8s.io/infra:v1.0.1",
+		"container manager: arg --feature-gates=Foo=false added",
+		"container manager: env TOKEN added",
+	})))
+	g.Expect(diff.Variables).To(Equal([]VariableDiff{{Name: "TOKEN", Set: true}}))
+}
+
+func Test_providerUpgrader_Diff_noNextVersion(t *testing.T) {
+	g := NewWithT(t)
+
+	u := &providerUpgrader{}
+	diff, err := u.Diff(context.Background(), UpgradeItem{
+		Provider: fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v1.0.0", "infra-system"),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(diff.IsEmpty()).To(BeTrue())
+}
+
+func Test_policyRuleChanges(t *testing.T) {
+	tests := []struct {
+		name      string
+		installed []rbacv1.PolicyRule
+		next      []rbacv1.PolicyRule
+		want      []string
+	}{
+		{
+			name:      "rules reorganized without changing permissions",
+			installed: []rbacv1.PolicyRule{{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get", "list"}}},
+			next: []rbacv1.PolicyRule{
+				{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"list"}},
+				{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get"}},
+			},
+			want: []string{},
+		},
+		{
+			name:      "permissions granted and revoked",
+			installed: []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"*"}}},
+			next: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"foo"}, Verbs: []string{"get"}},
+				{NonResourceURLs: []string{"/metrics"}, Verbs: []string{"get"}},
+			},
+			want: []string{"granted get /metrics", "granted get secrets/foo", "revoked * secrets"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(policyRuleChanges(tt.installed, tt.next)).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_crdChanges(t *testing.T) {
+	schema := func(properties map[string]apiextensionsv1.JSONSchemaProps) *apiextensionsv1.CustomResourceValidation {
+		return &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{Type: "object", Properties: properties}}
+	}
+	crd := func(versions ...apiextensionsv1.CustomResourceDefinitionVersion) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{Spec: apiextensionsv1.CustomResourceDefinitionSpec{Versions: versions}}
+	}
+
+	tests := []struct {
+		name      string
+		installed *apiextensionsv1.CustomResourceDefinition
+		next      *apiextensionsv1.CustomResourceDefinition
+		want      []string
+	}{
+		{
+			name:      "no changes",
+			installed: crd(apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1", Served: true, Storage: true, Schema: schema(nil)}),
+			next:      crd(apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1", Served: true, Storage: true, Schema: schema(nil)}),
+			want:      []string{},
+		},
+		{
+			name: "versions removed, no longer served and deprecated",
+			installed: crd(
+				apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1alpha1", Served: true},
+				apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1beta1", Served: true},
+				apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1", Served: true, Storage: true},
+			),
+			next: crd(
+				apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1beta1", Served: false, Deprecated: true},
+				apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1", Served: true, Storage: true},
+			),
+			want: []string{
+				"version v1beta1 is no longer served",
+				"version v1beta1 is now deprecated",
+				"version v1alpha1 removed",
+			},
+		},
+		{
+			name: "schema fields removed and changing type",
+			installed: crd(apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1", Storage: true, Schema: schema(map[string]apiextensionsv1.JSONSchemaProps{
+				"spec": {Type: "object", Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"replicas": {Type: "string"},
+					"hosts":    {Type: "array", Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"}}},
+				}},
+			})}),
+			next: crd(apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1", Storage: true, Schema: schema(map[string]apiextensionsv1.JSONSchemaProps{
+				"spec": {Type: "object", Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"replicas": {Type: "integer"},
+				}},
+			})}),
+			want: []string{
+				"version v1: field spec.hosts removed",
+				"version v1: field spec.hosts[*] removed",
+				"version v1: field spec.replicas type changed from string to integer",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(crdChanges(tt.installed, tt.next)).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_deploymentChanges(t *testing.T) {
+	g := NewWithT(t)
+
+	deployment := func(containers ...corev1.Container) *appsv1.Deployment {
+		d := &appsv1.Deployment{}
+		d.Spec.Template.Spec.Containers = containers
+		return d
+	}
+
+	installed := deployment(
+		corev1.Container{
+			Name: "manager",
+			Env: []corev1.EnvVar{
+				{Name: "POD_NAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{APIVersion: "v1", FieldPath: "metadata.name"}}},
+				{Name: "LEGACY", Value: "true"},
+			},
+		},
+		corev1.Container{Name: "sidecar"},
+	)
+	next := deployment(
+		corev1.Container{
+			Name: "manager",
+			Env: []corev1.EnvVar{
+				// Defaulted fields are not reported as changes.
+				{Name: "POD_NAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}}},
+			},
+		},
+		corev1.Container{Name: "webhook"},
+	)
+
+	g.Expect(deploymentChanges(installed, next)).To(Equal([]string{
+		"container manager: env LEGACY removed",
+		"container webhook added",
+		"container sidecar removed",
+	}))
+}
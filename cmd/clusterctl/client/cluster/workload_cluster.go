@@ -18,10 +18,14 @@ package cluster
 
 import (
 	"context"
+	"net/url"
+	"time"
 
 	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	utilkubeconfig "sigs.k8s.io/cluster-api/util/kubeconfig"
 )
 
@@ -29,6 +33,22 @@ import (
 type WorkloadCluster interface {
 	// GetKubeconfig returns the kubeconfig of the workload cluster.
 	GetKubeconfig(ctx context.Context, workloadClusterName string, namespace string) (string, error)
+
+	// GetUserKubeconfig returns a kubeconfig of the workload cluster with a new client certificate
+	// for the given user, signed by the cluster CA.
+	GetUserKubeconfig(ctx context.Context, workloadClusterName string, namespace string, options UserKubeconfigOptions) (string, error)
+}
+
+// UserKubeconfigOptions defines the user, the groups and the lifespan of the client certificate of a kubeconfig.
+type UserKubeconfigOptions struct {
+	// User is the user the client certificate is issued for.
+	User string
+
+	// Groups are the groups of the user.
+	Groups []string
+
+	// TTL is the lifespan of the client certificate.
+	TTL time.Duration
 }
 
 // workloadCluster implements WorkloadCluster.
@@ -59,3 +79,37 @@ func (p *workloadCluster) GetKubeconfig(ctx context.Context, workloadClusterName
 	}
 	return string(dataBytes), nil
 }
+
+func (p *workloadCluster) GetUserKubeconfig(ctx context.Context, workloadClusterName string, namespace string, options UserKubeconfigOptions) (string, error) {
+	cs, err := p.proxy.NewClient(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	obj := client.ObjectKey{
+		Namespace: namespace,
+		Name:      workloadClusterName,
+	}
+
+	cluster := &clusterv1.Cluster{}
+	if err := cs.Get(ctx, obj, cluster); err != nil {
+		return "", errors.Wrapf(err, "failed to get Cluster %s", klog.KRef(namespace, workloadClusterName))
+	}
+	if !cluster.Spec.ControlPlaneEndpoint.IsValid() {
+		return "", errors.Errorf("failed to get the control plane endpoint of Cluster %s", klog.KObj(cluster))
+	}
+	server, err := url.JoinPath("https://", cluster.Spec.ControlPlaneEndpoint.String())
+	if err != nil {
+		return "", err
+	}
+
+	dataBytes, err := utilkubeconfig.GenerateWithClientCertificate(ctx, cs, obj, server, utilkubeconfig.ClientCertificateOptions{
+		User:     options.User,
+		Groups:   options.Groups,
+		Duration: options.TTL,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to generate a kubeconfig for user %q", options.User)
+	}
+	return string(dataBytes), nil
+}
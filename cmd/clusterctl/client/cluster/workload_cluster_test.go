@@ -19,13 +19,17 @@ package cluster
 import (
 	"context"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	"sigs.k8s.io/cluster-api/util/certs"
 	"sigs.k8s.io/cluster-api/util/secret"
 )
 
@@ -100,3 +104,88 @@ users:
 		})
 	}
 }
+
+func Test_WorkloadCluster_GetUserKubeconfig(t *testing.T) {
+	g := NewWithT(t)
+
+	ca := &secret.Certificate{Purpose: secret.ClusterCA}
+	g.Expect(ca.Generate()).To(Succeed())
+
+	var (
+		cluster = &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test1",
+				Namespace: "test",
+			},
+			Spec: clusterv1.ClusterSpec{
+				ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "test-cluster-api", Port: 6443},
+			},
+		}
+		clusterWithoutEndpoint = &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test1",
+				Namespace: "test",
+			},
+		}
+		caSecret = ca.AsSecret(client.ObjectKey{Name: "test1", Namespace: "test"}, metav1.OwnerReference{})
+	)
+
+	tests := []struct {
+		name      string
+		expectErr bool
+		proxy     Proxy
+	}{
+		{
+			name:      "return kubeconfig with a client certificate for the user",
+			expectErr: false,
+			proxy:     test.NewFakeProxy().WithObjs(cluster, caSecret),
+		},
+		{
+			name:      "return error if cannot find cluster",
+			expectErr: true,
+			proxy:     test.NewFakeProxy().WithObjs(caSecret),
+		},
+		{
+			name:      "return error if the cluster has no control plane endpoint",
+			expectErr: true,
+			proxy:     test.NewFakeProxy().WithObjs(clusterWithoutEndpoint, caSecret),
+		},
+		{
+			name:      "return error if cannot find the cluster CA",
+			expectErr: true,
+			proxy:     test.NewFakeProxy().WithObjs(cluster),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ctx := context.Background()
+
+			wc := newWorkloadCluster(tt.proxy)
+			data, err := wc.GetUserKubeconfig(ctx, "test1", "test", UserKubeconfigOptions{
+				User:   "jane",
+				Groups: []string{"developers"},
+				TTL:    time.Hour,
+			})
+
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			config, err := clientcmd.Load([]byte(data))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(config.CurrentContext).To(Equal("jane@test1"))
+			g.Expect(config.Clusters["test1"].Server).To(Equal("https://test-cluster-api:6443"))
+
+			cert, err := certs.DecodeCertPEM(config.AuthInfos["jane"].ClientCertificateData)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(cert.Subject.CommonName).To(Equal("jane"))
+			g.Expect(cert.Subject.Organization).To(ConsistOf("developers"))
+			g.Expect(cert.NotAfter).To(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+		})
+	}
+}
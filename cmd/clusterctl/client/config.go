@@ -70,6 +70,10 @@ type ProcessYAMLOptions struct {
 	// SkipTemplateProcess return the list of variables expected by the template
 	// without executing any further processing.
 	SkipTemplateProcess bool
+
+	// YamlProcessor defines the yaml processor to use for the template
+	// processing. If not defined, SimpleProcessor will be used.
+	YamlProcessor Processor
 }
 
 func (c *clusterctlClient) ProcessYAML(ctx context.Context, options ProcessYAMLOptions) (YamlPrinter, error) {
@@ -80,10 +84,14 @@ func (c *clusterctlClient) ProcessYAML(ctx context.Context, options ProcessYAMLO
 		if err != nil {
 			return nil, err
 		}
+		var processor yaml.Processor = yaml.NewSimpleProcessor()
+		if options.YamlProcessor != nil {
+			processor = options.YamlProcessor
+		}
 		return repository.NewTemplate(repository.TemplateInput{
 			RawArtifact:           content,
 			ConfigVariablesClient: c.configClient.Variables(),
-			Processor:             yaml.NewSimpleProcessor(),
+			Processor:             processor,
 			TargetNamespace:       "",
 			SkipTemplateProcess:   options.SkipTemplateProcess,
 		})
@@ -97,6 +105,7 @@ func (c *clusterctlClient) ProcessYAML(ctx context.Context, options ProcessYAMLO
 		ClusterClientFactoryInput{
 			// use the default kubeconfig
 			Kubeconfig: Kubeconfig{},
+			Processor:  options.YamlProcessor,
 		},
 	)
 	if err != nil {
@@ -332,6 +341,14 @@ func (c *clusterctlClient) getTemplateFromRepository(ctx context.Context, cluste
 		return nil, err
 	}
 
+	// If the yaml processor is not set, use the one configured for the provider, if any.
+	if processor == nil && providerConfig.TemplateProcessor() != "" {
+		processor, err = yaml.NewProcessor(providerConfig.TemplateProcessor())
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid configuration for the %s with name %s", providerConfig.Type(), providerConfig.Name())
+		}
+	}
+
 	repo, err := c.repositoryClientFactory(ctx, RepositoryClientFactoryInput{Provider: providerConfig, Processor: processor})
 	if err != nil {
 		return nil, err
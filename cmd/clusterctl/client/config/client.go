@@ -20,6 +20,8 @@ import (
 	"context"
 
 	"github.com/pkg/errors"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 )
 
 // Client is used to interact with the clusterctl configurations.
@@ -28,6 +30,7 @@ import (
 // 2. The configuration of the providers (name, type and URL of the provider repository)
 // 3. Variables used when installing providers/creating clusters. Variables can be read from the environment or from the config file
 // 4. The configuration about image overrides.
+// 5. The configuration about the verification of provider components.
 type Client interface {
 	// CertManager provide access to the cert-manager configurations.
 	CertManager() CertManagerClient
@@ -40,11 +43,15 @@ type Client interface {
 
 	// ImageMeta provide access to image meta configurations.
 	ImageMeta() ImageMetaClient
+
+	// Verification provide access to the configuration for the verification of provider components.
+	Verification() VerificationClient
 }
 
 // configClient implements Client.
 type configClient struct {
-	reader Reader
+	reader            Reader
+	managementCluster *clusterctlv1.ManagementCluster
 }
 
 // ensure configClient implements Client.
@@ -66,6 +73,10 @@ func (c *configClient) ImageMeta() ImageMetaClient {
 	return newImageMetaClient(c.reader)
 }
 
+func (c *configClient) Verification() VerificationClient {
+	return newVerificationClient(c.reader)
+}
+
 // Option is a configuration option supplied to New.
 type Option func(*configClient)
 
@@ -76,6 +87,14 @@ func InjectReader(reader Reader) Option {
 	}
 }
 
+// WithManagementCluster allows to layer the variables, the image overrides and the cert-manager version
+// defined in a ManagementCluster on top of the configuration read by the configuration reader.
+func WithManagementCluster(managementCluster *clusterctlv1.ManagementCluster) Option {
+	return func(c *configClient) {
+		c.managementCluster = managementCluster
+	}
+}
+
 // New returns a Client for interacting with the clusterctl configuration.
 func New(ctx context.Context, path string, options ...Option) (Client, error) {
 	return newConfigClient(ctx, path, options...)
@@ -98,6 +117,12 @@ func newConfigClient(ctx context.Context, path string, options ...Option) (*conf
 		}
 	}
 
+	if client.managementCluster != nil {
+		if client.reader, err = newManagementClusterReader(client.reader, client.managementCluster); err != nil {
+			return nil, errors.Wrap(err, "failed to create the configuration reader for the management cluster")
+		}
+	}
+
 	return client, nil
 }
 
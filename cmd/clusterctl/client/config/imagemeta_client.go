@@ -19,6 +19,7 @@ package config
 import (
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/pkg/errors"
 
@@ -41,8 +42,11 @@ type ImageMetaClient interface {
 
 // imageMetaClient implements ImageMetaClient.
 type imageMetaClient struct {
-	reader         Reader
-	imageMetaCache map[string]*imageMeta
+	reader Reader
+
+	// imageMetaCacheLock guards imageMetaCache, given that images can be altered in parallel.
+	imageMetaCacheLock sync.Mutex
+	imageMetaCache     map[string]*imageMeta
 }
 
 // ensure imageMetaClient implements ImageMetaClient.
@@ -76,6 +80,9 @@ func (p *imageMetaClient) AlterImage(component, imageString string) (string, err
 
 // getImageMeta returns the image meta that applies to the selected component/image.
 func (p *imageMetaClient) getImageMeta(component, imageName string) (*imageMeta, error) {
+	p.imageMetaCacheLock.Lock()
+	defer p.imageMetaCacheLock.Unlock()
+
 	// if the image meta for the component is already known, return it
 	if im, ok := p.imageMetaCache[imageMetaCacheKey(component, imageName)]; ok {
 		return im, nil
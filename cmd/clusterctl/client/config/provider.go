@@ -34,6 +34,10 @@ type Provider interface {
 	// URL returns the name of the provider repository.
 	URL() string
 
+	// TemplateProcessor returns the name of the yaml processor to be used for the cluster templates of the provider.
+	// If empty, the default yaml processor is used.
+	TemplateProcessor() string
+
 	// SameAs returns true if two providers have the same name and type.
 	// Please note that this uniquely identifies a provider configuration, but not the provider instances in the cluster
 	// because it is possible to create many instances of the same provider.
@@ -50,9 +54,10 @@ type Provider interface {
 
 // provider implements Provider.
 type provider struct {
-	name         string
-	url          string
-	providerType clusterctlv1.ProviderType
+	name              string
+	url               string
+	providerType      clusterctlv1.ProviderType
+	templateProcessor string
 }
 
 // ensure provider implements provider.
@@ -66,6 +71,10 @@ func (p *provider) URL() string {
 	return p.url
 }
 
+func (p *provider) TemplateProcessor() string {
+	return p.templateProcessor
+}
+
 func (p *provider) Type() clusterctlv1.ProviderType {
 	return p.providerType
 }
@@ -455,9 +455,10 @@ func (p *providersClient) defaults() []Provider {
 
 // configProvider mirrors config.Provider interface and allows serialization of the corresponding info.
 type configProvider struct {
-	Name string                    `json:"name,omitempty"`
-	URL  string                    `json:"url,omitempty"`
-	Type clusterctlv1.ProviderType `json:"type,omitempty"`
+	Name              string                    `json:"name,omitempty"`
+	URL               string                    `json:"url,omitempty"`
+	Type              clusterctlv1.ProviderType `json:"type,omitempty"`
+	TemplateProcessor string                    `json:"templateProcessor,omitempty"`
 }
 
 func (p *providersClient) List() ([]Provider, error) {
@@ -479,7 +480,12 @@ func (p *providersClient) List() ([]Provider, error) {
 			return nil, errors.Wrapf(err, "unable to evaluate url: %q", u.URL)
 		}
 
-		provider := NewProvider(u.Name, u.URL, u.Type)
+		provider := &provider{
+			name:              u.Name,
+			url:               u.URL,
+			providerType:      u.Type,
+			templateProcessor: u.TemplateProcessor,
+		}
 		if err := validateProvider(provider); err != nil {
 			return nil, errors.Wrapf(err, "error validating configuration for the %s with name %s. Please fix the providers value in clusterctl configuration file", provider.Type(), provider.Name())
 		}
@@ -46,6 +46,17 @@ func Test_providers_List(t *testing.T) {
 		return defaultsAndZZZ[i].Less(defaultsAndZZZ[j])
 	})
 
+	defaultsAndZZZWithTemplateProcessor := append([]Provider{}, defaults...)
+	defaultsAndZZZWithTemplateProcessor = append(defaultsAndZZZWithTemplateProcessor, &provider{
+		name:              "zzz",
+		url:               "https://zzz/infrastructure-components.yaml",
+		providerType:      "InfrastructureProvider",
+		templateProcessor: "go-template",
+	})
+	sort.Slice(defaultsAndZZZWithTemplateProcessor, func(i, j int) bool {
+		return defaultsAndZZZWithTemplateProcessor[i].Less(defaultsAndZZZWithTemplateProcessor[j])
+	})
+
 	defaultsWithOverride := append([]Provider{}, defaults...)
 	defaultsWithOverride[0] = NewProvider(defaults[0].Name(), "https://zzz/infrastructure-components.yaml", defaults[0].Type())
 
@@ -98,6 +109,21 @@ func Test_providers_List(t *testing.T) {
 			want:    defaultsAndZZZ,
 			wantErr: false,
 		},
+		{
+			name: "Returns user defined provider configurations with a template processor",
+			fields: fields{
+				configGetter: test.NewFakeReader().
+					WithVar(
+						ProvidersConfigKey,
+						"- name: \"zzz\"\n"+
+							"  url: \"https://zzz/infrastructure-components.yaml\"\n"+
+							"  type: \"InfrastructureProvider\"\n"+
+							"  templateProcessor: \"go-template\"\n",
+					),
+			},
+			want:    defaultsAndZZZWithTemplateProcessor,
+			wantErr: false,
+		},
 		{
 			name: "User defined provider configurations override defaults",
 			fields: fields{
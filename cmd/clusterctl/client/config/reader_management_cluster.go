@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+)
+
+// managementClusterReader is a Reader that layers the configuration defined in a ManagementCluster
+// on top of the configuration read by another Reader.
+type managementClusterReader struct {
+	Reader
+
+	// variables defined in the ManagementCluster.
+	variables map[string]string
+
+	// overrides defined in the ManagementCluster, in JSON format, by configuration key.
+	overrides map[string][]byte
+}
+
+// ensure managementClusterReader implements Reader.
+var _ Reader = &managementClusterReader{}
+
+func newManagementClusterReader(reader Reader, managementCluster *clusterctlv1.ManagementCluster) (*managementClusterReader, error) {
+	r := &managementClusterReader{
+		Reader:    reader,
+		variables: map[string]string{},
+		overrides: map[string][]byte{},
+	}
+
+	for k, v := range managementCluster.Spec.Variables {
+		r.variables[k] = v
+	}
+
+	if len(managementCluster.Spec.Images) > 0 {
+		images := map[string]imageMeta{}
+		for k, v := range managementCluster.Spec.Images {
+			images[k] = imageMeta{Repository: v.Repository, Tag: v.Tag}
+		}
+		raw, err := json.Marshal(images)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal image overrides")
+		}
+		r.overrides[imagesConfigKey] = raw
+	}
+
+	if managementCluster.Spec.CertManager != nil && managementCluster.Spec.CertManager.Version != "" {
+		raw, err := json.Marshal(configCertManager{Version: managementCluster.Spec.CertManager.Version})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal cert-manager configuration")
+		}
+		r.overrides[CertManagerConfigKey] = raw
+	}
+
+	return r, nil
+}
+
+func (r *managementClusterReader) Get(key string) (string, error) {
+	if v, ok := r.variables[key]; ok {
+		return v, nil
+	}
+	return r.Reader.Get(key)
+}
+
+func (r *managementClusterReader) Set(key, value string) {
+	// Explicit overrides, e.g. from flags, take precedence over the ManagementCluster variables.
+	delete(r.variables, key)
+	r.Reader.Set(key, value)
+}
+
+func (r *managementClusterReader) UnmarshalKey(key string, value interface{}) error {
+	if err := r.Reader.UnmarshalKey(key, value); err != nil {
+		return err
+	}
+
+	// Merges the overrides from the ManagementCluster into the value read by the underlying reader.
+	if raw, ok := r.overrides[key]; ok {
+		if err := json.Unmarshal(raw, value); err != nil {
+			return errors.Wrapf(err, "failed to unmarshal %q from the management cluster configuration", key)
+		}
+	}
+	return nil
+}
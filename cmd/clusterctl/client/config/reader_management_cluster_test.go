@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func TestManagementClusterReader(t *testing.T) {
+	g := NewWithT(t)
+
+	reader := test.NewFakeReader().
+		WithVar("FOO", "foo").
+		WithVar("BAR", "bar").
+		WithImageMeta("all", "myorg.io/local-repo", "").
+		WithCertManager("foo-url", "v1.0.0", "")
+
+	managementCluster := &clusterctlv1.ManagementCluster{
+		Spec: clusterctlv1.ManagementClusterSpec{
+			CertManager: &clusterctlv1.ManagementClusterCertManager{Version: "v2.0.0"},
+			Variables:   map[string]string{"BAR": "bar-from-management-cluster", "BAZ": "baz"},
+			Images: map[string]clusterctlv1.ManagementClusterImage{
+				"cert-manager": {Tag: "v2.0.0"},
+			},
+		},
+	}
+
+	client, err := New(context.Background(), "", InjectReader(reader), WithManagementCluster(managementCluster))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Variables from the ManagementCluster take precedence.
+	g.Expect(client.Variables().Get("FOO")).To(Equal("foo"))
+	g.Expect(client.Variables().Get("BAR")).To(Equal("bar-from-management-cluster"))
+	g.Expect(client.Variables().Get("BAZ")).To(Equal("baz"))
+
+	// Explicit overrides take precedence over variables from the ManagementCluster.
+	client.Variables().Set("BAZ", "baz-override")
+	g.Expect(client.Variables().Get("BAZ")).To(Equal("baz-override"))
+
+	// Image overrides from the ManagementCluster are merged with the ones from the configuration.
+	g.Expect(client.ImageMeta().AlterImage("cert-manager", "quay.io/jetstack/cert-manager-controller:v1.0.0")).To(Equal("myorg.io/local-repo/cert-manager-controller:v2.0.0"))
+	g.Expect(client.ImageMeta().AlterImage("cluster-api", "registry.k8s.io/cluster-api/cluster-api-controller:v1.0.0")).To(Equal("myorg.io/local-repo/cluster-api-controller:v1.0.0"))
+
+	// The cert-manager version from the ManagementCluster is merged with the cert-manager configuration.
+	certManager, err := client.CertManager().Get()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(certManager.URL()).To(Equal("foo-url"))
+	g.Expect(certManager.Version()).To(Equal("v2.0.0"))
+}
@@ -19,6 +19,12 @@ package config
 const (
 	// GitHubTokenVariable defines a variable hosting the GitHub access token.
 	GitHubTokenVariable = "github-token"
+
+	// OCIUsernameVariable defines a variable hosting the username used to authenticate to OCI registries.
+	OCIUsernameVariable = "oci-username"
+
+	// OCIPasswordVariable defines a variable hosting the password or token used to authenticate to OCI registries.
+	OCIPasswordVariable = "oci-password"
 )
 
 // VariablesClient has methods to work with environment variables and with variables defined in the clusterctl configuration file.
@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"os"
+
+	"github.com/drone/envsubst/v2"
+	"github.com/pkg/errors"
+)
+
+const (
+	// VerificationConfigKey defines the name of the top level config key for the verification of provider components.
+	VerificationConfigKey = "componentsVerification"
+
+	allVerificationConfig = "all"
+)
+
+// ComponentsVerification defines how the components of a provider should be verified.
+type ComponentsVerification struct {
+	// Required defines if the components must be verified with a signature; if true, the components
+	// of a provider without a public key are rejected.
+	Required bool
+
+	// ChecksumFile is the name of the file with the checksums of the release assets of the provider,
+	// in the format of the sha256sum command, e.g. checksums.txt. If empty, checksums are not verified.
+	// NOTE: different providers might publish checksums in files with different names.
+	ChecksumFile string
+
+	// PublicKey is the path of the PEM encoded public key used to verify the signatures of the provider.
+	// If empty, signatures are not verified.
+	PublicKey string
+}
+
+// VerificationClient has methods to work with the configuration for the verification of provider components.
+type VerificationClient interface {
+	// Get returns the configuration for the verification of the components of a provider, given its manifest label, e.g. infrastructure-aws.
+	Get(providerLabel string) (ComponentsVerification, error)
+}
+
+// verificationClient implements VerificationClient.
+type verificationClient struct {
+	reader Reader
+}
+
+// ensure verificationClient implements VerificationClient.
+var _ VerificationClient = &verificationClient{}
+
+func newVerificationClient(reader Reader) *verificationClient {
+	return &verificationClient{
+		reader: reader,
+	}
+}
+
+// configVerification mirrors ComponentsVerification and allows serialization of the corresponding info.
+type configVerification struct {
+	Required      bool              `json:"required,omitempty"`
+	ChecksumFiles map[string]string `json:"checksumFiles,omitempty"`
+	PublicKeys    map[string]string `json:"publicKeys,omitempty"`
+}
+
+func (p *verificationClient) Get(providerLabel string) (ComponentsVerification, error) {
+	userVerification := &configVerification{}
+	if err := p.reader.UnmarshalKey(VerificationConfigKey, &userVerification); err != nil {
+		return ComponentsVerification{}, errors.Wrap(err, "failed to unmarshal componentsVerification from the clusterctl configuration file")
+	}
+
+	verification := ComponentsVerification{
+		Required:     userVerification.Required,
+		ChecksumFile: forProvider(userVerification.ChecksumFiles, providerLabel),
+	}
+
+	publicKey := forProvider(userVerification.PublicKeys, providerLabel)
+	if publicKey != "" {
+		var err error
+		publicKey, err = envsubst.Eval(publicKey, os.Getenv)
+		if err != nil {
+			return ComponentsVerification{}, errors.Wrapf(err, "unable to evaluate public key path: %q", publicKey)
+		}
+	}
+	verification.PublicKey = publicKey
+
+	return verification, nil
+}
+
+// forProvider returns the value for the provider with the given label from a map of values by provider;
+// the value for the provider takes precedence over the one for all the providers.
+func forProvider(values map[string]string, providerLabel string) string {
+	if value, ok := values[providerLabel]; ok {
+		return value
+	}
+	return values[allVerificationConfig]
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func TestVerificationGet(t *testing.T) {
+	verificationConfig := `required: true
+checksumFiles:
+  all: checksums.txt
+  infrastructure-bar: SHA256SUMS
+publicKeys:
+  all: ${TEST_KEYS_PATH}/all.pub
+  infrastructure-foo: ${TEST_KEYS_PATH}/foo.pub
+`
+	tests := []struct {
+		name          string
+		reader        Reader
+		providerLabel string
+		want          ComponentsVerification
+		wantErr       bool
+	}{
+		{
+			name:          "return empty configuration if no custom config is provided",
+			reader:        test.NewFakeReader(),
+			providerLabel: "infrastructure-foo",
+			want:          ComponentsVerification{},
+		},
+		{
+			name:          "return the public key for the provider if defined",
+			reader:        test.NewFakeReader().WithVar(VerificationConfigKey, verificationConfig),
+			providerLabel: "infrastructure-foo",
+			want: ComponentsVerification{
+				Required:     true,
+				ChecksumFile: "checksums.txt",
+				PublicKey:    "/tmp/keys/foo.pub",
+			},
+		},
+		{
+			name:          "return the public key and the checksum file for all the providers if they are not defined for the provider",
+			reader:        test.NewFakeReader().WithVar(VerificationConfigKey, verificationConfig),
+			providerLabel: "infrastructure-baz",
+			want: ComponentsVerification{
+				Required:     true,
+				ChecksumFile: "checksums.txt",
+				PublicKey:    "/tmp/keys/all.pub",
+			},
+		},
+		{
+			name:          "return the checksum file for the provider and the public key for all the providers if a public key for the provider is not defined",
+			reader:        test.NewFakeReader().WithVar(VerificationConfigKey, verificationConfig),
+			providerLabel: "infrastructure-bar",
+			want: ComponentsVerification{
+				Required:     true,
+				ChecksumFile: "SHA256SUMS",
+				PublicKey:    "/tmp/keys/all.pub",
+			},
+		},
+		{
+			name:          "return error if the configuration is not valid",
+			reader:        test.NewFakeReader().WithVar(VerificationConfigKey, "required: foo"),
+			providerLabel: "infrastructure-foo",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Setenv("TEST_KEYS_PATH", "/tmp/keys")
+
+			p := newVerificationClient(tt.reader)
+			got, err := p.Get(tt.providerLabel)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
@@ -19,6 +19,7 @@ package client
 import (
 	"context"
 
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/tree"
 )
 
@@ -64,41 +65,55 @@ type DescribeClusterOptions struct {
 
 // DescribeCluster returns the object tree representing the status of a Cluster API cluster.
 func (c *clusterctlClient) DescribeCluster(ctx context.Context, options DescribeClusterOptions) (*tree.ObjectTree, error) {
+	clusterClient, err := c.describeClusterManagementCluster(ctx, &options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch the Cluster client.
+	client, err := clusterClient.Proxy().NewClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Gets the object tree representing the status of a Cluster API cluster.
+	return tree.Discovery(ctx, client, options.Namespace, options.ClusterName, options.toDiscoverOptions())
+}
+
+// describeClusterManagementCluster returns the client for the management cluster hosting the cluster to describe,
+// and defaults the namespace if not set.
+func (c *clusterctlClient) describeClusterManagementCluster(ctx context.Context, options *DescribeClusterOptions) (cluster.Client, error) {
 	// gets access to the management cluster
-	cluster, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
 	if err != nil {
 		return nil, err
 	}
 
 	// Ensure this command only runs against management clusters with the current Cluster API contract.
-	if err := cluster.ProviderInventory().CheckCAPIContract(ctx); err != nil {
+	if err := clusterClient.ProviderInventory().CheckCAPIContract(ctx); err != nil {
 		return nil, err
 	}
 
 	// If the option specifying the Namespace is empty, try to detect it.
 	if options.Namespace == "" {
-		currentNamespace, err := cluster.Proxy().CurrentNamespace()
+		currentNamespace, err := clusterClient.Proxy().CurrentNamespace()
 		if err != nil {
 			return nil, err
 		}
 		options.Namespace = currentNamespace
 	}
+	return clusterClient, nil
+}
 
-	// Fetch the Cluster client.
-	client, err := cluster.Proxy().NewClient(ctx)
-	if err != nil {
-		return nil, err
+func (o DescribeClusterOptions) toDiscoverOptions() tree.DiscoverOptions {
+	return tree.DiscoverOptions{
+		ShowOtherConditions:     o.ShowOtherConditions,
+		ShowMachineSets:         o.ShowMachineSets,
+		ShowClusterResourceSets: o.ShowClusterResourceSets,
+		ShowTemplates:           o.ShowTemplates,
+		AddTemplateVirtualNode:  o.AddTemplateVirtualNode,
+		Echo:                    o.Echo,
+		Grouping:                o.Grouping,
+		V1Beta2:                 o.V1Beta2,
 	}
-
-	// Gets the object tree representing the status of a Cluster API cluster.
-	return tree.Discovery(ctx, client, options.Namespace, options.ClusterName, tree.DiscoverOptions{
-		ShowOtherConditions:     options.ShowOtherConditions,
-		ShowMachineSets:         options.ShowMachineSets,
-		ShowClusterResourceSets: options.ShowClusterResourceSets,
-		ShowTemplates:           options.ShowTemplates,
-		AddTemplateVirtualNode:  options.AddTemplateVirtualNode,
-		Echo:                    options.Echo,
-		Grouping:                options.Grouping,
-		V1Beta2:                 options.V1Beta2,
-	})
 }
@@ -0,0 +1,227 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/tree"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/scheme"
+)
+
+// describeClusterWatchDelay is the time to wait after a change before computing a new object tree,
+// so a burst of changes, e.g. all the Machines of a MachineDeployment being updated, results in a single update.
+var describeClusterWatchDelay = 500 * time.Millisecond
+
+// WatchCluster watches the objects representing the status of a Cluster API cluster, and calls the handler
+// with an updated object tree every time any of those objects changes, until the context is canceled.
+// NOTE: Objects are read from informers, so the API server is not listed again every time the object tree is computed.
+func (c *clusterctlClient) WatchCluster(ctx context.Context, options DescribeClusterOptions, handler func(*tree.ObjectTree) error) error {
+	clusterClient, err := c.describeClusterManagementCluster(ctx, &options)
+	if err != nil {
+		return err
+	}
+
+	restConfig, err := clusterClient.Proxy().GetConfig()
+	if err != nil {
+		return err
+	}
+
+	informers, err := cache.New(restConfig, cache.Options{
+		Scheme:            scheme.Scheme,
+		DefaultNamespaces: map[string]cache.Config{options.Namespace: {}},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create the cache for watching the management cluster")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	cacheErr := make(chan error, 1)
+	go func() {
+		err := informers.Start(ctx)
+		if err != nil {
+			cancel()
+		}
+		cacheErr <- err
+	}()
+	if !informers.WaitForCacheSync(ctx) {
+		cancel()
+		return <-cacheErr
+	}
+
+	cachedClient, err := client.New(restConfig, client.Options{
+		Scheme: scheme.Scheme,
+		Cache: &client.CacheOptions{
+			Reader:       informers,
+			Unstructured: true,
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create the client for watching the management cluster")
+	}
+
+	changed := make(chan struct{}, 1)
+	if err := watchCluster(ctx, newWatchingClient(cachedClient, informers, changed), changed, options, handler); err != nil {
+		return err
+	}
+
+	cancel()
+	return <-cacheErr
+}
+
+// watchCluster computes the object tree representing the status of a Cluster API cluster and calls the handler,
+// and then does it again every time a change is notified, until the context is canceled.
+func watchCluster(ctx context.Context, c client.Client, changed <-chan struct{}, options DescribeClusterOptions, handler func(*tree.ObjectTree) error) error {
+	for {
+		objectTree, err := tree.Discovery(ctx, c, options.Namespace, options.ClusterName, options.toDiscoverOptions())
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		if err := handler(objectTree); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changed:
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(describeClusterWatchDelay):
+		}
+
+		// Drop changes notified while waiting, they are going to be included in the next object tree.
+		select {
+		case <-changed:
+		default:
+		}
+	}
+}
+
+// watchingClient is a client.Client that, before reading an object kind for the first time, adds an event handler
+// to the informer for that kind, so changes to any of the objects read can be notified.
+type watchingClient struct {
+	client.Client
+
+	informers cache.Informers
+	changed   chan<- struct{}
+
+	lock    sync.Mutex
+	watched sets.Set[schema.GroupVersionKind]
+}
+
+func newWatchingClient(c client.Client, informers cache.Informers, changed chan<- struct{}) *watchingClient {
+	return &watchingClient{
+		Client:    c,
+		informers: informers,
+		changed:   changed,
+		watched:   sets.Set[schema.GroupVersionKind]{},
+	}
+}
+
+// Get adds an event handler to the informer for the object kind, if not already done, and then reads the object.
+func (c *watchingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if err := c.watch(ctx, obj); err != nil {
+		return err
+	}
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+// List adds an event handler to the informer for the object kind, if not already done, and then lists the objects.
+func (c *watchingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if err := c.watch(ctx, list); err != nil {
+		return err
+	}
+	return c.Client.List(ctx, list, opts...)
+}
+
+func (c *watchingClient) watch(ctx context.Context, obj runtime.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return err
+	}
+	if meta.IsListType(obj) {
+		gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.watched.Has(gvk) {
+		return nil
+	}
+
+	var informerObj client.Object
+	if _, ok := obj.(runtime.Unstructured); ok {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		informerObj = u
+	} else {
+		o, err := c.Scheme().New(gvk)
+		if err != nil {
+			return err
+		}
+		if informerObj, ok = o.(client.Object); !ok {
+			return errors.Errorf("%s is not a client.Object", gvk)
+		}
+	}
+
+	informer, err := c.informers.GetInformer(ctx, informerObj)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the informer for %s", gvk)
+	}
+	if _, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { c.notify() },
+		UpdateFunc: func(interface{}, interface{}) { c.notify() },
+		DeleteFunc: func(interface{}) { c.notify() },
+	}); err != nil {
+		return errors.Wrapf(err, "failed to add the event handler to the informer for %s", gvk)
+	}
+	c.watched.Insert(gvk)
+	return nil
+}
+
+// notify signals a change without blocking; if a change is already pending, there is nothing to add.
+func (c *watchingClient) notify() {
+	select {
+	case c.changed <- struct{}{}:
+	default:
+	}
+}
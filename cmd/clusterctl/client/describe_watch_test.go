@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/tree"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func Test_watchCluster(t *testing.T) {
+	g := NewWithT(t)
+
+	defaultDelay := describeClusterWatchDelay
+	describeClusterWatchDelay = 10 * time.Millisecond
+	defer func() { describeClusterWatchDelay = defaultDelay }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fakeClient, err := test.NewFakeProxy().WithObjs(test.NewFakeCluster("ns1", "cluster1").Objs()...).NewClient(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	informers := &informertest.FakeInformers{Scheme: test.FakeScheme}
+	changed := make(chan struct{}, 1)
+	c := newWatchingClient(fakeClient, informers, changed)
+
+	trees := make(chan *tree.ObjectTree)
+	done := make(chan error)
+	go func() {
+		done <- watchCluster(ctx, c, changed, DescribeClusterOptions{Namespace: "ns1", ClusterName: "cluster1"}, func(objectTree *tree.ObjectTree) error {
+			trees <- objectTree
+			return nil
+		})
+	}()
+
+	// The object tree is computed immediately.
+	var objectTree *tree.ObjectTree
+	g.Eventually(trees).Should(Receive(&objectTree))
+	g.Expect(tree.GetReadyCondition(objectTree.GetRoot())).To(BeNil())
+
+	// Informers are created for every kind read while computing the object tree, including unstructured objects.
+	c.lock.Lock()
+	g.Expect(c.watched.UnsortedList()).To(ContainElements(
+		clusterv1.GroupVersion.WithKind("Cluster"),
+		clusterv1.GroupVersion.WithKind("Machine"),
+		schema.GroupVersionKind{Group: "infrastructure.cluster.x-k8s.io", Version: "v1beta1", Kind: "GenericInfrastructureCluster"},
+	))
+	c.lock.Unlock()
+
+	// Nothing happens until a change is notified.
+	g.Consistently(trees, 100*time.Millisecond).ShouldNot(Receive())
+
+	// When an object changes, the object tree is computed again.
+	cluster := &clusterv1.Cluster{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: "ns1", Name: "cluster1"}, cluster)).To(Succeed())
+	clusterBefore := cluster.DeepCopy()
+	conditions.MarkTrue(cluster, clusterv1.ReadyCondition)
+	g.Expect(fakeClient.Update(ctx, cluster)).To(Succeed())
+
+	informer, err := informers.FakeInformerFor(ctx, &clusterv1.Cluster{})
+	g.Expect(err).ToNot(HaveOccurred())
+	informer.Update(clusterBefore, cluster)
+
+	g.Eventually(trees).Should(Receive(&objectTree))
+	g.Expect(tree.GetReadyCondition(objectTree.GetRoot())).ToNot(BeNil())
+
+	// Watching stops when the context is canceled.
+	cancel()
+	g.Eventually(done).Should(Receive(BeNil()))
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+)
+
+// PlanFleetUpgradeOptions carries the options supported by PlanFleetUpgrade.
+type PlanFleetUpgradeOptions struct {
+	// Kubeconfigs defines the kubeconfigs to use for accessing the management clusters of the fleet,
+	// e.g. one for each context of the same kubeconfig file.
+	Kubeconfigs []Kubeconfig
+
+	// Concurrency is the number of management clusters planned concurrently; if not set, management clusters
+	// are planned one at a time.
+	Concurrency int
+}
+
+// FleetUpgradePlan is the provider inventory, the health and the upgrade plan of a management cluster of a fleet.
+type FleetUpgradePlan struct {
+	// Kubeconfig is the kubeconfig used for accessing the management cluster.
+	Kubeconfig Kubeconfig
+
+	// CertManager is the upgrade plan for cert-manager.
+	CertManager CertManagerUpgradePlan
+
+	// UpgradePlans are the upgrade plans for the providers, one for each API Version of Cluster API (contract);
+	// the providers of the upgrade plans are the provider inventory of the management cluster.
+	UpgradePlans []UpgradePlan
+
+	// Health is the outcome of the checks run by clusterctl doctor on the management cluster.
+	Health *DoctorReport
+
+	// Error is the error which prevented planning the upgrade of the management cluster, if any.
+	Error error
+}
+
+func (c *clusterctlClient) PlanFleetUpgrade(ctx context.Context, options PlanFleetUpgradeOptions) ([]FleetUpgradePlan, error) {
+	if len(options.Kubeconfigs) == 0 {
+		return nil, errors.New("at least one kubeconfig is required to plan the upgrade of a fleet of management clusters")
+	}
+
+	// Plan the upgrade of the management clusters using up to Concurrency workers, so the number of
+	// management clusters accessed at the same time is bounded; failures are reported for each
+	// management cluster, so a single unreachable cluster does not block the others.
+	plans := make([]FleetUpgradePlan, len(options.Kubeconfigs))
+	indexCh := make(chan int)
+	wg := sync.WaitGroup{}
+	for range min(max(options.Concurrency, 1), len(options.Kubeconfigs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexCh {
+				plans[i] = c.planFleetUpgrade(ctx, options.Kubeconfigs[i])
+			}
+		}()
+	}
+	for i := range options.Kubeconfigs {
+		indexCh <- i
+	}
+	close(indexCh)
+	wg.Wait()
+
+	return plans, nil
+}
+
+// planFleetUpgrade returns the provider inventory, the health and the upgrade plan of a management cluster of a fleet.
+func (c *clusterctlClient) planFleetUpgrade(ctx context.Context, kubeconfig Kubeconfig) FleetUpgradePlan {
+	log := logf.Log.WithValues("kubeconfig", kubeconfig.Path, "context", kubeconfig.Context)
+
+	plan := FleetUpgradePlan{
+		Kubeconfig: kubeconfig,
+	}
+
+	upgradePlans, err := c.PlanUpgrade(ctx, PlanUpgradeOptions{Kubeconfig: kubeconfig})
+	if err != nil {
+		plan.Error = err
+		return plan
+	}
+	plan.UpgradePlans = upgradePlans
+
+	certManager, err := c.PlanCertManagerUpgrade(ctx, PlanUpgradeOptions{Kubeconfig: kubeconfig})
+	if err != nil {
+		plan.Error = err
+		return plan
+	}
+	plan.CertManager = certManager
+
+	health, err := c.Doctor(ctx, DoctorOptions{Kubeconfig: kubeconfig})
+	if err != nil {
+		plan.Error = err
+		return plan
+	}
+	plan.Health = health
+
+	log.V(1).Info("Planned the upgrade of the management cluster")
+	return plan
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_clusterctlClient_PlanFleetUpgrade(t *testing.T) {
+	t.Run("returns error if there are no kubeconfigs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := fakeClientForUpgrade().PlanFleetUpgrade(context.Background(), PlanFleetUpgradeOptions{})
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("returns a plan for each management cluster", func(t *testing.T) {
+		g := NewWithT(t)
+
+		kubeconfigs := []Kubeconfig{
+			{Path: "kubeconfig", Context: "mgmt-context"},
+			{Path: "kubeconfig", Context: "unknown-context"},
+		}
+		plans, err := fakeClientForUpgrade().PlanFleetUpgrade(context.Background(), PlanFleetUpgradeOptions{
+			Kubeconfigs: kubeconfigs,
+			Concurrency: 1,
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(plans).To(HaveLen(2))
+
+		// The plan for the reachable management cluster has the provider inventory, the upgrade plans and the health.
+		g.Expect(plans[0].Kubeconfig).To(Equal(kubeconfigs[0]))
+		g.Expect(plans[0].Error).ToNot(HaveOccurred())
+		g.Expect(plans[0].UpgradePlans).To(HaveLen(1))
+		g.Expect(plans[0].UpgradePlans[0].Providers).To(HaveLen(2))
+		g.Expect(plans[0].Health).ToNot(BeNil())
+
+		// The failure to plan the upgrade of a management cluster is reported only for that management cluster.
+		g.Expect(plans[1].Kubeconfig).To(Equal(kubeconfigs[1]))
+		g.Expect(plans[1].Error).To(HaveOccurred())
+		g.Expect(plans[1].UpgradePlans).To(BeEmpty())
+	})
+	t.Run("returns a plan for each management cluster with more management clusters than workers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		kubeconfigs := []Kubeconfig{
+			{Path: "kubeconfig", Context: "mgmt-context"},
+			{Path: "kubeconfig", Context: "unknown-context"},
+			{Path: "kubeconfig", Context: "mgmt-context"},
+		}
+		plans, err := fakeClientForUpgrade().PlanFleetUpgrade(context.Background(), PlanFleetUpgradeOptions{
+			Kubeconfigs: kubeconfigs,
+			Concurrency: 2,
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(plans).To(HaveLen(3))
+		for i := range plans {
+			g.Expect(plans[i].Kubeconfig).To(Equal(kubeconfigs[i]))
+		}
+		g.Expect(plans[0].Error).ToNot(HaveOccurred())
+		g.Expect(plans[1].Error).To(HaveOccurred())
+		g.Expect(plans[2].Error).ToNot(HaveOccurred())
+	})
+}
@@ -18,8 +18,11 @@ package client
 
 import (
 	"context"
+	"time"
 
 	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 )
 
 // GetKubeconfigOptions carries all the options supported by GetKubeconfig.
@@ -33,6 +36,16 @@ type GetKubeconfigOptions struct {
 
 	// WorkloadClusterName is the name of the workload cluster.
 	WorkloadClusterName string
+
+	// User is the user to issue a short-lived client certificate for, signed by the workload cluster CA.
+	// If empty, the admin kubeconfig of the workload cluster is returned.
+	User string
+
+	// Groups are the groups of the User.
+	Groups []string
+
+	// TTL is the lifespan of the client certificate issued for the User. If empty, it defaults to one hour.
+	TTL time.Duration
 }
 
 func (c *clusterctlClient) GetKubeconfig(ctx context.Context, options GetKubeconfigOptions) (string, error) {
@@ -58,5 +71,26 @@ func (c *clusterctlClient) GetKubeconfig(ctx context.Context, options GetKubecon
 		options.Namespace = currentNamespace
 	}
 
-	return clusterClient.WorkloadCluster().GetKubeconfig(ctx, options.WorkloadClusterName, options.Namespace)
+	if options.User == "" {
+		if len(options.Groups) > 0 {
+			return "", errors.New("groups can be specified only together with a user")
+		}
+		if options.TTL != 0 {
+			return "", errors.New("the TTL can be specified only together with a user")
+		}
+		return clusterClient.WorkloadCluster().GetKubeconfig(ctx, options.WorkloadClusterName, options.Namespace)
+	}
+
+	if options.TTL < 0 {
+		return "", errors.New("the TTL of the client certificate must be greater than zero")
+	}
+	if options.TTL == 0 {
+		options.TTL = time.Hour
+	}
+
+	return clusterClient.WorkloadCluster().GetUserKubeconfig(ctx, options.WorkloadClusterName, options.Namespace, cluster.UserKubeconfigOptions{
+		User:   options.User,
+		Groups: options.Groups,
+		TTL:    options.TTL,
+	})
 }
@@ -19,6 +19,7 @@ package client
 import (
 	"context"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 
@@ -37,6 +38,12 @@ func Test_clusterctlClient_GetKubeconfig(t *testing.T) {
 	clusterClient.fakeProxy = test.NewFakeProxy().WithNamespace("").WithFakeCAPISetup()
 	badClient := newFakeClient(ctx, configClient).WithCluster(clusterClient)
 
+	// create a clusterctl client where the proxy returns the default namespace
+	goodKubeconfig := cluster.Kubeconfig{Path: "cluster2"}
+	goodClusterClient := newFakeCluster(goodKubeconfig, configClient)
+	goodClusterClient.fakeProxy.WithFakeCAPISetup()
+	goodClient := newFakeClient(ctx, configClient).WithCluster(goodClusterClient)
+
 	tests := []struct {
 		name      string
 		client    *fakeClient
@@ -54,6 +61,24 @@ func Test_clusterctlClient_GetKubeconfig(t *testing.T) {
 			options:   GetKubeconfigOptions{Kubeconfig: Kubeconfig(kubeconfig)},
 			expectErr: true,
 		},
+		{
+			name:      "returns error if groups are specified without a user",
+			client:    goodClient,
+			options:   GetKubeconfigOptions{Kubeconfig: Kubeconfig(goodKubeconfig), WorkloadClusterName: "test1", Groups: []string{"developers"}},
+			expectErr: true,
+		},
+		{
+			name:      "returns error if the TTL is specified without a user",
+			client:    goodClient,
+			options:   GetKubeconfigOptions{Kubeconfig: Kubeconfig(goodKubeconfig), WorkloadClusterName: "test1", TTL: 8 * time.Hour},
+			expectErr: true,
+		},
+		{
+			name:      "returns error if the TTL is negative",
+			client:    goodClient,
+			options:   GetKubeconfigOptions{Kubeconfig: Kubeconfig(goodKubeconfig), WorkloadClusterName: "test1", User: "jane", TTL: -time.Hour},
+			expectErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -18,6 +18,7 @@ package client
 
 import (
 	"context"
+	"crypto/ed25519"
 	"os"
 
 	"github.com/pkg/errors"
@@ -39,6 +40,14 @@ type MoveOptions struct {
 	// namespace will be used.
 	Namespace string
 
+	// ClusterName restricts the move operation to the Cluster with the given name, together with the objects it depends on
+	// e.g. the ClusterClass, the ClusterResourceSets or the identities. If unspecified, all the Clusters in Namespace are moved.
+	ClusterName string
+
+	// ClusterSelector restricts the move operation to the Clusters matching the given label selector, together with
+	// the objects they depend on. If unspecified, all the Clusters in Namespace are moved.
+	ClusterSelector string
+
 	// ExperimentalResourceMutatorFn accepts any number of resource mutator functions that are applied on all resources being moved.
 	// This is an experimental feature and is exposed only from the library and not (yet) through the CLI.
 	ExperimentalResourceMutators []cluster.ResourceMutatorFunc
@@ -49,8 +58,33 @@ type MoveOptions struct {
 	// ToDirectory save configuration to directory.
 	ToDirectory string
 
+	// DirectoryPassphrase, if set, is used to encrypt the files written to ToDirectory, and to decrypt the files
+	// read from FromDirectory.
+	DirectoryPassphrase []byte
+
+	// DirectorySigningKey, if set, is used to sign the manifest listing all the files written to ToDirectory with their digests.
+	DirectorySigningKey ed25519.PrivateKey
+
+	// DirectoryVerificationKey, if set, is used to verify the signature of the manifest of the files read from FromDirectory
+	// before restoring any object; if set, FromDirectory fails if the directory does not contain a signed manifest.
+	DirectoryVerificationKey ed25519.PublicKey
+
+	// DirectorySkipSignatureVerification allows FromDirectory to read a directory with a signed manifest without
+	// verifying its signature; if not set, FromDirectory fails if the manifest is signed and DirectoryVerificationKey is not set.
+	DirectorySkipSignatureVerification bool
+
 	// DryRun means the move action is a dry run, no real action will be performed.
 	DryRun bool
+
+	// CheckpointFile is the file where the progress of the move operation is persisted, so a move interrupted
+	// midway can be resumed or rolled back. If empty, the progress is not persisted.
+	CheckpointFile string
+
+	// Resume completes a move operation interrupted midway, using the progress recorded in CheckpointFile.
+	Resume bool
+
+	// Rollback undoes a move operation interrupted midway, using the progress recorded in CheckpointFile.
+	Rollback bool
 }
 
 func (c *clusterctlClient) Move(ctx context.Context, options MoveOptions) error {
@@ -66,6 +100,42 @@ func (c *clusterctlClient) Move(ctx context.Context, options MoveOptions) error
 		return errors.Errorf("at least one of FromDirectory, ToDirectory and ToKubeconfig must be set")
 	}
 
+	if options.CheckpointFile != "" && (options.DryRun || options.FromDirectory != "" || options.ToDirectory != "") {
+		return errors.Errorf("CheckpointFile can't be used with DryRun, FromDirectory or ToDirectory")
+	}
+
+	if options.Resume && options.Rollback {
+		return errors.Errorf("can't set both Resume and Rollback")
+	}
+
+	if (options.Resume || options.Rollback) && options.CheckpointFile == "" {
+		return errors.Errorf("CheckpointFile must be set when using Resume or Rollback")
+	}
+
+	if (options.ClusterName != "" || options.ClusterSelector != "") && (options.Resume || options.Rollback || options.FromDirectory != "") {
+		return errors.Errorf("ClusterName and ClusterSelector can't be used with Resume, Rollback or FromDirectory")
+	}
+
+	if len(options.DirectoryPassphrase) > 0 && options.FromDirectory == "" && options.ToDirectory == "" {
+		return errors.Errorf("DirectoryPassphrase can be used only with FromDirectory or ToDirectory")
+	}
+
+	if options.DirectorySigningKey != nil && options.ToDirectory == "" {
+		return errors.Errorf("DirectorySigningKey can be used only with ToDirectory")
+	}
+
+	if options.DirectoryVerificationKey != nil && options.FromDirectory == "" {
+		return errors.Errorf("DirectoryVerificationKey can be used only with FromDirectory")
+	}
+
+	if options.DirectorySkipSignatureVerification && options.FromDirectory == "" {
+		return errors.Errorf("DirectorySkipSignatureVerification can be used only with FromDirectory")
+	}
+
+	if options.DirectorySkipSignatureVerification && options.DirectoryVerificationKey != nil {
+		return errors.Errorf("DirectorySkipSignatureVerification and DirectoryVerificationKey are mutually exclusive")
+	}
+
 	if options.ToDirectory != "" {
 		return c.toDirectory(ctx, options)
 	} else if options.FromDirectory != "" {
@@ -82,6 +152,22 @@ func (c *clusterctlClient) move(ctx context.Context, options MoveOptions) error
 		return err
 	}
 
+	var toCluster cluster.Client
+	if !options.DryRun {
+		// Get the client for interacting with the target management cluster.
+		if toCluster, err = c.getClusterClient(ctx, options.ToKubeconfig); err != nil {
+			return err
+		}
+	}
+
+	// Resume and rollback are using the namespace recorded in the checkpoint file.
+	if options.Resume {
+		return fromCluster.ObjectMover().ResumeMove(ctx, toCluster, options.CheckpointFile, options.ExperimentalResourceMutators...)
+	}
+	if options.Rollback {
+		return fromCluster.ObjectMover().RollbackMove(ctx, toCluster, options.CheckpointFile)
+	}
+
 	// If the option specifying the Namespace is empty, try to detect it.
 	if options.Namespace == "" {
 		currentNamespace, err := fromCluster.Proxy().CurrentNamespace()
@@ -91,15 +177,46 @@ func (c *clusterctlClient) move(ctx context.Context, options MoveOptions) error
 		options.Namespace = currentNamespace
 	}
 
+	if options.CheckpointFile != "" {
+		return fromCluster.ObjectMover().MoveWithCheckpoint(ctx, options.Namespace, options.clusterSelector(), toCluster, options.CheckpointFile, options.ExperimentalResourceMutators...)
+	}
+	return fromCluster.ObjectMover().Move(ctx, options.Namespace, options.clusterSelector(), toCluster, options.DryRun, options.ExperimentalResourceMutators...)
+}
+
+func (c *clusterctlClient) PlanMove(ctx context.Context, options MoveOptions) (*MovePlan, error) {
+	if options.FromDirectory != "" || options.ToDirectory != "" {
+		return nil, errors.Errorf("FromDirectory and ToDirectory can't be used when planning a move")
+	}
+
+	// Get the client for interacting with the source management cluster.
+	fromCluster, err := c.getClusterClient(ctx, options.FromKubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// If the option specifying the Namespace is empty, try to detect it.
+	if options.Namespace == "" {
+		currentNamespace, err := fromCluster.Proxy().CurrentNamespace()
+		if err != nil {
+			return nil, err
+		}
+		options.Namespace = currentNamespace
+	}
+
+	// Get the client for interacting with the target management cluster, if any; if not set, checks on the
+	// target management cluster are skipped.
 	var toCluster cluster.Client
-	if !options.DryRun {
-		// Get the client for interacting with the target management cluster.
+	if options.ToKubeconfig != (Kubeconfig{}) {
 		if toCluster, err = c.getClusterClient(ctx, options.ToKubeconfig); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	return fromCluster.ObjectMover().Move(ctx, options.Namespace, toCluster, options.DryRun, options.ExperimentalResourceMutators...)
+	plan, err := fromCluster.ObjectMover().Plan(ctx, options.Namespace, options.clusterSelector(), toCluster)
+	if err != nil {
+		return nil, err
+	}
+	return (*MovePlan)(plan), nil
 }
 
 func (c *clusterctlClient) fromDirectory(ctx context.Context, options MoveOptions) error {
@@ -112,7 +229,7 @@ func (c *clusterctlClient) fromDirectory(ctx context.Context, options MoveOption
 		return err
 	}
 
-	return toCluster.ObjectMover().FromDirectory(ctx, toCluster, options.FromDirectory)
+	return toCluster.ObjectMover().FromDirectory(ctx, toCluster, options.FromDirectory, options.directoryOptions())
 }
 
 func (c *clusterctlClient) toDirectory(ctx context.Context, options MoveOptions) error {
@@ -134,7 +251,25 @@ func (c *clusterctlClient) toDirectory(ctx context.Context, options MoveOptions)
 		return err
 	}
 
-	return fromCluster.ObjectMover().ToDirectory(ctx, options.Namespace, options.ToDirectory)
+	return fromCluster.ObjectMover().ToDirectory(ctx, options.Namespace, options.clusterSelector(), options.ToDirectory, options.directoryOptions())
+}
+
+// directoryOptions returns the options for protecting the files written to ToDirectory and read from FromDirectory.
+func (o MoveOptions) directoryOptions() cluster.DirectoryOptions {
+	return cluster.DirectoryOptions{
+		Passphrase:                o.DirectoryPassphrase,
+		SigningKey:                o.DirectorySigningKey,
+		VerificationKey:           o.DirectoryVerificationKey,
+		SkipSignatureVerification: o.DirectorySkipSignatureVerification,
+	}
+}
+
+// clusterSelector returns the selector for the Clusters to be moved.
+func (o MoveOptions) clusterSelector() cluster.ClusterSelector {
+	return cluster.ClusterSelector{
+		Name:          o.ClusterName,
+		LabelSelector: o.ClusterSelector,
+	}
 }
 
 func (c *clusterctlClient) getClusterClient(ctx context.Context, kubeconfig Kubeconfig) (cluster.Client, error) {
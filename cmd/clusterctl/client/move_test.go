@@ -18,6 +18,7 @@ package client
 
 import (
 	"context"
+	"crypto/ed25519"
 	"os"
 	"testing"
 
@@ -124,6 +125,124 @@ func Test_clusterctlClient_Move(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "does not return an error if CheckpointFile is set",
+			fields: fields{
+				client: fakeClientForMove(),
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ToKubeconfig:   Kubeconfig{Path: "kubeconfig", Context: "worker-context"},
+					CheckpointFile: "checkpoint.yaml",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "does not return an error if Resume and CheckpointFile are set",
+			fields: fields{
+				client: fakeClientForMove(),
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ToKubeconfig:   Kubeconfig{Path: "kubeconfig", Context: "worker-context"},
+					CheckpointFile: "checkpoint.yaml",
+					Resume:         true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "returns an error if Resume is set without CheckpointFile",
+			fields: fields{
+				client: fakeClientForMove(),
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ToKubeconfig:   Kubeconfig{Path: "kubeconfig", Context: "worker-context"},
+					Resume:         true,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "returns an error if both Resume and Rollback are set",
+			fields: fields{
+				client: fakeClientForMove(),
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ToKubeconfig:   Kubeconfig{Path: "kubeconfig", Context: "worker-context"},
+					CheckpointFile: "checkpoint.yaml",
+					Resume:         true,
+					Rollback:       true,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "returns an error if DirectoryPassphrase is set without FromDirectory or ToDirectory",
+			fields: fields{
+				client: fakeClientForMove(),
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig:      Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ToKubeconfig:        Kubeconfig{Path: "kubeconfig", Context: "worker-context"},
+					DirectoryPassphrase: []byte("passphrase"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "returns an error if CheckpointFile is set with DryRun",
+			fields: fields{
+				client: fakeClientForMove(),
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					CheckpointFile: "checkpoint.yaml",
+					DryRun:         true,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "does not return an error if ClusterName and ClusterSelector are set",
+			fields: fields{
+				client: fakeClientForMove(),
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig:  Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ToKubeconfig:    Kubeconfig{Path: "kubeconfig", Context: "worker-context"},
+					ClusterName:     "foo",
+					ClusterSelector: "env=dev",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "returns an error if ClusterSelector is set with Resume",
+			fields: fields{
+				client: fakeClientForMove(),
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig:  Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ToKubeconfig:    Kubeconfig{Path: "kubeconfig", Context: "worker-context"},
+					CheckpointFile:  "checkpoint.yaml",
+					Resume:          true,
+					ClusterSelector: "env=dev",
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -142,6 +261,65 @@ func Test_clusterctlClient_Move(t *testing.T) {
 	}
 }
 
+func Test_clusterctlClient_PlanMove(t *testing.T) {
+	tests := []struct {
+		name          string
+		options       MoveOptions
+		wantNamespace string
+		wantErr       bool
+	}{
+		{
+			name: "returns a plan without a target cluster",
+			options: MoveOptions{
+				FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+				Namespace:      "ns1",
+			},
+			wantNamespace: "ns1",
+		},
+		{
+			name: "returns a plan with a target cluster",
+			options: MoveOptions{
+				FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+				ToKubeconfig:   Kubeconfig{Path: "kubeconfig", Context: "worker-context"},
+				Namespace:      "ns1",
+			},
+			wantNamespace: "ns1",
+		},
+		{
+			name: "returns an error if to cluster client is not found",
+			options: MoveOptions{
+				FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+				ToKubeconfig:   Kubeconfig{Path: "kubeconfig", Context: "does-not-exist"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "returns an error if ToDirectory is set",
+			options: MoveOptions{
+				FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+				ToDirectory:    "/var/cache/toDirectory",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ctx := context.Background()
+
+			plan, err := fakeClientForMove().PlanMove(ctx, tt.options)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(plan.Namespace).To(Equal(tt.wantNamespace))
+		})
+	}
+}
+
 func Test_clusterctlClient_ToDirectory(t *testing.T) {
 	dir, err := os.MkdirTemp("/tmp", "cluster-api")
 	if err != nil {
@@ -189,6 +367,49 @@ func Test_clusterctlClient_ToDirectory(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "does not return error if passphrase and signing key are set",
+			fields: fields{
+				client: fakeClientForMove(),
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig:      Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ToDirectory:         dir,
+					DirectoryPassphrase: []byte("passphrase"),
+					DirectorySigningKey: ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "returns an error if verification key is set",
+			fields: fields{
+				client: fakeClientForMove(),
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig:           Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ToDirectory:              dir,
+					DirectoryVerificationKey: ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)).Public().(ed25519.PublicKey),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "returns an error if signature verification is skipped",
+			fields: fields{
+				client: fakeClientForMove(),
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig:                     Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ToDirectory:                        dir,
+					DirectorySkipSignatureVerification: true,
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -254,6 +475,21 @@ func Test_clusterctlClient_FromDirectory(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "returns an error if signature verification is skipped and verification key is set",
+			fields: fields{
+				client: fakeClientForMove(),
+			},
+			args: args{
+				options: MoveOptions{
+					ToKubeconfig:                       Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					FromDirectory:                      dir,
+					DirectoryVerificationKey:           ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)).Public().(ed25519.PublicKey),
+					DirectorySkipSignatureVerification: true,
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -307,11 +543,30 @@ type fakeObjectMover struct {
 	fromDirectoryErr error
 }
 
-func (f *fakeObjectMover) Move(_ context.Context, _ string, _ cluster.Client, _ bool, _ ...cluster.ResourceMutatorFunc) error {
+func (f *fakeObjectMover) Move(_ context.Context, _ string, _ cluster.ClusterSelector, _ cluster.Client, _ bool, _ ...cluster.ResourceMutatorFunc) error {
+	return f.moveErr
+}
+
+func (f *fakeObjectMover) MoveWithCheckpoint(_ context.Context, _ string, _ cluster.ClusterSelector, _ cluster.Client, _ string, _ ...cluster.ResourceMutatorFunc) error {
+	return f.moveErr
+}
+
+func (f *fakeObjectMover) ResumeMove(_ context.Context, _ cluster.Client, _ string, _ ...cluster.ResourceMutatorFunc) error {
 	return f.moveErr
 }
 
-func (f *fakeObjectMover) ToDirectory(_ context.Context, _ string, _ string) error {
+func (f *fakeObjectMover) RollbackMove(_ context.Context, _ cluster.Client, _ string) error {
+	return f.moveErr
+}
+
+func (f *fakeObjectMover) Plan(_ context.Context, namespace string, _ cluster.ClusterSelector, _ cluster.Client) (*cluster.MovePlan, error) {
+	if f.moveErr != nil {
+		return nil, f.moveErr
+	}
+	return &cluster.MovePlan{Namespace: namespace}, nil
+}
+
+func (f *fakeObjectMover) ToDirectory(_ context.Context, _ string, _ cluster.ClusterSelector, _ string, _ cluster.DirectoryOptions) error {
 	return f.toDirectoryErr
 }
 
@@ -319,7 +574,7 @@ func (f *fakeObjectMover) Backup(_ context.Context, _ string, _ string) error {
 	return f.toDirectoryErr
 }
 
-func (f *fakeObjectMover) FromDirectory(_ context.Context, _ cluster.Client, _ string) error {
+func (f *fakeObjectMover) FromDirectory(_ context.Context, _ cluster.Client, _ string, _ cluster.DirectoryOptions) error {
 	return f.fromDirectoryErr
 }
 
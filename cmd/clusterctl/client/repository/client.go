@@ -89,7 +89,7 @@ func (c *repositoryClient) ClusterClasses(version string) ClusterClassClient {
 }
 
 func (c *repositoryClient) Metadata(version string) MetadataClient {
-	return newMetadataClient(c.Provider, version, c.repository, c.configClient.Variables())
+	return newMetadataClient(c.Provider, version, c.repository, c.configClient)
 }
 
 // Option is a configuration option supplied to New.
@@ -194,7 +194,30 @@ func repositoryFactory(ctx context.Context, providerConfig config.Provider, conf
 			return repo, err
 		}
 
-		return nil, errors.Errorf("invalid provider url. Only GitHub and GitLab are supported for %q schema", rURL.Scheme)
+		// otherwise the url is a generic HTTP repository
+		repo, err := NewHTTPRepository(ctx, providerConfig, configVariablesClient)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating the HTTP repository client")
+		}
+		return repo, err
+	}
+
+	// if the url is a generic HTTP repository
+	if rURL.Scheme == httpScheme {
+		repo, err := NewHTTPRepository(ctx, providerConfig, configVariablesClient)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating the HTTP repository client")
+		}
+		return repo, err
+	}
+
+	// if the url is an OCI repository
+	if rURL.Scheme == ociScheme {
+		repo, err := NewOCIRepository(ctx, providerConfig, configVariablesClient)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating the OCI repository client")
+		}
+		return repo, err
 	}
 
 	// if the url is a local filesystem repository
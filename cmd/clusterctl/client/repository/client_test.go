@@ -77,6 +77,20 @@ func Test_newRepositoryClient_LocalFileSystemRepository(t *testing.T) {
 			},
 			expected: &gitLabRepository{},
 		},
+		{
+			name: "successfully creates repository client with OCI backend",
+			fields: fields{
+				provider: config.NewProvider("bar", "oci://registry.example.org/org/repo:v1.0.0/file.yaml", clusterctlv1.BootstrapProviderType),
+			},
+			expected: &ociRepository{},
+		},
+		{
+			name: "successfully creates repository client with HTTP backend",
+			fields: fields{
+				provider: config.NewProvider("bar", "https://artifacts.example.org/providers/bootstrap-bar/v1.0.0/file.yaml", clusterctlv1.BootstrapProviderType),
+			},
+			expected: &httpRepository{},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -69,6 +69,11 @@ type Components interface {
 	// This value is derived by the component YAML.
 	Variables() []string
 
+	// VariableMap used by the provider components with their default values. If the value is `nil`, there is no
+	// default and the variable is required.
+	// This value is derived by the component YAML.
+	VariableMap() map[string]*string
+
 	// Images required to install the provider components.
 	// This value is derived by the component YAML.
 	Images() []string
@@ -94,6 +99,7 @@ type components struct {
 	config.Provider
 	version         string
 	variables       []string
+	variableMap     map[string]*string
 	images          []string
 	targetNamespace string
 	objs            []unstructured.Unstructured
@@ -110,6 +116,10 @@ func (c *components) Variables() []string {
 	return c.variables
 }
 
+func (c *components) VariableMap() map[string]*string {
+	return c.variableMap
+}
+
 func (c *components) Images() []string {
 	return c.images
 }
@@ -199,6 +209,11 @@ func NewComponents(input ComponentsInput) (Components, error) {
 		return nil, err
 	}
 
+	variableMap, err := input.Processor.GetVariableMap(input.RawYaml)
+	if err != nil {
+		return nil, err
+	}
+
 	// If requested, we are skipping the call to the template processor; however, it is important to
 	// notice that this could work only if the rawYaml is a valid yaml by itself.
 	processedYaml := input.RawYaml
@@ -280,6 +295,7 @@ func NewComponents(input ComponentsInput) (Components, error) {
 		Provider:        input.Provider,
 		version:         input.Options.Version,
 		variables:       variables,
+		variableMap:     variableMap,
 		images:          images,
 		targetNamespace: input.Options.TargetNamespace,
 		objs:            objs,
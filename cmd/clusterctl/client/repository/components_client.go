@@ -96,8 +96,16 @@ func (f *componentsClient) getRawBytes(ctx context.Context, options *ComponentsO
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to read %q from provider's repository %q", path, f.provider.ManifestLabel())
 		}
+
+		// Verify the components read from the provider repository before returning them.
+		if err := verifyProviderFile(ctx, f.configClient, f.provider, f.repository, options.Version, path, file); err != nil {
+			return nil, err
+		}
 	} else {
 		log.Info("Using", "override", path, "provider", f.provider.ManifestLabel(), "version", options.Version)
+		if err := warnIfOverrideNotVerified(f.configClient, f.provider, options.Version, path); err != nil {
+			return nil, err
+		}
 	}
 	return file, nil
 }
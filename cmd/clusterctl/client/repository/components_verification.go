@@ -0,0 +1,202 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+)
+
+// signatureFileSuffix is the suffix of the files with the detached signature of a release asset,
+// e.g. infrastructure-components.yaml.sig.
+const signatureFileSuffix = ".sig"
+
+// verifyProviderFile verifies a file read from the repository of a provider, e.g. the components file or the metadata file,
+// according to the verification configuration for the provider.
+func verifyProviderFile(ctx context.Context, configClient config.Client, provider config.Provider, repository Repository, version, path string, content []byte) error {
+	verification, err := configClient.Verification().Get(provider.ManifestLabel())
+	if err != nil {
+		return err
+	}
+	if err := verifyFile(ctx, repository, verification, version, path, content); err != nil {
+		return errors.Wrapf(err, "failed to verify %q from provider's repository %q", path, provider.ManifestLabel())
+	}
+	return nil
+}
+
+// warnIfOverrideNotVerified logs a warning when a file is read from the overrides layer, which is not verified,
+// while verification is required for the provider.
+func warnIfOverrideNotVerified(configClient config.Client, provider config.Provider, version, path string) error {
+	verification, err := configClient.Verification().Get(provider.ManifestLabel())
+	if err != nil {
+		return err
+	}
+	if verification.Required {
+		logf.Log.Info("Warning: verification is required, but the override is not verified", "override", path, "provider", provider.ManifestLabel(), "version", version)
+	}
+	return nil
+}
+
+// verifyFile verifies a file read from a provider repository according to the verification configuration:
+//   - if a checksum file is configured, the checksum file must exist in the same release and the checksum of the file must match.
+//   - if a public key is configured, the file must be signed, either directly with a <file>.sig
+//     signature or with a <checksum file>.sig signature of the checksum file.
+//
+// Signatures are compatible with the ones generated by cosign sign-blob in keyed mode, so they can be verified offline.
+func verifyFile(ctx context.Context, repository Repository, verification config.ComponentsVerification, version, path string, content []byte) error {
+	log := logf.Log
+
+	if verification.Required && verification.PublicKey == "" {
+		return errors.New("verification is required, but no public key is configured for the provider. Please add the public key to the componentsVerification configuration")
+	}
+
+	var checksums []byte
+	if verification.ChecksumFile != "" {
+		var err error
+		checksums, err = repository.GetFile(ctx, version, verification.ChecksumFile)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read checksum file %q", verification.ChecksumFile)
+		}
+		if err := verifyChecksum(checksums, filepath.Base(path), content); err != nil {
+			return err
+		}
+		log.V(5).Info("Verified", "file", path, "checksumFile", verification.ChecksumFile)
+	}
+
+	if verification.PublicKey == "" {
+		return nil
+	}
+
+	publicKey, err := loadPublicKey(verification.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	// Verify the signature of the file, if any.
+	if signature, err := repository.GetFile(ctx, version, path+signatureFileSuffix); err == nil {
+		if err := verifySignature(publicKey, content, signature); err != nil {
+			return errors.Wrapf(err, "failed to verify signature %q", path+signatureFileSuffix)
+		}
+		log.V(5).Info("Verified", "file", path, "signature", path+signatureFileSuffix)
+		return nil
+	}
+
+	// Otherwise verify the signature of the checksum file, which is already verified to match the file.
+	if checksums != nil {
+		if signature, err := repository.GetFile(ctx, version, verification.ChecksumFile+signatureFileSuffix); err == nil {
+			if err := verifySignature(publicKey, checksums, signature); err != nil {
+				return errors.Wrapf(err, "failed to verify signature %q", verification.ChecksumFile+signatureFileSuffix)
+			}
+			log.V(5).Info("Verified", "file", path, "signature", verification.ChecksumFile+signatureFileSuffix)
+			return nil
+		}
+	}
+
+	return errors.Errorf("failed to find a signature for %q", path)
+}
+
+// verifyChecksum verifies the sha256 checksum of a file using a checksum file in the format of the sha256sum command.
+func verifyChecksum(checksums []byte, name string, content []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		// NOTE: sha256sum prefixes file names with * when reading in binary mode.
+		if strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+
+		expected, err := hex.DecodeString(fields[0])
+		if err != nil {
+			return errors.Wrapf(err, "invalid checksum for %q", name)
+		}
+		actual := sha256.Sum256(content)
+		if !bytes.Equal(expected, actual[:]) {
+			return errors.Errorf("checksum mismatch for %q: expected %s, got %s", name, fields[0], hex.EncodeToString(actual[:]))
+		}
+		return nil
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Wrap(err, "failed to read checksum file")
+	}
+	return errors.Errorf("failed to find a checksum for %q", name)
+}
+
+// loadPublicKey reads a PEM encoded public key, e.g. the cosign.pub file generated by cosign generate-key-pair.
+func loadPublicKey(path string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // The path is read from the clusterctl configuration file.
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read public key %q", path)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.Errorf("failed to decode public key %q: no PEM data found", path)
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse public key %q", path)
+	}
+	return publicKey, nil
+}
+
+// verifySignature verifies a detached signature, either base64 encoded like the ones generated by cosign sign-blob, or raw.
+// Following cosign, ECDSA and RSA (PKCS #1 v1.5) signatures are verified against the sha256 digest of the content,
+// while Ed25519 signatures are verified against the content itself.
+func verifySignature(publicKey crypto.PublicKey, content, signature []byte) error {
+	if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature))); err == nil {
+		signature = decoded
+	}
+	digest := sha256.Sum256(content)
+
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest[:], signature) {
+			return errors.New("invalid signature")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+			return errors.Wrap(err, "invalid signature")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, content, signature) {
+			return errors.New("invalid signature")
+		}
+	default:
+		return errors.Errorf("unsupported public key type %T", publicKey)
+	}
+	return nil
+}
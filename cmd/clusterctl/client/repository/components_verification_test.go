@@ -0,0 +1,226 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_verifyFile(t *testing.T) {
+	g := NewWithT(t)
+
+	components := []byte("components")
+	digest := sha256.Sum256(components)
+	checksums := []byte(fmt.Sprintf("%s  metadata.yaml\n%s *components.yaml\n", hex.EncodeToString(make([]byte, 32)), hex.EncodeToString(digest[:])))
+
+	// Generate a key pair and sign like cosign sign-blob does, i.e. a base64 encoded ASN.1 ECDSA signature of the sha256 digest.
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).ToNot(HaveOccurred())
+	publicKeyPath := writePublicKey(t, &privateKey.PublicKey)
+	sign := func(content []byte) []byte {
+		digest := sha256.Sum256(content)
+		signature, err := ecdsa.SignASN1(rand.Reader, privateKey, digest[:])
+		g.Expect(err).ToNot(HaveOccurred())
+		return []byte(base64.StdEncoding.EncodeToString(signature))
+	}
+
+	otherPrivateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).ToNot(HaveOccurred())
+	otherPublicKeyPath := writePublicKey(t, &otherPrivateKey.PublicKey)
+
+	tests := []struct {
+		name         string
+		repository   Repository
+		verification config.ComponentsVerification
+		wantErr      bool
+	}{
+		{
+			name: "pass without verification",
+			repository: NewMemoryRepository().
+				WithFile("v1.0.0", "components.yaml", components),
+			verification: config.ComponentsVerification{},
+		},
+		{
+			name: "pass if the checksum matches",
+			repository: NewMemoryRepository().
+				WithFile("v1.0.0", "components.yaml", components).
+				WithFile("v1.0.0", "checksums.txt", checksums),
+			verification: config.ComponentsVerification{ChecksumFile: "checksums.txt"},
+		},
+		{
+			name: "fails if the checksum does not match",
+			repository: NewMemoryRepository().
+				WithFile("v1.0.0", "components.yaml", components).
+				WithFile("v1.0.0", "checksums.txt", []byte(hex.EncodeToString(make([]byte, 32))+"  components.yaml")),
+			verification: config.ComponentsVerification{ChecksumFile: "checksums.txt"},
+			wantErr:      true,
+		},
+		{
+			name: "fails if the checksum is missing",
+			repository: NewMemoryRepository().
+				WithFile("v1.0.0", "components.yaml", components).
+				WithFile("v1.0.0", "checksums.txt", []byte(hex.EncodeToString(digest[:])+"  other.yaml")),
+			verification: config.ComponentsVerification{ChecksumFile: "checksums.txt"},
+			wantErr:      true,
+		},
+		{
+			name: "fails if the checksum file is missing",
+			repository: NewMemoryRepository().
+				WithFile("v1.0.0", "components.yaml", components),
+			verification: config.ComponentsVerification{ChecksumFile: "checksums.txt"},
+			wantErr:      true,
+		},
+		{
+			name: "pass if the signature of the components is valid",
+			repository: NewMemoryRepository().
+				WithFile("v1.0.0", "components.yaml", components).
+				WithFile("v1.0.0", "components.yaml.sig", sign(components)),
+			verification: config.ComponentsVerification{Required: true, PublicKey: publicKeyPath},
+		},
+		{
+			name: "pass if the signature of the checksum file is valid",
+			repository: NewMemoryRepository().
+				WithFile("v1.0.0", "components.yaml", components).
+				WithFile("v1.0.0", "checksums.txt", checksums).
+				WithFile("v1.0.0", "checksums.txt.sig", sign(checksums)),
+			verification: config.ComponentsVerification{Required: true, ChecksumFile: "checksums.txt", PublicKey: publicKeyPath},
+		},
+		{
+			name: "fails if the signature of the components is not valid",
+			repository: NewMemoryRepository().
+				WithFile("v1.0.0", "components.yaml", components).
+				WithFile("v1.0.0", "components.yaml.sig", sign(components)),
+			verification: config.ComponentsVerification{PublicKey: otherPublicKeyPath},
+			wantErr:      true,
+		},
+		{
+			name: "fails if the signature of the checksum file is not valid",
+			repository: NewMemoryRepository().
+				WithFile("v1.0.0", "components.yaml", components).
+				WithFile("v1.0.0", "checksums.txt", checksums).
+				WithFile("v1.0.0", "checksums.txt.sig", sign([]byte("other checksums"))),
+			verification: config.ComponentsVerification{ChecksumFile: "checksums.txt", PublicKey: publicKeyPath},
+			wantErr:      true,
+		},
+		{
+			name: "fails if the signature is missing",
+			repository: NewMemoryRepository().
+				WithFile("v1.0.0", "components.yaml", components).
+				WithFile("v1.0.0", "checksums.txt", checksums),
+			verification: config.ComponentsVerification{ChecksumFile: "checksums.txt", PublicKey: publicKeyPath},
+			wantErr:      true,
+		},
+		{
+			name: "fails if the public key does not exist",
+			repository: NewMemoryRepository().
+				WithFile("v1.0.0", "components.yaml", components).
+				WithFile("v1.0.0", "components.yaml.sig", sign(components)),
+			verification: config.ComponentsVerification{PublicKey: filepath.Join(t.TempDir(), "missing.pub")},
+			wantErr:      true,
+		},
+		{
+			name: "fails if verification is required but there is no public key",
+			repository: NewMemoryRepository().
+				WithFile("v1.0.0", "components.yaml", components).
+				WithFile("v1.0.0", "checksums.txt", checksums),
+			verification: config.ComponentsVerification{Required: true, ChecksumFile: "checksums.txt"},
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := verifyFile(context.Background(), tt.repository, tt.verification, "v1.0.0", "components.yaml", components)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
+
+func Test_componentsClient_Get_verification(t *testing.T) {
+	g := NewWithT(t)
+
+	p1 := config.NewProvider("p1", "", clusterctlv1.BootstrapProviderType)
+
+	configClient, err := config.New(context.Background(), "", config.InjectReader(test.NewFakeReader().
+		WithVar(config.VerificationConfigKey, "required: true")))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	repository := NewMemoryRepository().
+		WithPaths("root", "components.yaml").
+		WithDefaultVersion("v1.0.0").
+		WithFile("v1.0.0", "components.yaml", namespaceYaml)
+
+	f := newComponentsClient(p1, repository, configClient)
+	_, err = f.Raw(context.Background(), ComponentsOptions{Version: "v1.0.0"})
+	g.Expect(err).To(MatchError(ContainSubstring("failed to verify \"components.yaml\" from provider's repository \"bootstrap-p1\"")))
+}
+
+func Test_metadataClient_Get_verification(t *testing.T) {
+	g := NewWithT(t)
+
+	p1 := config.NewProvider("p1", "", clusterctlv1.BootstrapProviderType)
+
+	configClient, err := config.New(context.Background(), "", config.InjectReader(test.NewFakeReader().
+		WithVar(config.VerificationConfigKey, "required: true")))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	repository := NewMemoryRepository().
+		WithPaths("root", "components.yaml").
+		WithDefaultVersion("v1.0.0").
+		WithMetadata("v1.0.0", &clusterctlv1.Metadata{})
+
+	f := newMetadataClient(p1, "v1.0.0", repository, configClient)
+	_, err = f.Get(context.Background())
+	g.Expect(err).To(MatchError(ContainSubstring("failed to verify \"metadata.yaml\" from provider's repository \"bootstrap-p1\"")))
+}
+
+func writePublicKey(t *testing.T, publicKey *ecdsa.PublicKey) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "cosign.pub")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -40,22 +40,22 @@ type MetadataClient interface {
 
 // metadataClient implements MetadataClient.
 type metadataClient struct {
-	configVarClient config.VariablesClient
-	provider        config.Provider
-	version         string
-	repository      Repository
+	configClient config.Client
+	provider     config.Provider
+	version      string
+	repository   Repository
 }
 
 // ensure metadataClient implements MetadataClient.
 var _ MetadataClient = &metadataClient{}
 
 // newMetadataClient returns a metadataClient.
-func newMetadataClient(provider config.Provider, version string, repository Repository, config config.VariablesClient) *metadataClient {
+func newMetadataClient(provider config.Provider, version string, repository Repository, configClient config.Client) *metadataClient {
 	return &metadataClient{
-		configVarClient: config,
-		provider:        provider,
-		version:         version,
-		repository:      repository,
+		configClient: configClient,
+		provider:     provider,
+		version:      version,
+		repository:   repository,
 	}
 }
 
@@ -66,7 +66,7 @@ func (f *metadataClient) Get(ctx context.Context) (*clusterctlv1.Metadata, error
 	version := f.version
 
 	file, err := getLocalOverride(&newOverrideInput{
-		configVariablesClient: f.configVarClient,
+		configVariablesClient: f.configClient.Variables(),
 		provider:              f.provider,
 		version:               version,
 		filePath:              metadataFile,
@@ -80,8 +80,16 @@ func (f *metadataClient) Get(ctx context.Context) (*clusterctlv1.Metadata, error
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to read %q from the repository for provider %q", metadataFile, f.provider.ManifestLabel())
 		}
+
+		// Verify the metadata read from the provider repository, so the version compatibility matrix can be trusted.
+		if err := verifyProviderFile(ctx, f.configClient, f.provider, f.repository, version, metadataFile, file); err != nil {
+			return nil, err
+		}
 	} else {
 		log.V(1).Info("Using", "override", metadataFile, "provider", f.provider.ManifestLabel(), "version", version)
+		if err := warnIfOverrideNotVerified(f.configClient, f.provider, version, metadataFile); err != nil {
+			return nil, err
+		}
 	}
 
 	// Convert the yaml into a typed object
@@ -116,11 +116,14 @@ func Test_metadataClient_Get(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			g := NewWithT(t)
 
+			configClient, err := config.New(context.Background(), "", config.InjectReader(test.NewFakeReader()))
+			g.Expect(err).ToNot(HaveOccurred())
+
 			f := &metadataClient{
-				configVarClient: test.NewFakeVariableClient(),
-				provider:        tt.fields.provider,
-				version:         tt.fields.version,
-				repository:      tt.fields.repository,
+				configClient: configClient,
+				provider:     tt.fields.provider,
+				version:      tt.fields.version,
+				repository:   tt.fields.repository,
 			}
 			got, err := f.Get(context.Background())
 			if tt.wantErr {
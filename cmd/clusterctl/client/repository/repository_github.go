@@ -26,6 +26,7 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/blang/semver/v4"
@@ -53,7 +54,10 @@ var (
 	errNotFound = errors.New("404 Not Found")
 
 	// Caches used to limit the number of GitHub API calls.
+	// NOTE: caches are shared by the repositories of all the management clusters clusterctl works with,
+	// which can be processed in parallel, and must be accessed using getCache and setCache.
 
+	cacheLock                  sync.RWMutex
 	cacheVersions              = map[string][]string{}
 	cacheReleases              = map[string]*github.RepositoryRelease{}
 	cacheFiles                 = map[string][]byte{}
@@ -61,6 +65,21 @@ var (
 	retryableOperationTimeout  = 1 * time.Minute
 )
 
+// getCache returns the value for a key from one of the repository caches.
+func getCache[T any](cache map[string]T, key string) (T, bool) {
+	cacheLock.RLock()
+	defer cacheLock.RUnlock()
+	value, ok := cache[key]
+	return value, ok
+}
+
+// setCache sets the value for a key in one of the repository caches.
+func setCache[T any](cache map[string]T, key string, value T) {
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	cache[key] = value
+}
+
 // gitHubRepository provides support for providers hosted on GitHub.
 //
 // We support GitHub repositories that use the release feature to publish artifacts and versions.
@@ -105,7 +124,7 @@ func (g *gitHubRepository) GetVersions(ctx context.Context) ([]string, error) {
 	log := logf.Log
 
 	cacheID := fmt.Sprintf("%s/%s", g.owner, g.repository)
-	if versions, ok := cacheVersions[cacheID]; ok {
+	if versions, ok := getCache(cacheVersions, cacheID); ok {
 		return versions, nil
 	}
 
@@ -140,7 +159,7 @@ func (g *gitHubRepository) GetVersions(ctx context.Context) ([]string, error) {
 		}
 	}
 
-	cacheVersions[cacheID] = versions
+	setCache(cacheVersions, cacheID, versions)
 	return versions, nil
 }
 
@@ -159,7 +178,7 @@ func (g *gitHubRepository) GetFile(ctx context.Context, version, path string) ([
 	log := logf.Log
 
 	cacheID := fmt.Sprintf("%s/%s:%s:%s", g.owner, g.repository, version, path)
-	if content, ok := cacheFiles[cacheID]; ok {
+	if content, ok := getCache(cacheFiles, cacheID); ok {
 		return content, nil
 	}
 
@@ -170,7 +189,7 @@ func (g *gitHubRepository) GetFile(ctx context.Context, version, path string) ([
 		if err != nil {
 			log.V(5).Info("error using httpGet to get file from GitHub releases, falling back to github client", "owner", g.owner, "repository", g.repository, "version", version, "path", path, "error", err)
 		} else {
-			cacheFiles[cacheID] = files
+			setCache(cacheFiles, cacheID, files)
 			return files, nil
 		}
 	}
@@ -193,7 +212,7 @@ func (g *gitHubRepository) GetFile(ctx context.Context, version, path string) ([
 		return nil, errors.Wrapf(err, "failed to download files from GitHub release %s", version)
 	}
 
-	cacheFiles[cacheID] = files
+	setCache(cacheFiles, cacheID, files)
 	return files, nil
 }
 
@@ -366,7 +385,7 @@ func (g *gitHubRepository) getVersions(ctx context.Context) ([]string, error) {
 // getReleaseByTag returns the github repository release with a specific tag name.
 func (g *gitHubRepository) getReleaseByTag(ctx context.Context, tag string) (*github.RepositoryRelease, error) {
 	cacheID := fmt.Sprintf("%s/%s:%s", g.owner, g.repository, tag)
-	if release, ok := cacheReleases[cacheID]; ok {
+	if release, ok := getCache(cacheReleases, cacheID); ok {
 		return release, nil
 	}
 
@@ -396,7 +415,7 @@ func (g *gitHubRepository) getReleaseByTag(ctx context.Context, tag string) (*gi
 		return nil, retryError
 	}
 
-	cacheReleases[cacheID] = release
+	setCache(cacheReleases, cacheID, release)
 	return release, nil
 }
 
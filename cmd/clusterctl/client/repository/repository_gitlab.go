@@ -143,7 +143,7 @@ func (g *gitLabRepository) GetFile(ctx context.Context, version, path string) ([
 		path,
 	)
 
-	if content, ok := cacheFiles[url]; ok {
+	if content, ok := getCache(cacheFiles, url); ok {
 		return content, nil
 	}
 
@@ -170,6 +170,6 @@ func (g *gitLabRepository) GetFile(ctx context.Context, version, path string) ([
 		return nil, errors.Wrapf(err, "failed to get file %q with version %q from %q", path, version, url)
 	}
 
-	cacheFiles[url] = content
+	setCache(cacheFiles, url, content)
 	return content, nil
 }
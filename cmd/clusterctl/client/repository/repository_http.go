@@ -0,0 +1,251 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/version"
+	"sigs.k8s.io/yaml"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+)
+
+const (
+	httpScheme = "http"
+
+	// httpIndexFile is the name of the index document listing the versions available in an HTTP repository.
+	httpIndexFile = "index.yaml"
+)
+
+// httpDirectoryListingHrefRegex matches links in an HTML directory listing, e.g. <a href="v1.2.3/">v1.2.3/</a>.
+var httpDirectoryListingHrefRegex = regexp.MustCompile(`(?i)href="([^"]+)"`)
+
+// httpRepository provides support for providers hosted on a generic web server, e.g. Nginx or Artifactory.
+//
+// Repositories are expected to adhere to the same layout of the local filesystem repository:
+// {scheme}://{host}/{basepath}/{version}/{components.yaml}
+//
+// Available versions are read from an index document stored in {basepath}/index.yaml, e.g.
+//
+//	versions:
+//	- v1.2.3
+//	- v1.2.4
+//
+// If the index document does not exist, versions are discovered from the directory listing of {basepath}/ as
+// generated by most web servers (e.g. Nginx autoindex); in both cases versions not following semantic versioning
+// are ignored. "latest" is also an acceptable value for {version} and it is resolved to the latest version satisfying
+// the current API contract.
+type httpRepository struct {
+	providerConfig        config.Provider
+	configVariablesClient config.VariablesClient
+	httpClient            *http.Client
+	baseURL               *url.URL
+	defaultVersion        string
+	componentsPath        string
+}
+
+var _ Repository = &httpRepository{}
+
+// httpRepositoryIndex is the index document listing the versions available in an HTTP repository.
+type httpRepositoryIndex struct {
+	Versions []string `json:"versions"`
+}
+
+// NewHTTPRepository returns an httpRepository implementation.
+func NewHTTPRepository(ctx context.Context, providerConfig config.Provider, configVariablesClient config.VariablesClient) (Repository, error) {
+	return newHTTPRepository(ctx, providerConfig, configVariablesClient, http.DefaultClient)
+}
+
+func newHTTPRepository(ctx context.Context, providerConfig config.Provider, configVariablesClient config.VariablesClient, httpClient *http.Client) (*httpRepository, error) {
+	if configVariablesClient == nil {
+		return nil, errors.New("invalid arguments: configVariablesClient can't be nil")
+	}
+
+	rURL, err := url.Parse(providerConfig.URL())
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid url")
+	}
+
+	// Check if the url is an HTTP repository.
+	urlSplit := strings.Split(strings.TrimPrefix(rURL.Path, "/"), "/")
+	if (rURL.Scheme != httpScheme && rURL.Scheme != httpsScheme) || rURL.Host == "" || len(urlSplit) < 2 || urlSplit[len(urlSplit)-1] == "" || urlSplit[len(urlSplit)-2] == "" {
+		return nil, errors.New("invalid url: an HTTP repository url should be in the form http[s]://{host}/{basepath}/{latest|version}/{componentsClient.yaml}")
+	}
+
+	// Extract all the info from url split.
+	componentsPath := urlSplit[len(urlSplit)-1]
+	defaultVersion := urlSplit[len(urlSplit)-2]
+	baseURL := &url.URL{
+		Scheme: rURL.Scheme,
+		User:   rURL.User,
+		Host:   rURL.Host,
+		Path:   "/" + strings.Join(urlSplit[:len(urlSplit)-2], "/"),
+	}
+
+	repo := &httpRepository{
+		providerConfig:        providerConfig,
+		configVariablesClient: configVariablesClient,
+		httpClient:            httpClient,
+		baseURL:               baseURL,
+		defaultVersion:        defaultVersion,
+		componentsPath:        componentsPath,
+	}
+
+	if defaultVersion == latestVersionTag {
+		repo.defaultVersion, err = latestContractRelease(ctx, repo, clusterv1.GroupVersion.Version)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get latest release")
+		}
+	}
+
+	return repo, nil
+}
+
+// DefaultVersion returns defaultVersion field of httpRepository struct.
+func (h *httpRepository) DefaultVersion() string {
+	return h.defaultVersion
+}
+
+// RootPath returns the empty string as all the files are stored directly in the version folder.
+func (h *httpRepository) RootPath() string {
+	return ""
+}
+
+// ComponentsPath returns componentsPath field of httpRepository struct.
+func (h *httpRepository) ComponentsPath() string {
+	return h.componentsPath
+}
+
+// GetVersions returns the list of versions that are available in a provider repository.
+func (h *httpRepository) GetVersions(ctx context.Context) ([]string, error) {
+	log := logf.Log
+
+	cacheID := h.baseURL.Redacted()
+	if versions, ok := getCache(cacheVersions, cacheID); ok {
+		return versions, nil
+	}
+
+	var candidates []string
+	content, err := h.get(ctx, h.urlFor(httpIndexFile))
+	switch {
+	case err == nil:
+		index := &httpRepositoryIndex{}
+		if err := yaml.Unmarshal(content, index); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %q from %q", httpIndexFile, cacheID)
+		}
+		candidates = index.Versions
+	case errors.Is(err, errNotFound):
+		// Fallback to the directory listing if the repository does not have an index document.
+		log.V(5).Info("index document not found, falling back to directory listing", "url", cacheID)
+
+		listingURL := h.urlFor()
+		listingURL.Path += "/"
+		content, err = h.get(ctx, listingURL)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get versions from %q", cacheID)
+		}
+		for _, match := range httpDirectoryListingHrefRegex.FindAllStringSubmatch(string(content), -1) {
+			candidates = append(candidates, path.Base(strings.TrimSuffix(match[1], "/")))
+		}
+	default:
+		return nil, errors.Wrapf(err, "failed to get versions from %q", cacheID)
+	}
+
+	versions := []string{}
+	seen := map[string]bool{}
+	for _, v := range candidates {
+		if _, err := version.ParseSemantic(v); err != nil {
+			// Discard versions that are not a valid semantic versions (the user can point explicitly to such versions).
+			continue
+		}
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		versions = append(versions, v)
+	}
+
+	setCache(cacheVersions, cacheID, versions)
+	return versions, nil
+}
+
+// GetFile returns a file for a given provider version.
+func (h *httpRepository) GetFile(ctx context.Context, version, fileName string) ([]byte, error) {
+	fileURL := h.urlFor(version, fileName)
+	// NOTE: the redacted URL is used as a cache key, so credentials in the repository URL are never stored or logged.
+	cacheID := fileURL.Redacted()
+	if content, ok := getCache(cacheFiles, cacheID); ok {
+		return content, nil
+	}
+
+	content, err := h.get(ctx, fileURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get file %q with version %q from %q", fileName, version, h.baseURL.Redacted())
+	}
+
+	setCache(cacheFiles, cacheID, content)
+	return content, nil
+}
+
+// urlFor returns the URL of an element in the repository.
+func (h *httpRepository) urlFor(elem ...string) *url.URL {
+	u := *h.baseURL
+	u.Path = path.Join(append([]string{u.Path}, elem...)...)
+	return &u
+}
+
+// get executes an http GET request and returns the response body; errNotFound is returned if the server answers 404.
+// Errors only include the redacted URL, given that the URL could contain credentials.
+func (h *httpRepository) get(ctx context.Context, u *url.URL) ([]byte, error) {
+	redactedURL := u.Redacted()
+	timeoutctx, cancel := context.WithTimeoutCause(ctx, 30*time.Second, errors.New("http request timeout expired"))
+	defer cancel()
+	request, err := http.NewRequestWithContext(timeoutctx, http.MethodGet, u.String(), http.NoBody)
+	if err != nil {
+		return nil, errors.Errorf("failed to create request for %q", redactedURL)
+	}
+
+	response, err := h.httpClient.Do(request)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %q", redactedURL)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, errNotFound
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to get %q, got %d", redactedURL, response.StatusCode)
+	}
+
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %q", redactedURL)
+	}
+	return content, nil
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	goproxytest "sigs.k8s.io/cluster-api/internal/goproxy/test"
+)
+
+func Test_httpRepository_newHTTPRepository(t *testing.T) {
+	type field struct {
+		providerConfig config.Provider
+		variableClient config.VariablesClient
+	}
+	tests := []struct {
+		name               string
+		field              field
+		wantBaseURL        string
+		wantDefaultVersion string
+		wantComponentsPath string
+		wantedErr          string
+	}{
+		{
+			name: "can create a new HTTPS repo",
+			field: field{
+				providerConfig: config.NewProvider("test", "https://artifacts.example.org/providers/infrastructure-foo/v1.0.0/infrastructure-components.yaml", clusterctlv1.InfrastructureProviderType),
+				variableClient: test.NewFakeVariableClient(),
+			},
+			wantBaseURL:        "https://artifacts.example.org/providers/infrastructure-foo",
+			wantDefaultVersion: "v1.0.0",
+			wantComponentsPath: "infrastructure-components.yaml",
+		},
+		{
+			name: "can create a new HTTP repo",
+			field: field{
+				providerConfig: config.NewProvider("test", "http://artifacts.example.org:8080/v1.0.0/infrastructure-components.yaml", clusterctlv1.InfrastructureProviderType),
+				variableClient: test.NewFakeVariableClient(),
+			},
+			wantBaseURL:        "http://artifacts.example.org:8080/",
+			wantDefaultVersion: "v1.0.0",
+			wantComponentsPath: "infrastructure-components.yaml",
+		},
+		{
+			name: "missing variableClient",
+			field: field{
+				providerConfig: config.NewProvider("test", "https://artifacts.example.org/providers/infrastructure-foo/v1.0.0/infrastructure-components.yaml", clusterctlv1.InfrastructureProviderType),
+				variableClient: nil,
+			},
+			wantedErr: "invalid arguments: configVariablesClient can't be nil",
+		},
+		{
+			name: "provider url should have an http or https scheme",
+			field: field{
+				providerConfig: config.NewProvider("test", "ftp://artifacts.example.org/providers/infrastructure-foo/v1.0.0/infrastructure-components.yaml", clusterctlv1.InfrastructureProviderType),
+				variableClient: test.NewFakeVariableClient(),
+			},
+			wantedErr: "invalid url: an HTTP repository url should be in the form http[s]://{host}/{basepath}/{latest|version}/{componentsClient.yaml}",
+		},
+		{
+			name: "provider url should have a version",
+			field: field{
+				providerConfig: config.NewProvider("test", "https://artifacts.example.org/infrastructure-components.yaml", clusterctlv1.InfrastructureProviderType),
+				variableClient: test.NewFakeVariableClient(),
+			},
+			wantedErr: "invalid url: an HTTP repository url should be in the form http[s]://{host}/{basepath}/{latest|version}/{componentsClient.yaml}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			resetCaches()
+
+			got, err := newHTTPRepository(context.Background(), tt.field.providerConfig, tt.field.variableClient, http.DefaultClient)
+			if tt.wantedErr != "" {
+				g.Expect(err).To(MatchError(tt.wantedErr))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.baseURL.String()).To(Equal(tt.wantBaseURL))
+			g.Expect(got.DefaultVersion()).To(Equal(tt.wantDefaultVersion))
+			g.Expect(got.ComponentsPath()).To(Equal(tt.wantComponentsPath))
+		})
+	}
+}
+
+func Test_httpRepository_GetVersions(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(w http.ResponseWriter, r *http.Request)
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "versions from index document",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/providers/infrastructure-foo/index.yaml" {
+					fmt.Fprint(w, "versions:\n- v0.4.0\n- v0.4.1\n- not-a-version\n")
+					return
+				}
+				http.NotFound(w, r)
+			},
+			want: []string{"v0.4.0", "v0.4.1"},
+		},
+		{
+			name: "versions from directory listing",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/providers/infrastructure-foo/" {
+					fmt.Fprint(w, `<html><body><a href="../">../</a><a href="v0.4.0/">v0.4.0/</a><a href="v0.4.1/">v0.4.1/</a><a href="README.md">README.md</a></body></html>`)
+					return
+				}
+				http.NotFound(w, r)
+			},
+			want: []string{"v0.4.0", "v0.4.1"},
+		},
+		{
+			name: "fails if the server returns an error",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			resetCaches()
+
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				goproxytest.HTTPTestMethod(t, r, "GET")
+				tt.handler(w, r)
+			}))
+			defer server.Close()
+
+			providerURL := fmt.Sprintf("%s/providers/infrastructure-foo/v0.4.0/infrastructure-components.yaml", server.URL)
+			repo, err := newHTTPRepository(context.Background(), config.NewProvider("test", providerURL, clusterctlv1.InfrastructureProviderType), test.NewFakeVariableClient(), server.Client())
+			g.Expect(err).ToNot(HaveOccurred())
+
+			got, err := repo.GetVersions(context.Background())
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_httpRepository_GetFile(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewTLSServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/providers/infrastructure-foo/index.yaml", func(w http.ResponseWriter, r *http.Request) {
+		goproxytest.HTTPTestMethod(t, r, "GET")
+		fmt.Fprint(w, "versions:\n- v0.4.0\n- v0.4.1\n- v0.5.0\n")
+	})
+	mux.HandleFunc("/providers/infrastructure-foo/v0.4.1/", func(w http.ResponseWriter, r *http.Request) {
+		goproxytest.HTTPTestMethod(t, r, "GET")
+		fmt.Fprintf(w, "%s content", r.URL.Path)
+	})
+
+	tests := []struct {
+		name               string
+		providerVersion    string
+		version            string
+		fileName           string
+		want               []byte
+		wantDefaultVersion string
+		wantErr            bool
+	}{
+		{
+			name:               "File exists",
+			providerVersion:    "v0.4.1",
+			version:            "v0.4.1",
+			fileName:           "metadata.yaml",
+			want:               []byte("/providers/infrastructure-foo/v0.4.1/metadata.yaml content"),
+			wantDefaultVersion: "v0.4.1",
+		},
+		{
+			name:               "File exists, latest resolves to the latest published version",
+			providerVersion:    "latest",
+			version:            "v0.4.1",
+			fileName:           "cluster-template.yaml",
+			want:               []byte("/providers/infrastructure-foo/v0.4.1/cluster-template.yaml content"),
+			wantDefaultVersion: "v0.4.1",
+		},
+		{
+			name:               "Version does not exist",
+			providerVersion:    "v0.4.1",
+			version:            "v0.5.0",
+			fileName:           "metadata.yaml",
+			wantDefaultVersion: "v0.4.1",
+			wantErr:            true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			resetCaches()
+
+			providerURL := fmt.Sprintf("%s/providers/infrastructure-foo/%s/infrastructure-components.yaml", server.URL, tt.providerVersion)
+			repo, err := newHTTPRepository(context.Background(), config.NewProvider("test", providerURL, clusterctlv1.InfrastructureProviderType), test.NewFakeVariableClient(), server.Client())
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(repo.DefaultVersion()).To(Equal(tt.wantDefaultVersion))
+
+			got, err := repo.GetFile(context.Background(), tt.version, tt.fileName)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_httpRepository_GetFile_RedactsCredentials(t *testing.T) {
+	g := NewWithT(t)
+	resetCaches()
+
+	mux := http.NewServeMux()
+	server := httptest.NewTLSServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/providers/infrastructure-foo/v0.4.1/metadata.yaml", func(w http.ResponseWriter, r *http.Request) {
+		goproxytest.HTTPTestMethod(t, r, "GET")
+		fmt.Fprint(w, "content")
+	})
+	mux.HandleFunc("/providers/infrastructure-foo/v0.4.1/cluster-template.yaml", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	providerURL := strings.Replace(server.URL, "https://", "https://user:secret@", 1) + "/providers/infrastructure-foo/v0.4.1/infrastructure-components.yaml"
+	repo, err := newHTTPRepository(context.Background(), config.NewProvider("test", providerURL, clusterctlv1.InfrastructureProviderType), test.NewFakeVariableClient(), server.Client())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = repo.GetFile(context.Background(), "v0.4.1", "metadata.yaml")
+	g.Expect(err).ToNot(HaveOccurred())
+	for key := range cacheFiles {
+		g.Expect(key).ToNot(ContainSubstring("secret"))
+	}
+
+	_, err = repo.GetFile(context.Background(), "v0.4.1", "cluster-template.yaml")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).ToNot(ContainSubstring("secret"))
+}
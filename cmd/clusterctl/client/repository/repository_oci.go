@@ -0,0 +1,473 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+)
+
+const (
+	ociScheme = "oci"
+
+	// ociTitleAnnotation is the annotation used to identify the file name of an artifact layer.
+	// This is the same annotation used by ORAS when pushing files to an OCI registry.
+	ociTitleAnnotation = "org.opencontainers.image.title"
+
+	ociManifestMediaType      = "application/vnd.oci.image.manifest.v1+json"
+	dockerManifestMediaType   = "application/vnd.docker.distribution.manifest.v2+json"
+	ociListTagsPageSizeLimit  = 100
+	ociRequestTimeoutDuration = 30 * time.Second
+)
+
+// ociRepository provides support for providers hosted on an OCI registry.
+//
+// Each provider version is expected to be published as an OCI artifact tagged with the version, e.g. using
+// `oras push registry.example.com/myorg/infrastructure-foo:v1.2.3 metadata.yaml infrastructure-components.yaml cluster-template.yaml`.
+// Each file is stored in a separated layer and identified by the "org.opencontainers.image.title" annotation.
+// Repositories must use versioned tags; "latest" is resolved to the latest tag satisfying the current API contract.
+//
+// The repository URL should be in the form oci://{registry}/{repository}:{latest|version-tag}/{componentsClient.yaml}.
+type ociRepository struct {
+	providerConfig        config.Provider
+	configVariablesClient config.VariablesClient
+	httpClient            *http.Client
+	registry              string
+	repository            string
+	defaultVersion        string
+	rootPath              string
+	componentsPath        string
+	username              string
+	password              string
+	token                 string
+}
+
+var _ Repository = &ociRepository{}
+
+// ociManifest is the subset of an OCI image manifest used by clusterctl.
+type ociManifest struct {
+	MediaType string          `json:"mediaType,omitempty"`
+	Layers    []ociDescriptor `json:"layers"`
+}
+
+// ociDescriptor is the subset of an OCI content descriptor used by clusterctl.
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ociTagList is the response of the OCI distribution tag list API.
+type ociTagList struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+// NewOCIRepository returns an ociRepository implementation.
+func NewOCIRepository(ctx context.Context, providerConfig config.Provider, configVariablesClient config.VariablesClient) (Repository, error) {
+	return newOCIRepository(ctx, providerConfig, configVariablesClient, http.DefaultClient)
+}
+
+func newOCIRepository(ctx context.Context, providerConfig config.Provider, configVariablesClient config.VariablesClient, httpClient *http.Client) (*ociRepository, error) {
+	if configVariablesClient == nil {
+		return nil, errors.New("invalid arguments: configVariablesClient can't be nil")
+	}
+
+	rURL, err := url.Parse(providerConfig.URL())
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid url")
+	}
+
+	invalidURLErr := errors.New("invalid url: an OCI repository url should be in the form oci://{registry}/{repository}:{latest|version-tag}/{componentsClient.yaml}")
+
+	// Check if the url is an OCI repository.
+	if rURL.Scheme != ociScheme || rURL.Host == "" {
+		return nil, invalidURLErr
+	}
+
+	// Split the path in {repository}:{version} and {componentsPath}; the version is expected to be in
+	// the second to last path segment so repository names with nested paths are supported.
+	urlPath := strings.TrimPrefix(rURL.Path, "/")
+	componentsIdx := strings.LastIndex(urlPath, "/")
+	if componentsIdx <= 0 {
+		return nil, invalidURLErr
+	}
+	reference := urlPath[:componentsIdx]
+	componentsPath := urlPath[componentsIdx+1:]
+
+	versionIdx := strings.LastIndex(reference, ":")
+	if versionIdx <= 0 || versionIdx == len(reference)-1 || componentsPath == "" {
+		return nil, invalidURLErr
+	}
+	repository := reference[:versionIdx]
+	defaultVersion := reference[versionIdx+1:]
+
+	repo := &ociRepository{
+		providerConfig:        providerConfig,
+		configVariablesClient: configVariablesClient,
+		httpClient:            httpClient,
+		registry:              rURL.Host,
+		repository:            repository,
+		defaultVersion:        defaultVersion,
+		rootPath:              ".",
+		componentsPath:        componentsPath,
+	}
+
+	if username, err := configVariablesClient.Get(config.OCIUsernameVariable); err == nil {
+		repo.username = username
+	}
+	if password, err := configVariablesClient.Get(config.OCIPasswordVariable); err == nil {
+		repo.password = password
+	}
+
+	if defaultVersion == latestVersionTag {
+		repo.defaultVersion, err = latestContractRelease(ctx, repo, clusterv1.GroupVersion.Version)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get latest release")
+		}
+	}
+
+	return repo, nil
+}
+
+// Registry returns registry field of ociRepository struct.
+func (o *ociRepository) Registry() string {
+	return o.registry
+}
+
+// Repository returns repository field of ociRepository struct.
+func (o *ociRepository) Repository() string {
+	return o.repository
+}
+
+// DefaultVersion returns defaultVersion field of ociRepository struct.
+func (o *ociRepository) DefaultVersion() string {
+	return o.defaultVersion
+}
+
+// RootPath returns rootPath field of ociRepository struct.
+func (o *ociRepository) RootPath() string {
+	return o.rootPath
+}
+
+// ComponentsPath returns componentsPath field of ociRepository struct.
+func (o *ociRepository) ComponentsPath() string {
+	return o.componentsPath
+}
+
+// GetVersions returns the list of versions that are available in a provider repository.
+// Tags that are not valid semantic versions are discarded.
+func (o *ociRepository) GetVersions(ctx context.Context) ([]string, error) {
+	cacheID := fmt.Sprintf("oci://%s/%s", o.registry, o.repository)
+	if versions, ok := getCache(cacheVersions, cacheID); ok {
+		return versions, nil
+	}
+
+	versions := []string{}
+	next := fmt.Sprintf("/v2/%s/tags/list?n=%d", o.repository, ociListTagsPageSizeLimit)
+	for next != "" {
+		response, err := o.get(ctx, next, "application/json")
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the list of tags for %q", cacheID)
+		}
+
+		tagList := &ociTagList{}
+		err = json.NewDecoder(response.Body).Decode(tagList)
+		response.Body.Close()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode the list of tags for %q", cacheID)
+		}
+
+		for _, tag := range tagList.Tags {
+			if _, err := version.ParseSemantic(tag); err != nil {
+				// Discard tags that are not a valid semantic versions (the user can point explicitly to such tags).
+				continue
+			}
+			versions = append(versions, tag)
+		}
+
+		next = nextPageFromLinkHeader(response.Header.Get("Link"))
+	}
+
+	setCache(cacheVersions, cacheID, versions)
+	return versions, nil
+}
+
+// GetFile returns a file for a given provider version.
+func (o *ociRepository) GetFile(ctx context.Context, version, path string) ([]byte, error) {
+	cacheID := fmt.Sprintf("oci://%s/%s:%s:%s", o.registry, o.repository, version, path)
+	if content, ok := getCache(cacheFiles, cacheID); ok {
+		return content, nil
+	}
+
+	manifest, err := o.getManifest(ctx, version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get file %q with version %q", path, version)
+	}
+
+	fileName := filepath.Base(path)
+	var layer *ociDescriptor
+	for i := range manifest.Layers {
+		if manifest.Layers[i].Annotations[ociTitleAnnotation] == fileName {
+			layer = &manifest.Layers[i]
+			break
+		}
+	}
+	if layer == nil {
+		return nil, errors.Errorf("failed to get file %q with version %q: artifact %s/%s:%s does not contain a layer for the file", path, version, o.registry, o.repository, version)
+	}
+
+	content, err := o.getBlob(ctx, layer)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get file %q with version %q", path, version)
+	}
+
+	setCache(cacheFiles, cacheID, content)
+	return content, nil
+}
+
+// getManifest returns the manifest of the artifact tagged with the given version.
+func (o *ociRepository) getManifest(ctx context.Context, version string) (*ociManifest, error) {
+	response, err := o.get(ctx, fmt.Sprintf("/v2/%s/manifests/%s", o.repository, version), strings.Join([]string{ociManifestMediaType, dockerManifestMediaType}, ", "))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get manifest for %s/%s:%s", o.registry, o.repository, version)
+	}
+	defer response.Body.Close()
+
+	manifest := &ociManifest{}
+	if err := json.NewDecoder(response.Body).Decode(manifest); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode manifest for %s/%s:%s", o.registry, o.repository, version)
+	}
+	return manifest, nil
+}
+
+// getBlob returns the content of a blob, verifying it matches the digest in the descriptor.
+func (o *ociRepository) getBlob(ctx context.Context, descriptor *ociDescriptor) ([]byte, error) {
+	algorithm, encoded, ok := strings.Cut(descriptor.Digest, ":")
+	if !ok || algorithm != "sha256" {
+		return nil, errors.Errorf("unsupported digest %q", descriptor.Digest)
+	}
+
+	response, err := o.get(ctx, fmt.Sprintf("/v2/%s/blobs/%s", o.repository, descriptor.Digest), "")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get blob %s", descriptor.Digest)
+	}
+	defer response.Body.Close()
+
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read blob %s", descriptor.Digest)
+	}
+
+	sum := sha256.Sum256(content)
+	if hex.EncodeToString(sum[:]) != encoded {
+		return nil, errors.Errorf("blob %s does not match its digest", descriptor.Digest)
+	}
+	return content, nil
+}
+
+// get executes an http GET request against the registry, handling anonymous or basic-auth token authentication
+// as defined by the OCI distribution spec when the registry answers with 401 Unauthorized.
+// The caller is responsible for closing the response body.
+func (o *ociRepository) get(ctx context.Context, path, accept string) (*http.Response, error) {
+	response, err := o.do(ctx, path, accept)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode == http.StatusUnauthorized {
+		challenge := response.Header.Get("WWW-Authenticate")
+		response.Body.Close()
+		if err := o.authenticate(ctx, challenge); err != nil {
+			return nil, err
+		}
+		if response, err = o.do(ctx, path, accept); err != nil {
+			return nil, err
+		}
+	}
+
+	if response.StatusCode == http.StatusNotFound {
+		response.Body.Close()
+		return nil, errNotFound
+	}
+	if response.StatusCode != http.StatusOK {
+		response.Body.Close()
+		return nil, errors.Errorf("request to %s returned %d", response.Request.URL, response.StatusCode)
+	}
+	return response, nil
+}
+
+func (o *ociRepository) do(ctx context.Context, path, accept string) (*http.Response, error) {
+	timeoutctx, cancel := context.WithTimeoutCause(ctx, ociRequestTimeoutDuration, errors.New("http request timeout expired"))
+
+	url := fmt.Sprintf("https://%s%s", o.registry, path)
+	request, err := http.NewRequestWithContext(timeoutctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		cancel()
+		return nil, errors.Wrapf(err, "failed to create request for %q", url)
+	}
+	if accept != "" {
+		request.Header.Set("Accept", accept)
+	}
+	switch {
+	case o.token != "":
+		request.Header.Set("Authorization", "Bearer "+o.token)
+	case o.username != "" || o.password != "":
+		request.SetBasicAuth(o.username, o.password)
+	}
+
+	response, err := o.httpClient.Do(request)
+	if err != nil {
+		cancel()
+		return nil, errors.Wrapf(err, "failed to execute request for %q", url)
+	}
+	response.Body = &cancelOnCloseReader{ReadCloser: response.Body, cancel: cancel}
+	return response, nil
+}
+
+// authenticate handles a WWW-Authenticate challenge returned by the registry.
+func (o *ociRepository) authenticate(ctx context.Context, challenge string) error {
+	scheme, params := parseAuthChallenge(challenge)
+	switch scheme {
+	case "basic":
+		if o.username == "" && o.password == "" {
+			return errors.Errorf("registry %s requires authentication, please set %q and %q", o.registry, config.OCIUsernameVariable, config.OCIPasswordVariable)
+		}
+		// Basic credentials are already sent by do, so there is nothing more we can try.
+		return errors.Errorf("registry %s rejected the provided credentials", o.registry)
+	case "bearer":
+	default:
+		return errors.Errorf("registry %s requires an unsupported authentication scheme %q", o.registry, scheme)
+	}
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return errors.Errorf("registry %s returned an invalid bearer realm %q", o.registry, params["realm"])
+	}
+	query := realm.Query()
+	if service, ok := params["service"]; ok {
+		query.Set("service", service)
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", o.repository)
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	timeoutctx, cancel := context.WithTimeoutCause(ctx, ociRequestTimeoutDuration, errors.New("http request timeout expired"))
+	defer cancel()
+	request, err := http.NewRequestWithContext(timeoutctx, http.MethodGet, realm.String(), http.NoBody)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create token request for registry %s", o.registry)
+	}
+	if o.username != "" || o.password != "" {
+		request.SetBasicAuth(o.username, o.password)
+	}
+
+	response, err := o.httpClient.Do(request)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get a token for registry %s", o.registry)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return errors.Errorf("failed to get a token for registry %s, got %d", o.registry, response.StatusCode)
+	}
+
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(response.Body).Decode(&token); err != nil {
+		return errors.Wrapf(err, "failed to decode token for registry %s", o.registry)
+	}
+
+	o.token = token.Token
+	if o.token == "" {
+		o.token = token.AccessToken
+	}
+	if o.token == "" {
+		return errors.Errorf("registry %s returned an empty token", o.registry)
+	}
+	return nil
+}
+
+// parseAuthChallenge parses a WWW-Authenticate header value, e.g.
+// Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:foo:pull".
+func parseAuthChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := map[string]string{}
+	for rest != "" {
+		var key, value string
+		key, rest, _ = strings.Cut(strings.TrimSpace(rest), "=")
+		rest = strings.TrimSpace(rest)
+		if strings.HasPrefix(rest, "\"") {
+			value, rest, _ = strings.Cut(rest[1:], "\"")
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		rest = strings.TrimPrefix(strings.TrimSpace(rest), ",")
+		params[strings.ToLower(strings.TrimSpace(key))] = value
+	}
+	return strings.ToLower(scheme), params
+}
+
+// nextPageFromLinkHeader returns the path of the next page from a Link header, e.g.
+// </v2/foo/tags/list?n=100&last=v1.0.0>; rel="next".
+func nextPageFromLinkHeader(link string) string {
+	if link == "" {
+		return ""
+	}
+	start := strings.Index(link, "<")
+	end := strings.Index(link, ">")
+	if start < 0 || end < start || !strings.Contains(link[end:], `rel="next"`) {
+		return ""
+	}
+	next, err := url.Parse(link[start+1 : end])
+	if err != nil {
+		return ""
+	}
+	return next.RequestURI()
+}
+
+// cancelOnCloseReader cancels a context when the wrapped reader is closed.
+type cancelOnCloseReader struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (r *cancelOnCloseReader) Close() error {
+	defer r.cancel()
+	return r.ReadCloser.Close()
+}
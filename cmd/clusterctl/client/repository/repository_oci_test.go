@@ -0,0 +1,339 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	goproxytest "sigs.k8s.io/cluster-api/internal/goproxy/test"
+)
+
+// fakeOCIRegistry is a minimal OCI distribution API implementation serving artifacts pushed with ORAS-like layout.
+type fakeOCIRegistry struct {
+	repository string
+	artifacts  map[string]map[string]string
+	token      string
+}
+
+func (r *fakeOCIRegistry) handler(t *testing.T, serverURL func() string) http.Handler {
+	t.Helper()
+
+	blobs := map[string][]byte{}
+	manifests := map[string][]byte{}
+	tags := []string{}
+	for tag, files := range r.artifacts {
+		manifest := ociManifest{MediaType: ociManifestMediaType}
+		for name, content := range files {
+			digest := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(content)))
+			blobs[digest] = []byte(content)
+			manifest.Layers = append(manifest.Layers, ociDescriptor{
+				MediaType:   "application/vnd.oci.image.layer.v1.tar",
+				Digest:      digest,
+				Size:        int64(len(content)),
+				Annotations: map[string]string{ociTitleAnnotation: name},
+			})
+		}
+		raw, err := json.Marshal(manifest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		manifests[tag] = raw
+		tags = append(tags, tag)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		goproxytest.HTTPTestMethod(t, r, "GET")
+		fmt.Fprintf(w, `{"token": %q}`, "secret")
+	})
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, req *http.Request) {
+		goproxytest.HTTPTestMethod(t, req, "GET")
+		if r.token != "" && req.Header.Get("Authorization") != "Bearer "+r.token {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="fake"`, serverURL()))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		path := strings.TrimPrefix(req.URL.Path, "/v2/"+r.repository+"/")
+		switch {
+		case path == "tags/list":
+			// Return tags one per page to exercise pagination.
+			last := req.URL.Query().Get("last")
+			idx := 0
+			for i, tag := range tags {
+				if tag == last {
+					idx = i + 1
+				}
+			}
+			if idx >= len(tags) {
+				fmt.Fprintf(w, `{"name": %q, "tags": []}`, r.repository)
+				return
+			}
+			if idx+1 < len(tags) {
+				w.Header().Set("Link", fmt.Sprintf(`</v2/%s/tags/list?n=1&last=%s>; rel="next"`, r.repository, tags[idx]))
+			}
+			fmt.Fprintf(w, `{"name": %q, "tags": [%q]}`, r.repository, tags[idx])
+		case strings.HasPrefix(path, "manifests/"):
+			manifest, ok := manifests[strings.TrimPrefix(path, "manifests/")]
+			if !ok {
+				http.NotFound(w, req)
+				return
+			}
+			w.Header().Set("Content-Type", ociManifestMediaType)
+			_, _ = w.Write(manifest)
+		case strings.HasPrefix(path, "blobs/"):
+			blob, ok := blobs[strings.TrimPrefix(path, "blobs/")]
+			if !ok {
+				http.NotFound(w, req)
+				return
+			}
+			_, _ = w.Write(blob)
+		default:
+			http.NotFound(w, req)
+		}
+	})
+	return mux
+}
+
+func newFakeOCIRegistryServer(t *testing.T, registry *fakeOCIRegistry) *httptest.Server {
+	t.Helper()
+
+	var server *httptest.Server
+	server = httptest.NewTLSServer(registry.handler(t, func() string { return server.URL }))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func Test_ociRepository_newOCIRepository(t *testing.T) {
+	type field struct {
+		providerConfig config.Provider
+		variableClient config.VariablesClient
+	}
+	tests := []struct {
+		name      string
+		field     field
+		want      *ociRepository
+		wantedErr string
+	}{
+		{
+			name: "can create a new OCI repo",
+			field: field{
+				providerConfig: config.NewProvider("test", "oci://registry.example.org:5000/org/nested/infrastructure-foo:v1.0.0/infrastructure-components.yaml", clusterctlv1.InfrastructureProviderType),
+				variableClient: test.NewFakeVariableClient().WithVar(config.OCIUsernameVariable, "user").WithVar(config.OCIPasswordVariable, "pass"),
+			},
+			want: &ociRepository{
+				registry:       "registry.example.org:5000",
+				repository:     "org/nested/infrastructure-foo",
+				defaultVersion: "v1.0.0",
+				rootPath:       ".",
+				componentsPath: "infrastructure-components.yaml",
+				username:       "user",
+				password:       "pass",
+			},
+		},
+		{
+			name: "missing variableClient",
+			field: field{
+				providerConfig: config.NewProvider("test", "oci://registry.example.org/org/infrastructure-foo:v1.0.0/infrastructure-components.yaml", clusterctlv1.InfrastructureProviderType),
+				variableClient: nil,
+			},
+			wantedErr: "invalid arguments: configVariablesClient can't be nil",
+		},
+		{
+			name: "provider url should have the oci scheme",
+			field: field{
+				providerConfig: config.NewProvider("test", "https://registry.example.org/org/infrastructure-foo:v1.0.0/infrastructure-components.yaml", clusterctlv1.InfrastructureProviderType),
+				variableClient: test.NewFakeVariableClient(),
+			},
+			wantedErr: "invalid url: an OCI repository url should be in the form oci://{registry}/{repository}:{latest|version-tag}/{componentsClient.yaml}",
+		},
+		{
+			name: "provider url should have a version",
+			field: field{
+				providerConfig: config.NewProvider("test", "oci://registry.example.org/org/infrastructure-foo/infrastructure-components.yaml", clusterctlv1.InfrastructureProviderType),
+				variableClient: test.NewFakeVariableClient(),
+			},
+			wantedErr: "invalid url: an OCI repository url should be in the form oci://{registry}/{repository}:{latest|version-tag}/{componentsClient.yaml}",
+		},
+		{
+			name: "provider url should have a components path",
+			field: field{
+				providerConfig: config.NewProvider("test", "oci://registry.example.org/org/infrastructure-foo:v1.0.0", clusterctlv1.InfrastructureProviderType),
+				variableClient: test.NewFakeVariableClient(),
+			},
+			wantedErr: "invalid url: an OCI repository url should be in the form oci://{registry}/{repository}:{latest|version-tag}/{componentsClient.yaml}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			resetCaches()
+
+			got, err := newOCIRepository(context.Background(), tt.field.providerConfig, tt.field.variableClient, http.DefaultClient)
+			if tt.wantedErr != "" {
+				g.Expect(err).To(MatchError(tt.wantedErr))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.registry).To(Equal(tt.want.registry))
+			g.Expect(got.repository).To(Equal(tt.want.repository))
+			g.Expect(got.DefaultVersion()).To(Equal(tt.want.defaultVersion))
+			g.Expect(got.RootPath()).To(Equal(tt.want.rootPath))
+			g.Expect(got.ComponentsPath()).To(Equal(tt.want.componentsPath))
+			g.Expect(got.username).To(Equal(tt.want.username))
+			g.Expect(got.password).To(Equal(tt.want.password))
+		})
+	}
+}
+
+func Test_ociRepository_GetVersions(t *testing.T) {
+	g := NewWithT(t)
+	resetCaches()
+
+	server := newFakeOCIRegistryServer(t, &fakeOCIRegistry{
+		repository: "org/infrastructure-foo",
+		artifacts: map[string]map[string]string{
+			"v0.4.0":    {"metadata.yaml": "v0.4.0"},
+			"v0.4.1":    {"metadata.yaml": "v0.4.1"},
+			"not-a-tag": {"metadata.yaml": "foo"},
+		},
+	})
+	providerURL := fmt.Sprintf("oci://%s/org/infrastructure-foo:v0.4.0/infrastructure-components.yaml", strings.TrimPrefix(server.URL, "https://"))
+
+	repo, err := newOCIRepository(context.Background(), config.NewProvider("test", providerURL, clusterctlv1.InfrastructureProviderType), test.NewFakeVariableClient(), server.Client())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	got, err := repo.GetVersions(context.Background())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(ConsistOf("v0.4.0", "v0.4.1"))
+}
+
+func Test_ociRepository_GetFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		version  string
+		fileName string
+		want     []byte
+		wantErr  bool
+	}{
+		{
+			name:     "Artifact and file exist",
+			version:  "v0.4.1",
+			fileName: "infrastructure-components.yaml",
+			want:     []byte("components"),
+			wantErr:  false,
+		},
+		{
+			name:     "Artifact and file exist, registry requires a bearer token",
+			token:    "secret",
+			version:  "v0.4.1",
+			fileName: "cluster-template.yaml",
+			want:     []byte("template"),
+			wantErr:  false,
+		},
+		{
+			name:     "File does not exist",
+			version:  "v0.4.1",
+			fileName: "404.file",
+			wantErr:  true,
+		},
+		{
+			name:     "Artifact does not exist",
+			version:  "v0.4.2",
+			fileName: "infrastructure-components.yaml",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			resetCaches()
+
+			server := newFakeOCIRegistryServer(t, &fakeOCIRegistry{
+				repository: "org/infrastructure-foo",
+				token:      tt.token,
+				artifacts: map[string]map[string]string{
+					"v0.4.1": {
+						"metadata.yaml":                  "metadata",
+						"infrastructure-components.yaml": "components",
+						"cluster-template.yaml":          "template",
+					},
+				},
+			})
+			providerURL := fmt.Sprintf("oci://%s/org/infrastructure-foo:v0.4.1/infrastructure-components.yaml", strings.TrimPrefix(server.URL, "https://"))
+
+			repo, err := newOCIRepository(context.Background(), config.NewProvider("test", providerURL, clusterctlv1.InfrastructureProviderType), test.NewFakeVariableClient(), server.Client())
+			g.Expect(err).ToNot(HaveOccurred())
+
+			got, err := repo.GetFile(context.Background(), tt.version, tt.fileName)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_ociRepository_latestVersion(t *testing.T) {
+	g := NewWithT(t)
+	resetCaches()
+
+	server := newFakeOCIRegistryServer(t, &fakeOCIRegistry{
+		repository: "org/infrastructure-foo",
+		artifacts: map[string]map[string]string{
+			"v0.4.0":        {"metadata.yaml": "v0.4.0"},
+			"v0.4.1":        {"metadata.yaml": "v0.4.1"},
+			"v0.5.0-alpha1": {"metadata.yaml": "v0.5.0-alpha1"},
+		},
+	})
+	providerURL := fmt.Sprintf("oci://%s/org/infrastructure-foo:latest/infrastructure-components.yaml", strings.TrimPrefix(server.URL, "https://"))
+
+	repo, err := newOCIRepository(context.Background(), config.NewProvider("test", providerURL, clusterctlv1.InfrastructureProviderType), test.NewFakeVariableClient(), server.Client())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(repo.DefaultVersion()).To(Equal("v0.4.1"))
+}
+
+func Test_parseAuthChallenge(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, params := parseAuthChallenge(`Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:foo:pull"`)
+	g.Expect(scheme).To(Equal("bearer"))
+	g.Expect(params).To(Equal(map[string]string{
+		"realm":   "https://auth.example.com/token",
+		"service": "registry.example.com",
+		"scope":   "repository:foo:pull",
+	}))
+}
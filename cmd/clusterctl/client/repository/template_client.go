@@ -32,7 +32,16 @@ type TemplateClient interface {
 	Get(ctx context.Context, flavor, targetNamespace string, listVariablesOnly bool) (Template, error)
 }
 
-// templateClient implements TemplateClient.
+// RawTemplateClient is implemented by TemplateClients that can return cluster templates without any processing.
+// NOTE: this is an optional interface, so existing implementations of TemplateClient are not required to implement it;
+// use a type assertion on a TemplateClient to check if it is supported.
+type RawTemplateClient interface {
+	TemplateClient
+
+	Raw(ctx context.Context, flavor string) ([]byte, error)
+}
+
+// templateClient implements TemplateClient and RawTemplateClient.
 type templateClient struct {
 	provider              config.Provider
 	version               string
@@ -41,6 +50,9 @@ type templateClient struct {
 	processor             yaml.Processor
 }
 
+// ensure templateClient implements RawTemplateClient.
+var _ RawTemplateClient = &templateClient{}
+
 // TemplateClientInput is an input strict for newTemplateClient.
 type TemplateClientInput struct {
 	version               string
@@ -69,12 +81,29 @@ func newTemplateClient(input TemplateClientInput) *templateClient {
 // In case the template does not exists, an error is returned.
 // Get assumes the following naming convention for templates: cluster-template[-<flavor_name>].yaml.
 func (c *templateClient) Get(ctx context.Context, flavor, targetNamespace string, skipTemplateProcess bool) (Template, error) {
-	log := logf.Log
-
 	if targetNamespace == "" {
 		return nil, errors.New("invalid arguments: please provide a targetNamespace")
 	}
 
+	rawArtifact, err := c.Raw(ctx, flavor)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTemplate(TemplateInput{
+		rawArtifact,
+		c.configVariablesClient,
+		c.processor,
+		targetNamespace,
+		skipTemplateProcess,
+	})
+}
+
+// Raw returns the template for the flavor specified, without any processing.
+// In case the template does not exists, an error is returned.
+func (c *templateClient) Raw(ctx context.Context, flavor string) ([]byte, error) {
+	log := logf.Log
+
 	version := c.version
 	name := c.processor.GetTemplateName(version, flavor)
 
@@ -99,11 +128,5 @@ func (c *templateClient) Get(ctx context.Context, flavor, targetNamespace string
 		log.V(1).Info("Using", "override", name, "provider", c.provider.ManifestLabel(), "version", version)
 	}
 
-	return NewTemplate(TemplateInput{
-		rawArtifact,
-		c.configVariablesClient,
-		c.processor,
-		targetNamespace,
-		skipTemplateProcess,
-	})
+	return rawArtifact, nil
 }
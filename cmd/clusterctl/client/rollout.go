@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -86,6 +87,38 @@ type RolloutUndoOptions struct {
 	ToRevision int64
 }
 
+// RolloutStatusOptions carries the options supported by RolloutStatus.
+type RolloutStatusOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Resources for the rollout command
+	Resources []string
+
+	// Namespace where the resource(s) live. If unspecified, the namespace name will be inferred
+	// from the current configuration.
+	Namespace string
+
+	// StuckMachineTimeout is the time after which Machines that are not running, or that are still deleting,
+	// are reported as stuck. If unspecified, 10 minutes will be used.
+	StuckMachineTimeout time.Duration
+}
+
+// RolloutHistoryOptions carries the options supported by RolloutHistory.
+type RolloutHistoryOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Resources for the rollout command
+	Resources []string
+
+	// Namespace where the resource(s) live. If unspecified, the namespace name will be inferred
+	// from the current configuration.
+	Namespace string
+}
+
 func (c *clusterctlClient) RolloutRestart(ctx context.Context, options RolloutRestartOptions) error {
 	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
 	if err != nil {
@@ -154,6 +187,50 @@ func (c *clusterctlClient) RolloutUndo(ctx context.Context, options RolloutUndoO
 	return nil
 }
 
+func (c *clusterctlClient) RolloutStatus(ctx context.Context, options RolloutStatusOptions) ([]RolloutStatus, error) {
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+	objRefs, err := getObjectRefs(clusterClient, options.Namespace, options.Resources)
+	if err != nil {
+		return nil, err
+	}
+	stuckMachineTimeout := options.StuckMachineTimeout
+	if stuckMachineTimeout == 0 {
+		stuckMachineTimeout = 10 * time.Minute
+	}
+	statuses := make([]RolloutStatus, 0, len(objRefs))
+	for _, ref := range objRefs {
+		status, err := c.alphaClient.Rollout().ObjectStatusViewer(ctx, clusterClient.Proxy(), ref, stuckMachineTimeout)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, *status)
+	}
+	return statuses, nil
+}
+
+func (c *clusterctlClient) RolloutHistory(ctx context.Context, options RolloutHistoryOptions) ([]RolloutHistory, error) {
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+	objRefs, err := getObjectRefs(clusterClient, options.Namespace, options.Resources)
+	if err != nil {
+		return nil, err
+	}
+	histories := make([]RolloutHistory, 0, len(objRefs))
+	for _, ref := range objRefs {
+		history, err := c.alphaClient.Rollout().ObjectHistoryViewer(ctx, clusterClient.Proxy(), ref)
+		if err != nil {
+			return nil, err
+		}
+		histories = append(histories, *history)
+	}
+	return histories, nil
+}
+
 func getObjectRefs(clusterClient cluster.Client, namespace string, resources []string) ([]corev1.ObjectReference, error) {
 	// If the option specifying the Namespace is empty, try to detect it.
 	if namespace == "" {
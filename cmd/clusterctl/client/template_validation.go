@@ -0,0 +1,422 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	structuraldefaulting "k8s.io/apiextensions-apiserver/pkg/apiserver/schema/defaulting"
+	structuralpruning "k8s.io/apiextensions-apiserver/pkg/apiserver/schema/pruning"
+	apiextensionsvalidation "k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/scheme"
+	"sigs.k8s.io/cluster-api/internal/webhooks"
+	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
+)
+
+// ValidateClusterTemplateOptions carries the options supported by ValidateClusterTemplate.
+type ValidateClusterTemplateOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Template is the workload cluster template to validate.
+	Template Template
+
+	// CoreProvider, BootstrapProviders, ControlPlaneProviders and InfrastructureProviders define the providers
+	// to read CRDs from when the kinds in the template are not installed in the management cluster, or when the
+	// management cluster is not available. Each provider can be specified as name[:version]; if the version is
+	// omitted, the latest version in the provider repository is used.
+	// If CoreProvider, BootstrapProviders or ControlPlaneProviders are empty, the same defaults of clusterctl init apply.
+	CoreProvider            string
+	BootstrapProviders      []string
+	ControlPlaneProviders   []string
+	InfrastructureProviders []string
+}
+
+// TemplateValidationResult reports the validation errors for an object in a workload cluster template.
+type TemplateValidationResult struct {
+	// Object is the object in the template which failed validation.
+	Object corev1.ObjectReference
+
+	// Errors are the validation errors for the object, e.g. unknown fields or missing required fields.
+	Errors []string
+}
+
+// ValidateClusterTemplate validates the objects in a workload cluster template against the OpenAPI schemas
+// of the CRDs for the corresponding kinds, and validates the variables of Clusters using a ClusterClass.
+// CRDs are read from the management cluster when available, and from the provider repositories otherwise;
+// validation is performed offline without sending the objects to any API server.
+// Only the objects failing validation are returned.
+func (c *clusterctlClient) ValidateClusterTemplate(ctx context.Context, options ValidateClusterTemplateOptions) ([]TemplateValidationResult, error) {
+	if options.Template == nil {
+		return nil, errors.New("invalid cluster template: template can't be nil")
+	}
+
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+
+	v := &templateValidator{
+		crds: map[schema.GroupKind]*apiextensionsv1.CustomResourceDefinition{},
+	}
+
+	// Read the CRDs installed in the management cluster, if available; they take precedence
+	// over the ones in the provider repositories.
+	if err := clusterClient.Proxy().CheckClusterAvailable(ctx); err == nil {
+		managementClient, err := clusterClient.Proxy().NewClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+		crdList := &apiextensionsv1.CustomResourceDefinitionList{}
+		if err := managementClient.List(ctx, crdList); err != nil {
+			return nil, errors.Wrap(err, "failed to list CustomResourceDefinitions")
+		}
+		for i := range crdList.Items {
+			v.addCRD(&crdList.Items[i])
+		}
+		v.managementClient = managementClient
+	}
+
+	// Read the CRDs from the provider repositories only if some kinds are still missing.
+	if v.hasMissingKinds(options.Template.Objs()) {
+		for _, p := range providersForTemplateValidation(options) {
+			crds, err := c.getProviderCRDs(ctx, p.name, p.providerType)
+			if err != nil {
+				return nil, err
+			}
+			for _, crd := range crds {
+				v.addCRD(crd)
+			}
+		}
+	}
+
+	return v.validate(ctx, options.Template.Objs())
+}
+
+type templateValidationProvider struct {
+	name         string
+	providerType clusterctlv1.ProviderType
+}
+
+// providersForTemplateValidation returns the list of providers to read CRDs from, applying the same defaults of clusterctl init.
+func providersForTemplateValidation(options ValidateClusterTemplateOptions) []templateValidationProvider {
+	coreProvider := options.CoreProvider
+	if coreProvider == "" {
+		coreProvider = config.ClusterAPIProviderName
+	}
+	bootstrapProviders := options.BootstrapProviders
+	if len(bootstrapProviders) == 0 {
+		bootstrapProviders = []string{config.KubeadmBootstrapProviderName}
+	}
+	controlPlaneProviders := options.ControlPlaneProviders
+	if len(controlPlaneProviders) == 0 {
+		controlPlaneProviders = []string{config.KubeadmControlPlaneProviderName}
+	}
+
+	providers := []templateValidationProvider{{name: coreProvider, providerType: clusterctlv1.CoreProviderType}}
+	for _, p := range bootstrapProviders {
+		providers = append(providers, templateValidationProvider{name: p, providerType: clusterctlv1.BootstrapProviderType})
+	}
+	for _, p := range controlPlaneProviders {
+		providers = append(providers, templateValidationProvider{name: p, providerType: clusterctlv1.ControlPlaneProviderType})
+	}
+	for _, p := range options.InfrastructureProviders {
+		providers = append(providers, templateValidationProvider{name: p, providerType: clusterctlv1.InfrastructureProviderType})
+	}
+	return providers
+}
+
+// getProviderCRDs returns the CRDs included in the components of a provider.
+func (c *clusterctlClient) getProviderCRDs(ctx context.Context, provider string, providerType clusterctlv1.ProviderType) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	name, version, err := parseProviderName(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	providerConfig, err := c.configClient.Providers().Get(name, providerType)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := c.repositoryClientFactory(ctx, RepositoryClientFactoryInput{Provider: providerConfig})
+	if err != nil {
+		return nil, err
+	}
+
+	if version == "" {
+		version = repo.DefaultVersion()
+	}
+
+	// NOTE: Variables in the components YAML are not processed, given that they are not expected in CRDs.
+	rawYaml, err := repo.Components().Raw(ctx, repository.ComponentsOptions{Version: version, SkipTemplateProcess: true})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read components for provider %q", provider)
+	}
+
+	objs, err := utilyaml.ToUnstructured(rawYaml)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse components for provider %q", provider)
+	}
+
+	crds := []*apiextensionsv1.CustomResourceDefinition{}
+	for i := range objs {
+		if objs[i].GroupVersionKind().GroupKind() != apiextensionsv1.SchemeGroupVersion.WithKind("CustomResourceDefinition").GroupKind() {
+			continue
+		}
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := scheme.Scheme.Convert(&objs[i], crd, nil); err != nil {
+			return nil, errors.Wrapf(err, "failed to convert %s to a CustomResourceDefinition", objs[i].GetName())
+		}
+		crds = append(crds, crd)
+	}
+	return crds, nil
+}
+
+// templateValidator validates the objects in a workload cluster template.
+type templateValidator struct {
+	crds map[schema.GroupKind]*apiextensionsv1.CustomResourceDefinition
+
+	// managementClient is used to read ClusterClasses not included in the template; it is nil
+	// if the management cluster is not available.
+	managementClient client.Client
+}
+
+// addCRD adds a CRD to the validator, if a CRD for the same kind is not already known.
+func (v *templateValidator) addCRD(crd *apiextensionsv1.CustomResourceDefinition) {
+	gk := schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}
+	if _, ok := v.crds[gk]; ok {
+		return
+	}
+	v.crds[gk] = crd
+}
+
+// hasMissingKinds returns true if there are objects whose kind is not defined by a known CRD.
+func (v *templateValidator) hasMissingKinds(objs []unstructured.Unstructured) bool {
+	for i := range objs {
+		gk := objs[i].GroupVersionKind().GroupKind()
+		if _, ok := v.crds[gk]; !ok && !isBuiltInGroup(gk.Group) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *templateValidator) validate(ctx context.Context, objs []unstructured.Unstructured) ([]TemplateValidationResult, error) {
+	results := []TemplateValidationResult{}
+	for i := range objs {
+		obj := &objs[i]
+		allErrs := v.validateObject(obj)
+
+		if obj.GroupVersionKind().GroupKind() == clusterv1.GroupVersion.WithKind("Cluster").GroupKind() {
+			errs, err := v.validateClusterTopology(ctx, obj, objs)
+			if err != nil {
+				return nil, err
+			}
+			allErrs = append(allErrs, errs...)
+		}
+
+		if len(allErrs) == 0 {
+			continue
+		}
+
+		result := TemplateValidationResult{
+			Object: corev1.ObjectReference{
+				APIVersion: obj.GetAPIVersion(),
+				Kind:       obj.GetKind(),
+				Namespace:  obj.GetNamespace(),
+				Name:       obj.GetName(),
+			},
+		}
+		for _, err := range allErrs {
+			result.Errors = append(result.Errors, err.Error())
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// validateObject validates an object against the OpenAPI schema of the CRD for its kind.
+// NOTE: The object is defaulted before validation, as the API server does, so fields with a default
+// value in the schema are not reported as missing.
+func (v *templateValidator) validateObject(obj *unstructured.Unstructured) field.ErrorList {
+	gvk := obj.GroupVersionKind()
+	crd, ok := v.crds[gvk.GroupKind()]
+	if !ok {
+		// Kubernetes built-in types are not defined by CRDs, so they can't be validated.
+		if isBuiltInGroup(gvk.Group) {
+			return nil
+		}
+		return field.ErrorList{field.NotFound(field.NewPath("kind"), fmt.Sprintf("no CustomResourceDefinition found for %s", gvk.GroupKind()))}
+	}
+
+	var crdVersion *apiextensionsv1.CustomResourceDefinitionVersion
+	for i := range crd.Spec.Versions {
+		if crd.Spec.Versions[i].Name == gvk.Version {
+			crdVersion = &crd.Spec.Versions[i]
+			break
+		}
+	}
+	if crdVersion == nil || !crdVersion.Served {
+		return field.ErrorList{field.NotSupported(field.NewPath("apiVersion"), gvk.GroupVersion().String(), servedVersions(crd))}
+	}
+	if crdVersion.Schema == nil || crdVersion.Schema.OpenAPIV3Schema == nil {
+		return nil
+	}
+
+	internalSchema := &apiextensions.JSONSchemaProps{}
+	if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(crdVersion.Schema.OpenAPIV3Schema, internalSchema, nil); err != nil {
+		return field.ErrorList{field.InternalError(nil, errors.Wrapf(err, "failed to convert the schema of %s", crd.Name))}
+	}
+	ss, err := structuralschema.NewStructural(internalSchema)
+	if err != nil {
+		return field.ErrorList{field.InternalError(nil, errors.Wrapf(err, "failed to create the structural schema of %s", crd.Name))}
+	}
+	validator, _, err := apiextensionsvalidation.NewSchemaValidator(internalSchema)
+	if err != nil {
+		return field.ErrorList{field.InternalError(nil, errors.Wrapf(err, "failed to create the schema validator of %s", crd.Name))}
+	}
+
+	content := obj.DeepCopy().UnstructuredContent()
+	structuraldefaulting.Default(content, ss)
+
+	var allErrs field.ErrorList
+
+	// Run pruning to check if it would drop any unknown fields.
+	unknownFields := structuralpruning.PruneWithOptions(content, ss, true, structuralschema.UnknownFieldPathOptions{TrackUnknownFieldPaths: true})
+	for _, unknownField := range unknownFields {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath(unknownField), "unknown field, it is not defined in the schema"))
+	}
+
+	allErrs = append(allErrs, apiextensionsvalidation.ValidateCustomResource(nil, content, validator)...)
+	return allErrs
+}
+
+// validateClusterTopology validates the variables of a Cluster using a ClusterClass, using the ClusterClass
+// from the template if it exists, or the one from the management cluster otherwise.
+func (v *templateValidator) validateClusterTopology(ctx context.Context, obj *unstructured.Unstructured, objs []unstructured.Unstructured) (field.ErrorList, error) {
+	c := &clusterv1.Cluster{}
+	if err := scheme.Scheme.Convert(obj, c, nil); err != nil {
+		return nil, errors.Wrapf(err, "failed to convert %s to a Cluster", obj.GetName())
+	}
+	if c.Spec.Topology == nil {
+		return nil, nil
+	}
+
+	classKey := types.NamespacedName{Namespace: c.GetClassKey().Namespace, Name: c.GetClassKey().Name}
+	if classKey.Namespace == "" {
+		classKey.Namespace = c.Namespace
+	}
+
+	clusterClass, err := v.getClusterClass(ctx, classKey, objs)
+	if err != nil {
+		return nil, err
+	}
+	if clusterClass == nil {
+		return field.ErrorList{field.NotFound(field.NewPath("spec", "topology", "class"), classKey.String())}, nil
+	}
+
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, webhooks.DefaultAndValidateVariables(ctx, c, nil, clusterClass)...)
+	allErrs = append(allErrs, webhooks.ValidateClusterForClusterClass(c, clusterClass)...)
+	return allErrs, nil
+}
+
+// getClusterClass returns a ClusterClass from the template or from the management cluster, or nil if it does not exist.
+// NOTE: ClusterClasses in the template are not reconciled, so variable definitions are computed from the inline
+// variables only; variables discovered from external patches can't be validated offline.
+func (v *templateValidator) getClusterClass(ctx context.Context, key types.NamespacedName, objs []unstructured.Unstructured) (*clusterv1.ClusterClass, error) {
+	for i := range objs {
+		obj := &objs[i]
+		if obj.GroupVersionKind().GroupKind() != clusterv1.GroupVersion.WithKind("ClusterClass").GroupKind() {
+			continue
+		}
+		if obj.GetName() != key.Name || (obj.GetNamespace() != "" && obj.GetNamespace() != key.Namespace) {
+			continue
+		}
+		clusterClass := &clusterv1.ClusterClass{}
+		if err := scheme.Scheme.Convert(obj, clusterClass, nil); err != nil {
+			return nil, errors.Wrapf(err, "failed to convert %s to a ClusterClass", obj.GetName())
+		}
+		clusterClass.Status.Variables = inlineStatusVariables(clusterClass.Spec.Variables)
+		return clusterClass, nil
+	}
+
+	if v.managementClient == nil {
+		return nil, nil
+	}
+	clusterClass := &clusterv1.ClusterClass{}
+	if err := v.managementClient.Get(ctx, key, clusterClass); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get ClusterClass %s", key)
+	}
+	return clusterClass, nil
+}
+
+// inlineStatusVariables returns the variable definitions the ClusterClass controller computes from inline variables.
+func inlineStatusVariables(variables []clusterv1.ClusterClassVariable) []clusterv1.ClusterClassStatusVariable {
+	statusVariables := make([]clusterv1.ClusterClassStatusVariable, 0, len(variables))
+	for _, variable := range variables {
+		statusVariables = append(statusVariables, clusterv1.ClusterClassStatusVariable{
+			Name: variable.Name,
+			Definitions: []clusterv1.ClusterClassStatusVariableDefinition{
+				{
+					From:     clusterv1.VariableDefinitionFromInline,
+					Required: variable.Required,
+					Metadata: variable.Metadata,
+					Schema:   variable.Schema,
+				},
+			},
+		})
+	}
+	return statusVariables
+}
+
+// isBuiltInGroup returns true for the API groups of Kubernetes built-in types.
+func isBuiltInGroup(group string) bool {
+	return !strings.Contains(group, ".") || (strings.HasSuffix(group, ".k8s.io") && !strings.HasSuffix(group, ".x-k8s.io"))
+}
+
+func servedVersions(crd *apiextensionsv1.CustomResourceDefinition) []string {
+	versions := []string{}
+	for _, version := range crd.Spec.Versions {
+		if version.Served {
+			versions = append(versions, fmt.Sprintf("%s/%s", crd.Spec.Group, version.Name))
+		}
+	}
+	return versions
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
+	yaml "sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor"
+	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
+)
+
+var coreCRDsForValidationYAML = []byte(`apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: clusters.cluster.x-k8s.io
+spec:
+  group: cluster.x-k8s.io
+  names:
+    kind: Cluster
+    plural: clusters
+  scope: Namespaced
+  versions:
+  - name: v1beta1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        x-kubernetes-preserve-unknown-fields: true
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: clusterclasses.cluster.x-k8s.io
+spec:
+  group: cluster.x-k8s.io
+  names:
+    kind: ClusterClass
+    plural: clusterclasses
+  scope: Namespaced
+  versions:
+  - name: v1beta1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        x-kubernetes-preserve-unknown-fields: true`)
+
+var infraCRDsForValidationYAML = []byte(`apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: genericinfrastructureclusters.infrastructure.cluster.x-k8s.io
+spec:
+  group: infrastructure.cluster.x-k8s.io
+  names:
+    kind: GenericInfrastructureCluster
+    plural: genericinfrastructureclusters
+  scope: Namespaced
+  versions:
+  - name: v1beta1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          apiVersion:
+            type: string
+          kind:
+            type: string
+          metadata:
+            type: object
+          spec:
+            type: object
+            required:
+            - region
+            properties:
+              region:
+                type: string
+              zones:
+                type: integer
+                default: 1`)
+
+var templateForValidationYAML = []byte(`apiVersion: infrastructure.cluster.x-k8s.io/v1beta1
+kind: GenericInfrastructureCluster
+metadata:
+  name: valid
+spec:
+  region: eu
+---
+apiVersion: infrastructure.cluster.x-k8s.io/v1beta1
+kind: GenericInfrastructureCluster
+metadata:
+  name: unknown-field
+spec:
+  region: eu
+  foo: bar
+---
+apiVersion: infrastructure.cluster.x-k8s.io/v1beta1
+kind: GenericInfrastructureCluster
+metadata:
+  name: missing-field
+spec:
+  zones: 3
+---
+apiVersion: infrastructure.cluster.x-k8s.io/v1alpha1
+kind: GenericInfrastructureCluster
+metadata:
+  name: not-served-version
+spec:
+  region: eu
+---
+apiVersion: infrastructure.cluster.x-k8s.io/v1beta1
+kind: GenericInfrastructureMachine
+metadata:
+  name: unknown-kind
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: built-in-kind
+---
+apiVersion: cluster.x-k8s.io/v1beta1
+kind: ClusterClass
+metadata:
+  name: dev
+spec:
+  variables:
+  - name: cpu
+    required: true
+    schema:
+      openAPIV3Schema:
+        type: integer
+---
+apiVersion: cluster.x-k8s.io/v1beta1
+kind: Cluster
+metadata:
+  name: valid-variables
+spec:
+  topology:
+    class: dev
+    version: v1.30.0
+    variables:
+    - name: cpu
+      value: 4
+---
+apiVersion: cluster.x-k8s.io/v1beta1
+kind: Cluster
+metadata:
+  name: invalid-variables
+spec:
+  topology:
+    class: dev
+    version: v1.30.0
+    variables:
+    - name: cpu
+      value: four
+---
+apiVersion: cluster.x-k8s.io/v1beta1
+kind: Cluster
+metadata:
+  name: missing-variables
+spec:
+  topology:
+    class: dev
+    version: v1.30.0
+---
+apiVersion: cluster.x-k8s.io/v1beta1
+kind: Cluster
+metadata:
+  name: missing-class
+spec:
+  topology:
+    class: prod
+    version: v1.30.0`)
+
+func Test_clusterctlClient_ValidateClusterTemplate(t *testing.T) {
+	g := NewWithT(t)
+
+	ctx := context.Background()
+
+	config1 := newFakeConfig(ctx).
+		WithProvider(capiProviderConfig).
+		WithProvider(bootstrapProviderConfig).
+		WithProvider(controlPlaneProviderConfig).
+		WithProvider(infraProviderConfig)
+
+	repositoryWithComponents := func(provider config.Provider, components []byte) *fakeRepositoryClient {
+		return newFakeRepository(ctx, provider, config1).
+			WithPaths("root", "components.yaml").
+			WithDefaultVersion("v1.0.0").
+			WithFile("v1.0.0", "components.yaml", components)
+	}
+
+	// The management cluster does not have any CRD installed, so CRDs are read from the provider repositories.
+	cluster1 := newFakeCluster(cluster.Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"}, config1)
+
+	client := newFakeClient(ctx, config1).
+		WithCluster(cluster1).
+		WithRepository(repositoryWithComponents(capiProviderConfig, coreCRDsForValidationYAML)).
+		WithRepository(repositoryWithComponents(bootstrapProviderConfig, componentsYAML("ns1"))).
+		WithRepository(repositoryWithComponents(controlPlaneProviderConfig, componentsYAML("ns1"))).
+		WithRepository(repositoryWithComponents(infraProviderConfig, infraCRDsForValidationYAML))
+
+	template, err := repository.NewTemplate(repository.TemplateInput{
+		RawArtifact:           templateForValidationYAML,
+		ConfigVariablesClient: config1.Variables(),
+		Processor:             yaml.NewSimpleProcessor(),
+		TargetNamespace:       "ns1",
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	got, err := client.ValidateClusterTemplate(ctx, ValidateClusterTemplateOptions{
+		Kubeconfig:              Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+		Template:                template,
+		InfrastructureProviders: []string{infraProviderConfig.Name()},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	gotErrors := map[string][]string{}
+	for _, result := range got {
+		g.Expect(result.Object.Namespace).To(Equal("ns1"))
+		gotErrors[result.Object.Name] = result.Errors
+	}
+	g.Expect(gotErrors).To(HaveLen(7))
+	g.Expect(gotErrors["unknown-field"]).To(ConsistOf(ContainSubstring("spec.foo: Forbidden: unknown field")))
+	g.Expect(gotErrors["missing-field"]).To(ConsistOf(ContainSubstring("spec.region: Required value")))
+	g.Expect(gotErrors["not-served-version"]).To(ConsistOf(ContainSubstring("Unsupported value: \"infrastructure.cluster.x-k8s.io/v1alpha1\"")))
+	g.Expect(gotErrors["unknown-kind"]).To(ConsistOf(ContainSubstring("no CustomResourceDefinition found for GenericInfrastructureMachine.infrastructure.cluster.x-k8s.io")))
+	g.Expect(gotErrors["invalid-variables"]).To(ContainElement(ContainSubstring("spec.topology.variables[cpu].value: Invalid value")))
+	g.Expect(gotErrors["missing-variables"]).To(ConsistOf(ContainSubstring("spec.topology.variables: Required value: required variable \"cpu\" must be set")))
+	g.Expect(gotErrors["missing-class"]).To(ConsistOf(ContainSubstring("spec.topology.class: Not found: \"ns1/prod\"")))
+}
+
+func Test_templateValidator_getClusterClass(t *testing.T) {
+	g := NewWithT(t)
+
+	objs, err := utilyaml.ToUnstructured(templateForValidationYAML)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	v := &templateValidator{}
+
+	// ClusterClasses in the template get variable definitions computed from the inline variables.
+	clusterClass, err := v.getClusterClass(context.Background(), types.NamespacedName{Namespace: "ns1", Name: "dev"}, objs)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(clusterClass).ToNot(BeNil())
+	g.Expect(clusterClass.Status.Variables).To(HaveLen(1))
+	g.Expect(clusterClass.Status.Variables[0].Name).To(Equal("cpu"))
+	g.Expect(clusterClass.Status.Variables[0].Definitions).To(HaveLen(1))
+	g.Expect(clusterClass.Status.Variables[0].Definitions[0].From).To(Equal(clusterv1.VariableDefinitionFromInline))
+	g.Expect(clusterClass.Status.Variables[0].Definitions[0].Required).To(BeTrue())
+
+	// ClusterClasses not in the template are not found if the management cluster is not available.
+	clusterClass, err = v.getClusterClass(context.Background(), types.NamespacedName{Namespace: "ns1", Name: "prod"}, objs)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(clusterClass).To(BeNil())
+}
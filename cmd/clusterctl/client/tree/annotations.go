@@ -18,6 +18,7 @@ package tree
 
 import (
 	"strconv"
+	"strings"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -67,6 +68,13 @@ const (
 	// Objects are sorted by their z-order from highest to lowest, and then by their name in alphabetical order if the
 	// z-order is the same. Objects with no z-order set are assumed to have a default z-order of 0.
 	ObjectZOrderAnnotation = "tree.cluster.x-k8s.io.io/z-order"
+
+	// ChangedConditionsAnnotation contains the list of comma separated condition types that changed since the previous
+	// representation of the same cluster, e.g. when watching a cluster.
+	ChangedConditionsAnnotation = "tree.cluster.x-k8s.io.io/changed-conditions"
+
+	// ChangedConditionsSeparator is the separator used in the ChangedConditionsAnnotation.
+	ChangedConditionsSeparator = ","
 )
 
 // GetMetaName returns the object meta name that should be used for the object in the presentation layer, if defined.
@@ -159,6 +167,25 @@ func GetZOrder(obj client.Object) int {
 	return 0
 }
 
+// GetChangedConditions returns the condition types that changed since the previous representation of the same cluster.
+func GetChangedConditions(obj client.Object) []string {
+	if val, ok := getAnnotation(obj, ChangedConditionsAnnotation); ok && val != "" {
+		return strings.Split(val, ChangedConditionsSeparator)
+	}
+	return nil
+}
+
+// IsChangedCondition returns true if the condition with the given type changed since the previous
+// representation of the same cluster.
+func IsChangedCondition(obj client.Object, conditionType string) bool {
+	for _, t := range GetChangedConditions(obj) {
+		if t == conditionType {
+			return true
+		}
+	}
+	return false
+}
+
 // IsVirtualObject returns true if the object does not correspond to any real object, but instead it is
 // a virtual object introduced to provide a better representation of the cluster status.
 func IsVirtualObject(obj client.Object) bool {
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tree
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// ObjectTreeNode is a serializable representation of an object in an ObjectTree, including
+// all the information the presentation layer gets from the tree annotations.
+type ObjectTreeNode struct {
+	// Object references the object represented by the node.
+	// NOTE: virtual and group objects do not correspond to any real object.
+	Object corev1.ObjectReference `json:"object"`
+
+	// MetaName is the name that should be used for the object in the presentation layer, e.g. ControlPlane.
+	MetaName string `json:"metaName,omitempty"`
+
+	// Virtual is true if the object does not correspond to any real object, e.g. Workers.
+	Virtual bool `json:"virtual,omitempty"`
+
+	// Grouping is true if the node's children with the same status have been grouped.
+	Grouping bool `json:"grouping,omitempty"`
+
+	// Group is true if the node represents a group of sibling objects, e.g. a group of Machines.
+	Group bool `json:"group,omitempty"`
+
+	// GroupItems contains the names of the objects in the group.
+	GroupItems []string `json:"groupItems,omitempty"`
+
+	// GroupItemsAvailable is the number of available objects in the group, e.g. available Machines.
+	GroupItemsAvailable *int `json:"groupItemsAvailable,omitempty"`
+
+	// GroupItemsReady is the number of ready objects in the group, e.g. ready Machines.
+	GroupItemsReady *int `json:"groupItemsReady,omitempty"`
+
+	// GroupItemsUpToDate is the number of up-to-date objects in the group, e.g. up-to-date Machines.
+	GroupItemsUpToDate *int `json:"groupItemsUpToDate,omitempty"`
+
+	// Contract is the Cluster API contract the object abides to, if known.
+	Contract string `json:"contract,omitempty"`
+
+	// ShowConditions is true if all the object's conditions have been requested with ShowOtherConditions.
+	ShowConditions bool `json:"showConditions,omitempty"`
+
+	// DeletionTimestamp is set if the object is being deleted.
+	DeletionTimestamp *metav1.Time `json:"deletionTimestamp,omitempty"`
+
+	// Conditions are the object's conditions.
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+
+	// V1Beta2Conditions are the object's v1beta2 conditions.
+	V1Beta2Conditions []metav1.Condition `json:"v1beta2Conditions,omitempty"`
+
+	// Children are the node's children, sorted in the same order used when printing the tree.
+	Children []*ObjectTreeNode `json:"children,omitempty"`
+}
+
+// ToNode returns the serializable representation of the object tree, starting from the root.
+func (od ObjectTree) ToNode() *ObjectTreeNode {
+	return od.toNode(od.root)
+}
+
+func (od ObjectTree) toNode(obj client.Object) *ObjectTreeNode {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	node := &ObjectTreeNode{
+		Object: corev1.ObjectReference{
+			APIVersion: gvk.GroupVersion().String(),
+			Kind:       gvk.Kind,
+			Namespace:  obj.GetNamespace(),
+			Name:       obj.GetName(),
+			UID:        obj.GetUID(),
+		},
+		MetaName:          GetMetaName(obj),
+		Virtual:           IsVirtualObject(obj),
+		Grouping:          IsGroupingObject(obj),
+		Group:             IsGroupObject(obj),
+		Contract:          GetObjectContract(obj),
+		ShowConditions:    IsShowConditionsObject(obj),
+		DeletionTimestamp: obj.GetDeletionTimestamp(),
+		V1Beta2Conditions: GetAllV1Beta2Conditions(obj),
+	}
+	if getter := objToGetter(obj); getter != nil {
+		node.Conditions = getter.GetConditions()
+	}
+	if node.Group {
+		node.GroupItems = strings.Split(GetGroupItems(obj), GroupItemsSeparator)
+		node.GroupItemsAvailable = getIntAnnotation(obj, GroupItemsAvailableCounter)
+		node.GroupItemsReady = getIntAnnotation(obj, GroupItemsReadyCounter)
+		node.GroupItemsUpToDate = getIntAnnotation(obj, GroupItemsUpToDateCounter)
+	}
+
+	children := od.GetObjectsByParent(obj.GetUID())
+	// Children are sorted by z-order and name such that objects with higher z-order come first,
+	// and objects with the same z-order are in alphabetical order.
+	sort.SliceStable(children, func(i, j int) bool {
+		if GetZOrder(children[i]) == GetZOrder(children[j]) {
+			return nodeSortKey(children[i]) < nodeSortKey(children[j])
+		}
+		return GetZOrder(children[i]) > GetZOrder(children[j])
+	})
+	for _, child := range children {
+		node.Children = append(node.Children, od.toNode(child))
+	}
+	return node
+}
+
+func nodeSortKey(obj client.Object) string {
+	if IsVirtualObject(obj) {
+		if metaName := GetMetaName(obj); metaName != "" {
+			return metaName
+		}
+		return obj.GetName()
+	}
+	return fmt.Sprintf("%s/%s", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName())
+}
+
+func getIntAnnotation(obj client.Object, annotation string) *int {
+	val, ok := getAnnotation(obj, annotation)
+	if !ok {
+		return nil
+	}
+	v, err := strconv.Atoi(val)
+	if err != nil {
+		return nil
+	}
+	return &v
+}
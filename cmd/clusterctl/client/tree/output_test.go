@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tree
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func Test_ObjectTree_ToNode(t *testing.T) {
+	g := NewWithT(t)
+
+	root := fakeCluster("my-cluster",
+		withClusterCondition(conditions.TrueCondition(clusterv1.ReadyCondition)),
+		withClusterV1Beta2Condition(metav1.Condition{Type: clusterv1.ReadyV1Beta2Condition, Status: metav1.ConditionTrue, Reason: "Ready"}),
+	)
+	objectTree := NewObjectTree(root, ObjectTreeOptions{Grouping: true, ShowOtherConditions: "Cluster"})
+
+	controlPlane := fakeMachine("control-plane",
+		withMachineCondition(conditions.TrueCondition(clusterv1.ReadyCondition)),
+	)
+	objectTree.Add(root, controlPlane, ObjectMetaName("ControlPlane"), GroupingObject(true), ZOrder(1))
+
+	workers := VirtualObject("ns", "WorkerGroup", "Workers")
+	objectTree.Add(root, workers, GroupingObject(true))
+	for _, name := range []string{"m1", "m2"} {
+		objectTree.Add(workers, fakeMachine(name,
+			withMachineCondition(conditions.TrueCondition(clusterv1.ReadyCondition)),
+		))
+	}
+	objectTree.Add(workers, fakeMachine("m3",
+		withMachineCondition(conditions.FalseCondition(clusterv1.ReadyCondition, "Reason", clusterv1.ConditionSeverityError, "message")),
+	))
+
+	node := objectTree.ToNode()
+
+	g.Expect(node.Object.Kind).To(Equal("Cluster"))
+	g.Expect(node.Object.Name).To(Equal("my-cluster"))
+	g.Expect(node.ShowConditions).To(BeTrue())
+	g.Expect(node.Conditions).To(HaveLen(1))
+	g.Expect(node.V1Beta2Conditions).To(ConsistOf(HaveField("Type", clusterv1.ReadyV1Beta2Condition)))
+
+	// Children are sorted by z-order first.
+	g.Expect(node.Children).To(HaveLen(2))
+	g.Expect(node.Children[0].Object.Name).To(Equal("control-plane"))
+	g.Expect(node.Children[0].MetaName).To(Equal("ControlPlane"))
+	g.Expect(node.Children[0].Grouping).To(BeTrue())
+
+	g.Expect(node.Children[1].Object.Name).To(Equal("Workers"))
+	g.Expect(node.Children[1].Virtual).To(BeTrue())
+
+	// Machines with the same ready condition are grouped, other Machines are not.
+	machines := node.Children[1].Children
+	g.Expect(machines).To(HaveLen(2))
+	g.Expect(machines[0].Object.Name).To(Equal("m3"))
+	g.Expect(machines[0].Group).To(BeFalse())
+	g.Expect(machines[0].Conditions).To(ConsistOf(HaveField("Severity", clusterv1.ConditionSeverityError)))
+
+	g.Expect(machines[1].Group).To(BeTrue())
+	g.Expect(machines[1].Object.Kind).To(Equal("MachineGroup"))
+	g.Expect(machines[1].GroupItems).To(Equal([]string{"m1", "m2"}))
+	g.Expect(machines[1].Children).To(BeEmpty())
+}
+
+func Test_ObjectTree_ToNode_groupItemsCounters(t *testing.T) {
+	g := NewWithT(t)
+
+	root := fakeCluster("my-cluster")
+	objectTree := NewObjectTree(root, ObjectTreeOptions{Grouping: true, V1Beta2: true})
+
+	workers := VirtualObject("ns", "WorkerGroup", "Workers")
+	objectTree.Add(root, workers, GroupingObject(true))
+	for _, name := range []string{"m1", "m2"} {
+		objectTree.Add(workers, fakeMachine(name,
+			withMachineV1Beta2Condition(metav1.Condition{Type: clusterv1.AvailableV1Beta2Condition, Status: metav1.ConditionTrue}),
+			withMachineV1Beta2Condition(metav1.Condition{Type: clusterv1.ReadyV1Beta2Condition, Status: metav1.ConditionTrue}),
+			withMachineV1Beta2Condition(metav1.Condition{Type: clusterv1.MachineUpToDateV1Beta2Condition, Status: metav1.ConditionFalse}),
+		))
+	}
+
+	node := objectTree.ToNode()
+
+	g.Expect(node.Children).To(HaveLen(1))
+	g.Expect(node.Children[0].Children).To(HaveLen(1))
+	group := node.Children[0].Children[0]
+	g.Expect(group.Group).To(BeTrue())
+	g.Expect(group.GroupItems).To(Equal([]string{"m1", "m2"}))
+	g.Expect(group.GroupItemsAvailable).To(Equal(ptr.To(2)))
+	g.Expect(group.GroupItemsReady).To(Equal(ptr.To(2)))
+	g.Expect(group.GroupItemsUpToDate).To(Equal(ptr.To(0)))
+}
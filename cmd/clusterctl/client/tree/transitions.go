@@ -0,0 +1,154 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tree
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ConditionTransition describes the change of an object's condition between two representations of the same cluster.
+type ConditionTransition struct {
+	// Object is the object whose condition changed.
+	Object client.Object
+
+	// Type is the type of the condition.
+	Type string
+
+	// PreviousStatus and Status are the status of the condition before and after the change.
+	PreviousStatus, Status string
+
+	// PreviousReason and Reason are the reason of the condition before and after the change.
+	PreviousReason, Reason string
+
+	// Elapsed is the time spent by the condition in the previous status, if the status changed.
+	Elapsed time.Duration
+}
+
+// conditionState is the subset of condition fields compared when looking for changes;
+// it allows to compare both v1beta1 and v1beta2 conditions.
+type conditionState struct {
+	status             string
+	severity           string
+	reason             string
+	message            string
+	lastTransitionTime metav1.Time
+}
+
+// MarkChangedConditions compares the conditions of the objects in the current tree with the conditions of the same objects
+// in the previous tree, adds the ChangedConditionsAnnotation to the objects with changed conditions, and returns the list
+// of transitions. Objects existing only in one of the two trees, e.g. group objects, are ignored.
+func MarkChangedConditions(previous, current *ObjectTree) []ConditionTransition {
+	if previous == nil || current == nil {
+		return nil
+	}
+
+	previousObjects := previous.objectsByUID()
+	var transitions []ConditionTransition
+	for uid, obj := range current.objectsByUID() {
+		previousObj, ok := previousObjects[uid]
+		if !ok {
+			continue
+		}
+
+		previousConditions := getConditionStates(previousObj, current.options.V1Beta2)
+		currentConditions := getConditionStates(obj, current.options.V1Beta2)
+
+		var changed []string
+		for conditionType, c := range currentConditions {
+			p, ok := previousConditions[conditionType]
+			if ok && p.status == c.status && p.severity == c.severity && p.reason == c.reason && p.message == c.message {
+				continue
+			}
+
+			transition := ConditionTransition{
+				Object: obj,
+				Type:   conditionType,
+				Status: c.status,
+				Reason: c.reason,
+			}
+			if ok {
+				transition.PreviousStatus = p.status
+				transition.PreviousReason = p.reason
+				if p.status != c.status && !p.lastTransitionTime.IsZero() && c.lastTransitionTime.After(p.lastTransitionTime.Time) {
+					transition.Elapsed = c.lastTransitionTime.Sub(p.lastTransitionTime.Time)
+				}
+			}
+			transitions = append(transitions, transition)
+			changed = append(changed, conditionType)
+		}
+
+		if len(changed) > 0 {
+			sort.Strings(changed)
+			addAnnotation(obj, ChangedConditionsAnnotation, strings.Join(changed, ChangedConditionsSeparator))
+		}
+	}
+
+	sort.Slice(transitions, func(i, j int) bool {
+		ki, kj := nodeSortKey(transitions[i].Object), nodeSortKey(transitions[j].Object)
+		if ki == kj {
+			return transitions[i].Type < transitions[j].Type
+		}
+		return ki < kj
+	})
+	return transitions
+}
+
+// objectsByUID returns all the objects in the tree, including the root.
+func (od ObjectTree) objectsByUID() map[types.UID]client.Object {
+	objs := make(map[types.UID]client.Object, len(od.items)+1)
+	for uid, obj := range od.items {
+		objs[uid] = obj
+	}
+	objs[od.root.GetUID()] = od.root
+	return objs
+}
+
+func getConditionStates(obj client.Object, v1beta2 bool) map[string]conditionState {
+	states := map[string]conditionState{}
+	if v1beta2 {
+		for _, c := range GetAllV1Beta2Conditions(obj) {
+			states[c.Type] = conditionState{
+				status:             string(c.Status),
+				reason:             c.Reason,
+				message:            c.Message,
+				lastTransitionTime: c.LastTransitionTime,
+			}
+		}
+		return states
+	}
+
+	getter := objToGetter(obj)
+	if getter == nil {
+		return states
+	}
+	for _, c := range getter.GetConditions() {
+		states[string(c.Type)] = conditionState{
+			status:             string(c.Status),
+			severity:           string(c.Severity),
+			reason:             c.Reason,
+			message:            c.Message,
+			lastTransitionTime: c.LastTransitionTime,
+		}
+	}
+	return states
+}
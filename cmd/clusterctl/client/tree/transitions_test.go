@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tree
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func Test_MarkChangedConditions(t *testing.T) {
+	t0 := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	t1 := metav1.NewTime(t0.Add(90 * time.Second))
+
+	newTree := func(machineReady, machineHealthy *clusterv1.Condition) *ObjectTree {
+		root := fakeCluster("my-cluster",
+			withClusterCondition(&clusterv1.Condition{Type: clusterv1.ReadyCondition, Status: "True", LastTransitionTime: t0}),
+		)
+		objectTree := NewObjectTree(root, ObjectTreeOptions{})
+		objectTree.Add(root, fakeMachine("m1", withMachineCondition(machineReady), withMachineCondition(machineHealthy)))
+		objectTree.Add(root, fakeMachine("m2", withMachineCondition(&clusterv1.Condition{Type: clusterv1.ReadyCondition, Status: "True", LastTransitionTime: t0})))
+		return objectTree
+	}
+
+	g := NewWithT(t)
+
+	previous := newTree(
+		&clusterv1.Condition{Type: clusterv1.ReadyCondition, Status: "False", Severity: clusterv1.ConditionSeverityInfo, Reason: "WaitingForBootstrapData", LastTransitionTime: t0},
+		&clusterv1.Condition{Type: clusterv1.MachineHealthCheckSucceededCondition, Status: "True", LastTransitionTime: t0},
+	)
+	current := newTree(
+		&clusterv1.Condition{Type: clusterv1.ReadyCondition, Status: "True", LastTransitionTime: t1},
+		&clusterv1.Condition{Type: clusterv1.MachineHealthCheckSucceededCondition, Status: "True", LastTransitionTime: t0},
+	)
+
+	// Nothing is marked without a previous tree.
+	g.Expect(MarkChangedConditions(nil, current)).To(BeEmpty())
+
+	transitions := MarkChangedConditions(previous, current)
+	g.Expect(transitions).To(HaveLen(1))
+	g.Expect(transitions[0].Object.GetName()).To(Equal("m1"))
+	g.Expect(transitions[0].Type).To(Equal(string(clusterv1.ReadyCondition)))
+	g.Expect(transitions[0].PreviousStatus).To(Equal("False"))
+	g.Expect(transitions[0].PreviousReason).To(Equal("WaitingForBootstrapData"))
+	g.Expect(transitions[0].Status).To(Equal("True"))
+	g.Expect(transitions[0].Elapsed).To(Equal(90 * time.Second))
+
+	g.Expect(GetChangedConditions(current.GetObject("m1"))).To(Equal([]string{string(clusterv1.ReadyCondition)}))
+	g.Expect(IsChangedCondition(current.GetObject("m1"), string(clusterv1.ReadyCondition))).To(BeTrue())
+	g.Expect(IsChangedCondition(current.GetObject("m1"), string(clusterv1.MachineHealthCheckSucceededCondition))).To(BeFalse())
+	g.Expect(GetChangedConditions(current.GetObject("m2"))).To(BeEmpty())
+	g.Expect(GetChangedConditions(current.GetRoot())).To(BeEmpty())
+}
+
+func Test_MarkChangedConditions_v1Beta2(t *testing.T) {
+	g := NewWithT(t)
+
+	newTree := func(available metav1.Condition) *ObjectTree {
+		root := fakeCluster("my-cluster", withClusterV1Beta2Condition(available))
+		return NewObjectTree(root, ObjectTreeOptions{V1Beta2: true})
+	}
+
+	previous := newTree(metav1.Condition{Type: clusterv1.AvailableV1Beta2Condition, Status: metav1.ConditionFalse, Reason: "NotAvailable", Message: "* Control plane not available"})
+	current := newTree(metav1.Condition{Type: clusterv1.AvailableV1Beta2Condition, Status: metav1.ConditionFalse, Reason: "NotAvailable", Message: "* Workers not available"})
+
+	// A change of message is reported, without elapsed time because the status did not change.
+	transitions := MarkChangedConditions(previous, current)
+	g.Expect(transitions).To(HaveLen(1))
+	g.Expect(transitions[0].Type).To(Equal(clusterv1.AvailableV1Beta2Condition))
+	g.Expect(transitions[0].Elapsed).To(BeZero())
+	g.Expect(GetChangedConditions(current.GetRoot())).To(Equal([]string{clusterv1.AvailableV1Beta2Condition}))
+}
@@ -35,6 +35,10 @@ const upgradeItemProviderNameError = "invalid provider name %q. Provider name sh
 type PlanUpgradeOptions struct {
 	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty, default discovery rules apply.
 	Kubeconfig Kubeconfig
+
+	// Diff, if set, computes for each provider the changes to CRDs, RBAC, Deployments and variables
+	// that the upgrade would apply. This option is used only by PlanUpgrade.
+	Diff bool
 }
 
 func (c *clusterctlClient) PlanCertManagerUpgrade(ctx context.Context, options PlanUpgradeOptions) (CertManagerUpgradePlan, error) {
@@ -71,12 +75,25 @@ func (c *clusterctlClient) PlanUpgrade(ctx context.Context, options PlanUpgradeO
 		return nil, err
 	}
 
+	if options.Diff {
+		for i := range upgradePlans {
+			for _, upgradeItem := range upgradePlans[i].Providers {
+				diff, err := clusterClient.ProviderUpgrader().Diff(ctx, upgradeItem)
+				if err != nil {
+					return nil, err
+				}
+				upgradePlans[i].Diffs = append(upgradePlans[i].Diffs, *diff)
+			}
+		}
+	}
+
 	// UpgradePlan is an alias for cluster.UpgradePlan; this makes the conversion
 	aliasUpgradePlan := make([]UpgradePlan, len(upgradePlans))
 	for i, plan := range upgradePlans {
 		aliasUpgradePlan[i] = UpgradePlan{
 			Contract:  plan.Contract,
 			Providers: plan.Providers,
+			Diffs:     plan.Diffs,
 		}
 	}
 
@@ -123,6 +140,14 @@ type ApplyUpgradeOptions struct {
 
 	// WaitProviderTimeout sets the timeout per provider upgrade.
 	WaitProviderTimeout time.Duration
+
+	// CRDMigrationConcurrency sets the number of CRs migrated concurrently to the storage version of new CRDs.
+	// If not set, CRs are migrated one at a time.
+	CRDMigrationConcurrency int
+
+	// DryRun instructs the upgrade apply command to only report the CRs to be migrated to the storage version
+	// of new CRDs, without upgrading cert-manager and the providers.
+	DryRun bool
 }
 
 func (c *clusterctlClient) ApplyUpgrade(ctx context.Context, options ApplyUpgradeOptions) error {
@@ -156,9 +181,12 @@ func (c *clusterctlClient) ApplyUpgrade(ctx context.Context, options ApplyUpgrad
 	// NOTE: it is safe to upgrade to latest version of cert-manager given that it provides
 	// conversion web-hooks around Issuer/Certificate kinds, so installing an older versions of providers
 	// should continue to work with the latest cert-manager.
+	// NOTE: in dry-run mode cert-manager is not upgraded.
 	certManager := clusterClient.CertManager()
-	if err := certManager.EnsureLatestVersion(ctx); err != nil {
-		return err
+	if !options.DryRun {
+		if err := certManager.EnsureLatestVersion(ctx); err != nil {
+			return err
+		}
 	}
 
 	// Check if the user want a custom upgrade
@@ -171,8 +199,10 @@ func (c *clusterctlClient) ApplyUpgrade(ctx context.Context, options ApplyUpgrad
 		len(options.AddonProviders) > 0
 
 	opts := cluster.UpgradeOptions{
-		WaitProviders:       options.WaitProviders,
-		WaitProviderTimeout: options.WaitProviderTimeout,
+		WaitProviders:           options.WaitProviders,
+		WaitProviderTimeout:     options.WaitProviderTimeout,
+		CRDMigrationConcurrency: options.CRDMigrationConcurrency,
+		DryRun:                  options.DryRun,
 	}
 
 	// If we are upgrading a specific set of providers only, process the providers and call ApplyCustomPlan.
@@ -0,0 +1,289 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package yamlprocessor
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	cueyaml "cuelang.org/go/encoding/yaml"
+	"github.com/pkg/errors"
+)
+
+const (
+	// cueTagAttribute is the name of the attribute used to declare variables in CUE templates.
+	cueTagAttribute = "tag"
+
+	// cueObjectsField is the name of the top-level field of CUE templates containing the objects to be generated.
+	cueObjectsField = "objects"
+)
+
+// CUEProcessor is a yaml processor that renders templates written in CUE.
+// Variables are declared using tag attributes on fields, e.g.
+// vars: clusterName: string @tag(CLUSTER_NAME), and are required unless the field
+// has a default value, e.g. vars: replicas: *"1" | string @tag(WORKER_MACHINE_COUNT).
+// Variable values are strings, unless the tag attribute specifies a type, e.g.
+// vars: replicas: *1 | int @tag(WORKER_MACHINE_COUNT,type=int); supported types are
+// int, number and bool. The objects to be generated are read from the top-level
+// objects list of the template.
+// See https://cuelang.org/docs/ for more details.
+type CUEProcessor struct{}
+
+var _ Processor = &CUEProcessor{}
+
+// NewCUEProcessor returns a new CUE processor.
+func NewCUEProcessor() *CUEProcessor {
+	return &CUEProcessor{}
+}
+
+// GetTemplateName returns the name of the template that the CUE processor
+// uses. It follows the cluster template naming convention of
+// "cluster-template<-flavor>.cue".
+func (tp *CUEProcessor) GetTemplateName(_, flavor string) string {
+	name := "cluster-template"
+	if flavor != "" {
+		name = fmt.Sprintf("%s-%s", name, flavor)
+	}
+	return fmt.Sprintf("%s.cue", name)
+}
+
+// GetClusterClassTemplateName returns the name of the cluster class template
+// that the CUE processor uses. It follows the cluster class template naming
+// convention of "clusterclass<-name>.cue".
+func (tp *CUEProcessor) GetClusterClassTemplateName(_, name string) string {
+	return fmt.Sprintf("clusterclass-%s.cue", name)
+}
+
+// GetVariables returns a list of the variables specified in the template.
+func (tp *CUEProcessor) GetVariables(rawArtifact []byte) ([]string, error) {
+	variables, err := tp.GetVariableMap(rawArtifact)
+	if err != nil {
+		return nil, err
+	}
+	varNames := make([]string, 0, len(variables))
+	for k := range variables {
+		varNames = append(varNames, k)
+	}
+	sort.Strings(varNames)
+	return varNames, nil
+}
+
+// GetVariableMap returns a map of the variables specified in the template.
+func (tp *CUEProcessor) GetVariableMap(rawArtifact []byte) (map[string]*string, error) {
+	v, err := compileCUETemplate(rawArtifact)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := inspectCUEVariables(v)
+	if err != nil {
+		return nil, err
+	}
+	variables := map[string]*string{}
+	for _, f := range fields {
+		defaultValue, seen := variables[f.name]
+		if !seen || defaultValue != nil {
+			// NOTE: a variable is required if at least one of the fields using it does not have a default value.
+			variables[f.name] = f.defaultValue
+		}
+	}
+	return variables, nil
+}
+
+// Process returns the final yaml generated from the objects of the template,
+// after filling the fields which declare variables with the values of the variables.
+// If there are variables without corresponding values, it will return the raw
+// template along with an error.
+func (tp *CUEProcessor) Process(rawArtifact []byte, variablesClient func(string) (string, error)) ([]byte, error) {
+	v, err := compileCUETemplate(rawArtifact)
+	if err != nil {
+		return rawArtifact, err
+	}
+	fields, err := inspectCUEVariables(v)
+	if err != nil {
+		return rawArtifact, err
+	}
+
+	missingVariables := map[string]bool{}
+	for _, f := range fields {
+		value, err := variablesClient(f.name)
+		if err != nil {
+			// add to missingVariables list if the variable does not exist in the
+			// variablesClient AND the field does not have a default value
+			if f.defaultValue == nil {
+				missingVariables[f.name] = true
+			}
+			continue
+		}
+		typedValue, err := f.parse(value)
+		if err != nil {
+			return rawArtifact, err
+		}
+		v = v.FillPath(f.path, typedValue)
+	}
+
+	if len(missingVariables) > 0 {
+		missing := make([]string, 0, len(missingVariables))
+		for name := range missingVariables {
+			missing = append(missing, name)
+		}
+		return rawArtifact, &errMissingVariables{missing}
+	}
+
+	objects := v.LookupPath(cue.ParsePath(cueObjectsField))
+	if !objects.Exists() {
+		return rawArtifact, errors.Errorf("the template must define the objects to be generated in the top-level %s field", cueObjectsField)
+	}
+	if err := objects.Validate(cue.Final(), cue.Concrete(true)); err != nil {
+		return rawArtifact, errors.Wrap(err, "failed to evaluate the template")
+	}
+	iter, err := objects.List()
+	if err != nil {
+		return rawArtifact, errors.Wrapf(err, "the top-level %s field of the template must be a list", cueObjectsField)
+	}
+	out, err := cueyaml.EncodeStream(iter)
+	if err != nil {
+		return rawArtifact, errors.Wrap(err, "failed to encode the objects of the template to yaml")
+	}
+	return out, nil
+}
+
+func compileCUETemplate(rawArtifact []byte) (cue.Value, error) {
+	// NOTE: a new context is created for every template because contexts are not safe for concurrent use.
+	v := cuecontext.New().CompileBytes(rawArtifact, cue.Filename("template.cue"))
+	if err := v.Err(); err != nil {
+		return cue.Value{}, errors.Wrap(err, "failed to compile the template")
+	}
+	return v, nil
+}
+
+// cueVariableField is a field of a CUE template which declares a variable.
+type cueVariableField struct {
+	name         string
+	path         cue.Path
+	valueType    string
+	defaultValue *string
+}
+
+// parse converts the value of a variable to the type declared in the tag attribute.
+func (f cueVariableField) parse(value string) (interface{}, error) {
+	var typedValue interface{}
+	var err error
+	switch f.valueType {
+	case "", "string":
+		return value, nil
+	case "int":
+		typedValue, err = strconv.ParseInt(value, 10, 64)
+	case "number":
+		typedValue, err = strconv.ParseFloat(value, 64)
+	case "bool":
+		typedValue, err = strconv.ParseBool(value)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid value for variable %s of type %s", f.name, f.valueType)
+	}
+	return typedValue, nil
+}
+
+// inspectCUEVariables recursively walks down the value and returns the fields
+// with a tag attribute, which are the template variables.
+func inspectCUEVariables(v cue.Value) ([]cueVariableField, error) {
+	var fields []cueVariableField
+	var walk func(v cue.Value) error
+	walk = func(v cue.Value) error {
+		switch v.IncompleteKind() {
+		case cue.StructKind:
+			iter, err := v.Fields(cue.All())
+			if err != nil {
+				return errors.Wrapf(err, "failed to inspect %s", v.Path())
+			}
+			for iter.Next() {
+				field, ok, err := cueVariableFieldFor(iter.Value())
+				if err != nil {
+					return err
+				}
+				if ok {
+					fields = append(fields, field)
+					continue
+				}
+				if err := walk(iter.Value()); err != nil {
+					return err
+				}
+			}
+		case cue.ListKind:
+			iter, err := v.List()
+			if err != nil {
+				// NOTE: open lists without elements cannot be iterated.
+				return nil //nolint:nilerr
+			}
+			for iter.Next() {
+				if err := walk(iter.Value()); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := walk(v); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// cueVariableFieldFor returns the variable declared by a field using a tag
+// attribute, if any.
+func cueVariableFieldFor(v cue.Value) (cueVariableField, bool, error) {
+	attr := v.Attribute(cueTagAttribute)
+	if attr.Err() != nil {
+		return cueVariableField{}, false, nil
+	}
+	name, err := attr.String(0)
+	if err != nil || name == "" {
+		return cueVariableField{}, false, errors.Errorf("invalid tag attribute for %s: the variable name must be specified", v.Path())
+	}
+	valueType, _, err := attr.Lookup(1, "type")
+	if err != nil {
+		return cueVariableField{}, false, errors.Wrapf(err, "invalid tag attribute for %s", v.Path())
+	}
+	switch valueType {
+	case "", "string", "int", "number", "bool":
+	default:
+		return cueVariableField{}, false, errors.Errorf("invalid tag attribute for %s: type must be one of string, int, number or bool, got %q", v.Path(), valueType)
+	}
+
+	field := cueVariableField{
+		name:      name,
+		path:      v.Path(),
+		valueType: valueType,
+	}
+	if d, ok := v.Default(); ok && d.IsConcrete() {
+		field.defaultValue = cueValueString(d)
+	} else if v.IsConcrete() {
+		field.defaultValue = cueValueString(v)
+	}
+	return field, true, nil
+}
+
+func cueValueString(v cue.Value) *string {
+	if s, err := v.String(); err == nil {
+		return &s
+	}
+	s := fmt.Sprint(v)
+	return &s
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package yamlprocessor
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func TestCUEProcessor_GetTemplateName(t *testing.T) {
+	g := NewWithT(t)
+	p := NewCUEProcessor()
+	g.Expect(p.GetTemplateName("some-version", "some-flavor")).To(Equal("cluster-template-some-flavor.cue"))
+	g.Expect(p.GetTemplateName("", "")).To(Equal("cluster-template.cue"))
+	g.Expect(p.GetClusterClassTemplateName("some-version", "some-class")).To(Equal("clusterclass-some-class.cue"))
+}
+
+func TestCUEProcessor_GetVariableMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]*string
+		wantErr bool
+	}{
+		{
+			name: "variables are fields with tag attributes",
+			data: "clusterName: string @tag(CLUSTER_NAME)\n" +
+				"objects: [{metadata: name: clusterName}]",
+			want: map[string]*string{"CLUSTER_NAME": nil},
+		},
+		{
+			name: "variables with default values",
+			data: "valueA: *\"a\" | string @tag(A)\n" +
+				"valueB: *3 | int @tag(B,type=int)\n" +
+				"objects: [{spec: {a: valueA, b: valueB}}]",
+			want: map[string]*string{"A": ptr.To("a"), "B": ptr.To("3")},
+		},
+		{
+			name: "variables used at least once without default values are required",
+			data: "valueA: *\"a\" | string @tag(A)\n" +
+				"#Pool: {size: string @tag(A)}\n" +
+				"objects: [{spec: {a: valueA, pool: #Pool}}]",
+			want: map[string]*string{"A": nil},
+		},
+		{
+			name:    "returns error for invalid templates",
+			data:    "objects: [",
+			wantErr: true,
+		},
+		{
+			name:    "returns error for invalid tag attributes",
+			data:    "a: string @tag(A,type=time)",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			p := NewCUEProcessor()
+
+			got, err := p.GetVariableMap([]byte(tt.data))
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+
+			variables, err := p.GetVariables([]byte(tt.data))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(variables).To(HaveLen(len(tt.want)))
+		})
+	}
+}
+
+func TestCUEProcessor_Process(t *testing.T) {
+	tests := []struct {
+		name                  string
+		data                  string
+		configVariablesClient config.VariablesClient
+		want                  string
+		wantErr               bool
+		missingVariables      []string
+	}{
+		{
+			name: "generates the objects filling variables",
+			data: "import \"strings\"\n" +
+				"vars: clusterName: string @tag(CLUSTER_NAME)\n" +
+				"objects: [{kind: \"Cluster\", metadata: name: vars.clusterName}, {kind: \"ConfigMap\", data: upper: strings.ToUpper(vars.clusterName)}]",
+			configVariablesClient: test.NewFakeVariableClient().WithVar("CLUSTER_NAME", "c1"),
+			want:                  "kind: Cluster\nmetadata:\n  name: c1\n---\nkind: ConfigMap\ndata:\n  upper: C1\n",
+		},
+		{
+			name: "uses default values if variables do not exist in variables client",
+			data: "valueA: *\"a\" | string @tag(A)\n" +
+				"valueB: *3 | int @tag(B,type=int)\n" +
+				"objects: [{a: valueA, b: valueB}]",
+			configVariablesClient: test.NewFakeVariableClient().WithVar("B", "5"),
+			want:                  "a: a\nb: 5\n",
+		},
+		{
+			name: "supports conditionals and comprehensions",
+			data: "ha: *false | bool @tag(HA,type=bool)\n" +
+				"pools: *1 | int @tag(POOLS,type=int)\n" +
+				"objects: [\n" +
+				"  if ha {replicas: 3},\n" +
+				"  if !ha {replicas: 1},\n" +
+				"  for i, _ in [0, 1, 2] if i < pools {pool: \"pool-\\(i)\"},\n" +
+				"]",
+			configVariablesClient: test.NewFakeVariableClient().WithVar("HA", "true").WithVar("POOLS", "2"),
+			want:                  "replicas: 3\n---\npool: pool-0\n---\npool: pool-1\n",
+		},
+		{
+			name: "returns error with missing template variables listed",
+			data: "valueBar: string @tag(BAR)\n" +
+				"valueBaz: string @tag(BAZ)\n" +
+				"valueCar: string @tag(CAR)\n" +
+				"valueDar: *\"dar\" | string @tag(DAR)\n" +
+				"objects: [{bar: valueBar, baz: valueBaz, car: valueCar, dar: valueDar}]",
+			configVariablesClient: test.NewFakeVariableClient().WithVar("CAR", "car"),
+			wantErr:               true,
+			missingVariables:      []string{"BAR", "BAZ"},
+		},
+		{
+			name:                  "returns error if a variable has an invalid value",
+			data:                  "valueB: int @tag(B,type=int)\nobjects: [{b: valueB}]",
+			configVariablesClient: test.NewFakeVariableClient().WithVar("B", "foo"),
+			wantErr:               true,
+		},
+		{
+			name:                  "returns error if the objects are not concrete",
+			data:                  "objects: [{a: string}]",
+			configVariablesClient: test.NewFakeVariableClient(),
+			wantErr:               true,
+		},
+		{
+			name:                  "returns error if the template does not define objects",
+			data:                  "a: \"a\"",
+			configVariablesClient: test.NewFakeVariableClient(),
+			wantErr:               true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			p := NewCUEProcessor()
+
+			got, err := p.Process([]byte(tt.data), tt.configVariablesClient.Get)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				if len(tt.missingVariables) != 0 {
+					e, ok := err.(*errMissingVariables)
+					g.Expect(ok).To(BeTrue())
+					g.Expect(e.Missing).To(ConsistOf(tt.missingVariables))
+				}
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(string(got)).To(Equal(tt.want))
+		})
+	}
+}
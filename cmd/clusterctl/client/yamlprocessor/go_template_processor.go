@@ -0,0 +1,244 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package yamlprocessor
+
+import (
+	"bytes"
+	"sort"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/pkg/errors"
+)
+
+// GoTemplateProcessor is a yaml processor that uses Go text/template, extended
+// with the sprig functions, to render templates. Variables are referenced as
+// fields of the root data, e.g. {{ .VAR }} or {{ $.VAR }}, and all the values
+// are strings. Variables are required unless a default value is provided using
+// the sprig default function, e.g. {{ .VAR | default "value" }} or
+// {{ default "value" .VAR }}.
+// See https://pkg.go.dev/text/template and https://masterminds.github.io/sprig/
+// for more details.
+type GoTemplateProcessor struct{}
+
+var _ Processor = &GoTemplateProcessor{}
+
+// NewGoTemplateProcessor returns a new Go template processor.
+func NewGoTemplateProcessor() *GoTemplateProcessor {
+	return &GoTemplateProcessor{}
+}
+
+// GetTemplateName returns the name of the template that the Go template processor
+// uses. It follows the same cluster template naming convention of the simple processor.
+func (tp *GoTemplateProcessor) GetTemplateName(version, flavor string) string {
+	return NewSimpleProcessor().GetTemplateName(version, flavor)
+}
+
+// GetClusterClassTemplateName returns the name of the cluster class template
+// that the Go template processor uses. It follows the same cluster class template
+// naming convention of the simple processor.
+func (tp *GoTemplateProcessor) GetClusterClassTemplateName(version, name string) string {
+	return NewSimpleProcessor().GetClusterClassTemplateName(version, name)
+}
+
+// GetVariables returns a list of the variables specified in the yaml.
+func (tp *GoTemplateProcessor) GetVariables(rawArtifact []byte) ([]string, error) {
+	variables, err := tp.GetVariableMap(rawArtifact)
+	if err != nil {
+		return nil, err
+	}
+	varNames := make([]string, 0, len(variables))
+	for k := range variables {
+		varNames = append(varNames, k)
+	}
+	sort.Strings(varNames)
+	return varNames, nil
+}
+
+// GetVariableMap returns a map of the variables specified in the yaml.
+func (tp *GoTemplateProcessor) GetVariableMap(rawArtifact []byte) (map[string]*string, error) {
+	t, err := parseGoTemplate(rawArtifact)
+	if err != nil {
+		return nil, err
+	}
+	variables := map[string]*string{}
+	inspectGoTemplateVariables(t.Root, true, variables)
+	return variables, nil
+}
+
+// Process returns the final yaml rendered by executing the template with the
+// values of the variables. If there are variables without corresponding values,
+// it will return the raw yaml along with an error.
+func (tp *GoTemplateProcessor) Process(rawArtifact []byte, variablesClient func(string) (string, error)) ([]byte, error) {
+	t, err := parseGoTemplate(rawArtifact)
+	if err != nil {
+		return rawArtifact, err
+	}
+
+	variables := map[string]*string{}
+	inspectGoTemplateVariables(t.Root, true, variables)
+
+	var missingVariables []string
+	data := make(map[string]string, len(variables))
+	for name, defaultValue := range variables {
+		value, err := variablesClient(name)
+		if err != nil {
+			// add to missingVariables list if the variable does not exist in the
+			// variablesClient AND it does not have a default value
+			if defaultValue == nil {
+				missingVariables = append(missingVariables, name)
+				continue
+			}
+			// NOTE: the sprig default function treats empty values as not set.
+			value = ""
+		}
+		data[name] = value
+	}
+
+	if len(missingVariables) > 0 {
+		return rawArtifact, &errMissingVariables{missingVariables}
+	}
+
+	var out bytes.Buffer
+	if err := t.Execute(&out, data); err != nil {
+		return rawArtifact, errors.Wrap(err, "failed to execute the template")
+	}
+	return out.Bytes(), nil
+}
+
+func parseGoTemplate(rawArtifact []byte) (*template.Template, error) {
+	t, err := template.New("template").Option("missingkey=error").Funcs(sprig.HermeticTxtFuncMap()).Parse(string(rawArtifact))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse the template")
+	}
+	return t, nil
+}
+
+// inspectGoTemplateVariables recursively walks down the node and tracks the fields of the
+// root data, which are the template variables, and their default values, if any.
+// dotIsRoot is false inside range and with blocks, where fields refer to the value of the pipeline.
+func inspectGoTemplateVariables(node parse.Node, dotIsRoot bool, variables map[string]*string) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, ln := range n.Nodes {
+			inspectGoTemplateVariables(ln, dotIsRoot, variables)
+		}
+	case *parse.ActionNode:
+		inspectGoTemplateVariables(n.Pipe, dotIsRoot, variables)
+	case *parse.IfNode:
+		inspectGoTemplateVariables(n.Pipe, dotIsRoot, variables)
+		inspectGoTemplateVariables(n.List, dotIsRoot, variables)
+		inspectGoTemplateVariables(n.ElseList, dotIsRoot, variables)
+	case *parse.RangeNode:
+		inspectGoTemplateVariables(n.Pipe, dotIsRoot, variables)
+		inspectGoTemplateVariables(n.List, false, variables)
+		inspectGoTemplateVariables(n.ElseList, dotIsRoot, variables)
+	case *parse.WithNode:
+		inspectGoTemplateVariables(n.Pipe, dotIsRoot, variables)
+		inspectGoTemplateVariables(n.List, false, variables)
+		inspectGoTemplateVariables(n.ElseList, dotIsRoot, variables)
+	case *parse.TemplateNode:
+		inspectGoTemplateVariables(n.Pipe, dotIsRoot, variables)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for i := 0; i < len(n.Cmds); i++ {
+			// Detect default values, e.g. {{ .VAR | default "value" }}.
+			if i+1 < len(n.Cmds) {
+				if name, ok := rootField(n.Cmds[i], dotIsRoot); ok {
+					if defaultValue, ok := defaultFuncValue(n.Cmds[i+1].Args, 2); ok {
+						addGoTemplateVariable(variables, name, &defaultValue)
+						i++
+						continue
+					}
+				}
+			}
+			inspectGoTemplateVariables(n.Cmds[i], dotIsRoot, variables)
+		}
+	case *parse.CommandNode:
+		for i, arg := range n.Args {
+			name, ok := rootFieldName(arg, dotIsRoot)
+			if !ok {
+				inspectGoTemplateVariables(arg, dotIsRoot, variables)
+				continue
+			}
+			// Detect default values, e.g. {{ default "value" .VAR }}.
+			if defaultValue, ok := defaultFuncValue(n.Args[:i], 2); ok {
+				addGoTemplateVariable(variables, name, &defaultValue)
+				continue
+			}
+			addGoTemplateVariable(variables, name, nil)
+		}
+	case *parse.ChainNode:
+		inspectGoTemplateVariables(n.Node, dotIsRoot, variables)
+	}
+}
+
+// addGoTemplateVariable tracks a variable and its default value; a variable is required
+// (nil default value) if it is used at least once without a default value.
+func addGoTemplateVariable(variables map[string]*string, name string, defaultValue *string) {
+	if currentDefault, ok := variables[name]; ok && (currentDefault == nil || defaultValue != nil) {
+		return
+	}
+	variables[name] = defaultValue
+}
+
+// rootField returns the name of the variable if a command is made only by a reference to a root field.
+func rootField(cmd *parse.CommandNode, dotIsRoot bool) (string, bool) {
+	if len(cmd.Args) != 1 {
+		return "", false
+	}
+	return rootFieldName(cmd.Args[0], dotIsRoot)
+}
+
+// rootFieldName returns the name of the variable if the node is a reference to a root field,
+// e.g. .VAR (only where dot is the root data) or $.VAR.
+func rootFieldName(node parse.Node, dotIsRoot bool) (string, bool) {
+	switch n := node.(type) {
+	case *parse.FieldNode:
+		if dotIsRoot {
+			return n.Ident[0], true
+		}
+	case *parse.VariableNode:
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			return n.Ident[1], true
+		}
+	}
+	return "", false
+}
+
+// defaultFuncValue returns the default value if args are a call to the sprig default function
+// with a string literal as the default value, e.g. default "value".
+func defaultFuncValue(args []parse.Node, expectedArgs int) (string, bool) {
+	if len(args) != expectedArgs {
+		return "", false
+	}
+	identifier, ok := args[0].(*parse.IdentifierNode)
+	if !ok || identifier.Ident != "default" {
+		return "", false
+	}
+	value, ok := args[1].(*parse.StringNode)
+	if !ok {
+		return "", false
+	}
+	return value.Text, true
+}
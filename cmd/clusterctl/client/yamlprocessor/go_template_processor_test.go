@@ -0,0 +1,182 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package yamlprocessor
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func TestGoTemplateProcessor_GetTemplateName(t *testing.T) {
+	g := NewWithT(t)
+	p := NewGoTemplateProcessor()
+	g.Expect(p.GetTemplateName("some-version", "some-flavor")).To(Equal("cluster-template-some-flavor.yaml"))
+	g.Expect(p.GetTemplateName("", "")).To(Equal("cluster-template.yaml"))
+	g.Expect(p.GetClusterClassTemplateName("some-version", "some-class")).To(Equal("clusterclass-some-class.yaml"))
+}
+
+func TestGoTemplateProcessor_GetVariableMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]*string
+		wantErr bool
+	}{
+		{
+			name: "variables are fields of the root data",
+			data: "yaml with {{ .A }} {{ $.B }} {{ .C.foo }}",
+			want: map[string]*string{"A": nil, "B": nil, "C": nil},
+		},
+		{
+			name: "variables with default values",
+			data: "yaml with {{ .A | default \"a\" }} {{ default \"b\" .B }} {{ .C | default \"c\" | upper }}",
+			want: map[string]*string{"A": ptr.To("a"), "B": ptr.To("b"), "C": ptr.To("c")},
+		},
+		{
+			name: "variables used at least once without default values are required",
+			data: "yaml with {{ .A | default \"a\" }} {{ .A }} {{ .B }} {{ .B | default \"b\" }}",
+			want: map[string]*string{"A": nil, "B": nil},
+		},
+		{
+			name: "variables in conditionals and loops",
+			data: "{{ if eq .A \"true\" }}{{ .B }}{{ else }}{{ .C }}{{ end }}{{ range $i := until (atoi .D) }}{{ $.E }}{{ end }}",
+			want: map[string]*string{"A": nil, "B": nil, "C": nil, "D": nil, "E": nil},
+		},
+		{
+			name: "fields inside range and with blocks are not variables",
+			data: "{{ range (list .A) }}{{ .foo }}{{ end }}{{ with .B }}{{ .bar }}{{ $.C }}{{ else }}{{ .D }}{{ end }}",
+			want: map[string]*string{"A": nil, "B": nil, "C": nil, "D": nil},
+		},
+		{
+			name:    "returns error for invalid templates",
+			data:    "yaml with {{ .A ",
+			wantErr: true,
+		},
+		{
+			name:    "returns error for templates reading the environment",
+			data:    "token: {{ env \"GITHUB_TOKEN\" }}",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			p := NewGoTemplateProcessor()
+
+			got, err := p.GetVariableMap([]byte(tt.data))
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+
+			variables, err := p.GetVariables([]byte(tt.data))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(variables).To(HaveLen(len(tt.want)))
+		})
+	}
+}
+
+func TestGoTemplateProcessor_Process(t *testing.T) {
+	tests := []struct {
+		name                  string
+		yaml                  string
+		configVariablesClient config.VariablesClient
+		want                  string
+		wantErr               bool
+		missingVariables      []string
+	}{
+		{
+			name:                  "replaces variables",
+			yaml:                  "foo {{ .BAR }}, {{ $.BAR | upper }}",
+			configVariablesClient: test.NewFakeVariableClient().WithVar("BAR", "bar"),
+			want:                  "foo bar, BAR",
+		},
+		{
+			name:                  "uses default values if variables do not exist in variables client",
+			yaml:                  "foo {{ .BAR | default \"default_bar\" }} {{ default \"default_baz\" .BAZ }}",
+			configVariablesClient: test.NewFakeVariableClient().WithVar("BAR", "bar"),
+			want:                  "foo bar default_baz",
+		},
+		{
+			name: "supports conditionals and loops",
+			yaml: "{{ if eq .HA \"true\" }}replicas: 3{{ else }}replicas: 1{{ end }}\n" +
+				"{{- range $i := until (atoi .POOLS) }}\n- pool-{{ $i }}-{{ $.CLUSTER_NAME }}{{ end }}",
+			configVariablesClient: test.NewFakeVariableClient().
+				WithVar("HA", "true").WithVar("POOLS", "2").WithVar("CLUSTER_NAME", "c1"),
+			want: "replicas: 3\n- pool-0-c1\n- pool-1-c1",
+		},
+		{
+			name:                  "returns error with missing template variables listed",
+			yaml:                  "foo {{ .BAR }} {{ .BAZ }} {{ .CAR }} {{ .DAR | default \"dar\" }}",
+			configVariablesClient: test.NewFakeVariableClient().WithVar("CAR", "car"),
+			wantErr:               true,
+			missingVariables:      []string{"BAR", "BAZ"},
+		},
+		{
+			name:                  "returns error if the template fails to execute",
+			yaml:                  "foo {{ fail \"invalid\" }}",
+			configVariablesClient: test.NewFakeVariableClient(),
+			wantErr:               true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			p := NewGoTemplateProcessor()
+
+			got, err := p.Process([]byte(tt.yaml), tt.configVariablesClient.Get)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(string(got)).To(Equal(tt.yaml))
+				if len(tt.missingVariables) != 0 {
+					e, ok := err.(*errMissingVariables)
+					g.Expect(ok).To(BeTrue())
+					g.Expect(e.Missing).To(ConsistOf(tt.missingVariables))
+				}
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(string(got)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestNewProcessor(t *testing.T) {
+	g := NewWithT(t)
+
+	p, err := NewProcessor(SimpleProcessorName)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(p).To(BeAssignableToTypeOf(&SimpleProcessor{}))
+
+	p, err = NewProcessor(GoTemplateProcessorName)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(p).To(BeAssignableToTypeOf(&GoTemplateProcessor{}))
+
+	p, err = NewProcessor(CUEProcessorName)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(p).To(BeAssignableToTypeOf(&CUEProcessor{}))
+
+	_, err = NewProcessor("foo")
+	g.Expect(err).To(HaveOccurred())
+}
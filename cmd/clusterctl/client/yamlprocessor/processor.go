@@ -17,6 +17,12 @@ limitations under the License.
 // Package yamlprocessor implements YAML processing.
 package yamlprocessor
 
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
 // Processor defines the methods necessary for creating a specific yaml
 // processor.
 type Processor interface {
@@ -40,3 +46,31 @@ type Processor interface {
 	// yaml with values retrieved from the values getter
 	Process([]byte, func(string) (string, error)) ([]byte, error)
 }
+
+const (
+	// SimpleProcessorName is the name of the SimpleProcessor.
+	SimpleProcessorName = "simple"
+
+	// GoTemplateProcessorName is the name of the GoTemplateProcessor.
+	GoTemplateProcessorName = "go-template"
+
+	// CUEProcessorName is the name of the CUEProcessor.
+	CUEProcessorName = "cue"
+)
+
+// ProcessorNames is the list of the names of the built-in yaml processors.
+var ProcessorNames = []string{SimpleProcessorName, GoTemplateProcessorName, CUEProcessorName}
+
+// NewProcessor returns the built-in yaml processor with the given name.
+func NewProcessor(name string) (Processor, error) {
+	switch name {
+	case SimpleProcessorName:
+		return NewSimpleProcessor(), nil
+	case GoTemplateProcessorName:
+		return NewGoTemplateProcessor(), nil
+	case CUEProcessorName:
+		return NewCUEProcessor(), nil
+	default:
+		return nil, errors.Errorf("invalid template processor %q. Allowed values are [%s]", name, strings.Join(ProcessorNames, ", "))
+	}
+}
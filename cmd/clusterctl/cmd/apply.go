@@ -0,0 +1,202 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/cmd/internal/templates"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/scheme"
+)
+
+type applyOptions struct {
+	kubeconfig          string
+	kubeconfigContext   string
+	file                string
+	dryRun              bool
+	validate            bool
+	waitProviders       bool
+	waitProviderTimeout int
+}
+
+var applyOpts = &applyOptions{}
+
+var applyCmd = &cobra.Command{
+	Use:     "apply",
+	GroupID: groupManagement,
+	Short:   "Apply a management cluster configuration",
+	Long: templates.LongDesc(`
+		Apply a management cluster configuration.
+
+		Reconciles the management cluster with a ManagementCluster configuration file defining providers, versions,
+		target namespaces, variables, image overrides and the cert-manager version: missing providers are installed,
+		providers behind the desired version are upgraded, and any other difference, e.g. providers not defined in the
+		configuration file or installed with a newer version, is reported as drift.
+
+		The command is idempotent, and it can be executed on an empty management cluster as well.`),
+
+	Example: templates.Examples(`
+		# Apply a management cluster configuration.
+		clusterctl apply -f management-cluster.yaml
+
+		# Show the changes required to apply a management cluster configuration, without applying them.
+		clusterctl apply -f management-cluster.yaml --dry-run`),
+	Args: cobra.NoArgs,
+	RunE: func(*cobra.Command, []string) error {
+		return runApply()
+	},
+}
+
+func init() {
+	applyCmd.Flags().StringVar(&applyOpts.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig for the management cluster. If unspecified, default discovery rules apply.")
+	applyCmd.Flags().StringVar(&applyOpts.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	applyCmd.Flags().StringVarP(&applyOpts.file, "file", "f", "",
+		"Path to the ManagementCluster configuration file.")
+	applyCmd.Flags().BoolVar(&applyOpts.dryRun, "dry-run", false,
+		"If true, clusterctl will only show the changes required to apply the configuration, without applying them.")
+	applyCmd.Flags().BoolVar(&applyOpts.waitProviders, "wait-providers", false,
+		"Wait for providers to be installed or upgraded.")
+	applyCmd.Flags().IntVar(&applyOpts.waitProviderTimeout, "wait-provider-timeout", 5*60,
+		"Wait timeout per provider installation or upgrade in seconds. This value is ignored if --wait-providers is false")
+	applyCmd.Flags().BoolVar(&applyOpts.validate, "validate", true,
+		"If true, clusterctl will validate that the deployments will succeed on the management cluster.")
+
+	_ = applyCmd.MarkFlagRequired("file")
+
+	RootCmd.AddCommand(applyCmd)
+}
+
+func runApply() error {
+	ctx := context.Background()
+
+	managementCluster, err := readManagementCluster(applyOpts.file)
+	if err != nil {
+		return err
+	}
+
+	// Layer the variables, the image overrides and the cert-manager version defined in
+	// the ManagementCluster on top of the clusterctl configuration.
+	configClient, err := config.New(ctx, cfgFile, config.WithManagementCluster(managementCluster))
+	if err != nil {
+		return err
+	}
+
+	c, err := client.New(ctx, cfgFile, client.InjectConfig(configClient))
+	if err != nil {
+		return err
+	}
+
+	plan, err := c.ApplyManagementCluster(ctx, client.ApplyManagementClusterOptions{
+		Kubeconfig:             client.Kubeconfig{Path: applyOpts.kubeconfig, Context: applyOpts.kubeconfigContext},
+		ManagementCluster:      managementCluster,
+		DryRun:                 applyOpts.dryRun,
+		WaitProviders:          applyOpts.waitProviders,
+		WaitProviderTimeout:    time.Duration(applyOpts.waitProviderTimeout) * time.Second,
+		IgnoreValidationErrors: !applyOpts.validate,
+	})
+	if err != nil {
+		return err
+	}
+
+	return printManagementClusterPlan(os.Stdout, plan, applyOpts.dryRun)
+}
+
+// readManagementCluster reads a ManagementCluster from a file.
+func readManagementCluster(path string) (*clusterctlv1.ManagementCluster, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // The path is provided by the user.
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %q", path)
+	}
+
+	managementCluster := &clusterctlv1.ManagementCluster{}
+	codecFactory := serializer.NewCodecFactory(scheme.Scheme, serializer.EnableStrict)
+	if err := runtime.DecodeInto(codecFactory.UniversalDecoder(), data, managementCluster); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode %q, it must be a %s ManagementCluster", path, clusterctlv1.GroupVersion.String())
+	}
+	return managementCluster, nil
+}
+
+// printManagementClusterPlan prints the actions required to reconcile the management cluster, and the drift, if any.
+func printManagementClusterPlan(out io.Writer, plan *client.ManagementClusterPlan, dryRun bool) error {
+	if !plan.CertManager.ExternallyManaged {
+		if plan.CertManager.ShouldUpgrade {
+			fmt.Fprintf(out, "Cert-Manager upgrade from %q to %q\n\n", plan.CertManager.From, plan.CertManager.To)
+		} else {
+			fmt.Fprintf(out, "Cert-Manager is already up to date\n\n")
+		}
+	}
+
+	w := tabwriter.NewWriter(out, 10, 4, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tNAMESPACE\tTYPE\tCURRENT VERSION\tDESIRED VERSION\tACTION")
+	for _, p := range plan.Providers {
+		desiredVersion := p.DesiredVersion
+		if desiredVersion == "" {
+			desiredVersion = "-"
+			if p.Action == client.ManagementClusterProviderInstall {
+				desiredVersion = "latest"
+			}
+		}
+		currentVersion := p.CurrentVersion
+		if currentVersion == "" {
+			currentVersion = "-"
+		}
+		namespace := p.Namespace
+		if namespace == "" {
+			namespace = "(default)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", p.ManifestLabel(), namespace, p.Type, currentVersion, desiredVersion, p.Action)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "")
+
+	if plan.HasDrift() {
+		fmt.Fprintln(out, "Drift detected, it must be reconciled manually:")
+		for _, p := range plan.Providers {
+			if p.Action == client.ManagementClusterProviderDrift {
+				fmt.Fprintf(out, "  - %s: %s\n", p.ManifestLabel(), p.Message)
+			}
+		}
+		fmt.Fprintln(out, "")
+	}
+
+	switch {
+	case !plan.HasChanges():
+		fmt.Fprintln(out, "The management cluster is already up to date!")
+	case dryRun:
+		fmt.Fprintln(out, "You can now apply the changes by executing the same command without --dry-run.")
+	default:
+		fmt.Fprintln(out, "The management cluster configuration has been applied successfully!")
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+func Test_readManagementCluster(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    clusterctlv1.ManagementClusterSpec
+		wantErr bool
+	}{
+		{
+			name: "reads a ManagementCluster",
+			data: `apiVersion: clusterctl.cluster.x-k8s.io/v1alpha3
+kind: ManagementCluster
+spec:
+  certManager:
+    version: v1.16.0
+  core:
+    name: cluster-api
+    version: v1.9.0
+  infrastructure:
+  - name: aws
+    version: v2.7.0
+    targetNamespace: capa-system
+  variables:
+    AWS_REGION: eu-west-1
+  images:
+    all:
+      repository: myorg.io/local-repo
+`,
+			want: clusterctlv1.ManagementClusterSpec{
+				CertManager:             &clusterctlv1.ManagementClusterCertManager{Version: "v1.16.0"},
+				CoreProvider:            &clusterctlv1.ManagementClusterProvider{Name: "cluster-api", Version: "v1.9.0"},
+				InfrastructureProviders: []clusterctlv1.ManagementClusterProvider{{Name: "aws", Version: "v2.7.0", TargetNamespace: "capa-system"}},
+				Variables:               map[string]string{"AWS_REGION": "eu-west-1"},
+				Images:                  map[string]clusterctlv1.ManagementClusterImage{"all": {Repository: "myorg.io/local-repo"}},
+			},
+		},
+		{
+			name: "fails for unknown fields",
+			data: `apiVersion: clusterctl.cluster.x-k8s.io/v1alpha3
+kind: ManagementCluster
+spec:
+  infrastructre:
+  - name: aws
+`,
+			wantErr: true,
+		},
+		{
+			name: "fails for other kinds",
+			data: `apiVersion: clusterctl.cluster.x-k8s.io/v1alpha3
+kind: Metadata
+`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			path := filepath.Join(t.TempDir(), "management-cluster.yaml")
+			g.Expect(os.WriteFile(path, []byte(tt.data), 0600)).To(Succeed())
+
+			got, err := readManagementCluster(path)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.Spec).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_printManagementClusterPlan(t *testing.T) {
+	g := NewWithT(t)
+
+	plan := &client.ManagementClusterPlan{
+		CertManager: client.CertManagerUpgradePlan{From: "v1.15.0", To: "v1.16.0", ShouldUpgrade: true},
+		Providers: []client.ManagementClusterProviderPlan{
+			{Name: "cluster-api", Type: clusterctlv1.CoreProviderType, Namespace: "capi-system", CurrentVersion: "v1.8.0", DesiredVersion: "v1.9.0", Action: client.ManagementClusterProviderUpgrade},
+			{Name: "kubeadm", Type: clusterctlv1.BootstrapProviderType, Namespace: "capi-kubeadm-bootstrap-system", CurrentVersion: "v1.8.0", Action: client.ManagementClusterProviderDrift,
+				Message: "installed but not defined in the management cluster configuration"},
+			{Name: "aws", Type: clusterctlv1.InfrastructureProviderType, Action: client.ManagementClusterProviderInstall},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	g.Expect(printManagementClusterPlan(out, plan, true)).To(Succeed())
+	g.Expect(out.String()).To(Equal(`Cert-Manager upgrade from "v1.15.0" to "v1.16.0"
+
+NAME                 NAMESPACE                       TYPE                     CURRENT VERSION   DESIRED VERSION   ACTION
+cluster-api          capi-system                     CoreProvider             v1.8.0            v1.9.0            Upgrade
+bootstrap-kubeadm    capi-kubeadm-bootstrap-system   BootstrapProvider        v1.8.0            -                 Drift
+infrastructure-aws   (default)                       InfrastructureProvider   -                 latest            Install
+
+Drift detected, it must be reconciled manually:
+  - bootstrap-kubeadm: installed but not defined in the management cluster configuration
+
+You can now apply the changes by executing the same command without --dry-run.
+`))
+}
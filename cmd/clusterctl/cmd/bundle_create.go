@@ -0,0 +1,106 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/cmd/internal/templates"
+)
+
+type bundleCreateOptions struct {
+	coreProvider              string
+	bootstrapProviders        []string
+	controlPlaneProviders     []string
+	infrastructureProviders   []string
+	ipamProviders             []string
+	runtimeExtensionProviders []string
+	addonProviders            []string
+	flavors                   []string
+	output                    string
+}
+
+var bundleCreateOpts = &bundleCreateOptions{}
+
+var bundleCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a bundle with the providers required for initializing a management cluster",
+	Long: templates.LongDesc(`
+		Create a bundle with the providers required for initializing a management cluster.
+
+		The bundle is a tarball containing the components, metadata and cluster templates of the selected
+		providers and the cert-manager manifest, laid out as a local repository, plus the list of container
+		images required for initializing the management cluster.
+
+		The bundle can be copied to a disconnected environment and used with 'clusterctl bundle use'.`),
+
+	Example: templates.Examples(`
+		# Create a bundle with Cluster API, the kubeadm providers and the given infrastructure provider.
+		clusterctl bundle create --infrastructure vsphere --output bundle.tar.gz
+
+		# Create a bundle with specific provider versions and additional cluster template flavors.
+		clusterctl bundle create --core cluster-api:v1.9.0 --infrastructure vsphere:v1.12.0 --flavor topology --output bundle.tar.gz`),
+	Args: cobra.NoArgs,
+	RunE: func(*cobra.Command, []string) error {
+		return runBundleCreate()
+	},
+}
+
+func init() {
+	bundleCreateCmd.Flags().StringVar(&bundleCreateOpts.coreProvider, "core", "",
+		"Core provider version (e.g. cluster-api:v1.1.5) to add to the bundle. If unspecified, Cluster API's latest release is used.")
+	bundleCreateCmd.Flags().StringSliceVarP(&bundleCreateOpts.infrastructureProviders, "infrastructure", "i", nil,
+		"Infrastructure providers and versions (e.g. aws:v0.5.0) to add to the bundle.")
+	bundleCreateCmd.Flags().StringSliceVarP(&bundleCreateOpts.bootstrapProviders, "bootstrap", "b", nil,
+		"Bootstrap providers and versions (e.g. kubeadm:v1.1.5) to add to the bundle. If unspecified, Kubeadm bootstrap provider's latest release is used.")
+	bundleCreateCmd.Flags().StringSliceVarP(&bundleCreateOpts.controlPlaneProviders, "control-plane", "c", nil,
+		"Control plane providers and versions (e.g. kubeadm:v1.1.5) to add to the bundle. If unspecified, the Kubeadm control plane provider's latest release is used.")
+	bundleCreateCmd.Flags().StringSliceVar(&bundleCreateOpts.ipamProviders, "ipam", nil,
+		"IPAM providers and versions (e.g. in-cluster:v0.1.0) to add to the bundle.")
+	bundleCreateCmd.Flags().StringSliceVar(&bundleCreateOpts.runtimeExtensionProviders, "runtime-extension", nil,
+		"Runtime extension providers and versions to add to the bundle.")
+	bundleCreateCmd.Flags().StringSliceVar(&bundleCreateOpts.addonProviders, "addon", nil,
+		"Add-on providers and versions (e.g. helm:v0.1.0) to add to the bundle.")
+	bundleCreateCmd.Flags().StringSliceVarP(&bundleCreateOpts.flavors, "flavor", "f", nil,
+		"Additional cluster template flavors to add to the bundle for the infrastructure providers. The default cluster template is always added, if it exists.")
+	bundleCreateCmd.Flags().StringVarP(&bundleCreateOpts.output, "output", "o", "clusterctl-bundle.tar.gz",
+		"Path of the bundle tarball to be created.")
+}
+
+func runBundleCreate() error {
+	ctx := context.Background()
+
+	c, err := client.New(ctx, cfgFile)
+	if err != nil {
+		return err
+	}
+
+	return c.CreateBundle(ctx, client.CreateBundleOptions{
+		CoreProvider:              bundleCreateOpts.coreProvider,
+		BootstrapProviders:        bundleCreateOpts.bootstrapProviders,
+		ControlPlaneProviders:     bundleCreateOpts.controlPlaneProviders,
+		InfrastructureProviders:   bundleCreateOpts.infrastructureProviders,
+		IPAMProviders:             bundleCreateOpts.ipamProviders,
+		RuntimeExtensionProviders: bundleCreateOpts.runtimeExtensionProviders,
+		AddonProviders:            bundleCreateOpts.addonProviders,
+		Flavors:                   bundleCreateOpts.flavors,
+		OutputFile:                bundleCreateOpts.output,
+	})
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/cmd/internal/templates"
+)
+
+type bundleUseOptions struct {
+	directory string
+}
+
+var bundleUseOpts = &bundleUseOptions{}
+
+var bundleUseCmd = &cobra.Command{
+	Use:   "use BUNDLE",
+	Short: "Extract a bundle and generate a clusterctl configuration file pointing to it",
+	Long: templates.LongDesc(`
+		Extract a bundle created with 'clusterctl bundle create' and generate a clusterctl configuration
+		file that points the providers and cert-manager to the local repository in the bundle.
+
+		The generated configuration file can then be used with 'clusterctl init --config'; the list of
+		container images required for initializing the management cluster is stored in images.txt.`),
+
+	Example: templates.Examples(`
+		# Extract a bundle and initialize a management cluster using it.
+		clusterctl bundle use bundle.tar.gz --directory /opt/clusterctl-bundle
+		clusterctl init --config /opt/clusterctl-bundle/clusterctl.yaml --infrastructure vsphere`),
+	Args: cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runBundleUse(args[0])
+	},
+}
+
+func init() {
+	bundleUseCmd.Flags().StringVarP(&bundleUseOpts.directory, "directory", "d", "clusterctl-bundle",
+		"The directory where the bundle should be extracted.")
+}
+
+func runBundleUse(bundleFile string) error {
+	ctx := context.Background()
+
+	c, err := client.New(ctx, cfgFile)
+	if err != nil {
+		return err
+	}
+
+	configPath, err := c.UseBundle(ctx, client.UseBundleOptions{
+		BundleFile: bundleFile,
+		Directory:  bundleUseOpts.directory,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Bundle extracted to %q.\n\n", bundleUseOpts.directory)
+	fmt.Printf("You can now initialize a management cluster using the bundle by running:\n\n")
+	fmt.Printf("  clusterctl init --config %s\n", configPath)
+	return nil
+}
@@ -19,8 +19,10 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -59,6 +61,9 @@ var (
 	yellow = color.New(color.FgYellow)
 	white  = color.New(color.FgWhite)
 	cyan   = color.New(color.FgCyan)
+
+	// highlight is used for conditions changed since the previous update when watching a cluster.
+	highlight = color.New(color.ReverseVideo)
 )
 
 type describeClusterOptions struct {
@@ -74,6 +79,8 @@ type describeClusterOptions struct {
 	disableGrouping         bool
 	v1beta2                 bool
 	color                   bool
+	output                  string
+	watch                   bool
 }
 
 var dc = &describeClusterOptions{}
@@ -102,7 +109,16 @@ var describeClusterClusterCmd = &cobra.Command{
 
 		# Describe the cluster named test-1 showing the MachineInfrastructure and BootstrapConfig objects
 		# also when their status is the same as the status of the corresponding machine object.
-		clusterctl describe cluster test-1 --echo`),
+		clusterctl describe cluster test-1 --echo
+
+		# Describe the cluster named test-1 in json format, e.g. to be consumed by scripts.
+		clusterctl describe cluster test-1 -o json
+
+		# Describe the cluster named test-1 as a Graphviz graph, and render it as an image.
+		clusterctl describe cluster test-1 -o dot | dot -Tpng > test-1.png
+
+		# Watch the cluster named test-1, updating the output every time any of its objects changes.
+		clusterctl describe cluster test-1 --watch`),
 
 	Args: func(_ *cobra.Command, args []string) error {
 		if len(args) != 1 {
@@ -142,6 +158,10 @@ func init() {
 		"use --grouping instead.")
 	describeClusterClusterCmd.Flags().BoolVar(&dc.v1beta2, "v1beta2", false,
 		"Use V1Beta2 conditions..")
+	describeClusterClusterCmd.Flags().StringVarP(&dc.output, "output", "o", "",
+		fmt.Sprintf("Output format; available options are %s. If empty, the cluster is described as a table.", strings.Join(describeClusterOutputs, ", ")))
+	describeClusterClusterCmd.Flags().BoolVarP(&dc.watch, "watch", "w", false,
+		"Watch the cluster, updating the output every time any of its objects changes and highlighting the conditions changed since the previous update.")
 	describeClusterClusterCmd.Flags().BoolVarP(&dc.color, "color", "c", false, "Enable or disable color output; if not set color is enabled by default only if using tty. The flag is overridden by the NO_COLOR env variable if set.")
 
 	// completions
@@ -153,18 +173,24 @@ func init() {
 		"cluster",
 	)
 
+	describeClusterClusterCmd.MarkFlagsMutuallyExclusive("watch", "output")
+
 	describeCmd.AddCommand(describeClusterClusterCmd)
 }
 
 func runDescribeCluster(cmd *cobra.Command, name string) error {
 	ctx := context.Background()
 
+	if dc.output != "" && !slices.Contains(describeClusterOutputs, dc.output) {
+		return errors.Errorf("invalid output format %q, valid values are %s", dc.output, strings.Join(describeClusterOutputs, ", "))
+	}
+
 	c, err := client.New(ctx, cfgFile)
 	if err != nil {
 		return err
 	}
 
-	tree, err := c.DescribeCluster(ctx, client.DescribeClusterOptions{
+	options := client.DescribeClusterOptions{
 		Kubeconfig:              client.Kubeconfig{Path: dc.kubeconfig, Context: dc.kubeconfigContext},
 		Namespace:               dc.namespace,
 		ClusterName:             name,
@@ -176,29 +202,43 @@ func runDescribeCluster(cmd *cobra.Command, name string) error {
 		Echo:                    dc.echo,
 		Grouping:                dc.grouping && !dc.disableGrouping,
 		V1Beta2:                 dc.v1beta2,
-	})
-	if err != nil {
-		return err
 	}
 
 	if cmd.Flags().Changed("color") {
 		color.NoColor = !dc.color
 	}
 
-	switch dc.v1beta2 {
-	case true:
-		printObjectTreeV1Beta2(tree)
-	default:
-		printObjectTree(tree)
+	if dc.watch {
+		return runDescribeClusterWatch(ctx, c, options)
+	}
+
+	tree, err := c.DescribeCluster(ctx, options)
+	if err != nil {
+		return err
+	}
+
+	if dc.output != "" {
+		return writeObjectTree(os.Stdout, tree, dc.output, dc.v1beta2)
 	}
 
+	printTree(os.Stdout, tree, dc.v1beta2)
 	return nil
 }
 
-// printObjectTreeV1Beta2 prints the cluster status to stdout.
-func printObjectTreeV1Beta2(tree *tree.ObjectTree) {
+// printTree prints the cluster status to w, using v1beta2 conditions if required.
+func printTree(w io.Writer, tree *tree.ObjectTree, v1beta2 bool) {
+	switch v1beta2 {
+	case true:
+		printObjectTreeV1Beta2(w, tree)
+	default:
+		printObjectTree(w, tree)
+	}
+}
+
+// printObjectTreeV1Beta2 prints the cluster status to w.
+func printObjectTreeV1Beta2(w io.Writer, tree *tree.ObjectTree) {
 	// Creates the output table
-	tbl := tablewriter.NewWriter(os.Stdout)
+	tbl := tablewriter.NewWriter(w)
 	tbl.SetHeader([]string{"NAME", "REPLICAS", "AVAILABLE", "READY", "UP TO DATE", "STATUS", "REASON", "SINCE", "MESSAGE"})
 
 	formatTableTreeV1Beta2(tbl)
@@ -209,10 +249,10 @@ func printObjectTreeV1Beta2(tree *tree.ObjectTree) {
 	tbl.Render()
 }
 
-// printObjectTree prints the cluster status to stdout.
-func printObjectTree(tree *tree.ObjectTree) {
+// printObjectTree prints the cluster status to w.
+func printObjectTree(w io.Writer, tree *tree.ObjectTree) {
 	// Creates the output table
-	tbl := tablewriter.NewWriter(os.Stdout)
+	tbl := tablewriter.NewWriter(w)
 	tbl.SetHeader([]string{"NAME", "READY", "SEVERITY", "REASON", "SINCE", "MESSAGE"})
 
 	formatTableTree(tbl)
@@ -274,6 +314,11 @@ func addObjectRowV1Beta2(prefix string, tbl *tablewriter.Table, objectTree *tree
 	// NOTE: The object name gets manipulated in order to improve readability.
 	name := getRowName(obj)
 
+	// If any condition changed since the previous update, highlight the condition picked in the rowDescriptor.
+	if len(tree.GetChangedConditions(obj)) > 0 && rowDescriptor.status != "" {
+		rowDescriptor.status = highlight.Sprint(rowDescriptor.status)
+	}
+
 	// If we are going to show all conditions from this object, let's drop the condition picked in the rowDescriptor.
 	if tree.IsShowConditionsObject(obj) {
 		rowDescriptor.status = ""
@@ -365,12 +410,18 @@ func addObjectRow(prefix string, tbl *tablewriter.Table, objectTree *tree.Object
 	// NOTE: The object name gets manipulated in order to improve readability.
 	name := getRowName(obj)
 
+	// If the ready condition changed since the previous update, highlight it.
+	status := readyDescriptor.readyColor.Sprint(readyDescriptor.status)
+	if tree.IsChangedCondition(obj, string(clusterv1.ReadyCondition)) {
+		status = highlight.Sprint(status)
+	}
+
 	// Add the row representing the object that includes
 	// - The row name with the tree view prefix.
 	// - The object's ready condition.
 	tbl.Append([]string{
 		fmt.Sprintf("%s%s", gray.Sprint(prefix), name),
-		readyDescriptor.readyColor.Sprint(readyDescriptor.status),
+		status,
 		readyDescriptor.readyColor.Sprint(readyDescriptor.severity),
 		readyDescriptor.readyColor.Sprint(readyDescriptor.reason),
 		readyDescriptor.age,
@@ -431,6 +482,10 @@ func addOtherConditionsV1Beta2(prefix string, tbl *tablewriter.Table, objectTree
 
 		childPrefix := getChildPrefix(prefix+childrenPipe+filler, i, len(conditions))
 		c, status, age, reason, message := v1Beta2ConditionInfo(condition, positivePolarity)
+		conditionType := cyan.Sprint(condition.Type)
+		if tree.IsChangedCondition(obj, condition.Type) {
+			conditionType = highlight.Sprint(conditionType)
+		}
 
 		// Add the row representing each condition.
 		// Note: if the condition has a multiline message, also add additional rows for each line.
@@ -440,7 +495,7 @@ func addOtherConditionsV1Beta2(prefix string, tbl *tablewriter.Table, objectTree
 			msg0 = msg[0]
 		}
 		tbl.Append([]string{
-			fmt.Sprintf("%s%s", gray.Sprint(childPrefix), cyan.Sprint(condition.Type)),
+			fmt.Sprintf("%s%s", gray.Sprint(childPrefix), conditionType),
 			"",
 			"",
 			"",
@@ -482,8 +537,12 @@ func addOtherConditions(prefix string, tbl *tablewriter.Table, objectTree *tree.
 		otherCondition := otherConditions[i]
 		otherDescriptor := newConditionDescriptor(otherCondition)
 		otherConditionPrefix := getChildPrefix(prefix+childrenPipe+filler, i, len(otherConditions))
+		conditionType := cyan.Sprint(otherCondition.Type)
+		if tree.IsChangedCondition(obj, string(otherCondition.Type)) {
+			conditionType = highlight.Sprint(conditionType)
+		}
 		tbl.Append([]string{
-			fmt.Sprintf("%s%s", gray.Sprint(otherConditionPrefix), cyan.Sprint(otherCondition.Type)),
+			fmt.Sprintf("%s%s", gray.Sprint(otherConditionPrefix), conditionType),
 			otherDescriptor.readyColor.Sprint(otherDescriptor.status),
 			otherDescriptor.readyColor.Sprint(otherDescriptor.severity),
 			otherDescriptor.readyColor.Sprint(otherDescriptor.reason),
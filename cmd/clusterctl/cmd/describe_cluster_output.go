@@ -0,0 +1,184 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gobuffalo/flect"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/tree"
+)
+
+const (
+	describeClusterOutputJSON = "json"
+	describeClusterOutputYAML = "yaml"
+	describeClusterOutputDOT  = "dot"
+)
+
+var describeClusterOutputs = []string{describeClusterOutputJSON, describeClusterOutputYAML, describeClusterOutputDOT}
+
+// writeObjectTree writes the cluster status to w in the given output format.
+func writeObjectTree(w io.Writer, objectTree *tree.ObjectTree, output string, v1beta2 bool) error {
+	node := objectTree.ToNode()
+
+	switch output {
+	case describeClusterOutputJSON:
+		out, err := json.MarshalIndent(node, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal the cluster status to json")
+		}
+		_, err = fmt.Fprintln(w, string(out))
+		return err
+	case describeClusterOutputYAML:
+		out, err := yaml.Marshal(node)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal the cluster status to yaml")
+		}
+		_, err = w.Write(out)
+		return err
+	case describeClusterOutputDOT:
+		_, err := io.WriteString(w, objectTreeToDOT(node, v1beta2))
+		return err
+	default:
+		return errors.Errorf("invalid output format %q, valid values are %s", output, strings.Join(describeClusterOutputs, ", "))
+	}
+}
+
+// objectTreeToDOT returns a representation of the cluster status in the Graphviz DOT language.
+// Each object is a node labeled with its name and ready condition, and filled with a color
+// reflecting the ready condition; virtual objects are dashed.
+func objectTreeToDOT(root *tree.ObjectTreeNode, v1beta2 bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", dotQuote(fmt.Sprintf("%s/%s", root.Object.Kind, root.Object.Name)))
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\"];\n")
+
+	var addNode func(n *tree.ObjectTreeNode)
+	addNode = func(n *tree.ObjectTreeNode) {
+		label := []string{dotNodeName(n)}
+		status, fillColor := dotNodeStatus(n, v1beta2)
+		if status != "" {
+			label = append(label, status)
+		}
+		style := ""
+		if n.Virtual {
+			style = ", style=\"rounded,filled,dashed\""
+		}
+		fmt.Fprintf(&b, "  %s [label=%s, fillcolor=%s%s];\n",
+			dotQuote(string(n.Object.UID)), dotQuote(strings.Join(label, "\n")), dotQuote(fillColor), style)
+		for _, child := range n.Children {
+			addNode(child)
+			fmt.Fprintf(&b, "  %s -> %s;\n", dotQuote(string(n.Object.UID)), dotQuote(string(child.Object.UID)))
+		}
+	}
+	addNode(root)
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotNodeName returns the name of a node using the same rules used for the rows of the table output.
+func dotNodeName(n *tree.ObjectTreeNode) string {
+	if n.Group {
+		kind := flect.Pluralize(strings.TrimSuffix(n.Object.Kind, "Group"))
+		return fmt.Sprintf("%d %s...", len(n.GroupItems), kind)
+	}
+
+	if n.Virtual {
+		if n.MetaName != "" {
+			return n.MetaName
+		}
+		return n.Object.Name
+	}
+
+	name := fmt.Sprintf("%s/%s", n.Object.Kind, n.Object.Name)
+	if n.MetaName != "" {
+		name = fmt.Sprintf("%s - %s", n.MetaName, name)
+	}
+	if n.DeletionTimestamp != nil && !n.DeletionTimestamp.IsZero() {
+		name = fmt.Sprintf("!! DELETED !! %s", name)
+	}
+	return name
+}
+
+// dotNodeStatus returns a description of the ready condition of a node, and the corresponding fill color.
+func dotNodeStatus(n *tree.ObjectTreeNode, v1beta2 bool) (string, string) {
+	if v1beta2 {
+		for _, c := range n.V1Beta2Conditions {
+			if c.Type != clusterv1.ReadyV1Beta2Condition {
+				continue
+			}
+			status := fmt.Sprintf("Ready: %s", c.Status)
+			if c.Reason != "" {
+				status = fmt.Sprintf("%s (%s)", status, c.Reason)
+			}
+			switch c.Status {
+			case metav1.ConditionTrue:
+				return status, "palegreen"
+			case metav1.ConditionFalse:
+				return status, "lightcoral"
+			default:
+				return status, "lightgoldenrod"
+			}
+		}
+		return "", "white"
+	}
+
+	for _, c := range n.Conditions {
+		if c.Type != clusterv1.ReadyCondition {
+			continue
+		}
+		status := fmt.Sprintf("Ready: %s", c.Status)
+		if c.Reason != "" {
+			status = fmt.Sprintf("%s (%s)", status, c.Reason)
+		}
+		// NOTE: colors are determined according to Status and Severity, like in the table output.
+		switch c.Status {
+		case corev1.ConditionTrue:
+			return status, "palegreen"
+		case corev1.ConditionFalse, corev1.ConditionUnknown:
+			switch c.Severity {
+			case clusterv1.ConditionSeverityError:
+				return status, "lightcoral"
+			case clusterv1.ConditionSeverityWarning:
+				return status, "lightgoldenrod"
+			default:
+				return status, "white"
+			}
+		default:
+			return status, "lightgray"
+		}
+	}
+	return "", "white"
+}
+
+// dotQuote returns s as a DOT quoted string.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/yaml"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/tree"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func fakeObjectTreeForOutput() *tree.ObjectTree {
+	root := fakeObject("root",
+		withCondition(conditions.TrueCondition(clusterv1.ReadyCondition)),
+	)
+	objectTree := tree.NewObjectTree(root, tree.ObjectTreeOptions{})
+
+	workers := tree.VirtualObject("ns", "WorkerGroup", "Workers")
+	objectTree.Add(root, workers)
+	objectTree.Add(workers, fakeObject("child \"1\"",
+		withCondition(conditions.FalseCondition(clusterv1.ReadyCondition, "Failed", clusterv1.ConditionSeverityError, "")),
+	))
+	return objectTree
+}
+
+func Test_writeObjectTree(t *testing.T) {
+	tests := []struct {
+		name      string
+		output    string
+		unmarshal func([]byte, interface{}) error
+		wantErr   bool
+	}{
+		{
+			name:      "json",
+			output:    "json",
+			unmarshal: json.Unmarshal,
+		},
+		{
+			name:   "yaml",
+			output: "yaml",
+			unmarshal: func(b []byte, o interface{}) error {
+				return yaml.Unmarshal(b, o)
+			},
+		},
+		{
+			name:    "invalid output",
+			output:  "xml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			var out bytes.Buffer
+			err := writeObjectTree(&out, fakeObjectTreeForOutput(), tt.output, false)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			got := &tree.ObjectTreeNode{}
+			g.Expect(tt.unmarshal(out.Bytes(), got)).To(Succeed())
+			g.Expect(got.Object.Name).To(Equal("root"))
+			g.Expect(got.Conditions).To(HaveLen(1))
+			g.Expect(got.Children).To(HaveLen(1))
+			g.Expect(got.Children[0].Virtual).To(BeTrue())
+			g.Expect(got.Children[0].Children).To(HaveLen(1))
+			g.Expect(got.Children[0].Children[0].Object.Name).To(Equal("child \"1\""))
+		})
+	}
+}
+
+func Test_objectTreeToDOT(t *testing.T) {
+	g := NewWithT(t)
+
+	got := objectTreeToDOT(fakeObjectTreeForOutput().ToNode(), false)
+
+	g.Expect(got).To(Equal(`digraph "Object/root" {
+  rankdir=LR;
+  node [shape=box, style="rounded,filled", fontname="Helvetica"];
+  "root" [label="Object/root\nReady: True", fillcolor="palegreen"];
+  "virtual.cluster.x-k8s.io/v1beta1, Kind=WorkerGroup, ns/Workers" [label="Workers", fillcolor="white", style="rounded,filled,dashed"];
+  "child \"1\"" [label="Object/child \"1\"\nReady: False (Failed)", fillcolor="lightcoral"];
+  "virtual.cluster.x-k8s.io/v1beta1, Kind=WorkerGroup, ns/Workers" -> "child \"1\"";
+  "root" -> "virtual.cluster.x-k8s.io/v1beta1, Kind=WorkerGroup, ns/Workers";
+}
+`))
+}
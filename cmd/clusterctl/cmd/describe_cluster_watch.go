@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/tree"
+)
+
+const (
+	// ANSI escape sequences used for redrawing the output in place.
+	cursorHome        = "\x1b[H"
+	clearScreen       = "\x1b[2J"
+	clearToEndOfLine  = "\x1b[K"
+	clearToEndOfFrame = "\x1b[J"
+)
+
+// runDescribeClusterWatch prints the cluster status every time any of the objects of the cluster changes,
+// until the command is interrupted.
+func runDescribeClusterWatch(ctx context.Context, c client.Client, options client.DescribeClusterOptions) error {
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	w := &frameWriter{out: os.Stdout}
+	var previous *tree.ObjectTree
+	return c.WatchCluster(ctx, options, func(current *tree.ObjectTree) error {
+		transitions := tree.MarkChangedConditions(previous, current)
+		previous = current
+
+		var frame bytes.Buffer
+		writeWatchFrame(&frame, current, transitions, options.V1Beta2, time.Now())
+		return w.write(frame.Bytes())
+	})
+}
+
+// writeWatchFrame writes the cluster status and the conditions changed since the previous update to w.
+func writeWatchFrame(w io.Writer, objectTree *tree.ObjectTree, transitions []tree.ConditionTransition, v1beta2 bool, now time.Time) {
+	fmt.Fprintf(w, "%s\n\n", gray.Sprintf("Watching %s/%s, last update at %s (press Ctrl+C to exit)",
+		objectTree.GetRoot().GetNamespace(), objectTree.GetRoot().GetName(), now.Format(time.TimeOnly)))
+
+	printTree(w, objectTree, v1beta2)
+
+	if len(transitions) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n%s\n", color.New(color.Bold).Sprint("Changes since the previous update:"))
+	for _, t := range transitions {
+		fmt.Fprintf(w, "  %s %s\n", getRowName(t.Object), formatConditionTransition(t))
+	}
+}
+
+// formatConditionTransition returns a description of a condition transition, e.g. "Ready: False (Reason) → True, after 2m".
+func formatConditionTransition(t tree.ConditionTransition) string {
+	from := "<none>"
+	if t.PreviousStatus != "" {
+		from = formatConditionStatus(t.PreviousStatus, t.PreviousReason)
+	}
+	description := fmt.Sprintf("%s: %s → %s", cyan.Sprint(t.Type), from, formatConditionStatus(t.Status, t.Reason))
+	if t.Elapsed > 0 {
+		description = fmt.Sprintf("%s, after %s", description, duration.HumanDuration(t.Elapsed))
+	}
+	return description
+}
+
+func formatConditionStatus(status, reason string) string {
+	if reason == "" {
+		return status
+	}
+	return fmt.Sprintf("%s (%s)", status, reason)
+}
+
+// frameWriter writes frames in place, overwriting the previous frame line by line
+// instead of clearing the screen, so the output does not flicker.
+type frameWriter struct {
+	out     io.Writer
+	started bool
+}
+
+func (f *frameWriter) write(frame []byte) error {
+	var b bytes.Buffer
+	if !f.started {
+		b.WriteString(clearScreen)
+		f.started = true
+	}
+	b.WriteString(cursorHome)
+
+	scanner := bufio.NewScanner(bytes.NewReader(frame))
+	for scanner.Scan() {
+		b.Write(scanner.Bytes())
+		b.WriteString(clearToEndOfLine)
+		b.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	b.WriteString(clearToEndOfFrame)
+
+	_, err := f.out.Write(b.Bytes())
+	return err
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/tree"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func Test_formatConditionTransition(t *testing.T) {
+	tests := []struct {
+		name       string
+		transition tree.ConditionTransition
+		want       string
+	}{
+		{
+			name:       "new condition",
+			transition: tree.ConditionTransition{Type: "Ready", Status: "False", Reason: "WaitingForInfrastructure"},
+			want:       "Ready: <none> → False (WaitingForInfrastructure)",
+		},
+		{
+			name:       "status changed",
+			transition: tree.ConditionTransition{Type: "Ready", PreviousStatus: "False", PreviousReason: "WaitingForInfrastructure", Status: "True", Elapsed: 90 * time.Second},
+			want:       "Ready: False (WaitingForInfrastructure) → True, after 90s",
+		},
+		{
+			name:       "reason changed",
+			transition: tree.ConditionTransition{Type: "Ready", PreviousStatus: "False", PreviousReason: "WaitingForInfrastructure", Status: "False", Reason: "WaitingForBootstrapData"},
+			want:       "Ready: False (WaitingForInfrastructure) → False (WaitingForBootstrapData)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(formatConditionTransition(tt.transition)).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_writeWatchFrame(t *testing.T) {
+	g := NewWithT(t)
+
+	root := fakeObject("root")
+	objectTree := tree.NewObjectTree(root, tree.ObjectTreeOptions{})
+	child := fakeObject("child", withCondition(conditions.TrueCondition(clusterv1.ReadyCondition)))
+	objectTree.Add(root, child)
+
+	var out bytes.Buffer
+	writeWatchFrame(&out, objectTree, []tree.ConditionTransition{
+		{Object: child, Type: "Ready", PreviousStatus: "False", Status: "True", Elapsed: time.Minute},
+	}, false, time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC))
+
+	lines := strings.Split(out.String(), "\n")
+	g.Expect(lines[0]).To(Equal("Watching ns/root, last update at 10:30:00 (press Ctrl+C to exit)"))
+	g.Expect(out.String()).To(ContainSubstring("└─Object/child"))
+	g.Expect(out.String()).To(HaveSuffix("Changes since the previous update:\n  Object/child Ready: False → True, after 60s\n"))
+}
+
+func Test_frameWriter(t *testing.T) {
+	g := NewWithT(t)
+
+	var out bytes.Buffer
+	w := &frameWriter{out: &out}
+
+	// The first frame clears the screen.
+	g.Expect(w.write([]byte("line 1\nline 2\n"))).To(Succeed())
+	g.Expect(out.String()).To(Equal(clearScreen + cursorHome + "line 1" + clearToEndOfLine + "\nline 2" + clearToEndOfLine + "\n" + clearToEndOfFrame))
+
+	// Next frames overwrite the previous one.
+	out.Reset()
+	g.Expect(w.write([]byte("line 1\n"))).To(Succeed())
+	g.Expect(out.String()).To(Equal(cursorHome + "line 1" + clearToEndOfLine + "\n" + clearToEndOfFrame))
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/cmd/internal/templates"
+)
+
+type doctorOptions struct {
+	kubeconfig                 string
+	kubeconfigContext          string
+	certificateExpiryThreshold time.Duration
+}
+
+var do = &doctorOptions{}
+
+var doctorCmd = &cobra.Command{
+	Use:     "doctor",
+	GroupID: groupDebug,
+	Short:   "Diagnose the health of a management cluster",
+	Long: templates.LongDesc(`
+		Diagnose the health of a management cluster, checking it against what clusterctl knows about it.
+
+		The following checks are run:
+		- Providers: each provider in the inventory has its controllers deployed and available.
+		- Contracts: each provider implements the Cluster API contract of the management cluster, according to the provider metadata.
+		- Webhooks: provider webhooks point to an existing Service and have a valid CA bundle which is not close to expiry.
+		- Certificates: cert-manager Certificates in the provider namespaces are ready and not close to expiry.
+		- CRD stored versions: the objects of the provider CRDs are stored in the current storage version.
+		- Paused Clusters: no Cluster is left paused.
+
+		Each finding is reported as pass, warn or fail, together with a hint about how to fix it.
+		The command fails if at least one check failed.`),
+
+	Example: templates.Examples(`
+		# Diagnose the health of the management cluster.
+		clusterctl doctor
+
+		# Diagnose the health of the management cluster, warning about certificates expiring in less than 60 days.
+		clusterctl doctor --certificate-expiry-threshold=1440h`),
+
+	Args: cobra.NoArgs,
+	RunE: func(*cobra.Command, []string) error {
+		return runDoctor()
+	},
+}
+
+func init() {
+	doctorCmd.Flags().StringVar(&do.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig file to use for accessing the management cluster. If empty, default discovery rules apply.")
+	doctorCmd.Flags().StringVar(&do.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	doctorCmd.Flags().DurationVar(&do.certificateExpiryThreshold, "certificate-expiry-threshold", 30*24*time.Hour,
+		"The time before expiry after which certificates are reported with a warning.")
+
+	RootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor() error {
+	ctx := context.Background()
+
+	c, err := client.New(ctx, cfgFile)
+	if err != nil {
+		return err
+	}
+
+	report, err := c.Doctor(ctx, client.DoctorOptions{
+		Kubeconfig:                 client.Kubeconfig{Path: do.kubeconfig, Context: do.kubeconfigContext},
+		CertificateExpiryThreshold: do.certificateExpiryThreshold,
+	})
+	if err != nil {
+		return err
+	}
+
+	writeDoctorReport(os.Stdout, report)
+	if report.HasFailures() {
+		return errors.New("the management cluster failed one or more checks")
+	}
+	return nil
+}
+
+// writeDoctorReport writes a line for each finding, followed by the remediation hint, if any, and a summary.
+func writeDoctorReport(w io.Writer, report *client.DoctorReport) {
+	counts := map[cluster.DoctorCheckStatus]int{}
+	for _, result := range report.Results {
+		counts[result.Status]++
+		fmt.Fprintf(w, "[%s] %s: %s\n", strings.ToUpper(string(result.Status)), result.Check, result.Message)
+		if result.Remediation != "" {
+			fmt.Fprintf(w, "       Hint: %s\n", result.Remediation)
+		}
+	}
+	fmt.Fprintf(w, "\n%d passed, %d warnings, %d failed\n", counts[cluster.DoctorCheckPassed], counts[cluster.DoctorCheckWarning], counts[cluster.DoctorCheckFailed])
+}
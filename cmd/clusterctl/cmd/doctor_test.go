@@ -0,0 +1,52 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+)
+
+func Test_writeDoctorReport(t *testing.T) {
+	g := NewWithT(t)
+
+	report := &client.DoctorReport{
+		Results: []cluster.DoctorCheckResult{
+			{Check: "Providers", Status: cluster.DoctorCheckPassed, Message: "All the 4 providers in the inventory have their controllers available"},
+			{Check: "Paused Clusters", Status: cluster.DoctorCheckWarning, Message: "Cluster default/c1 is paused", Remediation: "Resume it."},
+			{Check: "Contracts", Status: cluster.DoctorCheckFailed, Message: "Provider infra implements the v1alpha4 contract", Remediation: "Upgrade it."},
+		},
+	}
+
+	var out bytes.Buffer
+	writeDoctorReport(&out, report)
+
+	g.Expect(out.String()).To(Equal(`[PASS] Providers: All the 4 providers in the inventory have their controllers available
+[WARN] Paused Clusters: Cluster default/c1 is paused
+       Hint: Resume it.
+[FAIL] Contracts: Provider infra implements the v1alpha4 contract
+       Hint: Upgrade it.
+
+1 passed, 1 warnings, 1 failed
+`))
+	g.Expect(report.HasFailures()).To(BeTrue())
+}
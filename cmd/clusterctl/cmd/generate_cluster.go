@@ -19,11 +19,15 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/cmd/internal/templates"
 )
 
@@ -43,7 +47,9 @@ type generateClusterOptions struct {
 	configMapName      string
 	configMapDataKey   string
 
-	listVariables bool
+	listVariables     bool
+	validate          bool
+	templateProcessor string
 
 	output string
 }
@@ -91,7 +97,15 @@ var generateClusterClusterCmd = &cobra.Command{
 		clusterctl generate cluster my-cluster --from ~/workspace/cluster-template.yaml
 
 		# Prints the list of variables required by the yaml file for creating workload cluster.
-		clusterctl generate cluster my-cluster --list-variables`),
+		clusterctl generate cluster my-cluster --list-variables
+
+		# Generates a yaml file for creating workload clusters using a template
+		# written with Go text/template.
+		clusterctl generate cluster my-cluster --from ~/workspace/cluster-template.yaml --template-processor go-template
+
+		# Generates a yaml file for creating workload clusters, after validating the objects
+		# against the schemas of the provider CRDs.
+		clusterctl generate cluster my-cluster --validate`),
 
 	Args: func(_ *cobra.Command, args []string) error {
 		if len(args) != 1 {
@@ -142,6 +156,10 @@ func init() {
 	// other flags
 	generateClusterClusterCmd.Flags().BoolVar(&gc.listVariables, "list-variables", false,
 		"Returns the list of variables expected by the template instead of the template yaml")
+	generateClusterClusterCmd.Flags().StringVar(&gc.templateProcessor, "template-processor", "",
+		fmt.Sprintf("The yaml processor to use for the workload cluster template. Allowed values are [%s]. If unspecified, the processor configured for the infrastructure provider or the simple processor will be used.", strings.Join(yamlprocessor.ProcessorNames, ", ")))
+	generateClusterClusterCmd.Flags().BoolVar(&gc.validate, "validate", false,
+		"Validates the objects in the template against the schemas of the provider CRDs, and the Cluster variables against the ClusterClass, before returning the template yaml. CRDs are read from the management cluster if available, from the provider repositories otherwise.")
 	generateClusterClusterCmd.Flags().StringVar(&gc.output, "write-to", "", "Specify the output file to write the template to, defaults to STDOUT if the flag is not set")
 
 	generateCmd.AddCommand(generateClusterClusterCmd)
@@ -163,6 +181,14 @@ func runGenerateClusterTemplate(cmd *cobra.Command, name string) error {
 		ListVariablesOnly: gc.listVariables,
 	}
 
+	if gc.templateProcessor != "" {
+		processor, err := yamlprocessor.NewProcessor(gc.templateProcessor)
+		if err != nil {
+			return err
+		}
+		templateOptions.YamlProcessor = processor
+	}
+
 	if cmd.Flags().Changed("control-plane-machine-count") {
 		templateOptions.ControlPlaneMachineCount = &gc.controlPlaneMachineCount
 	}
@@ -200,5 +226,37 @@ func runGenerateClusterTemplate(cmd *cobra.Command, name string) error {
 		return printVariablesOutput(template, templateOptions)
 	}
 
+	if gc.validate {
+		validateOptions := client.ValidateClusterTemplateOptions{
+			Kubeconfig: templateOptions.Kubeconfig,
+			Template:   template,
+		}
+		if gc.infrastructureProvider != "" {
+			validateOptions.InfrastructureProviders = []string{gc.infrastructureProvider}
+		}
+		results, err := c.ValidateClusterTemplate(ctx, validateOptions)
+		if err != nil {
+			return err
+		}
+		if len(results) > 0 {
+			writeTemplateValidationResults(os.Stderr, results)
+			return errors.Errorf("cluster template validation failed: %d object(s) are not valid", len(results))
+		}
+	}
+
 	return printYamlOutput(template, gc.output)
 }
+
+// writeTemplateValidationResults writes the validation errors for each object in the template.
+func writeTemplateValidationResults(w io.Writer, results []client.TemplateValidationResult) {
+	for _, result := range results {
+		key := result.Object.Name
+		if result.Object.Namespace != "" {
+			key = fmt.Sprintf("%s/%s", result.Object.Namespace, result.Object.Name)
+		}
+		fmt.Fprintf(w, "%s %s:\n", result.Object.Kind, key)
+		for _, err := range result.Errors {
+			fmt.Fprintf(w, "  - %s\n", err)
+		}
+	}
+}
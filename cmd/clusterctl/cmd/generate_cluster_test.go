@@ -0,0 +1,52 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+func Test_writeTemplateValidationResults(t *testing.T) {
+	g := NewWithT(t)
+
+	results := []client.TemplateValidationResult{
+		{
+			Object: corev1.ObjectReference{Kind: "DockerCluster", Namespace: "ns1", Name: "c1"},
+			Errors: []string{"spec.foo: Forbidden: unknown field, it is not defined in the schema", "spec.region: Required value"},
+		},
+		{
+			Object: corev1.ObjectReference{Kind: "Cluster", Name: "c1"},
+			Errors: []string{"spec.topology.class: Not found: \"ns1/dev\""},
+		},
+	}
+
+	var out bytes.Buffer
+	writeTemplateValidationResults(&out, results)
+
+	g.Expect(out.String()).To(Equal(`DockerCluster ns1/c1:
+  - spec.foo: Forbidden: unknown field, it is not defined in the schema
+  - spec.region: Required value
+Cluster c1:
+  - spec.topology.class: Not found: "ns1/dev"
+`))
+}
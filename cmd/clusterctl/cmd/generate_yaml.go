@@ -21,16 +21,19 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/cmd/internal/templates"
 )
 
 type generateYAMLOptions struct {
-	url           string
-	listVariables bool
+	url               string
+	listVariables     bool
+	templateProcessor string
 }
 
 var gyOpts = &generateYAMLOptions{}
@@ -42,7 +45,8 @@ var generateYamlCmd = &cobra.Command{
 		Process yaml using clusterctl's yaml processor.
 
 		clusterctl ships with a simple yaml processor that performs variable
-		substitution that takes into account of default values.
+		substitution that takes into account of default values, and with
+		processors based on Go text/template and on CUE which support conditionals and loops.
 
 		Variable values are either sourced from the clusterctl config file or
 		from environment variables`),
@@ -61,6 +65,14 @@ var generateYamlCmd = &cobra.Command{
 
 		# Prints list of variables from template passed in via stdin
 		cat ~/workspace/cluster-template.yaml | clusterctl generate yaml --list-variables
+
+		# Generates a configuration file with variable values using
+		a template written with Go text/template.
+		clusterctl generate yaml --from ~/workspace/cluster-template.yaml --template-processor go-template
+
+		# Generates a configuration file with variable values using
+		a template written in CUE.
+		clusterctl generate yaml --from ~/workspace/cluster-template.cue --template-processor cue
 `),
 
 	RunE: func(*cobra.Command, []string) error {
@@ -76,6 +88,8 @@ func init() {
 	// other flags
 	generateYamlCmd.Flags().BoolVar(&gyOpts.listVariables, "list-variables", false,
 		"Returns the list of variables expected by the template instead of the template yaml")
+	generateYamlCmd.Flags().StringVar(&gyOpts.templateProcessor, "template-processor", "",
+		fmt.Sprintf("The yaml processor to use for the template. Allowed values are [%s]. If unspecified, the simple processor will be used.", strings.Join(yamlprocessor.ProcessorNames, ", ")))
 
 	generateCmd.AddCommand(generateYamlCmd)
 }
@@ -90,6 +104,13 @@ func generateYAML(r io.Reader, w io.Writer) error {
 	options := client.ProcessYAMLOptions{
 		SkipTemplateProcess: gyOpts.listVariables,
 	}
+	if gyOpts.templateProcessor != "" {
+		processor, err := yamlprocessor.NewProcessor(gyOpts.templateProcessor)
+		if err != nil {
+			return err
+		}
+		options.YamlProcessor = processor
+	}
 	if gyOpts.url != "" {
 		if gyOpts.url == "-" {
 			options.ReaderSource = &client.ReaderSourceOptions{
@@ -40,6 +40,14 @@ v3: ${VAR3:-default3}`
 v2: bazfoo`)
 	defer cleanup2()
 
+	goTemplate, cleanup3 := createTempFile(g, `{{ range $i := until 2 }}v{{ $i }}: {{ $.VAR1 | default "default1" }}
+{{ end }}`)
+	defer cleanup3()
+
+	cueTemplate, cleanup4 := createTempFile(g, `v1: *"default1" | string @tag(VAR1)
+objects: [{v: v1}]`)
+	defer cleanup4()
+
 	inputReader := strings.NewReader(contents)
 
 	tests := []struct {
@@ -78,6 +86,34 @@ v3: default3
 			options:   &generateYAMLOptions{},
 			expectErr: true,
 		},
+		{
+			name:      "prints processed yaml using --template-processor flag",
+			options:   &generateYAMLOptions{url: goTemplate, templateProcessor: "go-template"},
+			expectErr: false,
+			expectedOutput: `v0: default1
+v1: default1
+`,
+		},
+		{
+			name:      "prints variables using --template-processor and --list-variables flags",
+			options:   &generateYAMLOptions{url: goTemplate, templateProcessor: "go-template", listVariables: true},
+			expectErr: false,
+			expectedOutput: `Variables:
+  - VAR1
+`,
+		},
+		{
+			name:      "prints processed yaml using the cue --template-processor",
+			options:   &generateYAMLOptions{url: cueTemplate, templateProcessor: "cue"},
+			expectErr: false,
+			expectedOutput: `v: default1
+`,
+		},
+		{
+			name:      "returns error for invalid --template-processor flag",
+			options:   &generateYAMLOptions{url: goTemplate, templateProcessor: "foo"},
+			expectErr: true,
+		},
 		{
 			name:           "prints nothing if there are no variables in the template",
 			options:        &generateYAMLOptions{url: templateWithoutVars, listVariables: true},
@@ -19,9 +19,11 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
@@ -32,6 +34,9 @@ type getKubeconfigOptions struct {
 	kubeconfig        string
 	kubeconfigContext string
 	namespace         string
+	user              string
+	groups            []string
+	ttl               time.Duration
 }
 
 var gk = &getKubeconfigOptions{}
@@ -40,14 +45,21 @@ var getKubeconfigCmd = &cobra.Command{
 	Use:   "kubeconfig NAME",
 	Short: "Gets the kubeconfig file for accessing a workload cluster",
 	Long: templates.LongDesc(`
-		Gets the kubeconfig file for accessing a workload cluster`),
+		Gets the kubeconfig file for accessing a workload cluster.
+
+		By default the admin kubeconfig of the workload cluster is returned; use --user to get a kubeconfig
+		with a short-lived client certificate for the given user and groups instead, signed by the workload
+		cluster CA, so the long-lived admin credentials are not shared.`),
 
 	Example: templates.Examples(`
 		# Get the workload cluster's kubeconfig.
 		clusterctl get kubeconfig <name of workload cluster>
 
 		# Get the workload cluster's kubeconfig in a particular namespace.
-		clusterctl get kubeconfig <name of workload cluster> --namespace foo`),
+		clusterctl get kubeconfig <name of workload cluster> --namespace foo
+
+		# Get a kubeconfig for the user jane in the developers group, valid for 8 hours.
+		clusterctl get kubeconfig <name of workload cluster> --user jane --group developers --ttl 8h`),
 
 	Args: func(_ *cobra.Command, args []string) error {
 		if len(args) != 1 {
@@ -55,8 +67,8 @@ var getKubeconfigCmd = &cobra.Command{
 		}
 		return nil
 	},
-	RunE: func(_ *cobra.Command, args []string) error {
-		return runGetKubeconfig(args[0])
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runGetKubeconfig(cmd.Flags(), args[0])
 	},
 }
 
@@ -67,6 +79,12 @@ func init() {
 		"Path to the kubeconfig file to use for accessing the management cluster. If unspecified, default discovery rules apply.")
 	getKubeconfigCmd.Flags().StringVar(&gk.kubeconfigContext, "kubeconfig-context", "",
 		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	getKubeconfigCmd.Flags().StringVar(&gk.user, "user", "",
+		"User to issue a short-lived client certificate for, signed by the workload cluster CA. If unspecified, the admin kubeconfig is returned.")
+	getKubeconfigCmd.Flags().StringSliceVar(&gk.groups, "group", nil,
+		"Groups of the user; system:masters is not allowed. This flag can be used only together with --user.")
+	getKubeconfigCmd.Flags().DurationVar(&gk.ttl, "ttl", time.Hour,
+		"Lifespan of the client certificate issued for the user, capped to the expiration of the workload cluster CA. This flag can be used only together with --user.")
 
 	// completions
 	getKubeconfigCmd.ValidArgsFunction = resourceNameCompletionFunc(
@@ -80,9 +98,13 @@ func init() {
 	getCmd.AddCommand(getKubeconfigCmd)
 }
 
-func runGetKubeconfig(workloadClusterName string) error {
+func runGetKubeconfig(flags *pflag.FlagSet, workloadClusterName string) error {
 	ctx := context.Background()
 
+	if err := gk.validate(flags); err != nil {
+		return err
+	}
+
 	c, err := client.New(ctx, cfgFile)
 	if err != nil {
 		return err
@@ -92,6 +114,12 @@ func runGetKubeconfig(workloadClusterName string) error {
 		Kubeconfig:          client.Kubeconfig{Path: gk.kubeconfig, Context: gk.kubeconfigContext},
 		WorkloadClusterName: workloadClusterName,
 		Namespace:           gk.namespace,
+		User:                gk.user,
+		Groups:              gk.groups,
+	}
+	// NOTE: the TTL is passed only if explicitly set, so the default is applied by the client.
+	if flags.Changed("ttl") {
+		options.TTL = gk.ttl
 	}
 
 	out, err := c.GetKubeconfig(ctx, options)
@@ -101,3 +129,17 @@ func runGetKubeconfig(workloadClusterName string) error {
 	fmt.Println(out)
 	return nil
 }
+
+// validate checks that the flags for issuing a client certificate are used only together with --user.
+func (o *getKubeconfigOptions) validate(flags *pflag.FlagSet) error {
+	if o.user != "" {
+		return nil
+	}
+	if len(o.groups) > 0 {
+		return errors.New("--group can be used only together with --user")
+	}
+	if flags.Changed("ttl") {
+		return errors.New("--ttl can be used only together with --user")
+	}
+	return nil
+}
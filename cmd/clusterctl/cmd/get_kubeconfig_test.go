@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+)
+
+func Test_getKubeconfigOptions_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{
+			name: "admin kubeconfig",
+			args: []string{},
+		},
+		{
+			name: "user kubeconfig",
+			args: []string{"--user", "jane", "--group", "developers", "--ttl", "8h"},
+		},
+		{
+			name:    "fails if --group is used without --user",
+			args:    []string{"--group", "developers"},
+			wantErr: true,
+		},
+		{
+			name:    "fails if --ttl is used without --user",
+			args:    []string{"--ttl", "8h"},
+			wantErr: true,
+		},
+		{
+			name:    "fails if --ttl is set to the default value without --user",
+			args:    []string{"--ttl", "1h"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			o := &getKubeconfigOptions{}
+			flags := pflag.NewFlagSet("kubeconfig", pflag.ContinueOnError)
+			flags.StringVar(&o.user, "user", "", "")
+			flags.StringSliceVar(&o.groups, "group", nil, "")
+			flags.DurationVar(&o.ttl, "ttl", time.Hour, "")
+			g.Expect(flags.Parse(tt.args)).To(Succeed())
+
+			err := o.validate(flags)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
@@ -17,12 +17,19 @@ limitations under the License.
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"os"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"k8s.io/client-go/rest"
+	"sigs.k8s.io/yaml"
 
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
@@ -33,15 +40,25 @@ import (
 )
 
 type moveOptions struct {
-	fromKubeconfig        string
-	fromKubeconfigContext string
-	toKubeconfig          string
-	toKubeconfigContext   string
-	namespace             string
-	fromDirectory         string
-	toDirectory           string
-	dryRun                bool
-	hideAPIWarnings       string
+	fromKubeconfig            string
+	fromKubeconfigContext     string
+	toKubeconfig              string
+	toKubeconfigContext       string
+	namespace                 string
+	clusterName               string
+	clusterSelector           string
+	fromDirectory             string
+	toDirectory               string
+	passphraseFile            string
+	signingKey                string
+	verificationKey           string
+	skipSignatureVerification bool
+	dryRun                    bool
+	output                    string
+	checkpointFile            string
+	resume                    bool
+	rollback                  bool
+	hideAPIWarnings           string
 }
 
 var mo = &moveOptions{}
@@ -64,6 +81,30 @@ var moveCmd = &cobra.Command{
 
 		Read Cluster API objects and all dependencies from a directory into a management cluster.
 		clusterctl move --from-directory /tmp/backup-directory
+
+		Write Cluster API objects and all dependencies from a management cluster to directory, encrypting the files and signing the manifest.
+		clusterctl move --to-directory /tmp/backup-directory --encryption-passphrase-file passphrase.txt --signing-key signing-key.pem
+
+		Read Cluster API objects and all dependencies from an encrypted directory into a management cluster, verifying the signed manifest.
+		clusterctl move --from-directory /tmp/backup-directory --encryption-passphrase-file passphrase.txt --verification-key verification-key.pem
+
+		Print the plan for moving Cluster API objects and all dependencies between management clusters in yaml format.
+		clusterctl move --to-kubeconfig=target-kubeconfig.yaml --dry-run -o yaml
+
+		Move Cluster API objects and all dependencies between management clusters, persisting the progress to a checkpoint file.
+		clusterctl move --to-kubeconfig=target-kubeconfig.yaml --checkpoint-file=move-checkpoint.yaml
+
+		Complete a move interrupted midway.
+		clusterctl move --to-kubeconfig=target-kubeconfig.yaml --checkpoint-file=move-checkpoint.yaml --resume
+
+		Undo a move interrupted midway.
+		clusterctl move --to-kubeconfig=target-kubeconfig.yaml --checkpoint-file=move-checkpoint.yaml --rollback
+
+		Move a single Cluster and the objects it depends on, e.g. its ClusterClass, between management clusters.
+		clusterctl move --to-kubeconfig=target-kubeconfig.yaml --cluster=my-cluster
+
+		Move the Clusters matching a label selector and the objects they depend on between management clusters.
+		clusterctl move --to-kubeconfig=target-kubeconfig.yaml --cluster-selector=env=dev
 	`),
 	Args: cobra.NoArgs,
 	RunE: func(*cobra.Command, []string) error {
@@ -82,18 +123,52 @@ func init() {
 		"Context to be used within the kubeconfig file for the destination management cluster. If empty, current context will be used.")
 	moveCmd.Flags().StringVarP(&mo.namespace, "namespace", "n", "",
 		"The namespace where the workload cluster is hosted. If unspecified, the current context's namespace is used.")
+	moveCmd.Flags().StringVar(&mo.clusterName, "cluster", "",
+		"The name of the Cluster to move, together with the objects it depends on. If unspecified, all the Clusters in the namespace are moved.")
+	moveCmd.Flags().StringVar(&mo.clusterSelector, "cluster-selector", "",
+		"Label selector for the Clusters to move, together with the objects they depend on. If unspecified, all the Clusters in the namespace are moved.")
 	moveCmd.Flags().BoolVar(&mo.dryRun, "dry-run", false,
 		"Enable dry run, don't really perform the move actions")
+	moveCmd.Flags().StringVarP(&mo.output, "output", "o", "",
+		"Output format for the plan of a dry run; available options are 'yaml' and 'json'. If empty, the dry run only prints logs. Requires --dry-run.")
 	moveCmd.Flags().StringVar(&mo.toDirectory, "to-directory", "",
 		"Write Cluster API objects and all dependencies from a management cluster to directory.")
 	moveCmd.Flags().StringVar(&mo.fromDirectory, "from-directory", "",
 		"Read Cluster API objects and all dependencies from a directory into a management cluster.")
+	moveCmd.Flags().StringVar(&mo.passphraseFile, "encryption-passphrase-file", "",
+		"Path to a file containing the passphrase used to encrypt the files written with --to-directory, or to decrypt the files read with --from-directory.")
+	moveCmd.Flags().StringVar(&mo.signingKey, "signing-key", "",
+		"Path to a PEM encoded Ed25519 private key used to sign the manifest of the files written with --to-directory.")
+	moveCmd.Flags().StringVar(&mo.verificationKey, "verification-key", "",
+		"Path to a PEM encoded Ed25519 public key used to verify the manifest of the files read with --from-directory before restoring any object.")
+	moveCmd.Flags().BoolVar(&mo.skipSignatureVerification, "skip-signature-verification", false,
+		"Read the files with --from-directory without verifying the signature of the manifest, if signed. Without this flag, a signed manifest requires --verification-key.")
+	moveCmd.Flags().StringVar(&mo.checkpointFile, "checkpoint-file", "",
+		"Path to a file where the progress of the move is persisted, so a move interrupted midway can be resumed or rolled back.")
+	moveCmd.Flags().BoolVar(&mo.resume, "resume", false,
+		"Complete a move interrupted midway, using the progress recorded in the checkpoint file.")
+	moveCmd.Flags().BoolVar(&mo.rollback, "rollback", false,
+		"Undo a move interrupted midway, using the progress recorded in the checkpoint file. Rollback is possible only if no object has been deleted from the source management cluster yet.")
 	moveCmd.Flags().StringVar(&mo.hideAPIWarnings, "hide-api-warnings", "default",
 		"Set of API server warnings to hide. Valid sets are \"default\" (includes metadata.finalizer warnings), \"all\" , and \"none\".")
 
 	moveCmd.MarkFlagsMutuallyExclusive("to-directory", "to-kubeconfig")
 	moveCmd.MarkFlagsMutuallyExclusive("from-directory", "to-directory")
 	moveCmd.MarkFlagsMutuallyExclusive("from-directory", "kubeconfig")
+	moveCmd.MarkFlagsMutuallyExclusive("resume", "rollback")
+	moveCmd.MarkFlagsMutuallyExclusive("checkpoint-file", "dry-run")
+	moveCmd.MarkFlagsMutuallyExclusive("checkpoint-file", "to-directory")
+	moveCmd.MarkFlagsMutuallyExclusive("checkpoint-file", "from-directory")
+	moveCmd.MarkFlagsMutuallyExclusive("cluster", "resume")
+	moveCmd.MarkFlagsMutuallyExclusive("cluster", "rollback")
+	moveCmd.MarkFlagsMutuallyExclusive("cluster", "from-directory")
+	moveCmd.MarkFlagsMutuallyExclusive("cluster-selector", "resume")
+	moveCmd.MarkFlagsMutuallyExclusive("cluster-selector", "rollback")
+	moveCmd.MarkFlagsMutuallyExclusive("cluster-selector", "from-directory")
+	moveCmd.MarkFlagsMutuallyExclusive("signing-key", "from-directory")
+	moveCmd.MarkFlagsMutuallyExclusive("verification-key", "to-directory")
+	moveCmd.MarkFlagsMutuallyExclusive("skip-signature-verification", "verification-key")
+	moveCmd.MarkFlagsMutuallyExclusive("skip-signature-verification", "to-directory")
 
 	RootCmd.AddCommand(moveCmd)
 }
@@ -108,6 +183,33 @@ func runMove() error {
 		return errors.New("please specify a target cluster using the --to-kubeconfig flag when not using --dry-run, --to-directory or --from-directory")
 	}
 
+	if mo.output != "" && !mo.dryRun {
+		return errors.New("the --output flag can be used only with --dry-run")
+	}
+
+	if mo.output != "" && mo.output != "yaml" && mo.output != "json" {
+		return errors.Errorf("invalid output format: %s", mo.output)
+	}
+
+	if (mo.resume || mo.rollback) && mo.checkpointFile == "" {
+		return errors.New("please specify the checkpoint file of the move to resume or rollback using the --checkpoint-file flag")
+	}
+
+	passphrase, err := readMovePassphrase(mo.passphraseFile)
+	if err != nil {
+		return err
+	}
+
+	signingKey, err := readMoveSigningKey(mo.signingKey)
+	if err != nil {
+		return err
+	}
+
+	verificationKey, err := readMoveVerificationKey(mo.verificationKey)
+	if err != nil {
+		return err
+	}
+
 	configClient, err := config.New(ctx, cfgFile)
 	if err != nil {
 		return err
@@ -161,12 +263,131 @@ func runMove() error {
 		return err
 	}
 
+	if mo.output != "" {
+		return runMovePlan(ctx, c)
+	}
+
 	return c.Move(ctx, client.MoveOptions{
-		FromKubeconfig: client.Kubeconfig{Path: mo.fromKubeconfig, Context: mo.fromKubeconfigContext},
-		ToKubeconfig:   client.Kubeconfig{Path: mo.toKubeconfig, Context: mo.toKubeconfigContext},
-		FromDirectory:  mo.fromDirectory,
-		ToDirectory:    mo.toDirectory,
-		Namespace:      mo.namespace,
-		DryRun:         mo.dryRun,
+		FromKubeconfig:                     client.Kubeconfig{Path: mo.fromKubeconfig, Context: mo.fromKubeconfigContext},
+		ToKubeconfig:                       client.Kubeconfig{Path: mo.toKubeconfig, Context: mo.toKubeconfigContext},
+		FromDirectory:                      mo.fromDirectory,
+		ToDirectory:                        mo.toDirectory,
+		Namespace:                          mo.namespace,
+		ClusterName:                        mo.clusterName,
+		ClusterSelector:                    mo.clusterSelector,
+		DirectoryPassphrase:                passphrase,
+		DirectorySigningKey:                signingKey,
+		DirectoryVerificationKey:           verificationKey,
+		DirectorySkipSignatureVerification: mo.skipSignatureVerification,
+		DryRun:                             mo.dryRun,
+		CheckpointFile:                     mo.checkpointFile,
+		Resume:                             mo.resume,
+		Rollback:                           mo.rollback,
+	})
+}
+
+// readMovePassphrase reads the passphrase used to encrypt or decrypt the files of a move to or from a directory.
+// Trailing newlines are ignored, so the file can be created with e.g. echo.
+func readMovePassphrase(path string) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+	content, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the passphrase file %q", path)
+	}
+	passphrase := bytes.TrimRight(content, "\r\n")
+	if len(passphrase) == 0 {
+		return nil, errors.Errorf("the passphrase file %q is empty", path)
+	}
+	return passphrase, nil
+}
+
+// readMoveSigningKey reads a PEM encoded, PKCS #8 Ed25519 private key, e.g. generated with
+// openssl genpkey -algorithm ed25519.
+func readMoveSigningKey(path string) (ed25519.PrivateKey, error) {
+	if path == "" {
+		return nil, nil
+	}
+	block, err := readPEMBlock(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the signing key %q", path)
+	}
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.Errorf("the signing key %q is not an Ed25519 private key", path)
+	}
+	return privateKey, nil
+}
+
+// readMoveVerificationKey reads a PEM encoded, PKIX Ed25519 public key, e.g. generated with
+// openssl pkey -pubout.
+func readMoveVerificationKey(path string) (ed25519.PublicKey, error) {
+	if path == "" {
+		return nil, nil
+	}
+	block, err := readPEMBlock(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the verification key %q", path)
+	}
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.Errorf("the verification key %q is not an Ed25519 public key", path)
+	}
+	return publicKey, nil
+}
+
+func readPEMBlock(path string) (*pem.Block, error) {
+	content, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the key file %q", path)
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, errors.Errorf("the key file %q does not contain a PEM encoded key", path)
+	}
+	return block, nil
+}
+
+func runMovePlan(ctx context.Context, c client.Client) error {
+	plan, err := c.PlanMove(ctx, client.MoveOptions{
+		FromKubeconfig:  client.Kubeconfig{Path: mo.fromKubeconfig, Context: mo.fromKubeconfigContext},
+		ToKubeconfig:    client.Kubeconfig{Path: mo.toKubeconfig, Context: mo.toKubeconfigContext},
+		Namespace:       mo.namespace,
+		ClusterName:     mo.clusterName,
+		ClusterSelector: mo.clusterSelector,
 	})
+	if err != nil {
+		return err
+	}
+
+	var out []byte
+	switch mo.output {
+	case "yaml":
+		if out, err = yaml.Marshal(plan); err != nil {
+			return err
+		}
+	case "json":
+		if out, err = json.MarshalIndent(plan, "", "  "); err != nil {
+			return err
+		}
+		out = append(out, '\n')
+	default:
+		return errors.Errorf("invalid output format: %s", mo.output)
+	}
+	fmt.Print(string(out))
+
+	// Fail if the move cannot be completed, so the plan can be used as a gate e.g. in CI.
+	if len(plan.Blockers) > 0 {
+		return errors.Errorf("the move plan has %d blocker(s)", len(plan.Blockers))
+	}
+	return nil
 }
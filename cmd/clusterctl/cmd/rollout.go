@@ -45,8 +45,17 @@ var (
 		clusterctl alpha rollout resume machinedeployment/my-md-0
 		clusterctl alpha rollout resume kubeadmcontrolplane/my-kcp
 
-		# Rollback a machinedeployment
-		clusterctl alpha rollout undo machinedeployment/my-md-0 --to-revision=3`)
+		# Rollback a machinedeployment, kubeadmcontrolplane or machinepool
+		clusterctl alpha rollout undo machinedeployment/my-md-0 --to-revision=3
+		clusterctl alpha rollout undo kubeadmcontrolplane/my-kcp
+		clusterctl alpha rollout undo machinepool/my-mp
+
+		# Wait for the rollout of a machinedeployment or kubeadmcontrolplane to complete
+		clusterctl alpha rollout status machinedeployment/my-md-0
+		clusterctl alpha rollout status kubeadmcontrolplane/my-kcp
+
+		# Show the revisions of a machinedeployment
+		clusterctl alpha rollout history machinedeployment/my-md-0`)
 
 	rolloutCmd = &cobra.Command{
 		Use:     "rollout SUBCOMMAND",
@@ -62,4 +71,6 @@ func init() {
 	rolloutCmd.AddCommand(rollout.NewCmdRolloutPause(cfgFile))
 	rolloutCmd.AddCommand(rollout.NewCmdRolloutResume(cfgFile))
 	rolloutCmd.AddCommand(rollout.NewCmdRolloutUndo(cfgFile))
+	rolloutCmd.AddCommand(rollout.NewCmdRolloutStatus(cfgFile))
+	rolloutCmd.AddCommand(rollout.NewCmdRolloutHistory(cfgFile))
 }
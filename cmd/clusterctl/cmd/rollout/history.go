@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/cmd/internal/templates"
+)
+
+// historyOptions is the start of the data required to perform the operation.
+type historyOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	resources         []string
+	namespace         string
+	revision          int64
+}
+
+var historyOpt = &historyOptions{}
+
+var (
+	historyLong = templates.LongDesc(`
+		Show the rollout history.
+
+	        For each revision, the MachineSet implementing the revision is listed together with the changes to the
+	        Machine template compared to the previous revision. Currently only MachineDeployments are supported.`)
+
+	historyExample = templates.Examples(`
+		# Show the revisions of a machinedeployment and the changes introduced by each revision
+		clusterctl alpha rollout history machinedeployment/my-md-0
+
+		# Show the changes introduced by revision 3 of a machinedeployment
+		clusterctl alpha rollout history machinedeployment/my-md-0 --revision=3`)
+)
+
+// NewCmdRolloutHistory returns a Command instance for 'rollout history' sub command.
+func NewCmdRolloutHistory(cfgFile string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "history RESOURCE",
+		DisableFlagsInUseLine: true,
+		Short:                 "Show the rollout history of a cluster-api resource",
+		Long:                  historyLong,
+		Example:               historyExample,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runHistory(cfgFile, args)
+		},
+	}
+	cmd.Flags().StringVar(&historyOpt.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig file to use for accessing the management cluster. If unspecified, default discovery rules apply.")
+	cmd.Flags().StringVar(&historyOpt.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	cmd.Flags().StringVarP(&historyOpt.namespace, "namespace", "n", "", "Namespace where the resource(s) reside. If unspecified, the defult namespace will be used.")
+	cmd.Flags().Int64Var(&historyOpt.revision, "revision", 0, "Show only the changes introduced by the given revision. Default to 0 (all revisions).")
+
+	return cmd
+}
+
+func runHistory(cfgFile string, args []string) error {
+	historyOpt.resources = args
+
+	ctx := context.Background()
+
+	c, err := client.New(ctx, cfgFile)
+	if err != nil {
+		return err
+	}
+
+	histories, err := c.RolloutHistory(ctx, client.RolloutHistoryOptions{
+		Kubeconfig: client.Kubeconfig{Path: historyOpt.kubeconfig, Context: historyOpt.kubeconfigContext},
+		Namespace:  historyOpt.namespace,
+		Resources:  historyOpt.resources,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, history := range histories {
+		if err := writeRolloutHistory(os.Stdout, history, historyOpt.revision, time.Now()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeRolloutHistory writes the revisions of a resource and the changes introduced by each revision;
+// if revision is not zero, only the changes introduced by the given revision are written.
+func writeRolloutHistory(w io.Writer, history client.RolloutHistory, revision int64, now time.Time) error {
+	key := fmt.Sprintf("%s/%s", history.Object.Kind, history.Object.Name)
+
+	if revision != 0 {
+		for _, rev := range history.Revisions {
+			if rev.Revision != revision {
+				continue
+			}
+			fmt.Fprintf(w, "%s with revision #%d (MachineSet %s)\n", key, rev.Revision, rev.MachineSet)
+			writeRevisionChanges(w, rev.Changes)
+			return nil
+		}
+		return errors.Errorf("unable to find revision %d of %s", revision, key)
+	}
+
+	fmt.Fprintln(w, key)
+	tw := tabwriter.NewWriter(w, 10, 4, 3, ' ', 0)
+	fmt.Fprintln(tw, "REVISION\tMACHINESET\tREPLICAS\tREADY\tAGE")
+	for _, rev := range history.Revisions {
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%d\t%s\n", rev.Revision, rev.MachineSet, rev.Replicas, rev.ReadyReplicas, duration.HumanDuration(now.Sub(rev.CreationTimestamp.Time)))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for i, rev := range history.Revisions {
+		// The first revision has nothing to be compared with.
+		if i == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\nRevision #%d changes:\n", rev.Revision)
+		writeRevisionChanges(w, rev.Changes)
+	}
+	fmt.Fprintln(w, "")
+	return nil
+}
+
+func writeRevisionChanges(w io.Writer, changes []string) {
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "  No changes to the Machine template")
+		return
+	}
+	fmt.Fprintf(w, "  %s\n", strings.Join(changes, "\n  "))
+}
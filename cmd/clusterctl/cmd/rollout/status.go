@@ -0,0 +1,190 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/cmd/internal/templates"
+)
+
+// statusOptions is the start of the data required to perform the operation.
+type statusOptions struct {
+	kubeconfig          string
+	kubeconfigContext   string
+	resources           []string
+	namespace           string
+	watch               bool
+	timeout             time.Duration
+	stuckMachineTimeout time.Duration
+}
+
+var statusOpt = &statusOptions{}
+
+// rolloutStatusPollInterval is the time between two checks of the rollout status.
+var rolloutStatusPollInterval = 2 * time.Second
+
+var (
+	statusLong = templates.LongDesc(`
+		Show the status of the rollout.
+
+	        By default, the command waits until the rollout completes, printing the replica progress and the Machines
+	        that are failed or that did not complete provisioning or deletion in the expected time. The command fails
+	        if the rollout can't complete without a manual intervention, e.g. because of failed Machines, or if the
+	        resource is paused. Currently only MachineDeployments and KubeadmControlPlanes are supported.`)
+
+	statusExample = templates.Examples(`
+		# Wait for the rollout of a machinedeployment to complete
+		clusterctl alpha rollout status machinedeployment/my-md-0
+
+		# Show the current status of the rollout of a kubeadmcontrolplane, without waiting
+		clusterctl alpha rollout status kubeadmcontrolplane/my-kcp --watch=false
+
+		# Wait for the rollout to complete for up to 30 minutes
+		clusterctl alpha rollout status machinedeployment/my-md-0 --timeout=30m`)
+)
+
+// NewCmdRolloutStatus returns a Command instance for 'rollout status' sub command.
+func NewCmdRolloutStatus(cfgFile string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "status RESOURCE",
+		DisableFlagsInUseLine: true,
+		Short:                 "Show the status of the rollout of a cluster-api resource",
+		Long:                  statusLong,
+		Example:               statusExample,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runStatus(cfgFile, args)
+		},
+	}
+	cmd.Flags().StringVar(&statusOpt.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig file to use for accessing the management cluster. If unspecified, default discovery rules apply.")
+	cmd.Flags().StringVar(&statusOpt.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	cmd.Flags().StringVarP(&statusOpt.namespace, "namespace", "n", "", "Namespace where the resource(s) reside. If unspecified, the defult namespace will be used.")
+	cmd.Flags().BoolVarP(&statusOpt.watch, "watch", "w", true,
+		"Watch the status of the rollout until it completes.")
+	cmd.Flags().DurationVar(&statusOpt.timeout, "timeout", 0,
+		"The length of time to wait for the rollout to complete. Zero means wait forever.")
+	cmd.Flags().DurationVar(&statusOpt.stuckMachineTimeout, "stuck-machine-timeout", 10*time.Minute,
+		"The length of time after which Machines that are not running, or that are still deleting, are reported as stuck.")
+
+	return cmd
+}
+
+func runStatus(cfgFile string, args []string) error {
+	statusOpt.resources = args
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if statusOpt.timeout > 0 {
+		var timeoutCancel context.CancelFunc
+		ctx, timeoutCancel = context.WithTimeout(ctx, statusOpt.timeout)
+		defer timeoutCancel()
+	}
+
+	c, err := client.New(ctx, cfgFile)
+	if err != nil {
+		return err
+	}
+
+	return waitForRollout(ctx, os.Stdout, statusOpt.watch, func() ([]client.RolloutStatus, error) {
+		return c.RolloutStatus(ctx, client.RolloutStatusOptions{
+			Kubeconfig:          client.Kubeconfig{Path: statusOpt.kubeconfig, Context: statusOpt.kubeconfigContext},
+			Namespace:           statusOpt.namespace,
+			Resources:           statusOpt.resources,
+			StuckMachineTimeout: statusOpt.stuckMachineTimeout,
+		})
+	})
+}
+
+// waitForRollout prints the rollout status every time it changes, until all the rollouts are completed.
+// If watch is false, the rollout status is printed only once.
+func waitForRollout(ctx context.Context, w io.Writer, watch bool, getStatus func() ([]client.RolloutStatus, error)) error {
+	printed := map[string]string{}
+	for {
+		statuses, err := getStatus()
+		if err != nil {
+			return err
+		}
+
+		done := true
+		for _, status := range statuses {
+			key := fmt.Sprintf("%s/%s", status.Object.Kind, status.Object.Name)
+
+			// NOTE: durations are not considered when detecting changes, so the status is not printed again on every check.
+			if summary := rolloutStatusSummary(status); printed[key] != summary {
+				printed[key] = summary
+				writeRolloutStatus(w, status)
+			}
+
+			if status.Failed {
+				return errors.Errorf("rollout of %s failed: some Machines reported a failure and require a manual intervention", key)
+			}
+			if !status.Done {
+				done = false
+				if watch && status.Paused {
+					return errors.Errorf("rollout of %s is paused: please run 'clusterctl alpha rollout resume %s' first", key, key)
+				}
+			}
+		}
+
+		if done || !watch {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return errors.New("timed out waiting for the rollout to complete")
+			}
+			return nil
+		case <-time.After(rolloutStatusPollInterval):
+		}
+	}
+}
+
+func writeRolloutStatus(w io.Writer, status client.RolloutStatus) {
+	fmt.Fprintln(w, status.Message)
+	for _, m := range status.StuckMachines {
+		if m.Failed {
+			fmt.Fprintf(w, "  Machine %s failed: %s\n", m.Name, m.Reason)
+			continue
+		}
+		fmt.Fprintf(w, "  Machine %s has been in phase %s for %s: %s\n", m.Name, m.Phase, duration.HumanDuration(m.Duration), m.Reason)
+	}
+}
+
+func rolloutStatusSummary(status client.RolloutStatus) string {
+	summary := []string{status.Message}
+	for _, m := range status.StuckMachines {
+		summary = append(summary, fmt.Sprintf("%s/%s/%s", m.Name, m.Phase, m.Reason))
+	}
+	return strings.Join(summary, "\n")
+}
@@ -38,14 +38,24 @@ var undoOpt = &undoOptions{}
 
 var (
 	undoLong = templates.LongDesc(`
-		Rollback to a previous rollout.`)
+		Rollback to a previous rollout.
+
+	        MachineDeployments can be rolled back to any revision implemented by one of their MachineSets.
+	        KubeadmControlPlanes and MachinePools can be rolled back to any revision recorded by their controller
+	        in a ControllerRevision.`)
 
 	undoExample = templates.Examples(`
 		# Rollback to the previous deployment
 		clusterctl alpha rollout undo machinedeployment/my-md-0
 
 		# Rollback to previous machinedeployment --to-revision=3
-		clusterctl alpha rollout undo machinedeployment/my-md-0 --to-revision=3`)
+		clusterctl alpha rollout undo machinedeployment/my-md-0 --to-revision=3
+
+		# Rollback a kubeadmcontrolplane to the previous revision
+		clusterctl alpha rollout undo kubeadmcontrolplane/my-kcp
+
+		# Rollback a machinepool to revision 2
+		clusterctl alpha rollout undo machinepool/my-mp --to-revision=2`)
 )
 
 // NewCmdRolloutUndo returns a Command instance for 'rollout undo' sub command.
@@ -40,6 +40,8 @@ type upgradeApplyOptions struct {
 	addonProviders            []string
 	waitProviders             bool
 	waitProviderTimeout       int
+	crdMigrationConcurrency   int
+	dryRun                    bool
 }
 
 var ua = &upgradeApplyOptions{}
@@ -61,7 +63,14 @@ var upgradeApplyCmd = &cobra.Command{
 		clusterctl upgrade apply --contract v1alpha4
 
 		# Upgrades only the aws provider to the v2.0.1 version.
-		clusterctl upgrade apply --infrastructure aws:v2.0.1`),
+		clusterctl upgrade apply --infrastructure aws:v2.0.1
+
+		# Reports the CRs to be migrated to the storage version of the new CRDs when upgrading all the providers,
+		# without changing the management cluster.
+		clusterctl upgrade apply --contract v1beta1 --dry-run
+
+		# Upgrades all the providers in the management cluster, migrating up to 10 CRs concurrently.
+		clusterctl upgrade apply --contract v1beta1 --crd-migration-concurrency 10`),
 	Args: cobra.NoArgs,
 	RunE: func(*cobra.Command, []string) error {
 		return runUpgradeApply()
@@ -94,6 +103,10 @@ func init() {
 		"Wait for providers to be upgraded.")
 	upgradeApplyCmd.Flags().IntVar(&ua.waitProviderTimeout, "wait-provider-timeout", 5*60,
 		"Wait timeout per provider upgrade in seconds. This value is ignored if --wait-providers is false")
+	upgradeApplyCmd.Flags().IntVar(&ua.crdMigrationConcurrency, "crd-migration-concurrency", 1,
+		"Number of CRs migrated concurrently to the storage version of the new CRDs.")
+	upgradeApplyCmd.Flags().BoolVar(&ua.dryRun, "dry-run", false,
+		"If true, clusterctl will only report the CRs to be migrated to the storage version of the new CRDs, without upgrading the providers.")
 }
 
 func runUpgradeApply() error {
@@ -131,5 +144,7 @@ func runUpgradeApply() error {
 		AddonProviders:            ua.addonProviders,
 		WaitProviders:             ua.waitProviders,
 		WaitProviderTimeout:       time.Duration(ua.waitProviderTimeout) * time.Second,
+		CRDMigrationConcurrency:   ua.crdMigrationConcurrency,
+		DryRun:                    ua.dryRun,
 	})
 }
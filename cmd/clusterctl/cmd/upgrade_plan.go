@@ -32,6 +32,9 @@ import (
 type upgradePlanOptions struct {
 	kubeconfig        string
 	kubeconfigContext string
+	contexts          []string
+	concurrency       int
+	diff              bool
 }
 
 var up = &upgradePlanOptions{}
@@ -48,11 +51,27 @@ var upgradePlanCmd = &cobra.Command{
 
 		Then, for each provider, the following upgrade options are provided:
 		- The latest patch release for the current API Version of Cluster API (contract).
-		- The latest patch release for the next API Version of Cluster API (contract), if available.`),
+		- The latest patch release for the next API Version of Cluster API (contract), if available.
+
+		Use the --diff flag to show, for each provider, the changes to CRDs, RBAC and Deployments
+		that the upgrade would apply, as well as the variables newly required by the target version.
+
+		Use the --contexts flag to plan the upgrade of a fleet of management clusters, one for each context
+		of the kubeconfig file; up to --concurrency management clusters are processed in parallel, and a consolidated report
+		of the provider versions, contracts, available upgrades and health of all of them is printed.`),
 
 	Example: templates.Examples(`
 		# Gets the recommended target versions for upgrading Cluster API providers.
-		clusterctl upgrade plan`),
+		clusterctl upgrade plan
+
+		# Gets the recommended target versions and the changes each upgrade would apply to the providers.
+		clusterctl upgrade plan --diff
+
+		# Gets the recommended target versions for upgrading the management clusters of the prod-1 and prod-2 contexts.
+		clusterctl upgrade plan --contexts prod-1,prod-2
+
+		# Gets the recommended target versions for upgrading the management clusters of many contexts, ten at a time.
+		clusterctl upgrade plan --contexts prod-1,prod-2,prod-3,prod-4 --concurrency 10`),
 
 	RunE: func(*cobra.Command, []string) error {
 		return runUpgradePlan()
@@ -64,9 +83,19 @@ func init() {
 		"Path to the kubeconfig file to use for accessing the management cluster. If empty, default discovery rules apply.")
 	upgradePlanCmd.Flags().StringVar(&up.kubeconfigContext, "kubeconfig-context", "",
 		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	upgradePlanCmd.Flags().StringSliceVar(&up.contexts, "contexts", nil,
+		"Contexts within the kubeconfig file of the management clusters of a fleet to plan the upgrade for. This flag can't be used in combination with --kubeconfig-context or --diff.")
+	upgradePlanCmd.Flags().IntVar(&up.concurrency, "concurrency", 5,
+		"Number of management clusters planned in parallel when using the --contexts flag.")
+	upgradePlanCmd.Flags().BoolVar(&up.diff, "diff", false,
+		"Show the changes to CRDs, RBAC, Deployments and required variables that upgrading each provider would apply.")
 }
 
 func runUpgradePlan() error {
+	if len(up.contexts) > 0 {
+		return runUpgradePlanFleet()
+	}
+
 	ctx := context.Background()
 
 	c, err := client.New(ctx, cfgFile)
@@ -90,6 +119,7 @@ func runUpgradePlan() error {
 
 	upgradePlans, err := c.PlanUpgrade(ctx, client.PlanUpgradeOptions{
 		Kubeconfig: client.Kubeconfig{Path: up.kubeconfig, Context: up.kubeconfigContext},
+		Diff:       up.diff,
 	})
 
 	if err != nil {
@@ -126,6 +156,10 @@ func runUpgradePlan() error {
 		}
 		fmt.Println("")
 
+		if up.diff && upgradeAvailable {
+			writeProviderDiffs(os.Stdout, plan.Diffs)
+		}
+
 		if upgradeAvailable {
 			if plan.Contract == clusterv1.GroupVersion.Version {
 				fmt.Println("You can now apply the upgrade by executing the following command:")
@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+)
+
+// writeProviderDiffs writes, for each provider with a next version, the changes the upgrade would apply.
+// Objects are prefixed with + if added, - if removed and ~ if changed.
+func writeProviderDiffs(w io.Writer, diffs []client.ProviderDiff) {
+	// ensure providers are sorted consistently (by Type, Name, Namespace), like in the upgrade plan table.
+	sort.SliceStable(diffs, func(i, j int) bool {
+		if diffs[i].Type != diffs[j].Type {
+			return diffs[i].Type < diffs[j].Type
+		}
+		if diffs[i].Name != diffs[j].Name {
+			return diffs[i].Name < diffs[j].Name
+		}
+		return diffs[i].Namespace < diffs[j].Namespace
+	})
+
+	for _, diff := range diffs {
+		if diff.NextVersion == "" {
+			continue
+		}
+
+		fmt.Fprintf(w, "Changes for upgrading %s from %s to %s:\n", diff.InstanceName(), diff.Version, diff.NextVersion)
+		if diff.IsEmpty() {
+			fmt.Fprintf(w, "  No changes to CRDs, RBAC, Deployments or required variables\n\n")
+			continue
+		}
+
+		writeObjectDiffs(w, "CustomResourceDefinitions", diff.CustomResourceDefinitions)
+		writeObjectDiffs(w, "RBAC", diff.RBAC)
+		writeObjectDiffs(w, "Deployments", diff.Deployments)
+
+		if len(diff.Variables) > 0 {
+			fmt.Fprintf(w, "  Newly required variables:\n")
+			for _, v := range diff.Variables {
+				status := "set"
+				if !v.Set {
+					status = "not set"
+				}
+				fmt.Fprintf(w, "    + %s (%s)\n", v.Name, status)
+			}
+		}
+		fmt.Fprintln(w, "")
+	}
+}
+
+func writeObjectDiffs(w io.Writer, title string, objDiffs []cluster.ObjectDiff) {
+	if len(objDiffs) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "  %s:\n", title)
+	for _, objDiff := range objDiffs {
+		name := objDiff.Name
+		if objDiff.Namespace != "" {
+			name = fmt.Sprintf("%s/%s", objDiff.Namespace, objDiff.Name)
+		}
+		fmt.Fprintf(w, "    %s %s %s\n", objectDiffOperationSymbol(objDiff.Operation), objDiff.Kind, name)
+		for _, change := range objDiff.Changes {
+			fmt.Fprintf(w, "        %s\n", change)
+		}
+	}
+}
+
+func objectDiffOperationSymbol(operation cluster.ObjectDiffOperation) string {
+	switch operation {
+	case cluster.ObjectAdded:
+		return "+"
+	case cluster.ObjectRemoved:
+		return "-"
+	default:
+		return "~"
+	}
+}
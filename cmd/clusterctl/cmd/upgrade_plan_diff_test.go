@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+)
+
+func Test_writeProviderDiffs(t *testing.T) {
+	g := NewWithT(t)
+
+	provider := func(name string, providerType clusterctlv1.ProviderType) clusterctlv1.Provider {
+		return clusterctlv1.Provider{
+			ObjectMeta:   metav1.ObjectMeta{Namespace: name + "-system", Name: clusterctlv1.ManifestLabel(name, providerType)},
+			ProviderName: name,
+			Type:         string(providerType),
+			Version:      "v1.0.0",
+		}
+	}
+
+	diffs := []client.ProviderDiff{
+		{
+			UpgradeItem: cluster.UpgradeItem{Provider: provider("infra", clusterctlv1.InfrastructureProviderType), NextVersion: "v1.1.0"},
+			CustomResourceDefinitions: []cluster.ObjectDiff{
+				{Kind: "CustomResourceDefinition", Name: "foos.infrastructure.cluster.x-k8s.io", Operation: cluster.ObjectChanged, Changes: []string{"version v1beta2 added"}},
+			},
+			RBAC: []cluster.ObjectDiff{
+				{Kind: "Role", Namespace: "infra-system", Name: "leader-election-role", Operation: cluster.ObjectRemoved},
+			},
+			Variables: []cluster.VariableDiff{{Name: "TOKEN"}},
+		},
+		{
+			UpgradeItem: cluster.UpgradeItem{Provider: provider("cluster-api", clusterctlv1.CoreProviderType), NextVersion: "v1.0.1"},
+		},
+		{
+			UpgradeItem: cluster.UpgradeItem{Provider: provider("kubeadm", clusterctlv1.BootstrapProviderType)},
+		},
+	}
+
+	var out bytes.Buffer
+	writeProviderDiffs(&out, diffs)
+
+	g.Expect(out.String()).To(Equal(`Changes for upgrading cluster-api-system/cluster-api from v1.0.0 to v1.0.1:
+  No changes to CRDs, RBAC, Deployments or required variables
+
+Changes for upgrading infra-system/infrastructure-infra from v1.0.0 to v1.1.0:
+  CustomResourceDefinitions:
+    ~ CustomResourceDefinition foos.infrastructure.cluster.x-k8s.io
+        version v1beta2 added
+  RBAC:
+    - Role infra-system/leader-election-role
+  Newly required variables:
+    + TOKEN (not set)
+
+`))
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+)
+
+func runUpgradePlanFleet() error {
+	ctx := context.Background()
+
+	if up.kubeconfigContext != "" {
+		return errors.New("the --contexts flag can't be used in combination with --kubeconfig-context")
+	}
+	if up.diff {
+		return errors.New("the --contexts flag can't be used in combination with --diff")
+	}
+	if up.concurrency < 1 {
+		return errors.New("the --concurrency flag must be greater than 0")
+	}
+
+	c, err := client.New(ctx, cfgFile)
+	if err != nil {
+		return err
+	}
+
+	kubeconfigs := make([]client.Kubeconfig, 0, len(up.contexts))
+	for _, kubeconfigContext := range up.contexts {
+		kubeconfigs = append(kubeconfigs, client.Kubeconfig{Path: up.kubeconfig, Context: kubeconfigContext})
+	}
+
+	plans, err := c.PlanFleetUpgrade(ctx, client.PlanFleetUpgradeOptions{
+		Kubeconfigs: kubeconfigs,
+		Concurrency: up.concurrency,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := writeFleetUpgradePlans(os.Stdout, plans); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, plan := range plans {
+		if plan.Error != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("failed to plan the upgrade of %d of %d management clusters", failed, len(plans))
+	}
+	return nil
+}
+
+// writeFleetUpgradePlans writes a consolidated report of the management clusters of a fleet, with the
+// cert-manager upgrade and the health of each management cluster, followed by the provider versions,
+// contracts and available upgrades of all the management clusters.
+func writeFleetUpgradePlans(out io.Writer, plans []client.FleetUpgradePlan) error {
+	w := tabwriter.NewWriter(out, 10, 4, 3, ' ', 0)
+	fmt.Fprintln(w, "MANAGEMENT CLUSTER\tCERT-MANAGER\tHEALTH\tSTATUS")
+	for _, plan := range plans {
+		if plan.Error != nil {
+			fmt.Fprintf(w, "%s\t-\t-\tError: %s\n", fleetManagementClusterName(plan.Kubeconfig), plan.Error)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\tOK\n", fleetManagementClusterName(plan.Kubeconfig), fleetCertManagerStatus(plan.CertManager), fleetHealthStatus(plan.Health))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "")
+
+	w = tabwriter.NewWriter(out, 10, 4, 3, ' ', 0)
+	fmt.Fprintln(w, "MANAGEMENT CLUSTER\tNAME\tNAMESPACE\tTYPE\tCURRENT VERSION\tCONTRACT\tNEXT VERSION")
+	for _, plan := range plans {
+		// ensure upgrade plans are sorted consistently (by CoreProvider.Namespace, Contract).
+		sortUpgradePlans(plan.UpgradePlans)
+
+		for _, upgradePlan := range plan.UpgradePlans {
+			// ensure provider are sorted consistently (by Type, Name, Namespace).
+			sortUpgradeItems(upgradePlan)
+
+			for _, upgradeItem := range upgradePlan.Providers {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", fleetManagementClusterName(plan.Kubeconfig), upgradeItem.Provider.Name, upgradeItem.Provider.Namespace, upgradeItem.Provider.Type, upgradeItem.Provider.Version, upgradePlan.Contract, prettifyTargetVersion(upgradeItem.NextVersion))
+			}
+		}
+	}
+	return w.Flush()
+}
+
+// fleetManagementClusterName returns the name used to identify a management cluster in the fleet report.
+func fleetManagementClusterName(kubeconfig client.Kubeconfig) string {
+	if kubeconfig.Context != "" {
+		return kubeconfig.Context
+	}
+	if kubeconfig.Path != "" {
+		return kubeconfig.Path
+	}
+	return "(current context)"
+}
+
+func fleetCertManagerStatus(plan client.CertManagerUpgradePlan) string {
+	switch {
+	case plan.ExternallyManaged:
+		return "Externally managed"
+	case plan.ShouldUpgrade:
+		return fmt.Sprintf("%s -> %s", plan.From, plan.To)
+	default:
+		return fmt.Sprintf("%s (up to date)", plan.From)
+	}
+}
+
+func fleetHealthStatus(report *client.DoctorReport) string {
+	if report == nil {
+		return "-"
+	}
+	counts := map[cluster.DoctorCheckStatus]int{}
+	for _, result := range report.Results {
+		counts[result.Status]++
+	}
+	return fmt.Sprintf("%d passed, %d warnings, %d failed", counts[cluster.DoctorCheckPassed], counts[cluster.DoctorCheckWarning], counts[cluster.DoctorCheckFailed])
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+)
+
+func Test_writeFleetUpgradePlans(t *testing.T) {
+	g := NewWithT(t)
+
+	provider := func(name string, providerType clusterctlv1.ProviderType, version string) clusterctlv1.Provider {
+		return clusterctlv1.Provider{
+			ObjectMeta:   metav1.ObjectMeta{Namespace: name + "-system", Name: clusterctlv1.ManifestLabel(name, providerType)},
+			ProviderName: name,
+			Type:         string(providerType),
+			Version:      version,
+		}
+	}
+
+	plans := []client.FleetUpgradePlan{
+		{
+			Kubeconfig:  client.Kubeconfig{Context: "prod-1"},
+			CertManager: client.CertManagerUpgradePlan{From: "v1.14.0", To: "v1.15.0", ShouldUpgrade: true},
+			UpgradePlans: []client.UpgradePlan{
+				{
+					Contract: "v1beta1",
+					Providers: []cluster.UpgradeItem{
+						{Provider: provider("docker", clusterctlv1.InfrastructureProviderType, "v1.7.0"), NextVersion: "v1.8.1"},
+						{Provider: provider("cluster-api", clusterctlv1.CoreProviderType, "v1.7.0"), NextVersion: "v1.8.1"},
+					},
+				},
+			},
+			Health: &client.DoctorReport{
+				Results: []cluster.DoctorCheckResult{
+					{Status: cluster.DoctorCheckPassed},
+					{Status: cluster.DoctorCheckPassed},
+					{Status: cluster.DoctorCheckWarning},
+				},
+			},
+		},
+		{
+			Kubeconfig:  client.Kubeconfig{Context: "prod-2"},
+			CertManager: client.CertManagerUpgradePlan{From: "v1.15.0", To: "v1.15.0"},
+			UpgradePlans: []client.UpgradePlan{
+				{
+					Contract: "v1beta1",
+					Providers: []cluster.UpgradeItem{
+						{Provider: provider("cluster-api", clusterctlv1.CoreProviderType, "v1.8.1")},
+					},
+				},
+			},
+			Health: &client.DoctorReport{},
+		},
+		{
+			Kubeconfig: client.Kubeconfig{Context: "prod-3"},
+			Error:      errors.New("connection refused"),
+		},
+	}
+
+	out := &bytes.Buffer{}
+	g.Expect(writeFleetUpgradePlans(out, plans)).To(Succeed())
+	g.Expect(out.String()).To(Equal(`MANAGEMENT CLUSTER   CERT-MANAGER           HEALTH                           STATUS
+prod-1               v1.14.0 -> v1.15.0     2 passed, 1 warnings, 0 failed   OK
+prod-2               v1.15.0 (up to date)   0 passed, 0 warnings, 0 failed   OK
+prod-3               -                      -                                Error: connection refused
+
+MANAGEMENT CLUSTER   NAME                    NAMESPACE            TYPE                     CURRENT VERSION   CONTRACT   NEXT VERSION
+prod-1               cluster-api             cluster-api-system   CoreProvider             v1.7.0            v1beta1    v1.8.1
+prod-1               infrastructure-docker   docker-system        InfrastructureProvider   v1.7.0            v1beta1    v1.8.1
+prod-2               cluster-api             cluster-api-system   CoreProvider             v1.8.1            v1beta1    Already up to date
+`))
+}
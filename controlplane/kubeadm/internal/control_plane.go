@@ -253,6 +253,14 @@ func (c *ControlPlane) UpToDateMachines() collections.Machines {
 	return c.Machines.Difference(c.machinesNotUptoDate)
 }
 
+// MachinesUpdatableInPlace returns the machines whose changes required to be up to date with the control plane's
+// configuration could be applied in-place.
+func (c *ControlPlane) MachinesUpdatableInPlace(machines collections.Machines) collections.Machines {
+	return machines.Filter(func(machine *clusterv1.Machine) bool {
+		return CanUpdateInPlace(machine, c.KCP, &c.reconciliationTime, c.InfraResources, c.KubeadmConfigs)
+	})
+}
+
 // getInfraResources fetches the external infrastructure resource for each machine in the collection and returns a map of machine.Name -> infraResource.
 func getInfraResources(ctx context.Context, cl client.Client, machines collections.Machines) (map[string]*unstructured.Unstructured, error) {
 	result := map[string]*unstructured.Unstructured{}
@@ -0,0 +1,140 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/internal/inplace"
+	"sigs.k8s.io/cluster-api/util/collections"
+)
+
+// inPlaceUpdatesEnabled returns true if Machines needing rollout should be updated in-place when possible.
+func (r *KubeadmControlPlaneReconciler) inPlaceUpdatesEnabled() bool {
+	return feature.Gates.Enabled(feature.InPlaceUpdates) && r.RuntimeClient != nil
+}
+
+// rolloutControlPlaneInPlace rolls out Machines needing rollout by updating them in-place, one at a time, whenever
+// the Runtime Extension implementing the in-place update hooks accepts the update.
+// Machines that cannot be updated in-place, either because the required changes go beyond the Kubernetes version
+// and the KubeadmConfig or because the Runtime Extension declined the update, are replaced with a rolling update.
+func (r *KubeadmControlPlaneReconciler) rolloutControlPlaneInPlace(ctx context.Context, controlPlane *internal.ControlPlane, machinesRequireUpgrade collections.Machines) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Complete in-place updates already in progress first.
+	if machinesInProgress := machinesRequireUpgrade.Filter(inplace.IsUpdateInProgress); len(machinesInProgress) > 0 {
+		return r.updateMachineInPlace(ctx, controlPlane, machinesInProgress.Oldest())
+	}
+
+	machinesToReplace := machinesRequireUpgrade.Difference(
+		controlPlane.MachinesUpdatableInPlace(machinesRequireUpgrade).Filter(collections.Not(inplace.IsUpdateDeclined)),
+	)
+	if len(machinesToReplace) > 0 {
+		log.Info("Machines cannot be updated in-place, replacing them", "machines", machinesToReplace.Names())
+		return r.rollingUpdateControlPlane(ctx, controlPlane, machinesToReplace)
+	}
+
+	// Complete a pending scale down, e.g. after a replacement, before starting a new in-place update.
+	if int32(controlPlane.Machines.Len()) > *controlPlane.KCP.Spec.Replicas {
+		return r.scaleDownControlPlane(ctx, controlPlane, machinesRequireUpgrade)
+	}
+
+	// Start a new in-place update only if the control plane is healthy.
+	if result, err := r.preflightChecks(ctx, controlPlane); err != nil || !result.IsZero() {
+		return result, err
+	}
+
+	return r.updateMachineInPlace(ctx, controlPlane, machinesRequireUpgrade.Oldest())
+}
+
+// updateMachineInPlace updates a Machine in-place to the Kubernetes version and the KubeadmConfig of the KCP.
+func (r *KubeadmControlPlaneReconciler) updateMachineInPlace(ctx context.Context, controlPlane *internal.ControlPlane, machine *clusterv1.Machine) (ctrl.Result, error) {
+	kubeadmConfig, ok := controlPlane.GetKubeadmConfig(machine.Name)
+	if !ok {
+		return ctrl.Result{}, errors.Errorf("failed to update Machine %s in-place: KubeadmConfig not found", klog.KObj(machine))
+	}
+	infraMachine, ok := controlPlane.InfraResources[machine.Name]
+	if !ok {
+		return ctrl.Result{}, errors.Errorf("failed to update Machine %s in-place: InfrastructureMachine not found", klog.KObj(machine))
+	}
+
+	desiredMachine := machine.DeepCopy()
+	desiredMachine.Spec.Version = &controlPlane.KCP.Spec.Version
+	// Only update the ClusterConfiguration annotation if the machine already has it, consistent with computeDesiredMachine.
+	if _, ok := desiredMachine.Annotations[controlplanev1.KubeadmClusterConfigurationAnnotation]; ok {
+		clusterConfig, err := json.Marshal(controlPlane.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to marshal cluster configuration")
+		}
+		desiredMachine.Annotations[controlplanev1.KubeadmClusterConfigurationAnnotation] = string(clusterConfig)
+	}
+
+	desiredKubeadmConfig := kubeadmConfig.DeepCopy()
+	desiredKubeadmConfig.Spec = *internal.DesiredKubeadmConfigSpec(controlPlane.KCP, kubeadmConfig)
+
+	bootstrapConfig, err := kubeadmConfigToUnstructured(kubeadmConfig)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	desiredBootstrapConfig, err := kubeadmConfigToUnstructured(desiredKubeadmConfig)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	updater := &inplace.Updater{
+		Client:        r.Client,
+		RuntimeClient: r.RuntimeClient,
+	}
+	// NOTE: The Machine is copied so changes applied by the updater are not patched again at the end of the reconcile.
+	result, err := updater.Update(ctx, inplace.Request{
+		Cluster:                controlPlane.Cluster,
+		Machine:                machine.DeepCopy(),
+		DesiredMachine:         desiredMachine,
+		BootstrapConfig:        bootstrapConfig,
+		DesiredBootstrapConfig: desiredBootstrapConfig,
+		InfrastructureMachine:  infraMachine,
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if result.Declined {
+		return r.rollingUpdateControlPlane(ctx, controlPlane, collections.FromMachines(machine))
+	}
+	return ctrl.Result{RequeueAfter: result.RequeueAfter}, nil
+}
+
+func kubeadmConfigToUnstructured(kubeadmConfig *bootstrapv1.KubeadmConfig) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(kubeadmConfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to convert KubeadmConfig %s to unstructured", klog.KObj(kubeadmConfig))
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(bootstrapv1.GroupVersion.WithKind("KubeadmConfig"))
+	return u, nil
+}
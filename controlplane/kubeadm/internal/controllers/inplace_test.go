@@ -0,0 +1,221 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
+	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/feature"
+	fakeruntimeclient "sigs.k8s.io/cluster-api/internal/runtime/client/fake"
+	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestKubeadmControlPlaneReconciler_rolloutControlPlaneInPlace(t *testing.T) {
+	utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.InPlaceUpdates, true)
+
+	catalog := runtimecatalog.New()
+	_ = runtimehooksv1.AddToCatalog(catalog)
+	canUpdateMachineGVH, err := catalog.GroupVersionHook(runtimehooksv1.CanUpdateMachine)
+	if err != nil {
+		panic("unable to compute GVH")
+	}
+	updateMachineGVH, err := catalog.GroupVersionHook(runtimehooksv1.UpdateMachine)
+	if err != nil {
+		panic("unable to compute GVH")
+	}
+
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = bootstrapv1.AddToScheme(scheme)
+
+	kcp := &controlplanev1.KubeadmControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kcp",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: controlplanev1.KubeadmControlPlaneSpec{
+			Replicas: ptr.To[int32](1),
+			Version:  "v1.32.0",
+			MachineTemplate: controlplanev1.KubeadmControlPlaneMachineTemplate{
+				InfrastructureRef: corev1.ObjectReference{APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1", Kind: "GenericMachineTemplate", Name: "template1"},
+			},
+			KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+				Files: []bootstrapv1.File{{Path: "/etc/desired"}},
+			},
+		},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machine1",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: "cluster",
+			Version:     ptr.To("v1.31.0"),
+			Bootstrap: clusterv1.Bootstrap{
+				ConfigRef: &corev1.ObjectReference{APIVersion: bootstrapv1.GroupVersion.String(), Kind: "KubeadmConfig", Name: "machine1"},
+			},
+		},
+		Status: clusterv1.MachineStatus{
+			NodeRef: &corev1.ObjectReference{Kind: "Node", Name: "node1"},
+			Conditions: clusterv1.Conditions{
+				*conditions.TrueCondition(controlplanev1.MachineAPIServerPodHealthyCondition),
+				*conditions.TrueCondition(controlplanev1.MachineControllerManagerPodHealthyCondition),
+				*conditions.TrueCondition(controlplanev1.MachineSchedulerPodHealthyCondition),
+				*conditions.TrueCondition(controlplanev1.MachineEtcdPodHealthyCondition),
+				*conditions.TrueCondition(controlplanev1.MachineEtcdMemberHealthyCondition),
+			},
+		},
+	}
+	kubeadmConfig := &bootstrapv1.KubeadmConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machine1",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: bootstrapv1.KubeadmConfigSpec{
+			Files: []bootstrapv1.File{{Path: "/etc/current"}},
+		},
+	}
+	infraMachine := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "GenericMachine",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+			"metadata": map[string]interface{}{
+				"name":      "machine1",
+				"namespace": metav1.NamespaceDefault,
+				"annotations": map[string]interface{}{
+					clusterv1.TemplateClonedFromNameAnnotation:      "template1",
+					clusterv1.TemplateClonedFromGroupKindAnnotation: "GenericMachineTemplate.infrastructure.cluster.x-k8s.io",
+				},
+			},
+		},
+	}
+
+	inProgressMachine := machine.DeepCopy()
+	inProgressMachine.Annotations = map[string]string{clusterv1.UpdateInProgressAnnotation: ""}
+
+	tests := []struct {
+		name                     string
+		machine                  *clusterv1.Machine
+		getAllExtensionResponses map[runtimecatalog.GroupVersionHook][]string
+		callExtensionResponses   map[string]runtimehooksv1.ResponseObject
+		wantResult               ctrl.Result
+		wantVersion              string
+		wantFiles                []bootstrapv1.File
+	}{
+		{
+			name:    "Update the Machine in-place if the Runtime Extension accepts the update",
+			machine: machine,
+			getAllExtensionResponses: map[runtimecatalog.GroupVersionHook][]string{
+				canUpdateMachineGVH: {"can-update-machine"},
+				updateMachineGVH:    {"update-machine"},
+			},
+			callExtensionResponses: map[string]runtimehooksv1.ResponseObject{
+				"can-update-machine": &runtimehooksv1.CanUpdateMachineResponse{
+					CommonResponse: runtimehooksv1.CommonResponse{Status: runtimehooksv1.ResponseStatusSuccess},
+					Accepted:       true,
+				},
+				"update-machine": &runtimehooksv1.UpdateMachineResponse{
+					CommonRetryResponse: runtimehooksv1.CommonRetryResponse{
+						CommonResponse: runtimehooksv1.CommonResponse{Status: runtimehooksv1.ResponseStatusSuccess},
+					},
+				},
+			},
+			wantResult:  ctrl.Result{},
+			wantVersion: "v1.32.0",
+			wantFiles:   []bootstrapv1.File{{Path: "/etc/desired"}},
+		},
+		{
+			name:    "Requeue while the in-place update is in progress",
+			machine: inProgressMachine,
+			getAllExtensionResponses: map[runtimecatalog.GroupVersionHook][]string{
+				updateMachineGVH: {"update-machine"},
+			},
+			callExtensionResponses: map[string]runtimehooksv1.ResponseObject{
+				"update-machine": &runtimehooksv1.UpdateMachineResponse{
+					CommonRetryResponse: runtimehooksv1.CommonRetryResponse{
+						CommonResponse:    runtimehooksv1.CommonResponse{Status: runtimehooksv1.ResponseStatusSuccess},
+						RetryAfterSeconds: 10,
+					},
+				},
+			},
+			wantResult:  ctrl.Result{RequeueAfter: 10 * time.Second},
+			wantVersion: "v1.31.0",
+			wantFiles:   []bootstrapv1.File{{Path: "/etc/current"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+
+			m := tt.machine.DeepCopy()
+			c := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(m, kubeadmConfig.DeepCopy()).
+				WithStatusSubresource(&clusterv1.Machine{}).
+				Build()
+
+			r := &KubeadmControlPlaneReconciler{
+				Client: c,
+				RuntimeClient: fakeruntimeclient.NewRuntimeClientBuilder().
+					WithCatalog(catalog).
+					WithGetAllExtensionResponses(tt.getAllExtensionResponses).
+					WithCallExtensionResponses(tt.callExtensionResponses).
+					Build(),
+			}
+			controlPlane := &internal.ControlPlane{
+				Cluster:        &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: metav1.NamespaceDefault}},
+				KCP:            kcp,
+				Machines:       collections.FromMachines(m),
+				KubeadmConfigs: map[string]*bootstrapv1.KubeadmConfig{m.Name: kubeadmConfig.DeepCopy()},
+				InfraResources: map[string]*unstructured.Unstructured{m.Name: infraMachine},
+			}
+
+			result, err := r.rolloutControlPlaneInPlace(ctx, controlPlane, collections.FromMachines(m))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(result).To(BeComparableTo(tt.wantResult))
+
+			gotMachine := &clusterv1.Machine{}
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(m), gotMachine)).To(Succeed())
+			g.Expect(*gotMachine.Spec.Version).To(Equal(tt.wantVersion))
+
+			gotKubeadmConfig := &bootstrapv1.KubeadmConfig{}
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(kubeadmConfig), gotKubeadmConfig)).To(Succeed())
+			g.Expect(gotKubeadmConfig.Spec.Files).To(Equal(tt.wantFiles))
+		})
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/internal/util/revision"
+	"sigs.k8s.io/cluster-api/util/labels/format"
+)
+
+// reconcileRevisionHistory records the parts of the KubeadmControlPlane spec driving a rollout, i.e. the Kubernetes
+// version, the infrastructure template and the kubeadm config spec, into a ControllerRevision each time they change,
+// so a rollout can be undone by restoring a previous revision, e.g. with clusterctl alpha rollout undo.
+func (r *KubeadmControlPlaneReconciler) reconcileRevisionHistory(ctx context.Context, controlPlane *internal.ControlPlane) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	data, err := kubeadmControlPlaneRevisionData(controlPlane.KCP)
+	if err != nil {
+		return err
+	}
+
+	labels := map[string]string{
+		clusterv1.ClusterNameLabel:             controlPlane.Cluster.Name,
+		clusterv1.MachineControlPlaneNameLabel: format.MustFormatValue(controlPlane.KCP.Name),
+	}
+	rev, err := revision.Record(ctx, r.Client, controlPlane.KCP, controlplanev1.GroupVersion.WithKind(kubeadmControlPlaneKind), labels, data, revision.DefaultHistoryLimit)
+	if err != nil {
+		return errors.Wrap(err, "failed to record the KubeadmControlPlane revision")
+	}
+	log.V(4).Info("Recorded KubeadmControlPlane revision", "revision", rev)
+	return nil
+}
+
+// kubeadmControlPlaneRevisionData returns the data recorded in a revision of a KubeadmControlPlane; this is a partial
+// KubeadmControlPlane object in JSON format, with only the fields of the spec restored on rollback.
+func kubeadmControlPlaneRevisionData(kcp *controlplanev1.KubeadmControlPlane) ([]byte, error) {
+	data, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"version": kcp.Spec.Version,
+			"machineTemplate": map[string]interface{}{
+				"infrastructureRef": kcp.Spec.MachineTemplate.InfrastructureRef,
+			},
+			"kubeadmConfigSpec": kcp.Spec.KubeadmConfigSpec,
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the KubeadmControlPlane revision")
+	}
+	return data, nil
+}
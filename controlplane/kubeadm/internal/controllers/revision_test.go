@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/internal/util/revision"
+)
+
+func TestKubeadmControlPlaneReconciler_reconcileRevisionHistory(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+	kcp := &controlplanev1.KubeadmControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kcp",
+			Namespace: metav1.NamespaceDefault,
+			UID:       "kcp-uid",
+		},
+		Spec: controlplanev1.KubeadmControlPlaneSpec{
+			Version: "v1.31.0",
+			MachineTemplate: controlplanev1.KubeadmControlPlaneMachineTemplate{
+				InfrastructureRef: corev1.ObjectReference{APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1", Kind: "GenericMachineTemplate", Name: "template1"},
+			},
+			KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+				Files: []bootstrapv1.File{{Path: "/etc/foo"}},
+			},
+		},
+	}
+	controlPlane := &internal.ControlPlane{
+		Cluster: cluster,
+		KCP:     kcp,
+	}
+
+	r := &KubeadmControlPlaneReconciler{
+		Client: fake.NewClientBuilder().Build(),
+	}
+
+	// The current spec is recorded as the first revision, and recording it again does not create a new revision.
+	g.Expect(r.reconcileRevisionHistory(ctx, controlPlane)).To(Succeed())
+	g.Expect(r.reconcileRevisionHistory(ctx, controlPlane)).To(Succeed())
+
+	// Changes to the spec not driving a rollout do not create a new revision.
+	kcp.Spec.Replicas = ptr.To[int32](3)
+	g.Expect(r.reconcileRevisionHistory(ctx, controlPlane)).To(Succeed())
+
+	// Changes to the spec driving a rollout create a new revision.
+	kcp.Spec.Version = "v1.32.0"
+	kcp.Spec.MachineTemplate.InfrastructureRef.Name = "template2"
+	g.Expect(r.reconcileRevisionHistory(ctx, controlPlane)).To(Succeed())
+
+	revisions, err := revision.List(ctx, r.Client, kcp, map[string]string{clusterv1.MachineControlPlaneNameLabel: kcp.Name})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(revisions).To(HaveLen(2))
+	g.Expect(revisions[0].Labels).To(HaveKeyWithValue(clusterv1.ClusterNameLabel, cluster.Name))
+
+	previous := &controlplanev1.KubeadmControlPlane{}
+	g.Expect(json.Unmarshal(revisions[0].Data.Raw, previous)).To(Succeed())
+	g.Expect(revisions[0].Revision).To(Equal(int64(1)))
+	g.Expect(previous.Spec.Version).To(Equal("v1.31.0"))
+	g.Expect(previous.Spec.MachineTemplate.InfrastructureRef.Name).To(Equal("template1"))
+	g.Expect(previous.Spec.KubeadmConfigSpec).To(Equal(kcp.Spec.KubeadmConfigSpec))
+
+	current := &controlplanev1.KubeadmControlPlane{}
+	g.Expect(json.Unmarshal(revisions[1].Data.Raw, current)).To(Succeed())
+	g.Expect(revisions[1].Revision).To(Equal(int64(2)))
+	g.Expect(current.Spec.Version).To(Equal("v1.32.0"))
+	g.Expect(current.Spec.MachineTemplate.InfrastructureRef.Name).To(Equal("template2"))
+}
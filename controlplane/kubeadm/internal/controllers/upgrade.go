@@ -117,15 +117,27 @@ func (r *KubeadmControlPlaneReconciler) upgradeControlPlane(
 	switch controlPlane.KCP.Spec.RolloutStrategy.Type {
 	case controlplanev1.RollingUpdateStrategyType:
 		// RolloutStrategy is currently defaulted and validated to be RollingUpdate
-		// We can ignore MaxUnavailable because we are enforcing health checks before we get here.
-		maxNodes := *controlPlane.KCP.Spec.Replicas + int32(controlPlane.KCP.Spec.RolloutStrategy.RollingUpdate.MaxSurge.IntValue())
-		if int32(controlPlane.Machines.Len()) < maxNodes {
-			// scaleUp ensures that we don't continue scaling up while waiting for Machines to have NodeRefs
-			return r.scaleUpControlPlane(ctx, controlPlane)
+		if r.inPlaceUpdatesEnabled() {
+			return r.rolloutControlPlaneInPlace(ctx, controlPlane, machinesRequireUpgrade)
 		}
-		return r.scaleDownControlPlane(ctx, controlPlane, machinesRequireUpgrade)
+		return r.rollingUpdateControlPlane(ctx, controlPlane, machinesRequireUpgrade)
 	default:
 		logger.Info("RolloutStrategy type is not set to RollingUpdateStrategyType, unable to determine the strategy for rolling out machines")
 		return ctrl.Result{}, nil
 	}
 }
+
+// rollingUpdateControlPlane replaces machinesRequireUpgrade by scaling up and then scaling down the control plane.
+func (r *KubeadmControlPlaneReconciler) rollingUpdateControlPlane(
+	ctx context.Context,
+	controlPlane *internal.ControlPlane,
+	machinesRequireUpgrade collections.Machines,
+) (ctrl.Result, error) {
+	// We can ignore MaxUnavailable because we are enforcing health checks before we get here.
+	maxNodes := *controlPlane.KCP.Spec.Replicas + int32(controlPlane.KCP.Spec.RolloutStrategy.RollingUpdate.MaxSurge.IntValue())
+	if int32(controlPlane.Machines.Len()) < maxNodes {
+		// scaleUp ensures that we don't continue scaling up while waiting for Machines to have NodeRefs
+		return r.scaleUpControlPlane(ctx, controlPlane)
+	}
+	return r.scaleDownControlPlane(ctx, controlPlane, machinesRequireUpgrade)
+}
@@ -111,6 +111,40 @@ func UpToDate(machine *clusterv1.Machine, kcp *controlplanev1.KubeadmControlPlan
 	return true, nil, nil, nil
 }
 
+// CanUpdateInPlace checks if the changes required to bring a Machine up to date with the control plane's configuration
+// could be applied in-place, i.e. if the changes are limited to the Kubernetes version and to the KubeadmConfig.
+// Machines whose certificates are about to expire or that are scheduled for rollout, as well as Machines whose
+// infrastructure template has been rotated, must always be replaced.
+func CanUpdateInPlace(machine *clusterv1.Machine, kcp *controlplanev1.KubeadmControlPlane, reconciliationTime *metav1.Time, infraConfigs map[string]*unstructured.Unstructured, machineConfigs map[string]*bootstrapv1.KubeadmConfig) bool {
+	if collections.ShouldRolloutBefore(reconciliationTime, kcp.Spec.RolloutBefore)(machine) ||
+		collections.ShouldRolloutAfter(reconciliationTime, kcp.Spec.RolloutAfter)(machine) {
+		return false
+	}
+
+	// The InfrastructureMachine and the KubeadmConfig are required by the in-place update hooks.
+	if _, ok := infraConfigs[machine.Name]; !ok {
+		return false
+	}
+	if _, ok := machineConfigs[machine.Name]; !ok {
+		return false
+	}
+
+	_, matches := matchesTemplateClonedFrom(infraConfigs, kcp, machine)
+	return matches
+}
+
+// DesiredKubeadmConfigSpec returns the KubeadmConfigSpec a Machine's KubeadmConfig should have to be up to date with
+// the control plane's configuration.
+// NOTE: JoinConfiguration.Discovery is preserved from the Machine's KubeadmConfig, because it is generated
+// by the kubeadm bootstrap provider when the Machine joins the control plane.
+func DesiredKubeadmConfigSpec(kcp *controlplanev1.KubeadmControlPlane, machineConfig *bootstrapv1.KubeadmConfig) *bootstrapv1.KubeadmConfigSpec {
+	desiredConfig := getAdjustedKcpConfig(kcp, machineConfig)
+	if desiredConfig.JoinConfiguration != nil && machineConfig.Spec.JoinConfiguration != nil {
+		desiredConfig.JoinConfiguration.Discovery = *machineConfig.Spec.JoinConfiguration.Discovery.DeepCopy()
+	}
+	return desiredConfig
+}
+
 // matchesTemplateClonedFrom checks if a Machine has a corresponding infrastructure machine that
 // matches a given KCP infra template and if it doesn't match returns the reason why.
 // Note: Differences to the labels and annotations on the infrastructure machine are not considered for matching
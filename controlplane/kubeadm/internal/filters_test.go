@@ -1592,3 +1592,139 @@ func TestUpToDate(t *testing.T) {
 		})
 	}
 }
+
+func TestCanUpdateInPlace(t *testing.T) {
+	reconciliationTime := metav1.Now()
+
+	defaultKcp := &controlplanev1.KubeadmControlPlane{
+		Spec: controlplanev1.KubeadmControlPlaneSpec{
+			Version: "v1.31.0",
+			MachineTemplate: controlplanev1.KubeadmControlPlaneMachineTemplate{
+				InfrastructureRef: corev1.ObjectReference{APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1", Kind: "AWSMachineTemplate", Name: "template1"},
+			},
+		},
+	}
+	defaultMachine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "machine1",
+			CreationTimestamp: metav1.Time{Time: reconciliationTime.Add(-2 * 24 * time.Hour)}, // two days ago.
+		},
+		Spec: clusterv1.MachineSpec{
+			Version: ptr.To("v1.30.0"),
+		},
+	}
+	defaultInfraConfigs := map[string]*unstructured.Unstructured{
+		defaultMachine.Name: {
+			Object: map[string]interface{}{
+				"kind":       "AWSMachine",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      "infra-config1",
+					"namespace": "default",
+					"annotations": map[string]interface{}{
+						"cluster.x-k8s.io/cloned-from-name":      "template1",
+						"cluster.x-k8s.io/cloned-from-groupkind": "AWSMachineTemplate.infrastructure.cluster.x-k8s.io",
+					},
+				},
+			},
+		},
+	}
+	defaultMachineConfigs := map[string]*bootstrapv1.KubeadmConfig{
+		defaultMachine.Name: {},
+	}
+
+	tests := []struct {
+		name           string
+		kcp            *controlplanev1.KubeadmControlPlane
+		infraConfigs   map[string]*unstructured.Unstructured
+		machineConfigs map[string]*bootstrapv1.KubeadmConfig
+		want           bool
+	}{
+		{
+			name:           "machine with only version and KubeadmConfig changes can be updated in-place",
+			kcp:            defaultKcp,
+			infraConfigs:   defaultInfraConfigs,
+			machineConfigs: defaultMachineConfigs,
+			want:           true,
+		},
+		{
+			name: "machine with expired rolloutAfter cannot be updated in-place",
+			kcp: func() *controlplanev1.KubeadmControlPlane {
+				kcp := defaultKcp.DeepCopy()
+				kcp.Spec.RolloutAfter = ptr.To(metav1.Time{Time: reconciliationTime.Add(-1 * 24 * time.Hour)}) // one day ago
+				return kcp
+			}(),
+			infraConfigs:   defaultInfraConfigs,
+			machineConfigs: defaultMachineConfigs,
+			want:           false,
+		},
+		{
+			name: "machine with rotated infrastructure template cannot be updated in-place",
+			kcp: func() *controlplanev1.KubeadmControlPlane {
+				kcp := defaultKcp.DeepCopy()
+				kcp.Spec.MachineTemplate.InfrastructureRef.Name = "template2"
+				return kcp
+			}(),
+			infraConfigs:   defaultInfraConfigs,
+			machineConfigs: defaultMachineConfigs,
+			want:           false,
+		},
+		{
+			name:           "machine without InfrastructureMachine cannot be updated in-place",
+			kcp:            defaultKcp,
+			infraConfigs:   map[string]*unstructured.Unstructured{},
+			machineConfigs: defaultMachineConfigs,
+			want:           false,
+		},
+		{
+			name:           "machine without KubeadmConfig cannot be updated in-place",
+			kcp:            defaultKcp,
+			infraConfigs:   defaultInfraConfigs,
+			machineConfigs: map[string]*bootstrapv1.KubeadmConfig{},
+			want:           false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(CanUpdateInPlace(defaultMachine, tt.kcp, &reconciliationTime, tt.infraConfigs, tt.machineConfigs)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestDesiredKubeadmConfigSpec(t *testing.T) {
+	g := NewWithT(t)
+
+	kcp := &controlplanev1.KubeadmControlPlane{
+		Spec: controlplanev1.KubeadmControlPlaneSpec{
+			KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+				InitConfiguration: &bootstrapv1.InitConfiguration{
+					NodeRegistration: bootstrapv1.NodeRegistrationOptions{Name: "init"},
+				},
+				JoinConfiguration: &bootstrapv1.JoinConfiguration{
+					NodeRegistration: bootstrapv1.NodeRegistrationOptions{Name: "join"},
+				},
+				Files: []bootstrapv1.File{{Path: "/etc/desired"}},
+			},
+		},
+	}
+	machineConfig := &bootstrapv1.KubeadmConfig{
+		Spec: bootstrapv1.KubeadmConfigSpec{
+			JoinConfiguration: &bootstrapv1.JoinConfiguration{
+				Discovery: bootstrapv1.Discovery{
+					BootstrapToken: &bootstrapv1.BootstrapTokenDiscovery{Token: "token"},
+				},
+			},
+			Files: []bootstrapv1.File{{Path: "/etc/current"}},
+		},
+	}
+
+	desiredSpec := DesiredKubeadmConfigSpec(kcp, machineConfig)
+	g.Expect(desiredSpec.InitConfiguration).To(BeNil())
+	g.Expect(desiredSpec.JoinConfiguration.NodeRegistration.Name).To(Equal("join"))
+	g.Expect(desiredSpec.JoinConfiguration.Discovery).To(Equal(machineConfig.Spec.JoinConfiguration.Discovery))
+	g.Expect(desiredSpec.Files).To(Equal([]bootstrapv1.File{{Path: "/etc/desired"}}))
+	// The KCP must not be changed.
+	g.Expect(kcp.Spec.KubeadmConfigSpec.JoinConfiguration.Discovery.BootstrapToken).To(BeNil())
+}
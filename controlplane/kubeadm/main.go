@@ -58,9 +58,16 @@ import (
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/etcd"
 	kcpwebhooks "sigs.k8s.io/cluster-api/controlplane/kubeadm/webhooks"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	runtimev1 "sigs.k8s.io/cluster-api/exp/runtime/api/v1alpha1"
+	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
+	runtimeclient "sigs.k8s.io/cluster-api/exp/runtime/client"
+	runtimecontrollers "sigs.k8s.io/cluster-api/exp/runtime/controllers"
+	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/feature"
 	controlplanev1alpha3 "sigs.k8s.io/cluster-api/internal/apis/controlplane/kubeadm/v1alpha3"
 	controlplanev1alpha4 "sigs.k8s.io/cluster-api/internal/apis/controlplane/kubeadm/v1alpha4"
+	internalruntimeclient "sigs.k8s.io/cluster-api/internal/runtime/client"
+	runtimeregistry "sigs.k8s.io/cluster-api/internal/runtime/registry"
 	"sigs.k8s.io/cluster-api/util/apiwarnings"
 	"sigs.k8s.io/cluster-api/util/flags"
 	"sigs.k8s.io/cluster-api/version"
@@ -68,6 +75,7 @@ import (
 
 var (
 	scheme         = runtime.NewScheme()
+	catalog        = runtimecatalog.New()
 	setupLog       = ctrl.Log.WithName("setup")
 	controllerName = "cluster-api-kubeadm-control-plane-manager"
 
@@ -108,6 +116,10 @@ func init() {
 	_ = controlplanev1alpha4.AddToScheme(scheme)
 	_ = controlplanev1.AddToScheme(scheme)
 	_ = bootstrapv1.AddToScheme(scheme)
+	_ = runtimev1.AddToScheme(scheme)
+
+	// Register the RuntimeHook types into the catalog.
+	_ = runtimehooksv1.AddToCatalog(catalog)
 	_ = apiextensionsv1.AddToScheme(scheme)
 }
 
@@ -228,6 +240,11 @@ func main() {
 		os.Exit(1)
 	}
 
+	if feature.Gates.Enabled(feature.InPlaceUpdates) && !feature.Gates.Enabled(feature.RuntimeSDK) {
+		setupLog.Error(errors.Errorf("the %s feature gate requires the %s feature gate to be enabled", feature.InPlaceUpdates, feature.RuntimeSDK), "Unable to start manager")
+		os.Exit(1)
+	}
+
 	tlsOptions, metricsOptions, err := flags.GetManagerOptions(managerOptions)
 	if err != nil {
 		setupLog.Error(err, "Unable to start manager: invalid flags")
@@ -272,6 +289,11 @@ func main() {
 				&corev1.Secret{}: {
 					Label: clusterSecretCacheSelector,
 				},
+				// Note: Only ControllerRevisions with the cluster name label, i.e. the ones recording the revision
+				// history of KubeadmControlPlanes, are cached.
+				&appsv1.ControllerRevision{}: {
+					Label: clusterSecretCacheSelector,
+				},
 			},
 		},
 		Client: client.Options{
@@ -387,9 +409,33 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		os.Exit(1)
 	}
 
+	var runtimeClient runtimeclient.Client
+	if feature.Gates.Enabled(feature.InPlaceUpdates) {
+		// This is the creation of the runtimeClient for the in-place update hooks, embedding a shared catalog and registry instance.
+		runtimeClient = internalruntimeclient.New(internalruntimeclient.Options{
+			Catalog:  catalog,
+			Registry: runtimeregistry.New(),
+			Client:   mgr.GetClient(),
+		})
+
+		// NOTE: ExtensionConfigs are discovered by the core Cluster API controller, here they are only registered
+		// into the registry used by the runtimeClient.
+		if err := (&runtimecontrollers.ExtensionConfigReconciler{
+			Client:           mgr.GetClient(),
+			APIReader:        mgr.GetAPIReader(),
+			RuntimeClient:    runtimeClient,
+			WatchFilterValue: watchFilterValue,
+			ReadOnly:         true,
+		}).SetupWithManager(ctx, mgr, controller.Options{}, nil); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ExtensionConfig")
+			os.Exit(1)
+		}
+	}
+
 	if err := (&kubeadmcontrolplanecontrollers.KubeadmControlPlaneReconciler{
 		Client:                      mgr.GetClient(),
 		SecretCachingClient:         secretCachingClient,
+		RuntimeClient:               runtimeClient,
 		ClusterCache:                clusterCache,
 		WatchFilterValue:            watchFilterValue,
 		EtcdDialTimeout:             etcdDialTimeout,
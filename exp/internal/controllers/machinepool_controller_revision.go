@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/util/revision"
+	"sigs.k8s.io/cluster-api/util/labels/format"
+)
+
+// reconcileRevisionHistory records the Machine template of the MachinePool into a ControllerRevision each time
+// it changes, so a rollout can be undone by restoring a previous revision, e.g. with clusterctl alpha rollout undo.
+// NOTE: Only the references to the bootstrap config and to the infrastructure MachinePool are recorded, not the
+// content of the referenced objects.
+func (r *MachinePoolReconciler) reconcileRevisionHistory(ctx context.Context, s *scope) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	mp := s.machinePool
+
+	data, err := machinePoolRevisionData(mp)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	labels := map[string]string{
+		clusterv1.ClusterNameLabel:     mp.Spec.ClusterName,
+		clusterv1.MachinePoolNameLabel: format.MustFormatValue(mp.Name),
+	}
+	rev, err := revision.Record(ctx, r.Client, mp, machinePoolKind, labels, data, revision.DefaultHistoryLimit)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to record the MachinePool revision")
+	}
+	log.V(4).Info("Recorded MachinePool revision", "revision", rev)
+	return ctrl.Result{}, nil
+}
+
+// machinePoolRevisionData returns the data recorded in a revision of a MachinePool; this is a partial MachinePool
+// object in JSON format, with only the Machine template.
+func machinePoolRevisionData(mp *expv1.MachinePool) ([]byte, error) {
+	template := mp.Spec.Template.DeepCopy()
+	// The bootstrap data secret name is set by the MachinePool controller from the bootstrap config,
+	// so it is not part of the revision unless it is set by the user.
+	if template.Spec.Bootstrap.ConfigRef != nil {
+		template.Spec.Bootstrap.DataSecretName = nil
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": template,
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the MachinePool revision")
+	}
+	return data, nil
+}
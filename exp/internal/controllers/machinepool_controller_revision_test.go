@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/util/revision"
+	"sigs.k8s.io/cluster-api/util/test/builder"
+)
+
+func TestMachinePoolReconciler_reconcileRevisionHistory(t *testing.T) {
+	g := NewWithT(t)
+
+	mp := &expv1.MachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machinepool-test",
+			Namespace: metav1.NamespaceDefault,
+			UID:       "machinepool-uid",
+		},
+		Spec: expv1.MachinePoolSpec{
+			ClusterName: clusterName,
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					Version: ptr.To("v1.31.0"),
+					Bootstrap: clusterv1.Bootstrap{
+						ConfigRef: &corev1.ObjectReference{
+							APIVersion: builder.BootstrapGroupVersion.String(),
+							Kind:       builder.TestBootstrapConfigKind,
+							Name:       "bootstrap-config1",
+						},
+					},
+					InfrastructureRef: corev1.ObjectReference{
+						APIVersion: builder.InfrastructureGroupVersion.String(),
+						Kind:       builder.TestInfrastructureMachineTemplateKind,
+						Name:       "infra-config1",
+					},
+				},
+			},
+		},
+	}
+	s := &scope{
+		machinePool: mp,
+	}
+
+	r := &MachinePoolReconciler{
+		Client: fake.NewClientBuilder().Build(),
+	}
+
+	// The current template is recorded as the first revision.
+	_, err := r.reconcileRevisionHistory(ctx, s)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The bootstrap data secret name set from the bootstrap config does not create a new revision.
+	mp.Spec.Template.Spec.Bootstrap.DataSecretName = ptr.To("bootstrap-data1")
+	_, err = r.reconcileRevisionHistory(ctx, s)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Changes to the template create a new revision.
+	mp.Spec.Template.Spec.Version = ptr.To("v1.32.0")
+	mp.Spec.Template.Spec.Bootstrap.ConfigRef.Name = "bootstrap-config2"
+	_, err = r.reconcileRevisionHistory(ctx, s)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	revisions, err := revision.List(ctx, r.Client, mp, map[string]string{clusterv1.MachinePoolNameLabel: mp.Name})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(revisions).To(HaveLen(2))
+	g.Expect(revisions[0].Labels).To(HaveKeyWithValue(clusterv1.ClusterNameLabel, clusterName))
+
+	previous := &expv1.MachinePool{}
+	g.Expect(json.Unmarshal(revisions[0].Data.Raw, previous)).To(Succeed())
+	g.Expect(revisions[0].Revision).To(Equal(int64(1)))
+	g.Expect(previous.Spec.Template.Spec.Version).To(Equal(ptr.To("v1.31.0")))
+	g.Expect(previous.Spec.Template.Spec.Bootstrap.ConfigRef.Name).To(Equal("bootstrap-config1"))
+	g.Expect(previous.Spec.Template.Spec.Bootstrap.DataSecretName).To(BeNil())
+	g.Expect(revisions[1].Revision).To(Equal(int64(2)))
+}
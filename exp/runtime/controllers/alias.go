@@ -36,6 +36,10 @@ type ExtensionConfigReconciler struct {
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
+
+	// ReadOnly configures the reconciler to register ExtensionConfigs as reported in status, without running
+	// discovery and without patching ExtensionConfigs; discovery is performed by the core Cluster API controller.
+	ReadOnly bool
 }
 
 func (r *ExtensionConfigReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options, partialSecretCache cache.Cache) error {
@@ -44,5 +48,6 @@ func (r *ExtensionConfigReconciler) SetupWithManager(ctx context.Context, mgr ct
 		APIReader:        r.APIReader,
 		RuntimeClient:    r.RuntimeClient,
 		WatchFilterValue: r.WatchFilterValue,
+		ReadOnly:         r.ReadOnly,
 	}).SetupWithManager(ctx, mgr, options, partialSecretCache)
 }
@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
+)
+
+// CanUpdateMachineRequest is the request of the CanUpdateMachine hook.
+// +kubebuilder:object:root=true
+type CanUpdateMachineRequest struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// CommonRequest contains fields common to all request types.
+	CommonRequest `json:",inline"`
+
+	// cluster is the cluster object the Machine belongs to.
+	Cluster clusterv1.Cluster `json:"cluster"`
+
+	// machine is the current Machine object.
+	Machine clusterv1.Machine `json:"machine"`
+
+	// desiredMachine is the Machine object with the desired spec.
+	DesiredMachine clusterv1.Machine `json:"desiredMachine"`
+
+	// bootstrapConfig is the current BootstrapConfig object of the Machine, if any.
+	// +optional
+	BootstrapConfig runtime.RawExtension `json:"bootstrapConfig,omitempty"`
+
+	// desiredBootstrapConfig is the BootstrapConfig object of the Machine with the desired spec, if any.
+	// +optional
+	DesiredBootstrapConfig runtime.RawExtension `json:"desiredBootstrapConfig,omitempty"`
+
+	// infrastructureMachine is the InfrastructureMachine object of the Machine.
+	InfrastructureMachine runtime.RawExtension `json:"infrastructureMachine"`
+}
+
+var _ ResponseObject = &CanUpdateMachineResponse{}
+
+// CanUpdateMachineResponse is the response of the CanUpdateMachine hook.
+// +kubebuilder:object:root=true
+type CanUpdateMachineResponse struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// CommonResponse contains Status and Message fields common to all response types.
+	CommonResponse `json:",inline"`
+
+	// accepted is true if the Runtime Extension can update the Machine in-place to the desired spec.
+	// If false, the Machine is going to be replaced, and message should report why the update was declined.
+	Accepted bool `json:"accepted"`
+}
+
+// CanUpdateMachine is the hook that will be called to determine if a Machine can be updated in-place
+// to a desired spec instead of being replaced.
+func CanUpdateMachine(*CanUpdateMachineRequest, *CanUpdateMachineResponse) {}
+
+// UpdateMachineRequest is the request of the UpdateMachine hook.
+// +kubebuilder:object:root=true
+type UpdateMachineRequest struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// CommonRequest contains fields common to all request types.
+	CommonRequest `json:",inline"`
+
+	// cluster is the cluster object the Machine belongs to.
+	Cluster clusterv1.Cluster `json:"cluster"`
+
+	// machine is the current Machine object.
+	Machine clusterv1.Machine `json:"machine"`
+
+	// desiredMachine is the Machine object with the desired spec.
+	DesiredMachine clusterv1.Machine `json:"desiredMachine"`
+
+	// bootstrapConfig is the current BootstrapConfig object of the Machine, if any.
+	// +optional
+	BootstrapConfig runtime.RawExtension `json:"bootstrapConfig,omitempty"`
+
+	// desiredBootstrapConfig is the BootstrapConfig object of the Machine with the desired spec, if any.
+	// +optional
+	DesiredBootstrapConfig runtime.RawExtension `json:"desiredBootstrapConfig,omitempty"`
+
+	// infrastructureMachine is the InfrastructureMachine object of the Machine.
+	InfrastructureMachine runtime.RawExtension `json:"infrastructureMachine"`
+}
+
+var _ RetryResponseObject = &UpdateMachineResponse{}
+
+// UpdateMachineResponse is the response of the UpdateMachine hook.
+// +kubebuilder:object:root=true
+type UpdateMachineResponse struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// CommonRetryResponse contains Status, Message and RetryAfterSeconds fields.
+	CommonRetryResponse `json:",inline"`
+}
+
+// UpdateMachine is the hook that will be called to update a Machine in-place to a desired spec.
+func UpdateMachine(*UpdateMachineRequest, *UpdateMachineResponse) {}
+
+func init() {
+	catalogBuilder.RegisterHook(CanUpdateMachine, &runtimecatalog.HookMeta{
+		Tags:    []string{"In-Place Update Hooks"},
+		Summary: "Cluster API Runtime will call this hook to determine if a Machine can be updated in-place",
+		Description: "Cluster API Runtime will call this hook when the spec of a Machine controlled by a MachineSet or " +
+			"a KubeadmControlPlane is outdated, and the change could be applied in-place instead of replacing the Machine.\n" +
+			"\n" +
+			"Notes:\n" +
+			"- This hook will be called only if the InPlaceUpdates feature flag is enabled\n" +
+			"- Only changes to the Kubernetes version and to the BootstrapConfig can be applied in-place; changes to the " +
+			"InfrastructureMachine always trigger a replacement of the Machine\n" +
+			"- The call's request contains the Cluster, the current and the desired Machine and BootstrapConfig, and the InfrastructureMachine\n" +
+			"- Only a single Runtime Extension can implement in-place updates\n" +
+			"- If the Runtime Extension does not accept the update, the Machine is replaced",
+	})
+
+	catalogBuilder.RegisterHook(UpdateMachine, &runtimecatalog.HookMeta{
+		Tags:    []string{"In-Place Update Hooks"},
+		Summary: "Cluster API Runtime will call this hook to update a Machine in-place",
+		Description: "Cluster API Runtime will call this hook after the Runtime Extension accepted the in-place update of a Machine " +
+			"through the CanUpdateMachine hook, until the update is completed.\n" +
+			"\n" +
+			"Notes:\n" +
+			"- This hook will be called only if the InPlaceUpdates feature flag is enabled\n" +
+			"- The call's request contains the Cluster, the current and the desired Machine and BootstrapConfig, and the InfrastructureMachine\n" +
+			"- This is a blocking hook; Runtime Extension implementers must return a RetryAfterSeconds greater than zero " +
+			"while the update is in progress; once the update is completed the desired spec is applied to the Machine and to the BootstrapConfig\n" +
+			"- A failure response marks the in-place update as failed, and the hook is going to be called again",
+	})
+}
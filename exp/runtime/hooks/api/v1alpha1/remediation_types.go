@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
+)
+
+// RemediateMachineRequest is the request of the RemediateMachine hook.
+// +kubebuilder:object:root=true
+type RemediateMachineRequest struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// CommonRequest contains fields common to all request types.
+	CommonRequest `json:",inline"`
+
+	// cluster is the cluster object the Machine belongs to.
+	Cluster clusterv1.Cluster `json:"cluster"`
+
+	// machine is the unhealthy Machine object.
+	Machine clusterv1.Machine `json:"machine"`
+
+	// step is the name of the remediation step of the MachineHealthCheck.
+	Step string `json:"step"`
+
+	// action is the remediation action to be performed on the Machine, one of Reboot or Reprovision.
+	Action clusterv1.MachineHealthCheckRemediationAction `json:"action"`
+
+	// attempt is the number of the current attempt of the remediation step, starting from 1.
+	Attempt int32 `json:"attempt"`
+}
+
+var _ ResponseObject = &RemediateMachineResponse{}
+
+// RemediateMachineResponse is the response of the RemediateMachine hook.
+// +kubebuilder:object:root=true
+type RemediateMachineResponse struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// CommonResponse contains Status and Message fields common to all response types.
+	CommonResponse `json:",inline"`
+}
+
+// RemediateMachine is the hook that will be called to remediate an unhealthy Machine, e.g. by rebooting it.
+func RemediateMachine(*RemediateMachineRequest, *RemediateMachineResponse) {}
+
+func init() {
+	catalogBuilder.RegisterHook(RemediateMachine, &runtimecatalog.HookMeta{
+		Tags:    []string{"Remediation Hooks"},
+		Summary: "Cluster API Runtime will call this hook to remediate an unhealthy Machine",
+		Description: "Cluster API Runtime will call this hook for each attempt of a remediation step of a MachineHealthCheck " +
+			"which references the Runtime Extension.\n" +
+			"\n" +
+			"Notes:\n" +
+			"- The call's request contains the Cluster, the Machine, the name of the remediation step, the action to be " +
+			"performed, e.g. Reboot or Reprovision, and the number of the attempt\n" +
+			"- This is a non-blocking hook; the Runtime Extension should trigger the remediation and return; " +
+			"the MachineHealthCheck checks if the Machine became healthy after the timeout of the remediation step\n" +
+			"- A failure response does not count as an attempt, and the hook is going to be called again",
+	})
+}
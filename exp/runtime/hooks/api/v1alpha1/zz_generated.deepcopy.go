@@ -366,6 +366,62 @@ func (in *Builtins) DeepCopy() *Builtins {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanUpdateMachineRequest) DeepCopyInto(out *CanUpdateMachineRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.CommonRequest.DeepCopyInto(&out.CommonRequest)
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	in.Machine.DeepCopyInto(&out.Machine)
+	in.DesiredMachine.DeepCopyInto(&out.DesiredMachine)
+	in.BootstrapConfig.DeepCopyInto(&out.BootstrapConfig)
+	in.DesiredBootstrapConfig.DeepCopyInto(&out.DesiredBootstrapConfig)
+	in.InfrastructureMachine.DeepCopyInto(&out.InfrastructureMachine)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanUpdateMachineRequest.
+func (in *CanUpdateMachineRequest) DeepCopy() *CanUpdateMachineRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(CanUpdateMachineRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CanUpdateMachineRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanUpdateMachineResponse) DeepCopyInto(out *CanUpdateMachineResponse) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.CommonResponse = in.CommonResponse
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanUpdateMachineResponse.
+func (in *CanUpdateMachineResponse) DeepCopy() *CanUpdateMachineResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(CanUpdateMachineResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CanUpdateMachineResponse) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterBuiltins) DeepCopyInto(out *ClusterBuiltins) {
 	*out = *in
@@ -954,6 +1010,114 @@ func (in *MachinePoolBuiltins) DeepCopy() *MachinePoolBuiltins {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediateMachineRequest) DeepCopyInto(out *RemediateMachineRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.CommonRequest.DeepCopyInto(&out.CommonRequest)
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	in.Machine.DeepCopyInto(&out.Machine)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediateMachineRequest.
+func (in *RemediateMachineRequest) DeepCopy() *RemediateMachineRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediateMachineRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RemediateMachineRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediateMachineResponse) DeepCopyInto(out *RemediateMachineResponse) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.CommonResponse = in.CommonResponse
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediateMachineResponse.
+func (in *RemediateMachineResponse) DeepCopy() *RemediateMachineResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediateMachineResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RemediateMachineResponse) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdateMachineRequest) DeepCopyInto(out *UpdateMachineRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.CommonRequest.DeepCopyInto(&out.CommonRequest)
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	in.Machine.DeepCopyInto(&out.Machine)
+	in.DesiredMachine.DeepCopyInto(&out.DesiredMachine)
+	in.BootstrapConfig.DeepCopyInto(&out.BootstrapConfig)
+	in.DesiredBootstrapConfig.DeepCopyInto(&out.DesiredBootstrapConfig)
+	in.InfrastructureMachine.DeepCopyInto(&out.InfrastructureMachine)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateMachineRequest.
+func (in *UpdateMachineRequest) DeepCopy() *UpdateMachineRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdateMachineRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *UpdateMachineRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdateMachineResponse) DeepCopyInto(out *UpdateMachineResponse) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.CommonRetryResponse = in.CommonRetryResponse
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateMachineResponse.
+func (in *UpdateMachineResponse) DeepCopy() *UpdateMachineResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdateMachineResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *UpdateMachineResponse) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ValidateTopologyRequest) DeepCopyInto(out *ValidateTopologyRequest) {
 	*out = *in
@@ -60,6 +60,12 @@ type Reconciler struct {
 	RuntimeClient runtimeclient.Client
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
+
+	// ReadOnly configures the Reconciler to register ExtensionConfigs into the registry as they are reported
+	// in status, without running discovery and without patching ExtensionConfigs.
+	// This allows controllers other than the core Cluster API controller to call Runtime Extensions,
+	// while discovery is performed by the core Cluster API controller only.
+	ReadOnly bool
 }
 
 func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options, partialSecretCache cache.Cache) error {
@@ -68,9 +74,11 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 	}
 
 	predicateLog := ctrl.LoggerFrom(ctx).WithValues("controller", "extensionconfig")
-	err := ctrl.NewControllerManagedBy(mgr).
-		For(&runtimev1.ExtensionConfig{}).
-		WatchesRawSource(source.Kind(
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&runtimev1.ExtensionConfig{})
+	// Secrets are only required to inject the CA bundle before discovery, which is not performed in read-only mode.
+	if !r.ReadOnly {
+		b = b.WatchesRawSource(source.Kind(
 			partialSecretCache,
 			&metav1.PartialObjectMetadata{
 				TypeMeta: metav1.TypeMeta{
@@ -82,16 +90,19 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 				r.secretToExtensionConfig,
 			),
 			predicates.TypedResourceIsChanged[*metav1.PartialObjectMetadata](mgr.GetScheme(), predicateLog),
-		)).
-		WithOptions(options).
+		))
+	}
+	err := b.WithOptions(options).
 		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(mgr.GetScheme(), predicateLog, r.WatchFilterValue)).
 		Complete(r)
 	if err != nil {
 		return errors.Wrap(err, "failed setting up with a controller manager")
 	}
 
-	if err := indexByExtensionInjectCAFromSecretName(ctx, mgr); err != nil {
-		return errors.Wrap(err, "failed setting up with a controller manager")
+	if !r.ReadOnly {
+		if err := indexByExtensionInjectCAFromSecretName(ctx, mgr); err != nil {
+			return errors.Wrap(err, "failed setting up with a controller manager")
+		}
 	}
 
 	// warmupRunnable will attempt to sync the RuntimeSDK registry with existing ExtensionConfig objects to ensure extensions
@@ -100,6 +111,7 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 		Client:        r.Client,
 		APIReader:     r.APIReader,
 		RuntimeClient: r.RuntimeClient,
+		ReadOnly:      r.ReadOnly,
 	})
 	if err != nil {
 		return errors.Wrap(err, "failed adding warmupRunnable to controller manager")
@@ -142,6 +154,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return r.reconcileDelete(ctx, extensionConfig)
 	}
 
+	// In read-only mode, register the ExtensionConfig as discovered by the core Cluster API controller.
+	if r.ReadOnly {
+		log.V(4).Info("Registering ExtensionConfig information into registry")
+		if err := r.RuntimeClient.Register(extensionConfig); err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to register ExtensionConfig %s/%s", extensionConfig.Namespace, extensionConfig.Name)
+		}
+		return ctrl.Result{}, nil
+	}
+
 	// Copy to avoid modifying the original extensionConfig.
 	original := extensionConfig.DeepCopy()
 
@@ -44,6 +44,7 @@ type warmupRunnable struct {
 	Client         client.Client
 	APIReader      client.Reader
 	RuntimeClient  runtimeclient.Client
+	ReadOnly       bool
 	warmupTimeout  time.Duration
 	warmupInterval time.Duration
 }
@@ -73,7 +74,12 @@ func (r *warmupRunnable) Start(ctx context.Context) error {
 	defer cancel()
 
 	err := wait.PollUntilContextTimeout(ctx, r.warmupInterval, r.warmupTimeout, true, func(ctx context.Context) (done bool, err error) {
-		if err = warmupRegistry(ctx, r.Client, r.APIReader, r.RuntimeClient); err != nil {
+		if r.ReadOnly {
+			err = warmupRegistryReadOnly(ctx, r.APIReader, r.RuntimeClient)
+		} else {
+			err = warmupRegistry(ctx, r.Client, r.APIReader, r.RuntimeClient)
+		}
+		if err != nil {
 			log.Error(err, "ExtensionConfig registry warmup failed")
 			return false, nil
 		}
@@ -138,3 +144,22 @@ func warmupRegistry(ctx context.Context, client client.Client, reader client.Rea
 
 	return nil
 }
+
+// warmupRegistryReadOnly warms up the registry by passing it the list of ExtensionConfigs as discovered
+// by the core Cluster API controller, without running discovery and without patching ExtensionConfigs.
+func warmupRegistryReadOnly(ctx context.Context, reader client.Reader, runtimeClient runtimeclient.Client) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	extensionConfigList := runtimev1.ExtensionConfigList{}
+	if err := reader.List(ctx, &extensionConfigList); err != nil {
+		return errors.Wrapf(err, "failed to list ExtensionConfigs")
+	}
+
+	if err := runtimeClient.WarmUp(&extensionConfigList); err != nil {
+		return err
+	}
+
+	log.Info("The extension registry is warmed up")
+
+	return nil
+}
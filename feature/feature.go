@@ -72,6 +72,12 @@ const (
 	// beta: v1.9
 	MachineWaitForVolumeDetachConsiderVolumeAttachments featuregate.Feature = "MachineWaitForVolumeDetachConsiderVolumeAttachments"
 
+	// InPlaceUpdates is a feature gate for the in-place update of Machines controlled by MachineSets and KubeadmControlPlanes.
+	// It requires the RuntimeSDK feature gate, as the in-place updates are delegated to a Runtime Extension.
+	//
+	// alpha: v1.10
+	InPlaceUpdates featuregate.Feature = "InPlaceUpdates"
+
 	// PriorityQueue is a feature gate that controls if the controller uses the controller-runtime PriorityQueue
 	// instead of the default queue implementation.
 	//
@@ -95,4 +101,5 @@ var defaultClusterAPIFeatureGates = map[featuregate.Feature]featuregate.FeatureS
 	ClusterTopology:                {Default: false, PreRelease: featuregate.Alpha},
 	KubeadmBootstrapFormatIgnition: {Default: false, PreRelease: featuregate.Alpha},
 	RuntimeSDK:                     {Default: false, PreRelease: featuregate.Alpha},
+	InPlaceUpdates:                 {Default: false, PreRelease: featuregate.Alpha},
 }
@@ -105,6 +105,7 @@ func (src *Machine) ConvertTo(dstRaw conversion.Hub) error {
 	dst.Status.NodeInfo = restored.Status.NodeInfo
 	dst.Status.CertificatesExpiryDate = restored.Status.CertificatesExpiryDate
 	dst.Status.Deletion = restored.Status.Deletion
+	dst.Status.Remediation = restored.Status.Remediation
 	dst.Status.V1Beta2 = restored.Status.V1Beta2
 
 	return nil
@@ -269,6 +270,9 @@ func (src *MachineHealthCheck) ConvertTo(dstRaw conversion.Hub) error {
 	if restored.Spec.UnhealthyRange != nil {
 		dst.Spec.UnhealthyRange = restored.Spec.UnhealthyRange
 	}
+	dst.Spec.UnhealthyMachineConditions = restored.Spec.UnhealthyMachineConditions
+	dst.Spec.UnhealthyExpressions = restored.Spec.UnhealthyExpressions
+	dst.Spec.Remediation = restored.Spec.Remediation
 	dst.Status.V1Beta2 = restored.Status.V1Beta2
 
 	return nil
@@ -327,6 +331,7 @@ func Convert_v1beta1_MachineRollingUpdateDeployment_To_v1alpha3_MachineRollingUp
 }
 
 func Convert_v1beta1_MachineHealthCheckSpec_To_v1alpha3_MachineHealthCheckSpec(in *clusterv1.MachineHealthCheckSpec, out *MachineHealthCheckSpec, s apiconversion.Scope) error {
+	// UnhealthyMachineConditions, UnhealthyExpressions and Remediation were added in v1beta1.
 	return autoConvert_v1beta1_MachineHealthCheckSpec_To_v1alpha3_MachineHealthCheckSpec(in, out, s)
 }
 
@@ -194,6 +194,7 @@ func (src *Machine) ConvertTo(dstRaw conversion.Hub) error {
 	dst.Status.CertificatesExpiryDate = restored.Status.CertificatesExpiryDate
 	dst.Spec.NodeVolumeDetachTimeout = restored.Spec.NodeVolumeDetachTimeout
 	dst.Status.Deletion = restored.Status.Deletion
+	dst.Status.Remediation = restored.Status.Remediation
 	dst.Status.V1Beta2 = restored.Status.V1Beta2
 
 	return nil
@@ -343,6 +344,9 @@ func (src *MachineHealthCheck) ConvertTo(dstRaw conversion.Hub) error {
 	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
 		return err
 	}
+	dst.Spec.UnhealthyMachineConditions = restored.Spec.UnhealthyMachineConditions
+	dst.Spec.UnhealthyExpressions = restored.Spec.UnhealthyExpressions
+	dst.Spec.Remediation = restored.Spec.Remediation
 	dst.Status.V1Beta2 = restored.Status.V1Beta2
 
 	return nil
@@ -466,6 +470,11 @@ func Convert_v1beta1_MachineSetStatus_To_v1alpha4_MachineSetStatus(in *clusterv1
 	return autoConvert_v1beta1_MachineSetStatus_To_v1alpha4_MachineSetStatus(in, out, s)
 }
 
+func Convert_v1beta1_MachineHealthCheckSpec_To_v1alpha4_MachineHealthCheckSpec(in *clusterv1.MachineHealthCheckSpec, out *MachineHealthCheckSpec, s apiconversion.Scope) error {
+	// UnhealthyMachineConditions, UnhealthyExpressions and Remediation were added in v1beta1.
+	return autoConvert_v1beta1_MachineHealthCheckSpec_To_v1alpha4_MachineHealthCheckSpec(in, out, s)
+}
+
 func Convert_v1beta1_MachineHealthCheckStatus_To_v1alpha4_MachineHealthCheckStatus(in *clusterv1.MachineHealthCheckStatus, out *MachineHealthCheckStatus, s apiconversion.Scope) error {
 	// V1Beta2 was added in v1beta1.
 	return autoConvert_v1beta1_MachineHealthCheckStatus_To_v1alpha4_MachineHealthCheckStatus(in, out, s)
@@ -33,6 +33,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -61,6 +62,9 @@ type Helper struct {
 	// DeletionTimeStamp > N seconds. This can be used e.g. when a Node is unreachable
 	// and the Pods won't drain because of that.
 	SkipWaitForDeleteTimeoutSeconds int
+
+	// WebhookClient is used to call the webhooks of MachineDrainRules with behavior "Webhook".
+	WebhookClient *WebhookClient
 }
 
 // CordonNode cordons a Node.
@@ -154,13 +158,14 @@ func (d *Helper) GetPodsForEviction(ctx context.Context, cluster *clusterv1.Clus
 		// Skip Pods with label cluster.x-k8s.io/drain == "skip" or "wait-completed"
 		d.drainLabelFilter,
 
-		// Use drain behavior and order from first matching MachineDrainRule
+		// Use drain behavior, order and webhook from first matching MachineDrainRule
 		// If there is no matching MachineDrainRule, use behavior: "Drain" and order: 0
 		d.machineDrainRulesFilter(machineDrainRulesMatchingMachine, podNamespaces),
 	})
 	if errs := list.errors(); len(errs) > 0 {
 		return nil, errors.Wrapf(kerrors.NewAggregate(errs), "failed to get Pods for eviction")
 	}
+	list.machine = machine
 
 	return list, nil
 }
@@ -241,7 +246,7 @@ func filterPods(ctx context.Context, allPods []*corev1.Pod, filters []PodFilter)
 		// Note: It only makes sense to aggregate warnings if we are going ahead with the deletion.
 		// If we don't, it's absolutely fine to just use the status from the filter that decided that
 		// we are not going to delete the Pod.
-		if status.DrainBehavior == clusterv1.MachineDrainRuleDrainBehaviorDrain &&
+		if drainBehaviorRemovesPod(status.DrainBehavior) &&
 			(status.Reason == PodDeleteStatusTypeOkay || status.Reason == PodDeleteStatusTypeWarning) &&
 			len(deleteWarnings) > 0 {
 			status.Reason = PodDeleteStatusTypeWarning
@@ -287,7 +292,7 @@ func (d *Helper) EvictPods(ctx context.Context, podDeleteList *PodDeleteList) Ev
 	var podsToWaitCompletedLater []PodDelete
 	for _, pod := range podDeleteList.items {
 		switch {
-		case pod.Status.DrainBehavior == clusterv1.MachineDrainRuleDrainBehaviorDrain && pod.Pod.DeletionTimestamp.IsZero():
+		case drainBehaviorRemovesPod(pod.Status.DrainBehavior) && pod.Pod.DeletionTimestamp.IsZero():
 			if ptr.Deref(pod.Status.DrainOrder, 0) == minDrainOrder {
 				podsToTriggerEvictionNow = append(podsToTriggerEvictionNow, pod)
 			} else {
@@ -299,7 +304,7 @@ func (d *Helper) EvictPods(ctx context.Context, podDeleteList *PodDeleteList) Ev
 			} else {
 				podsToWaitCompletedLater = append(podsToWaitCompletedLater, pod)
 			}
-		case pod.Status.DrainBehavior == clusterv1.MachineDrainRuleDrainBehaviorDrain:
+		case drainBehaviorRemovesPod(pod.Status.DrainBehavior):
 			podsWithDeletionTimestamp = append(podsWithDeletionTimestamp, pod)
 		default:
 			podsToBeIgnored = append(podsToBeIgnored, pod)
@@ -358,9 +363,33 @@ evictionLoop:
 		default:
 		}
 
-		log.V(4).Info("Evicting Pod")
+		var err error
+		switch pd.Status.DrainBehavior {
+		case clusterv1.MachineDrainRuleDrainBehaviorDelete:
+			log.V(4).Info("Deleting Pod")
+			err = d.deletePod(ctx, pd.Pod)
+		case clusterv1.MachineDrainRuleDrainBehaviorWebhook:
+			log.V(4).Info("Calling webhook before evicting Pod", "webhook", pd.Status.Webhook.URL)
+			resp, webhookErr := d.callWebhook(ctx, pd.Status.Webhook, webhookRequestForPod(podDeleteList.machine, pd.Pod))
+			if webhookErr != nil {
+				msg := webhookErrorMessage(pd.Status.Webhook, "%v", webhookErr)
+				log.V(4).Info("Error when calling webhook", "err", webhookErr)
+				res.PodsFailedEviction[msg] = append(res.PodsFailedEviction[msg], pd.Pod)
+				continue evictionLoop
+			}
+			if !resp.Allowed {
+				msg := webhookErrorMessage(pd.Status.Webhook, "eviction not allowed yet: %s", resp.Message)
+				log.V(4).Info("Webhook did not allow eviction yet", "message", resp.Message)
+				res.PodsFailedEviction[msg] = append(res.PodsFailedEviction[msg], pd.Pod)
+				continue evictionLoop
+			}
+			log.V(4).Info("Evicting Pod")
+			err = d.evictPod(ctx, pd.Pod)
+		default:
+			log.V(4).Info("Evicting Pod")
+			err = d.evictPod(ctx, pd.Pod)
+		}
 
-		err := d.evictPod(ctx, pd.Pod)
 		switch {
 		case err == nil:
 			log.V(4).Info("Pod eviction successfully triggered")
@@ -371,6 +400,7 @@ evictionLoop:
 			res.PodsNotFound = append(res.PodsNotFound, pd.Pod)
 		case apierrors.IsTooManyRequests(err):
 			var statusError *apierrors.StatusError
+			isPodDisruptionBudgetFailure := apierrors.HasStatusCause(err, policyv1.DisruptionBudgetCause)
 
 			// Ensure the causes are also included in the error message.
 			// Before: "Cannot evict pod as it would violate the pod's disruption budget."
@@ -389,6 +419,12 @@ evictionLoop:
 
 			log.V(4).Info("Error when evicting Pod", "err", err)
 			res.PodsFailedEviction[err.Error()] = append(res.PodsFailedEviction[err.Error()], pd.Pod)
+			if isPodDisruptionBudgetFailure {
+				if res.PodDisruptionBudgetFailures == nil {
+					res.PodDisruptionBudgetFailures = sets.Set[string]{}
+				}
+				res.PodDisruptionBudgetFailures.Insert(err.Error())
+			}
 		case apierrors.IsForbidden(err) && apierrors.HasStatusCause(err, corev1.NamespaceTerminatingCause):
 			// Creating an eviction resource in a terminating namespace will throw a forbidden error, e.g.:
 			// "pods "pod-6-to-trigger-eviction-namespace-terminating" is forbidden: unable to create new content in namespace test-namespace because it is being terminated"
@@ -418,7 +454,7 @@ evictionLoop:
 func minDrainOrderOfPodsToDrain(pds []PodDelete) int32 {
 	minOrder := int32(math.MaxInt32)
 	for _, pd := range pds {
-		if pd.Status.DrainBehavior == clusterv1.MachineDrainRuleDrainBehaviorDrain &&
+		if drainBehaviorRemovesPod(pd.Status.DrainBehavior) &&
 			ptr.Deref(pd.Status.DrainOrder, 0) < minOrder {
 			minOrder = ptr.Deref(pd.Status.DrainOrder, 0)
 		}
@@ -449,6 +485,16 @@ func (d *Helper) evictPod(ctx context.Context, pod *corev1.Pod) error {
 	return d.RemoteClient.SubResource("eviction").Create(ctx, pod, eviction)
 }
 
+// deletePod deletes the given Pod, or return an error if it couldn't.
+func (d *Helper) deletePod(ctx context.Context, pod *corev1.Pod) error {
+	var deleteOpts []client.DeleteOption
+	if d.GracePeriodSeconds >= 0 {
+		deleteOpts = append(deleteOpts, client.GracePeriodSeconds(int64(d.GracePeriodSeconds)))
+	}
+
+	return d.RemoteClient.Delete(ctx, pod, deleteOpts...)
+}
+
 // EvictionResult contains the results of an eviction.
 type EvictionResult struct {
 	PodsDeletionTimestampSet   []*corev1.Pod
@@ -458,6 +504,10 @@ type EvictionResult struct {
 	PodsToWaitCompletedLater   []*corev1.Pod
 	PodsNotFound               []*corev1.Pod
 	PodsIgnored                []*corev1.Pod
+
+	// PodDisruptionBudgetFailures contains the keys of PodsFailedEviction for which
+	// the eviction API reported that the eviction would violate a PodDisruptionBudget.
+	PodDisruptionBudgetFailures sets.Set[string]
 }
 
 // DrainCompleted returns if a Node is entirely drained, i.e. if all relevant Pods have gone away.
@@ -541,6 +591,82 @@ func (r EvictionResult) ConditionMessage(nodeDrainStartTime *metav1.Time) string
 	return conditionMessage
 }
 
+// maxBlockingPods is the maximum number of blocking Pods reported in the drain status.
+const maxBlockingPods = 10
+
+// maxBlockingPodMessageLength is the maximum length of the message of a blocking Pod in the drain status.
+const maxBlockingPodMessageLength = 1024
+
+// NodeDrainStatus returns the drain status for the EvictionResult of a drain attempt.
+// previous is the drain status of the previous drain attempt (if any) and is used to count
+// attempts of the drain and of the single blocking Pods.
+func (r EvictionResult) NodeDrainStatus(previous *clusterv1.MachineNodeDrainStatus, now metav1.Time) *clusterv1.MachineNodeDrainStatus {
+	status := &clusterv1.MachineNodeDrainStatus{
+		LastAttemptTime:          &now,
+		Attempts:                 1,
+		PodsTerminating:          int32(len(r.PodsDeletionTimestampSet)),
+		PodsWaitingForCompletion: int32(len(r.PodsToWaitCompletedNow)),
+		PodsPendingEviction:      int32(len(r.PodsToTriggerEvictionLater) + len(r.PodsToWaitCompletedLater)),
+	}
+	if previous != nil {
+		status.Attempts = previous.Attempts + 1
+	}
+
+	var pdbBlockingPods, failedBlockingPods []clusterv1.MachineNodeDrainBlockingPod
+	for _, failureMessage := range slices.Sorted(maps.Keys(r.PodsFailedEviction)) {
+		pods := r.PodsFailedEviction[failureMessage]
+		if r.PodDisruptionBudgetFailures.Has(failureMessage) {
+			status.PodsBlockedByPodDisruptionBudget += int32(len(pods))
+			pdbBlockingPods = append(pdbBlockingPods, blockingPods(pods, clusterv1.MachineNodeDrainBlockingPodReasonPodDisruptionBudget, failureMessage)...)
+			continue
+		}
+		status.PodsFailedEviction += int32(len(pods))
+		failedBlockingPods = append(failedBlockingPods, blockingPods(pods, clusterv1.MachineNodeDrainBlockingPodReasonEvictionFailed, failureMessage)...)
+	}
+
+	var allBlockingPods []clusterv1.MachineNodeDrainBlockingPod
+	allBlockingPods = append(allBlockingPods, pdbBlockingPods...)
+	allBlockingPods = append(allBlockingPods, failedBlockingPods...)
+	allBlockingPods = append(allBlockingPods, blockingPods(r.PodsDeletionTimestampSet, clusterv1.MachineNodeDrainBlockingPodReasonTerminating, "deletionTimestamp set, but still not removed from the Node")...)
+	allBlockingPods = append(allBlockingPods, blockingPods(r.PodsToWaitCompletedNow, clusterv1.MachineNodeDrainBlockingPodReasonWaitCompleted, "waiting for completion")...)
+	if len(allBlockingPods) > maxBlockingPods {
+		allBlockingPods = allBlockingPods[:maxBlockingPods]
+	}
+
+	for i := range allBlockingPods {
+		allBlockingPods[i].Attempts = 1
+		if previous == nil {
+			continue
+		}
+		for _, previousPod := range previous.BlockingPods {
+			if previousPod.Namespace == allBlockingPods[i].Namespace && previousPod.Name == allBlockingPods[i].Name && previousPod.Reason == allBlockingPods[i].Reason {
+				allBlockingPods[i].Attempts = previousPod.Attempts + 1
+				break
+			}
+		}
+	}
+	status.BlockingPods = allBlockingPods
+
+	return status
+}
+
+// blockingPods returns the blocking Pods for a list of Pods that are blocking the drain for the same reason.
+func blockingPods(pods []*corev1.Pod, reason clusterv1.MachineNodeDrainBlockingPodReason, message string) []clusterv1.MachineNodeDrainBlockingPod {
+	if len(message) > maxBlockingPodMessageLength {
+		message = message[:maxBlockingPodMessageLength-3] + "..."
+	}
+	res := make([]clusterv1.MachineNodeDrainBlockingPod, 0, len(pods))
+	for _, pod := range pods {
+		res = append(res, clusterv1.MachineNodeDrainBlockingPod{
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			Reason:    reason,
+			Message:   message,
+		})
+	}
+	return res
+}
+
 // podDeleteListToString returns a comma-separated list of the first n entries of the PodDelete list.
 func podDeleteListToString(podList []PodDelete, n int) string {
 	return clog.ListToString(podList, func(pd PodDelete) string {
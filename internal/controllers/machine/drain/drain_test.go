@@ -30,6 +30,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -166,6 +167,63 @@ func TestGetPodsForEviction(t *testing.T) {
 			},
 		},
 	}
+	mdrBehaviorDelete := &clusterv1.MachineDrainRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mdr-behavior-delete",
+			Namespace: "test-namespace",
+		},
+		Spec: clusterv1.MachineDrainRuleSpec{
+			Drain: clusterv1.MachineDrainRuleDrainConfig{
+				Behavior: clusterv1.MachineDrainRuleDrainBehaviorDelete,
+				Order:    ptr.To[int32](12),
+			},
+			Machines: nil, // Match all machines
+			Pods: []clusterv1.MachineDrainRulePodSelector{
+				{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"app": "behavior-delete",
+						},
+					},
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"kubernetes.io/metadata.name": "test-namespace",
+						},
+					},
+				},
+			},
+		},
+	}
+	mdrBehaviorWebhook := &clusterv1.MachineDrainRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mdr-behavior-webhook",
+			Namespace: "test-namespace",
+		},
+		Spec: clusterv1.MachineDrainRuleSpec{
+			Drain: clusterv1.MachineDrainRuleDrainConfig{
+				Behavior: clusterv1.MachineDrainRuleDrainBehaviorWebhook,
+				Order:    ptr.To[int32](13),
+				Webhook: &clusterv1.MachineDrainRuleDrainWebhook{
+					URL: "https://failover.example.com/pre-evict",
+				},
+			},
+			Machines: nil, // Match all machines
+			Pods: []clusterv1.MachineDrainRulePodSelector{
+				{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"app": "behavior-webhook",
+						},
+					},
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"kubernetes.io/metadata.name": "test-namespace",
+						},
+					},
+				},
+			},
+		},
+	}
 	mdrBehaviorUnknown := &clusterv1.MachineDrainRule{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "mdr-behavior-unknown",
@@ -771,8 +829,26 @@ func TestGetPodsForEviction(t *testing.T) {
 						},
 					},
 				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pod-4-behavior-delete",
+						Namespace: "test-namespace", // matches the Namespace of the selector in mdrBehaviorDelete.
+						Labels: map[string]string{
+							"app": "behavior-delete", // matches mdrBehaviorDelete.
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pod-5-behavior-webhook",
+						Namespace: "test-namespace", // matches the Namespace of the selector in mdrBehaviorWebhook.
+						Labels: map[string]string{
+							"app": "behavior-webhook", // matches mdrBehaviorWebhook.
+						},
+					},
+				},
 			},
-			machineDrainRules: []*clusterv1.MachineDrainRule{mdrBehaviorDrain, mdrBehaviorSkip, mdrBehaviorUnknown, mdrBehaviorWaitCompleted},
+			machineDrainRules: []*clusterv1.MachineDrainRule{mdrBehaviorDrain, mdrBehaviorSkip, mdrBehaviorUnknown, mdrBehaviorWaitCompleted, mdrBehaviorDelete, mdrBehaviorWebhook},
 			wantPodDeleteList: PodDeleteList{items: []PodDelete{
 				{
 					Pod: &corev1.Pod{
@@ -814,6 +890,37 @@ func TestGetPodsForEviction(t *testing.T) {
 						Reason:        PodDeleteStatusTypeWaitCompleted,
 					},
 				},
+				{
+					Pod: &corev1.Pod{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "pod-4-behavior-delete",
+							Namespace: "test-namespace",
+						},
+					},
+					Status: PodDeleteStatus{
+						DrainBehavior: clusterv1.MachineDrainRuleDrainBehaviorDelete,
+						DrainOrder:    ptr.To[int32](12),
+						// Preserve warning from other filters.
+						Reason:  PodDeleteStatusTypeWarning,
+						Message: "evicting Pod that has no controller",
+					},
+				},
+				{
+					Pod: &corev1.Pod{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "pod-5-behavior-webhook",
+							Namespace: "test-namespace",
+						},
+					},
+					Status: PodDeleteStatus{
+						DrainBehavior: clusterv1.MachineDrainRuleDrainBehaviorWebhook,
+						DrainOrder:    ptr.To[int32](13),
+						Webhook:       mdrBehaviorWebhook.Spec.Drain.Webhook,
+						// Preserve warning from other filters.
+						Reason:  PodDeleteStatusTypeWarning,
+						Message: "evicting Pod that has no controller",
+					},
+				},
 			}},
 		},
 	}
@@ -1432,6 +1539,7 @@ func TestEvictPods(t *testing.T) {
 						},
 					},
 				},
+				PodDisruptionBudgetFailures: sets.New("Cannot evict pod as it would violate the pod's disruption budget. The disruption budget pod-5-pdb needs 3 healthy pods and has 2 currently"),
 				PodsToTriggerEvictionLater: []*corev1.Pod{
 					{
 						ObjectMeta: metav1.ObjectMeta{
@@ -1621,6 +1729,7 @@ func TestEvictPods(t *testing.T) {
 						},
 					},
 				},
+				PodDisruptionBudgetFailures: sets.New("Cannot evict pod as it would violate the pod's disruption budget. The disruption budget pod-5-pdb needs 3 healthy pods and has 2 currently"),
 				PodsToTriggerEvictionLater: []*corev1.Pod{
 					{
 						ObjectMeta: metav1.ObjectMeta{
@@ -2046,6 +2155,115 @@ After above Pods have been removed from the Node, waiting for the following Pods
 	}
 }
 
+func TestEvictionResult_NodeDrainStatus(t *testing.T) {
+	now := metav1.Now()
+	pod := func(name string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: name}}
+	}
+	pdbMessage := "Cannot evict pod as it would violate the pod's disruption budget. The disruption budget pod-pdb needs 2 healthy pods and has 2 currently"
+
+	tests := []struct {
+		name                string
+		evictionResult      EvictionResult
+		previous            *clusterv1.MachineNodeDrainStatus
+		wantNodeDrainStatus *clusterv1.MachineNodeDrainStatus
+	}{
+		{
+			name:           "Compute drain status for a completed drain",
+			evictionResult: EvictionResult{},
+			wantNodeDrainStatus: &clusterv1.MachineNodeDrainStatus{
+				LastAttemptTime: &now,
+				Attempts:        1,
+			},
+		},
+		{
+			name: "Compute drain status with counts and blocking Pods",
+			evictionResult: EvictionResult{
+				PodsDeletionTimestampSet: []*corev1.Pod{pod("pod-1-terminating")},
+				PodsFailedEviction: map[string][]*corev1.Pod{
+					pdbMessage:         {pod("pod-2-pdb"), pod("pod-3-pdb")},
+					"some other error": {pod("pod-4-error")},
+				},
+				PodDisruptionBudgetFailures: sets.New(pdbMessage),
+				PodsToWaitCompletedNow:      []*corev1.Pod{pod("pod-5-wait-completed")},
+				PodsToTriggerEvictionLater:  []*corev1.Pod{pod("pod-6-eviction-later")},
+				PodsToWaitCompletedLater:    []*corev1.Pod{pod("pod-7-wait-completed-later")},
+			},
+			wantNodeDrainStatus: &clusterv1.MachineNodeDrainStatus{
+				LastAttemptTime:                  &now,
+				Attempts:                         1,
+				PodsTerminating:                  1,
+				PodsBlockedByPodDisruptionBudget: 2,
+				PodsFailedEviction:               1,
+				PodsWaitingForCompletion:         1,
+				PodsPendingEviction:              2,
+				BlockingPods: []clusterv1.MachineNodeDrainBlockingPod{
+					{Namespace: metav1.NamespaceDefault, Name: "pod-2-pdb", Reason: clusterv1.MachineNodeDrainBlockingPodReasonPodDisruptionBudget, Message: pdbMessage, Attempts: 1},
+					{Namespace: metav1.NamespaceDefault, Name: "pod-3-pdb", Reason: clusterv1.MachineNodeDrainBlockingPodReasonPodDisruptionBudget, Message: pdbMessage, Attempts: 1},
+					{Namespace: metav1.NamespaceDefault, Name: "pod-4-error", Reason: clusterv1.MachineNodeDrainBlockingPodReasonEvictionFailed, Message: "some other error", Attempts: 1},
+					{Namespace: metav1.NamespaceDefault, Name: "pod-1-terminating", Reason: clusterv1.MachineNodeDrainBlockingPodReasonTerminating, Message: "deletionTimestamp set, but still not removed from the Node", Attempts: 1},
+					{Namespace: metav1.NamespaceDefault, Name: "pod-5-wait-completed", Reason: clusterv1.MachineNodeDrainBlockingPodReasonWaitCompleted, Message: "waiting for completion", Attempts: 1},
+				},
+			},
+		},
+		{
+			name: "Compute drain status and count attempts of Pods that are still blocking with the same reason",
+			evictionResult: EvictionResult{
+				PodsDeletionTimestampSet: []*corev1.Pod{pod("pod-1-terminating")},
+				PodsFailedEviction: map[string][]*corev1.Pod{
+					pdbMessage: {pod("pod-2-pdb")},
+				},
+				PodDisruptionBudgetFailures: sets.New(pdbMessage),
+			},
+			previous: &clusterv1.MachineNodeDrainStatus{
+				Attempts: 3,
+				BlockingPods: []clusterv1.MachineNodeDrainBlockingPod{
+					{Namespace: metav1.NamespaceDefault, Name: "pod-1-terminating", Reason: clusterv1.MachineNodeDrainBlockingPodReasonEvictionFailed, Attempts: 2},
+					{Namespace: metav1.NamespaceDefault, Name: "pod-2-pdb", Reason: clusterv1.MachineNodeDrainBlockingPodReasonPodDisruptionBudget, Attempts: 3},
+				},
+			},
+			wantNodeDrainStatus: &clusterv1.MachineNodeDrainStatus{
+				LastAttemptTime:                  &now,
+				Attempts:                         4,
+				PodsTerminating:                  1,
+				PodsBlockedByPodDisruptionBudget: 1,
+				BlockingPods: []clusterv1.MachineNodeDrainBlockingPod{
+					{Namespace: metav1.NamespaceDefault, Name: "pod-2-pdb", Reason: clusterv1.MachineNodeDrainBlockingPodReasonPodDisruptionBudget, Message: pdbMessage, Attempts: 4},
+					{Namespace: metav1.NamespaceDefault, Name: "pod-1-terminating", Reason: clusterv1.MachineNodeDrainBlockingPodReasonTerminating, Message: "deletionTimestamp set, but still not removed from the Node", Attempts: 1},
+				},
+			},
+		},
+		{
+			name: "Compute drain status with a limited number of blocking Pods",
+			evictionResult: EvictionResult{
+				PodsDeletionTimestampSet: []*corev1.Pod{
+					pod("pod-01"), pod("pod-02"), pod("pod-03"), pod("pod-04"), pod("pod-05"), pod("pod-06"),
+					pod("pod-07"), pod("pod-08"), pod("pod-09"), pod("pod-10"), pod("pod-11"), pod("pod-12"),
+				},
+			},
+			wantNodeDrainStatus: &clusterv1.MachineNodeDrainStatus{
+				LastAttemptTime: &now,
+				Attempts:        1,
+				PodsTerminating: 12,
+				BlockingPods: func() []clusterv1.MachineNodeDrainBlockingPod {
+					var res []clusterv1.MachineNodeDrainBlockingPod
+					for i := 1; i <= 10; i++ {
+						res = append(res, clusterv1.MachineNodeDrainBlockingPod{Namespace: metav1.NamespaceDefault, Name: fmt.Sprintf("pod-%02d", i), Reason: clusterv1.MachineNodeDrainBlockingPodReasonTerminating, Message: "deletionTimestamp set, but still not removed from the Node", Attempts: 1})
+					}
+					return res
+				}(),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(tt.evictionResult.NodeDrainStatus(tt.previous, now)).To(BeComparableTo(tt.wantNodeDrainStatus))
+		})
+	}
+}
+
 func podByNodeName(o client.Object) []string {
 	pod, ok := o.(*corev1.Pod)
 	if !ok {
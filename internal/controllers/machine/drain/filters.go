@@ -55,13 +55,16 @@ type PodDelete struct {
 // PodDeleteList is a wrapper around []PodDelete.
 type PodDeleteList struct {
 	items []PodDelete
+
+	// machine is the Machine that is drained, it is used to build requests for webhooks.
+	machine *clusterv1.Machine
 }
 
 // Pods returns a list of Pods that have to go away before the Node can be considered completely drained.
 func (l *PodDeleteList) Pods() []*corev1.Pod {
 	pods := []*corev1.Pod{}
 	for _, i := range l.items {
-		if i.Status.DrainBehavior == clusterv1.MachineDrainRuleDrainBehaviorDrain ||
+		if drainBehaviorRemovesPod(i.Status.DrainBehavior) ||
 			i.Status.DrainBehavior == clusterv1.MachineDrainRuleDrainBehaviorWaitCompleted {
 			pods = append(pods, i.Pod)
 		}
@@ -106,13 +109,17 @@ func (l *PodDeleteList) errors() []error {
 
 // PodDeleteStatus informs filters if a pod should be deleted.
 type PodDeleteStatus struct {
-	// DrainBehavior defines the drain behavior of a Pod, it is either "Skip" or "Drain".
+	// DrainBehavior defines the drain behavior of a Pod, it is either "Skip", "WaitCompleted", "Drain", "Delete" or "Webhook".
 	DrainBehavior clusterv1.MachineDrainRuleDrainBehavior
 
 	// DrainOrder defines the order in which Pods are drained.
-	// DrainOrder is only used if DrainBehavior is "Drain".
+	// DrainOrder is only used if DrainBehavior is "Drain", "Delete", "Webhook" or "WaitCompleted".
 	DrainOrder *int32
 
+	// Webhook is the webhook that has to allow the eviction of the Pod.
+	// Webhook is only used if DrainBehavior is "Webhook".
+	Webhook *clusterv1.MachineDrainRuleDrainWebhook
+
 	Reason  string
 	Message string
 }
@@ -151,6 +158,25 @@ func MakePodDeleteStatusOkayWithOrder(order *int32) PodDeleteStatus {
 	}
 }
 
+// MakePodDeleteStatusDeleteWithOrder is a helper method to return the corresponding PodDeleteStatus.
+func MakePodDeleteStatusDeleteWithOrder(order *int32) PodDeleteStatus {
+	return PodDeleteStatus{
+		DrainBehavior: clusterv1.MachineDrainRuleDrainBehaviorDelete,
+		DrainOrder:    order,
+		Reason:        PodDeleteStatusTypeOkay,
+	}
+}
+
+// MakePodDeleteStatusWebhookWithOrder is a helper method to return the corresponding PodDeleteStatus.
+func MakePodDeleteStatusWebhookWithOrder(order *int32, webhook *clusterv1.MachineDrainRuleDrainWebhook) PodDeleteStatus {
+	return PodDeleteStatus{
+		DrainBehavior: clusterv1.MachineDrainRuleDrainBehaviorWebhook,
+		DrainOrder:    order,
+		Webhook:       webhook,
+		Reason:        PodDeleteStatusTypeOkay,
+	}
+}
+
 // MakePodDeleteStatusSkip is a helper method to return the corresponding PodDeleteStatus.
 func MakePodDeleteStatusSkip() PodDeleteStatus {
 	return PodDeleteStatus{
@@ -191,6 +217,14 @@ func MakePodDeleteStatusWithError(message string) PodDeleteStatus {
 	}
 }
 
+// drainBehaviorRemovesPod returns true if Pods with the given drain behavior are actively removed from the Node,
+// i.e. they are evicted or deleted.
+func drainBehaviorRemovesPod(behavior clusterv1.MachineDrainRuleDrainBehavior) bool {
+	return behavior == clusterv1.MachineDrainRuleDrainBehaviorDrain ||
+		behavior == clusterv1.MachineDrainRuleDrainBehaviorDelete ||
+		behavior == clusterv1.MachineDrainRuleDrainBehaviorWebhook
+}
+
 func hasLocalStorage(pod *corev1.Pod) bool {
 	for _, volume := range pod.Spec.Volumes {
 		if volume.EmptyDir != nil {
@@ -323,6 +357,15 @@ func (d *Helper) machineDrainRulesFilter(machineDrainRules []*clusterv1.MachineD
 			case clusterv1.MachineDrainRuleDrainBehaviorWaitCompleted:
 				log.V(4).Info(fmt.Sprintf("Skip evicting Pod, because MachineDrainRule %s with behavior %s applies to the Pod", mdr.Name, clusterv1.MachineDrainRuleDrainBehaviorWaitCompleted))
 				return MakePodDeleteStatusWaitCompleted()
+			case clusterv1.MachineDrainRuleDrainBehaviorDelete:
+				return MakePodDeleteStatusDeleteWithOrder(mdr.Spec.Drain.Order)
+			case clusterv1.MachineDrainRuleDrainBehaviorWebhook:
+				if mdr.Spec.Drain.Webhook == nil {
+					return MakePodDeleteStatusWithError(
+						fmt.Sprintf("MachineDrainRule %q has spec.drain.behavior %q but spec.drain.webhook is not set",
+							mdr.Name, mdr.Spec.Drain.Behavior))
+				}
+				return MakePodDeleteStatusWebhookWithOrder(mdr.Spec.Drain.Order, mdr.Spec.Drain.Webhook)
 			default:
 				return MakePodDeleteStatusWithError(
 					fmt.Sprintf("MachineDrainRule %q has unknown spec.drain.behavior: %q",
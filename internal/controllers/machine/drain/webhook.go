@@ -0,0 +1,243 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/transport"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	defaultWebhookTimeout = 10 * time.Second
+
+	// maxWebhookResponseSize is the maximum size of the response body read from a webhook.
+	maxWebhookResponseSize = 64 * 1024
+)
+
+// WebhookRequest is the request sent to the webhook of a MachineDrainRule with behavior "Webhook"
+// before a Pod is evicted.
+// NOTE: MachineDrainRules are namespaced and the webhook is called using the identity of the controller,
+// so the request only contains the data required to identify the Pod, and not e.g. its spec, which could
+// contain sensitive data like environment variables.
+type WebhookRequest struct {
+	// ClusterName is the name of the Cluster the Machine belongs to.
+	ClusterName string `json:"clusterName"`
+
+	// Machine is the Machine that is drained.
+	Machine corev1.ObjectReference `json:"machine"`
+
+	// NodeName is the name of the Node that is drained.
+	NodeName string `json:"nodeName"`
+
+	// Pod is the Pod that is going to be evicted.
+	Pod WebhookPod `json:"pod"`
+}
+
+// WebhookPod identifies the Pod that is going to be evicted in a WebhookRequest.
+type WebhookPod struct {
+	// Namespace is the namespace of the Pod.
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the Pod.
+	Name string `json:"name"`
+
+	// Labels are the labels of the Pod.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// OwnerReferences are the owner references of the Pod, e.g. the StatefulSet of the Pod.
+	// +optional
+	OwnerReferences []metav1.OwnerReference `json:"ownerReferences,omitempty"`
+}
+
+// WebhookResponse is the response returned by the webhook of a MachineDrainRule with behavior "Webhook".
+type WebhookResponse struct {
+	// Allowed is true if the Pod can be evicted.
+	// If false, the webhook is called again on the next reconcile.
+	Allowed bool `json:"allowed"`
+
+	// Message is a human-readable message, e.g. why the eviction is not allowed yet.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// WebhookClient calls the webhooks of MachineDrainRules.
+// The same WebhookClient should be used for all the calls, so http connections are reused.
+type WebhookClient struct {
+	lock    sync.Mutex
+	clients map[string]*webhookHTTPClient
+}
+
+// webhookHTTPClient is the http client for the webhooks of a host, using a CA bundle.
+type webhookHTTPClient struct {
+	caBundle []byte
+	client   *http.Client
+}
+
+// NewWebhookClient returns a new WebhookClient.
+func NewWebhookClient() *WebhookClient {
+	return &WebhookClient{
+		clients: map[string]*webhookHTTPClient{},
+	}
+}
+
+// httpClientFor returns the http client for a webhook URL using a CA bundle.
+// Clients are cached by host, so there is at most one client (and one pool of connections) per host;
+// if the CA bundle for a host changes, the previous client is replaced and its idle connections are closed.
+func (c *WebhookClient) httpClientFor(webhookURL *url.URL, caBundle []byte) (*http.Client, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if cached, ok := c.clients[webhookURL.Host]; ok {
+		if bytes.Equal(cached.caBundle, caBundle) {
+			return cached.client, nil
+		}
+		cached.client.CloseIdleConnections()
+		delete(c.clients, webhookURL.Host)
+	}
+
+	// Use client-go's transport.TLSConfigureFor to ensure good defaults for tls
+	tlsConfig, err := transport.TLSConfigFor(&transport.Config{
+		TLS: transport.TLSConfig{
+			CAData:     caBundle,
+			ServerName: webhookURL.Hostname(),
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create tls config")
+	}
+	httpClient := &http.Client{
+		// This also adds http2
+		Transport: utilnet.SetTransportDefaults(&http.Transport{
+			TLSClientConfig: tlsConfig,
+		}),
+		// Redirects are not followed, so the webhook is only called at the URL of the MachineDrainRule.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	c.clients[webhookURL.Host] = &webhookHTTPClient{
+		caBundle: caBundle,
+		client:   httpClient,
+	}
+	return httpClient, nil
+}
+
+// callWebhook calls the webhook of a MachineDrainRule for a Pod and returns its response.
+func (d *Helper) callWebhook(ctx context.Context, webhook *clusterv1.MachineDrainRuleDrainWebhook, request *WebhookRequest) (*WebhookResponse, error) {
+	if d.WebhookClient == nil {
+		return nil, errors.New("failed to call webhook: webhook client is not set")
+	}
+
+	webhookURL, err := url.Parse(webhook.URL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to call webhook: invalid URL %q", webhook.URL)
+	}
+
+	timeout := defaultWebhookTimeout
+	if webhook.TimeoutSeconds != nil {
+		timeout = time.Duration(*webhook.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeoutCause(ctx, timeout, errors.New("webhook timeout expired"))
+	defer cancel()
+
+	postBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to call webhook: failed to marshal request")
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL.String(), bytes.NewBuffer(postBody))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to call webhook: failed to create http request")
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+
+	httpClient, err := d.WebhookClient.httpClientFor(webhookURL, webhook.CABundle)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to call webhook")
+	}
+
+	resp, err := httpClient.Do(httpRequest)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to call webhook")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, errors.Errorf("failed to call webhook: unexpected status code %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	// NOTE: the response body is read up to one byte more than the limit, to detect responses exceeding the limit.
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxWebhookResponseSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to call webhook: failed to read response")
+	}
+	if len(body) > maxWebhookResponseSize {
+		return nil, errors.Errorf("failed to call webhook: response exceeds the maximum size of %d bytes", maxWebhookResponseSize)
+	}
+
+	response := &WebhookResponse{}
+	if err := json.Unmarshal(body, response); err != nil {
+		return nil, errors.Wrap(err, "failed to call webhook: failed to decode response")
+	}
+	return response, nil
+}
+
+// webhookRequestForPod returns the webhook request for a Pod.
+func webhookRequestForPod(machine *clusterv1.Machine, pod *corev1.Pod) *WebhookRequest {
+	request := &WebhookRequest{
+		NodeName: pod.Spec.NodeName,
+		Pod: WebhookPod{
+			Namespace:       pod.Namespace,
+			Name:            pod.Name,
+			Labels:          pod.Labels,
+			OwnerReferences: pod.OwnerReferences,
+		},
+	}
+	if machine != nil {
+		request.ClusterName = machine.Spec.ClusterName
+		request.Machine = corev1.ObjectReference{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Machine",
+			Namespace:  machine.Namespace,
+			Name:       machine.Name,
+			UID:        machine.UID,
+		}
+	}
+	return request
+}
+
+// webhookErrorMessage returns the message used to report that a Pod has not been evicted because of its webhook.
+func webhookErrorMessage(webhook *clusterv1.MachineDrainRuleDrainWebhook, format string, args ...any) string {
+	return fmt.Sprintf("webhook %s: %s", webhook.URL, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drain
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func Test_callWebhook(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := &WebhookRequest{}
+		if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch request.Pod.Name {
+		case "pod-redirect":
+			http.Redirect(w, r, "https://example.com", http.StatusFound)
+		case "pod-allowed":
+			_ = json.NewEncoder(w).Encode(&WebhookResponse{Allowed: true})
+		case "pod-not-allowed":
+			_ = json.NewEncoder(w).Encode(&WebhookResponse{Allowed: false, Message: "failover in progress"})
+		case "pod-large-response":
+			_ = json.NewEncoder(w).Encode(&WebhookResponse{Allowed: true, Message: strings.Repeat("a", maxWebhookResponseSize)})
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("internal error"))
+		}
+	}))
+	defer server.Close()
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	webhookClient := NewWebhookClient()
+
+	tests := []struct {
+		name         string
+		webhook      *clusterv1.MachineDrainRuleDrainWebhook
+		podName      string
+		wantResponse *WebhookResponse
+		wantErr      string
+	}{
+		{
+			name:         "Return allowed response",
+			webhook:      &clusterv1.MachineDrainRuleDrainWebhook{URL: server.URL, CABundle: caBundle},
+			podName:      "pod-allowed",
+			wantResponse: &WebhookResponse{Allowed: true},
+		},
+		{
+			name:         "Return not allowed response",
+			webhook:      &clusterv1.MachineDrainRuleDrainWebhook{URL: server.URL, CABundle: caBundle, TimeoutSeconds: ptr.To[int32](5)},
+			podName:      "pod-not-allowed",
+			wantResponse: &WebhookResponse{Allowed: false, Message: "failover in progress"},
+		},
+		{
+			name:    "Return error if the webhook returns an unexpected status code",
+			webhook: &clusterv1.MachineDrainRuleDrainWebhook{URL: server.URL, CABundle: caBundle},
+			podName: "pod-error",
+			wantErr: "failed to call webhook: unexpected status code 500: internal error",
+		},
+		{
+			name:    "Return error if the webhook response exceeds the maximum size",
+			webhook: &clusterv1.MachineDrainRuleDrainWebhook{URL: server.URL, CABundle: caBundle},
+			podName: "pod-large-response",
+			wantErr: "failed to call webhook: response exceeds the maximum size of 65536 bytes",
+		},
+		{
+			name:    "Return error if the webhook redirects",
+			webhook: &clusterv1.MachineDrainRuleDrainWebhook{URL: server.URL, CABundle: caBundle},
+			podName: "pod-redirect",
+			wantErr: "failed to call webhook: unexpected status code 302",
+		},
+		{
+			name:    "Return error if the server certificate cannot be verified",
+			webhook: &clusterv1.MachineDrainRuleDrainWebhook{URL: server.URL},
+			podName: "pod-allowed",
+			wantErr: "failed to call webhook",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: tt.podName, Namespace: metav1.NamespaceDefault}}
+
+			drainer := &Helper{WebhookClient: webhookClient}
+			gotResponse, err := drainer.callWebhook(context.Background(), tt.webhook, webhookRequestForPod(nil, pod))
+			if tt.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(gotResponse).To(BeComparableTo(tt.wantResponse))
+		})
+	}
+}
+
+func TestEvictPodsWithDeleteAndWebhookBehavior(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := &WebhookRequest{}
+		if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if request.Machine.Name != "test-machine" || request.ClusterName != "test-cluster" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		allowed := request.Pod.Name == "pod-3-webhook-allowed"
+		_ = json.NewEncoder(w).Encode(&WebhookResponse{Allowed: allowed, Message: "failover in progress"})
+	}))
+	defer server.Close()
+	webhook := &clusterv1.MachineDrainRuleDrainWebhook{
+		URL:      server.URL,
+		CABundle: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}),
+	}
+
+	podDeleteList := &PodDeleteList{
+		machine: &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-machine", Namespace: metav1.NamespaceDefault},
+			Spec:       clusterv1.MachineSpec{ClusterName: "test-cluster"},
+		},
+		items: []PodDelete{
+			{
+				Pod:    &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-1-delete"}},
+				Status: MakePodDeleteStatusDeleteWithOrder(nil),
+			},
+			{
+				Pod:    &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-2-webhook-not-allowed"}},
+				Status: MakePodDeleteStatusWebhookWithOrder(nil, webhook),
+			},
+			{
+				Pod:    &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-3-webhook-allowed"}},
+				Status: MakePodDeleteStatusWebhookWithOrder(nil, webhook),
+			},
+			{
+				Pod:    &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-4-webhook-later"}},
+				Status: MakePodDeleteStatusWebhookWithOrder(ptr.To[int32](5), webhook),
+			},
+		},
+	}
+
+	var deletedPods, evictedPods []string
+	fakeClient := interceptor.NewClient(fake.NewClientBuilder().Build(), interceptor.Funcs{
+		Delete: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.DeleteOption) error {
+			deletedPods = append(deletedPods, obj.GetName())
+			return nil
+		},
+		SubResourceCreate: func(_ context.Context, _ client.Client, subResourceName string, obj client.Object, _ client.Object, _ ...client.SubResourceCreateOption) error {
+			g.Expect(subResourceName).To(Equal("eviction"))
+			evictedPods = append(evictedPods, obj.GetName())
+			return nil
+		},
+	})
+
+	drainer := &Helper{
+		RemoteClient:  fakeClient,
+		WebhookClient: NewWebhookClient(),
+	}
+
+	gotEvictionResult := drainer.EvictPods(context.Background(), podDeleteList)
+	g.Expect(deletedPods).To(ConsistOf("pod-1-delete"))
+	g.Expect(evictedPods).To(ConsistOf("pod-3-webhook-allowed"))
+	g.Expect(gotEvictionResult.PodsDeletionTimestampSet).To(HaveLen(2))
+	g.Expect(gotEvictionResult.PodsFailedEviction).To(HaveKey("webhook " + server.URL + ": eviction not allowed yet: failover in progress"))
+	g.Expect(gotEvictionResult.PodsToTriggerEvictionLater).To(HaveLen(1))
+	g.Expect(gotEvictionResult.DrainCompleted()).To(BeFalse())
+}
+
+func Test_webhookRequestForPod(t *testing.T) {
+	g := NewWithT(t)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "pod",
+			Namespace:       metav1.NamespaceDefault,
+			Labels:          map[string]string{"app": "database"},
+			Annotations:     map[string]string{"secret-annotation": "value"},
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "StatefulSet", Name: "database"}},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: "node",
+			Containers: []corev1.Container{{
+				Name: "database",
+				Env:  []corev1.EnvVar{{Name: "PASSWORD", Value: "secret-value"}},
+			}},
+		},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: metav1.NamespaceDefault},
+		Spec:       clusterv1.MachineSpec{ClusterName: "cluster"},
+	}
+
+	request := webhookRequestForPod(machine, pod)
+	g.Expect(request.ClusterName).To(Equal("cluster"))
+	g.Expect(request.Machine.Name).To(Equal("machine"))
+	g.Expect(request.NodeName).To(Equal("node"))
+	g.Expect(request.Pod).To(BeComparableTo(WebhookPod{
+		Namespace:       metav1.NamespaceDefault,
+		Name:            "pod",
+		Labels:          map[string]string{"app": "database"},
+		OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "StatefulSet", Name: "database"}},
+	}))
+
+	// The request sent to the webhook must not contain e.g. annotations or environment variables of the Pod.
+	body, err := json.Marshal(request)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(body)).ToNot(ContainSubstring("secret"))
+}
+
+func TestWebhookClient_httpClientFor(t *testing.T) {
+	g := NewWithT(t)
+
+	webhookClient := NewWebhookClient()
+	webhookURL, err := url.Parse("https://webhook.example.com/pre-evict")
+	g.Expect(err).ToNot(HaveOccurred())
+	otherWebhookURL, err := url.Parse("https://webhook.example.com/other")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The same http client is used for all the webhooks of a host with the same CA bundle.
+	httpClient, err := webhookClient.httpClientFor(webhookURL, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	otherHTTPClient, err := webhookClient.httpClientFor(otherWebhookURL, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(otherHTTPClient).To(BeIdenticalTo(httpClient))
+
+	// The http client is replaced if the CA bundle changes.
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	newHTTPClient, err := webhookClient.httpClientFor(webhookURL, caBundle)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(newHTTPClient).ToNot(BeIdenticalTo(httpClient))
+	g.Expect(webhookClient.clients).To(HaveLen(1))
+}
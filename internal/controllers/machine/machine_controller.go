@@ -115,6 +115,10 @@ type Reconciler struct {
 	// e.g. spamming workload clusters with eviction requests during Node drain.
 	reconcileDeleteCache cache.Cache[cache.ReconcileEntry]
 
+	// drainWebhookClient is used to call the webhooks of MachineDrainRules during Node drain;
+	// it is shared across reconciles, so connections to the webhooks are reused.
+	drainWebhookClient *drain.WebhookClient
+
 	predicateLog *logr.Logger
 }
 
@@ -184,6 +188,7 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 		PredicateLogger: r.predicateLog,
 	}
 	r.reconcileDeleteCache = cache.New[cache.ReconcileEntry]()
+	r.drainWebhookClient = drain.NewWebhookClient()
 	return nil
 }
 
@@ -823,6 +828,7 @@ func (r *Reconciler) drainNode(ctx context.Context, s *scope) (ctrl.Result, erro
 		Client:             r.Client,
 		RemoteClient:       remoteClient,
 		GracePeriodSeconds: -1,
+		WebhookClient:      r.drainWebhookClient,
 	}
 
 	if noderefutil.IsNodeUnreachable(node) {
@@ -858,8 +864,13 @@ func (r *Reconciler) drainNode(ctx context.Context, s *scope) (ctrl.Result, erro
 		return ctrl.Result{}, err
 	}
 
+	if machine.Status.Deletion == nil {
+		machine.Status.Deletion = &clusterv1.MachineDeletionStatus{}
+	}
+
 	podsToBeDrained := podDeleteList.Pods()
 	if len(podsToBeDrained) == 0 {
+		machine.Status.Deletion.NodeDrain = drain.EvictionResult{}.NodeDrainStatus(machine.Status.Deletion.NodeDrain, metav1.Now())
 		log.Info("Drain completed")
 		return ctrl.Result{}, nil
 	}
@@ -868,11 +879,19 @@ func (r *Reconciler) drainNode(ctx context.Context, s *scope) (ctrl.Result, erro
 
 	evictionResult := drainer.EvictPods(ctx, podDeleteList)
 
+	previousNodeDrainStatus := machine.Status.Deletion.NodeDrain
+	machine.Status.Deletion.NodeDrain = evictionResult.NodeDrainStatus(previousNodeDrainStatus, metav1.Now())
+
 	if evictionResult.DrainCompleted() {
 		log.Info("Drain completed, remaining Pods on the Node have been evicted")
 		return ctrl.Result{}, nil
 	}
 
+	// Emit an event only when the Pods blocking the drain change, so that the event is not repeated on every drain attempt.
+	if nodeDrainBlockingPodsChanged(previousNodeDrainStatus, machine.Status.Deletion.NodeDrain) {
+		r.recorder.Eventf(machine, corev1.EventTypeWarning, "NodeDrainBlocked", "drain of Machine's node %q is blocked: %s", nodeName, nodeDrainStatusSummary(machine.Status.Deletion.NodeDrain))
+	}
+
 	// Add entry to the reconcileDeleteCache so we won't retry drain again before drainRetryInterval.
 	r.reconcileDeleteCache.Add(cache.NewReconcileEntry(machine, time.Now().Add(drainRetryInterval)))
 
@@ -894,6 +913,43 @@ func (r *Reconciler) drainNode(ctx context.Context, s *scope) (ctrl.Result, erro
 	return ctrl.Result{RequeueAfter: drainRetryInterval}, nil
 }
 
+// nodeDrainBlockingPodsChanged returns true if the Pods blocking the drain (or the reasons why they are blocking) changed.
+func nodeDrainBlockingPodsChanged(previous, current *clusterv1.MachineNodeDrainStatus) bool {
+	if previous == nil {
+		return len(current.BlockingPods) > 0
+	}
+	if len(previous.BlockingPods) != len(current.BlockingPods) {
+		return true
+	}
+	for i := range current.BlockingPods {
+		if previous.BlockingPods[i].Namespace != current.BlockingPods[i].Namespace ||
+			previous.BlockingPods[i].Name != current.BlockingPods[i].Name ||
+			previous.BlockingPods[i].Reason != current.BlockingPods[i].Reason {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeDrainStatusSummary returns a short summary of the drain status, e.g. to be used in events.
+func nodeDrainStatusSummary(status *clusterv1.MachineNodeDrainStatus) string {
+	summary := fmt.Sprintf("%d Pods blocked by PodDisruptionBudgets, %d Pods failed eviction, %d Pods terminating, %d Pods waiting for completion, %d Pods pending eviction",
+		status.PodsBlockedByPodDisruptionBudget, status.PodsFailedEviction, status.PodsTerminating, status.PodsWaitingForCompletion, status.PodsPendingEviction)
+
+	blockingPods := []string{}
+	for i, pod := range status.BlockingPods {
+		if i >= 3 {
+			blockingPods = append(blockingPods, "...")
+			break
+		}
+		blockingPods = append(blockingPods, fmt.Sprintf("%s (%s)", klog.KRef(pod.Namespace, pod.Name), pod.Reason))
+	}
+	if len(blockingPods) > 0 {
+		summary = fmt.Sprintf("%s; blocking Pods: %s", summary, strings.Join(blockingPods, ", "))
+	}
+	return summary
+}
+
 // shouldWaitForNodeVolumes returns true if node status still have volumes attached and the node is reachable
 // pod deletion and volume detach happen asynchronously, so pod could be deleted before volume detached from the node
 // this could cause issue for some storage provisioner, for example, vsphere-volume this is problematic
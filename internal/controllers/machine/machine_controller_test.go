@@ -1710,6 +1710,7 @@ func TestDrainNode(t *testing.T) {
 				Client:               c,
 				ClusterCache:         clustercache.NewFakeClusterCache(remoteClient, client.ObjectKeyFromObject(testCluster)),
 				reconcileDeleteCache: cache.New[cache.ReconcileEntry](),
+				recorder:             record.NewFakeRecorder(10),
 			}
 
 			testMachine.Status.NodeRef = &corev1.ObjectReference{
@@ -1841,10 +1842,12 @@ func TestDrainNode_withCaching(t *testing.T) {
 		Build()
 
 	reconcileDeleteCache := cache.New[cache.ReconcileEntry]()
+	recorder := record.NewFakeRecorder(10)
 	r := &Reconciler{
 		Client:               c,
 		ClusterCache:         clustercache.NewFakeClusterCache(remoteClient, client.ObjectKeyFromObject(testCluster)),
 		reconcileDeleteCache: reconcileDeleteCache,
+		recorder:             recorder,
 	}
 
 	s := &scope{
@@ -1873,6 +1876,23 @@ func TestDrainNode_withCaching(t *testing.T) {
 	g.Expect(s.deletingMessage).To(Equal(`Drain not completed yet (started at 2024-10-09T16:13:59Z):
 * Pod test-namespace/pod-delete-running-deployment-pod: deletionTimestamp set, but still not removed from the Node`))
 
+	// Drain status should report the one Pod that has been evicted.
+	gotNodeDrain := testMachine.Status.Deletion.NodeDrain
+	g.Expect(gotNodeDrain).ToNot(BeNil())
+	g.Expect(gotNodeDrain.LastAttemptTime).ToNot(BeNil())
+	g.Expect(gotNodeDrain.Attempts).To(Equal(int32(1)))
+	g.Expect(gotNodeDrain.PodsTerminating).To(Equal(int32(1)))
+	g.Expect(gotNodeDrain.BlockingPods).To(BeComparableTo([]clusterv1.MachineNodeDrainBlockingPod{
+		{
+			Namespace: "test-namespace",
+			Name:      "pod-delete-running-deployment-pod",
+			Reason:    clusterv1.MachineNodeDrainBlockingPodReasonTerminating,
+			Message:   "deletionTimestamp set, but still not removed from the Node",
+			Attempts:  1,
+		},
+	}))
+	g.Expect(recorder.Events).To(Receive(ContainSubstring("NodeDrainBlocked")))
+
 	// Node should be cordoned.
 	gotNode := &corev1.Node{}
 	g.Expect(remoteClient.Get(ctx, client.ObjectKeyFromObject(node), gotNode)).To(Succeed())
@@ -36,6 +36,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/internal/controllers/machinedeployment/mdutil"
 	"sigs.k8s.io/cluster-api/internal/util/hash"
 	"sigs.k8s.io/cluster-api/internal/util/ssa"
@@ -78,6 +79,15 @@ func (r *Reconciler) sync(ctx context.Context, md *clusterv1.MachineDeployment,
 // This may lead to stale reads of machine sets, thus incorrect deployment status.
 func (r *Reconciler) getAllMachineSetsAndSyncRevision(ctx context.Context, md *clusterv1.MachineDeployment, msList []*clusterv1.MachineSet, createIfNotExisted, templateExists bool) (*clusterv1.MachineSet, []*clusterv1.MachineSet, error) {
 	reconciliationTime := metav1.Now()
+
+	// If Machines can be updated in-place, propagate the version and the bootstrap config template to the existing
+	// MachineSet instead of creating a new one; the MachineSet controller then updates its Machines in-place.
+	if feature.Gates.Enabled(feature.InPlaceUpdates) {
+		if err := r.updateMachineSetForInPlaceUpdate(ctx, md, msList, &reconciliationTime); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	allOldMSs, err := mdutil.FindOldMachineSets(md, msList, &reconciliationTime)
 	if err != nil {
 		return nil, nil, err
@@ -161,6 +171,40 @@ func (r *Reconciler) updateMachineSet(ctx context.Context, deployment *clusterv1
 	return updatedMS, nil
 }
 
+// updateMachineSetForInPlaceUpdate updates the version and the bootstrap config template of the MachineSet that can be
+// updated in-place to the ones of the MachineDeployment, if any. The updated MachineSet replaces the original one in msList.
+func (r *Reconciler) updateMachineSetForInPlaceUpdate(ctx context.Context, md *clusterv1.MachineDeployment, msList []*clusterv1.MachineSet, reconciliationTime *metav1.Time) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	ms := mdutil.FindMachineSetForInPlaceUpdate(md, msList, reconciliationTime)
+	if ms == nil {
+		return nil
+	}
+
+	oldMSs := make([]*clusterv1.MachineSet, 0, len(msList)-1)
+	for _, oldMS := range msList {
+		if oldMS != ms {
+			oldMSs = append(oldMSs, oldMS)
+		}
+	}
+
+	msToUpdate := ms.DeepCopy()
+	msToUpdate.Spec.Template.Spec.Version = md.Spec.Template.Spec.Version
+	msToUpdate.Spec.Template.Spec.Bootstrap = *md.Spec.Template.Spec.Bootstrap.DeepCopy()
+	updatedMS, err := r.updateMachineSet(ctx, md, msToUpdate, oldMSs)
+	if err != nil {
+		return err
+	}
+	log.Info("Updated MachineSet to update its Machines in-place", "MachineSet", klog.KObj(updatedMS))
+
+	for i := range msList {
+		if msList[i] == ms {
+			msList[i] = updatedMS
+		}
+	}
+	return nil
+}
+
 // createMachineSetAndWait creates a new MachineSet with the desired intent of the MachineDeployment.
 // It waits for the cache to be updated with the newly created MachineSet.
 func (r *Reconciler) createMachineSetAndWait(ctx context.Context, deployment *clusterv1.MachineDeployment, oldMSs []*clusterv1.MachineSet, createReason string) (*clusterv1.MachineSet, error) {
@@ -454,6 +454,8 @@ func MachineTemplateDeepCopyRolloutFields(template *clusterv1.MachineTemplateSpe
 // in-place mutable fields).
 // Note: If the reconciliation time is after the deployment's `rolloutAfter` time, a MS has to be newer than
 // `rolloutAfter` to be considered as matching the deployment's intent.
+// Note: A MS whose Machines cannot be updated in-place is never considered as matching the deployment's intent,
+// so its Machines are replaced by a rolling update.
 // NOTE: If we find a matching MachineSet which only differs in in-place mutable fields we can use it to
 // fulfill the intent of the MachineDeployment by just updating the MachineSet to propagate in-place mutable fields.
 // Thus we don't have to create a new MachineSet and we can avoid an unnecessary rollout.
@@ -476,6 +478,11 @@ func FindNewMachineSet(deployment *clusterv1.MachineDeployment, msList []*cluste
 	var matchingMachineSets []*clusterv1.MachineSet
 	var diffs []string
 	for _, ms := range msList {
+		// MachineSets whose Machines cannot be updated in-place are rolled out, even if their template is up to date.
+		if _, ok := ms.Annotations[clusterv1.MachineSetInPlaceUpdateDeclinedAnnotation]; ok {
+			diffs = append(diffs, fmt.Sprintf("MachineSet %s: in-place update declined", ms.Name))
+			continue
+		}
 		upToDate, logMessages, _ := MachineTemplateUpToDate(&ms.Spec.Template, &deployment.Spec.Template)
 		if upToDate {
 			matchingMachineSets = append(matchingMachineSets, ms)
@@ -527,6 +534,51 @@ func FindOldMachineSets(deployment *clusterv1.MachineDeployment, msList []*clust
 	return allMSs, nil
 }
 
+// FindMachineSetForInPlaceUpdate returns the MachineSet whose Machines can be updated in-place to the intent of the
+// given deployment, if any.
+// A MachineSet can be updated in-place only if it is the only active MachineSet of the deployment, no MachineSet matches
+// the deployment's intent, an in-place update of its Machines has not been declined, and its machine template differs
+// from the deployment's only in the version and in the bootstrap config template (of the same kind).
+// Note: If the reconciliation time is after the deployment's `rolloutAfter` time, a MachineSet created before
+// `rolloutAfter` is never updated in-place, so the rollout replaces its Machines.
+func FindMachineSetForInPlaceUpdate(deployment *clusterv1.MachineDeployment, msList []*clusterv1.MachineSet, reconciliationTime *metav1.Time) *clusterv1.MachineSet {
+	if newMS, _, err := FindNewMachineSet(deployment, msList, reconciliationTime); err != nil || newMS != nil {
+		return nil
+	}
+
+	activeMSs := FilterActiveMachineSets(msList)
+	if len(activeMSs) != 1 {
+		return nil
+	}
+	ms := activeMSs[0]
+
+	if _, ok := ms.Annotations[clusterv1.MachineSetInPlaceUpdateDeclinedAnnotation]; ok {
+		return nil
+	}
+
+	if deployment.Spec.RolloutAfter != nil && !reconciliationTime.Before(deployment.Spec.RolloutAfter) &&
+		!ms.CreationTimestamp.After(deployment.Spec.RolloutAfter.Time) {
+		return nil
+	}
+
+	currentCopy := MachineTemplateDeepCopyRolloutFields(&ms.Spec.Template)
+	desiredCopy := MachineTemplateDeepCopyRolloutFields(&deployment.Spec.Template)
+
+	currentBootstrapRef := currentCopy.Spec.Bootstrap.ConfigRef
+	desiredBootstrapRef := desiredCopy.Spec.Bootstrap.ConfigRef
+	if currentBootstrapRef == nil || desiredBootstrapRef == nil ||
+		currentBootstrapRef.GroupVersionKind().GroupKind() != desiredBootstrapRef.GroupVersionKind().GroupKind() {
+		return nil
+	}
+
+	currentCopy.Spec.Version = desiredCopy.Spec.Version
+	currentCopy.Spec.Bootstrap = desiredCopy.Spec.Bootstrap
+	if !reflect.DeepEqual(currentCopy, desiredCopy) {
+		return nil
+	}
+	return ms
+}
+
 // GetReplicaCountForMachineSets returns the sum of Replicas of the given machine sets.
 func GetReplicaCountForMachineSets(machineSets []*clusterv1.MachineSet) int32 {
 	totalReplicas := int32(0)
@@ -421,6 +421,9 @@ func TestFindNewMachineSet(t *testing.T) {
 	msCreatedAfterRolloutAfter := generateMS(deployment)
 	msCreatedAfterRolloutAfter.CreationTimestamp = oneAfterRolloutAfter
 
+	matchingMSInPlaceUpdateDeclined := generateMS(deployment)
+	matchingMSInPlaceUpdateDeclined.Annotations = map[string]string{clusterv1.MachineSetInPlaceUpdateDeclinedAnnotation: ""}
+
 	tests := []struct {
 		Name               string
 		deployment         clusterv1.MachineDeployment
@@ -461,6 +464,13 @@ func TestFindNewMachineSet(t *testing.T) {
 			expected:     nil,
 			createReason: fmt.Sprintf(`couldn't find MachineSet matching MachineDeployment spec template: MachineSet %s: diff: spec.infrastructureRef InfrastructureMachineTemplate old-infra-ref, InfrastructureMachineTemplate new-infra-ref required`, oldMS.Name),
 		},
+		{
+			Name:         "Get nil if the in-place update of the Machines of the MachineSet matching the desired intent of the MachineDeployment has been declined",
+			deployment:   deployment,
+			msList:       []*clusterv1.MachineSet{&matchingMSInPlaceUpdateDeclined},
+			expected:     nil,
+			createReason: fmt.Sprintf("couldn't find MachineSet matching MachineDeployment spec template: MachineSet %s: in-place update declined", matchingMSInPlaceUpdateDeclined.Name),
+		},
 		{
 			Name:               "Get the MachineSet if reconciliationTime < rolloutAfter",
 			deployment:         *deploymentWithRolloutAfter,
@@ -609,6 +619,106 @@ func TestFindOldMachineSets(t *testing.T) {
 	}
 }
 
+func TestFindMachineSetForInPlaceUpdate(t *testing.T) {
+	beforeRolloutAfter := metav1.Now()
+	rolloutAfter := metav1.NewTime(beforeRolloutAfter.Add(time.Minute))
+	afterRolloutAfter := metav1.NewTime(rolloutAfter.Add(time.Minute))
+
+	oldDeployment := generateDeployment("nginx")
+	oldDeployment.Spec.Template.Spec.Version = ptr.To("v1.31.0")
+	oldDeployment.Spec.Template.Spec.Bootstrap.ConfigRef = &corev1.ObjectReference{
+		APIVersion: "bootstrap.cluster.x-k8s.io/v1beta1",
+		Kind:       "KubeadmConfigTemplate",
+		Name:       "bootstrap-template-1",
+	}
+
+	deployment := *oldDeployment.DeepCopy()
+	deployment.Spec.Template.Spec.Version = ptr.To("v1.32.0")
+	deployment.Spec.Template.Spec.Bootstrap.ConfigRef.Name = "bootstrap-template-2"
+
+	deploymentWithRolloutAfter := deployment.DeepCopy()
+	deploymentWithRolloutAfter.Spec.RolloutAfter = &rolloutAfter
+
+	deploymentWithChangedInfrastructure := deployment.DeepCopy()
+	deploymentWithChangedInfrastructure.Spec.Template.Spec.InfrastructureRef.Name = "changed-infra-ref"
+
+	deploymentWithChangedBootstrapKind := deployment.DeepCopy()
+	deploymentWithChangedBootstrapKind.Spec.Template.Spec.Bootstrap.ConfigRef.Kind = "OtherConfigTemplate"
+
+	oldMS := generateMS(oldDeployment)
+	oldMS.Spec.Replicas = ptr.To[int32](3)
+	oldMS.CreationTimestamp = beforeRolloutAfter
+
+	anotherOldMS := generateMS(oldDeployment)
+	anotherOldMS.Spec.Replicas = ptr.To[int32](1)
+
+	newMS := generateMS(deployment)
+	newMS.Spec.Replicas = ptr.To[int32](1)
+
+	oldMSInPlaceUpdateDeclined := oldMS.DeepCopy()
+	oldMSInPlaceUpdateDeclined.Annotations = map[string]string{clusterv1.MachineSetInPlaceUpdateDeclinedAnnotation: ""}
+
+	tests := []struct {
+		Name       string
+		deployment clusterv1.MachineDeployment
+		msList     []*clusterv1.MachineSet
+		expected   *clusterv1.MachineSet
+	}{
+		{
+			Name:       "Get the MachineSet if only the version and the bootstrap config template changed",
+			deployment: deployment,
+			msList:     []*clusterv1.MachineSet{&oldMS},
+			expected:   &oldMS,
+		},
+		{
+			Name:       "Get no MachineSet if a MachineSet matches the MachineDeployment",
+			deployment: deployment,
+			msList:     []*clusterv1.MachineSet{&oldMS, &newMS},
+			expected:   nil,
+		},
+		{
+			Name:       "Get no MachineSet if there is more than one active MachineSet",
+			deployment: deployment,
+			msList:     []*clusterv1.MachineSet{&oldMS, &anotherOldMS},
+			expected:   nil,
+		},
+		{
+			Name:       "Get no MachineSet if the infrastructure template changed",
+			deployment: *deploymentWithChangedInfrastructure,
+			msList:     []*clusterv1.MachineSet{&oldMS},
+			expected:   nil,
+		},
+		{
+			Name:       "Get no MachineSet if the in-place update of its Machines has been declined",
+			deployment: deployment,
+			msList:     []*clusterv1.MachineSet{oldMSInPlaceUpdateDeclined},
+			expected:   nil,
+		},
+		{
+			Name:       "Get no MachineSet if the kind of the bootstrap config template changed",
+			deployment: *deploymentWithChangedBootstrapKind,
+			msList:     []*clusterv1.MachineSet{&oldMS},
+			expected:   nil,
+		},
+		{
+			Name:       "Get no MachineSet if it has been created before rolloutAfter and reconciliationTime > rolloutAfter",
+			deployment: *deploymentWithRolloutAfter,
+			msList:     []*clusterv1.MachineSet{&oldMS},
+			expected:   nil,
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.Name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ms := FindMachineSetForInPlaceUpdate(&test.deployment, test.msList, &afterRolloutAfter)
+			g.Expect(ms).To(Equal(test.expected))
+		})
+	}
+}
+
 func TestGetReplicaCountForMachineSets(t *testing.T) {
 	ms1 := generateMS(generateDeployment("foo"))
 	*(ms1.Spec.Replicas) = 1
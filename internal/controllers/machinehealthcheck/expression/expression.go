@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package expression implements the CEL expressions used by MachineHealthChecks
+// to determine if a Machine is unhealthy.
+package expression
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/ext"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	celconfig "k8s.io/apiserver/pkg/apis/cel"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// MachineVariable is the name of the variable containing the Machine.
+	MachineVariable = "machine"
+
+	// NodeVariable is the name of the variable containing the Node of the Machine (null if there is no Node).
+	NodeVariable = "node"
+
+	// NowVariable is the name of the variable containing the current time.
+	NowVariable = "now"
+)
+
+// newEnv returns the CEL environment used to compile expressions.
+// The environment is created only once, because creating it is expensive.
+var newEnv = sync.OnceValues(func() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable(MachineVariable, cel.DynType),
+		cel.Variable(NodeVariable, cel.DynType),
+		cel.Variable(NowVariable, cel.TimestampType),
+		cel.OptionalTypes(),
+		ext.Strings(),
+		ext.Lists(),
+	)
+})
+
+// Program is a compiled expression.
+type Program struct {
+	program       cel.Program
+	dependsOnTime bool
+}
+
+// Compile compiles an expression. It returns an error if the expression is invalid
+// or does not evaluate to a boolean.
+func Compile(expression string) (*Program, error) {
+	env, err := newEnv()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CEL environment")
+	}
+
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, errors.Wrap(issues.Err(), "failed to compile expression")
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, errors.Errorf("expression must evaluate to a bool, got %s", ast.OutputType())
+	}
+
+	program, err := env.Program(ast,
+		cel.EvalOptions(cel.OptTrackCost),
+		cel.CostLimit(celconfig.PerCallLimit),
+		cel.InterruptCheckFrequency(celconfig.CheckFrequency),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create program for expression")
+	}
+
+	// Expressions using the current time can change their result even if neither the Machine nor the Node change.
+	dependsOnTime := false
+	for _, ref := range ast.NativeRep().ReferenceMap() {
+		if ref.Name == NowVariable {
+			dependsOnTime = true
+			break
+		}
+	}
+	return &Program{program: program, dependsOnTime: dependsOnTime}, nil
+}
+
+// DependsOnTime returns true if the expression uses the current time, and thus it must be evaluated again periodically.
+func (p *Program) DependsOnTime() bool {
+	return p.dependsOnTime
+}
+
+// Evaluate evaluates the expression for a Machine and its Node (if any).
+func (p *Program) Evaluate(machine *clusterv1.Machine, node *corev1.Node, now time.Time) (bool, error) {
+	machineVal, err := runtime.DefaultUnstructuredConverter.ToUnstructured(machine)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to convert Machine to unstructured")
+	}
+	var nodeVal any = types.NullValue
+	if node != nil {
+		nodeVal, err = runtime.DefaultUnstructuredConverter.ToUnstructured(node)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to convert Node to unstructured")
+		}
+	}
+
+	out, _, err := p.program.Eval(map[string]any{
+		MachineVariable: machineVal,
+		NodeVariable:    nodeVal,
+		NowVariable:     now,
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to evaluate expression")
+	}
+	result, ok := out.Value().(bool)
+	if !ok {
+		return false, errors.Errorf("expression must evaluate to a bool, got %s", out.Type())
+	}
+	return result, nil
+}
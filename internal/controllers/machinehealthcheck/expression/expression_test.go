@@ -0,0 +1,178 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expression
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name              string
+		expression        string
+		wantErr           string
+		wantDependsOnTime bool
+	}{
+		{
+			name:       "Compile valid expression",
+			expression: "node != null && node.spec.?unschedulable.orValue(false)",
+		},
+		{
+			name:       "Compile valid expression using the machine",
+			expression: "machine.metadata.labels['foo'] == 'bar'",
+		},
+		{
+			name:              "Compile valid expression using the current time",
+			expression:        "node.status.conditions.exists(c, c.type == 'Ready' && now - timestamp(c.lastTransitionTime) > duration('10m'))",
+			wantDependsOnTime: true,
+		},
+		{
+			name:       "Fail to compile invalid expression",
+			expression: "node.spec.unschedulable ==",
+			wantErr:    "failed to compile expression",
+		},
+		{
+			name:       "Fail to compile expression using an unknown variable",
+			expression: "cluster.metadata.name == 'foo'",
+			wantErr:    "undeclared reference to 'cluster'",
+		},
+		{
+			name:       "Fail to compile expression not returning a bool",
+			expression: "'foo'",
+			wantErr:    "expression must evaluate to a bool, got string",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			program, err := Compile(tt.expression)
+			if tt.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(program.DependsOnTime()).To(Equal(tt.wantDependsOnTime))
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	now := time.Now()
+
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machine",
+			Namespace: metav1.NamespaceDefault,
+			Labels:    map[string]string{"foo": "bar"},
+		},
+	}
+	notReadyUnschedulableNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "node",
+			Labels: map[string]string{"problem.example.com/kernel-deadlock": "true"},
+		},
+		Spec: corev1.NodeSpec{
+			Unschedulable: true,
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{
+					Type:               corev1.NodeReady,
+					Status:             corev1.ConditionFalse,
+					LastTransitionTime: metav1.NewTime(now.Add(-20 * time.Minute)),
+				},
+			},
+		},
+	}
+	notReadyUnschedulableFor10m := "node != null && node.spec.?unschedulable.orValue(false) && " +
+		"node.status.conditions.exists(c, c.type == 'Ready' && c.status != 'True' && now - timestamp(c.lastTransitionTime) > duration('10m'))"
+
+	tests := []struct {
+		name       string
+		expression string
+		machine    *clusterv1.Machine
+		node       *corev1.Node
+		want       bool
+		wantErr    string
+	}{
+		{
+			name:       "Evaluate expression on the machine",
+			expression: "machine.metadata.labels['foo'] == 'bar'",
+			machine:    machine,
+			want:       true,
+		},
+		{
+			name:       "Evaluate expression on a label set on the node",
+			expression: "node != null && node.metadata.?labels['problem.example.com/kernel-deadlock'].orValue('') == 'true'",
+			machine:    machine,
+			node:       notReadyUnschedulableNode,
+			want:       true,
+		},
+		{
+			name:       "Evaluate expression on a node that has been not ready and unschedulable for more than 10m",
+			expression: notReadyUnschedulableFor10m,
+			machine:    machine,
+			node:       notReadyUnschedulableNode,
+			want:       true,
+		},
+		{
+			name:       "Evaluate expression on a node that has been not ready and unschedulable for less than 30m",
+			expression: "node.status.conditions.exists(c, c.type == 'Ready' && c.status != 'True' && now - timestamp(c.lastTransitionTime) > duration('30m'))",
+			machine:    machine,
+			node:       notReadyUnschedulableNode,
+			want:       false,
+		},
+		{
+			name:       "Evaluate expression on a machine without node",
+			expression: notReadyUnschedulableFor10m,
+			machine:    machine,
+			want:       false,
+		},
+		{
+			name:       "Fail to evaluate expression accessing fields of a null node",
+			expression: "node.spec.unschedulable",
+			machine:    machine,
+			wantErr:    "failed to evaluate expression",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			program, err := Compile(tt.expression)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			got, err := program.Evaluate(tt.machine, tt.node, now)
+			if tt.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
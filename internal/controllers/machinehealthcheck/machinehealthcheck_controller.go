@@ -48,6 +48,7 @@ import (
 	"sigs.k8s.io/cluster-api/api/v1beta1/index"
 	"sigs.k8s.io/cluster-api/controllers/clustercache"
 	"sigs.k8s.io/cluster-api/controllers/external"
+	runtimeclient "sigs.k8s.io/cluster-api/exp/runtime/client"
 	"sigs.k8s.io/cluster-api/internal/controllers/machine"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
@@ -69,6 +70,10 @@ const (
 	unhealthyTargetsKeyLog = "unhealthyTargets"
 	unhealthyRangeKeyLog   = "unhealthyRange"
 	totalTargetKeyLog      = "totalTarget"
+
+	// unhealthyExpressionsCheckInterval is the interval after which unhealthy expressions depending on the
+	// current time are evaluated again.
+	unhealthyExpressionsCheckInterval = 1 * time.Minute
 )
 
 // +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
@@ -78,8 +83,9 @@ const (
 
 // Reconciler reconciles a MachineHealthCheck object.
 type Reconciler struct {
-	Client       client.Client
-	ClusterCache clustercache.ClusterCache
+	Client        client.Client
+	ClusterCache  clustercache.ClusterCache
+	RuntimeClient runtimeclient.Client
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
@@ -169,9 +175,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		patchOpts := []patch.Option{
 			patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
 				clusterv1.RemediationAllowedCondition,
+				clusterv1.UnhealthyExpressionsValidCondition,
 			}},
 			patch.WithOwnedV1Beta2Conditions{Conditions: []string{
 				clusterv1.MachineHealthCheckRemediationAllowedV1Beta2Condition,
+				clusterv1.MachineHealthCheckUnhealthyExpressionsValidV1Beta2Condition,
 			}},
 		}
 		if reterr == nil {
@@ -236,8 +244,12 @@ func (r *Reconciler) reconcile(ctx context.Context, logger logr.Logger, cluster
 		nodeStartupTimeout = &clusterv1.DefaultNodeStartupTimeout
 	}
 
+	// compile unhealthy expressions once for all the targets; invalid expressions are reported and not evaluated.
+	unhealthyExpressions, compileErrs := compileUnhealthyExpressions(m)
+	setUnhealthyExpressionsValidCondition(m, compileErrs)
+
 	// health check all targets and reconcile mhc status
-	healthy, unhealthy, nextCheckTimes := r.healthCheckTargets(targets, logger, *nodeStartupTimeout)
+	healthy, unhealthy, nextCheckTimes := r.healthCheckTargets(targets, logger, *nodeStartupTimeout, unhealthyExpressions)
 	m.Status.CurrentHealthy = int32(len(healthy))
 
 	// check MHC current health against MaxUnhealthy
@@ -349,8 +361,9 @@ func (r *Reconciler) reconcile(ctx context.Context, logger logr.Logger, cluster
 		Reason: clusterv1.MachineHealthCheckRemediationAllowedV1Beta2Reason,
 	})
 
-	errList := r.patchUnhealthyTargets(ctx, logger, unhealthy, cluster, m)
-	errList = append(errList, r.patchHealthyTargets(ctx, logger, healthy, m)...)
+	remediationNextCheckTimes, errList := r.patchUnhealthyTargets(ctx, logger, unhealthy, cluster, m)
+	stabilizationNextCheckTimes, healthyErrList := r.patchHealthyTargets(ctx, logger, healthy, m)
+	errList = append(errList, healthyErrList...)
 
 	// handle update errors
 	if len(errList) > 0 {
@@ -358,7 +371,16 @@ func (r *Reconciler) reconcile(ctx context.Context, logger logr.Logger, cluster
 		return reconcile.Result{}, kerrors.NewAggregate(errList)
 	}
 
-	if minNextCheck := minDuration(nextCheckTimes); minNextCheck > 0 {
+	nextCheckTimes = append(nextCheckTimes, remediationNextCheckTimes...)
+	minNextCheck := minDuration(append(nextCheckTimes, stabilizationNextCheckTimes...))
+
+	// Unhealthy expressions depending on the current time have to be evaluated again periodically.
+	if unhealthyExpressionsDependOnTime(unhealthyExpressions) && (minNextCheck == 0 || minNextCheck > unhealthyExpressionsCheckInterval) {
+		logger.V(3).Info("Ensuring a requeue happens to evaluate unhealthy expressions again", "requeueAfter", unhealthyExpressionsCheckInterval.String())
+		return ctrl.Result{RequeueAfter: unhealthyExpressionsCheckInterval}, nil
+	}
+
+	if minNextCheck > 0 {
 		logger.V(3).Info("Some targets might go unhealthy. Ensuring a requeue happens", "requeueAfter", minNextCheck.Truncate(time.Second).String())
 		return ctrl.Result{RequeueAfter: minNextCheck}, nil
 	}
@@ -369,9 +391,22 @@ func (r *Reconciler) reconcile(ctx context.Context, logger logr.Logger, cluster
 }
 
 // patchHealthyTargets patches healthy machines with MachineHealthCheckSucceededCondition.
-func (r *Reconciler) patchHealthyTargets(ctx context.Context, logger logr.Logger, healthy []healthCheckTarget, m *clusterv1.MachineHealthCheck) []error {
+// It also resets the remediation escalation of machines which have stayed healthy for the stabilization period, and
+// returns the durations after which the remediation escalation of the other machines must be reconciled again.
+func (r *Reconciler) patchHealthyTargets(ctx context.Context, logger logr.Logger, healthy []healthCheckTarget, m *clusterv1.MachineHealthCheck) ([]time.Duration, []error) {
+	nextCheckTimes := []time.Duration{}
 	errList := []error{}
+	now := time.Now()
 	for _, t := range healthy {
+		nextCheck, err := r.reconcileHealthyRemediationEscalation(ctx, logger.WithValues("Machine", klog.KObj(t.Machine)), m, t.Machine, now)
+		if err != nil {
+			errList = append(errList, errors.Wrapf(err, "failed to delete remediation requests for machine %q in namespace %q within cluster %q", t.Machine.Name, t.Machine.Namespace, t.Machine.Spec.ClusterName))
+			continue
+		}
+		if nextCheck > 0 {
+			nextCheckTimes = append(nextCheckTimes, nextCheck)
+		}
+
 		if m.Spec.RemediationTemplate != nil {
 			// Get remediation request object
 			obj, err := r.getExternalRemediationRequest(ctx, m, t.Machine.Name)
@@ -408,12 +443,16 @@ func (r *Reconciler) patchHealthyTargets(ctx context.Context, logger logr.Logger
 			errList = append(errList, errors.Wrapf(err, "failed to patch healthy machine status for machine: %s/%s", t.Machine.Namespace, t.Machine.Name))
 		}
 	}
-	return errList
+	return nextCheckTimes, errList
 }
 
 // patchUnhealthyTargets patches machines with MachineOwnerRemediatedCondition for remediation.
-func (r *Reconciler) patchUnhealthyTargets(ctx context.Context, logger logr.Logger, unhealthy []healthCheckTarget, cluster *clusterv1.Cluster, m *clusterv1.MachineHealthCheck) []error {
+// If the MachineHealthCheck has remediation escalation steps, machines are marked for remediation by their owner
+// only after all the steps have been exhausted; it also returns the durations after which the remediation
+// escalation of machines must be reconciled again.
+func (r *Reconciler) patchUnhealthyTargets(ctx context.Context, logger logr.Logger, unhealthy []healthCheckTarget, cluster *clusterv1.Cluster, m *clusterv1.MachineHealthCheck) ([]time.Duration, []error) {
 	// mark for remediation
+	nextCheckTimes := []time.Duration{}
 	errList := []error{}
 	for _, t := range unhealthy {
 		logger := logger.WithValues("Machine", klog.KObj(t.Machine), "Node", klog.KObj(t.Node))
@@ -426,7 +465,7 @@ func (r *Reconciler) patchUnhealthyTargets(ctx context.Context, logger logr.Logg
 				// If external remediation request already exists,
 				// return early
 				if r.externalRemediationRequestExists(ctx, m, t.Machine.Name) {
-					return errList
+					return nextCheckTimes, errList
 				}
 
 				cloneOwnerRef := &metav1.OwnerReference{
@@ -447,7 +486,7 @@ func (r *Reconciler) patchUnhealthyTargets(ctx context.Context, logger logr.Logg
 						Message: fmt.Sprintf("Error retrieving remediation template %s %s", m.Spec.RemediationTemplate.Kind, klog.KRef(m.Spec.RemediationTemplate.Namespace, m.Spec.RemediationTemplate.Name)),
 					})
 					errList = append(errList, errors.Wrapf(err, "error retrieving remediation template %v %q for machine %q in namespace %q within cluster %q", m.Spec.RemediationTemplate.GroupVersionKind(), m.Spec.RemediationTemplate.Name, t.Machine.Name, t.Machine.Namespace, m.Spec.ClusterName))
-					return errList
+					return nextCheckTimes, errList
 				}
 
 				generateTemplateInput := &external.GenerateTemplateInput{
@@ -460,7 +499,7 @@ func (r *Reconciler) patchUnhealthyTargets(ctx context.Context, logger logr.Logg
 				to, err := external.GenerateTemplate(generateTemplateInput)
 				if err != nil {
 					errList = append(errList, errors.Wrapf(err, "failed to create template for remediation request %v %q for machine %q in namespace %q within cluster %q", m.Spec.RemediationTemplate.GroupVersionKind(), m.Spec.RemediationTemplate.Name, t.Machine.Name, t.Machine.Namespace, m.Spec.ClusterName))
-					return errList
+					return nextCheckTimes, errList
 				}
 
 				// Set the Remediation Request to match the Machine name, the name is used to
@@ -483,7 +522,7 @@ func (r *Reconciler) patchUnhealthyTargets(ctx context.Context, logger logr.Logg
 						Message: "Please check controller logs for errors",
 					})
 					errList = append(errList, errors.Wrapf(err, "error creating remediation request for machine %q in namespace %q within cluster %q", t.Machine.Name, t.Machine.Namespace, t.Machine.Spec.ClusterName))
-					return errList
+					return nextCheckTimes, errList
 				}
 
 				v1beta2conditions.Set(t.Machine, metav1.Condition{
@@ -492,20 +531,37 @@ func (r *Reconciler) patchUnhealthyTargets(ctx context.Context, logger logr.Logg
 					Reason: clusterv1.MachineExternallyRemediatedWaitingForRemediationV1Beta2Reason,
 				})
 			} else if t.Machine.DeletionTimestamp.IsZero() { // Only setting the OwnerRemediated conditions when machine is not already in deletion.
-				logger.Info("Machine has failed health check, marking for remediation", "reason", condition.Reason, "message", condition.Message)
-				// NOTE: MHC is responsible for creating MachineOwnerRemediatedCondition if missing or to trigger another remediation if the previous one is completed;
-				// instead, if a remediation is in already progress, the remediation owner is responsible for completing the process and MHC should not overwrite the condition.
-				if !conditions.Has(t.Machine, clusterv1.MachineOwnerRemediatedCondition) || conditions.IsTrue(t.Machine, clusterv1.MachineOwnerRemediatedCondition) {
-					conditions.MarkFalse(t.Machine, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "")
+				// If the MachineHealthCheck has remediation escalation steps, the machine is remediated by its owner
+				// only after all the steps have been exhausted.
+				remediateByOwner := true
+				if hasRemediationEscalation(m) {
+					var requeueAfter time.Duration
+					var err error
+					remediateByOwner, requeueAfter, err = r.reconcileRemediationEscalation(ctx, logger, cluster, m, t.Machine, time.Now())
+					if err != nil {
+						errList = append(errList, err)
+					}
+					if requeueAfter > 0 {
+						nextCheckTimes = append(nextCheckTimes, requeueAfter)
+					}
 				}
 
-				if ownerRemediatedCondition := v1beta2conditions.Get(t.Machine, clusterv1.MachineOwnerRemediatedV1Beta2Condition); ownerRemediatedCondition == nil || ownerRemediatedCondition.Status == metav1.ConditionTrue {
-					v1beta2conditions.Set(t.Machine, metav1.Condition{
-						Type:    clusterv1.MachineOwnerRemediatedV1Beta2Condition,
-						Status:  metav1.ConditionFalse,
-						Reason:  clusterv1.MachineOwnerRemediatedWaitingForRemediationV1Beta2Reason,
-						Message: "Waiting for remediation",
-					})
+				if remediateByOwner {
+					logger.Info("Machine has failed health check, marking for remediation", "reason", condition.Reason, "message", condition.Message)
+					// NOTE: MHC is responsible for creating MachineOwnerRemediatedCondition if missing or to trigger another remediation if the previous one is completed;
+					// instead, if a remediation is in already progress, the remediation owner is responsible for completing the process and MHC should not overwrite the condition.
+					if !conditions.Has(t.Machine, clusterv1.MachineOwnerRemediatedCondition) || conditions.IsTrue(t.Machine, clusterv1.MachineOwnerRemediatedCondition) {
+						conditions.MarkFalse(t.Machine, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "")
+					}
+
+					if ownerRemediatedCondition := v1beta2conditions.Get(t.Machine, clusterv1.MachineOwnerRemediatedV1Beta2Condition); ownerRemediatedCondition == nil || ownerRemediatedCondition.Status == metav1.ConditionTrue {
+						v1beta2conditions.Set(t.Machine, metav1.Condition{
+							Type:    clusterv1.MachineOwnerRemediatedV1Beta2Condition,
+							Status:  metav1.ConditionFalse,
+							Reason:  clusterv1.MachineOwnerRemediatedWaitingForRemediationV1Beta2Reason,
+							Message: "Waiting for remediation",
+						})
+					}
 				}
 			}
 		}
@@ -534,7 +590,7 @@ func (r *Reconciler) patchUnhealthyTargets(ctx context.Context, logger logr.Logg
 			klog.KObj(t.MHC),
 		)
 	}
-	return errList
+	return nextCheckTimes, errList
 }
 
 // clusterToMachineHealthCheck maps events from Cluster objects to
@@ -721,6 +777,37 @@ func unhealthyMachineCount(mhc *clusterv1.MachineHealthCheck) int {
 	return int(mhc.Status.ExpectedMachines - mhc.Status.CurrentHealthy)
 }
 
+// setUnhealthyExpressionsValidCondition reports whether the unhealthy expressions of the MachineHealthCheck can be
+// compiled; the condition is removed if the MachineHealthCheck has no unhealthy expressions.
+func setUnhealthyExpressionsValidCondition(mhc *clusterv1.MachineHealthCheck, compileErrs []error) {
+	if len(mhc.Spec.UnhealthyExpressions) == 0 {
+		conditions.Delete(mhc, clusterv1.UnhealthyExpressionsValidCondition)
+		v1beta2conditions.Delete(mhc, clusterv1.MachineHealthCheckUnhealthyExpressionsValidV1Beta2Condition)
+		return
+	}
+
+	if len(compileErrs) > 0 {
+		message := kerrors.NewAggregate(compileErrs).Error()
+		conditions.MarkFalse(mhc, clusterv1.UnhealthyExpressionsValidCondition, clusterv1.InvalidUnhealthyExpressionReason, clusterv1.ConditionSeverityError, "%s", message)
+
+		v1beta2conditions.Set(mhc, metav1.Condition{
+			Type:    clusterv1.MachineHealthCheckUnhealthyExpressionsValidV1Beta2Condition,
+			Status:  metav1.ConditionFalse,
+			Reason:  clusterv1.MachineHealthCheckInvalidUnhealthyExpressionV1Beta2Reason,
+			Message: message,
+		})
+		return
+	}
+
+	conditions.MarkTrue(mhc, clusterv1.UnhealthyExpressionsValidCondition)
+
+	v1beta2conditions.Set(mhc, metav1.Condition{
+		Type:   clusterv1.MachineHealthCheckUnhealthyExpressionsValidV1Beta2Condition,
+		Status: metav1.ConditionTrue,
+		Reason: clusterv1.MachineHealthCheckUnhealthyExpressionsValidV1Beta2Reason,
+	})
+}
+
 // getExternalRemediationRequest gets reference to External Remediation Request, unstructured object.
 func (r *Reconciler) getExternalRemediationRequest(ctx context.Context, m *clusterv1.MachineHealthCheck, machineName string) (*unstructured.Unstructured, error) {
 	remediationRef := &corev1.ObjectReference{
@@ -2378,6 +2378,75 @@ func TestGetMaxUnhealthy(t *testing.T) {
 	}
 }
 
+func TestSetUnhealthyExpressionsValidCondition(t *testing.T) {
+	t.Run("removes the condition when there are no unhealthy expressions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mhc := &clusterv1.MachineHealthCheck{}
+		conditions.MarkTrue(mhc, clusterv1.UnhealthyExpressionsValidCondition)
+		v1beta2conditions.Set(mhc, metav1.Condition{
+			Type:   clusterv1.MachineHealthCheckUnhealthyExpressionsValidV1Beta2Condition,
+			Status: metav1.ConditionTrue,
+			Reason: clusterv1.MachineHealthCheckUnhealthyExpressionsValidV1Beta2Reason,
+		})
+
+		_, compileErrs := compileUnhealthyExpressions(mhc)
+		setUnhealthyExpressionsValidCondition(mhc, compileErrs)
+
+		g.Expect(conditions.Has(mhc, clusterv1.UnhealthyExpressionsValidCondition)).To(BeFalse())
+		g.Expect(v1beta2conditions.Has(mhc, clusterv1.MachineHealthCheckUnhealthyExpressionsValidV1Beta2Condition)).To(BeFalse())
+	})
+
+	t.Run("reports valid unhealthy expressions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mhc := &clusterv1.MachineHealthCheck{
+			Spec: clusterv1.MachineHealthCheckSpec{
+				UnhealthyExpressions: []clusterv1.UnhealthyExpression{
+					{Name: "unschedulable", Expression: "node != null && node.spec.?unschedulable.orValue(false)"},
+				},
+			},
+		}
+
+		unhealthyExpressions, compileErrs := compileUnhealthyExpressions(mhc)
+		setUnhealthyExpressionsValidCondition(mhc, compileErrs)
+
+		g.Expect(unhealthyExpressions).To(HaveLen(1))
+		g.Expect(unhealthyExpressionsDependOnTime(unhealthyExpressions)).To(BeFalse())
+		g.Expect(conditions.IsTrue(mhc, clusterv1.UnhealthyExpressionsValidCondition)).To(BeTrue())
+		g.Expect(v1beta2conditions.IsTrue(mhc, clusterv1.MachineHealthCheckUnhealthyExpressionsValidV1Beta2Condition)).To(BeTrue())
+	})
+
+	t.Run("reports invalid unhealthy expressions and skips them", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mhc := &clusterv1.MachineHealthCheck{
+			Spec: clusterv1.MachineHealthCheckSpec{
+				UnhealthyExpressions: []clusterv1.UnhealthyExpression{
+					{Name: "invalid", Expression: "node.spec.unschedulable =="},
+					{Name: "not-ready", Expression: "node.status.conditions.exists(c, c.type == 'Ready' && c.status != 'True' && now - timestamp(c.lastTransitionTime) > duration('10m'))"},
+				},
+			},
+		}
+
+		unhealthyExpressions, compileErrs := compileUnhealthyExpressions(mhc)
+		setUnhealthyExpressionsValidCondition(mhc, compileErrs)
+
+		g.Expect(unhealthyExpressions).To(HaveLen(1))
+		g.Expect(unhealthyExpressions[0].name).To(Equal("not-ready"))
+		g.Expect(unhealthyExpressionsDependOnTime(unhealthyExpressions)).To(BeTrue())
+		g.Expect(conditions.IsFalse(mhc, clusterv1.UnhealthyExpressionsValidCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(mhc, clusterv1.UnhealthyExpressionsValidCondition)).To(Equal(clusterv1.InvalidUnhealthyExpressionReason))
+		g.Expect(conditions.GetMessage(mhc, clusterv1.UnhealthyExpressionsValidCondition)).To(ContainSubstring("failed to compile unhealthy expression invalid"))
+
+		c := v1beta2conditions.Get(mhc, clusterv1.MachineHealthCheckUnhealthyExpressionsValidV1Beta2Condition)
+		g.Expect(c).ToNot(BeNil())
+		g.Expect(c.Status).To(Equal(metav1.ConditionFalse))
+		g.Expect(c.Reason).To(Equal(clusterv1.MachineHealthCheckInvalidUnhealthyExpressionV1Beta2Reason))
+		g.Expect(c.Message).To(ContainSubstring("failed to compile unhealthy expression invalid"))
+	})
+}
+
 func ownerReferenceForCluster(ctx context.Context, g *WithT, c *clusterv1.Cluster) metav1.OwnerReference {
 	// Fetch the cluster to populate the UID
 	cc := &clusterv1.Cluster{}
@@ -2784,11 +2853,13 @@ func TestPatchTargets(t *testing.T) {
 	}
 
 	// Target with wrong patch helper will fail but the other one will be patched.
-	g.Expect(r.patchUnhealthyTargets(context.TODO(), logr.New(log.NullLogSink{}), []healthCheckTarget{target1, target3}, defaultCluster, mhc)).ToNot(BeEmpty())
+	_, errList := r.patchUnhealthyTargets(context.TODO(), logr.New(log.NullLogSink{}), []healthCheckTarget{target1, target3}, defaultCluster, mhc)
+	g.Expect(errList).ToNot(BeEmpty())
 	g.Expect(cl.Get(ctx, client.ObjectKey{Name: machine2.Name, Namespace: machine2.Namespace}, machine2)).ToNot(HaveOccurred())
 	g.Expect(conditions.Get(machine2, clusterv1.MachineOwnerRemediatedCondition).Status).To(Equal(corev1.ConditionFalse))
 	g.Expect(v1beta2conditions.Get(machine2, clusterv1.MachineOwnerRemediatedV1Beta2Condition).Status).To(Equal(metav1.ConditionFalse))
 
 	// Target with wrong patch helper will fail but the other one will be patched.
-	g.Expect(r.patchHealthyTargets(context.TODO(), logr.New(log.NullLogSink{}), []healthCheckTarget{target1, target3}, mhc)).ToNot(BeEmpty())
+	_, errList = r.patchHealthyTargets(context.TODO(), logr.New(log.NullLogSink{}), []healthCheckTarget{target1, target3}, mhc)
+	g.Expect(errList).ToNot(BeEmpty())
 }
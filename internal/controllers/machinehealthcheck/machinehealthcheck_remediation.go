@@ -0,0 +1,321 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinehealthcheck
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	v1beta2conditions "sigs.k8s.io/cluster-api/util/conditions/v1beta2"
+)
+
+const (
+	// EventRemediationStepStarted is emitted when an attempt of a remediation escalation step
+	// is started for an unhealthy machine.
+	EventRemediationStepStarted string = "RemediationStepStarted"
+
+	// remediationRequestDeletionRequeueAfter is the interval after which the remediation escalation of a machine
+	// is reconciled again while waiting for the remediation requests of previous attempts to be deleted.
+	remediationRequestDeletionRequeueAfter = 10 * time.Second
+)
+
+// hasRemediationEscalation returns true if the MachineHealthCheck has remediation escalation steps.
+func hasRemediationEscalation(m *clusterv1.MachineHealthCheck) bool {
+	return m.Spec.Remediation != nil && len(m.Spec.Remediation.Escalation) > 0
+}
+
+// reconcileRemediationEscalation remediates an unhealthy Machine through the remediation escalation steps of the
+// MachineHealthCheck, and it keeps track of the attempts of each step in the Machine status.
+// It returns true if all the steps have been exhausted and the Machine must be remediated by its owner,
+// and the duration after which the remediation escalation of the Machine must be reconciled again.
+func (r *Reconciler) reconcileRemediationEscalation(ctx context.Context, logger logr.Logger, cluster *clusterv1.Cluster, m *clusterv1.MachineHealthCheck, machine *clusterv1.Machine, now time.Time) (bool, time.Duration, error) {
+	if machine.Status.Remediation == nil {
+		machine.Status.Remediation = &clusterv1.MachineRemediationStatus{
+			StartTime: ptr.To(metav1.NewTime(now)),
+		}
+	}
+	status := machine.Status.Remediation
+	steps := m.Spec.Remediation.Escalation
+
+	index, startAttempt := nextRemediationStep(steps, status, now)
+	if index == len(steps) {
+		// Delete the remediation requests of the last attempts before handing off the Machine to its owner.
+		if _, err := r.deleteRemediationRequests(ctx, m, machine); err != nil {
+			return false, 0, err
+		}
+		if status.CurrentStep != "" {
+			logger.Info("Machine is still unhealthy after all the remediation escalation steps, marking for remediation by its owner")
+			status.CurrentStep = ""
+		}
+		v1beta2conditions.Delete(machine, clusterv1.MachineExternallyRemediatedV1Beta2Condition)
+		return true, 0, nil
+	}
+
+	step := steps[index]
+	status.CurrentStep = step.Name
+	stepStatus := getRemediationStepStatus(status, step.Name)
+	if stepStatus == nil {
+		status.Steps = append(status.Steps, clusterv1.MachineRemediationStepStatus{Name: step.Name})
+		stepStatus = &status.Steps[len(status.Steps)-1]
+	}
+
+	// The current attempt is still in progress, check again once the attempt times out.
+	if !startAttempt {
+		return false, stepStatus.TimeoutTime.Sub(now), nil
+	}
+
+	// Ensure the remediation requests of previous attempts are gone before starting a new attempt,
+	// so different external remediation controllers are not acting on the Machine at the same time.
+	pending, err := r.deleteRemediationRequests(ctx, m, machine)
+	if err != nil {
+		return false, 0, err
+	}
+	if pending {
+		logger.V(3).Info("Waiting for the remediation requests of previous attempts to be deleted", "step", step.Name)
+		return false, remediationRequestDeletionRequeueAfter, nil
+	}
+
+	attempt := stepStatus.Attempts + 1
+	maxAttempts := remediationStepMaxAttempts(step)
+	switch {
+	case step.TemplateRef != nil:
+		if err := r.createRemediationRequest(ctx, step.TemplateRef, machine); err != nil {
+			v1beta2conditions.Set(machine, metav1.Condition{
+				Type:    clusterv1.MachineExternallyRemediatedV1Beta2Condition,
+				Status:  metav1.ConditionFalse,
+				Reason:  clusterv1.MachineExternallyRemediatedRemediationRequestCreationFailedV1Beta2Reason,
+				Message: fmt.Sprintf("Failed to create remediation request for remediation step %s; please check controller logs for errors", step.Name),
+			})
+			return false, 0, errors.Wrapf(err, "failed to start remediation step %q for Machine %s", step.Name, klog.KObj(machine))
+		}
+	case step.Extension != nil:
+		if err := r.callRemediateMachineExtension(ctx, cluster, machine, step, attempt); err != nil {
+			return false, 0, errors.Wrapf(err, "failed to start remediation step %q for Machine %s", step.Name, klog.KObj(machine))
+		}
+	}
+
+	timeout := remediationStepTimeout(step)
+	stepStatus.Attempts = attempt
+	stepStatus.LastAttemptTime = ptr.To(metav1.NewTime(now))
+	stepStatus.TimeoutTime = ptr.To(metav1.NewTime(now.Add(timeout)))
+
+	v1beta2conditions.Set(machine, metav1.Condition{
+		Type:    clusterv1.MachineExternallyRemediatedV1Beta2Condition,
+		Status:  metav1.ConditionFalse,
+		Reason:  clusterv1.MachineExternallyRemediatedWaitingForRemediationV1Beta2Reason,
+		Message: fmt.Sprintf("Waiting for remediation step %s (attempt %d of %d)", step.Name, attempt, maxAttempts),
+	})
+
+	logger.Info("Machine has failed health check, starting remediation step", "step", step.Name, "action", step.Action, "attempt", attempt, "maxAttempts", maxAttempts)
+	r.recorder.Eventf(
+		machine,
+		corev1.EventTypeNormal,
+		EventRemediationStepStarted,
+		"Started attempt %d of %d of remediation step %s (%s) for Machine %s",
+		attempt,
+		maxAttempts,
+		step.Name,
+		step.Action,
+		klog.KObj(machine),
+	)
+	return false, timeout, nil
+}
+
+// reconcileHealthyRemediationEscalation resets the remediation escalation of a healthy Machine, deleting the remediation
+// requests created by the remediation escalation steps, only once the Machine has stayed healthy for the stabilization
+// period, so a Machine flapping between healthy and unhealthy does not restart from the first step.
+// It returns the duration after which the Machine must be checked again, if any.
+func (r *Reconciler) reconcileHealthyRemediationEscalation(ctx context.Context, logger logr.Logger, m *clusterv1.MachineHealthCheck, machine *clusterv1.Machine, now time.Time) (time.Duration, error) {
+	if machine.Status.Remediation == nil {
+		return 0, nil
+	}
+
+	healthySince := now
+	if lastTransitionTime := conditions.GetLastTransitionTime(machine, clusterv1.MachineHealthCheckSucceededCondition); lastTransitionTime != nil {
+		healthySince = lastTransitionTime.Time
+	}
+	if remaining := remediationStabilizationPeriod(m) - now.Sub(healthySince); remaining > 0 {
+		return remaining, nil
+	}
+
+	if hasRemediationEscalation(m) {
+		if _, err := r.deleteRemediationRequests(ctx, m, machine); err != nil {
+			return 0, err
+		}
+	}
+	logger.V(3).Info("Machine has been healthy for the stabilization period, resetting remediation escalation")
+	machine.Status.Remediation = nil
+	return 0, nil
+}
+
+// nextRemediationStep returns the index of the remediation step for a Machine and true if a new attempt of the
+// step must be started. The returned index is equal to the number of steps if all the steps have been exhausted.
+func nextRemediationStep(steps []clusterv1.MachineHealthCheckRemediationStep, status *clusterv1.MachineRemediationStatus, now time.Time) (int, bool) {
+	for i, step := range steps {
+		stepStatus := getRemediationStepStatus(status, step.Name)
+		if stepStatus == nil || stepStatus.Attempts == 0 {
+			return i, true
+		}
+		if stepStatus.TimeoutTime != nil && now.Before(stepStatus.TimeoutTime.Time) {
+			return i, false
+		}
+		if stepStatus.Attempts < remediationStepMaxAttempts(step) {
+			return i, true
+		}
+	}
+	return len(steps), false
+}
+
+// getRemediationStepStatus returns the status of the remediation step with the given name, if any.
+func getRemediationStepStatus(status *clusterv1.MachineRemediationStatus, name string) *clusterv1.MachineRemediationStepStatus {
+	if status == nil {
+		return nil
+	}
+	for i := range status.Steps {
+		if status.Steps[i].Name == name {
+			return &status.Steps[i]
+		}
+	}
+	return nil
+}
+
+func remediationStepMaxAttempts(step clusterv1.MachineHealthCheckRemediationStep) int32 {
+	if step.MaxAttempts == nil {
+		return 1
+	}
+	return *step.MaxAttempts
+}
+
+func remediationStepTimeout(step clusterv1.MachineHealthCheckRemediationStep) time.Duration {
+	if step.Timeout == nil {
+		return clusterv1.DefaultRemediationStepTimeout.Duration
+	}
+	return step.Timeout.Duration
+}
+
+func remediationStabilizationPeriod(m *clusterv1.MachineHealthCheck) time.Duration {
+	if m.Spec.Remediation == nil || m.Spec.Remediation.StabilizationPeriod == nil {
+		return clusterv1.DefaultRemediationStabilizationPeriod.Duration
+	}
+	return m.Spec.Remediation.StabilizationPeriod.Duration
+}
+
+// createRemediationRequest creates a remediation request for a Machine from a remediation template.
+// The remediation request has the same name of the Machine, and it is owned by the Machine.
+func (r *Reconciler) createRemediationRequest(ctx context.Context, templateRef *corev1.ObjectReference, machine *clusterv1.Machine) error {
+	from, err := external.Get(ctx, r.Client, templateRef)
+	if err != nil {
+		return errors.Wrapf(err, "error retrieving remediation template %v %q", templateRef.GroupVersionKind(), templateRef.Name)
+	}
+
+	to, err := external.GenerateTemplate(&external.GenerateTemplateInput{
+		Template:    from,
+		TemplateRef: templateRef,
+		Namespace:   machine.Namespace,
+		ClusterName: machine.Spec.ClusterName,
+		OwnerRef: &metav1.OwnerReference{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Machine",
+			Name:       machine.Name,
+			UID:        machine.UID,
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create template for remediation request %v %q", templateRef.GroupVersionKind(), templateRef.Name)
+	}
+	to.SetName(machine.Name)
+
+	if err := r.Client.Create(ctx, to); err != nil {
+		return errors.Wrapf(err, "error creating remediation request %v %q", to.GroupVersionKind(), to.GetName())
+	}
+	return nil
+}
+
+// deleteRemediationRequests deletes the remediation requests created for a Machine by the remediation escalation
+// steps of the MachineHealthCheck. It returns true if there are remediation requests still being deleted.
+func (r *Reconciler) deleteRemediationRequests(ctx context.Context, m *clusterv1.MachineHealthCheck, machine *clusterv1.Machine) (bool, error) {
+	pending := false
+	seen := sets.Set[string]{}
+	for _, step := range m.Spec.Remediation.Escalation {
+		if step.TemplateRef == nil {
+			continue
+		}
+		remediationRef := &corev1.ObjectReference{
+			APIVersion: step.TemplateRef.APIVersion,
+			Kind:       strings.TrimSuffix(step.TemplateRef.Kind, clusterv1.TemplateSuffix),
+			Name:       machine.Name,
+			Namespace:  machine.Namespace,
+		}
+		gvk := remediationRef.GroupVersionKind().String()
+		if seen.Has(gvk) {
+			continue
+		}
+		seen.Insert(gvk)
+
+		obj, err := external.Get(ctx, r.Client, remediationRef)
+		if err != nil {
+			if apierrors.IsNotFound(errors.Cause(err)) {
+				continue
+			}
+			return false, errors.Wrapf(err, "failed to fetch remediation request %v %q", gvk, machine.Name)
+		}
+		pending = true
+		// Check that obj has no DeletionTimestamp to avoid hot loop
+		if obj.GetDeletionTimestamp() == nil {
+			if err := r.Client.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+				return false, errors.Wrapf(err, "failed to delete %v %q for Machine %q", obj.GroupVersionKind(), obj.GetName(), machine.Name)
+			}
+		}
+	}
+	return pending, nil
+}
+
+// callRemediateMachineExtension calls the RemediateMachine hook of the Runtime Extension of a remediation step.
+func (r *Reconciler) callRemediateMachineExtension(ctx context.Context, cluster *clusterv1.Cluster, machine *clusterv1.Machine, step clusterv1.MachineHealthCheckRemediationStep, attempt int32) error {
+	if !feature.Gates.Enabled(feature.RuntimeSDK) || r.RuntimeClient == nil {
+		return errors.Errorf("failed to call Runtime Extension %q: the %s feature gate is not enabled", *step.Extension, feature.RuntimeSDK)
+	}
+
+	request := &runtimehooksv1.RemediateMachineRequest{
+		Cluster: *cluster,
+		Machine: *machine,
+		Step:    step.Name,
+		Action:  step.Action,
+		Attempt: attempt,
+	}
+	response := &runtimehooksv1.RemediateMachineResponse{}
+	if err := r.RuntimeClient.CallExtension(ctx, runtimehooksv1.RemediateMachine, machine, *step.Extension, request, response); err != nil {
+		return errors.Wrapf(err, "failed to call Runtime Extension %q", *step.Extension)
+	}
+	return nil
+}
@@ -0,0 +1,340 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinehealthcheck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
+	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/feature"
+	fakeruntimeclient "sigs.k8s.io/cluster-api/internal/runtime/client/fake"
+	v1beta2conditions "sigs.k8s.io/cluster-api/util/conditions/v1beta2"
+	"sigs.k8s.io/cluster-api/util/test/builder"
+)
+
+func TestNextRemediationStep(t *testing.T) {
+	now := time.Now()
+
+	steps := []clusterv1.MachineHealthCheckRemediationStep{
+		{
+			Name:        "reboot",
+			Action:      clusterv1.MachineHealthCheckRemediationActionReboot,
+			MaxAttempts: ptr.To[int32](2),
+		},
+		{
+			Name:   "reprovision",
+			Action: clusterv1.MachineHealthCheckRemediationActionReprovision,
+		},
+	}
+
+	tests := []struct {
+		name             string
+		status           *clusterv1.MachineRemediationStatus
+		wantIndex        int
+		wantStartAttempt bool
+	}{
+		{
+			name:             "Start the first step if the remediation did not start yet",
+			status:           nil,
+			wantIndex:        0,
+			wantStartAttempt: true,
+		},
+		{
+			name: "Wait for the current attempt if it did not time out yet",
+			status: &clusterv1.MachineRemediationStatus{
+				Steps: []clusterv1.MachineRemediationStepStatus{
+					{Name: "reboot", Attempts: 1, TimeoutTime: ptr.To(metav1.NewTime(now.Add(time.Minute)))},
+				},
+			},
+			wantIndex:        0,
+			wantStartAttempt: false,
+		},
+		{
+			name: "Start another attempt of the current step if the attempt timed out",
+			status: &clusterv1.MachineRemediationStatus{
+				Steps: []clusterv1.MachineRemediationStepStatus{
+					{Name: "reboot", Attempts: 1, TimeoutTime: ptr.To(metav1.NewTime(now.Add(-time.Minute)))},
+				},
+			},
+			wantIndex:        0,
+			wantStartAttempt: true,
+		},
+		{
+			name: "Start the next step if all the attempts of the current step timed out",
+			status: &clusterv1.MachineRemediationStatus{
+				Steps: []clusterv1.MachineRemediationStepStatus{
+					{Name: "reboot", Attempts: 2, TimeoutTime: ptr.To(metav1.NewTime(now.Add(-time.Minute)))},
+				},
+			},
+			wantIndex:        1,
+			wantStartAttempt: true,
+		},
+		{
+			name: "Report all the steps exhausted if all the attempts of the last step timed out",
+			status: &clusterv1.MachineRemediationStatus{
+				Steps: []clusterv1.MachineRemediationStepStatus{
+					{Name: "reboot", Attempts: 2, TimeoutTime: ptr.To(metav1.NewTime(now.Add(-20 * time.Minute)))},
+					{Name: "reprovision", Attempts: 1, TimeoutTime: ptr.To(metav1.NewTime(now.Add(-time.Minute)))},
+				},
+			},
+			wantIndex:        2,
+			wantStartAttempt: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			index, startAttempt := nextRemediationStep(steps, tt.status, now)
+			g.Expect(index).To(Equal(tt.wantIndex))
+			g.Expect(startAttempt).To(Equal(tt.wantStartAttempt))
+		})
+	}
+}
+
+func TestReconcileRemediationEscalation(t *testing.T) {
+	utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.RuntimeSDK, true)
+	g := NewWithT(t)
+
+	catalog := runtimecatalog.New()
+	_ = runtimehooksv1.AddToCatalog(catalog)
+
+	namespace := metav1.NamespaceDefault
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testClusterName,
+			Namespace: namespace,
+		},
+	}
+	machine := newTestMachine("machine", namespace, testClusterName, "node", map[string]string{})
+	mhc := newMachineHealthCheckWithLabels("mhc", namespace, testClusterName, map[string]string{})
+	mhc.Spec.Remediation = &clusterv1.MachineHealthCheckRemediation{
+		Escalation: []clusterv1.MachineHealthCheckRemediationStep{
+			{
+				Name:   "reboot",
+				Action: clusterv1.MachineHealthCheckRemediationActionReboot,
+				TemplateRef: &corev1.ObjectReference{
+					APIVersion: builder.RemediationGroupVersion.String(),
+					Kind:       "GenericExternalRemediationTemplate",
+					Name:       "reboot",
+					Namespace:  namespace,
+				},
+				Timeout:     &metav1.Duration{Duration: 5 * time.Minute},
+				MaxAttempts: ptr.To[int32](2),
+			},
+			{
+				Name:      "reprovision",
+				Action:    clusterv1.MachineHealthCheckRemediationActionReprovision,
+				Extension: ptr.To("reprovision"),
+			},
+		},
+	}
+	remediationTemplate := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": builder.RemediationGroupVersion.String(),
+			"kind":       "GenericExternalRemediationTemplate",
+			"metadata": map[string]interface{}{
+				"name":      "reboot",
+				"namespace": namespace,
+			},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{},
+				},
+			},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithObjects(remediationTemplate).Build()
+	r := &Reconciler{
+		Client: c,
+		RuntimeClient: fakeruntimeclient.NewRuntimeClientBuilder().
+			WithCatalog(catalog).
+			WithCallExtensionResponses(map[string]runtimehooksv1.ResponseObject{
+				"reprovision": &runtimehooksv1.RemediateMachineResponse{
+					CommonResponse: runtimehooksv1.CommonResponse{Status: runtimehooksv1.ResponseStatusSuccess},
+				},
+			}).
+			Build(),
+		recorder: record.NewFakeRecorder(32),
+	}
+	logger := logr.New(log.NullLogSink{})
+
+	remediationRequestKey := client.ObjectKey{Namespace: namespace, Name: machine.Name}
+	getRemediationRequest := func() error {
+		remediationRequest := &unstructured.Unstructured{}
+		remediationRequest.SetAPIVersion(builder.RemediationGroupVersion.String())
+		remediationRequest.SetKind("GenericExternalRemediation")
+		return c.Get(ctx, remediationRequestKey, remediationRequest)
+	}
+
+	now := time.Now()
+
+	// The first attempt of the first step creates a remediation request.
+	exhausted, requeueAfter, err := r.reconcileRemediationEscalation(ctx, logger, cluster, mhc, machine, now)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(exhausted).To(BeFalse())
+	g.Expect(requeueAfter).To(Equal(5 * time.Minute))
+	g.Expect(getRemediationRequest()).To(Succeed())
+	g.Expect(machine.Status.Remediation.CurrentStep).To(Equal("reboot"))
+	g.Expect(machine.Status.Remediation.Steps).To(HaveLen(1))
+	g.Expect(machine.Status.Remediation.Steps[0].Attempts).To(Equal(int32(1)))
+	g.Expect(v1beta2conditions.Get(machine, clusterv1.MachineExternallyRemediatedV1Beta2Condition).Message).To(Equal("Waiting for remediation step reboot (attempt 1 of 2)"))
+
+	// Wait for the first attempt to time out.
+	now = now.Add(2 * time.Minute)
+	exhausted, requeueAfter, err = r.reconcileRemediationEscalation(ctx, logger, cluster, mhc, machine, now)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(exhausted).To(BeFalse())
+	g.Expect(requeueAfter).To(Equal(3 * time.Minute))
+	g.Expect(machine.Status.Remediation.Steps[0].Attempts).To(Equal(int32(1)))
+
+	// The second attempt of the first step deletes the previous remediation request before creating a new one.
+	now = now.Add(4 * time.Minute)
+	exhausted, requeueAfter, err = r.reconcileRemediationEscalation(ctx, logger, cluster, mhc, machine, now)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(exhausted).To(BeFalse())
+	g.Expect(requeueAfter).To(Equal(remediationRequestDeletionRequeueAfter))
+	g.Expect(apierrors.IsNotFound(getRemediationRequest())).To(BeTrue())
+	g.Expect(machine.Status.Remediation.Steps[0].Attempts).To(Equal(int32(1)))
+
+	exhausted, requeueAfter, err = r.reconcileRemediationEscalation(ctx, logger, cluster, mhc, machine, now)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(exhausted).To(BeFalse())
+	g.Expect(requeueAfter).To(Equal(5 * time.Minute))
+	g.Expect(getRemediationRequest()).To(Succeed())
+	g.Expect(machine.Status.Remediation.Steps[0].Attempts).To(Equal(int32(2)))
+
+	// After all the attempts of the first step, the remediation request is deleted and the second step calls the extension.
+	now = now.Add(6 * time.Minute)
+	exhausted, requeueAfter, err = r.reconcileRemediationEscalation(ctx, logger, cluster, mhc, machine, now)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(exhausted).To(BeFalse())
+	g.Expect(requeueAfter).To(Equal(remediationRequestDeletionRequeueAfter))
+	g.Expect(apierrors.IsNotFound(getRemediationRequest())).To(BeTrue())
+
+	exhausted, requeueAfter, err = r.reconcileRemediationEscalation(ctx, logger, cluster, mhc, machine, now)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(exhausted).To(BeFalse())
+	g.Expect(requeueAfter).To(Equal(clusterv1.DefaultRemediationStepTimeout.Duration))
+	g.Expect(machine.Status.Remediation.CurrentStep).To(Equal("reprovision"))
+	g.Expect(machine.Status.Remediation.Steps).To(HaveLen(2))
+	g.Expect(machine.Status.Remediation.Steps[1].Attempts).To(Equal(int32(1)))
+
+	// After all the steps, the Machine is remediated by its owner.
+	now = now.Add(clusterv1.DefaultRemediationStepTimeout.Duration)
+	exhausted, _, err = r.reconcileRemediationEscalation(ctx, logger, cluster, mhc, machine, now)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(exhausted).To(BeTrue())
+	g.Expect(machine.Status.Remediation.CurrentStep).To(BeEmpty())
+	g.Expect(v1beta2conditions.Get(machine, clusterv1.MachineExternallyRemediatedV1Beta2Condition)).To(BeNil())
+}
+
+func TestReconcileHealthyRemediationEscalation(t *testing.T) {
+	g := NewWithT(t)
+
+	namespace := metav1.NamespaceDefault
+	mhc := newMachineHealthCheckWithLabels("mhc", namespace, testClusterName, map[string]string{})
+	mhc.Spec.Remediation = &clusterv1.MachineHealthCheckRemediation{
+		Escalation: []clusterv1.MachineHealthCheckRemediationStep{
+			{
+				Name:   "reboot",
+				Action: clusterv1.MachineHealthCheckRemediationActionReboot,
+				TemplateRef: &corev1.ObjectReference{
+					APIVersion: builder.RemediationGroupVersion.String(),
+					Kind:       "GenericExternalRemediationTemplate",
+					Name:       "reboot",
+					Namespace:  namespace,
+				},
+			},
+		},
+		StabilizationPeriod: &metav1.Duration{Duration: 5 * time.Minute},
+	}
+
+	now := time.Now()
+	machine := newTestMachine("machine", namespace, testClusterName, "node", map[string]string{})
+	machine.Status.Remediation = &clusterv1.MachineRemediationStatus{
+		StartTime:   ptr.To(metav1.NewTime(now.Add(-10 * time.Minute))),
+		CurrentStep: "reboot",
+		Steps: []clusterv1.MachineRemediationStepStatus{
+			{Name: "reboot", Attempts: 1},
+		},
+	}
+	machine.Status.Conditions = clusterv1.Conditions{
+		{
+			Type:               clusterv1.MachineHealthCheckSucceededCondition,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(now.Add(-2 * time.Minute)),
+		},
+	}
+	remediationRequest := &unstructured.Unstructured{}
+	remediationRequest.SetAPIVersion(builder.RemediationGroupVersion.String())
+	remediationRequest.SetKind("GenericExternalRemediation")
+	remediationRequest.SetName(machine.Name)
+	remediationRequest.SetNamespace(namespace)
+
+	c := fake.NewClientBuilder().WithObjects(remediationRequest).Build()
+	r := &Reconciler{
+		Client:   c,
+		recorder: record.NewFakeRecorder(32),
+	}
+	logger := logr.New(log.NullLogSink{})
+
+	getRemediationRequest := func() error {
+		return c.Get(ctx, client.ObjectKeyFromObject(remediationRequest), remediationRequest.DeepCopy())
+	}
+
+	// The remediation escalation is kept while the Machine has been healthy for less than the stabilization period.
+	requeueAfter, err := r.reconcileHealthyRemediationEscalation(ctx, logger, mhc, machine, now)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(requeueAfter).To(Equal(3 * time.Minute))
+	g.Expect(machine.Status.Remediation).ToNot(BeNil())
+	g.Expect(machine.Status.Remediation.CurrentStep).To(Equal("reboot"))
+	g.Expect(getRemediationRequest()).To(Succeed())
+
+	// The remediation escalation is reset and the remediation requests are deleted after the stabilization period.
+	now = now.Add(3 * time.Minute)
+	requeueAfter, err = r.reconcileHealthyRemediationEscalation(ctx, logger, mhc, machine, now)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(requeueAfter).To(BeZero())
+	g.Expect(machine.Status.Remediation).To(BeNil())
+	g.Expect(apierrors.IsNotFound(getRemediationRequest())).To(BeTrue())
+
+	// Nothing is done for Machines without a remediation escalation in progress.
+	remediationRequest.SetResourceVersion("")
+	g.Expect(c.Create(ctx, remediationRequest)).To(Succeed())
+	requeueAfter, err = r.reconcileHealthyRemediationEscalation(ctx, logger, mhc, machine, now)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(requeueAfter).To(BeZero())
+	g.Expect(getRemediationRequest()).To(Succeed())
+}
@@ -31,6 +31,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/controllers/machinehealthcheck/expression"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -64,6 +65,39 @@ type healthCheckTarget struct {
 	nodeMissing bool
 }
 
+// unhealthyExpression is an unhealthy expression of a MachineHealthCheck compiled into a program.
+type unhealthyExpression struct {
+	name    string
+	program *expression.Program
+}
+
+// compileUnhealthyExpressions compiles the unhealthy expressions of a MachineHealthCheck, so they are compiled once
+// per reconcile and not for each target; expressions that cannot be compiled are skipped, and the compile errors
+// are returned.
+func compileUnhealthyExpressions(mhc *clusterv1.MachineHealthCheck) ([]unhealthyExpression, []error) {
+	var unhealthyExpressions []unhealthyExpression
+	var errs []error
+	for _, e := range mhc.Spec.UnhealthyExpressions {
+		program, err := expression.Compile(e.Expression)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to compile unhealthy expression %s", e.Name))
+			continue
+		}
+		unhealthyExpressions = append(unhealthyExpressions, unhealthyExpression{name: e.Name, program: program})
+	}
+	return unhealthyExpressions, errs
+}
+
+// unhealthyExpressionsDependOnTime returns true if any of the unhealthy expressions depends on the current time.
+func unhealthyExpressionsDependOnTime(unhealthyExpressions []unhealthyExpression) bool {
+	for _, e := range unhealthyExpressions {
+		if e.program.DependsOnTime() {
+			return true
+		}
+	}
+	return false
+}
+
 // Get the node name if the target has a node.
 func (t *healthCheckTarget) nodeName() string {
 	if t.Node != nil {
@@ -78,11 +112,13 @@ func (t *healthCheckTarget) nodeName() string {
 // - The Machine has failed for some reason
 // - The Machine did not get a node before `timeoutForMachineToHaveNode` elapses
 // - The Node has gone away
+// - Any condition on the machine is matched for the given timeout
+// - Any unhealthy expression evaluates to true
 // - Any condition on the node is matched for the given timeout
 // If the target doesn't currently need rememdiation, provide a duration after
 // which the target should next be checked.
 // The target should be requeued after this duration.
-func (t *healthCheckTarget) needsRemediation(logger logr.Logger, timeoutForMachineToHaveNode metav1.Duration) (bool, time.Duration) {
+func (t *healthCheckTarget) needsRemediation(logger logr.Logger, timeoutForMachineToHaveNode metav1.Duration, unhealthyExpressions []unhealthyExpression) (bool, time.Duration) {
 	var nextCheckTimes []time.Duration
 	now := time.Now()
 
@@ -140,12 +176,65 @@ func (t *healthCheckTarget) needsRemediation(logger logr.Logger, timeoutForMachi
 		return false, 0
 	}
 
+	// check machine conditions
+	for _, c := range t.MHC.Spec.UnhealthyMachineConditions {
+		status, lastTransitionTime, found := getMachineCondition(t.Machine, c.Type)
+
+		// Skip when current machine condition is different from the one reported
+		// in the MachineHealthCheck.
+		if !found || status != c.Status {
+			continue
+		}
+
+		// If the condition has been in the unhealthy state for longer than the
+		// timeout, return true with no requeue time.
+		if lastTransitionTime.Add(c.Timeout.Duration).Before(now) {
+			conditions.MarkFalse(t.Machine, clusterv1.MachineHealthCheckSucceededCondition, clusterv1.UnhealthyMachineConditionReason, clusterv1.ConditionSeverityWarning, "Condition %s on machine is reporting status %s for more than %s", c.Type, c.Status, c.Timeout.Duration.String())
+			logger.V(3).Info("Target is unhealthy: machine condition is in state longer than allowed timeout", "condition", c.Type, "state", c.Status, "timeout", c.Timeout.Duration.String())
+
+			v1beta2conditions.Set(t.Machine, metav1.Condition{
+				Type:    clusterv1.MachineHealthCheckSucceededV1Beta2Condition,
+				Status:  metav1.ConditionFalse,
+				Reason:  clusterv1.MachineHealthCheckUnhealthyMachineV1Beta2Reason,
+				Message: fmt.Sprintf("Health check failed: Condition %s on Machine is reporting status %s for more than %s", c.Type, c.Status, c.Timeout.Duration.String()),
+			})
+			return true, time.Duration(0)
+		}
+
+		durationUnhealthy := now.Sub(lastTransitionTime)
+		nextCheck := c.Timeout.Duration - durationUnhealthy + time.Second
+		if nextCheck > 0 {
+			nextCheckTimes = append(nextCheckTimes, nextCheck)
+		}
+	}
+
+	// check unhealthy expressions
+	for _, e := range unhealthyExpressions {
+		unhealthy, err := e.program.Evaluate(t.Machine, t.Node, now)
+		if err != nil {
+			logger.V(3).Info("Failed to evaluate unhealthy expression", "expression", e.name, "err", err.Error())
+			continue
+		}
+		if unhealthy {
+			conditions.MarkFalse(t.Machine, clusterv1.MachineHealthCheckSucceededCondition, clusterv1.UnhealthyExpressionReason, clusterv1.ConditionSeverityWarning, "Expression %s evaluated to true", e.name)
+			logger.V(3).Info("Target is unhealthy: expression evaluated to true", "expression", e.name)
+
+			v1beta2conditions.Set(t.Machine, metav1.Condition{
+				Type:    clusterv1.MachineHealthCheckSucceededV1Beta2Condition,
+				Status:  metav1.ConditionFalse,
+				Reason:  clusterv1.MachineHealthCheckUnhealthyExpressionV1Beta2Reason,
+				Message: fmt.Sprintf("Health check failed: Expression %s evaluated to true", e.name),
+			})
+			return true, time.Duration(0)
+		}
+	}
+
 	// the node has not been set yet
 	if t.Node == nil {
 		if timeoutForMachineToHaveNode == disabledNodeStartupTimeout {
 			// Startup timeout is disabled so no need to go any further.
 			// No node yet to check conditions, can return early here.
-			return false, 0
+			return false, minDuration(nextCheckTimes)
 		}
 
 		controlPlaneInitialized := conditions.GetLastTransitionTime(t.Cluster, clusterv1.ControlPlaneInitializedCondition)
@@ -189,7 +278,7 @@ func (t *healthCheckTarget) needsRemediation(logger logr.Logger, timeoutForMachi
 		durationUnhealthy := now.Sub(comparisonTime)
 		nextCheck := timeoutDuration - durationUnhealthy + time.Second
 
-		return false, nextCheck
+		return false, minDuration(append(nextCheckTimes, nextCheck))
 	}
 
 	// check conditions
@@ -316,7 +405,7 @@ func (r *Reconciler) getNodeFromMachine(ctx context.Context, clusterClient clien
 
 // healthCheckTargets health checks a slice of targets
 // and gives a data to measure the average health.
-func (r *Reconciler) healthCheckTargets(targets []healthCheckTarget, logger logr.Logger, timeoutForMachineToHaveNode metav1.Duration) ([]healthCheckTarget, []healthCheckTarget, []time.Duration) {
+func (r *Reconciler) healthCheckTargets(targets []healthCheckTarget, logger logr.Logger, timeoutForMachineToHaveNode metav1.Duration, unhealthyExpressions []unhealthyExpression) ([]healthCheckTarget, []healthCheckTarget, []time.Duration) {
 	var nextCheckTimes []time.Duration
 	var unhealthy []healthCheckTarget
 	var healthy []healthCheckTarget
@@ -324,7 +413,7 @@ func (r *Reconciler) healthCheckTargets(targets []healthCheckTarget, logger logr
 	for _, t := range targets {
 		logger := logger.WithValues("Machine", klog.KObj(t.Machine), "Node", klog.KObj(t.Node))
 		logger.V(3).Info("Health checking target")
-		needsRemediation, nextCheck := t.needsRemediation(logger, timeoutForMachineToHaveNode)
+		needsRemediation, nextCheck := t.needsRemediation(logger, timeoutForMachineToHaveNode, unhealthyExpressions)
 
 		if needsRemediation {
 			unhealthy = append(unhealthy, t)
@@ -359,6 +448,19 @@ func (r *Reconciler) healthCheckTargets(targets []healthCheckTarget, logger logr
 	return healthy, unhealthy, nextCheckTimes
 }
 
+// getMachineCondition returns status and last transition time of a machine condition by type.
+// If a condition with the given type exists both in the v1beta2 conditions and in the v1beta1 conditions,
+// the v1beta2 condition takes precedence.
+func getMachineCondition(machine *clusterv1.Machine, conditionType string) (metav1.ConditionStatus, time.Time, bool) {
+	if c := v1beta2conditions.Get(machine, conditionType); c != nil {
+		return c.Status, c.LastTransitionTime.Time, true
+	}
+	if c := conditions.Get(machine, clusterv1.ConditionType(conditionType)); c != nil {
+		return metav1.ConditionStatus(c.Status), c.LastTransitionTime.Time, true
+	}
+	return "", time.Time{}, false
+}
+
 // getNodeCondition returns node condition by type.
 func getNodeCondition(node *corev1.Node, conditionType corev1.NodeConditionType) *corev1.NodeCondition {
 	for _, cond := range node.Status.Conditions {
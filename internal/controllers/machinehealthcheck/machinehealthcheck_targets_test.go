@@ -423,6 +423,74 @@ func TestHealthCheckTargets(t *testing.T) {
 	machineAnnotationRemediationCondition := newFailedHealthCheckCondition(clusterv1.HasRemediateMachineAnnotationReason, annotationRemediationMsg)
 	machineAnnotationRemediationV1Beta2Condition := newFailedHealthCheckV1Beta2Condition(clusterv1.MachineHealthCheckHasRemediateAnnotationV1Beta2Reason, annotationRemediationV1Beta2Msg)
 
+	// Create a test MHC with unhealthy machine conditions and unhealthy expressions
+	testMHCMachineChecks := testMHC.DeepCopy()
+	testMHCMachineChecks.Spec.UnhealthyMachineConditions = []clusterv1.UnhealthyMachineCondition{
+		{
+			Type:    string(clusterv1.InfrastructureReadyCondition),
+			Status:  metav1.ConditionFalse,
+			Timeout: metav1.Duration{Duration: timeoutForUnhealthyConditions},
+		},
+	}
+	testMHCMachineChecks.Spec.UnhealthyExpressions = []clusterv1.UnhealthyExpression{
+		{
+			Name:       "kernel-deadlock",
+			Expression: "node != null && node.metadata.?labels['problem.example.com/kernel-deadlock'].orValue('') == 'true'",
+		},
+	}
+
+	// Target for when the machine condition has been in the unhealthy state for shorter than the timeout
+	testMachineInfraNotReady200 := testMachine.DeepCopy()
+	conditions.Set(testMachineInfraNotReady200, &clusterv1.Condition{
+		Type:               clusterv1.InfrastructureReadyCondition,
+		Status:             corev1.ConditionFalse,
+		Severity:           clusterv1.ConditionSeverityError,
+		LastTransitionTime: metav1.NewTime(time.Now().Add(-200 * time.Second)),
+	})
+	machineInfraNotReady200 := healthCheckTarget{
+		Cluster: cluster,
+		MHC:     testMHCMachineChecks,
+		Machine: testMachineInfraNotReady200,
+		Node:    testNodeHealthy,
+	}
+
+	// Target for when the machine condition has been in the unhealthy state for longer than the timeout
+	testMachineInfraNotReady400 := testMachine.DeepCopy()
+	conditions.Set(testMachineInfraNotReady400, &clusterv1.Condition{
+		Type:               clusterv1.InfrastructureReadyCondition,
+		Status:             corev1.ConditionFalse,
+		Severity:           clusterv1.ConditionSeverityError,
+		LastTransitionTime: metav1.NewTime(time.Now().Add(-400 * time.Second)),
+	})
+	machineInfraNotReady400 := healthCheckTarget{
+		Cluster: cluster,
+		MHC:     testMHCMachineChecks,
+		Machine: testMachineInfraNotReady400,
+		Node:    testNodeHealthy,
+	}
+	machineInfraNotReady400Condition := newFailedHealthCheckCondition(clusterv1.UnhealthyMachineConditionReason, "Condition InfrastructureReady on machine is reporting status False for more than %s", timeoutForUnhealthyConditions)
+	machineInfraNotReady400V1Beta2Condition := newFailedHealthCheckV1Beta2Condition(clusterv1.MachineHealthCheckUnhealthyMachineV1Beta2Reason, "Health check failed: Condition InfrastructureReady on Machine is reporting status False for more than %s", timeoutForUnhealthyConditions)
+
+	// Target for when an unhealthy expression evaluates to true
+	testNodeKernelDeadlock := newTestNode("node1")
+	testNodeKernelDeadlock.Labels = map[string]string{"problem.example.com/kernel-deadlock": "true"}
+	nodeKernelDeadlock := healthCheckTarget{
+		Cluster: cluster,
+		MHC:     testMHCMachineChecks,
+		Machine: testMachine.DeepCopy(),
+		Node:    testNodeKernelDeadlock,
+	}
+	nodeKernelDeadlockCondition := newFailedHealthCheckCondition(clusterv1.UnhealthyExpressionReason, "Expression kernel-deadlock evaluated to true")
+	nodeKernelDeadlockV1Beta2Condition := newFailedHealthCheckV1Beta2Condition(clusterv1.MachineHealthCheckUnhealthyExpressionV1Beta2Reason, "Health check failed: Expression kernel-deadlock evaluated to true")
+
+	// Target for when machine conditions and unhealthy expressions are healthy
+	nodeHealthyMachineChecks := healthCheckTarget{
+		Cluster: cluster,
+		MHC:     testMHCMachineChecks,
+		Machine: testMachine.DeepCopy(),
+		Node:    testNodeHealthy,
+	}
+
 	testCases := []struct {
 		desc                                     string
 		targets                                  []healthCheckTarget
@@ -539,6 +607,38 @@ func TestHealthCheckTargets(t *testing.T) {
 			expectedNeedsRemediationV1Beta2Condition: []metav1.Condition{nodeGoneAwayV1Beta2Condition},
 			expectedNextCheckTimes:                   []time.Duration{},
 		},
+		{
+			desc:                     "when the machine condition has been in the unhealthy state for shorter than the timeout",
+			targets:                  []healthCheckTarget{machineInfraNotReady200},
+			expectedHealthy:          []healthCheckTarget{},
+			expectedNeedsRemediation: []healthCheckTarget{},
+			expectedNextCheckTimes:   []time.Duration{100 * time.Second},
+		},
+		{
+			desc:                                     "when the machine condition has been in the unhealthy state for longer than the timeout",
+			targets:                                  []healthCheckTarget{machineInfraNotReady400},
+			expectedHealthy:                          []healthCheckTarget{},
+			expectedNeedsRemediation:                 []healthCheckTarget{machineInfraNotReady400},
+			expectedNeedsRemediationCondition:        []clusterv1.Condition{machineInfraNotReady400Condition},
+			expectedNeedsRemediationV1Beta2Condition: []metav1.Condition{machineInfraNotReady400V1Beta2Condition},
+			expectedNextCheckTimes:                   []time.Duration{},
+		},
+		{
+			desc:                                     "when an unhealthy expression evaluates to true",
+			targets:                                  []healthCheckTarget{nodeKernelDeadlock},
+			expectedHealthy:                          []healthCheckTarget{},
+			expectedNeedsRemediation:                 []healthCheckTarget{nodeKernelDeadlock},
+			expectedNeedsRemediationCondition:        []clusterv1.Condition{nodeKernelDeadlockCondition},
+			expectedNeedsRemediationV1Beta2Condition: []metav1.Condition{nodeKernelDeadlockV1Beta2Condition},
+			expectedNextCheckTimes:                   []time.Duration{},
+		},
+		{
+			desc:                     "when machine conditions and unhealthy expressions are healthy",
+			targets:                  []healthCheckTarget{nodeHealthyMachineChecks},
+			expectedHealthy:          []healthCheckTarget{nodeHealthyMachineChecks},
+			expectedNeedsRemediation: []healthCheckTarget{},
+			expectedNextCheckTimes:   []time.Duration{},
+		},
 		{
 			desc:                              "health check with empty unhealthy conditions and node",
 			targets:                           []healthCheckTarget{nodeEmptyConditions},
@@ -564,7 +664,15 @@ func TestHealthCheckTargets(t *testing.T) {
 				timeout.Duration = *tc.timeoutForMachineToHaveNode
 			}
 
-			healthy, unhealthy, nextCheckTimes := reconciler.healthCheckTargets(tc.targets, ctrl.LoggerFrom(ctx), timeout)
+			// All the targets of a test case share the same MachineHealthCheck.
+			var unhealthyExpressions []unhealthyExpression
+			if len(tc.targets) > 0 {
+				var compileErrs []error
+				unhealthyExpressions, compileErrs = compileUnhealthyExpressions(tc.targets[0].MHC)
+				gs.Expect(compileErrs).To(BeEmpty())
+			}
+
+			healthy, unhealthy, nextCheckTimes := reconciler.healthCheckTargets(tc.targets, ctrl.LoggerFrom(ctx), timeout, unhealthyExpressions)
 
 			// Round durations down to nearest second account for minute differences
 			// in timing when running tests
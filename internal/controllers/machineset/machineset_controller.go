@@ -48,6 +48,7 @@ import (
 	"sigs.k8s.io/cluster-api/controllers/clustercache"
 	"sigs.k8s.io/cluster-api/controllers/external"
 	"sigs.k8s.io/cluster-api/controllers/noderefutil"
+	runtimeclient "sigs.k8s.io/cluster-api/exp/runtime/client"
 	"sigs.k8s.io/cluster-api/internal/contract"
 	"sigs.k8s.io/cluster-api/internal/controllers/machine"
 	"sigs.k8s.io/cluster-api/internal/controllers/machinedeployment/mdutil"
@@ -91,9 +92,10 @@ const machineSetManagerName = "capi-machineset"
 
 // Reconciler reconciles a MachineSet object.
 type Reconciler struct {
-	Client       client.Client
-	APIReader    client.Reader
-	ClusterCache clustercache.ClusterCache
+	Client        client.Client
+	APIReader     client.Reader
+	ClusterCache  clustercache.ClusterCache
+	RuntimeClient runtimeclient.Client
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
@@ -252,6 +254,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (retres ct
 
 	reconcileNormal := append(alwaysReconcile,
 		wrapErrMachineSetReconcileFunc(r.reconcileUnhealthyMachines, "failed to reconcile unhealthy machines"),
+		wrapErrMachineSetReconcileFunc(r.reconcileInPlaceUpdates, "failed to reconcile in-place updates"),
 		wrapErrMachineSetReconcileFunc(r.syncMachines, "failed to sync Machines"),
 		wrapErrMachineSetReconcileFunc(r.syncReplicas, "failed to sync replicas"),
 	)
@@ -269,6 +272,7 @@ type scope struct {
 	owningMachineDeployment                   *clusterv1.MachineDeployment
 	scaleUpPreflightCheckErrMessages          []string
 	reconciliationTime                        time.Time
+	machinesPendingInPlaceUpdate              sets.Set[string]
 }
 
 type machineSetReconcileFunc func(ctx context.Context, s *scope) (ctrl.Result, error)
@@ -494,6 +498,15 @@ func (r *Reconciler) syncMachines(ctx context.Context, s *scope) (ctrl.Result, e
 		m := machines[i]
 
 		upToDateCondition := newMachineUpToDateCondition(s)
+		// Machines pending an in-place update are not up to date, even if the spec of the MachineSet is.
+		if upToDateCondition != nil && s.machinesPendingInPlaceUpdate.Has(m.Name) {
+			upToDateCondition = &metav1.Condition{
+				Type:    clusterv1.MachineUpToDateV1Beta2Condition,
+				Status:  metav1.ConditionFalse,
+				Reason:  clusterv1.MachineNotUpToDateV1Beta2Reason,
+				Message: "* In-place update pending",
+			}
+		}
 
 		// If the machine is already being deleted, we only need to sync
 		// the subset of fields that impact tearing down a machine
@@ -547,6 +560,10 @@ func (r *Reconciler) syncMachines(ctx context.Context, s *scope) (ctrl.Result, e
 		if err != nil {
 			return ctrl.Result{}, errors.Wrap(err, "failed to update Machine: failed to compute desired Machine")
 		}
+		// Preserve the version of Machines pending an in-place update; the version is changed by the in-place update.
+		if s.machinesPendingInPlaceUpdate.Has(m.Name) {
+			updatedMachine.Spec.Version = m.Spec.Version
+		}
 		err = ssa.Patch(ctx, r.Client, machineSetManagerName, updatedMachine, ssa.WithCachingProxy{Cache: r.ssaCache, Original: m})
 		if err != nil {
 			log.Error(err, "Failed to update Machine", "Machine", klog.KObj(updatedMachine))
@@ -632,6 +649,11 @@ func newMachineUpToDateCondition(s *scope) *metav1.Condition {
 		}
 	}
 
+	if _, ok := s.machineSet.Annotations[clusterv1.MachineSetInPlaceUpdateDeclinedAnnotation]; ok {
+		upToDate = false
+		conditionMessages = append(conditionMessages, "In-place update declined")
+	}
+
 	if !upToDate {
 		for i := range conditionMessages {
 			conditionMessages[i] = fmt.Sprintf("* %s", conditionMessages[i])
@@ -0,0 +1,215 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machineset
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/internal/inplace"
+)
+
+// inPlaceUpdatesEnabled returns true if Machines should be updated in-place to the spec of the MachineSet when possible.
+func (r *Reconciler) inPlaceUpdatesEnabled() bool {
+	return feature.Gates.Enabled(feature.InPlaceUpdates) && r.RuntimeClient != nil
+}
+
+// reconcileInPlaceUpdates updates Machines in-place, one at a time, when the Kubernetes version or the
+// BootstrapConfigTemplate of the MachineSet has been changed, e.g. by the MachineDeployment controller.
+// Machines which cannot be updated in-place, either because the Runtime Extension declined the update or because
+// they are not eligible for in-place updates, are not deleted by the MachineSet controller; instead the MachineSet is
+// marked with the in-place update declined annotation, so the MachineDeployment controller replaces its Machines with
+// a rolling update, respecting MaxSurge and MaxUnavailable.
+func (r *Reconciler) reconcileInPlaceUpdates(ctx context.Context, s *scope) (ctrl.Result, error) {
+	if !r.inPlaceUpdatesEnabled() || !s.getAndAdoptMachinesForMachineSetSucceeded {
+		return ctrl.Result{}, nil
+	}
+	log := ctrl.LoggerFrom(ctx)
+
+	_, declinedMachineSet := s.machineSet.Annotations[clusterv1.MachineSetInPlaceUpdateDeclinedAnnotation]
+
+	s.machinesPendingInPlaceUpdate = sets.Set[string]{}
+	requests := map[string]*inplace.Request{}
+	var inProgress, pending []*clusterv1.Machine
+	var declined []string
+	for _, m := range s.machines {
+		if !m.DeletionTimestamp.IsZero() {
+			continue
+		}
+		req, needsUpdate, err := r.computeInPlaceUpdateRequest(ctx, s, m)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !needsUpdate {
+			continue
+		}
+		switch {
+		case req != nil && inplace.IsUpdateInProgress(m):
+			// In-place updates already in progress are always completed, so Machines are not left half updated.
+			inProgress = append(inProgress, m)
+		case req == nil || inplace.IsUpdateDeclined(m):
+			declined = append(declined, m.Name)
+			continue
+		case declinedMachineSet:
+			// The Machines of the MachineSet are going to be replaced, do not start new in-place updates.
+			continue
+		default:
+			pending = append(pending, m)
+		}
+		s.machinesPendingInPlaceUpdate.Insert(m.Name)
+		requests[m.Name] = req
+	}
+
+	if len(declined) > 0 && !declinedMachineSet {
+		log.Info("Machines cannot be updated in-place, the MachineSet is going to be rolled out by the MachineDeployment", "Machines", declined)
+		markInPlaceUpdateDeclined(s.machineSet)
+	}
+
+	var machine *clusterv1.Machine
+	switch {
+	case len(inProgress) > 0:
+		machine = inProgress[0]
+	case len(pending) > 0:
+		machine = pending[0]
+	default:
+		return ctrl.Result{}, nil
+	}
+
+	updater := &inplace.Updater{
+		Client:        r.Client,
+		RuntimeClient: r.RuntimeClient,
+	}
+	// NOTE: The Machine from the scope is updated by the updater, so the following phases use the up-to-date Machine.
+	result, err := updater.Update(ctx, *requests[machine.Name])
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if result.Declined {
+		s.machinesPendingInPlaceUpdate.Delete(machine.Name)
+		markInPlaceUpdateDeclined(s.machineSet)
+		return ctrl.Result{}, nil
+	}
+	if result.RequeueAfter == 0 {
+		s.machinesPendingInPlaceUpdate.Delete(machine.Name)
+	}
+	return ctrl.Result{RequeueAfter: result.RequeueAfter}, nil
+}
+
+// markInPlaceUpdateDeclined marks the MachineSet so its Machines are replaced by the MachineDeployment controller.
+// NOTE: The annotation is persisted when the MachineSet is patched at the end of the reconcile.
+func markInPlaceUpdateDeclined(machineSet *clusterv1.MachineSet) {
+	annotations := machineSet.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[clusterv1.MachineSetInPlaceUpdateDeclinedAnnotation] = ""
+	machineSet.SetAnnotations(annotations)
+}
+
+// computeInPlaceUpdateRequest checks if the Kubernetes version or the BootstrapConfig of a Machine are not up to date
+// with the spec of the MachineSet; if so, it returns the in-place update request for the Machine, or nil if the
+// Machine is not eligible for in-place updates, e.g. because its InfrastructureMachine has not been created from
+// the InfrastructureMachineTemplate of the MachineSet, and thus the Machine must be replaced.
+func (r *Reconciler) computeInPlaceUpdateRequest(ctx context.Context, s *scope, machine *clusterv1.Machine) (*inplace.Request, bool, error) {
+	template := s.machineSet.Spec.Template.Spec
+
+	versionChanged := template.Version != nil && (machine.Spec.Version == nil || *machine.Spec.Version != *template.Version)
+
+	var bootstrapConfig *unstructured.Unstructured
+	bootstrapConfigChanged := false
+	if machine.Spec.Bootstrap.ConfigRef != nil && template.Bootstrap.ConfigRef != nil {
+		var err error
+		bootstrapConfig, err = external.Get(ctx, r.Client, machine.Spec.Bootstrap.ConfigRef)
+		if err != nil {
+			if apierrors.IsNotFound(errors.Cause(err)) {
+				return nil, versionChanged, nil
+			}
+			return nil, false, errors.Wrapf(err, "failed to get BootstrapConfig %s", klog.KRef(machine.Spec.Bootstrap.ConfigRef.Namespace, machine.Spec.Bootstrap.ConfigRef.Name))
+		}
+		bootstrapConfigChanged = bootstrapConfig.GetAnnotations()[clusterv1.TemplateClonedFromNameAnnotation] != template.Bootstrap.ConfigRef.Name
+	}
+
+	if !versionChanged && !bootstrapConfigChanged {
+		return nil, false, nil
+	}
+
+	infraMachine, err := external.Get(ctx, r.Client, &machine.Spec.InfrastructureRef)
+	if err != nil {
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			return nil, true, nil
+		}
+		return nil, false, errors.Wrapf(err, "failed to get InfrastructureMachine %s", klog.KRef(machine.Spec.InfrastructureRef.Namespace, machine.Spec.InfrastructureRef.Name))
+	}
+	// Changes to the InfrastructureMachine cannot be applied in-place.
+	if infraMachine.GetAnnotations()[clusterv1.TemplateClonedFromNameAnnotation] != template.InfrastructureRef.Name {
+		return nil, true, nil
+	}
+
+	var desiredBootstrapConfig *unstructured.Unstructured
+	if bootstrapConfigChanged {
+		desiredBootstrapConfig, err = r.computeDesiredBootstrapConfig(ctx, s.machineSet, bootstrapConfig)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	desiredMachine := machine.DeepCopy()
+	desiredMachine.Spec.Version = template.Version
+
+	return &inplace.Request{
+		Cluster:                s.cluster,
+		Machine:                machine,
+		DesiredMachine:         desiredMachine,
+		BootstrapConfig:        bootstrapConfig,
+		DesiredBootstrapConfig: desiredBootstrapConfig,
+		InfrastructureMachine:  infraMachine,
+	}, true, nil
+}
+
+// computeDesiredBootstrapConfig returns the BootstrapConfig of a Machine with the spec of the BootstrapConfigTemplate
+// of the MachineSet.
+func (r *Reconciler) computeDesiredBootstrapConfig(ctx context.Context, machineSet *clusterv1.MachineSet, bootstrapConfig *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	templateRef := machineSet.Spec.Template.Spec.Bootstrap.ConfigRef
+	template, err := external.Get(ctx, r.Client, templateRef)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get BootstrapConfigTemplate %s", klog.KRef(templateRef.Namespace, templateRef.Name))
+	}
+	spec, _, err := unstructured.NestedMap(template.Object, "spec", "template", "spec")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get spec.template.spec from BootstrapConfigTemplate %s", klog.KObj(template))
+	}
+
+	desiredBootstrapConfig := bootstrapConfig.DeepCopy()
+	desiredBootstrapConfig.Object["spec"] = spec
+	annotations := desiredBootstrapConfig.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[clusterv1.TemplateClonedFromNameAnnotation] = templateRef.Name
+	annotations[clusterv1.TemplateClonedFromGroupKindAnnotation] = templateRef.GroupVersionKind().GroupKind().String()
+	desiredBootstrapConfig.SetAnnotations(annotations)
+	return desiredBootstrapConfig, nil
+}
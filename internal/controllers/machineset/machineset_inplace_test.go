@@ -0,0 +1,334 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machineset
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
+	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/feature"
+	fakeruntimeclient "sigs.k8s.io/cluster-api/internal/runtime/client/fake"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/test/builder"
+)
+
+func TestMachineSetReconciler_reconcileInPlaceUpdates(t *testing.T) {
+	utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.InPlaceUpdates, true)
+
+	catalog := runtimecatalog.New()
+	_ = runtimehooksv1.AddToCatalog(catalog)
+	canUpdateMachineGVH, err := catalog.GroupVersionHook(runtimehooksv1.CanUpdateMachine)
+	if err != nil {
+		panic("unable to compute GVH")
+	}
+	updateMachineGVH, err := catalog.GroupVersionHook(runtimehooksv1.UpdateMachine)
+	if err != nil {
+		panic("unable to compute GVH")
+	}
+
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+
+	machineSet := &clusterv1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ms",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: clusterv1.MachineSetSpec{
+			ClusterName: "cluster",
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					ClusterName: "cluster",
+					Version:     ptr.To("v1.32.0"),
+					Bootstrap: clusterv1.Bootstrap{
+						ConfigRef: &corev1.ObjectReference{
+							APIVersion: builder.BootstrapGroupVersion.String(),
+							Kind:       "GenericBootstrapConfigTemplate",
+							Name:       "bootstrap-template-2",
+							Namespace:  metav1.NamespaceDefault,
+						},
+					},
+					InfrastructureRef: corev1.ObjectReference{
+						APIVersion: builder.InfrastructureGroupVersion.String(),
+						Kind:       "GenericInfrastructureMachineTemplate",
+						Name:       "infra-template",
+						Namespace:  metav1.NamespaceDefault,
+					},
+				},
+			},
+		},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machine",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: "cluster",
+			Version:     ptr.To("v1.31.0"),
+			Bootstrap: clusterv1.Bootstrap{
+				ConfigRef: &corev1.ObjectReference{
+					APIVersion: builder.BootstrapGroupVersion.String(),
+					Kind:       "GenericBootstrapConfig",
+					Name:       "machine",
+					Namespace:  metav1.NamespaceDefault,
+				},
+			},
+			InfrastructureRef: corev1.ObjectReference{
+				APIVersion: builder.InfrastructureGroupVersion.String(),
+				Kind:       "GenericInfrastructureMachine",
+				Name:       "machine",
+				Namespace:  metav1.NamespaceDefault,
+			},
+		},
+	}
+	infraMachine := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": builder.InfrastructureGroupVersion.String(),
+			"kind":       "GenericInfrastructureMachine",
+			"metadata": map[string]interface{}{
+				"name":      "machine",
+				"namespace": metav1.NamespaceDefault,
+				"annotations": map[string]interface{}{
+					clusterv1.TemplateClonedFromNameAnnotation: "infra-template",
+				},
+			},
+		},
+	}
+	bootstrapConfig := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": builder.BootstrapGroupVersion.String(),
+			"kind":       "GenericBootstrapConfig",
+			"metadata": map[string]interface{}{
+				"name":      "machine",
+				"namespace": metav1.NamespaceDefault,
+				"annotations": map[string]interface{}{
+					clusterv1.TemplateClonedFromNameAnnotation: "bootstrap-template-1",
+				},
+			},
+			"spec": map[string]interface{}{
+				"foo": "bar",
+			},
+		},
+	}
+	bootstrapTemplate := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": builder.BootstrapGroupVersion.String(),
+			"kind":       "GenericBootstrapConfigTemplate",
+			"metadata": map[string]interface{}{
+				"name":      "bootstrap-template-2",
+				"namespace": metav1.NamespaceDefault,
+			},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"foo": "baz",
+					},
+				},
+			},
+		},
+	}
+
+	acceptedResponses := map[string]runtimehooksv1.ResponseObject{
+		"can-update-machine": &runtimehooksv1.CanUpdateMachineResponse{
+			CommonResponse: runtimehooksv1.CommonResponse{Status: runtimehooksv1.ResponseStatusSuccess},
+			Accepted:       true,
+		},
+		"update-machine": &runtimehooksv1.UpdateMachineResponse{
+			CommonRetryResponse: runtimehooksv1.CommonRetryResponse{
+				CommonResponse: runtimehooksv1.CommonResponse{Status: runtimehooksv1.ResponseStatusSuccess},
+			},
+		},
+	}
+	extensions := map[runtimecatalog.GroupVersionHook][]string{
+		canUpdateMachineGVH: {"can-update-machine"},
+		updateMachineGVH:    {"update-machine"},
+	}
+
+	upToDateMachine := machine.DeepCopy()
+	upToDateMachine.Spec.Version = ptr.To("v1.32.0")
+	upToDateBootstrapConfig := bootstrapConfig.DeepCopy()
+	upToDateBootstrapConfig.SetAnnotations(map[string]string{clusterv1.TemplateClonedFromNameAnnotation: "bootstrap-template-2"})
+
+	declinedMachine := machine.DeepCopy()
+	conditions.MarkFalse(declinedMachine, clusterv1.InPlaceUpdateSucceededCondition, clusterv1.InPlaceUpdateDeclinedReason, clusterv1.ConditionSeverityInfo, "Declined")
+
+	notEligibleInfraMachine := infraMachine.DeepCopy()
+	notEligibleInfraMachine.SetAnnotations(map[string]string{clusterv1.TemplateClonedFromNameAnnotation: "old-infra-template"})
+
+	tests := []struct {
+		name                     string
+		machineSetDeclined       bool
+		machine                  *clusterv1.Machine
+		infraMachine             *unstructured.Unstructured
+		bootstrapConfig          *unstructured.Unstructured
+		noExtensions             bool
+		callExtensionResponses   map[string]runtimehooksv1.ResponseObject
+		wantResult               ctrl.Result
+		wantVersion              string
+		wantBootstrapConfigSpec  string
+		wantPendingInPlaceUpdate bool
+		wantMachineSetDeclined   bool
+	}{
+		{
+			name:                    "Machine up to date is not updated",
+			machine:                 upToDateMachine,
+			bootstrapConfig:         upToDateBootstrapConfig,
+			wantVersion:             "v1.32.0",
+			wantBootstrapConfigSpec: "bar",
+		},
+		{
+			name:                    "Machine with outdated version and BootstrapConfig is updated in-place",
+			machine:                 machine,
+			bootstrapConfig:         bootstrapConfig,
+			callExtensionResponses:  acceptedResponses,
+			wantVersion:             "v1.32.0",
+			wantBootstrapConfigSpec: "baz",
+		},
+		{
+			name:            "Machine is marked as pending while the in-place update is in progress",
+			machine:         machine,
+			bootstrapConfig: bootstrapConfig,
+			callExtensionResponses: map[string]runtimehooksv1.ResponseObject{
+				"can-update-machine": acceptedResponses["can-update-machine"],
+				"update-machine": &runtimehooksv1.UpdateMachineResponse{
+					CommonRetryResponse: runtimehooksv1.CommonRetryResponse{
+						CommonResponse:    runtimehooksv1.CommonResponse{Status: runtimehooksv1.ResponseStatusSuccess},
+						RetryAfterSeconds: 15,
+					},
+				},
+			},
+			wantResult:               ctrl.Result{RequeueAfter: 15 * time.Second},
+			wantVersion:              "v1.31.0",
+			wantBootstrapConfigSpec:  "bar",
+			wantPendingInPlaceUpdate: true,
+		},
+		{
+			name:                    "Machine is not deleted and the MachineSet is marked for rollout if no Runtime Extension is registered",
+			machine:                 machine,
+			bootstrapConfig:         bootstrapConfig,
+			noExtensions:            true,
+			wantVersion:             "v1.31.0",
+			wantBootstrapConfigSpec: "bar",
+			wantMachineSetDeclined:  true,
+		},
+		{
+			name:                    "Machine whose in-place update has been declined is not deleted and the MachineSet is marked for rollout",
+			machine:                 declinedMachine,
+			bootstrapConfig:         bootstrapConfig,
+			wantVersion:             "v1.31.0",
+			wantBootstrapConfigSpec: "bar",
+			wantMachineSetDeclined:  true,
+		},
+		{
+			name:                    "Machine not eligible for in-place updates is not deleted and the MachineSet is marked for rollout",
+			machine:                 machine,
+			infraMachine:            notEligibleInfraMachine,
+			bootstrapConfig:         bootstrapConfig,
+			callExtensionResponses:  acceptedResponses,
+			wantVersion:             "v1.31.0",
+			wantBootstrapConfigSpec: "bar",
+			wantMachineSetDeclined:  true,
+		},
+		{
+			name:                    "In-place updates are not started for a MachineSet marked for rollout",
+			machineSetDeclined:      true,
+			machine:                 machine,
+			bootstrapConfig:         bootstrapConfig,
+			callExtensionResponses:  acceptedResponses,
+			wantVersion:             "v1.31.0",
+			wantBootstrapConfigSpec: "bar",
+			wantMachineSetDeclined:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			m := tt.machine.DeepCopy()
+			im := infraMachine.DeepCopy()
+			if tt.infraMachine != nil {
+				im = tt.infraMachine.DeepCopy()
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(m, im, tt.bootstrapConfig.DeepCopy(), bootstrapTemplate.DeepCopy()).
+				WithStatusSubresource(&clusterv1.Machine{}).
+				Build()
+
+			registeredExtensions := extensions
+			if tt.noExtensions {
+				registeredExtensions = map[runtimecatalog.GroupVersionHook][]string{}
+			}
+			r := &Reconciler{
+				Client: c,
+				RuntimeClient: fakeruntimeclient.NewRuntimeClientBuilder().
+					WithCatalog(catalog).
+					WithGetAllExtensionResponses(registeredExtensions).
+					WithCallExtensionResponses(tt.callExtensionResponses).
+					Build(),
+			}
+			ms := machineSet.DeepCopy()
+			if tt.machineSetDeclined {
+				ms.Annotations = map[string]string{clusterv1.MachineSetInPlaceUpdateDeclinedAnnotation: ""}
+			}
+			s := &scope{
+				cluster:    &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: metav1.NamespaceDefault}},
+				machineSet: ms,
+				machines:   []*clusterv1.Machine{m},
+				getAndAdoptMachinesForMachineSetSucceeded: true,
+			}
+
+			result, err := r.reconcileInPlaceUpdates(ctx, s)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(result).To(BeComparableTo(tt.wantResult))
+			g.Expect(s.machinesPendingInPlaceUpdate.Has(m.Name)).To(Equal(tt.wantPendingInPlaceUpdate))
+			if tt.wantMachineSetDeclined {
+				g.Expect(s.machineSet.Annotations).To(HaveKey(clusterv1.MachineSetInPlaceUpdateDeclinedAnnotation))
+			} else {
+				g.Expect(s.machineSet.Annotations).ToNot(HaveKey(clusterv1.MachineSetInPlaceUpdateDeclinedAnnotation))
+			}
+
+			// Machines are never deleted by in-place updates; Machines which cannot be updated in-place
+			// are replaced by the MachineDeployment with a rolling update.
+			gotMachine := &clusterv1.Machine{}
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(m), gotMachine)).To(Succeed())
+			g.Expect(gotMachine.DeletionTimestamp.IsZero()).To(BeTrue())
+			g.Expect(*gotMachine.Spec.Version).To(Equal(tt.wantVersion))
+
+			gotBootstrapConfig := &unstructured.Unstructured{}
+			gotBootstrapConfig.SetGroupVersionKind(bootstrapConfig.GroupVersionKind())
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(bootstrapConfig), gotBootstrapConfig)).To(Succeed())
+			spec, _, err := unstructured.NestedString(gotBootstrapConfig.Object, "spec", "foo")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(spec).To(Equal(tt.wantBootstrapConfigSpec))
+		})
+	}
+}
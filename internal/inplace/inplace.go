@@ -0,0 +1,289 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package inplace implements the in-place update of Machines, delegating the update to the
+// Runtime Extension implementing the in-place update hooks.
+package inplace
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
+	runtimeclient "sigs.k8s.io/cluster-api/exp/runtime/client"
+	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+)
+
+// Updater updates Machines in-place.
+type Updater struct {
+	Client        client.Client
+	RuntimeClient runtimeclient.Client
+}
+
+// Request is the in-place update of a Machine to a desired state.
+type Request struct {
+	// Cluster is the Cluster the Machine belongs to.
+	Cluster *clusterv1.Cluster
+
+	// Machine is the Machine to be updated.
+	// When the update is completed, the version and the annotations of the DesiredMachine are applied to the Machine.
+	Machine *clusterv1.Machine
+
+	// DesiredMachine is the Machine with the desired spec.
+	DesiredMachine *clusterv1.Machine
+
+	// BootstrapConfig is the BootstrapConfig of the Machine, if any.
+	// When the update is completed, the spec and the annotations of the DesiredBootstrapConfig are applied to the BootstrapConfig.
+	BootstrapConfig *unstructured.Unstructured
+
+	// DesiredBootstrapConfig is the BootstrapConfig with the desired spec, if any.
+	DesiredBootstrapConfig *unstructured.Unstructured
+
+	// InfrastructureMachine is the InfrastructureMachine of the Machine.
+	InfrastructureMachine *unstructured.Unstructured
+}
+
+// Result is the outcome of an in-place update.
+// If the Machine is neither declined nor requeued, the in-place update is completed.
+type Result struct {
+	// Declined is true if the Machine cannot be updated in-place, and it must be replaced instead.
+	Declined bool
+
+	// RequeueAfter is the time after which the in-place update, which is still in progress, should be checked again.
+	RequeueAfter time.Duration
+}
+
+// IsUpdateInProgress returns true if the Machine is being updated in-place.
+func IsUpdateInProgress(machine *clusterv1.Machine) bool {
+	_, ok := machine.GetAnnotations()[clusterv1.UpdateInProgressAnnotation]
+	return ok
+}
+
+// IsUpdateDeclined returns true if the in-place update of the Machine has been declined, and thus
+// the Machine should be replaced.
+func IsUpdateDeclined(machine *clusterv1.Machine) bool {
+	return !IsUpdateInProgress(machine) &&
+		conditions.IsFalse(machine, clusterv1.InPlaceUpdateSucceededCondition) &&
+		conditions.GetReason(machine, clusterv1.InPlaceUpdateSucceededCondition) == clusterv1.InPlaceUpdateDeclinedReason
+}
+
+// Update updates a Machine in-place.
+// When the update of the Machine is not yet in progress, the Runtime Extension is asked whether the Machine can be
+// updated in-place; if the Runtime Extension declines, the Machine is marked accordingly and it must be replaced.
+// Otherwise the Runtime Extension is called until the update is completed, and then the desired state is applied to
+// the Machine and to its BootstrapConfig.
+func (u *Updater) Update(ctx context.Context, req Request) (Result, error) {
+	log := ctrl.LoggerFrom(ctx).WithValues("Machine", klog.KObj(req.Machine))
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	if !IsUpdateInProgress(req.Machine) {
+		if IsUpdateDeclined(req.Machine) {
+			return Result{Declined: true}, nil
+		}
+
+		accepted, message, err := u.canUpdateMachine(ctx, req)
+		if err != nil {
+			return Result{}, err
+		}
+		if !accepted {
+			log.Info("In-place update declined, the Machine is going to be replaced", "reason", message)
+			if err := u.patchMachine(ctx, req.Machine, func(machine *clusterv1.Machine) {
+				conditions.MarkFalse(machine, clusterv1.InPlaceUpdateSucceededCondition, clusterv1.InPlaceUpdateDeclinedReason, clusterv1.ConditionSeverityInfo, "%s", message)
+			}); err != nil {
+				return Result{}, err
+			}
+			return Result{Declined: true}, nil
+		}
+
+		log.Info("Starting in-place update")
+		if err := u.patchMachine(ctx, req.Machine, func(machine *clusterv1.Machine) {
+			annotations := machine.GetAnnotations()
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[clusterv1.UpdateInProgressAnnotation] = ""
+			machine.SetAnnotations(annotations)
+			conditions.MarkFalse(machine, clusterv1.InPlaceUpdateSucceededCondition, clusterv1.InPlaceUpdateInProgressReason, clusterv1.ConditionSeverityInfo, "")
+		}); err != nil {
+			return Result{}, err
+		}
+	}
+
+	retryAfterSeconds, err := u.updateMachine(ctx, req)
+	if err != nil {
+		if patchErr := u.patchMachine(ctx, req.Machine, func(machine *clusterv1.Machine) {
+			conditions.MarkFalse(machine, clusterv1.InPlaceUpdateSucceededCondition, clusterv1.InPlaceUpdateFailedReason, clusterv1.ConditionSeverityWarning, "%s", err.Error())
+		}); patchErr != nil {
+			log.Error(patchErr, "Failed to mark the in-place update as failed")
+		}
+		return Result{}, err
+	}
+	if retryAfterSeconds > 0 {
+		log.V(4).Info("In-place update in progress", "retryAfterSeconds", retryAfterSeconds)
+		if conditions.GetReason(req.Machine, clusterv1.InPlaceUpdateSucceededCondition) != clusterv1.InPlaceUpdateInProgressReason {
+			if err := u.patchMachine(ctx, req.Machine, func(machine *clusterv1.Machine) {
+				conditions.MarkFalse(machine, clusterv1.InPlaceUpdateSucceededCondition, clusterv1.InPlaceUpdateInProgressReason, clusterv1.ConditionSeverityInfo, "")
+			}); err != nil {
+				return Result{}, err
+			}
+		}
+		return Result{RequeueAfter: time.Duration(retryAfterSeconds) * time.Second}, nil
+	}
+
+	// The in-place update is completed, apply the desired state to the BootstrapConfig and to the Machine;
+	// the BootstrapConfig is patched first, so the update is not marked as completed if the patch fails.
+	if req.BootstrapConfig != nil && req.DesiredBootstrapConfig != nil {
+		bootstrapConfig := req.BootstrapConfig.DeepCopy()
+		bootstrapConfig.Object["spec"] = req.DesiredBootstrapConfig.Object["spec"]
+		annotations := bootstrapConfig.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		for k, v := range req.DesiredBootstrapConfig.GetAnnotations() {
+			annotations[k] = v
+		}
+		bootstrapConfig.SetAnnotations(annotations)
+		if err := u.Client.Patch(ctx, bootstrapConfig, client.MergeFrom(req.BootstrapConfig)); err != nil {
+			return Result{}, errors.Wrapf(err, "failed to complete in-place update of Machine %s: failed to patch %s %s", klog.KObj(req.Machine), req.BootstrapConfig.GetKind(), klog.KObj(req.BootstrapConfig))
+		}
+	}
+
+	if err := u.patchMachine(ctx, req.Machine, func(machine *clusterv1.Machine) {
+		machine.Spec.Version = req.DesiredMachine.Spec.Version
+		annotations := machine.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		for k, v := range req.DesiredMachine.GetAnnotations() {
+			annotations[k] = v
+		}
+		delete(annotations, clusterv1.UpdateInProgressAnnotation)
+		machine.SetAnnotations(annotations)
+		conditions.MarkTrue(machine, clusterv1.InPlaceUpdateSucceededCondition)
+	}); err != nil {
+		return Result{}, err
+	}
+	log.Info("In-place update completed")
+	return Result{}, nil
+}
+
+// canUpdateMachine calls the CanUpdateMachine hook and returns true if the Runtime Extension accepted the update;
+// if the update is declined, it also returns the reason why.
+func (u *Updater) canUpdateMachine(ctx context.Context, req Request) (bool, string, error) {
+	extensionName, err := u.extensionName(ctx, runtimehooksv1.CanUpdateMachine, req.Machine)
+	if err != nil {
+		return false, "", err
+	}
+	if extensionName == "" {
+		return false, "No Runtime Extension is registered for in-place updates", nil
+	}
+
+	request := &runtimehooksv1.CanUpdateMachineRequest{
+		Cluster:                *req.Cluster,
+		Machine:                *req.Machine,
+		DesiredMachine:         *req.DesiredMachine,
+		BootstrapConfig:        rawExtension(req.BootstrapConfig),
+		DesiredBootstrapConfig: rawExtension(req.DesiredBootstrapConfig),
+		InfrastructureMachine:  rawExtension(req.InfrastructureMachine),
+	}
+	response := &runtimehooksv1.CanUpdateMachineResponse{}
+	if err := u.RuntimeClient.CallExtension(ctx, runtimehooksv1.CanUpdateMachine, req.Machine, extensionName, request, response); err != nil {
+		return false, "", errors.Wrapf(err, "failed to check if Machine %s can be updated in-place", klog.KObj(req.Machine))
+	}
+	if response.Accepted {
+		return true, "", nil
+	}
+	message := response.GetMessage()
+	if message == "" {
+		message = "The Runtime Extension declined the in-place update"
+	}
+	return false, message, nil
+}
+
+// updateMachine calls the UpdateMachine hook and returns the RetryAfterSeconds of the response; a value greater
+// than zero means the update is still in progress.
+func (u *Updater) updateMachine(ctx context.Context, req Request) (int32, error) {
+	extensionName, err := u.extensionName(ctx, runtimehooksv1.UpdateMachine, req.Machine)
+	if err != nil {
+		return 0, err
+	}
+	if extensionName == "" {
+		return 0, errors.Errorf("failed to update Machine %s in-place: no Runtime Extension is registered for in-place updates", klog.KObj(req.Machine))
+	}
+
+	request := &runtimehooksv1.UpdateMachineRequest{
+		Cluster:                *req.Cluster,
+		Machine:                *req.Machine,
+		DesiredMachine:         *req.DesiredMachine,
+		BootstrapConfig:        rawExtension(req.BootstrapConfig),
+		DesiredBootstrapConfig: rawExtension(req.DesiredBootstrapConfig),
+		InfrastructureMachine:  rawExtension(req.InfrastructureMachine),
+	}
+	response := &runtimehooksv1.UpdateMachineResponse{}
+	if err := u.RuntimeClient.CallExtension(ctx, runtimehooksv1.UpdateMachine, req.Machine, extensionName, request, response); err != nil {
+		return 0, errors.Wrapf(err, "failed to update Machine %s in-place", klog.KObj(req.Machine))
+	}
+	return response.GetRetryAfterSeconds(), nil
+}
+
+// extensionName returns the name of the Runtime Extension implementing a hook, if any.
+// Only a single Runtime Extension can implement the in-place update hooks.
+func (u *Updater) extensionName(ctx context.Context, hook runtimecatalog.Hook, machine *clusterv1.Machine) (string, error) {
+	names, err := u.RuntimeClient.GetAllExtensions(ctx, hook, machine)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the Runtime Extensions for in-place updates")
+	}
+	switch len(names) {
+	case 0:
+		return "", nil
+	case 1:
+		return names[0], nil
+	default:
+		return "", errors.Errorf("found multiple Runtime Extensions for hook %s, only one is allowed: %v", runtimecatalog.HookName(hook), names)
+	}
+}
+
+// patchMachine applies the changes of mutate to the Machine.
+func (u *Updater) patchMachine(ctx context.Context, machine *clusterv1.Machine, mutate func(*clusterv1.Machine)) error {
+	patchHelper, err := patch.NewHelper(machine, u.Client)
+	if err != nil {
+		return errors.Wrapf(err, "failed to patch Machine %s", klog.KObj(machine))
+	}
+	mutate(machine)
+	if err := patchHelper.Patch(ctx, machine, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+		clusterv1.InPlaceUpdateSucceededCondition,
+	}}); err != nil {
+		return errors.Wrapf(err, "failed to patch Machine %s", klog.KObj(machine))
+	}
+	return nil
+}
+
+func rawExtension(obj *unstructured.Unstructured) runtime.RawExtension {
+	if obj == nil {
+		return runtime.RawExtension{}
+	}
+	return runtime.RawExtension{Object: obj}
+}
@@ -0,0 +1,300 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inplace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
+	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
+	fakeruntimeclient "sigs.k8s.io/cluster-api/internal/runtime/client/fake"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestUpdater_Update(t *testing.T) {
+	catalog := runtimecatalog.New()
+	_ = runtimehooksv1.AddToCatalog(catalog)
+	canUpdateMachineGVH, err := catalog.GroupVersionHook(runtimehooksv1.CanUpdateMachine)
+	if err != nil {
+		panic("unable to compute GVH")
+	}
+	updateMachineGVH, err := catalog.GroupVersionHook(runtimehooksv1.UpdateMachine)
+	if err != nil {
+		panic("unable to compute GVH")
+	}
+
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machine",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: "cluster",
+			Version:     ptr.To("v1.31.0"),
+		},
+	}
+	desiredMachine := machine.DeepCopy()
+	desiredMachine.Spec.Version = ptr.To("v1.32.0")
+
+	bootstrapConfig := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "bootstrap.cluster.x-k8s.io/v1beta1",
+			"kind":       "GenericBootstrapConfig",
+			"metadata": map[string]interface{}{
+				"name":      "machine",
+				"namespace": metav1.NamespaceDefault,
+				"annotations": map[string]interface{}{
+					clusterv1.TemplateClonedFromNameAnnotation: "template-1",
+				},
+			},
+			"spec": map[string]interface{}{
+				"foo": "bar",
+			},
+		},
+	}
+	desiredBootstrapConfig := bootstrapConfig.DeepCopy()
+	desiredBootstrapConfig.Object["spec"] = map[string]interface{}{
+		"foo": "baz",
+	}
+	desiredBootstrapConfig.SetAnnotations(map[string]string{
+		clusterv1.TemplateClonedFromNameAnnotation: "template-2",
+	})
+
+	inProgressMachine := machine.DeepCopy()
+	inProgressMachine.Annotations = map[string]string{clusterv1.UpdateInProgressAnnotation: ""}
+	conditions.MarkFalse(inProgressMachine, clusterv1.InPlaceUpdateSucceededCondition, clusterv1.InPlaceUpdateInProgressReason, clusterv1.ConditionSeverityInfo, "")
+
+	declinedMachine := machine.DeepCopy()
+	conditions.MarkFalse(declinedMachine, clusterv1.InPlaceUpdateSucceededCondition, clusterv1.InPlaceUpdateDeclinedReason, clusterv1.ConditionSeverityInfo, "Declined")
+
+	tests := []struct {
+		name                     string
+		machine                  *clusterv1.Machine
+		getAllExtensionResponses map[runtimecatalog.GroupVersionHook][]string
+		callExtensionResponses   map[string]runtimehooksv1.ResponseObject
+		wantResult               Result
+		wantErr                  bool
+		wantVersion              string
+		wantInProgress           bool
+		wantConditionReason      string
+		wantConditionTrue        bool
+		wantBootstrapConfigSpec  string
+	}{
+		{
+			name:                    "Decline if no Runtime Extension is registered for in-place updates",
+			machine:                 machine,
+			wantResult:              Result{Declined: true},
+			wantVersion:             "v1.31.0",
+			wantConditionReason:     clusterv1.InPlaceUpdateDeclinedReason,
+			wantBootstrapConfigSpec: "bar",
+		},
+		{
+			name:    "Decline if the Runtime Extension does not accept the update",
+			machine: machine,
+			getAllExtensionResponses: map[runtimecatalog.GroupVersionHook][]string{
+				canUpdateMachineGVH: {"in-place"},
+			},
+			callExtensionResponses: map[string]runtimehooksv1.ResponseObject{
+				"in-place": &runtimehooksv1.CanUpdateMachineResponse{
+					CommonResponse: runtimehooksv1.CommonResponse{Status: runtimehooksv1.ResponseStatusSuccess, Message: "Kernel upgrade required"},
+					Accepted:       false,
+				},
+			},
+			wantResult:              Result{Declined: true},
+			wantVersion:             "v1.31.0",
+			wantConditionReason:     clusterv1.InPlaceUpdateDeclinedReason,
+			wantBootstrapConfigSpec: "bar",
+		},
+		{
+			name:                    "Decline without calling the Runtime Extension if the update has been already declined",
+			machine:                 declinedMachine,
+			wantResult:              Result{Declined: true},
+			wantVersion:             "v1.31.0",
+			wantConditionReason:     clusterv1.InPlaceUpdateDeclinedReason,
+			wantBootstrapConfigSpec: "bar",
+		},
+		{
+			name:    "Fail if multiple Runtime Extensions are registered for in-place updates",
+			machine: machine,
+			getAllExtensionResponses: map[runtimecatalog.GroupVersionHook][]string{
+				canUpdateMachineGVH: {"in-place-1", "in-place-2"},
+			},
+			wantErr:                 true,
+			wantVersion:             "v1.31.0",
+			wantBootstrapConfigSpec: "bar",
+		},
+		{
+			name:    "Start the update if the Runtime Extension accepts the update",
+			machine: machine,
+			// NOTE: the fake runtime client returns responses by extension name, so different names are used
+			// for the handlers of the two hooks.
+			getAllExtensionResponses: map[runtimecatalog.GroupVersionHook][]string{
+				canUpdateMachineGVH: {"can-update-machine"},
+				updateMachineGVH:    {"update-machine"},
+			},
+			callExtensionResponses: map[string]runtimehooksv1.ResponseObject{
+				"can-update-machine": &runtimehooksv1.CanUpdateMachineResponse{
+					CommonResponse: runtimehooksv1.CommonResponse{Status: runtimehooksv1.ResponseStatusSuccess},
+					Accepted:       true,
+				},
+				"update-machine": &runtimehooksv1.UpdateMachineResponse{
+					CommonRetryResponse: runtimehooksv1.CommonRetryResponse{
+						CommonResponse:    runtimehooksv1.CommonResponse{Status: runtimehooksv1.ResponseStatusSuccess},
+						RetryAfterSeconds: 5,
+					},
+				},
+			},
+			wantResult:              Result{RequeueAfter: 5 * time.Second},
+			wantVersion:             "v1.31.0",
+			wantInProgress:          true,
+			wantConditionReason:     clusterv1.InPlaceUpdateInProgressReason,
+			wantBootstrapConfigSpec: "bar",
+		},
+		{
+			name:    "Requeue while the update is in progress",
+			machine: inProgressMachine,
+			getAllExtensionResponses: map[runtimecatalog.GroupVersionHook][]string{
+				updateMachineGVH: {"in-place"},
+			},
+			callExtensionResponses: map[string]runtimehooksv1.ResponseObject{
+				"in-place": &runtimehooksv1.UpdateMachineResponse{
+					CommonRetryResponse: runtimehooksv1.CommonRetryResponse{
+						CommonResponse:    runtimehooksv1.CommonResponse{Status: runtimehooksv1.ResponseStatusSuccess},
+						RetryAfterSeconds: 10,
+					},
+				},
+			},
+			wantResult:              Result{RequeueAfter: 10 * time.Second},
+			wantVersion:             "v1.31.0",
+			wantInProgress:          true,
+			wantConditionReason:     clusterv1.InPlaceUpdateInProgressReason,
+			wantBootstrapConfigSpec: "bar",
+		},
+		{
+			name:    "Mark the update as failed if the Runtime Extension fails",
+			machine: inProgressMachine,
+			getAllExtensionResponses: map[runtimecatalog.GroupVersionHook][]string{
+				updateMachineGVH: {"in-place"},
+			},
+			callExtensionResponses: map[string]runtimehooksv1.ResponseObject{
+				"in-place": &runtimehooksv1.UpdateMachineResponse{
+					CommonRetryResponse: runtimehooksv1.CommonRetryResponse{
+						CommonResponse: runtimehooksv1.CommonResponse{Status: runtimehooksv1.ResponseStatusFailure, Message: "kubelet upgrade failed"},
+					},
+				},
+			},
+			wantErr:                 true,
+			wantVersion:             "v1.31.0",
+			wantInProgress:          true,
+			wantConditionReason:     clusterv1.InPlaceUpdateFailedReason,
+			wantBootstrapConfigSpec: "bar",
+		},
+		{
+			name:    "Apply the desired state when the update is completed",
+			machine: inProgressMachine,
+			getAllExtensionResponses: map[runtimecatalog.GroupVersionHook][]string{
+				updateMachineGVH: {"in-place"},
+			},
+			callExtensionResponses: map[string]runtimehooksv1.ResponseObject{
+				"in-place": &runtimehooksv1.UpdateMachineResponse{
+					CommonRetryResponse: runtimehooksv1.CommonRetryResponse{
+						CommonResponse: runtimehooksv1.CommonResponse{Status: runtimehooksv1.ResponseStatusSuccess},
+					},
+				},
+			},
+			wantResult:              Result{},
+			wantVersion:             "v1.32.0",
+			wantConditionTrue:       true,
+			wantBootstrapConfigSpec: "baz",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+
+			m := tt.machine.DeepCopy()
+			b := bootstrapConfig.DeepCopy()
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(m, b).WithStatusSubresource(&clusterv1.Machine{}).Build()
+
+			u := &Updater{
+				Client: c,
+				RuntimeClient: fakeruntimeclient.NewRuntimeClientBuilder().
+					WithCatalog(catalog).
+					WithGetAllExtensionResponses(tt.getAllExtensionResponses).
+					WithCallExtensionResponses(tt.callExtensionResponses).
+					Build(),
+			}
+
+			res, err := u.Update(ctx, Request{
+				Cluster:                cluster,
+				Machine:                m,
+				DesiredMachine:         desiredMachine,
+				BootstrapConfig:        b,
+				DesiredBootstrapConfig: desiredBootstrapConfig,
+				InfrastructureMachine:  &unstructured.Unstructured{},
+			})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(res).To(Equal(tt.wantResult))
+			}
+
+			gotMachine := &clusterv1.Machine{}
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(m), gotMachine)).To(Succeed())
+			g.Expect(*gotMachine.Spec.Version).To(Equal(tt.wantVersion))
+			g.Expect(IsUpdateInProgress(gotMachine)).To(Equal(tt.wantInProgress))
+			if tt.wantConditionTrue {
+				g.Expect(conditions.IsTrue(gotMachine, clusterv1.InPlaceUpdateSucceededCondition)).To(BeTrue())
+			}
+			if tt.wantConditionReason != "" {
+				g.Expect(conditions.GetReason(gotMachine, clusterv1.InPlaceUpdateSucceededCondition)).To(Equal(tt.wantConditionReason))
+			}
+
+			gotBootstrapConfig := &unstructured.Unstructured{}
+			gotBootstrapConfig.SetGroupVersionKind(b.GroupVersionKind())
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(b), gotBootstrapConfig)).To(Succeed())
+			spec, _, err := unstructured.NestedString(gotBootstrapConfig.Object, "spec", "foo")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(spec).To(Equal(tt.wantBootstrapConfigSpec))
+			if tt.wantBootstrapConfigSpec == "baz" {
+				g.Expect(gotBootstrapConfig.GetAnnotations()).To(HaveKeyWithValue(clusterv1.TemplateClonedFromNameAnnotation, "template-2"))
+			}
+		})
+	}
+}
@@ -1135,6 +1135,79 @@ func TestClient_CallAllExtensions(t *testing.T) {
 	}
 }
 
+func TestClient_GetAllExtensions(t *testing.T) {
+	g := NewWithT(t)
+
+	ns := &corev1.Namespace{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Namespace",
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "foo",
+			Labels: map[string]string{
+				corev1.LabelMetadataName: "foo",
+			},
+		},
+	}
+
+	handler := func(name string, hook string) runtimev1.ExtensionHandler {
+		return runtimev1.ExtensionHandler{
+			Name: name,
+			RequestHook: runtimev1.GroupVersionHook{
+				APIVersion: fakev1alpha1.GroupVersion.String(),
+				Hook:       hook,
+			},
+		}
+	}
+	extensionConfigs := []runtimev1.ExtensionConfig{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "matching"},
+			Spec: runtimev1.ExtensionConfigSpec{
+				NamespaceSelector: &metav1.LabelSelector{},
+			},
+			Status: runtimev1.ExtensionConfigStatus{
+				Handlers: []runtimev1.ExtensionHandler{
+					handler("first-extension.matching", "FakeHook"),
+					handler("second-extension.matching", "FakeHook"),
+					handler("third-extension.matching", "SecondFakeHook"),
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "not-matching"},
+			Spec: runtimev1.ExtensionConfigSpec{
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{corev1.LabelMetadataName: "bar"},
+				},
+			},
+			Status: runtimev1.ExtensionConfigStatus{
+				Handlers: []runtimev1.ExtensionHandler{
+					handler("first-extension.not-matching", "FakeHook"),
+				},
+			},
+		},
+	}
+
+	cat := runtimecatalog.New()
+	_ = fakev1alpha1.AddToCatalog(cat)
+	c := New(Options{
+		Catalog:  cat,
+		Registry: registry(extensionConfigs),
+		Client:   fake.NewClientBuilder().WithObjects(ns).Build(),
+	})
+
+	obj := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster",
+			Namespace: "foo",
+		},
+	}
+	names, err := c.GetAllExtensions(context.Background(), fakev1alpha1.FakeHook, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(names).To(ConsistOf("first-extension.matching", "second-extension.matching"))
+}
+
 func Test_client_matchNamespace(t *testing.T) {
 	g := NewWithT(t)
 	foo := &corev1.Namespace{
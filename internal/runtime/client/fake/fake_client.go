@@ -36,6 +36,7 @@ type RuntimeClientBuilder struct {
 	catalog          *runtimecatalog.Catalog
 	callAllResponses map[runtimecatalog.GroupVersionHook]runtimehooksv1.ResponseObject
 	callResponses    map[string]runtimehooksv1.ResponseObject
+	getAllResponses  map[runtimecatalog.GroupVersionHook][]string
 }
 
 // NewRuntimeClientBuilder returns a new builder for the fake runtime client.
@@ -61,6 +62,12 @@ func (f *RuntimeClientBuilder) WithCallExtensionResponses(responses map[string]r
 	return f
 }
 
+// WithGetAllExtensionResponses can be used to dictate the responses for GetAllExtensions.
+func (f *RuntimeClientBuilder) WithGetAllExtensionResponses(responses map[runtimecatalog.GroupVersionHook][]string) *RuntimeClientBuilder {
+	f.getAllResponses = responses
+	return f
+}
+
 // MarkReady can be used to mark the fake runtime client as either ready or not ready.
 func (f *RuntimeClientBuilder) MarkReady(ready bool) *RuntimeClientBuilder {
 	f.ready = ready
@@ -73,6 +80,7 @@ func (f *RuntimeClientBuilder) Build() *RuntimeClient {
 		isReady:          f.ready,
 		callAllResponses: f.callAllResponses,
 		callResponses:    f.callResponses,
+		getAllResponses:  f.getAllResponses,
 		catalog:          f.catalog,
 		callAllTracker:   map[string]int{},
 	}
@@ -86,6 +94,7 @@ type RuntimeClient struct {
 	catalog          *runtimecatalog.Catalog
 	callAllResponses map[runtimecatalog.GroupVersionHook]runtimehooksv1.ResponseObject
 	callResponses    map[string]runtimehooksv1.ResponseObject
+	getAllResponses  map[runtimecatalog.GroupVersionHook][]string
 
 	callAllTracker map[string]int
 }
@@ -138,6 +147,15 @@ func (fc *RuntimeClient) CallExtension(ctx context.Context, _ runtimecatalog.Hoo
 	return nil
 }
 
+// GetAllExtensions implements Client.
+func (fc *RuntimeClient) GetAllExtensions(_ context.Context, hook runtimecatalog.Hook, _ metav1.Object) ([]string, error) {
+	gvh, err := fc.catalog.GroupVersionHook(hook)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to compute GVH")
+	}
+	return fc.getAllResponses[gvh], nil
+}
+
 // Discover implements Client.
 func (fc *RuntimeClient) Discover(context.Context, *runtimev1.ExtensionConfig) (*runtimev1.ExtensionConfig, error) {
 	panic("unimplemented")
@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package revision provides utils to record the history of the spec of an object into ControllerRevisions.
+package revision
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultHistoryLimit is the number of revisions kept for each object.
+const DefaultHistoryLimit = 10
+
+// maxNamePrefixLength is the maximum length of the owner name used as a prefix for the name of a ControllerRevision,
+// leaving room for the hash suffix.
+const maxNamePrefixLength = 220
+
+// Record ensures the latest revision of owner records the given data; if not, a new revision is recorded, or
+// an existing revision with the same data gets the next revision number, e.g. after a rollback. Revisions
+// exceeding historyLimit are deleted, oldest first.
+// The ControllerRevisions are created in the namespace of owner, with the given labels and owner as a controller.
+// NOTE: data must be deterministic, e.g. the JSON serialization of the relevant fields of the spec of owner.
+func Record(ctx context.Context, c client.Client, owner client.Object, ownerGVK schema.GroupVersionKind, labels map[string]string, data []byte, historyLimit int) (int64, error) {
+	revisions, err := List(ctx, c, owner, labels)
+	if err != nil {
+		return 0, err
+	}
+
+	var latest int64
+	if len(revisions) > 0 {
+		last := revisions[len(revisions)-1]
+		if bytes.Equal(last.Data.Raw, data) {
+			return last.Revision, nil
+		}
+		latest = last.Revision
+	}
+
+	name := Name(owner.GetName(), data)
+	var recorded *appsv1.ControllerRevision
+	for _, r := range revisions {
+		if r.Name == name && bytes.Equal(r.Data.Raw, data) {
+			recorded = r
+			break
+		}
+	}
+
+	if recorded != nil {
+		// The data has been already recorded in a previous revision, e.g. because the owner has been rolled back,
+		// so the existing revision becomes the latest one.
+		recorded.Revision = latest + 1
+		if err := c.Update(ctx, recorded); err != nil {
+			return 0, errors.Wrapf(err, "failed to update ControllerRevision %s", name)
+		}
+	} else {
+		recorded = &appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       owner.GetNamespace(),
+				Labels:          labels,
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(owner, ownerGVK)},
+			},
+			Data:     runtime.RawExtension{Raw: data},
+			Revision: latest + 1,
+		}
+		if err := c.Create(ctx, recorded); err != nil {
+			// NOTE: the ControllerRevision might be already created but not yet in the cache; in this case, the
+			// revision number is fixed in one of the next reconciles.
+			if apierrors.IsAlreadyExists(err) {
+				return latest + 1, nil
+			}
+			return 0, errors.Wrapf(err, "failed to create ControllerRevision %s", name)
+		}
+		revisions = append(revisions, recorded)
+	}
+
+	// Delete the oldest revisions exceeding the history limit; the revision just recorded is always kept.
+	sortByRevision(revisions)
+	for i := 0; i < len(revisions)-historyLimit; i++ {
+		if revisions[i] == recorded {
+			continue
+		}
+		if err := c.Delete(ctx, revisions[i]); err != nil && !apierrors.IsNotFound(err) {
+			return 0, errors.Wrapf(err, "failed to delete ControllerRevision %s", revisions[i].Name)
+		}
+	}
+	return recorded.Revision, nil
+}
+
+// List returns the ControllerRevisions of owner with the given labels, sorted by revision, oldest first.
+func List(ctx context.Context, c client.Reader, owner client.Object, labels map[string]string) ([]*appsv1.ControllerRevision, error) {
+	list := &appsv1.ControllerRevisionList{}
+	if err := c.List(ctx, list, client.InNamespace(owner.GetNamespace()), client.MatchingLabels(labels)); err != nil {
+		return nil, errors.Wrapf(err, "failed to list ControllerRevisions for %s", owner.GetName())
+	}
+
+	revisions := []*appsv1.ControllerRevision{}
+	for i := range list.Items {
+		if !metav1.IsControlledBy(&list.Items[i], owner) {
+			continue
+		}
+		revisions = append(revisions, &list.Items[i])
+	}
+	sortByRevision(revisions)
+	return revisions, nil
+}
+
+// Name returns the name of the ControllerRevision recording data for the owner with the given name.
+func Name(ownerName string, data []byte) string {
+	if len(ownerName) > maxNamePrefixLength {
+		ownerName = ownerName[:maxNamePrefixLength]
+	}
+	hasher := fnv.New32a()
+	_, _ = hasher.Write(data)
+	return fmt.Sprintf("%s-%s", ownerName, rand.SafeEncodeString(fmt.Sprint(hasher.Sum32())))
+}
+
+func sortByRevision(revisions []*appsv1.ControllerRevision) {
+	sort.SliceStable(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+}
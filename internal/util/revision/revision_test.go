@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRecord(t *testing.T) {
+	g := NewWithT(t)
+
+	ctx := context.Background()
+	owner := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "default",
+			UID:       "foo-uid",
+		},
+	}
+	ownerGVK := corev1.SchemeGroupVersion.WithKind("ConfigMap")
+	labels := map[string]string{"owner": "foo"}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+
+	// The first revision is recorded.
+	rev, err := Record(ctx, c, owner, ownerGVK, labels, []byte(`{"v":1}`), 2)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(rev).To(Equal(int64(1)))
+
+	// The same data does not create a new revision.
+	rev, err = Record(ctx, c, owner, ownerGVK, labels, []byte(`{"v":1}`), 2)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(rev).To(Equal(int64(1)))
+
+	// New data creates a new revision.
+	rev, err = Record(ctx, c, owner, ownerGVK, labels, []byte(`{"v":2}`), 2)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(rev).To(Equal(int64(2)))
+
+	// Data already recorded in a previous revision gets the next revision number.
+	rev, err = Record(ctx, c, owner, ownerGVK, labels, []byte(`{"v":1}`), 2)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(rev).To(Equal(int64(3)))
+
+	revisions, err := List(ctx, c, owner, labels)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(revisions).To(HaveLen(2))
+	g.Expect(revisions[0].Revision).To(Equal(int64(2)))
+	g.Expect(revisions[0].Data.Raw).To(BeEquivalentTo(`{"v":2}`))
+	g.Expect(revisions[1].Revision).To(Equal(int64(3)))
+	g.Expect(revisions[1].Data.Raw).To(BeEquivalentTo(`{"v":1}`))
+	g.Expect(revisions[1].Name).To(Equal(Name("foo", []byte(`{"v":1}`))))
+	g.Expect(revisions[1].Labels).To(Equal(labels))
+	g.Expect(metav1.IsControlledBy(revisions[1], owner)).To(BeTrue())
+
+	// Revisions exceeding the history limit are deleted, oldest first.
+	rev, err = Record(ctx, c, owner, ownerGVK, labels, []byte(`{"v":3}`), 2)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(rev).To(Equal(int64(4)))
+
+	revisions, err = List(ctx, c, owner, labels)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(revisions).To(HaveLen(2))
+	g.Expect(revisions[0].Revision).To(Equal(int64(3)))
+	g.Expect(revisions[1].Revision).To(Equal(int64(4)))
+
+	// Revisions of other owners are ignored.
+	other := owner.DeepCopy()
+	other.UID = "other-uid"
+	revisions, err = List(ctx, c, other, labels)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(revisions).To(BeEmpty())
+}
@@ -19,6 +19,7 @@ package webhooks
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"reflect"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -87,6 +88,7 @@ func (webhook *MachineDrainRule) validate(newMDR *clusterv1.MachineDrainRule) er
 		}
 	}
 
+	allErrs = append(allErrs, validateMachineDrainRuleWebhook(newMDR)...)
 	allErrs = append(allErrs, ValidateMachineDrainRulesSelectors(newMDR)...)
 
 	if len(allErrs) == 0 {
@@ -96,6 +98,41 @@ func (webhook *MachineDrainRule) validate(newMDR *clusterv1.MachineDrainRule) er
 	return apierrors.NewInvalid(clusterv1.GroupVersion.WithKind("MachineDrainRule").GroupKind(), newMDR.Name, allErrs)
 }
 
+func validateMachineDrainRuleWebhook(machineDrainRule *clusterv1.MachineDrainRule) field.ErrorList {
+	var allErrs field.ErrorList
+
+	webhookPath := field.NewPath("spec", "drain", "webhook")
+	webhookConfig := machineDrainRule.Spec.Drain.Webhook
+	if machineDrainRule.Spec.Drain.Behavior != clusterv1.MachineDrainRuleDrainBehaviorWebhook {
+		if webhookConfig != nil {
+			allErrs = append(allErrs,
+				field.Forbidden(webhookPath,
+					fmt.Sprintf("webhook can only be set if drain behavior is %q", clusterv1.MachineDrainRuleDrainBehaviorWebhook)),
+			)
+		}
+		return allErrs
+	}
+
+	if webhookConfig == nil {
+		return append(allErrs,
+			field.Required(webhookPath,
+				fmt.Sprintf("webhook must be set if drain behavior is %q", clusterv1.MachineDrainRuleDrainBehaviorWebhook)),
+		)
+	}
+
+	webhookURL, err := url.Parse(webhookConfig.URL)
+	switch {
+	case err != nil:
+		allErrs = append(allErrs, field.Invalid(webhookPath.Child("url"), webhookConfig.URL, fmt.Sprintf("must be a valid URL: %v", err)))
+	case webhookURL.Scheme != "https":
+		allErrs = append(allErrs, field.Invalid(webhookPath.Child("url"), webhookConfig.URL, "must use the https scheme"))
+	case webhookURL.Host == "":
+		allErrs = append(allErrs, field.Invalid(webhookPath.Child("url"), webhookConfig.URL, "must have a host"))
+	}
+
+	return allErrs
+}
+
 // ValidateMachineDrainRulesSelectors validate the selectors of a MachineDrainRule.
 // Note: This func is exported so it can be also used to validate selectors in the Machine controller.
 func ValidateMachineDrainRulesSelectors(machineDrainRule *clusterv1.MachineDrainRule) field.ErrorList {
@@ -31,6 +31,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/internal/controllers/machinehealthcheck/expression"
 )
 
 var (
@@ -91,6 +93,15 @@ func (webhook *MachineHealthCheck) Default(_ context.Context, obj runtime.Object
 		m.Spec.RemediationTemplate.Namespace = m.Namespace
 	}
 
+	if m.Spec.Remediation != nil {
+		for i := range m.Spec.Remediation.Escalation {
+			step := &m.Spec.Remediation.Escalation[i]
+			if step.TemplateRef != nil && step.TemplateRef.Namespace == "" {
+				step.TemplateRef.Namespace = m.Namespace
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -158,6 +169,9 @@ func (webhook *MachineHealthCheck) validate(oldMHC, newMHC *clusterv1.MachineHea
 	}
 
 	allErrs = append(allErrs, webhook.validateCommonFields(newMHC, specPath)...)
+	allErrs = append(allErrs, validateUnhealthyMachineConditions(newMHC.Spec.UnhealthyMachineConditions, specPath.Child("unhealthyMachineConditions"))...)
+	allErrs = append(allErrs, validateUnhealthyExpressions(newMHC.Spec.UnhealthyExpressions, specPath.Child("unhealthyExpressions"))...)
+	allErrs = append(allErrs, validateRemediation(newMHC, specPath)...)
 
 	if len(allErrs) == 0 {
 		return nil
@@ -199,3 +213,91 @@ func (webhook *MachineHealthCheck) validateCommonFields(m *clusterv1.MachineHeal
 
 	return allErrs
 }
+
+// validateUnhealthyMachineConditions validates the unhealthy machine conditions of the MHC.
+func validateUnhealthyMachineConditions(unhealthyMachineConditions []clusterv1.UnhealthyMachineCondition, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, c := range unhealthyMachineConditions {
+		// Conditions set by the MachineHealthCheck controller or as a consequence of a remediation can't be used,
+		// otherwise a Machine could never become healthy again.
+		switch c.Type {
+		case string(clusterv1.MachineHealthCheckSucceededCondition), string(clusterv1.MachineOwnerRemediatedCondition):
+			allErrs = append(allErrs,
+				field.Invalid(fldPath.Index(i).Child("type"), c.Type, "conditions set by the MachineHealthCheck controller cannot be used"),
+			)
+		}
+	}
+
+	return allErrs
+}
+
+// validateUnhealthyExpressions validates that the unhealthy expressions of the MHC can be compiled.
+func validateUnhealthyExpressions(unhealthyExpressions []clusterv1.UnhealthyExpression, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, e := range unhealthyExpressions {
+		if _, err := expression.Compile(e.Expression); err != nil {
+			allErrs = append(allErrs,
+				field.Invalid(fldPath.Index(i).Child("expression"), e.Expression, err.Error()),
+			)
+		}
+	}
+
+	return allErrs
+}
+
+// validateRemediation validates the remediation escalation steps of the MHC.
+func validateRemediation(m *clusterv1.MachineHealthCheck, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if m.Spec.Remediation == nil {
+		return allErrs
+	}
+
+	if m.Spec.RemediationTemplate != nil && len(m.Spec.Remediation.Escalation) > 0 {
+		allErrs = append(allErrs,
+			field.Forbidden(fldPath.Child("remediation", "escalation"), "cannot be set together with remediationTemplate"),
+		)
+	}
+
+	if m.Spec.Remediation.StabilizationPeriod != nil && m.Spec.Remediation.StabilizationPeriod.Duration < 0 {
+		allErrs = append(allErrs,
+			field.Invalid(fldPath.Child("remediation", "stabilizationPeriod"), m.Spec.Remediation.StabilizationPeriod.String(), "must be greater than or equal to 0"),
+		)
+	}
+
+	escalationPath := fldPath.Child("remediation", "escalation")
+	for i, step := range m.Spec.Remediation.Escalation {
+		switch {
+		case step.TemplateRef == nil && step.Extension == nil:
+			allErrs = append(allErrs,
+				field.Required(escalationPath.Index(i), "one of templateRef or extension must be set"),
+			)
+		case step.TemplateRef != nil && step.Extension != nil:
+			allErrs = append(allErrs,
+				field.Forbidden(escalationPath.Index(i), "only one of templateRef or extension can be set"),
+			)
+		}
+
+		if step.TemplateRef != nil && step.TemplateRef.Namespace != m.Namespace {
+			allErrs = append(allErrs,
+				field.Invalid(escalationPath.Index(i).Child("templateRef", "namespace"), step.TemplateRef.Namespace, "must match metadata.namespace"),
+			)
+		}
+
+		if step.Extension != nil && !feature.Gates.Enabled(feature.RuntimeSDK) {
+			allErrs = append(allErrs,
+				field.Forbidden(escalationPath.Index(i).Child("extension"), "can be used only if the RuntimeSDK feature flag is enabled"),
+			)
+		}
+
+		if step.Timeout != nil && step.Timeout.Duration <= 0 {
+			allErrs = append(allErrs,
+				field.Invalid(escalationPath.Index(i).Child("timeout"), step.Timeout.String(), "must be greater than 0"),
+			)
+		}
+	}
+
+	return allErrs
+}
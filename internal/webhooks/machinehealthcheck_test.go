@@ -24,8 +24,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/ptr"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/internal/webhooks/util"
 )
 
@@ -246,6 +249,265 @@ func TestMachineHealthCheckUnhealthyConditions(t *testing.T) {
 	}
 }
 
+func TestMachineHealthCheckUnhealthyMachineConditionsAndExpressions(t *testing.T) {
+	tests := []struct {
+		name                       string
+		unhealthyMachineConditions []clusterv1.UnhealthyMachineCondition
+		unhealthyExpressions       []clusterv1.UnhealthyExpression
+		expectErr                  bool
+	}{
+		{
+			name: "pass with correctly defined unhealthyMachineConditions and unhealthyExpressions",
+			unhealthyMachineConditions: []clusterv1.UnhealthyMachineCondition{
+				{
+					Type:    string(clusterv1.InfrastructureReadyCondition),
+					Status:  metav1.ConditionFalse,
+					Timeout: metav1.Duration{Duration: 5 * time.Minute},
+				},
+			},
+			unhealthyExpressions: []clusterv1.UnhealthyExpression{
+				{
+					Name:       "kernel-deadlock",
+					Expression: "node != null && node.metadata.?labels['problem.example.com/kernel-deadlock'].orValue('') == 'true'",
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "fail if an unhealthyMachineCondition uses the HealthCheckSucceeded condition",
+			unhealthyMachineConditions: []clusterv1.UnhealthyMachineCondition{
+				{
+					Type:   string(clusterv1.MachineHealthCheckSucceededCondition),
+					Status: metav1.ConditionFalse,
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail if an unhealthyMachineCondition uses the OwnerRemediated condition",
+			unhealthyMachineConditions: []clusterv1.UnhealthyMachineCondition{
+				{
+					Type:   string(clusterv1.MachineOwnerRemediatedCondition),
+					Status: metav1.ConditionFalse,
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail if an unhealthyExpression cannot be compiled",
+			unhealthyExpressions: []clusterv1.UnhealthyExpression{
+				{
+					Name:       "invalid",
+					Expression: "node.spec.unschedulable ==",
+				},
+			},
+			expectErr: true,
+		},
+		{
+			// Note: machine and node are dynamically typed, so in this case the result type is only checked at runtime.
+			name: "pass if an unhealthyExpression on dynamically typed variables does not evaluate to a bool",
+			unhealthyExpressions: []clusterv1.UnhealthyExpression{
+				{
+					Name:       "not-a-bool",
+					Expression: "machine.metadata.name",
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "fail if an unhealthyExpression is a constant that does not evaluate to a bool",
+			unhealthyExpressions: []clusterv1.UnhealthyExpression{
+				{
+					Name:       "not-a-bool",
+					Expression: "'foo'",
+				},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mhc := &clusterv1.MachineHealthCheck{
+				Spec: clusterv1.MachineHealthCheckSpec{
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"test": "test",
+						},
+					},
+					UnhealthyMachineConditions: tt.unhealthyMachineConditions,
+					UnhealthyExpressions:       tt.unhealthyExpressions,
+				},
+			}
+			webhook := &MachineHealthCheck{}
+
+			if tt.expectErr {
+				warnings, err := webhook.ValidateCreate(ctx, mhc)
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(warnings).To(BeEmpty())
+				warnings, err = webhook.ValidateUpdate(ctx, mhc, mhc)
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(warnings).To(BeEmpty())
+			} else {
+				warnings, err := webhook.ValidateCreate(ctx, mhc)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(warnings).To(BeEmpty())
+				warnings, err = webhook.ValidateUpdate(ctx, mhc, mhc)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(warnings).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestMachineHealthCheckRemediation(t *testing.T) {
+	rebootTemplate := &corev1.ObjectReference{
+		APIVersion: "remediation.example.com/v1alpha1",
+		Kind:       "RebootRemediationTemplate",
+		Name:       "reboot",
+		Namespace:  "foo",
+	}
+
+	tests := []struct {
+		name                  string
+		enableRuntimeSDK      bool
+		remediationTemplate   *corev1.ObjectReference
+		remediationEscalation []clusterv1.MachineHealthCheckRemediationStep
+		stabilizationPeriod   *metav1.Duration
+		expectErr             bool
+	}{
+		{
+			name:             "pass with remediation escalation steps using templateRef and extension",
+			enableRuntimeSDK: true,
+			remediationEscalation: []clusterv1.MachineHealthCheckRemediationStep{
+				{
+					Name:        "reboot",
+					Action:      clusterv1.MachineHealthCheckRemediationActionReboot,
+					TemplateRef: rebootTemplate,
+					MaxAttempts: ptr.To[int32](2),
+				},
+				{
+					Name:      "reprovision",
+					Action:    clusterv1.MachineHealthCheckRemediationActionReprovision,
+					Extension: ptr.To("reprovision.remediation-extension"),
+					Timeout:   &metav1.Duration{Duration: 30 * time.Minute},
+				},
+			},
+			stabilizationPeriod: &metav1.Duration{Duration: 30 * time.Minute},
+			expectErr:           false,
+		},
+		{
+			name: "fail if stabilizationPeriod is negative",
+			remediationEscalation: []clusterv1.MachineHealthCheckRemediationStep{
+				{
+					Name:        "reboot",
+					Action:      clusterv1.MachineHealthCheckRemediationActionReboot,
+					TemplateRef: rebootTemplate,
+				},
+			},
+			stabilizationPeriod: &metav1.Duration{Duration: -1 * time.Minute},
+			expectErr:           true,
+		},
+		{
+			name:                "fail if remediation escalation steps are set together with remediationTemplate",
+			remediationTemplate: rebootTemplate,
+			remediationEscalation: []clusterv1.MachineHealthCheckRemediationStep{
+				{
+					Name:        "reboot",
+					Action:      clusterv1.MachineHealthCheckRemediationActionReboot,
+					TemplateRef: rebootTemplate,
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail if neither templateRef nor extension are set",
+			remediationEscalation: []clusterv1.MachineHealthCheckRemediationStep{
+				{
+					Name:   "reboot",
+					Action: clusterv1.MachineHealthCheckRemediationActionReboot,
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name:             "fail if both templateRef and extension are set",
+			enableRuntimeSDK: true,
+			remediationEscalation: []clusterv1.MachineHealthCheckRemediationStep{
+				{
+					Name:        "reboot",
+					Action:      clusterv1.MachineHealthCheckRemediationActionReboot,
+					TemplateRef: rebootTemplate,
+					Extension:   ptr.To("reboot.remediation-extension"),
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail if templateRef namespace does not match the MachineHealthCheck namespace",
+			remediationEscalation: []clusterv1.MachineHealthCheckRemediationStep{
+				{
+					Name:   "reboot",
+					Action: clusterv1.MachineHealthCheckRemediationActionReboot,
+					TemplateRef: &corev1.ObjectReference{
+						APIVersion: "remediation.example.com/v1alpha1",
+						Kind:       "RebootRemediationTemplate",
+						Name:       "reboot",
+						Namespace:  "bar",
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail if extension is set and the RuntimeSDK feature gate is disabled",
+			remediationEscalation: []clusterv1.MachineHealthCheckRemediationStep{
+				{
+					Name:      "reboot",
+					Action:    clusterv1.MachineHealthCheckRemediationActionReboot,
+					Extension: ptr.To("reboot.remediation-extension"),
+				},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.RuntimeSDK, tt.enableRuntimeSDK)
+
+			g := NewWithT(t)
+			mhc := &clusterv1.MachineHealthCheck{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "foo",
+				},
+				Spec: clusterv1.MachineHealthCheckSpec{
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"test": "test",
+						},
+					},
+					RemediationTemplate: tt.remediationTemplate,
+					Remediation: &clusterv1.MachineHealthCheckRemediation{
+						Escalation:          tt.remediationEscalation,
+						StabilizationPeriod: tt.stabilizationPeriod,
+					},
+				},
+			}
+			webhook := &MachineHealthCheck{}
+
+			warnings, err := webhook.ValidateCreate(ctx, mhc)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+			g.Expect(warnings).To(BeEmpty())
+		})
+	}
+}
+
 func TestMachineHealthCheckNodeStartupTimeout(t *testing.T) {
 	zero := metav1.Duration{Duration: 0}
 	twentyNineSeconds := metav1.Duration{Duration: 29 * time.Second}
@@ -119,6 +119,81 @@ func Test_validate(t *testing.T) {
 				"MachineDrainRule.cluster.x-k8s.io \"mdr\" is invalid: " +
 				"spec.drain.order: Invalid value: 5: order must not be set if drain behavior is \"Skip\" or \"WaitCompleted\"",
 		},
+		{
+			name: "Return no error if webhook is set with drain behavior Webhook",
+			machineDrainRule: &clusterv1.MachineDrainRule{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "mdr",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: clusterv1.MachineDrainRuleSpec{
+					Drain: clusterv1.MachineDrainRuleDrainConfig{
+						Behavior: clusterv1.MachineDrainRuleDrainBehaviorWebhook,
+						Order:    ptr.To[int32](5),
+						Webhook: &clusterv1.MachineDrainRuleDrainWebhook{
+							URL: "https://failover.example.com/pre-evict",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Return error if webhook is not set with drain behavior Webhook",
+			machineDrainRule: &clusterv1.MachineDrainRule{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "mdr",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: clusterv1.MachineDrainRuleSpec{
+					Drain: clusterv1.MachineDrainRuleDrainConfig{
+						Behavior: clusterv1.MachineDrainRuleDrainBehaviorWebhook,
+					},
+				},
+			},
+			wantErr: "admission webhook \"validation.machinedrainrule.cluster.x-k8s.io\" denied the request: " +
+				"MachineDrainRule.cluster.x-k8s.io \"mdr\" is invalid: " +
+				"spec.drain.webhook: Required value: webhook must be set if drain behavior is \"Webhook\"",
+		},
+		{
+			name: "Return error if webhook is set with drain behavior Delete",
+			machineDrainRule: &clusterv1.MachineDrainRule{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "mdr",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: clusterv1.MachineDrainRuleSpec{
+					Drain: clusterv1.MachineDrainRuleDrainConfig{
+						Behavior: clusterv1.MachineDrainRuleDrainBehaviorDelete,
+						Webhook: &clusterv1.MachineDrainRuleDrainWebhook{
+							URL: "https://failover.example.com/pre-evict",
+						},
+					},
+				},
+			},
+			wantErr: "admission webhook \"validation.machinedrainrule.cluster.x-k8s.io\" denied the request: " +
+				"MachineDrainRule.cluster.x-k8s.io \"mdr\" is invalid: " +
+				"spec.drain.webhook: Forbidden: webhook can only be set if drain behavior is \"Webhook\"",
+		},
+		{
+			name: "Return error if webhook url does not use https",
+			machineDrainRule: &clusterv1.MachineDrainRule{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "mdr",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: clusterv1.MachineDrainRuleSpec{
+					Drain: clusterv1.MachineDrainRuleDrainConfig{
+						Behavior: clusterv1.MachineDrainRuleDrainBehaviorWebhook,
+						Webhook: &clusterv1.MachineDrainRuleDrainWebhook{
+							URL: "http://failover.example.com/pre-evict",
+						},
+					},
+				},
+			},
+			wantErr: "admission webhook \"validation.machinedrainrule.cluster.x-k8s.io\" denied the request: " +
+				"MachineDrainRule.cluster.x-k8s.io \"mdr\" is invalid: " +
+				"spec.drain.webhook.url: Invalid value: \"http://failover.example.com/pre-evict\": must use the https scheme",
+		},
 		{
 			name: "Return error for MachineDrainRules with invalid selector",
 			machineDrainRule: &clusterv1.MachineDrainRule{
@@ -309,6 +309,11 @@ func main() {
 		os.Exit(1)
 	}
 
+	if feature.Gates.Enabled(feature.InPlaceUpdates) && !feature.Gates.Enabled(feature.RuntimeSDK) {
+		setupLog.Error(errors.Errorf("the %s feature gate requires the %s feature gate to be enabled", feature.InPlaceUpdates, feature.RuntimeSDK), "Unable to start manager")
+		os.Exit(1)
+	}
+
 	if err := version.CheckKubernetesVersion(restConfig, minVer); err != nil {
 		setupLog.Error(err, "Unable to start manager")
 		os.Exit(1)
@@ -358,6 +363,11 @@ func main() {
 				&corev1.Secret{}: {
 					Label: clusterSecretCacheSelector,
 				},
+				// Note: Only ControllerRevisions with the cluster name label, i.e. the ones recording the revision
+				// history of MachinePools, are cached.
+				&appsv1.ControllerRevision{}: {
+					Label: clusterSecretCacheSelector,
+				},
 			},
 		},
 		Client: client.Options{
@@ -595,6 +605,7 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager, watchNamespaces map
 		Client:           mgr.GetClient(),
 		APIReader:        mgr.GetAPIReader(),
 		ClusterCache:     clusterCache,
+		RuntimeClient:    runtimeClient,
 		WatchFilterValue: watchFilterValue,
 	}).SetupWithManager(ctx, mgr, concurrency(machineSetConcurrency)); err != nil {
 		setupLog.Error(err, "Unable to create controller", "controller", "MachineSet")
@@ -642,6 +653,7 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager, watchNamespaces map
 	if err := (&controllers.MachineHealthCheckReconciler{
 		Client:           mgr.GetClient(),
 		ClusterCache:     clusterCache,
+		RuntimeClient:    runtimeClient,
 		WatchFilterValue: watchFilterValue,
 	}).SetupWithManager(ctx, mgr, concurrency(machineHealthCheckConcurrency)); err != nil {
 		setupLog.Error(err, "Unable to create controller", "controller", "MachineHealthCheck")
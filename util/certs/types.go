@@ -46,6 +46,12 @@ type Config struct {
 	Organization []string
 	AltNames     AltNames
 	Usages       []x509.ExtKeyUsage
+
+	// NotBefore is the start of the validity of the certificate; if not set, the NotBefore of the CA certificate is used.
+	NotBefore time.Time
+
+	// Duration is the lifespan of the certificate; if not set, DefaultCertDuration is used.
+	Duration time.Duration
 }
 
 // NewSignedCert creates a signed certificate using the given CA certificate and key.
@@ -63,6 +69,16 @@ func (cfg *Config) NewSignedCert(key *rsa.PrivateKey, caCert *x509.Certificate,
 		return nil, errors.New("must specify at least one ExtKeyUsage")
 	}
 
+	duration := cfg.Duration
+	if duration == 0 {
+		duration = DefaultCertDuration
+	}
+
+	notBefore := caCert.NotBefore
+	if !cfg.NotBefore.IsZero() {
+		notBefore = cfg.NotBefore.UTC()
+	}
+
 	tmpl := x509.Certificate{
 		Subject: pkix.Name{
 			CommonName:   cfg.CommonName,
@@ -71,8 +87,8 @@ func (cfg *Config) NewSignedCert(key *rsa.PrivateKey, caCert *x509.Certificate,
 		DNSNames:     cfg.AltNames.DNSNames,
 		IPAddresses:  cfg.AltNames.IPs,
 		SerialNumber: serial,
-		NotBefore:    caCert.NotBefore,
-		NotAfter:     time.Now().Add(DefaultCertDuration).UTC(),
+		NotBefore:    notBefore,
+		NotAfter:     time.Now().Add(duration).UTC(),
 		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
 		ExtKeyUsage:  cfg.Usages,
 	}
@@ -44,6 +44,15 @@ var (
 	ErrDependentCertificateNotFound = errors.New("could not find secret ca")
 )
 
+const (
+	// systemMastersGroup is the group granting unrestricted access to the cluster, bypassing RBAC.
+	systemMastersGroup = "system:masters"
+
+	// clientCertificateClockSkew is subtracted from the current time when setting the start of the validity
+	// of a client certificate, so the certificate is immediately valid on API servers with clocks slightly behind.
+	clientCertificateClockSkew = 5 * time.Minute
+)
+
 // FromSecret fetches the Kubeconfig for a Cluster.
 func FromSecret(ctx context.Context, c client.Reader, cluster client.ObjectKey) ([]byte, error) {
 	out, err := secret.Get(ctx, c, cluster, secret.Kubeconfig)
@@ -57,10 +66,81 @@ func FromSecret(ctx context.Context, c client.Reader, cluster client.ObjectKey)
 func New(clusterName, endpoint string, caCert *x509.Certificate, caKey crypto.Signer) (*api.Config, error) {
 	cfg := &certs.Config{
 		CommonName:   "kubernetes-admin",
-		Organization: []string{"system:masters"},
+		Organization: []string{systemMastersGroup},
 		Usages:       []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
 	}
+	return newWithClientCertificate(clusterName, endpoint, fmt.Sprintf("%s-admin", clusterName), cfg, caCert, caKey)
+}
+
+// ClientCertificateOptions defines the user, the groups and the lifespan of the client certificate of a Kubeconfig.
+type ClientCertificateOptions struct {
+	// User is the user the client certificate is issued for, i.e. the common name of the certificate.
+	User string
+
+	// Groups are the groups of the user, i.e. the organizations of the certificate.
+	Groups []string
+
+	// Duration is the lifespan of the client certificate; if not set, certs.DefaultCertDuration is used.
+	// The client certificate never outlives the cluster CA.
+	Duration time.Duration
+}
+
+// NewWithClientCertificate creates a new Kubeconfig using the cluster name and specified endpoint,
+// with a client certificate for the given user and groups signed by the cluster CA.
+// The system:masters group is rejected, because it cannot be restricted with RBAC; use New for admin credentials.
+func NewWithClientCertificate(clusterName, endpoint string, caCert *x509.Certificate, caKey crypto.Signer, options ClientCertificateOptions) (*api.Config, error) {
+	if options.User == "" {
+		return nil, errors.New("must specify a user")
+	}
+	for _, group := range options.Groups {
+		if group == systemMastersGroup {
+			return nil, errors.Errorf("the %s group is not allowed, because it grants unrestricted access to the cluster bypassing RBAC", systemMastersGroup)
+		}
+	}
+
+	now := time.Now()
+	duration := options.Duration
+	if duration == 0 {
+		duration = certs.DefaultCertDuration
+	}
+	if remaining := caCert.NotAfter.Sub(now); duration > remaining {
+		if remaining <= 0 {
+			return nil, errors.Errorf("the cluster CA expired at %s", caCert.NotAfter.UTC().Format(time.RFC3339))
+		}
+		duration = remaining
+	}
 
+	cfg := &certs.Config{
+		CommonName:   options.User,
+		Organization: options.Groups,
+		Usages:       []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		NotBefore:    now.Add(-clientCertificateClockSkew),
+		Duration:     duration,
+	}
+	return newWithClientCertificate(clusterName, endpoint, options.User, cfg, caCert, caKey)
+}
+
+// GenerateWithClientCertificate returns a Kubeconfig for the given cluster name and endpoint,
+// with a client certificate for the given user and groups signed by the cluster CA.
+func GenerateWithClientCertificate(ctx context.Context, c client.Reader, clusterName client.ObjectKey, endpoint string, options ClientCertificateOptions) ([]byte, error) {
+	cert, key, err := getClusterCA(ctx, c, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := NewWithClientCertificate(clusterName.Name, endpoint, cert, key, options)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate a kubeconfig")
+	}
+
+	out, err := clientcmd.Write(*cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to serialize config to yaml")
+	}
+	return out, nil
+}
+
+func newWithClientCertificate(clusterName, endpoint, userName string, cfg *certs.Config, caCert *x509.Certificate, caKey crypto.Signer) (*api.Config, error) {
 	clientKey, err := certs.NewPrivateKey()
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create private key")
@@ -71,7 +151,6 @@ func New(clusterName, endpoint string, caCert *x509.Certificate, caKey crypto.Si
 		return nil, errors.Wrap(err, "unable to sign certificate")
 	}
 
-	userName := fmt.Sprintf("%s-admin", clusterName)
 	contextName := fmt.Sprintf("%s@%s", userName, clusterName)
 
 	return &api.Config{
@@ -206,38 +285,47 @@ func RegenerateSecret(ctx context.Context, c client.Client, configSecret *corev1
 }
 
 func generateKubeconfig(ctx context.Context, c client.Client, clusterName client.ObjectKey, endpoint string) ([]byte, error) {
-	clusterCA, err := secret.GetFromNamespacedName(ctx, c, clusterName, secret.ClusterCA)
+	cert, key, err := getClusterCA(ctx, c, clusterName)
 	if err != nil {
-		if apierrors.IsNotFound(err) {
-			return nil, ErrDependentCertificateNotFound
-		}
 		return nil, err
 	}
 
-	cert, err := certs.DecodeCertPEM(clusterCA.Data[secret.TLSCrtDataName])
+	cfg, err := New(clusterName.Name, endpoint, cert, key)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to decode CA Cert")
-	} else if cert == nil {
-		return nil, errors.New("certificate not found in config")
+		return nil, errors.Wrap(err, "failed to generate a kubeconfig")
 	}
 
-	key, err := certs.DecodePrivateKeyPEM(clusterCA.Data[secret.TLSKeyDataName])
+	out, err := clientcmd.Write(*cfg)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to decode private key")
-	} else if key == nil {
-		return nil, errors.New("CA private key not found")
+		return nil, errors.Wrap(err, "failed to serialize config to yaml")
+	}
+	return out, nil
+}
+
+// getClusterCA returns the certificate and the private key of the cluster CA.
+func getClusterCA(ctx context.Context, c client.Reader, clusterName client.ObjectKey) (*x509.Certificate, crypto.Signer, error) {
+	clusterCA, err := secret.GetFromNamespacedName(ctx, c, clusterName, secret.ClusterCA)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil, ErrDependentCertificateNotFound
+		}
+		return nil, nil, err
 	}
 
-	cfg, err := New(clusterName.Name, endpoint, cert, key)
+	cert, err := certs.DecodeCertPEM(clusterCA.Data[secret.TLSCrtDataName])
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to generate a kubeconfig")
+		return nil, nil, errors.Wrap(err, "failed to decode CA Cert")
+	} else if cert == nil {
+		return nil, nil, errors.New("certificate not found in config")
 	}
 
-	out, err := clientcmd.Write(*cfg)
+	key, err := certs.DecodePrivateKeyPEM(clusterCA.Data[secret.TLSKeyDataName])
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to serialize config to yaml")
+		return nil, nil, errors.Wrap(err, "failed to decode private key")
+	} else if key == nil {
+		return nil, nil, errors.New("CA private key not found")
 	}
-	return out, nil
+	return cert, key, nil
 }
 
 func toKubeconfigBytes(out *corev1.Secret) ([]byte, error) {
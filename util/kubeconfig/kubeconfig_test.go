@@ -472,3 +472,66 @@ func TestRegenerateClientCerts(t *testing.T) {
 
 	g.Expect(newCert.NotAfter).To(BeTemporally(">", oldCert.NotAfter))
 }
+
+func TestGenerateWithClientCertificate(t *testing.T) {
+	g := NewWithT(t)
+	caKey, err := certs.NewPrivateKey()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	caCert, err := getTestCACert(caKey)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	caSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test1-ca",
+			Namespace: "test",
+		},
+		Data: map[string][]byte{
+			secret.TLSKeyDataName: certs.EncodePrivateKeyPEM(caKey),
+			secret.TLSCrtDataName: certs.EncodeCertPEM(caCert),
+		},
+	}
+
+	c := fake.NewClientBuilder().WithObjects(caSecret).Build()
+	clusterName := client.ObjectKey{Name: "test1", Namespace: "test"}
+	options := ClientCertificateOptions{
+		User:     "jane",
+		Groups:   []string{"developers", "viewers"},
+		Duration: time.Hour,
+	}
+
+	out, err := GenerateWithClientCertificate(ctx, c, clusterName, "https://test1:6443", options)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	config, err := clientcmd.Load(out)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(config.CurrentContext).To(Equal("jane@test1"))
+	g.Expect(config.Clusters["test1"].Server).To(Equal("https://test1:6443"))
+	g.Expect(config.AuthInfos).To(HaveKey("jane"))
+
+	cert, err := certs.DecodeCertPEM(config.AuthInfos["jane"].ClientCertificateData)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cert.Subject.CommonName).To(Equal("jane"))
+	g.Expect(cert.Subject.Organization).To(ConsistOf("developers", "viewers"))
+	g.Expect(cert.NotBefore).To(BeTemporally("~", time.Now().Add(-clientCertificateClockSkew), time.Minute))
+	g.Expect(cert.NotAfter).To(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+	g.Expect(cert.CheckSignatureFrom(caCert)).To(Succeed())
+
+	// The client certificate does not outlive the CA.
+	out, err = GenerateWithClientCertificate(ctx, c, clusterName, "https://test1:6443", ClientCertificateOptions{User: "jane", Duration: 48 * time.Hour})
+	g.Expect(err).ToNot(HaveOccurred())
+	config, err = clientcmd.Load(out)
+	g.Expect(err).ToNot(HaveOccurred())
+	cert, err = certs.DecodeCertPEM(config.AuthInfos["jane"].ClientCertificateData)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cert.NotAfter).To(BeTemporally("~", caCert.NotAfter, time.Second))
+
+	_, err = GenerateWithClientCertificate(ctx, c, clusterName, "https://test1:6443", ClientCertificateOptions{})
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = GenerateWithClientCertificate(ctx, c, clusterName, "https://test1:6443", ClientCertificateOptions{User: "jane", Groups: []string{"system:masters"}})
+	g.Expect(err).To(MatchError(ContainSubstring("system:masters group is not allowed")))
+
+	_, err = GenerateWithClientCertificate(ctx, c, client.ObjectKey{Name: "test2", Namespace: "test"}, "https://test2:6443", options)
+	g.Expect(err).To(MatchError(ErrDependentCertificateNotFound))
+}